@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/app"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+)
+
+// runBench runs the headless render benchmark for each workload and prints
+// the frame times and allocations.
+func runBench(workloads []string, opts app.BenchOptions, jsonOutput bool) error {
+	if err := theme.Initialize(themeName); err != nil {
+		log.Printf("Warning: Failed to load theme '%s': %v", themeName, err)
+	}
+
+	results := make([]app.BenchResult, 0, len(workloads))
+	for _, workload := range workloads {
+		opts.Workload = workload
+		result, err := app.RunBench(opts)
+		if err != nil {
+			return fmt.Errorf("failed to run %s benchmark: %w", workload, err)
+		}
+		results = append(results, result)
+	}
+
+	if jsonOutput {
+		outputJSON(results)
+		return nil
+	}
+	fmt.Printf("%d windows, %d frames at %dx%d\n\n", opts.Windows, opts.Frames, opts.Width, opts.Height)
+	fmt.Printf("%-8s %9s %9s %9s %9s %9s %9s %10s %12s\n",
+		"WORKLOAD", "MIN", "AVG", "P50", "P95", "P99", "MAX", "ALLOCS/FR", "BYTES/FR")
+	for _, r := range results {
+		fmt.Printf("%-8s %9s %9s %9s %9s %9s %9s %10.0f %12.0f\n", r.Workload,
+			benchDuration(r.Min), benchDuration(r.Avg), benchDuration(r.P50),
+			benchDuration(r.P95), benchDuration(r.P99), benchDuration(r.Max),
+			r.AllocsPerFrame, r.BytesPerFrame)
+	}
+	return nil
+}
+
+// benchDuration formats a frame time to microsecond precision.
+func benchDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
@@ -20,6 +20,78 @@ func printConfigPath() error {
 	return nil
 }
 
+// selectProfile selects the --profile config profile, which must exist.
+func selectProfile(name string) error {
+	if err := config.SetProfile(name); err != nil {
+		return err
+	}
+	path, err := config.GetProfilePath()
+	if err != nil || path == "" {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("profile %q not found: create %s", name, path)
+	}
+	return nil
+}
+
+func checkConfigFile(path string) error {
+	paths := []string{path}
+	if path == "" {
+		configPath, err := config.GetConfigPath()
+		if err != nil {
+			return fmt.Errorf("could not determine config path: %w", err)
+		}
+		paths = []string{configPath}
+
+		profilePath, err := config.GetProfilePath()
+		if err != nil {
+			return err
+		}
+		if profilePath != "" {
+			paths = append(paths, profilePath)
+		}
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, path := range paths {
+		// #nosec G304 - reading the config file the user asked to check is intentional
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		issues := config.CheckConfig(data)
+		if len(issues) == 0 {
+			fmt.Printf("%s: OK\n", path)
+			continue
+		}
+		for _, issue := range issues {
+			kind := "error"
+			if issue.Warning {
+				kind = "warning"
+				warningCount++
+			} else {
+				errorCount++
+			}
+			location := path
+			if issue.Line > 0 {
+				location = fmt.Sprintf("%s:%d", path, issue.Line)
+			}
+			fmt.Printf("%s: %s: %s\n", location, kind, issue)
+		}
+	}
+
+	if errorCount+warningCount == 0 {
+		return nil
+	}
+	fmt.Printf("\n%d error(s), %d warning(s)\n", errorCount, warningCount)
+	if errorCount > 0 {
+		return fmt.Errorf("configuration has %d error(s)", errorCount)
+	}
+	return nil
+}
+
 func editConfigFile() error {
 	configPath, err := config.GetConfigPath()
 	if err != nil {
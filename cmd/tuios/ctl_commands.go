@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Gaurav-Gosain/tuios/internal/app"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/google/uuid"
+)
+
+// ctlTarget returns the session and window the calling program runs in,
+// from the variables TUIOS exports to the shells of its windows.
+func ctlTarget() (sessionName, windowID string, err error) {
+	sessionName = os.Getenv("TUIOS_SESSION")
+	windowID = os.Getenv("TUIOS_WINDOW_ID")
+	if sessionName == "" || windowID == "" {
+		return "", "", fmt.Errorf("tuios ctl must run inside a window of a daemon session (start one with 'tuios new')")
+	}
+	return sessionName, windowID, nil
+}
+
+// runCtl executes a remote command in the session the calling program runs
+// in, through the daemon socket exported to the window.
+func runCtl(sessionName, command string, args []string, jsonOutput bool) error {
+	client := session.NewClient(&session.ClientConfig{
+		Version:    version,
+		SocketPath: os.Getenv("TUIOS_SOCKET"),
+	})
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	requestID := uuid.New().String()
+	msg, err := session.NewMessage(session.MsgExecuteCommand, &session.ExecuteCommandPayload{
+		SessionName: sessionName,
+		CommandType: command,
+		Args:        args,
+		RequestID:   requestID,
+		CallerPID:   os.Getpid(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+
+	return sendAndWaitForResultWithFormat(client, msg, requestID, jsonOutput)
+}
+
+// runCtlNew opens a window in the caller's session running command, in the
+// caller's working directory unless dir is given.
+func runCtlNew(command []string, dir string, jsonOutput bool) error {
+	sessionName, _, err := ctlTarget()
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+	}
+	dir, err = filepath.Abs(config.ExpandHome(dir))
+	if err != nil {
+		return fmt.Errorf("failed to resolve directory: %w", err)
+	}
+	return runCtl(sessionName, "RunInNewWindow", []string{app.ShellJoin(command), dir}, jsonOutput)
+}
+
+// runCtlRename renames the caller's window, also setting its icon when
+// setIcon is true.
+func runCtlRename(name, icon string, setIcon, jsonOutput bool) error {
+	sessionName, windowID, err := ctlTarget()
+	if err != nil {
+		return err
+	}
+	args := []string{windowID, name}
+	if setIcon {
+		args = append(args, icon)
+	}
+	return runCtl(sessionName, "RenameWindowByID", args, jsonOutput)
+}
+
+// runCtlRenameWorkspace names a workspace, by default the one of the
+// caller's window.
+func runCtlRenameWorkspace(name string, workspace int, jsonOutput bool) error {
+	sessionName, _, err := ctlTarget()
+	if err != nil {
+		return err
+	}
+	args := []string{name}
+	if workspace > 0 {
+		args = []string{strconv.Itoa(workspace), name}
+	}
+	return runCtl(sessionName, "RenameWorkspace", args, jsonOutput)
+}
+
+// runCtlMove moves the caller's window to a workspace.
+func runCtlMove(workspace string, jsonOutput bool) error {
+	sessionName, windowID, err := ctlTarget()
+	if err != nil {
+		return err
+	}
+	return runCtl(sessionName, "MoveWindowByID", []string{windowID, workspace}, jsonOutput)
+}
+
+// runCtlTeleport moves the caller's window to another session of the same
+// daemon.
+func runCtlTeleport(target string, jsonOutput bool) error {
+	sessionName, windowID, err := ctlTarget()
+	if err != nil {
+		return err
+	}
+	return runCtl(sessionName, "MoveWindowToSession", []string{windowID, target}, jsonOutput)
+}
+
+// runCtlTag adds tags to the caller's window, or removes them (all of them
+// when none are given) when remove is true.
+func runCtlTag(tags []string, remove, jsonOutput bool) error {
+	sessionName, windowID, err := ctlTarget()
+	if err != nil {
+		return err
+	}
+	if remove {
+		return runCtl(sessionName, "UntagWindow", append([]string{windowID}, tags...), jsonOutput)
+	}
+	if len(tags) == 0 {
+		return fmt.Errorf("no tags given (use --remove to remove all tags)")
+	}
+	return runCtl(sessionName, "TagWindow", append([]string{windowID}, tags...), jsonOutput)
+}
+
+// runCtlExport exports the caller's session to a bundle at path.
+func runCtlExport(path string, jsonOutput bool) error {
+	sessionName, _, err := ctlTarget()
+	if err != nil {
+		return err
+	}
+	args, err := resolveExportArg([]string{path})
+	if err != nil {
+		return err
+	}
+	return runCtl(sessionName, "ExportSession", args, jsonOutput)
+}
+
+// runCtlNotify shows a notification in the caller's session.
+func runCtlNotify(message, notificationType string, jsonOutput bool) error {
+	sessionName, _, err := ctlTarget()
+	if err != nil {
+		return err
+	}
+	return runCtl(sessionName, "ShowNotification", []string{message, notificationType}, jsonOutput)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+
+	"github.com/Gaurav-Gosain/tuios/internal/app"
+)
+
+// doctorReport is the JSON form of the tuios doctor report.
+type doctorReport struct {
+	Version      string                  `json:"version"`
+	Platform     string                  `json:"platform"`
+	Terminal     string                  `json:"terminal"`
+	Responded    bool                    `json:"responded"`
+	CellWidth    int                     `json:"cell_width"`
+	CellHeight   int                     `json:"cell_height"`
+	Environment  map[string]string       `json:"environment"`
+	Capabilities []app.CapabilityFeature `json:"capabilities"`
+}
+
+// doctorEnvironment are the variables capability detection looks at.
+var doctorEnvironment = []string{"TERM", "TERM_PROGRAM", "COLORTERM", "VTE_VERSION", "TMUX", "SSH_TTY", "WT_SESSION"}
+
+// runDoctor probes the host terminal and reports what it supports and what
+// each capability is used for, so missing features can be explained.
+func runDoctor(jsonOutput bool) error {
+	caps := app.DetectHostCapabilities()
+	report := doctorReport{
+		Version:      version,
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		Terminal:     caps.TerminalName,
+		Responded:    caps.Responded,
+		CellWidth:    caps.CellWidth,
+		CellHeight:   caps.CellHeight,
+		Environment:  make(map[string]string),
+		Capabilities: caps.Features(),
+	}
+	variables := slices.Concat(doctorEnvironment, app.CapabilityOverrides)
+	for _, name := range variables {
+		if value, ok := os.LookupEnv(name); ok {
+			report.Environment[name] = value
+		}
+	}
+
+	if jsonOutput {
+		outputJSON(report)
+		return nil
+	}
+
+	terminal := report.Terminal
+	if terminal == "" {
+		terminal = "unknown"
+	}
+	fmt.Printf("TUIOS %s on %s\n\n", report.Version, report.Platform)
+	fmt.Printf("Terminal:   %s\n", terminal)
+	fmt.Printf("Cell size:  %dx%d pixels\n", report.CellWidth, report.CellHeight)
+	if !report.Responded {
+		fmt.Println("Queries:    no answer, so graphics and synchronized output were taken as unsupported")
+	}
+	fmt.Println()
+	for _, name := range variables {
+		if value, ok := report.Environment[name]; ok {
+			fmt.Printf("  %-21s %s\n", name, value)
+		}
+	}
+	fmt.Println()
+
+	for _, feature := range report.Capabilities {
+		mark := "no "
+		if feature.Supported {
+			mark = "yes"
+		}
+		fmt.Printf("  [%s] %-20s %s\n", mark, feature.Name, feature.Used)
+	}
+	fmt.Println("\nDetection can be wrong: set an override variable to 1 or 0 to force a")
+	fmt.Println("capability on or off (e.g. TUIOS_CLIPBOARD=1).")
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Gaurav-Gosain/tuios/internal/imageview"
+)
+
+// runViewImage shows an image with the built-in viewer.
+func runViewImage(path, protocol, cellSize string) error {
+	switch protocol {
+	case imageview.ProtocolKitty, imageview.ProtocolBlocks:
+	default:
+		return fmt.Errorf("unknown graphics protocol %q (use kitty or blocks)", protocol)
+	}
+
+	opts := imageview.Options{Protocol: protocol}
+	if cellSize != "" {
+		if _, err := fmt.Sscanf(cellSize, "%dx%d", &opts.CellWidth, &opts.CellHeight); err != nil {
+			return fmt.Errorf("invalid cell size %q (use WxH): %w", cellSize, err)
+		}
+	}
+	return imageview.Run(path, opts)
+}
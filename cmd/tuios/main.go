@@ -8,7 +8,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/Gaurav-Gosain/tuios/internal/app"
 	"github.com/Gaurav-Gosain/tuios/internal/session"
 	"github.com/charmbracelet/fang"
 	tint "github.com/lrstanley/bubbletint/v2"
@@ -39,6 +41,12 @@ var (
 	noAnimations        bool
 	windowTitlePosition string
 	hideClock           bool
+	pprofAddr           string
+	metricsAddr         string
+	recordInput         string
+	layoutFile          string
+	importFile          string
+	profile             string
 )
 
 func main() {
@@ -65,6 +73,19 @@ comprehensive keyboard/mouse interactions.`,
   # Run with a specific theme
   tuios --theme dracula
 
+  # Use the settings from ~/.config/tuios/profiles/work.toml
+  tuios --profile work
+
+  # Start with the workspaces and windows from a layout file
+  tuios --layout dev.toml
+  tuios --layout dev.yaml
+
+  # Recreate the windows of a session bundle a teammate exported
+  tuios --import session.json
+
+  # Open windows running commands, btop on workspace 2
+  tuios -e "nvim ." -e "npm run dev" --workspace 2 -e btop
+
   # List all available themes
   tuios --list-themes
 
@@ -77,12 +98,18 @@ comprehensive keyboard/mouse interactions.`,
   # Run as SSH server
   tuios ssh --port 2222
 
+  # Serve sessions over SSH with public-key auth
+  tuios serve --ssh :2222
+
   # Edit configuration
   tuios config edit
 
   # List all keybindings
   tuios keybinds list`,
 		Version: version,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			return selectProfile(profile)
+		},
 		RunE: func(_ *cobra.Command, _ []string) error {
 			if previewTheme != "" {
 				return previewThemeColors(previewTheme)
@@ -102,6 +129,7 @@ comprehensive keyboard/mouse interactions.`,
 	}
 
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Enable debug logging")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Config profile to merge over the base config (profiles/<name>.toml next to config.toml)")
 	rootCmd.PersistentFlags().StringVar(&cpuProfile, "cpuprofile", "", "Write CPU profile to file")
 	rootCmd.PersistentFlags().BoolVar(&asciiOnly, "ascii-only", false, "Use ASCII characters instead of Nerd Font icons")
 	rootCmd.PersistentFlags().StringVar(&themeName, "theme", "", "Color theme to use (e.g., dracula, nord, tokyonight). Leave empty to use standard terminal colors without theming")
@@ -115,8 +143,16 @@ comprehensive keyboard/mouse interactions.`,
 	rootCmd.PersistentFlags().BoolVar(&noAnimations, "no-animations", false, "Disable UI animations for instant transitions")
 	rootCmd.PersistentFlags().StringVar(&windowTitlePosition, "window-title-position", "", "Window title position: bottom, top, hidden (default: from config or bottom)")
 	rootCmd.PersistentFlags().BoolVar(&hideClock, "hide-clock", false, "Hide the clock overlay")
-
-	var sshPort, sshHost, sshKeyPath, sshDefaultSession string
+	rootCmd.PersistentFlags().StringVar(&pprofAddr, "pprof", "", "Serve pprof and expvar metrics on a localhost address (e.g. localhost:6060)")
+	_ = rootCmd.PersistentFlags().MarkHidden("pprof")
+	rootCmd.PersistentFlags().StringVar(&metricsAddr, "metrics", "", "Serve Prometheus metrics at /metrics on a localhost address (e.g. localhost:9464)")
+	rootCmd.PersistentFlags().StringVar(&recordInput, "record-input", "", "Record every input event to a file, for 'tuios replay'")
+	rootCmd.Flags().StringVar(&layoutFile, "layout", "", "Layout file (TOML, or YAML when named .yaml/.yml) describing workspaces and windows to create at startup")
+	rootCmd.Flags().StringVar(&importFile, "import", "", "Recreate the windows of a session bundle exported with 'tuios ctl export' at startup")
+	rootCmd.Flags().VarP(execFlag{startup}, "exec", "e", "Open a window running this command at startup, closing when it exits (repeatable)")
+	rootCmd.Flags().Var(workspaceFlag{startup}, "workspace", "Workspace for the -e windows that follow (default: 1)")
+
+	var sshPort, sshHost, sshKeyPath, sshDefaultSession, sshAuthorizedKeys string
 	var sshEphemeral bool
 
 	sshCmd := &cobra.Command{
@@ -148,9 +184,12 @@ Use --ephemeral for standalone sessions (legacy behavior).`,
   tuios ssh --default-session mysession
 
   # Run in ephemeral mode (standalone, no daemon)
-  tuios ssh --ephemeral`,
+  tuios ssh --ephemeral
+
+  # Only accept clients listed in an authorized_keys file
+  tuios ssh --authorized-keys ~/.ssh/authorized_keys`,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return runSSHServer(sshHost, sshPort, sshKeyPath, sshDefaultSession, sshEphemeral)
+			return runSSHServer(sshHost, sshPort, sshKeyPath, sshDefaultSession, sshAuthorizedKeys, sshEphemeral)
 		},
 	}
 
@@ -159,6 +198,39 @@ Use --ephemeral for standalone sessions (legacy behavior).`,
 	sshCmd.Flags().StringVar(&sshKeyPath, "key-path", "", "Path to SSH host key (auto-generated if not specified)")
 	sshCmd.Flags().StringVar(&sshDefaultSession, "default-session", "", "Default session name for all connections")
 	sshCmd.Flags().BoolVar(&sshEphemeral, "ephemeral", false, "Run in ephemeral mode (standalone, no daemon)")
+	sshCmd.Flags().StringVar(&sshAuthorizedKeys, "authorized-keys", "", "Only accept clients whose public key is listed in this authorized_keys file")
+
+	var serveSSHAddr, serveKeyPath, serveDefaultSession, serveAuthorizedKeys string
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve TUIOS sessions to remote clients",
+		Long: `Serve TUIOS sessions to remote clients
+
+With --ssh, runs an SSH server that attaches each connection to a daemon
+session, so a session can be reached from another machine with a plain
+ssh client. Clients must authenticate with a public key listed in
+~/.ssh/authorized_keys (or the file given with --authorized-keys);
+password and keyboard-interactive logins are not accepted.
+
+Sessions are selected the same way as with 'tuios ssh'.`,
+		Example: `  # Serve on port 2222 on all interfaces
+  tuios serve --ssh :2222
+
+  # Serve on localhost only, with a dedicated key list
+  tuios serve --ssh localhost:2222 --authorized-keys ~/.config/tuios/authorized_keys
+
+  # Connect from another machine
+  ssh -p 2222 mysession@myhost`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runServe(serveSSHAddr, serveKeyPath, serveDefaultSession, serveAuthorizedKeys)
+		},
+	}
+
+	serveCmd.Flags().StringVar(&serveSSHAddr, "ssh", "", "Serve sessions over SSH on this address (e.g. :2222)")
+	serveCmd.Flags().StringVar(&serveKeyPath, "key-path", "", "Path to SSH host key (auto-generated if not specified)")
+	serveCmd.Flags().StringVar(&serveDefaultSession, "default-session", "", "Default session name for all connections")
+	serveCmd.Flags().StringVar(&serveAuthorizedKeys, "authorized-keys", "", "authorized_keys file listing the accepted client keys (default: ~/.ssh/authorized_keys)")
 
 	configCmd := &cobra.Command{
 		Use:   "config",
@@ -200,6 +272,53 @@ This will overwrite your existing configuration after confirmation.`,
 
 	configCmd.AddCommand(configPathCmd, configEditCmd, configResetCmd)
 
+	checkConfigCmd := &cobra.Command{
+		Use:   "check-config [file]",
+		Short: "Validate the configuration file",
+		Long: `Parse and validate the TUIOS configuration file and print every problem
+with its line number. With --profile, the profile file is checked as well.
+
+Errors (invalid TOML or key bindings) stop TUIOS from loading the config.
+Warnings (unknown options, invalid values, conflicting bindings) are ignored
+at runtime and the default is used. Exits with status 1 if there are errors.`,
+		Example: `  # Check the default config file
+  tuios check-config
+
+  # Check another file
+  tuios check-config ./config.toml`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return checkConfigFile(path)
+		},
+	}
+
+	var doctorJSON bool
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Report what the host terminal supports",
+		Long: `Probe the host terminal the way TUIOS does at startup and report which
+capabilities were detected: true color, Kitty and Sixel graphics,
+synchronized output, OSC 52 clipboard writes and OSC 8 hyperlinks.
+
+Run it in the terminal TUIOS misbehaves in to see why a feature is missing.
+Capabilities with no query are guessed from the terminal's name; each can be
+forced on or off with its override variable.`,
+		Example: `  # Show the report
+  tuios doctor
+
+  # As JSON, for a bug report
+  tuios doctor --json`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runDoctor(doctorJSON)
+		},
+	}
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output the report as JSON")
+
 	keybindsCmd := &cobra.Command{
 		Use:     "keybinds",
 		Aliases: []string{"keys", "kb"},
@@ -311,7 +430,7 @@ in the terminal UI. Press Ctrl+P to pause/resume playback.`,
 
 	tapeCmd.AddCommand(tapePlayCmd, tapeValidateCmd, tapeListCmd, tapeDirCmd, tapeDeleteCmd, tapeShowCmd)
 
-	var createIfMissing bool
+	var createIfMissing, attachReadOnly bool
 
 	attachCmd := &cobra.Command{
 		Use:   "attach [session-name]",
@@ -329,17 +448,21 @@ This requires the TUIOS daemon to be running.`,
   tuios attach mysession
 
   # Attach and create if session doesn't exist
-  tuios attach mysession -c`,
+  tuios attach mysession -c
+
+  # Mirror a session without sending input (e.g. on a projector)
+  tuios attach mysession --read-only`,
 		Aliases: []string{"a"},
 		RunE: func(_ *cobra.Command, args []string) error {
 			name := ""
 			if len(args) > 0 {
 				name = args[0]
 			}
-			return runAttach(name, createIfMissing)
+			return runAttach(name, createIfMissing, attachReadOnly)
 		},
 	}
 	attachCmd.Flags().BoolVarP(&createIfMissing, "create", "c", false, "Create session if it doesn't exist")
+	attachCmd.Flags().BoolVarP(&attachReadOnly, "read-only", "r", false, "Attach as a mirror that renders the session but discards all input")
 
 	newCmd := &cobra.Command{
 		Use:   "new [session-name]",
@@ -564,6 +687,9 @@ Use --json to get machine-readable output for scripting.`,
 			// First argument: command name
 			return getRunCommandCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
 		}
+		if args[0] == "ApplyLayout" || args[0] == "OpenImage" {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
 		// Second+ arguments depend on the command
 		return getRunCommandArgCompletions(args[0], len(args), toComplete), cobra.ShellCompDirectiveNoFileComp
 	}
@@ -669,8 +795,138 @@ Logs are stored in a ring buffer (1000 entries by default).`,
 	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Follow logs (continuously show new entries)")
 	logsCmd.Flags().Bool("all", false, "Show all log entries")
 
+	// Image viewer run inside windows opened with OpenImage
+	var viewImageProtocol string
+	var viewImageCellSize string
+	viewImageCmd := &cobra.Command{
+		Use:   "view-image <file>",
+		Short: "Show an image in the terminal",
+		Long: `Show an image (PNG, JPEG or GIF) scaled to fit the terminal.
+
+Arrow keys or h/j/k/l pan, +/- zoom, 0 fits the image again and q or Esc quits.
+TUIOS runs this in a new window for 'tuios run-command OpenImage <file>'.`,
+		Example: `  tuios view-image photo.png
+  tuios view-image --protocol kitty --cell-size 10x20 photo.png`,
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runViewImage(args[0], viewImageProtocol, viewImageCellSize)
+		},
+	}
+	viewImageCmd.Flags().StringVar(&viewImageProtocol, "protocol", "blocks", "Graphics protocol: kitty or blocks")
+	viewImageCmd.Flags().StringVar(&viewImageCellSize, "cell-size", "", "Terminal cell size in pixels (WxH)")
+
+	var benchWorkloads []string
+	var benchJSON bool
+	benchOpts := app.BenchOptions{}
+	benchCmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure rendering performance headlessly",
+		Long: `Render frames of synthetic windows without a terminal and report frame
+times and allocations per frame.
+
+Each workload feeds the windows the same output every run, so results can be
+compared before and after a change:
+  scroll  plain lines flooding every window
+  color   lines full of SGR color changes
+  resize  plain lines plus a screen resize every 10 frames
+  idle    no output, so frames come from the caches`,
+		Example: `  # Run every workload with the defaults
+  tuios bench
+
+  # Eight tiled windows on a large screen, as JSON
+  tuios bench --windows 8 --tiling --size 240x70 --json
+
+  # Only the color workload
+  tuios bench --workload color --frames 1000`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runBench(benchWorkloads, benchOpts, benchJSON)
+		},
+	}
+	benchCmd.Flags().StringSliceVarP(&benchWorkloads, "workload", "w", app.BenchWorkloads, "Workloads to run (scroll, color, resize, idle)")
+	benchCmd.Flags().IntVar(&benchOpts.Windows, "windows", 4, "Number of synthetic windows")
+	benchCmd.Flags().IntVarP(&benchOpts.Frames, "frames", "n", 300, "Number of frames to render per workload")
+	benchCmd.Flags().IntVar(&benchOpts.LinesPerFrame, "lines", 20, "Lines written to each window before every frame")
+	benchCmd.Flags().BoolVar(&benchOpts.Tiling, "tiling", false, "Tile the windows instead of cascading them")
+	benchCmd.Flags().BoolVar(&benchJSON, "json", false, "Output results as JSON")
+	benchSize := benchCmd.Flags().String("size", "160x48", "Screen size in cells (WxH)")
+	benchCmd.PreRunE = func(_ *cobra.Command, _ []string) error {
+		if _, err := fmt.Sscanf(*benchSize, "%dx%d", &benchOpts.Width, &benchOpts.Height); err != nil {
+			return fmt.Errorf("invalid size %q (use WxH): %w", *benchSize, err)
+		}
+		return nil
+	}
+	_ = benchCmd.RegisterFlagCompletionFunc("workload", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return app.BenchWorkloads, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	var replayUseConfig bool
+	replayCmd := &cobra.Command{
+		Use:   "replay <file>",
+		Short: "Replay recorded input headlessly",
+		Long: `Replay input recorded with --record-input through a headless session, to
+reproduce a crash.
+
+Windows get no shell and the session's files go to a throwaway directory, so
+a recording takes the same path every time it is replayed. A panic is
+reported with the event that raised it and its stack trace.`,
+		Example: `  # Record a session, then replay it
+  tuios --record-input /tmp/input.jsonl
+  tuios replay /tmp/input.jsonl
+
+  # Replay with your key bindings instead of the defaults
+  tuios replay /tmp/input.jsonl --use-config`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			width, height, err := parseReplaySize(cmd)
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			return runReplay(args[0], replayOptions(width, height, replayUseConfig))
+		},
+	}
+	replayCmd.Flags().String("size", "120x40", "Screen size in cells until the first recorded resize (WxH)")
+	replayCmd.Flags().BoolVar(&replayUseConfig, "use-config", false, "Replay with the key bindings from your config")
+
+	var fuzzSeed uint64
+	var fuzzRuns, fuzzEvents int
+	var fuzzOut string
+	var fuzzUseConfig bool
+	fuzzCmd := &cobra.Command{
+		Use:   "fuzz",
+		Short: "Fuzz the key and mouse handling with random input",
+		Long: `Replay random key presses, clicks, drags, pastes and resizes through a
+headless session until one panics.
+
+Each run uses the next seed, so a run is repeatable. The input of the first
+crashing run is saved for 'tuios replay'.`,
+		Example: `  # 100 runs of 2000 events
+  tuios fuzz
+
+  # A longer search from a fixed seed
+  tuios fuzz --seed 42 --runs 1000 --out /tmp/crash.jsonl`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			width, height, err := parseReplaySize(cmd)
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			return runFuzz(fuzzSeed, fuzzRuns, fuzzEvents, fuzzOut, replayOptions(width, height, fuzzUseConfig))
+		},
+	}
+	fuzzCmd.Flags().Uint64Var(&fuzzSeed, "seed", 1, "Seed of the first run")
+	fuzzCmd.Flags().IntVar(&fuzzRuns, "runs", 100, "Number of runs")
+	fuzzCmd.Flags().IntVarP(&fuzzEvents, "events", "n", 2000, "Number of events per run")
+	fuzzCmd.Flags().StringVarP(&fuzzOut, "out", "o", "tuios-crash.jsonl", "File the crashing input is saved to")
+	fuzzCmd.Flags().String("size", "120x40", "Initial screen size in cells (WxH)")
+	fuzzCmd.Flags().BoolVar(&fuzzUseConfig, "use-config", false, "Fuzz with the key bindings from your config")
+
 	// Inspection commands for scripting and hackability
 	var listWindowsSession string
+	var listWindowsTag string
 	var listWindowsJSON bool
 	listWindowsCmd := &cobra.Command{
 		Use:   "list-windows",
@@ -686,12 +942,16 @@ Use --json for machine-readable output that can be used for scripting.`,
   tuios list-windows --json
 
   # Use with jq to get focused window ID
-  tuios list-windows --json | jq '.focused_window_id'`,
+  tuios list-windows --json | jq '.focused_window_id'
+
+  # Only the windows tagged prod
+  tuios list-windows --tag prod`,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return queryWindows(listWindowsSession, listWindowsJSON)
+			return queryWindows(listWindowsSession, listWindowsTag, listWindowsJSON)
 		},
 	}
 	listWindowsCmd.Flags().StringVarP(&listWindowsSession, "session", "s", "", "Target session (default: most recently active)")
+	listWindowsCmd.Flags().StringVar(&listWindowsTag, "tag", "", "Only list the windows with this tag")
 	listWindowsCmd.Flags().BoolVar(&listWindowsJSON, "json", false, "Output as JSON")
 	_ = listWindowsCmd.RegisterFlagCompletionFunc("session", completeSessionNames)
 
@@ -752,11 +1012,217 @@ Use --json for machine-readable output.`,
 	sessionInfoCmd.Flags().BoolVar(&sessionInfoJSON, "json", false, "Output as JSON")
 	_ = sessionInfoCmd.RegisterFlagCompletionFunc("session", completeSessionNames)
 
-	rootCmd.AddCommand(sshCmd, configCmd, keybindsCmd, tapeCmd)
+	var statusSession, statusFormat string
+	var statusJSON bool
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print a one-line summary of the session",
+		Long: `Print a one-line summary of the running TUIOS session, for shell
+prompts and status bars: the current workspace, the focused window, window
+counts and urgent windows.
+
+--format takes a Go template over the fields printed by --json, e.g.
+{{.workspace}}, {{.focused_name}} or {{join .urgent_workspaces ","}}.
+Inside a window of a daemon session the status is that session's.`,
+		Example: `  # Default status line, e.g. "2:logs vim [3/5] !1"
+  tuios status
+
+  # Custom format for a status bar
+  tuios status --format '{{.workspace}} {{.focused_name}}{{if .urgent}} ({{.urgent}} urgent){{end}}'
+
+  # All fields as JSON
+  tuios status --json`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runStatus(statusSession, statusFormat, statusJSON)
+		},
+	}
+	statusCmd.Flags().StringVarP(&statusSession, "session", "s", "", "Target session (default: the one the command runs in, else most recently active)")
+	statusCmd.Flags().StringVarP(&statusFormat, "format", "f", defaultStatusFormat, "Go template the status line is formatted with")
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output as JSON")
+	_ = statusCmd.RegisterFlagCompletionFunc("session", completeSessionNames)
+
+	var popupSession, popupTitle string
+	var popupTimeout time.Duration
+	var popupChoices []string
+	var popupJSON bool
+	popupCmd := &cobra.Command{
+		Use:   "popup <text>",
+		Short: "Show a popup in a running TUIOS session",
+		Long: `Show a popup with a title and text in a running TUIOS session.
+
+With --choice the popup asks the user to pick one of the choices, and the
+command waits for the answer and prints the picked choice. It exits with an
+error when the popup is dismissed or times out. Without choices the popup
+closes after 10 seconds unless --timeout is given.`,
+		Example: `  # Tell the user something
+  tuios popup --title "Backup" "Backup finished in 3m12s"
+
+  # Ask a question and act on the answer
+  if [ "$(tuios popup --title Deploy --choice Yes --choice No "Deploy to prod?")" = Yes ]; then
+    ./deploy.sh prod
+  fi
+
+  # Give up after 30 seconds
+  tuios popup --timeout 30s --choice Retry --choice Abort "Tests failed"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			timeout := popupTimeout
+			if !cmd.Flags().Changed("timeout") {
+				timeout = -1 // Let the session pick the default
+			}
+			return runPopup(popupSession, popupTitle, args[0], timeout, popupChoices, popupJSON)
+		},
+	}
+	popupCmd.Flags().StringVarP(&popupSession, "session", "s", "", "Target session (default: most recently active)")
+	popupCmd.Flags().StringVarP(&popupTitle, "title", "t", "", "Popup title")
+	popupCmd.Flags().DurationVar(&popupTimeout, "timeout", 0, "Close the popup after this long, 0 to wait until closed (default: 10s without choices, no limit with choices)")
+	popupCmd.Flags().StringArrayVarP(&popupChoices, "choice", "c", nil, "Choice to offer (repeatable, up to 9 get number keys)")
+	popupCmd.Flags().BoolVar(&popupJSON, "json", false, "Output result as JSON (for scripting)")
+	_ = popupCmd.RegisterFlagCompletionFunc("session", completeSessionNames)
+
+	var ctlJSON bool
+	ctlCmd := &cobra.Command{
+		Use:   "ctl",
+		Short: "Control TUIOS from inside one of its windows",
+		Long: `Control TUIOS from inside one of its windows.
+
+The session and window are found from the TUIOS_SESSION, TUIOS_WINDOW_ID
+and TUIOS_SOCKET variables TUIOS exports to the shells of its windows, so
+these commands only work inside a daemon session.`,
+	}
+	ctlCmd.PersistentFlags().BoolVar(&ctlJSON, "json", false, "Output result as JSON (for scripting)")
+
+	var ctlNewCwd string
+	ctlNewCmd := &cobra.Command{
+		Use:   "new [command...]",
+		Short: "Open a new window, optionally running a command",
+		Example: `  # Open a shell in the current directory
+  tuios ctl new
+
+  # Run a command in a new window
+  tuios ctl new htop
+
+  # Start in another directory
+  tuios ctl new --cwd ~/src make watch`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runCtlNew(args, ctlNewCwd, ctlJSON)
+		},
+	}
+	ctlNewCmd.Flags().StringVar(&ctlNewCwd, "cwd", "", "Directory to start in (default: the current directory)")
+
+	var ctlRenameIcon string
+	ctlRenameCmd := &cobra.Command{
+		Use:   "rename <name>",
+		Short: "Rename the current window",
+		Example: `  tuios ctl rename server
+
+  # Flag the outcome of a deploy
+  tuios ctl rename --icon 🚀 "deploy: prod ✔"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCtlRename(args[0], ctlRenameIcon, cmd.Flags().Changed("icon"), ctlJSON)
+		},
+	}
+	ctlRenameCmd.Flags().StringVar(&ctlRenameIcon, "icon", "", "Icon shown in place of the program's icon (empty restores it)")
+
+	var ctlRenameWorkspace int
+	ctlRenameWorkspaceCmd := &cobra.Command{
+		Use:   "rename-workspace <name>",
+		Short: "Name the current window's workspace",
+		Example: `  tuios ctl rename-workspace deploy
+
+  # Name another workspace
+  tuios ctl rename-workspace --workspace 2 logs
+
+  # Clear the name
+  tuios ctl rename-workspace ""`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runCtlRenameWorkspace(args[0], ctlRenameWorkspace, ctlJSON)
+		},
+	}
+	ctlRenameWorkspaceCmd.Flags().IntVarP(&ctlRenameWorkspace, "workspace", "w", 0, "Workspace to name, 1-9 (default: the current window's)")
+
+	ctlMoveCmd := &cobra.Command{
+		Use:     "move <workspace>",
+		Short:   "Move the current window to a workspace",
+		Example: `  tuios ctl move 3`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runCtlMove(args[0], ctlJSON)
+		},
+		ValidArgs: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+	}
+
+	ctlTeleportCmd := &cobra.Command{
+		Use:   "teleport <session>",
+		Short: "Move the current window to another session",
+		Long: `Move the current window, with its running process, screen and scrollback,
+to another session of the same daemon. The window opens in that session
+right away if a client is attached to it, or else when one next attaches.`,
+		Example: `  # Hand a long-running job over to the session kept on the server
+  tuios ctl teleport server`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runCtlTeleport(args[0], ctlJSON)
+		},
+	}
+
+	var ctlTagRemove bool
+	ctlTagCmd := &cobra.Command{
+		Use:   "tag [tags...]",
+		Short: "Tag the current window",
+		Long: `Add tags to the current window. Tags pick windows in 'tag:NAME' selectors of
+run-command, filter the sidebar and gather windows in the tag view.`,
+		Example: `  tuios ctl tag prod db
+
+  # Remove a tag, or every tag
+  tuios ctl tag --remove db
+  tuios ctl tag --remove`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runCtlTag(args, ctlTagRemove, ctlJSON)
+		},
+	}
+	ctlTagCmd.Flags().BoolVarP(&ctlTagRemove, "remove", "r", false, "Remove the tags (all of them when none are given)")
+
+	ctlExportCmd := &cobra.Command{
+		Use:   "export <file>",
+		Short: "Export the session to a shareable bundle",
+		Long: `Write a session bundle: the workspaces and their names, the windows with
+their names, geometry and working directories, the commands running in them
+and the tiling layout. 'tuios --import <file>' recreates the windows from it.`,
+		Example: `  # Share the debugging setup with a teammate
+  tuios ctl export session.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runCtlExport(args[0], ctlJSON)
+		},
+	}
+
+	var ctlNotifyType string
+	ctlNotifyCmd := &cobra.Command{
+		Use:   "notify <message>",
+		Short: "Show a notification",
+		Example: `  # Tell when a long build is done
+  make; tuios ctl notify --type success "Build finished"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runCtlNotify(args[0], ctlNotifyType, ctlJSON)
+		},
+	}
+	ctlNotifyCmd.Flags().StringVarP(&ctlNotifyType, "type", "t", "info", "Notification type (info, success, warning, error)")
+	_ = ctlNotifyCmd.RegisterFlagCompletionFunc("type", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"info", "success", "warning", "error"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	ctlCmd.AddCommand(ctlNewCmd, ctlRenameCmd, ctlRenameWorkspaceCmd, ctlMoveCmd, ctlTeleportCmd, ctlTagCmd, ctlExportCmd, ctlNotifyCmd)
+
+	rootCmd.AddCommand(sshCmd, serveCmd, configCmd, checkConfigCmd, doctorCmd, keybindsCmd, tapeCmd)
 	rootCmd.AddCommand(attachCmd, newCmd, lsCmd, killSessionCmd)
 	rootCmd.AddCommand(startDaemonCmd, daemonCmd, killDaemonCmd)
 	rootCmd.AddCommand(sendKeysCmd, runCommandCmd, setConfigCmd, logsCmd)
-	rootCmd.AddCommand(listWindowsCmd, getWindowCmd, sessionInfoCmd)
+	rootCmd.AddCommand(listWindowsCmd, getWindowCmd, sessionInfoCmd, statusCmd, popupCmd, ctlCmd)
+	rootCmd.AddCommand(viewImageCmd, benchCmd, replayCmd, fuzzCmd)
 
 	if err := fang.Execute(
 		context.Background(),
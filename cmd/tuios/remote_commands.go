@@ -6,9 +6,13 @@ import (
 	"maps"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/session"
 	"github.com/Gaurav-Gosain/tuios/internal/tape"
 	"github.com/google/uuid"
@@ -66,6 +70,28 @@ func runCommand(sessionName, command string, args []string, jsonOutput bool) err
 	}
 	defer func() { _ = client.Close() }()
 
+	if command == "ApplyLayout" {
+		resolved, err := resolveLayoutArg(args)
+		if err != nil {
+			return err
+		}
+		args = resolved
+	}
+	if command == "OpenImage" {
+		resolved, err := resolveImageArg(args)
+		if err != nil {
+			return err
+		}
+		args = resolved
+	}
+	if command == "ExportSession" {
+		resolved, err := resolveExportArg(args)
+		if err != nil {
+			return err
+		}
+		args = resolved
+	}
+
 	requestID := uuid.New().String()
 
 	// Send the execute command
@@ -74,6 +100,7 @@ func runCommand(sessionName, command string, args []string, jsonOutput bool) err
 		CommandType: command,
 		Args:        args,
 		RequestID:   requestID,
+		CallerPID:   os.Getpid(),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create message: %w", err)
@@ -86,8 +113,120 @@ func runCommand(sessionName, command string, args []string, jsonOutput bool) err
 	return nil
 }
 
+// runPopup shows a popup in a running session. With choices it waits for the
+// user to pick one and prints it, failing when the popup is dismissed or
+// times out.
+func runPopup(sessionName, title, body string, timeout time.Duration, choices []string, jsonOutput bool) error {
+	if !session.IsDaemonRunning() {
+		return fmt.Errorf("TUIOS daemon is not running. Start a session first with 'tuios new'")
+	}
+
+	// The answer comes when the popup closes, so wait past its timeout
+	responseTimeout := time.Duration(0)
+	if len(choices) > 0 {
+		responseTimeout = -1
+		if timeout > 0 {
+			responseTimeout = timeout + 5*time.Second
+		}
+	}
+	client := session.NewClient(&session.ClientConfig{
+		Version:         version,
+		ResponseTimeout: responseTimeout,
+	})
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	timeoutArg := ""
+	if timeout >= 0 {
+		timeoutArg = timeout.String()
+	}
+	requestID := uuid.New().String()
+	msg, err := session.NewMessage(session.MsgExecuteCommand, &session.ExecuteCommandPayload{
+		SessionName: sessionName,
+		CommandType: "ShowPopup",
+		Args:        append([]string{title, body, timeoutArg}, choices...),
+		RequestID:   requestID,
+		CallerPID:   os.Getpid(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+	if jsonOutput || len(choices) == 0 {
+		return sendAndWaitForResultWithFormat(client, msg, requestID, jsonOutput)
+	}
+
+	resp, err := client.SendControlMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to send command: %w", err)
+	}
+	if resp.Type == session.MsgError {
+		var errPayload session.ErrorPayload
+		if err := resp.ParsePayloadWithCodec(&errPayload, client.GetCodec()); err != nil {
+			return fmt.Errorf("command failed with unknown error")
+		}
+		return fmt.Errorf("command failed: %s", errPayload.Message)
+	}
+	var result session.CommandResultPayload
+	if err := resp.ParsePayloadWithCodec(&result, client.GetCodec()); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%s", result.Message)
+	}
+	fmt.Println(result.Message)
+	return nil
+}
+
+// resolveLayoutArg validates the layout file given to ApplyLayout and makes
+// its path absolute, since the session may run in a different directory.
+func resolveLayoutArg(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("ApplyLayout requires a layout file path")
+	}
+	path, err := filepath.Abs(config.ExpandHome(args[0]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve layout path: %w", err)
+	}
+	if _, err := config.LoadLayoutFile(path); err != nil {
+		return nil, err
+	}
+	return append([]string{path}, args[1:]...), nil
+}
+
+// resolveImageArg checks the image given to OpenImage exists and makes its
+// path absolute, since the session may run in a different directory.
+func resolveImageArg(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("OpenImage requires an image file path")
+	}
+	path, err := filepath.Abs(config.ExpandHome(args[0]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve image path: %w", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	return append([]string{path}, args[1:]...), nil
+}
+
+// resolveExportArg makes the path given to ExportSession absolute, since
+// the session may run in a different directory.
+func resolveExportArg(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("ExportSession requires a file path")
+	}
+	path, err := filepath.Abs(config.ExpandHome(args[0]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve bundle path: %w", err)
+	}
+	return append([]string{path}, args[1:]...), nil
+}
+
 // queryWindows queries window list directly from daemon (doesn't require TUI).
-func queryWindows(sessionName string, jsonOutput bool) error {
+// With a tag, only the windows with that tag are listed.
+func queryWindows(sessionName, tag string, jsonOutput bool) error {
 	if !session.IsDaemonRunning() {
 		return fmt.Errorf("TUIOS daemon is not running. Start a session first with 'tuios new'")
 	}
@@ -106,6 +245,7 @@ func queryWindows(sessionName string, jsonOutput bool) error {
 	msg, err := session.NewMessage(session.MsgQueryWindows, &session.QueryWindowsPayload{
 		SessionName: sessionName,
 		RequestID:   requestID,
+		Tag:         strings.TrimPrefix(tag, "#"),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create message: %w", err)
@@ -150,6 +290,88 @@ func querySession(sessionName string, jsonOutput bool) error {
 	return nil
 }
 
+// defaultStatusFormat is the status line tuios status prints without
+// --format, e.g. "2:logs vim [3/5] !1".
+const defaultStatusFormat = `{{.workspace}}{{with .workspace_name}}:{{.}}{{end}} {{.focused_name}} [{{.workspace_windows}}/{{.windows}}]{{if .urgent}} !{{.urgent}}{{end}}`
+
+// runStatus prints a one-line summary of a running session, formatted with
+// a text/template over the fields of the GetStatus command. Inside a window
+// of a daemon session it reports that session unless another is given.
+func runStatus(sessionName, format string, jsonOutput bool) error {
+	tmpl, err := template.New("status").Funcs(template.FuncMap{"join": joinStatusList}).Parse(format)
+	if err != nil {
+		return fmt.Errorf("failed to parse status format: %w", err)
+	}
+	if !session.IsDaemonRunning() {
+		return fmt.Errorf("TUIOS daemon is not running. Start a session first with 'tuios new'")
+	}
+	if sessionName == "" {
+		sessionName = os.Getenv("TUIOS_SESSION")
+	}
+
+	client := session.NewClient(&session.ClientConfig{
+		Version: version,
+	})
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	requestID := uuid.New().String()
+	msg, err := session.NewMessage(session.MsgExecuteCommand, &session.ExecuteCommandPayload{
+		SessionName: sessionName,
+		CommandType: "GetStatus",
+		RequestID:   requestID,
+		CallerPID:   os.Getpid(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+	if jsonOutput {
+		return sendAndWaitForResultWithFormat(client, msg, requestID, true)
+	}
+
+	resp, err := client.SendControlMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to send command: %w", err)
+	}
+	if resp.Type == session.MsgError {
+		var errPayload session.ErrorPayload
+		if err := resp.ParsePayloadWithCodec(&errPayload, client.GetCodec()); err != nil {
+			return fmt.Errorf("command failed with unknown error")
+		}
+		return fmt.Errorf("command failed: %s", errPayload.Message)
+	}
+	var result session.CommandResultPayload
+	if err := resp.ParsePayloadWithCodec(&result, client.GetCodec()); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("command failed: %s", result.Message)
+	}
+
+	var line strings.Builder
+	if err := tmpl.Execute(&line, result.Data); err != nil {
+		return fmt.Errorf("failed to format status: %w", err)
+	}
+	fmt.Println(line.String())
+	return nil
+}
+
+// joinStatusList joins the items of a list field of the status, e.g. the
+// urgent workspaces, for the join function of status formats.
+func joinStatusList(list any, sep string) string {
+	items := reflect.ValueOf(list)
+	if items.Kind() != reflect.Slice {
+		return fmt.Sprint(list)
+	}
+	parts := make([]string, items.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(items.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
 // runSetConfig sets a configuration option in a running TUIOS session.
 func runSetConfig(sessionName, path, value string) error {
 	if !session.IsDaemonRunning() {
@@ -369,6 +591,23 @@ func listAvailableCommands() {
 		// Workspace
 		{"SwitchWorkspace 1-9", "Switch to workspace N", "tuios run-command SwitchWorkspace 2"},
 		{"MoveToWorkspace 1-9", "Move focused window to workspace N", "tuios run-command MoveToWorkspace 3"},
+		{"ApplyLayout <file>", "Add a layout file's windows to the current workspace", "tuios run-command ApplyLayout dev.toml"},
+		{"OpenImage <file>", "Open an image in a new viewer window", "tuios run-command OpenImage photo.png"},
+		{"RunInNewWindow <command> [dir]", "Run a command in a new window", "tuios run-command RunInNewWindow htop"},
+		{"ExecInWindow [window=<window>] <command...>", "Replace a window's shell (default: the focused one) with a command", "tuios run-command ExecInWindow window=tag:db ssh host"},
+		{"RenameWindowByID <id> <name> [icon]", "Rename a window by ID, optionally setting its icon", "tuios run-command RenameWindowByID a1b2c3d4 server"},
+		{"SetWindowIcon <id> <icon>", "Set the icon of a window by ID", "tuios run-command SetWindowIcon a1b2c3d4 🚀"},
+		{"SetClipboardPolicy <id> <policy>", "Allow, confirm or deny a window's OSC 52 clipboard writes (default: follow the config)", "tuios run-command SetClipboardPolicy a1b2c3d4 confirm"},
+		{"RenameWorkspace [1-9] <name>", "Name a workspace (default: the current one)", "tuios run-command RenameWorkspace 2 logs"},
+		{"MoveWindowByID <id> 1-9", "Move a window by ID to workspace N", "tuios run-command MoveWindowByID a1b2c3d4 3"},
+		{"MoveWindowToSession [id] <session>", "Move a window (default: the focused one) to another daemon session", "tuios run-command MoveWindowToSession server"},
+		{"TagWindow <window> <tag...>", "Tag a window by ID, name or tag: selector", "tuios run-command TagWindow a1b2c3d4 prod db"},
+		{"UntagWindow <window> [tag...]", "Remove tags (default: all) from a window", "tuios run-command UntagWindow tag:db db"},
+		{"ShowTagView <tag>", "Gather the windows with a tag on an empty workspace", "tuios run-command ShowTagView prod"},
+		{"HideTagView", "Put the windows of the tag view back", "tuios run-command HideTagView"},
+		{"FindOnScreen <text>", "Focus the next window showing text and highlight it", "tuios run-command FindOnScreen 'panic:'"},
+		{"GatherWindows", "Pull floating windows off screen back into view", "tuios run-command GatherWindows"},
+		{"ExportSession <file>", "Export the session to a bundle for 'tuios --import'", "tuios run-command ExportSession session.json"},
 
 		// Animations
 		{"EnableAnimations", "Enable UI animations", "tuios run-command EnableAnimations"},
@@ -380,11 +619,13 @@ func listAvailableCommands() {
 		{"SetBorderStyle style", "Change window border style", "tuios run-command SetBorderStyle rounded"},
 		{"SetTheme themename", "Change the color theme", "tuios run-command SetTheme dracula"},
 		{"ShowNotification message [type]", "Show a notification", "tuios run-command ShowNotification \"Hello!\" info"},
+		{"ShowPopup <title> <text> [timeout] [choice...]", "Show a popup, answered with the picked choice", "tuios popup --choice Yes --choice No \"Deploy?\""},
 
 		// Inspection commands
 		{"ListWindows", "List all windows (use --json)", "tuios list-windows --json"},
 		{"GetWindow [id-or-name]", "Get window info (use --json)", "tuios get-window --json"},
 		{"GetSessionInfo", "Get session info (use --json)", "tuios session-info --json"},
+		{"GetStatus", "Get the status line summary (use --json)", "tuios status --json"},
 	}
 
 	fmt.Println("Available commands for 'tuios run-command':")
@@ -496,6 +737,23 @@ func getRunCommandCompletions(toComplete string) []string {
 		"SwitchWorkspace\tSwitch to workspace N",
 		"MoveToWorkspace\tMove window to workspace N",
 		"MoveAndFollowWorkspace\tMove and follow to workspace N",
+		"ApplyLayout\tApply a layout file to the current workspace",
+		"OpenImage\tOpen an image in a viewer window",
+		"RunInNewWindow\tRun a command in a new window",
+		"ExecInWindow\tReplace a window's shell with a command",
+		"RenameWindowByID\tRename a window by ID",
+		"SetWindowIcon\tSet the icon of a window by ID",
+		"SetClipboardPolicy\tSet a window's clipboard write policy",
+		"RenameWorkspace\tName a workspace",
+		"MoveWindowByID\tMove a window by ID to workspace N",
+		"MoveWindowToSession\tMove a window to another session",
+		"TagWindow\tTag a window",
+		"UntagWindow\tRemove tags from a window",
+		"ShowTagView\tShow the windows with a tag",
+		"HideTagView\tLeave the tag view",
+		"FindOnScreen\tFind text on screen",
+		"GatherWindows\tPull windows off screen into view",
+		"ExportSession\tExport the session to a bundle",
 		"EnableAnimations\tEnable animations",
 		"DisableAnimations\tDisable animations",
 		"ToggleAnimations\tToggle animations",
@@ -504,6 +762,7 @@ func getRunCommandCompletions(toComplete string) []string {
 		"SetDockbarPosition\tChange dockbar position",
 		"SetBorderStyle\tChange border style",
 		"ShowNotification\tShow a notification",
+		"ShowPopup\tShow a popup",
 		"FocusDirection\tFocus window in direction",
 	}
 
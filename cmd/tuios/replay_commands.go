@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/replay"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+	"github.com/spf13/cobra"
+)
+
+// inputFilter returns the message filter for the program: filterMessages,
+// plus recording of the input that gets through when --record-input is set.
+// The returned function closes the recording.
+func inputFilter() (func(tea.Model, tea.Msg) tea.Msg, func(), error) {
+	if recordInput == "" {
+		return filterMessages, func() {}, nil
+	}
+	recorder, err := replay.NewRecorder(recordInput)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter := func(model tea.Model, msg tea.Msg) tea.Msg {
+		msg = filterMessages(model, msg)
+		if msg != nil {
+			recorder.Record(msg)
+		}
+		return msg
+	}
+	return filter, func() {
+		if err := recorder.Close(); err != nil {
+			log.Printf("Warning: failed to close input recording: %v", err)
+		}
+	}, nil
+}
+
+// replayOptions returns the options replays run with. The user's key
+// bindings are used only when asked for, since recordings made with the
+// defaults would otherwise replay differently on every machine.
+func replayOptions(width, height int, useConfig bool) replay.Options {
+	if err := theme.Initialize(themeName); err != nil {
+		log.Printf("Warning: Failed to load theme '%s': %v", themeName, err)
+	}
+	opts := replay.Options{Width: width, Height: height}
+	if useConfig {
+		userConfig, err := config.LoadUserConfig()
+		if err != nil {
+			log.Printf("Warning: Failed to load config, using defaults: %v", err)
+		} else {
+			opts.Config = userConfig
+		}
+	}
+	return opts
+}
+
+// runReplay replays a recording headlessly and reports whether it crashed.
+func runReplay(path string, opts replay.Options) error {
+	events, err := replay.Load(path)
+	if err != nil {
+		return err
+	}
+	result, err := replay.Run(events, opts)
+	if err != nil {
+		return err
+	}
+	if result.Crash != nil {
+		printCrash(result.Crash)
+		return result.Crash
+	}
+	fmt.Printf("Replayed %d events without a crash (%d windows open at the end)\n", result.Events, result.Windows)
+	return nil
+}
+
+// runFuzz replays random input until a run crashes, saving the events of
+// the crashing run so it can be replayed.
+func runFuzz(seed uint64, runs, events int, out string, opts replay.Options) error {
+	width, height := opts.Width, opts.Height
+	if width <= 0 || height <= 0 {
+		width, height = 120, 40
+	}
+	for run := range runs {
+		runSeed := seed + uint64(run)
+		input := replay.Random(runSeed, events, width, height)
+		result, err := replay.Run(input, opts)
+		if err != nil {
+			return err
+		}
+		if result.Crash == nil {
+			continue
+		}
+		fmt.Printf("Run %d (seed %d) crashed\n", run+1, runSeed)
+		printCrash(result.Crash)
+		if err := saveRecording(out, input[:result.Events]); err != nil {
+			return err
+		}
+		fmt.Printf("\nCrashing input saved to %s, reproduce it with:\n  tuios replay %s\n", out, out)
+		return result.Crash
+	}
+	fmt.Printf("%d runs of %d events without a crash (seeds %d-%d)\n", runs, events, seed, seed+uint64(runs)-1)
+	return nil
+}
+
+// printCrash prints a replay crash with its stack trace.
+func printCrash(crash *replay.Crash) {
+	fmt.Printf("Panic at event %d (%s): %v\n\n%s", crash.Index, crash.Event.Type, crash.Value, crash.Stack)
+}
+
+// saveRecording writes events as a recording file.
+func saveRecording(path string, events []replay.Event) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create recording: %w", err)
+	}
+	defer func() { err = errors.Join(err, f.Close()) }()
+	if err := replay.Write(f, events); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// parseReplaySize parses the --size flag of a replay command.
+func parseReplaySize(cmd *cobra.Command) (int, int, error) {
+	size, _ := cmd.Flags().GetString("size")
+	var width, height int
+	if _, err := fmt.Sscanf(size, "%dx%d", &width, &height); err != nil {
+		return 0, 0, fmt.Errorf("invalid size %q (use WxH): %w", size, err)
+	}
+	return width, height, nil
+}
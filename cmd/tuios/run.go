@@ -1,9 +1,12 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"runtime/pprof"
@@ -13,11 +16,21 @@ import (
 	"github.com/Gaurav-Gosain/tuios/internal/app"
 	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/input"
+	"github.com/Gaurav-Gosain/tuios/internal/profiling"
 	"github.com/Gaurav-Gosain/tuios/internal/server"
 	"github.com/Gaurav-Gosain/tuios/internal/session"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 )
 
+// filterMessages is the message filter of every program: the host
+// capabilities are applied first, then filterMouseMotion.
+func filterMessages(model tea.Model, msg tea.Msg) tea.Msg {
+	if msg = app.FilterHostCapabilities(msg); msg == nil {
+		return nil
+	}
+	return filterMouseMotion(model, msg)
+}
+
 // filterMouseMotion filters out redundant mouse motion events to reduce CPU usage.
 // Only passes through mouse motion during drag/resize operations.
 func filterMouseMotion(model tea.Model, msg tea.Msg) tea.Msg {
@@ -63,7 +76,7 @@ func runLocal() error {
 		userConfig = config.DefaultConfig()
 	}
 
-	config.ApplyOverrides(config.Overrides{
+	overrides := config.Overrides{
 		ASCIIOnly:           asciiOnly,
 		BorderStyle:         borderStyle,
 		DockbarPosition:     dockbarPosition,
@@ -73,7 +86,8 @@ func runLocal() error {
 		ScrollbackLines:     scrollbackLines,
 		NoAnimations:        noAnimations,
 		ThemeName:           themeName,
-	}, userConfig)
+	}
+	config.ApplyOverrides(overrides, userConfig)
 
 	if cpuProfile != "" {
 		f, err := os.Create(cpuProfile)
@@ -92,6 +106,29 @@ func runLocal() error {
 		defer pprof.StopCPUProfile()
 	}
 
+	if stop := startDebugServer(userConfig); stop != nil {
+		defer stop()
+	}
+
+	startupLayout := startup.layout()
+	if layoutFile != "" {
+		startupLayout, err = config.LoadLayoutFile(layoutFile)
+		if err != nil {
+			return err
+		}
+		startup.mergeInto(startupLayout)
+	}
+	var bundle *app.SessionBundle
+	if importFile != "" {
+		if startupLayout != nil {
+			return fmt.Errorf("--import cannot be combined with --layout or --exec")
+		}
+		bundle, err = app.LoadSessionBundle(importFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	app.SetInputHandler(input.HandleInput)
 
 	keybindRegistry := config.NewKeybindRegistry(userConfig)
@@ -107,14 +144,37 @@ func runLocal() error {
 		KeybindRegistry:           keybindRegistry,
 		ShowKeys:                  showKeys,
 		IsDaemonSession:           isDaemonSession,
+		Nested:                    app.RunningInsideTUIOS(),
 		EnableGraphicsPassthrough: true,
 	})
 
+	initialOS.PendingLayout = startupLayout
+	initialOS.PendingBundle = bundle
+
+	if configPath, err := config.GetConfigPath(); err == nil {
+		initialOS.WatchConfig(configPath, overrides)
+	}
+
+	// Offer to restore the layout left behind by a crash
+	if !isDaemonSession && startupLayout == nil && bundle == nil {
+		if recovery, err := app.LoadRecoveryState(); err != nil {
+			log.Printf("Warning: %v", err)
+		} else if recovery != nil {
+			initialOS.OfferRecovery(recovery)
+		}
+	}
+
+	filter, stopRecording, err := inputFilter()
+	if err != nil {
+		return err
+	}
+	defer stopRecording()
+
 	p := tea.NewProgram(
 		initialOS,
 		tea.WithFPS(config.NormalFPS),
 		tea.WithoutSignalHandler(),
-		tea.WithFilter(filterMouseMotion),
+		tea.WithFilter(filter),
 	)
 
 	sigChan := make(chan os.Signal, 1)
@@ -126,6 +186,17 @@ func runLocal() error {
 
 	finalModel, err := p.Run()
 
+	// Bubble Tea recovers panics and returns no model, but the OS it was
+	// rendering is still intact enough to save its layout for next start.
+	if errors.Is(err, tea.ErrProgramPanic) && !isDaemonSession {
+		saveRecoveryAfterPanic(initialOS)
+	} else if err == nil && !isDaemonSession && !initialOS.ShowRestorePrompt {
+		// A clean exit closes the layout on purpose, so drop the autosave
+		if removeErr := app.RemoveRecoveryState(); removeErr != nil {
+			log.Printf("Warning: %v", removeErr)
+		}
+	}
+
 	if finalOS, ok := finalModel.(*app.OS); ok {
 		finalOS.Cleanup()
 	}
@@ -139,7 +210,7 @@ func runLocal() error {
 	return nil
 }
 
-func runSSHServer(sshHost, sshPort, sshKeyPath, defaultSession string, ephemeral bool) error {
+func runSSHServer(sshHost, sshPort, sshKeyPath, defaultSession, authorizedKeys string, ephemeral bool) error {
 	if debugMode {
 		_ = os.Setenv("TUIOS_DEBUG_INTERNAL", "1")
 		fmt.Println("Debug mode enabled")
@@ -180,9 +251,74 @@ func runSSHServer(sshHost, sshPort, sshKeyPath, defaultSession string, ephemeral
 		DefaultSession: defaultSession,
 		Version:        version,
 		Ephemeral:      ephemeral,
+
+		AuthorizedKeysPath: authorizedKeys,
 	}
 	if err := server.StartSSHServer(ctx, cfg); err != nil {
 		return fmt.Errorf("SSH server error: %w", err)
 	}
 	return nil
 }
+
+// runServe serves daemon sessions over SSH on addr, accepting only clients
+// whose public key is listed in authorizedKeys (default ~/.ssh/authorized_keys).
+func runServe(addr, keyPath, defaultSession, authorizedKeys string) error {
+	if addr == "" {
+		return fmt.Errorf("nothing to serve: specify --ssh <addr>")
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid --ssh address %q: %w", addr, err)
+	}
+	if authorizedKeys == "" {
+		if authorizedKeys, err = server.DefaultAuthorizedKeysPath(); err != nil {
+			return err
+		}
+	}
+	return runSSHServer(host, port, keyPath, defaultSession, authorizedKeys, false)
+}
+
+// startDebugServer starts the pprof/expvar debug server if it was requested
+// with --pprof or debug.pprof_addr, and the Prometheus metrics server if it
+// was requested with --metrics or debug.metrics_addr. It returns a function
+// that stops the servers, or nil if no server was started.
+func startDebugServer(userConfig *config.UserConfig) func() {
+	var servers []*profiling.Server
+	addr := cmp.Or(pprofAddr, userConfig.Debug.PprofAddr)
+	if addr != "" {
+		if srv, err := profiling.Start(addr); err != nil {
+			log.Printf("Warning: Failed to start debug server: %v", err)
+		} else {
+			log.Printf("Debug server listening on http://%s/debug/pprof/", srv.Addr())
+			servers = append(servers, srv)
+		}
+	}
+	addr = cmp.Or(metricsAddr, userConfig.Debug.MetricsAddr)
+	if addr != "" {
+		if srv, err := profiling.StartMetrics(addr); err != nil {
+			log.Printf("Warning: Failed to start metrics server: %v", err)
+		} else {
+			log.Printf("Metrics server listening on http://%s/metrics", srv.Addr())
+			servers = append(servers, srv)
+		}
+	}
+	if len(servers) == 0 {
+		return nil
+	}
+	return func() {
+		for _, srv := range servers {
+			_ = srv.Close()
+		}
+	}
+}
+
+// saveRecoveryAfterPanic writes the session layout to the recovery file after
+// the program panicked and tells the user how to get it back.
+func saveRecoveryAfterPanic(o *app.OS) {
+	path, err := o.SaveRecoveryState("panic")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuios: failed to save session for recovery: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "tuios: saved %d windows to %s; run tuios again to restore them\n", len(o.Windows), path)
+}
@@ -18,7 +18,10 @@ import (
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 )
 
-func runAttach(sessionName string, createIfMissing bool) error {
+func runAttach(sessionName string, createIfMissing, readOnly bool) error {
+	if readOnly && createIfMissing {
+		return fmt.Errorf("--read-only cannot be combined with --create")
+	}
 	if !session.IsDaemonRunning() {
 		if createIfMissing {
 			fmt.Println("Starting TUIOS daemon...")
@@ -31,7 +34,7 @@ func runAttach(sessionName string, createIfMissing bool) error {
 		}
 	}
 
-	return runDaemonSession(sessionName, createIfMissing)
+	return runDaemonSession(sessionName, createIfMissing, readOnly)
 }
 
 func runNewSession(sessionName string) error {
@@ -56,7 +59,7 @@ func runNewSession(sessionName string) error {
 		fmt.Printf("Creating session '%s'\n", sessionName)
 	}
 
-	return runDaemonSession(sessionName, true)
+	return runDaemonSession(sessionName, true, false)
 }
 
 func generateUniqueSessionName(existingNames []string) string {
@@ -73,7 +76,7 @@ func generateUniqueSessionName(existingNames []string) string {
 	}
 }
 
-func runDaemonSession(sessionName string, createNew bool) error {
+func runDaemonSession(sessionName string, createNew, readOnly bool) error {
 	if debugMode {
 		_ = os.Setenv("TUIOS_DEBUG_INTERNAL", "1")
 		fmt.Println("Debug mode enabled")
@@ -85,7 +88,7 @@ func runDaemonSession(sessionName string, createNew bool) error {
 		userConfig = config.DefaultConfig()
 	}
 
-	config.ApplyOverrides(config.Overrides{
+	overrides := config.Overrides{
 		ASCIIOnly:           asciiOnly,
 		BorderStyle:         borderStyle,
 		DockbarPosition:     dockbarPosition,
@@ -95,7 +98,15 @@ func runDaemonSession(sessionName string, createNew bool) error {
 		ScrollbackLines:     scrollbackLines,
 		NoAnimations:        noAnimations,
 		ThemeName:           themeName,
-	}, userConfig)
+	}
+	config.ApplyOverrides(overrides, userConfig)
+
+	if stop := startDebugServer(userConfig); stop != nil {
+		defer stop()
+	}
+
+	// Applies to the in-process daemon that connecting may start.
+	session.SetWindowSizePolicy(session.ParseWindowSizePolicy(userConfig.Daemon.WindowSize))
 
 	app.SetInputHandler(input.HandleInput)
 
@@ -126,8 +137,13 @@ func runDaemonSession(sessionName string, createNew bool) error {
 	}
 	log.Printf("[CLIENT] Connected to daemon")
 
-	log.Printf("[CLIENT] Attaching to session '%s' (createNew=%v)", sessionName, createNew)
-	state, err := client.AttachSession(sessionName, createNew, width, height)
+	log.Printf("[CLIENT] Attaching to session '%s' (createNew=%v, readOnly=%v)", sessionName, createNew, readOnly)
+	var state *session.SessionState
+	if readOnly {
+		state, err = client.AttachSessionReadOnly(sessionName, width, height)
+	} else {
+		state, err = client.AttachSession(sessionName, createNew, width, height)
+	}
 	if err != nil {
 		_ = client.Close()
 		return fmt.Errorf("failed to attach to session: %w", err)
@@ -143,9 +159,15 @@ func runDaemonSession(sessionName string, createNew bool) error {
 		IsDaemonSession:           true,
 		DaemonClient:              client,
 		SessionName:               client.SessionName(),
+		ReadOnly:                  readOnly,
+		Nested:                    app.RunningInsideTUIOS(),
 		EnableGraphicsPassthrough: true,
 	})
 
+	if configPath, err := config.GetConfigPath(); err == nil {
+		initialOS.WatchConfig(configPath, overrides)
+	}
+
 	windowCount := 0
 	if state != nil {
 		windowCount = len(state.Windows)
@@ -170,19 +192,27 @@ func runDaemonSession(sessionName string, createNew bool) error {
 
 		// Sync daemon PTY dimensions to match window dimensions from state
 		// This fixes the issue where PTYs have stale dimensions after detach/reattach
-		log.Printf("[CLIENT] Syncing daemon PTY dimensions")
-		initialOS.SyncDaemonPTYDimensions()
+		if !readOnly {
+			log.Printf("[CLIENT] Syncing daemon PTY dimensions")
+			initialOS.SyncDaemonPTYDimensions()
+		}
 
 		log.Printf("[CLIENT] Restore complete, %d windows in OS", len(initialOS.Windows))
 	} else {
 		log.Printf("[CLIENT] No existing state to restore")
 	}
 
+	filter, stopRecording, err := inputFilter()
+	if err != nil {
+		return err
+	}
+	defer stopRecording()
+
 	p := tea.NewProgram(
 		initialOS,
 		tea.WithFPS(config.NormalFPS),
 		tea.WithoutSignalHandler(),
-		tea.WithFilter(filterMouseMotion),
+		tea.WithFilter(filter),
 	)
 
 	// Set up remote command handler for CLI-initiated commands
@@ -203,6 +233,9 @@ func runDaemonSession(sessionName string, createNew bool) error {
 				ConfigPath:  payload.ConfigPath,
 				ConfigValue: payload.ConfigValue,
 				RequestID:   payload.RequestID,
+				SourcePTYID: payload.SourcePTYID,
+				CallerPID:   payload.CallerPID,
+				Window:      payload.Window,
 			})
 		}()
 		return nil // Don't report error here - it will be handled by the Update loop
@@ -415,11 +448,12 @@ func runDaemon(foreground bool) error {
 		return startDaemonBackground()
 	}
 
-	if session.GetDebugLevel() == session.DebugOff {
-		userConfig, err := config.LoadUserConfig()
-		if err == nil && userConfig.Daemon.LogLevel != "" {
+	userConfig, err := config.LoadUserConfig()
+	if err == nil {
+		if session.GetDebugLevel() == session.DebugOff && userConfig.Daemon.LogLevel != "" {
 			session.SetDebugLevel(session.ParseDebugLevel(userConfig.Daemon.LogLevel))
 		}
+		session.SetWindowSizePolicy(session.ParseWindowSizePolicy(userConfig.Daemon.WindowSize))
 	}
 
 	daemon := session.NewDaemon(&session.DaemonConfig{
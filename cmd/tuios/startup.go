@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// startupWindows collects the windows requested with -e and --workspace.
+// Both flags write to the same value so that each --workspace applies to the
+// -e flags that follow it, in command-line order. Each -e window runs its
+// command as the window's command, like a layout file window.
+type startupWindows struct {
+	workspace  int
+	workspaces []config.LayoutWorkspace
+}
+
+var startup = &startupWindows{workspace: 1}
+
+func (s *startupWindows) addCommand(command string) {
+	for i := range s.workspaces {
+		if s.workspaces[i].Number == s.workspace {
+			s.workspaces[i].Windows = append(s.workspaces[i].Windows, config.LayoutWindow{Command: command})
+			return
+		}
+	}
+	s.workspaces = append(s.workspaces, config.LayoutWorkspace{
+		Number:  s.workspace,
+		Windows: []config.LayoutWindow{{Command: command}},
+	})
+}
+
+func (s *startupWindows) setWorkspace(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 9 {
+		return fmt.Errorf("workspace must be a number between 1 and 9")
+	}
+	s.workspace = n
+	return nil
+}
+
+// layout returns the requested windows as a layout, or nil if -e was not used.
+func (s *startupWindows) layout() *config.LayoutFile {
+	if len(s.workspaces) == 0 {
+		return nil
+	}
+	return &config.LayoutFile{Workspaces: s.workspaces}
+}
+
+// mergeInto adds the requested windows to a layout loaded with --layout.
+func (s *startupWindows) mergeInto(lf *config.LayoutFile) {
+	for _, ws := range s.workspaces {
+		merged := false
+		for i := range lf.Workspaces {
+			if lf.Workspaces[i].Number == ws.Number {
+				lf.Workspaces[i].Windows = append(lf.Workspaces[i].Windows, ws.Windows...)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			lf.Workspaces = append(lf.Workspaces, ws)
+		}
+	}
+}
+
+// execFlag is the pflag.Value behind -e/--exec.
+type execFlag struct{ windows *startupWindows }
+
+func (f execFlag) String() string { return "" }
+func (f execFlag) Type() string   { return "command" }
+func (f execFlag) Set(value string) error {
+	f.windows.addCommand(value)
+	return nil
+}
+
+// workspaceFlag is the pflag.Value behind --workspace.
+type workspaceFlag struct{ windows *startupWindows }
+
+func (f workspaceFlag) String() string         { return "" }
+func (f workspaceFlag) Type() string           { return "1-9" }
+func (f workspaceFlag) Set(value string) error { return f.windows.setWorkspace(value) }
@@ -86,7 +86,7 @@ func runTapeInteractive(tapeFile string) error {
 		initialOS,
 		tea.WithFPS(config.NormalFPS),
 		tea.WithoutSignalHandler(),
-		tea.WithFilter(filterMouseMotion),
+		tea.WithFilter(filterMessages),
 	)
 
 	sigChan := make(chan os.Signal, 1)
@@ -49,8 +49,9 @@ func (m *OS) CreateSnapAnimation(i int, quarter SnapQuarter) *ui.Animation {
 	targetX, targetY, targetWidth, targetHeight := m.calculateSnapBounds(quarter)
 
 	// Enforce minimum size
-	targetWidth = max(targetWidth, config.DefaultWindowWidth)
-	targetHeight = max(targetHeight, config.DefaultWindowHeight)
+	minWidth, minHeight := window.MinimumSize()
+	targetWidth = max(targetWidth, minWidth)
+	targetHeight = max(targetHeight, minHeight)
 
 	return ui.NewSnapAnimation(window, targetX, targetY, targetWidth, targetHeight, config.GetAnimationDuration())
 }
@@ -133,7 +134,7 @@ func (m *OS) UpdateAnimations() {
 			}
 
 			if m.KittyPassthrough != nil && anim.Window != nil && anim.Window.Terminal != nil {
-				scrollbackLen := anim.Window.Terminal.ScrollbackLen()
+				scrollbackLen := anim.Window.Terminal.ScrollbackPushed()
 				viewportHeight := max(anim.Window.Height-2, 1)
 				m.KittyPassthrough.OnWindowMove(
 					anim.Window.ID,
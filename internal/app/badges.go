@@ -0,0 +1,134 @@
+package app
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// UpdateWindowBadges collects the bells and output of every window for its
+// dock badge. A window in view (focused on the current workspace and not
+// minimized) has its badges and urgency cleared instead. It reports whether
+// any badge changed.
+func (m *OS) UpdateWindowBadges() bool {
+	changed := false
+	for i, w := range m.Windows {
+		bells, activity := 0, false
+		for _, t := range badgeTerminals(w) {
+			bells += t.TakeBells()
+			if t.TakeActivity() {
+				activity = true
+			}
+		}
+		if i == m.FocusedWindow && w.Workspace == m.CurrentWorkspace && !w.Minimized {
+			if w.Bells > 0 || w.Activity || !w.UrgentSince.IsZero() {
+				w.Bells, w.Activity = 0, false
+				w.UrgentSince = time.Time{}
+				changed = true
+			}
+			continue
+		}
+		if bells > 0 {
+			w.Bells += bells
+			markUrgent(w)
+			changed = true
+		}
+		if activity && !w.Activity {
+			w.Activity = true
+			changed = true
+		}
+	}
+	return changed
+}
+
+// badgeTerminals returns the window's terminal and those of its panes and
+// tabs, each once.
+func badgeTerminals(w *terminal.Window) []*terminal.Window {
+	terminals := []*terminal.Window{w}
+	for _, t := range append(w.PaneWindows(), w.Tabs...) {
+		if t != w {
+			terminals = append(terminals, t)
+		}
+	}
+	return terminals
+}
+
+// windowBadge returns the badge of a window: the bell icon and the number of
+// unseen bells, else a cross for an urgent window without bells (a failed
+// command), else an activity dot for unseen output, or an empty string.
+func windowBadge(w *terminal.Window) string {
+	switch {
+	case w.Bells > 0:
+		count := strconv.Itoa(w.Bells)
+		if w.Bells > 9 {
+			count = "9+"
+		}
+		if config.UseASCIIOnly {
+			return "!" + count
+		}
+		return "\uf0f3" + count
+	case !w.UrgentSince.IsZero():
+		if config.UseASCIIOnly {
+			return "x"
+		}
+		return "✗"
+	case w.Activity:
+		if config.UseASCIIOnly {
+			return "*"
+		}
+		return "●"
+	}
+	return ""
+}
+
+// windowBadgeColor returns the color of a window's badge: yellow for bells,
+// red for failed commands, cyan for activity.
+func windowBadgeColor(w *terminal.Window) string {
+	switch {
+	case w.Bells > 0:
+		return "#ffcc00"
+	case !w.UrgentSince.IsZero():
+		return "#ff5f5f"
+	}
+	return "#66ccff"
+}
+
+// markUrgent flags a window urgent, keeping the time it first was.
+func markUrgent(w *terminal.Window) {
+	if w.UrgentSince.IsZero() {
+		w.UrgentSince = time.Now()
+	}
+}
+
+// UrgentWindows returns the indexes of the urgent windows, the longest
+// urgent first.
+func (m *OS) UrgentWindows() []int {
+	var urgent []int
+	for i, w := range m.Windows {
+		if !w.UrgentSince.IsZero() {
+			urgent = append(urgent, i)
+		}
+	}
+	slices.SortStableFunc(urgent, func(a, b int) int {
+		return m.Windows[a].UrgentSince.Compare(m.Windows[b].UrgentSince)
+	})
+	return urgent
+}
+
+// JumpToUrgentWindow focuses the window that has been urgent the longest,
+// wherever it is, and clears its urgency, so pressing it again goes on to
+// the next urgent window.
+func (m *OS) JumpToUrgentWindow() {
+	urgent := m.UrgentWindows()
+	if len(urgent) == 0 {
+		m.ShowNotification("No urgent windows", "info", config.NotificationDuration)
+		return
+	}
+	w := m.Windows[urgent[0]]
+	w.Bells, w.Activity = 0, false
+	w.UrgentSince = time.Time{}
+	m.JumpToWindow(urgent[0])
+}
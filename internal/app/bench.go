@@ -0,0 +1,162 @@
+package app
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// Workloads the headless benchmark can drive.
+const (
+	BenchWorkloadScroll = "scroll" // Plain lines flooding every window
+	BenchWorkloadColor  = "color"  // Lines full of SGR color changes
+	BenchWorkloadResize = "resize" // Plain lines plus a screen resize every few frames
+	BenchWorkloadIdle   = "idle"   // No output, so frames come from the caches
+)
+
+// BenchWorkloads lists the benchmark workloads.
+var BenchWorkloads = []string{BenchWorkloadScroll, BenchWorkloadColor, BenchWorkloadResize, BenchWorkloadIdle}
+
+// benchResizeEvery is how many frames the resize workload renders between
+// screen resizes.
+const benchResizeEvery = 10
+
+// BenchOptions configures a headless benchmark run.
+type BenchOptions struct {
+	Workload      string // One of BenchWorkloads
+	Windows       int    // Synthetic windows to open
+	Frames        int    // Frames to render
+	Width         int    // Screen width in cells
+	Height        int    // Screen height in cells
+	LinesPerFrame int    // Lines written to each window before every frame
+	Tiling        bool   // Tile the windows instead of cascading them
+}
+
+// BenchResult is what a benchmark run measured. Frame times only cover
+// rendering; writing the workload's output is measured on its own.
+type BenchResult struct {
+	Workload       string        `json:"workload"`
+	Windows        int           `json:"windows"`
+	Frames         int           `json:"frames"`
+	Width          int           `json:"width"`
+	Height         int           `json:"height"`
+	Total          time.Duration `json:"total_ns"`
+	Output         time.Duration `json:"output_ns"`
+	Min            time.Duration `json:"min_ns"`
+	Avg            time.Duration `json:"avg_ns"`
+	P50            time.Duration `json:"p50_ns"`
+	P95            time.Duration `json:"p95_ns"`
+	P99            time.Duration `json:"p99_ns"`
+	Max            time.Duration `json:"max_ns"`
+	AllocsPerFrame float64       `json:"allocs_per_frame"`
+	BytesPerFrame  float64       `json:"bytes_per_frame"`
+}
+
+// RunBench renders frames of synthetic windows without a terminal, feeding
+// them the workload's output between frames. The same options always drive
+// the same output, so runs can be compared across changes.
+func RunBench(opts BenchOptions) (BenchResult, error) {
+	if !slices.Contains(BenchWorkloads, opts.Workload) {
+		return BenchResult{}, fmt.Errorf("unknown workload %q (use %s)", opts.Workload, strings.Join(BenchWorkloads, ", "))
+	}
+	if opts.Windows < 1 || opts.Frames < 1 {
+		return BenchResult{}, fmt.Errorf("windows and frames must be at least 1")
+	}
+	if opts.Width < 20 || opts.Height < 10 {
+		return BenchResult{}, fmt.Errorf("screen must be at least 20x10, got %dx%d", opts.Width, opts.Height)
+	}
+
+	// Windows land at their place at once instead of animating there
+	suppressed := config.AnimationsSuppressed
+	config.AnimationsSuppressed = true
+	defer func() { config.AnimationsSuppressed = suppressed }()
+
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = opts.Width, opts.Height
+	m.AutoTiling = opts.Tiling
+	for i := range opts.Windows {
+		width, height := max(opts.Width/2, 10), max(opts.Height/2, 5)
+		x := min(i*4, opts.Width-width)
+		y := m.GetTopMargin() + min(i*2, opts.Height-height)
+		w := terminal.NewDaemonWindow(createID(), fmt.Sprintf("bench %d", i+1), x, y, width, height, i, "")
+		w.Workspace = m.CurrentWorkspace
+		m.Windows = append(m.Windows, w)
+	}
+	m.FocusWindow(len(m.Windows) - 1)
+	if m.AutoTiling {
+		m.TileAllWindows()
+	}
+
+	result := BenchResult{Workload: opts.Workload, Windows: opts.Windows, Frames: opts.Frames, Width: opts.Width, Height: opts.Height}
+	frameTimes := make([]time.Duration, 0, opts.Frames)
+	var before, after runtime.MemStats
+	var allocs, bytes uint64
+	for frame := range opts.Frames {
+		start := time.Now()
+		benchStep(m, opts, frame)
+		result.Output += time.Since(start)
+
+		runtime.ReadMemStats(&before)
+		start = time.Now()
+		_ = m.View()
+		frameTimes = append(frameTimes, time.Since(start))
+		runtime.ReadMemStats(&after)
+		allocs += after.Mallocs - before.Mallocs
+		bytes += after.TotalAlloc - before.TotalAlloc
+	}
+
+	for _, d := range frameTimes {
+		result.Total += d
+	}
+	slices.Sort(frameTimes)
+	percentile := func(p int) time.Duration {
+		return frameTimes[min(len(frameTimes)*p/100, len(frameTimes)-1)]
+	}
+	result.Min, result.Max = frameTimes[0], frameTimes[len(frameTimes)-1]
+	result.Avg = result.Total / time.Duration(len(frameTimes))
+	result.P50, result.P95, result.P99 = percentile(50), percentile(95), percentile(99)
+	result.AllocsPerFrame = float64(allocs) / float64(opts.Frames)
+	result.BytesPerFrame = float64(bytes) / float64(opts.Frames)
+	return result, nil
+}
+
+// benchStep applies one frame of the workload: output written to every
+// window and, for the resize workload, a screen size change.
+func benchStep(m *OS, opts BenchOptions, frame int) {
+	if opts.Workload == BenchWorkloadIdle {
+		return
+	}
+	if opts.Workload == BenchWorkloadResize && frame%benchResizeEvery == 0 {
+		width, height := opts.Width, opts.Height
+		if frame/benchResizeEvery%2 == 1 {
+			width, height = width*3/4, height*3/4
+		}
+		m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	}
+	for i, w := range m.Windows {
+		w.WriteOutput(benchOutput(opts.Workload, frame, i, max(opts.LinesPerFrame, 1)))
+	}
+}
+
+// benchOutput returns the lines written to a window for one frame.
+func benchOutput(workload string, frame, window, lines int) []byte {
+	var b strings.Builder
+	for line := range lines {
+		n := frame*lines + line
+		if workload == BenchWorkloadColor {
+			for word := range 8 {
+				fmt.Fprintf(&b, "\x1b[38;5;%dm\x1b[48;5;%dmword%d ", (n+word)%256, (n*7+word)%256, word)
+			}
+			b.WriteString("\x1b[1mbold\x1b[0m\r\n")
+			continue
+		}
+		fmt.Fprintf(&b, "window %d frame %d line %d: the quick brown fox jumps over the lazy dog\r\n", window, frame, n)
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,19 @@
+package app
+
+import "testing"
+
+// TestRunBench verifies every workload runs and reports ordered frame times.
+func TestRunBench(t *testing.T) {
+	if _, err := RunBench(BenchOptions{Workload: "nope", Windows: 1, Frames: 1, Width: 80, Height: 24}); err == nil {
+		t.Error("expected an unknown workload to be rejected")
+	}
+	for _, workload := range BenchWorkloads {
+		result, err := RunBench(BenchOptions{Workload: workload, Windows: 2, Frames: 12, Width: 80, Height: 24, LinesPerFrame: 3, Tiling: true})
+		if err != nil {
+			t.Fatalf("%s: %v", workload, err)
+		}
+		if result.Frames != 12 || result.Min <= 0 || result.Min > result.P50 || result.P50 > result.Max {
+			t.Errorf("%s: expected ordered frame times for 12 frames, got %+v", workload, result)
+		}
+	}
+}
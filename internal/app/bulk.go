@@ -0,0 +1,141 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// closeAllListLimit is how many windows the close-all confirmation lists
+// before summarizing the rest.
+const closeAllListLimit = 8
+
+// workspaceWindows returns the windows of the current workspace, including
+// minimized and swallowed ones, in window order.
+func (m *OS) workspaceWindows() []*terminal.Window {
+	var windows []*terminal.Window
+	for _, w := range m.Windows {
+		if w.Workspace == m.CurrentWorkspace {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// RequestCloseAllWindows asks for confirmation before closing every window
+// of the current workspace.
+func (m *OS) RequestCloseAllWindows() {
+	windows := m.workspaceWindows()
+	if len(windows) == 0 {
+		m.ShowNotification("No windows in this workspace", "info", config.NotificationDuration)
+		return
+	}
+	title := fmt.Sprintf("Close %d windows in workspace %d?", len(windows), m.CurrentWorkspace)
+	m.requestCloseWindows(windows, m.RunningPrograms(windows), title)
+}
+
+// requestCloseWindows opens the close-all confirmation for windows, asking
+// title and listing the programs running in them.
+func (m *OS) requestCloseWindows(windows []*terminal.Window, running map[string]string, title string) {
+	m.CloseAllTargets = windows
+	m.CloseAllRunning = running
+	m.CloseAllTitle = title
+	m.closeAllFromSidebar = false
+	m.ShowCloseAllConfirm = true
+	m.CloseAllSelection = 1 // Default to No, closing kills running programs
+}
+
+// CancelCloseAllWindows dismisses the close-all confirmation.
+func (m *OS) CancelCloseAllWindows() {
+	m.ShowCloseAllConfirm = false
+	m.CloseAllTargets = nil
+}
+
+// CloseAllWindows closes the windows of the close-all confirmation and
+// dismisses it.
+func (m *OS) CloseAllWindows() {
+	m.ShowCloseAllConfirm = false
+	closing := m.CloseAllTargets
+	m.CloseAllTargets = nil
+	m.closeWindows(closing)
+	if m.closeAllFromSidebar {
+		m.finishSidebarBatch()
+	} else if len(m.Windows) == 0 {
+		m.Mode = WindowManagementMode
+	}
+	m.ShowNotification(fmt.Sprintf("Closed %d windows", len(closing)), "info", config.NotificationDuration)
+	m.MarkAllDirty()
+}
+
+// closeWindows closes the windows that are still open.
+func (m *OS) closeWindows(windows []*terminal.Window) {
+	for _, window := range windows {
+		if i := slices.Index(m.Windows, window); i >= 0 {
+			m.DeleteWindow(i)
+		}
+	}
+}
+
+// MinimizeAllWindows minimizes every visible window of the current
+// workspace.
+func (m *OS) MinimizeAllWindows() {
+	count := 0
+	for i, w := range m.Windows {
+		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing {
+			m.minimizeWindow(i)
+			count++
+		}
+	}
+	if count == 0 {
+		m.ShowNotification("No windows to minimize", "info", config.NotificationDuration)
+		return
+	}
+	m.Mode = WindowManagementMode
+	m.MarkAllDirty()
+}
+
+// RestoreAllWindows restores every minimized window of the current
+// workspace.
+func (m *OS) RestoreAllWindows() {
+	for i, w := range m.Windows {
+		if w.Minimized && w.Workspace == m.CurrentWorkspace {
+			m.RestoreWindow(i)
+		}
+	}
+	// Retile if in tiling mode
+	if m.AutoTiling {
+		m.TileAllWindows()
+	}
+}
+
+// renderCloseAllConfirmDialog renders the close-all confirmation, listing the
+// windows that will close and the programs still running in them.
+func (m *OS) renderCloseAllConfirmDialog() (string, int, int) {
+	windows := m.CloseAllTargets
+	var details []string
+	running := 0
+	for n, w := range windows {
+		process := m.CloseAllRunning[w.ID]
+		if process != "" {
+			running++
+		}
+		if n >= closeAllListLimit {
+			continue
+		}
+		line := ansi.Truncate(m.getWindowDisplayName(w), 30, "…")
+		if process != "" {
+			line += " (running " + process + ")"
+		}
+		details = append(details, line)
+	}
+	if extra := len(windows) - closeAllListLimit; extra > 0 {
+		details = append(details, fmt.Sprintf("and %d more", extra))
+	}
+	if running > 0 {
+		details = append(details, "", fmt.Sprintf("%d running programs will be killed.", running))
+	}
+	return renderConfirmDialog(m.CloseAllTitle, details, m.CloseAllSelection)
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestBulkWindowOperations(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", Width: 40, Height: 20, Workspace: 2},
+		{ID: "window-three-00", Width: 40, Height: 20, Workspace: 1},
+	}
+
+	m.MinimizeAllWindows()
+	if !m.Windows[0].Minimized || !m.Windows[2].Minimized || m.Windows[1].Minimized {
+		t.Error("expected only the windows of workspace 1 minimized")
+	}
+	m.RestoreAllWindows()
+	if m.Windows[0].Minimized || m.Windows[2].Minimized {
+		t.Error("expected the windows of workspace 1 restored")
+	}
+
+	m.RequestCloseAllWindows()
+	if !m.ShowCloseAllConfirm {
+		t.Fatal("expected a confirmation before closing all windows")
+	}
+	m.CloseAllWindows()
+	if m.ShowCloseAllConfirm || len(m.Windows) != 1 || m.Windows[0].Workspace != 2 {
+		t.Errorf("expected only the window of workspace 2 left, got %d windows", len(m.Windows))
+	}
+}
@@ -0,0 +1,225 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+)
+
+// bundleVersion is the version of the session bundle format written by
+// ExportSessionBundle. Bundles of a newer version are refused.
+const bundleVersion = 1
+
+// SessionBundle is a shareable description of a session: its workspaces
+// and their names, the windows with their names, geometry, working
+// directories and the commands running in them, and the tiling layout.
+// Importing it recreates the windows in each directory, running each
+// command again.
+type SessionBundle struct {
+	Version    int                   `json:"version"`
+	ExportedAt time.Time             `json:"exported_at"`
+	State      *session.SessionState `json:"state"`
+}
+
+// BuildSessionBundle describes the current session as a bundle. Working
+// directories below the home directory are written relative to it, so the
+// bundle works for someone else with the same checkout under their home.
+func (m *OS) BuildSessionBundle() *SessionBundle {
+	state := m.BuildRecoveryState()
+	processes := m.windowProcesses()
+	home, _ := os.UserHomeDir()
+	for i := range state.Windows {
+		ws := &state.Windows[i]
+		if info, ok := processes[ws.ID]; ok {
+			ws.Run = ShellJoin(info.Command)
+			if ws.Cwd == "" {
+				ws.Cwd = info.Cwd
+			}
+		}
+		if home != "" && (ws.Cwd == home || strings.HasPrefix(ws.Cwd, home+string(filepath.Separator))) {
+			ws.Cwd = "~" + strings.TrimPrefix(ws.Cwd, home)
+		}
+		ws.IsAltScreen = false
+	}
+	return &SessionBundle{Version: bundleVersion, ExportedAt: time.Now(), State: state}
+}
+
+// windowProcesses returns the command running in each window and its
+// working directory, by window ID. Daemon sessions ask the daemon, which
+// owns the processes; local windows are asked directly.
+func (m *OS) windowProcesses() map[string]session.PTYInfo {
+	if m.DaemonClient == nil {
+		processes := make(map[string]session.PTYInfo, len(m.Windows))
+		for _, w := range m.Windows {
+			if w.Pty != nil {
+				processes[w.ID] = session.PTYInfo{Command: w.ForegroundCommandLine(), Cwd: w.WorkingDirectory()}
+			}
+		}
+		return processes
+	}
+	ptys, err := m.DaemonClient.ListPTYs()
+	if err != nil {
+		m.LogError("Failed to list the processes of the windows: %v", err)
+		return nil
+	}
+	byPTY := make(map[string]session.PTYInfo, len(ptys))
+	for _, pty := range ptys {
+		byPTY[pty.ID] = pty
+	}
+	processes := make(map[string]session.PTYInfo, len(m.Windows))
+	for _, w := range m.Windows {
+		if info, ok := byPTY[w.PTYID]; ok && w.PTYID != "" {
+			processes[w.ID] = info
+		}
+	}
+	return processes
+}
+
+// ExportSessionBundle writes the session bundle to path and returns the
+// number of windows in it.
+func (m *OS) ExportSessionBundle(path string) (int, error) {
+	bundle := m.BuildSessionBundle()
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode session bundle: %w", err)
+	}
+	if err := os.WriteFile(config.ExpandHome(path), append(data, '\n'), 0o644); err != nil {
+		return 0, fmt.Errorf("failed to write session bundle: %w", err)
+	}
+	m.LogInfo("Exported %d windows to session bundle %s", len(bundle.State.Windows), path)
+	return len(bundle.State.Windows), nil
+}
+
+// LoadSessionBundle reads and validates a session bundle.
+func LoadSessionBundle(path string) (*SessionBundle, error) {
+	data, err := os.ReadFile(config.ExpandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session bundle: %w", err)
+	}
+	return ParseSessionBundle(data)
+}
+
+// ParseSessionBundle parses and validates session bundle contents.
+func ParseSessionBundle(data []byte) (*SessionBundle, error) {
+	var bundle SessionBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse session bundle: %w", err)
+	}
+	if bundle.Version < 1 || bundle.Version > bundleVersion {
+		return nil, fmt.Errorf("unsupported session bundle version %d (this tuios reads version %d)", bundle.Version, bundleVersion)
+	}
+	if bundle.State == nil || len(bundle.State.Windows) == 0 {
+		return nil, fmt.Errorf("session bundle has no windows")
+	}
+	for _, ws := range bundle.State.Windows {
+		if ws.ID == "" {
+			return nil, fmt.Errorf("session bundle has a window without an id")
+		}
+		if ws.Workspace < 1 || ws.Workspace > 9 {
+			return nil, fmt.Errorf("window %s: workspace must be between 1 and 9", ws.ID)
+		}
+	}
+	return &bundle, nil
+}
+
+// Choices of the popup confirming the commands of an imported bundle.
+var bundleCommandChoices = []string{"Run", "Don't run"}
+
+// bundleCommand is a command of an imported bundle, waiting to be run in
+// the window it was saved with.
+type bundleCommand struct {
+	window string // Name of the window, or its ID when it has none
+	run    string
+}
+
+// ImportSessionBundle recreates the windows of a session bundle as local
+// windows, started in each window's directory. When the bundle has commands
+// they are shown first, and the windows are only created once that is
+// answered: with Run the windows with a command run it in place of a shell,
+// else every window gets a shell. It returns the number of windows created
+// right away.
+func (m *OS) ImportSessionBundle(bundle *SessionBundle) int {
+	if bundle == nil || bundle.State == nil {
+		return 0
+	}
+	state := *bundle.State
+	state.Windows = make([]session.WindowState, len(bundle.State.Windows))
+	var commands []bundleCommand
+	for i, ws := range bundle.State.Windows {
+		ws.Cwd = config.ExpandHome(ws.Cwd)
+		ws.Clipboard = "" // A shared bundle does not get to grant clipboard access
+		state.Windows[i] = ws
+		if ws.Run != "" {
+			name := ws.CustomName
+			if name == "" {
+				name = ws.ID
+			}
+			commands = append(commands, bundleCommand{window: name, run: ws.Run})
+		}
+	}
+	if len(commands) == 0 {
+		return m.restoreBundle(&state)
+	}
+	m.confirmBundleCommands(commands, func(run bool) {
+		if !run {
+			m.LogInfo("Did not run the %d commands of the session bundle", len(commands))
+			for i := range state.Windows {
+				state.Windows[i].Run = ""
+			}
+		}
+		m.restoreBundle(&state)
+	})
+	return 0
+}
+
+// restoreBundle creates the windows of an imported bundle and returns how
+// many were created.
+func (m *OS) restoreBundle(state *session.SessionState) int {
+	created := m.RestoreLocalLayout(state)
+	m.LogInfo("Imported %d windows from session bundle", created)
+	return created
+}
+
+// confirmBundleCommands shows the commands of an imported bundle, each with
+// its window, and calls done with whether Run was picked. A bundle can come
+// from anyone, so "Don't run" is selected at first and typing cannot pick
+// Run.
+func (m *OS) confirmBundleCommands(commands []bundleCommand, done func(run bool)) {
+	lines := make([]string, len(commands))
+	for i, c := range commands {
+		lines[i] = visibleControls(c.window + ": " + c.run)
+	}
+	body := fmt.Sprintf("The session bundle runs these commands:\n\n%s", strings.Join(lines, "\n"))
+	popup := m.ShowPopup("Run bundle commands", body, 0, bundleCommandChoices, "")
+	popup.Selection = 1
+	popup.Guarded = true
+	popup.OnClose = func(index int) {
+		done(index == 0)
+	}
+}
+
+// ShellJoin joins command arguments into a command line for POSIX shells,
+// quoting the arguments that need it.
+func ShellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsFunc(arg, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,+@%", r))
+		}) {
+			arg = shellQuote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
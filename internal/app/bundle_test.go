@@ -0,0 +1,104 @@
+package app
+
+import (
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestSessionBundleRoundTrip(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", CustomName: "api", X: 1, Y: 2, Width: 40, Height: 20, Workspace: 1, PTYID: "pty-1"},
+		{ID: "window-two-0000", X: 41, Y: 2, Width: 40, Height: 20, Workspace: 2},
+	}
+	m.WorkspaceNames = map[int]string{2: "logs"}
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	windows, err := m.ExportSessionBundle(path)
+	if err != nil || windows != 2 {
+		t.Fatalf("ExportSessionBundle = %d, %v", windows, err)
+	}
+	bundle, err := LoadSessionBundle(path)
+	if err != nil {
+		t.Fatalf("LoadSessionBundle failed: %v", err)
+	}
+	if bundle.Version != bundleVersion || len(bundle.State.Windows) != 2 {
+		t.Fatalf("unexpected bundle: %+v", bundle)
+	}
+	if w := bundle.State.Windows[0]; w.CustomName != "api" || w.PTYID != "" || w.Workspace != 1 {
+		t.Errorf("unexpected window state: %+v", w)
+	}
+	if bundle.State.Windows[1].Workspace != 2 || bundle.State.WorkspaceNames[2] != "logs" {
+		t.Errorf("unexpected workspaces: %+v", bundle.State)
+	}
+
+	for name, data := range map[string]string{
+		"newer version": `{"version": 99, "state": {"windows": [{"id": "a", "workspace": 1}]}}`,
+		"no windows":    `{"version": 1, "state": {"windows": []}}`,
+		"bad workspace": `{"version": 1, "state": {"windows": [{"id": "a", "workspace": 12}]}}`,
+	} {
+		if _, err := ParseSessionBundle([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if got, want := ShellJoin([]string{"go", "test", "-run", "Test Foo", "./..."}), `go test -run 'Test Foo' ./...`; got != want {
+		t.Errorf("ShellJoin = %q, want %q", got, want)
+	}
+}
+
+// TestImportedBundleCommandsNeedConfirmation verifies the commands of an
+// imported bundle are shown first and only run as their windows' commands
+// once Run is picked.
+func TestImportedBundleCommandsNeedConfirmation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	t.Setenv("SHELL", "/bin/sh")
+	dir := t.TempDir()
+	bundle := &SessionBundle{Version: bundleVersion, State: &session.SessionState{
+		CurrentWorkspace: 1,
+		Windows: []session.WindowState{{
+			ID: "window-one-0000", CustomName: "api", Workspace: 1, Width: 40, Height: 20,
+			Cwd: dir, Run: "sleep 30 #\x1b[2J",
+		}},
+	}}
+	closeWindows := func(m *OS) {
+		for _, w := range m.Windows {
+			w.Close()
+		}
+	}
+
+	m := NewOS(OSOptions{})
+	if created := m.ImportSessionBundle(bundle); created != 0 || len(m.Windows) != 0 {
+		t.Fatalf("expected no windows before the commands are confirmed, got %d", len(m.Windows))
+	}
+	if m.Popup == nil || m.Popup.Selection != 1 {
+		t.Fatalf("expected a confirmation with Don't run selected, got %+v", m.Popup)
+	}
+	if !strings.Contains(m.Popup.Body, "api: sleep 30 #·[2J") {
+		t.Errorf("expected the command shown with its controls visible, got %q", m.Popup.Body)
+	}
+	m.ClosePopup(m.Popup.Selection)
+	defer closeWindows(m)
+	if len(m.Windows) != 1 || m.Windows[0].Cmd == nil || slices.Contains(m.Windows[0].Cmd.Args, "-c") {
+		t.Fatalf("expected a window with a shell without confirmation, got %d windows", len(m.Windows))
+	}
+
+	m = NewOS(OSOptions{})
+	m.ImportSessionBundle(bundle)
+	m.ClosePopup(0)
+	defer closeWindows(m)
+	if len(m.Windows) != 1 || m.Windows[0].Cmd == nil {
+		t.Fatalf("expected the window created once confirmed, got %d windows", len(m.Windows))
+	}
+	if cmd := m.Windows[0].Cmd; !slices.Equal(cmd.Args, []string{"/bin/sh", "-c", "sleep 30 #\x1b[2J"}) || cmd.Dir != dir {
+		t.Errorf("window runs %q in %q, want the bundle command in %s", cmd.Args, cmd.Dir, dir)
+	}
+}
@@ -8,6 +8,10 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // HostCapabilities holds information about the host terminal's capabilities.
@@ -16,6 +20,10 @@ type HostCapabilities struct {
 	KittyGraphics bool
 	SixelGraphics bool
 	TrueColor     bool
+	SyncOutput    bool // Synchronized output (DEC mode 2026)
+	Clipboard     bool // Clipboard writes through OSC 52
+	Hyperlinks    bool // OSC 8 hyperlinks
+	Responded     bool // The host answered the startup queries
 	TerminalName  string
 	PixelWidth    int
 	PixelHeight   int
@@ -90,14 +98,18 @@ func DetectHostCapabilities() *HostCapabilities {
 	// Query graphics support - use fast method with fallback
 	queryGraphicsSupport(caps)
 
+	// Fill in what the host cannot be asked about from its name
+	detectFromTerminalName(caps)
+
 	// Apply environment overrides
 	applyEnvironmentOverrides(caps)
 
 	// Debug output if requested - writes to /tmp/tuios_caps.log
 	if os.Getenv("TUIOS_DEBUG_CAPS") == "1" {
 		if f, err := os.OpenFile("/tmp/tuios_caps.log", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644); err == nil {
-			_, _ = fmt.Fprintf(f, "Terminal: %s\nKitty: %v\nSixel: %v\nTrueColor: %v\nCell: %dx%d\nPixel: %dx%d\n",
-				caps.TerminalName, caps.KittyGraphics, caps.SixelGraphics, caps.TrueColor,
+			_, _ = fmt.Fprintf(f, "Terminal: %s\nResponded: %v\nKitty: %v\nSixel: %v\nTrueColor: %v\nSyncOutput: %v\nClipboard: %v\nHyperlinks: %v\nCell: %dx%d\nPixel: %dx%d\n",
+				caps.TerminalName, caps.Responded, caps.KittyGraphics, caps.SixelGraphics, caps.TrueColor,
+				caps.SyncOutput, caps.Clipboard, caps.Hyperlinks,
 				caps.CellWidth, caps.CellHeight, caps.PixelWidth, caps.PixelHeight)
 			_ = f.Close()
 		}
@@ -179,19 +191,42 @@ func queryGraphicsSupport(caps *HostCapabilities) {
 	}
 	defer restoreTerminal(tty.Fd(), oldState)
 
-	// Send both queries at once
-	_, _ = tty.WriteString("\x1b[c")                                    // DA1 for sixel
+	// Send every query at once. DA1 goes last: terminals answer in order and
+	// every terminal answers DA1, so its reply means the others are in.
 	_, _ = tty.WriteString("\x1b_Gi=1,a=q,t=d,f=24,s=1,v=1;AAAA\x1b\\") // Kitty graphics query
+	_, _ = tty.WriteString(ansi.RequestModeSynchronizedOutput)          // DECRQM for synchronized output
+	_, _ = tty.WriteString("\x1b[c")                                    // DA1 for sixel and clipboard
 
 	// Read response with timeout (300ms to account for slower terminals)
-	response := readTTYResponse(tty, 300*time.Millisecond)
+	response := readTTYUntil(tty, 300*time.Millisecond, func(response string) bool {
+		return da1Re.MatchString(response)
+	})
+	parseProbeResponse(caps, response)
+}
 
-	// Parse DA1 response for sixel (look for "4" in params)
-	da1Re := regexp.MustCompile(`\x1b\[\?([0-9;]+)c`)
+var (
+	da1Re    = regexp.MustCompile(`\x1b\[\?([0-9;]+)c`)
+	decrpmRe = regexp.MustCompile(`\x1b\[\?2026;([0-9])\$y`)
+)
+
+// parseProbeResponse records what the host's answers to the startup
+// queries say it supports.
+func parseProbeResponse(caps *HostCapabilities, response string) {
+	// Parse DA1 response: "4" is sixel, "52" clipboard access through OSC 52
 	if matches := da1Re.FindStringSubmatch(response); len(matches) >= 2 {
-		if slices.Contains(slices.Collect(strings.SplitSeq(matches[1], ";")), "4") {
+		caps.Responded = true
+		params := slices.Collect(strings.SplitSeq(matches[1], ";"))
+		if slices.Contains(params, "4") {
 			caps.SixelGraphics = true
 		}
+		if slices.Contains(params, "52") {
+			caps.Clipboard = true
+		}
+	}
+
+	// Parse DECRPM: the mode is known (set or reset) unless it answers 0 or 4
+	if matches := decrpmRe.FindStringSubmatch(response); len(matches) == 2 {
+		caps.SyncOutput = matches[1] == "1" || matches[1] == "2"
 	}
 
 	// Parse Kitty response (look for OK)
@@ -200,6 +235,94 @@ func queryGraphicsSupport(caps *HostCapabilities) {
 	}
 }
 
+// Terminals known to support what cannot be queried, by detected name.
+var (
+	trueColorTerminals = []string{"ghostty", "kitty", "wezterm", "konsole", "iterm2", "alacritty", "foot", "contour", "mintty"}
+	clipboardTerminals = []string{"ghostty", "kitty", "wezterm", "iterm2", "alacritty", "foot", "contour", "mintty"}
+	hyperlinkTerminals = []string{"ghostty", "kitty", "wezterm", "konsole", "iterm2", "alacritty", "foot", "contour", "mintty"}
+)
+
+// detectFromTerminalName fills in the capabilities the host has no query
+// for from the terminal it was detected to be. Hosts that forward OSC 52
+// themselves (tmux, Windows Terminal) count as supporting the clipboard.
+func detectFromTerminalName(caps *HostCapabilities) {
+	if slices.Contains(trueColorTerminals, caps.TerminalName) {
+		caps.TrueColor = true
+	}
+	if slices.Contains(clipboardTerminals, caps.TerminalName) || os.Getenv("TMUX") != "" || os.Getenv("WT_SESSION") != "" {
+		caps.Clipboard = true
+	}
+	if slices.Contains(hyperlinkTerminals, caps.TerminalName) || os.Getenv("WT_SESSION") != "" {
+		caps.Hyperlinks = true
+	}
+	// VTE (GNOME Terminal, Tilix, ...) supports hyperlinks since 0.50
+	if vte, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		caps.Hyperlinks = true
+	}
+}
+
+// FilterHostCapabilities adjusts the messages Bubble Tea acts on itself to
+// the host's capabilities. Synchronized output is only turned on for hosts
+// that support it, and the color profile follows TrueColor. It is meant to
+// run first in the program's message filter.
+func FilterHostCapabilities(msg tea.Msg) tea.Msg {
+	caps := GetHostCapabilities()
+	switch msg := msg.(type) {
+	case tea.ModeReportMsg:
+		if msg.Mode == ansi.ModeSynchronizedOutput && !caps.SyncOutput {
+			return nil
+		}
+	case tea.ColorProfileMsg:
+		switch {
+		case caps.TrueColor && msg.Profile == colorprofile.ANSI256:
+			msg.Profile = colorprofile.TrueColor
+		case !caps.TrueColor && msg.Profile == colorprofile.TrueColor:
+			msg.Profile = colorprofile.ANSI256
+		}
+		return msg
+	}
+	return msg
+}
+
+// syncOutputCmd turns on synchronized output for hosts that support it. It
+// reports the mode as supported and off, the answer Bubble Tea waits for
+// before it wraps frames in DEC 2026, since Bubble Tea only asks some hosts
+// itself.
+func syncOutputCmd() tea.Cmd {
+	if !GetHostCapabilities().SyncOutput {
+		return nil
+	}
+	return func() tea.Msg {
+		return tea.ModeReportMsg{Mode: ansi.ModeSynchronizedOutput, Value: ansi.ModeReset}
+	}
+}
+
+// readTTYUntil reads from tty until done reports the response complete or
+// the timeout passes.
+func readTTYUntil(tty *os.File, timeout time.Duration, done func(string) bool) string {
+	buf := make([]byte, 512)
+	var result strings.Builder
+	deadline := time.Now().Add(timeout)
+
+	for !done(result.String()) {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+		ready, err := pollReadable(tty.Fd(), remaining)
+		if err != nil || !ready {
+			break
+		}
+		n, err := tty.Read(buf)
+		if err != nil {
+			break
+		}
+		result.Write(buf[:n])
+	}
+
+	return result.String()
+}
+
 // readTTYResponse reads from tty with a timeout using poll-based I/O
 func readTTYResponse(tty *os.File, timeout time.Duration) string {
 	buf := make([]byte, 512)
@@ -237,16 +360,48 @@ func readTTYResponse(tty *os.File, timeout time.Duration) string {
 }
 
 func applyEnvironmentOverrides(caps *HostCapabilities) {
-	if os.Getenv("TUIOS_KITTY_GRAPHICS") == "1" {
-		caps.KittyGraphics = true
-	} else if os.Getenv("TUIOS_KITTY_GRAPHICS") == "0" {
-		caps.KittyGraphics = false
+	envOverride("TUIOS_KITTY_GRAPHICS", &caps.KittyGraphics)
+	envOverride("TUIOS_SIXEL_GRAPHICS", &caps.SixelGraphics)
+	envOverride("TUIOS_TRUECOLOR", &caps.TrueColor)
+	envOverride("TUIOS_SYNC_OUTPUT", &caps.SyncOutput)
+	envOverride("TUIOS_CLIPBOARD", &caps.Clipboard)
+	envOverride("TUIOS_HYPERLINKS", &caps.Hyperlinks)
+}
+
+// CapabilityOverrides are the environment variables that force a detected
+// capability on (1) or off (0).
+var CapabilityOverrides = []string{
+	"TUIOS_KITTY_GRAPHICS", "TUIOS_SIXEL_GRAPHICS", "TUIOS_TRUECOLOR",
+	"TUIOS_SYNC_OUTPUT", "TUIOS_CLIPBOARD", "TUIOS_HYPERLINKS",
+}
+
+// CapabilityFeature describes one capability for the tuios doctor report.
+type CapabilityFeature struct {
+	Name      string `json:"name"`
+	Supported bool   `json:"supported"`
+	Used      string `json:"used_for"` // What TUIOS uses the capability for
+	Override  string `json:"override"` // Environment variable forcing it on or off
+}
+
+// Features lists the capabilities with what each is used for.
+func (caps *HostCapabilities) Features() []CapabilityFeature {
+	return []CapabilityFeature{
+		{"True color", caps.TrueColor, "Exact theme and program colors (otherwise 256 colors)", "TUIOS_TRUECOLOR"},
+		{"Kitty graphics", caps.KittyGraphics, "Images from programs in windows and the image viewer", "TUIOS_KITTY_GRAPHICS"},
+		{"Sixel graphics", caps.SixelGraphics, "Sixel images (detected only, passthrough is not supported yet)", "TUIOS_SIXEL_GRAPHICS"},
+		{"Synchronized output", caps.SyncOutput, "Drawing each frame at once, without tearing", "TUIOS_SYNC_OUTPUT"},
+		{"OSC 52 clipboard", caps.Clipboard, "Copy mode yanks and copying command output", "TUIOS_CLIPBOARD"},
+		{"OSC 8 hyperlinks", caps.Hyperlinks, "Passing on links from programs in windows", "TUIOS_HYPERLINKS"},
 	}
+}
 
-	if os.Getenv("TUIOS_SIXEL_GRAPHICS") == "1" {
-		caps.SixelGraphics = true
-	} else if os.Getenv("TUIOS_SIXEL_GRAPHICS") == "0" {
-		caps.SixelGraphics = false
+// envOverride sets a capability from an environment variable set to 1 or 0.
+func envOverride(name string, capability *bool) {
+	switch os.Getenv(name) {
+	case "1":
+		*capability = true
+	case "0":
+		*capability = false
 	}
 }
 
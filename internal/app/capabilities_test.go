@@ -0,0 +1,65 @@
+package app
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseProbeResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     HostCapabilities
+	}{
+		{"no answer", "", HostCapabilities{}},
+		{"plain DA1", "\x1b[?62;22c", HostCapabilities{Responded: true}},
+		{
+			"everything",
+			"\x1b_Gi=1;OK\x1b\\\x1b[?2026;2$y\x1b[?62;4;22;52c",
+			HostCapabilities{Responded: true, KittyGraphics: true, SixelGraphics: true, SyncOutput: true, Clipboard: true},
+		},
+		{"sync output unknown", "\x1b[?2026;0$y\x1b[?1;2c", HostCapabilities{Responded: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var caps HostCapabilities
+			parseProbeResponse(&caps, tt.response)
+			if caps != tt.want {
+				t.Errorf("got %+v, want %+v", caps, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterHostCapabilities(t *testing.T) {
+	t.Cleanup(ResetHostCapabilities)
+	syncReport := tea.ModeReportMsg{Mode: ansi.ModeSynchronizedOutput, Value: ansi.ModeReset}
+
+	SetClientCapabilities(&HostCapabilities{})
+	if msg := FilterHostCapabilities(syncReport); msg != nil {
+		t.Errorf("expected the sync output report to be dropped without support, got %v", msg)
+	}
+	if cmd := syncOutputCmd(); cmd != nil {
+		t.Error("expected no sync output command without support")
+	}
+	if msg := FilterHostCapabilities(tea.ColorProfileMsg{Profile: colorprofile.TrueColor}); msg.(tea.ColorProfileMsg).Profile != colorprofile.ANSI256 {
+		t.Errorf("expected true color to be lowered to 256 colors, got %v", msg)
+	}
+
+	SetClientCapabilities(&HostCapabilities{SyncOutput: true, TrueColor: true})
+	if msg := FilterHostCapabilities(syncReport); msg != syncReport {
+		t.Errorf("expected the sync output report to pass, got %v", msg)
+	}
+	if cmd := syncOutputCmd(); cmd == nil || cmd() != syncReport {
+		t.Error("expected the sync output command to report the mode as supported")
+	}
+	if msg := FilterHostCapabilities(tea.ColorProfileMsg{Profile: colorprofile.ANSI256}); msg.(tea.ColorProfileMsg).Profile != colorprofile.TrueColor {
+		t.Errorf("expected 256 colors to be raised to true color, got %v", msg)
+	}
+	if msg := FilterHostCapabilities(tea.ColorProfileMsg{Profile: colorprofile.Ascii}); msg.(tea.ColorProfileMsg).Profile != colorprofile.Ascii {
+		t.Errorf("expected a colorless profile to be kept, got %v", msg)
+	}
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// clipboardPreviewLines is how many lines of a clipboard write the
+// confirmation popup shows.
+const clipboardPreviewLines = 6
+
+// Choices of the clipboard write confirmation popup.
+var clipboardChoices = []string{"Allow", "Deny", "Always allow", "Always deny"}
+
+// HandleClipboardWrites applies the clipboard policy to the clipboard
+// writes programs asked for with OSC 52 since the last tick. Writes
+// larger than config.ClipboardWriteMaxBytes are dropped; the others are
+// passed to the host terminal, dropped or shown for confirmation as the
+// window's policy says. It returns the commands setting the host clipboard
+// and reports whether a confirmation popup opened or was queued.
+func (m *OS) HandleClipboardWrites() (tea.Cmd, bool) {
+	var cmds []tea.Cmd
+	for _, write := range m.approvedClipboard {
+		cmds = append(cmds, setClipboard(write))
+	}
+	m.approvedClipboard = nil
+
+	queued := len(m.popupQueue)
+	popup := m.Popup
+	for _, w := range m.Windows {
+		for _, t := range badgeTerminals(w) {
+			write, ok := t.TakeClipboardWrite()
+			if !ok {
+				continue
+			}
+			if cmd := m.handleClipboardWrite(w, write); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	}
+	return tea.Batch(cmds...), m.Popup != popup || len(m.popupQueue) != queued
+}
+
+// handleClipboardWrite applies the policy of window w to a clipboard write
+// from one of its terminals.
+func (m *OS) handleClipboardWrite(w *terminal.Window, write terminal.ClipboardWrite) tea.Cmd {
+	name := m.getWindowDisplayName(w)
+	if limit := config.ClipboardWriteMaxBytes; limit > 0 && len(write.Content) > limit {
+		m.LogInfo("Dropped clipboard write of %d bytes from %s (limit %d)", len(write.Content), name, limit)
+		m.ShowNotification(fmt.Sprintf("Blocked %d KiB clipboard write from %s (too large)", len(write.Content)/1024, name), "warning", config.NotificationDuration)
+		return nil
+	}
+
+	switch policy, _ := w.ClipboardWritePolicy(); policy {
+	case "deny":
+		m.LogInfo("Dropped clipboard write from %s (denied)", name)
+		return nil
+	case "confirm":
+		m.confirmClipboardWrite(w, write)
+		return nil
+	}
+	return setClipboard(write)
+}
+
+// confirmClipboardWrite asks whether to let a clipboard write from window w
+// through, showing the start of its content. The always choices set the
+// window's policy for the writes that follow. The popup opens while the
+// user may be typing, so Deny is selected at first and typing cannot pick
+// a choice; it waits behind popups already up.
+func (m *OS) confirmClipboardWrite(w *terminal.Window, write terminal.ClipboardWrite) {
+	name := m.getWindowDisplayName(w)
+	body := fmt.Sprintf("%s wants to set the clipboard to %d bytes:\n\n%s", name, len(write.Content), clipboardPreview(write.Content))
+	popup := m.ShowPopup("Clipboard write", body, 0, clipboardChoices, "")
+	popup.Selection = 1
+	popup.Guarded = true
+	windowID := w.ID
+	popup.OnClose = func(index int) {
+		if index == 2 || index == 3 {
+			policy := "allow"
+			if index == 3 {
+				policy = "deny"
+			}
+			if err := m.SetClipboardPolicy(windowID, policy); err != nil {
+				m.LogError("Failed to set the clipboard policy of %s: %v", name, err)
+			}
+		}
+		if index == 0 || index == 2 {
+			m.approvedClipboard = append(m.approvedClipboard, write)
+		} else {
+			m.LogInfo("Dropped clipboard write from %s (not confirmed)", name)
+		}
+	}
+}
+
+// SetClipboardPolicy sets the clipboard write policy of a window: allow,
+// confirm or deny, or "" to follow the window rules and
+// appearance.clipboard_write again.
+func (m *OS) SetClipboardPolicy(windowID, policy string) error {
+	if policy != "" && !slices.Contains(config.ClipboardWriteModes, policy) {
+		return fmt.Errorf("invalid clipboard policy %q (use: %s)", policy, strings.Join(config.ClipboardWriteModes, ", "))
+	}
+	w := m.findTerminal(windowID)
+	if w == nil {
+		return fmt.Errorf("window not found: %s", windowID)
+	}
+	w.ClipboardPolicy = policy
+	m.SyncStateToDaemon()
+	return nil
+}
+
+// setClipboard returns the command passing a clipboard write to the host
+// terminal: the primary selection when the program named only that one,
+// otherwise the clipboard.
+func setClipboard(write terminal.ClipboardWrite) tea.Cmd {
+	if write.Selection == "p" {
+		return tea.SetPrimaryClipboard(write.Content)
+	}
+	return tea.SetClipboard(write.Content)
+}
+
+// clipboardPreview returns the first lines of clipboard content for the
+// confirmation popup, with control characters made visible so the content
+// cannot redraw the screen or pose as part of the popup.
+func clipboardPreview(content string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	more := len(lines) - clipboardPreviewLines
+	if more > 0 {
+		lines = lines[:clipboardPreviewLines]
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(visibleControls(line), popupMaxWidth, "…")
+	}
+	if more > 0 {
+		lines = append(lines, fmt.Sprintf("… %d more lines", more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// visibleControls returns a line of untrusted text for a popup, with tabs
+// made spaces and other control characters dots.
+func visibleControls(line string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return '·'
+		}
+		return r
+	}, line)
+}
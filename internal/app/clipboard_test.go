@@ -0,0 +1,69 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestClipboardWritePolicy(t *testing.T) {
+	prevPolicy, prevMax := config.ClipboardWrite, config.ClipboardWriteMaxBytes
+	t.Cleanup(func() { config.ClipboardWrite, config.ClipboardWriteMaxBytes = prevPolicy, prevMax })
+
+	m := NewOS(OSOptions{})
+	w := terminal.NewDaemonWindow("window-clip-0000", "", 0, 0, 40, 20, 0, "")
+	t.Cleanup(w.Close)
+	m.Windows = []*terminal.Window{w}
+	write := func() (bool, bool) {
+		_, _ = w.Terminal.Write([]byte("\x1b]52;c;aGVsbG8gd29ybGQ=\x07"))
+		cmd, prompted := m.HandleClipboardWrites()
+		return cmd != nil, prompted
+	}
+
+	config.ClipboardWrite = "allow"
+	if set, prompted := write(); !set || prompted {
+		t.Errorf("allow: set = %v, prompted = %v", set, prompted)
+	}
+
+	config.ClipboardWrite = "deny"
+	if set, prompted := write(); set || prompted {
+		t.Errorf("deny: set = %v, prompted = %v", set, prompted)
+	}
+
+	config.ClipboardWrite = "confirm"
+	if set, prompted := write(); set || !prompted || m.Popup == nil {
+		t.Fatalf("confirm: set = %v, prompted = %v", set, prompted)
+	}
+	if m.Popup.Selection != 1 || !m.Popup.Guarded {
+		t.Errorf("expected the confirmation guarded with Deny selected, got %+v", m.Popup)
+	}
+	// A second write waits behind the first confirmation
+	first := m.Popup
+	if set, prompted := write(); set || !prompted || m.Popup != first {
+		t.Fatalf("second confirm: set = %v, prompted = %v, popup replaced = %v", set, prompted, m.Popup != first)
+	}
+	m.ClosePopup(m.Popup.Selection) // Deny
+	if m.Popup == nil || m.Popup == first {
+		t.Fatal("expected the queued confirmation shown once the first closed")
+	}
+	if cmd, _ := m.HandleClipboardWrites(); cmd != nil {
+		t.Error("expected the denied write to be dropped")
+	}
+	m.ClosePopup(2) // Always allow
+	if policy, source := w.ClipboardWritePolicy(); policy != "allow" || source != "window" {
+		t.Errorf("policy after always allow = %s from %s", policy, source)
+	}
+	if cmd, _ := m.HandleClipboardWrites(); cmd == nil {
+		t.Error("expected the confirmed write to be sent")
+	}
+
+	config.ClipboardWriteMaxBytes = 4
+	if set, _ := write(); set {
+		t.Error("expected a write over the size limit to be dropped")
+	}
+
+	if err := m.SetClipboardPolicy(w.ID, "sometimes"); err == nil {
+		t.Error("expected an invalid policy to be refused")
+	}
+}
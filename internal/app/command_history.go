@@ -0,0 +1,177 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxCommandHistory is the number of commands kept in the command history.
+const maxCommandHistory = 1000
+
+// commandHistoryRows is the number of entries the history browser lists.
+const commandHistoryRows = 15
+
+// CollectCommandHistory adds the commands that finished in every window's
+// shell since the last call to the command history. Commands of panes and
+// tabs are filed under their window. A command that failed in a window out
+// of view flags the window urgent; it reports whether one did.
+func (m *OS) CollectCommandHistory() bool {
+	changed := false
+	for i, w := range m.Windows {
+		inView := i == m.FocusedWindow && w.Workspace == m.CurrentWorkspace && !w.Minimized
+		for _, t := range badgeTerminals(w) {
+			for _, record := range t.TakeCommands() {
+				record.WindowID = w.ID
+				m.CommandHistory = append(m.CommandHistory, record)
+				if record.ExitCode > 0 && !inView && w.UrgentSince.IsZero() {
+					markUrgent(w)
+					changed = true
+				}
+			}
+		}
+	}
+	if excess := len(m.CommandHistory) - maxCommandHistory; excess > 0 {
+		m.CommandHistory = slices.Delete(m.CommandHistory, 0, excess)
+	}
+	return changed
+}
+
+// ToggleCommandHistory shows or hides the command history browser.
+func (m *OS) ToggleCommandHistory() {
+	if m.ShowCommandHistory {
+		m.ShowCommandHistory = false
+		return
+	}
+	if len(m.CommandHistory) == 0 {
+		m.ShowNotification("No commands recorded (needs shell integration marks, OSC 133)", "info", config.NotificationDuration)
+		return
+	}
+	m.ShowCommandHistory = true
+	m.CommandHistoryQuery = ""
+	m.CommandHistorySelection = 0
+}
+
+// CommandHistoryMatches returns the commands matching the history query,
+// newest first. Every word of the query must appear in the command, its
+// directory or its window's name, ignoring case.
+func (m *OS) CommandHistoryMatches() []terminal.CommandRecord {
+	words := strings.Fields(strings.ToLower(m.CommandHistoryQuery))
+	var matches []terminal.CommandRecord
+	for i := len(m.CommandHistory) - 1; i >= 0; i-- {
+		record := m.CommandHistory[i]
+		text := strings.ToLower(record.Command + " " + record.Dir + " " + m.historyWindowName(record))
+		if !slices.ContainsFunc(words, func(word string) bool { return !strings.Contains(text, word) }) {
+			matches = append(matches, record)
+		}
+	}
+	return matches
+}
+
+// SetCommandHistoryQuery filters the history browser by query and selects
+// the newest match.
+func (m *OS) SetCommandHistoryQuery(query string) {
+	m.CommandHistoryQuery = query
+	m.CommandHistorySelection = 0
+}
+
+// MoveCommandHistorySelection moves the history browser highlight by delta
+// entries, staying within the matches.
+func (m *OS) MoveCommandHistorySelection(delta int) {
+	n := len(m.CommandHistoryMatches())
+	m.CommandHistorySelection = min(max(m.CommandHistorySelection+delta, 0), max(n-1, 0))
+}
+
+// UseCommandHistoryEntry closes the history browser and types the selected
+// command into the focused window, running it when run is set.
+func (m *OS) UseCommandHistoryEntry(run bool) {
+	matches := m.CommandHistoryMatches()
+	if m.CommandHistorySelection >= len(matches) {
+		return
+	}
+	m.ShowCommandHistory = false
+	w := m.GetFocusedWindow()
+	if w == nil {
+		m.ShowNotification("No window to send the command to", "warning", config.NotificationDuration)
+		return
+	}
+	input := matches[m.CommandHistorySelection].Command
+	if run {
+		input += "\r"
+	}
+	if err := w.ActivePane().SendInput([]byte(input)); err != nil {
+		m.LogError("Failed to send command from history: %v", err)
+	}
+}
+
+// historyWindowName returns the name of the window a command ran in.
+func (m *OS) historyWindowName(record terminal.CommandRecord) string {
+	for _, w := range m.Windows {
+		if w.ID == record.WindowID {
+			return m.getWindowDisplayName(w)
+		}
+	}
+	return "closed window"
+}
+
+// renderCommandHistory renders the command history browser: the query and
+// the matching commands, newest first, with their time, exit code and
+// window.
+func (m *OS) renderCommandHistory() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true)
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Background(lipgloss.Color("#4865f2")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+
+	width := min(max(m.GetRenderWidth()-10, 30), 100)
+	matches := m.CommandHistoryMatches()
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Command History (%d/%d)", len(matches), len(m.CommandHistory))),
+		"",
+		"Search: " + m.CommandHistoryQuery + "█",
+		"",
+	}
+
+	// Scroll so the selection stays in view
+	first := max(0, m.CommandHistorySelection-commandHistoryRows+1)
+	for n := first; n < len(matches) && n < first+commandHistoryRows; n++ {
+		record := matches[n]
+		status := okStyle.Render(" ok ")
+		switch {
+		case record.ExitCode < 0:
+			status = dimStyle.Render("  ? ")
+		case record.ExitCode > 0:
+			status = failStyle.Render(fmt.Sprintf("%4s", strconv.Itoa(record.ExitCode)))
+		}
+		window := ansi.Truncate(m.historyWindowName(record), 20, "…")
+		// The time, the status and the window take 15 columns around the command
+		commandWidth := max(width-ansi.StringWidth(window)-15, 10)
+		command := ansi.Truncate(record.Command, commandWidth, "…")
+		command += strings.Repeat(" ", commandWidth-ansi.StringWidth(command))
+		label := " " + record.Time.Format("15:04") + "  " + command + "  "
+		if n == m.CommandHistorySelection {
+			lines = append(lines, selectedStyle.Render(label)+status+" "+dimStyle.Render(window))
+		} else {
+			lines = append(lines, itemStyle.Render(label)+status+" "+dimStyle.Render(window))
+		}
+	}
+	if len(matches) == 0 {
+		lines = append(lines, dimStyle.Render(" No matching commands"))
+	}
+	lines = append(lines, "", dimStyle.Render("Type to search, ↑/↓ to move, Enter to run, Tab to paste, Esc to close"))
+
+	return lipgloss.NewStyle().
+		Border(getBorder()).
+		BorderForeground(lipgloss.Color("13")).
+		Padding(1, 2).
+		Background(lipgloss.Color("#1a1a2a")).
+		Render(strings.Join(lines, "\n"))
+}
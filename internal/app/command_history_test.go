@@ -0,0 +1,51 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestCommandHistoryMatches(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{{ID: "a", Title: "api"}, {ID: "b", CustomName: "logs"}}
+	m.CommandHistory = []terminal.CommandRecord{
+		{Command: "go test ./...", ExitCode: 1, WindowID: "a", Dir: "/src/api"},
+		{Command: "tail -f app.log", ExitCode: -1, WindowID: "b"},
+		{Command: "go build", ExitCode: 0, WindowID: "a"},
+	}
+
+	m.ToggleCommandHistory()
+	if !m.ShowCommandHistory {
+		t.Fatal("expected the history browser shown")
+	}
+	commands := func() []string {
+		var list []string
+		for _, record := range m.CommandHistoryMatches() {
+			list = append(list, record.Command)
+		}
+		return list
+	}
+	if got := commands(); len(got) != 3 || got[0] != "go build" {
+		t.Fatalf("expected every command, newest first, got %v", got)
+	}
+
+	m.SetCommandHistoryQuery("GO api")
+	if got := commands(); len(got) != 2 {
+		t.Errorf("expected the query words matched against commands, directories and windows, got %v", got)
+	}
+	m.SetCommandHistoryQuery("logs")
+	if got := commands(); len(got) != 1 || got[0] != "tail -f app.log" {
+		t.Errorf("expected the command of the logs window, got %v", got)
+	}
+
+	m.SetCommandHistoryQuery("")
+	m.MoveCommandHistorySelection(5)
+	if m.CommandHistorySelection != 2 {
+		t.Errorf("expected the selection to stop at the oldest match, got %d", m.CommandHistorySelection)
+	}
+	m.MoveCommandHistorySelection(-5)
+	if m.CommandHistorySelection != 0 {
+		t.Errorf("expected the selection to stop at the newest match, got %d", m.CommandHistorySelection)
+	}
+}
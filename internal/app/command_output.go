@@ -0,0 +1,98 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// lastCommandOutput returns the output of the last command run in the
+// focused terminal, or notifies why there is none.
+func (m *OS) lastCommandOutput() (string, bool) {
+	w := m.GetFocusedWindow()
+	if w == nil {
+		return "", false
+	}
+	output, ok := w.ActivePane().LastCommandOutput()
+	if !ok {
+		m.ShowNotification("No command output found (needs shell integration marks, OSC 133)", "warning", config.NotificationDuration)
+		return "", false
+	}
+	if output == "" {
+		m.ShowNotification("The last command printed nothing", "info", config.NotificationDuration)
+		return "", false
+	}
+	return output, true
+}
+
+// CopyLastCommandOutput copies the output of the last command run in the
+// focused terminal to the clipboard.
+func (m *OS) CopyLastCommandOutput() tea.Cmd {
+	output, ok := m.lastCommandOutput()
+	if !ok {
+		return nil
+	}
+	lines := strings.Count(output, "\n") + 1
+	return m.CopyToClipboard(output, fmt.Sprintf("Copied %d lines of command output", lines))
+}
+
+// OpenLastCommandOutput opens the output of the last command run in the
+// focused terminal in a pager in a new window, through a temporary file that
+// is removed when the window closes.
+func (m *OS) OpenLastCommandOutput() error {
+	output, ok := m.lastCommandOutput()
+	if !ok {
+		return nil
+	}
+	dir := m.GetFocusedWindow().ActivePane().WorkingDirectory()
+
+	f, err := os.CreateTemp("", "tuios-output-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to save command output: %w", err)
+	}
+	_, err = f.WriteString(output + "\n")
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to save command output: %w", err)
+	}
+
+	before := len(m.Windows)
+	m.AddCommandWindow("", append(pagerArgs(), f.Name()), dir)
+	if len(m.Windows) == before {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to start pager")
+	}
+	window := m.Windows[len(m.Windows)-1]
+	window.CustomName = "Command output"
+	window.TempFile = f.Name()
+	m.MarkAllDirty()
+	return nil
+}
+
+// pagerArgs returns the command line of the pager set in $PAGER, or of the
+// platform's default pager.
+func pagerArgs() []string {
+	if pager := strings.Fields(os.Getenv("PAGER")); len(pager) > 0 {
+		return pager
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"more"}
+	}
+	return []string{"less"}
+}
+
+// removeTempFile removes the temporary file shown in a window.
+func removeTempFile(w *terminal.Window) {
+	if w.TempFile != "" {
+		_ = os.Remove(w.TempFile)
+		w.TempFile = ""
+	}
+}
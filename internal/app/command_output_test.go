@@ -0,0 +1,31 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// TestCommandOutputWindowCleanup verifies the pager comes from $PAGER and
+// the output file is removed once its window closes.
+func TestCommandOutputWindowCleanup(t *testing.T) {
+	t.Setenv("PAGER", "less -R")
+	if got := pagerArgs(); !slices.Equal(got, []string{"less", "-R"}) {
+		t.Errorf("pagerArgs() = %q, want less -R", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "output.txt")
+	if err := os.WriteFile(path, []byte("output\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{{ID: "window-output-0", Workspace: 1, TempFile: path}}
+	m.FocusedWindow = 0
+	m.DeleteWindow(0)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the output file removed with its window, got %v", err)
+	}
+}
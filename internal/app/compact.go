@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/layout"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// updateCompactMode switches to the compact layout when the screen gets
+// smaller than appearance.compact_width or compact_height, and back to the
+// full layout when it is large enough again. The compact layout hides the
+// dock; the dock position it replaces is put back when it ends. It reports
+// whether the layout changed, leaving retiling to the caller.
+func (m *OS) updateCompactMode() bool {
+	compact := config.CompactScreen(m.screenWidth(), m.GetRenderHeight())
+	if compact == m.CompactMode {
+		return false
+	}
+	m.CompactMode = compact
+	if compact {
+		m.compactDockbarPosition = config.DockbarPosition
+		config.DockbarPosition = "hidden"
+		m.LogInfo("Compact layout on at %dx%d", m.screenWidth(), m.GetRenderHeight())
+		m.ShowNotification("Compact layout", "info", config.NotificationDuration)
+	} else {
+		config.DockbarPosition = m.compactDockbarPosition
+		m.LogInfo("Compact layout off at %dx%d", m.screenWidth(), m.GetRenderHeight())
+		m.ShowNotification("Full layout", "info", config.NotificationDuration)
+	}
+	m.MarkAllDirty()
+	return true
+}
+
+// bspLayout returns the rectangles the tiling tree gives its windows. In
+// the compact layout every window gets the whole tiling area instead, so
+// they stack like tabs, while the tree keeps its splits for later.
+func (m *OS) bspLayout(tree *layout.BSPTree) map[int]layout.Rect {
+	bounds := m.GetBSPBounds()
+	if !m.CompactMode {
+		return tree.ApplyLayoutWithMinSizes(bounds, m.bspMinSizes())
+	}
+	rects := make(map[int]layout.Rect)
+	for _, id := range tree.GetAllWindowIDs() {
+		rects[id] = bounds
+	}
+	return rects
+}
+
+// compactTabs returns the title bar of a window stacked by the compact
+// layout: the names of the windows in its stack, its own in brackets. It
+// returns an empty string for any other window.
+func (m *OS) compactTabs(w *terminal.Window) string {
+	if !m.CompactMode || w.Floating || w.PiP || !m.WorkspaceAutoTiling(w.Workspace) {
+		return ""
+	}
+	var tabs []string
+	for _, other := range m.Windows {
+		if other.Workspace != w.Workspace || other.Minimized || other.Floating || other.PiP {
+			continue
+		}
+		name := m.getWindowDisplayName(other)
+		if other == w {
+			name = fmt.Sprintf("[%s]", name)
+		}
+		tabs = append(tabs, name)
+	}
+	if len(tabs) < 2 {
+		return ""
+	}
+	return strings.Join(tabs, " ")
+}
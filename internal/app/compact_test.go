@@ -0,0 +1,63 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/layout"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestCompactMode(t *testing.T) {
+	savedWidth, savedHeight, savedDock := config.CompactWidth, config.CompactHeight, config.DockbarPosition
+	defer func() {
+		config.CompactWidth, config.CompactHeight, config.DockbarPosition = savedWidth, savedHeight, savedDock
+	}()
+	config.CompactWidth, config.CompactHeight, config.DockbarPosition = 100, 0, "top"
+
+	m := NewOS(OSOptions{})
+	m.NumWorkspaces = 9
+	m.CurrentWorkspace = 1
+	m.AutoTiling = true
+	m.Width, m.Height = 120, 40
+	one := &terminal.Window{ID: "window-one-0000", CustomName: "one", Width: 40, Height: 20, Workspace: 1}
+	two := &terminal.Window{ID: "window-two-0000", CustomName: "two", Width: 40, Height: 20, Workspace: 1}
+	float := &terminal.Window{ID: "window-float-00", CustomName: "float", Width: 20, Height: 10, Workspace: 1, Floating: true}
+	m.Windows = []*terminal.Window{one, two, float}
+	tree := m.GetOrCreateBSPTree()
+	tree.InsertWindow(m.getWindowIntID(one.ID), 0, layout.SplitNone, 0.5, m.GetBSPBounds())
+	tree.InsertWindow(m.getWindowIntID(two.ID), m.getWindowIntID(one.ID), layout.SplitNone, 0.5, m.GetBSPBounds())
+
+	if m.updateCompactMode() || m.CompactMode {
+		t.Fatal("expected the full layout on a large screen")
+	}
+	if rects := m.bspLayout(tree); rects[m.getWindowIntID(one.ID)] == rects[m.getWindowIntID(two.ID)] {
+		t.Errorf("expected the tiled windows side by side, got %v", rects)
+	}
+
+	m.Width = 80
+	if !m.updateCompactMode() || !m.CompactMode || config.DockbarPosition != "hidden" {
+		t.Fatalf("expected the compact layout with the dock hidden, got %v dock %s", m.CompactMode, config.DockbarPosition)
+	}
+	bounds := m.GetBSPBounds()
+	for id, rect := range m.bspLayout(tree) {
+		if rect != bounds {
+			t.Errorf("expected window %d stacked over %v, got %v", id, bounds, rect)
+		}
+	}
+	if got := m.titleBarText(two); got != "one [two]" {
+		t.Errorf("expected the tabs of the stack in the title bar, got %q", got)
+	}
+	if got := m.titleBarText(float); got != "float" {
+		t.Errorf("expected a floating window to keep its title, got %q", got)
+	}
+
+	// A dock position set in the compact layout waits for the full layout
+	if err := m.SetDockbarPosition("bottom"); err != nil || config.DockbarPosition != "hidden" {
+		t.Errorf("expected the dock kept hidden, got %s (%v)", config.DockbarPosition, err)
+	}
+	m.Width = 120
+	if !m.updateCompactMode() || m.CompactMode || config.DockbarPosition != "bottom" {
+		t.Errorf("expected the full layout with the dock at the bottom, got %v dock %s", m.CompactMode, config.DockbarPosition)
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+)
+
+// WatchConfig enables live reload of the config file at path. Settings given
+// as CLI flags in overrides keep precedence over the file.
+func (m *OS) WatchConfig(path string, overrides config.Overrides) {
+	m.ConfigPath = path
+	m.ConfigOverrides = overrides
+	m.configModTime, _ = configModTime(path)
+}
+
+// configModTime returns the latest modification time of the config file
+// and the selected profile's file.
+func configModTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	modTime := info.ModTime()
+
+	if profilePath, _ := config.GetProfilePath(); profilePath != "" {
+		if info, err := os.Stat(profilePath); err == nil && info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return modTime, nil
+}
+
+// CheckConfigReload reloads the config file when it or the selected profile
+// changed on disk. It is called on every tick and stats the files at most
+// every config.ConfigReloadCheckInterval.
+func (m *OS) CheckConfigReload() {
+	if m.ConfigPath == "" {
+		return
+	}
+	now := time.Now()
+	if now.Sub(m.LastConfigCheck) < config.ConfigReloadCheckInterval {
+		return
+	}
+	m.LastConfigCheck = now
+
+	modTime, err := configModTime(m.ConfigPath)
+	if err != nil {
+		// Editors may briefly remove the file while saving
+		if !errors.Is(err, os.ErrNotExist) {
+			m.LogError("Failed to check config file: %v", err)
+		}
+		return
+	}
+	if modTime.Equal(m.configModTime) {
+		return
+	}
+	m.configModTime = modTime
+
+	if err := m.ReloadConfig(); err != nil {
+		m.LogError("Config reload failed: %v", err)
+		m.ShowNotification(fmt.Sprintf("Config error: %v", err), "error", 5*time.Second)
+		return
+	}
+	m.ShowNotification("Config reloaded", "success", config.NotificationDuration)
+}
+
+// ReloadConfig reads the config file and applies it to the running session:
+// appearance, theme, dock position, keybindings and scrollback limits. The
+// current settings are kept if the file does not parse or validate.
+func (m *OS) ReloadConfig() error {
+	cfg, err := config.ReloadUserConfig(m.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	oldTheme := theme.Current()
+	oldDockbar := config.DockbarPosition
+
+	config.ApplyOverrides(m.ConfigOverrides, cfg)
+	m.KeybindRegistry = config.NewKeybindRegistry(cfg)
+	if m.CompactMode {
+		// The reloaded dock position waits for the full layout
+		m.compactDockbarPosition, config.DockbarPosition = config.DockbarPosition, "hidden"
+	}
+	compactChanged := m.updateCompactMode()
+
+	if theme.Current() != oldTheme {
+		m.UpdateAllWindowThemes()
+	}
+	if config.DockbarPosition != oldDockbar || compactChanged {
+		if m.AutoTiling {
+			m.TileAllWindows()
+		} else {
+			m.ClampWindowsToView()
+		}
+	}
+
+	// Scrollback limits follow the reloaded global limit and window rules
+	for _, w := range m.Windows {
+		w.ApplyScrollbackLimit()
+		for _, pane := range append(w.PaneWindows(), w.Tabs...) {
+			if pane != w {
+				pane.ApplyScrollbackLimit()
+			}
+		}
+		w.InvalidateCache()
+	}
+	m.MarkAllDirty()
+	m.LogInfo("Reloaded config from %s", m.ConfigPath)
+	return nil
+}
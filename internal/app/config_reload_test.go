@@ -0,0 +1,52 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// TestCheckConfigReload tests that edits to the config file are applied live
+// and that an invalid file keeps the current settings.
+func TestCheckConfigReload(t *testing.T) {
+	originalDockbar, originalBorder := config.DockbarPosition, config.BorderStyle
+	t.Cleanup(func() {
+		config.DockbarPosition, config.BorderStyle = originalDockbar, originalBorder
+	})
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	write := func(data string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+	write("[appearance]\ndockbar_position = \"bottom\"\n", start)
+
+	m := NewOS(OSOptions{})
+	m.WatchConfig(path, config.Overrides{BorderStyle: "thick"})
+
+	write("[appearance]\ndockbar_position = \"top\"\nborder_style = \"double\"\n", start.Add(time.Minute))
+	m.CheckConfigReload()
+	if config.DockbarPosition != "top" {
+		t.Errorf("DockbarPosition = %q, want top", config.DockbarPosition)
+	}
+	if config.BorderStyle != "thick" {
+		t.Errorf("BorderStyle = %q, want the CLI override thick", config.BorderStyle)
+	}
+
+	write("[appearance\ndockbar_position = \"hidden\"\n", start.Add(2*time.Minute))
+	m.LastConfigCheck = time.Time{}
+	m.CheckConfigReload()
+	if config.DockbarPosition != "top" {
+		t.Errorf("DockbarPosition = %q after invalid config, want top", config.DockbarPosition)
+	}
+}
@@ -14,30 +14,39 @@ func (m *OS) getRealCursor() *tea.Cursor {
 		return nil
 	}
 
-	window := m.Windows[m.FocusedWindow]
-	if window == nil || window.Terminal == nil {
+	frame := m.Windows[m.FocusedWindow]
+	if frame == nil {
 		return nil
 	}
+	// The cursor of a split window is in its active pane
+	window := frame.ActivePane()
+	if window.Terminal == nil {
+		return nil
+	}
+	rect := frame.ActivePaneRect()
 
 	// Hide during copy mode, scrollback, or when VT hides cursor
 	if (window.CopyMode != nil && window.CopyMode.Active) ||
 		window.ScrollbackOffset > 0 ||
+		window.Replay != nil ||
 		window.Terminal.IsCursorHidden() {
 		return nil
 	}
 
 	pos := window.Terminal.CursorPosition()
-	contentWidth := window.Width - 2 // subtract borders
-	contentHeight := window.Height - 2
+	if frame.Zoomed {
+		// Each cell of a zoomed window is drawn over 2x2 cells
+		pos.X, pos.Y = pos.X*2, pos.Y*2
+	}
 
 	// Bounds check - cursor must be within visible content area
-	if pos.X < 0 || pos.X >= contentWidth || pos.Y < 0 || pos.Y >= contentHeight {
+	if pos.X < 0 || pos.X >= rect.W || pos.Y < 0 || pos.Y >= rect.H {
 		return nil
 	}
 
 	// Transform to screen coordinates (+1 for border)
-	screenX := window.X + 1 + pos.X
-	screenY := window.Y + 1 + pos.Y
+	screenX := m.ScreenX(frame.X + 1 + rect.X + pos.X)
+	screenY := frame.Y + 1 + rect.Y + pos.Y
 
 	cursor := tea.NewCursor(screenX, screenY)
 	cursor.Shape = mapCursorStyle(window.CursorStyle)
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/debuglog"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// debugConsoleTitle is the title of debug console windows.
+const debugConsoleTitle = "Debug Console"
+
+// debugConsole is a window showing the internal log stream. It has no
+// process: keys typed into it change what it shows.
+type debugConsole struct {
+	level debuglog.Level // Least severe level shown
+	seq   uint64         // Last message written to the window
+	stop  func()         // Stops keeping debug messages for this console
+}
+
+// debugLevelColors are the SGR colors messages are written in, by level.
+var debugLevelColors = map[debuglog.Level]string{
+	debuglog.Debug: "2",
+	debuglog.Info:  "36",
+	debuglog.Warn:  "33",
+	debuglog.Error: "1;31",
+}
+
+// OpenDebugConsole opens a window showing tuios's internal log stream live:
+// the app log plus render, PTY and graphics passthrough messages, which are
+// otherwise only written to the debug log file in debug mode.
+func (m *OS) OpenDebugConsole() {
+	screenWidth, screenHeight := m.GetRenderWidth(), m.GetUsableHeight()
+	if screenWidth == 0 || screenHeight == 0 {
+		screenWidth, screenHeight = 80, 24
+	}
+	width, height := screenWidth/2, screenHeight/2
+	x, y := screenWidth/4, screenHeight/4
+	if !m.AutoTiling {
+		x, y = m.newWindowPosition(nil, screenWidth, screenHeight, width, height)
+	}
+
+	window := terminal.NewDaemonWindow(createID(), debugConsoleTitle, x, y, width, height, len(m.Windows), "")
+	window.Workspace = m.CurrentWorkspace
+	console := &debugConsole{level: debuglog.Info, stop: debuglog.Listen()}
+	if m.DebugConsoles == nil {
+		m.DebugConsoles = make(map[string]*debugConsole)
+	}
+	m.DebugConsoles[window.ID] = console
+	windowID := window.ID
+	window.DaemonWriteFunc = func(data []byte) error {
+		m.debugConsoleInput(windowID, data)
+		return nil
+	}
+
+	m.placeWindow(window)
+	m.redrawDebugConsole(window, console)
+	m.LogInfo("Debug console opened")
+}
+
+// IsDebugConsole reports whether a window is a debug console.
+func (m *OS) IsDebugConsole(w *terminal.Window) bool {
+	return w != nil && m.DebugConsoles[w.ID] != nil
+}
+
+// FeedDebugConsoles writes the messages logged since the last tick to the
+// open debug consoles, forgetting consoles whose window was closed. It
+// reports whether any console was written to.
+func (m *OS) FeedDebugConsoles() bool {
+	written := false
+	for id, console := range m.DebugConsoles {
+		window := m.findTerminal(id)
+		if window == nil {
+			console.stop()
+			delete(m.DebugConsoles, id)
+			continue
+		}
+		if m.writeDebugEntries(window, console, debuglog.Since(console.seq)) {
+			written = true
+		}
+	}
+	return written
+}
+
+// writeDebugEntries writes the entries at or above the console's level to
+// its window and reports whether any was written.
+func (m *OS) writeDebugEntries(window *terminal.Window, console *debugConsole, entries []debuglog.Entry) bool {
+	var b strings.Builder
+	for _, e := range entries {
+		console.seq = e.Seq
+		if e.Level < console.level {
+			continue
+		}
+		message := strings.ReplaceAll(e.Message, "\x1b", "^[")
+		message = strings.ReplaceAll(strings.TrimRight(message, "\n"), "\n", "\r\n  ")
+		fmt.Fprintf(&b, "\x1b[2m%s\x1b[0m \x1b[%sm%-5s\x1b[0m \x1b[1m%s\x1b[0m %s\r\n",
+			e.Time.Format("15:04:05.000"), debugLevelColors[e.Level], e.Level, e.Source, message)
+	}
+	if b.Len() == 0 {
+		return false
+	}
+	window.WriteOutput([]byte(b.String()))
+	return true
+}
+
+// redrawDebugConsole clears a console and writes its key hints followed by
+// every kept message at or above its level.
+func (m *OS) redrawDebugConsole(window *terminal.Window, console *debugConsole) {
+	window.WriteOutput([]byte("\x1b[2J\x1b[3J\x1b[H\x1b[2;3m" +
+		"1-4: show DEBUG, INFO, WARN or ERROR and above  c: clear\x1b[0m\r\n"))
+	window.Title = fmt.Sprintf("%s (%s+)", debugConsoleTitle, console.level)
+	window.MarkPositionDirty()
+	console.seq = 0
+	m.writeDebugEntries(window, console, debuglog.Since(0))
+}
+
+// debugConsoleInput handles keys typed into a debug console: 1-4 pick the
+// least severe level shown and c clears the window.
+func (m *OS) debugConsoleInput(id string, data []byte) {
+	console, window := m.DebugConsoles[id], m.findTerminal(id)
+	if console == nil || window == nil || len(data) != 1 {
+		return
+	}
+	switch key := data[0]; {
+	case key >= '1' && key <= '4':
+		console.level = debuglog.Level(key - '1')
+		m.redrawDebugConsole(window, console)
+		m.ShowNotification("Debug console: "+console.level.String()+" and above", "info", config.NotificationDuration)
+	case key == 'c':
+		window.WriteOutput([]byte("\x1b[2J\x1b[3J\x1b[H"))
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDebugConsole(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.OpenDebugConsole()
+	console := m.GetFocusedWindow()
+	if !m.IsDebugConsole(console) || !strings.HasPrefix(console.Title, "Debug Console") {
+		t.Fatalf("expected the debug console focused, got %+v", console)
+	}
+
+	screen := func() string {
+		var lines []string
+		for y := range console.Terminal.Height() {
+			var line strings.Builder
+			for x := range console.Terminal.Width() {
+				if cell := console.Terminal.CellAt(x, y); cell != nil {
+					line.WriteString(cell.Content)
+				}
+			}
+			lines = append(lines, line.String())
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	m.LogWarn("disk almost full")
+	m.LogInfo("window moved")
+	m.FeedDebugConsoles()
+	if s := screen(); !strings.Contains(s, "disk almost full") || !strings.Contains(s, "window moved") {
+		t.Fatalf("expected the app log in the console, got:\n%s", s)
+	}
+
+	// 3 shows warnings and errors only
+	if err := console.SendInput([]byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	if s := screen(); !strings.Contains(s, "disk almost full") || strings.Contains(s, "window moved") {
+		t.Errorf("expected only warnings after picking WARN, got:\n%s", s)
+	}
+
+	state := m.BuildSessionState()
+	if len(state.Windows) != 0 {
+		t.Errorf("expected the debug console left out of the session state, got %+v", state.Windows)
+	}
+
+	m.DeleteWindow(m.FocusedWindow)
+	m.FeedDebugConsoles()
+	if len(m.DebugConsoles) != 0 {
+		t.Error("expected the closed console forgotten")
+	}
+}
@@ -3,6 +3,8 @@ package app
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
 	"charm.land/lipgloss/v2"
 	"github.com/Gaurav-Gosain/tuios/internal/config"
@@ -14,7 +16,8 @@ import (
 type DockItem struct {
 	WindowIndex int
 	Label       string
-	Width       int // Total width including circles
+	Badge       string // Unseen bells or activity, drawn after the label
+	Width       int    // Total width including circles
 }
 
 // DockLayout contains calculated layout information for the dock
@@ -25,8 +28,10 @@ type DockLayout struct {
 	CenterStartX   int
 	ItemPositions  []ItemPosition // Position of each dock item
 	TruncatedCount int            // Number of items that don't fit
+	OverflowX      int            // Column of the " ..." shown when items don't fit
 	VisibleItems   []DockItem     // Items that fit and should be displayed
 	ModeInfo       ModeInfo       // Mode display information for styling
+	Rows           int            // Rows of the dock bar: 2 while items wrap onto a second row
 }
 
 // ItemPosition holds the position and size of a dock item
@@ -34,6 +39,7 @@ type ItemPosition struct {
 	StartX      int
 	EndX        int
 	WindowIndex int
+	Row         int // Dock row: 0 next to the mode and status, 1 the wrapped row
 }
 
 // CalculateDockLayout calculates the layout for the dock including positions of all items.
@@ -69,7 +75,7 @@ type ModeInfo struct {
 // buildDockLeftText builds the left side of the dock (mode + workspace info)
 // Returns the text, width, and mode info for styling
 func (m *OS) buildDockLeftText() (string, int, ModeInfo) {
-	focusedWindow := m.GetFocusedWindow()
+	focusedWindow := m.GetFocusedPane()
 
 	// Build mode info (will be styled with colors in render.go)
 	modeInfo := ModeInfo{
@@ -87,6 +93,12 @@ func (m *OS) buildDockLeftText() (string, int, ModeInfo) {
 		}
 	}
 
+	// The tiling indicator shows F instead of the split when the focused window floats
+	tilingLabel := config.GetDockModeIconTiling() + modeInfo.NextSplit
+	if w := m.GetFocusedWindow(); w != nil && w.Floating {
+		tilingLabel = config.GetDockModeIconTiling() + "F"
+	}
+
 	var modeText string
 	var modeLabel string
 
@@ -101,7 +113,7 @@ func (m *OS) buildDockLeftText() (string, int, ModeInfo) {
 			modeInfo.Color = theme.ColorToString(theme.DockColorTerminal())
 			// Add tiling indicator for terminal mode (with split direction)
 			if m.AutoTiling {
-				modeLabel = config.GetDockModeIconTiling() + modeInfo.NextSplit
+				modeLabel = tilingLabel
 			} else {
 				modeLabel = config.GetDockModeIconTerminal()
 			}
@@ -111,12 +123,16 @@ func (m *OS) buildDockLeftText() (string, int, ModeInfo) {
 		modeInfo.Color = theme.ColorToString(theme.DockColorWindow())
 		// Add tiling indicator for window mode (with split direction)
 		if m.AutoTiling {
-			modeLabel = config.GetDockModeIconTiling() + modeInfo.NextSplit
+			modeLabel = tilingLabel
 		} else {
 			modeLabel = config.GetDockModeIconWindow()
 		}
 	}
 
+	if m.ReadOnly {
+		modeLabel = "RO"
+	}
+
 	// Build pill-style mode indicator with configurable semicircles
 	// This will be styled in render.go with the mode color
 	modeText = config.GetDockPillLeftChar() + modeLabel + config.GetDockPillRightChar()
@@ -140,15 +156,22 @@ func (m *OS) buildDockLeftText() (string, int, ModeInfo) {
 	// - 2:3 = workspace 2, 3 windows in current
 	// - 5  = 5 terminals total (space before icon)
 	// - 3  = 3 workspaces in use (space before icon)
+	// followed by the number of urgent windows, if any
 	windowsInCurrent := m.GetWorkspaceWindowCount(m.CurrentWorkspace)
-	workspaceText := fmt.Sprintf(" %d:%d%s%d %s %d %s ",
-		m.CurrentWorkspace,
+	workspaceText := fmt.Sprintf(" %s:%d%s%d %s %d %s ",
+		m.workspaceLabel(m.CurrentWorkspace),
 		windowsInCurrent,
 		config.GetDockSeparator(),
 		totalTerminals,
 		config.GetDockIconTerminalCount(),
 		workspacesUsed,
 		config.GetDockIconWorkspaceCount())
+	if modes := m.dockWorkspaceModes(); modes != "" {
+		workspaceText += modes + " "
+	}
+	if urgent := len(m.UrgentWindows()); urgent > 0 {
+		workspaceText += fmt.Sprintf("%s %d ", config.GetDockIconUrgentCount(), urgent)
+	}
 
 	// Combine mode and workspace
 	leftText := modeText + workspaceText
@@ -160,9 +183,31 @@ func (m *OS) buildDockLeftText() (string, int, ModeInfo) {
 	return leftText, width, modeInfo
 }
 
+// dockWorkspaceModes lists the workspaces in use, the tiled ones marked with
+// the tiling icon, e.g. "1# 2 4#" in ASCII-only mode. It is empty unless
+// windows are open on more than one workspace, as the mode indicator already
+// shows the current workspace's mode.
+func (m *OS) dockWorkspaceModes() string {
+	var modes []string
+	for ws := 1; ws <= m.NumWorkspaces; ws++ {
+		if ws != m.CurrentWorkspace && m.GetWorkspaceWindowCount(ws) == 0 {
+			continue
+		}
+		mode := strconv.Itoa(ws)
+		if m.WorkspaceAutoTiling(ws) {
+			mode += strings.TrimSpace(config.GetDockModeIconTiling())
+		}
+		modes = append(modes, mode)
+	}
+	if len(modes) < 2 {
+		return ""
+	}
+	return strings.Join(modes, " ")
+}
+
 // calculateDockRightWidth calculates the width of the right side of the dock
 func (m *OS) calculateDockRightWidth() int {
-	focusedWindow := m.GetFocusedWindow()
+	focusedWindow := m.GetFocusedPane()
 	inCopyMode := focusedWindow != nil && focusedWindow.CopyMode != nil && focusedWindow.CopyMode.Active
 
 	if inCopyMode {
@@ -177,6 +222,8 @@ func (m *OS) calculateDockRightWidth() int {
 			return 90 // Length of visual char mode help text + padding
 		case terminal.CopyModeVisualLine:
 			return 35 // Length of visual line mode help text + padding
+		case terminal.CopyModeVisualBlock:
+			return 55 // Length of visual block mode help text + padding
 		default:
 			return 32
 		}
@@ -190,7 +237,7 @@ func (m *OS) getDockItems() []DockItem {
 	// Find all minimized/minimizing windows in current workspace
 	dockWindows := []int{}
 	for i, window := range m.Windows {
-		if window.Workspace == m.CurrentWorkspace && (window.Minimized || window.Minimizing) {
+		if window.Workspace == m.CurrentWorkspace && (window.Minimized || window.Minimizing) && window.SwallowedBy == "" {
 			dockWindows = append(dockWindows, i)
 		}
 	}
@@ -210,17 +257,19 @@ func (m *OS) getDockItems() []DockItem {
 		// Get window name (only custom names)
 		windowName := window.CustomName
 
-		// Format label based on whether we have a custom name
+		// Format label based on whether we have a custom name, after the
+		// icon of the window's program
+		icon := windowIconPrefix(window)
 		var labelText string
 		if windowName != "" {
 			// Truncate if too long (max 12 chars for dock item)
 			if len(windowName) > 12 {
 				windowName = windowName[:9] + "..."
 			}
-			labelText = fmt.Sprintf(" %d:%s ", itemNumber, windowName)
+			labelText = fmt.Sprintf(" %s%d:%s ", icon, itemNumber, windowName)
 		} else {
 			// Just show the number if no custom name
-			labelText = fmt.Sprintf(" %d ", itemNumber)
+			labelText = fmt.Sprintf(" %s%d ", icon, itemNumber)
 		}
 
 		// Calculate width: 2 for circles (left + right) + actual rendered label width
@@ -228,10 +277,15 @@ func (m *OS) getDockItems() []DockItem {
 		itemWidth := lipgloss.Width(config.GetDockPillLeftChar()) +
 			lipgloss.Width(labelText) +
 			lipgloss.Width(config.GetDockPillRightChar())
+		badge := windowBadge(window)
+		if badge != "" {
+			itemWidth += lipgloss.Width(badge) + 1
+		}
 
 		items = append(items, DockItem{
 			WindowIndex: windowIndex,
 			Label:       labelText,
+			Badge:       badge,
 			Width:       itemWidth,
 		})
 
@@ -241,37 +295,68 @@ func (m *OS) getDockItems() []DockItem {
 	return items
 }
 
-// calculateItemPositions determines which items fit and their X positions
+// truncationIndicatorWidth is the width of the " ..." shown after the dock
+// items when not all of them fit.
+const truncationIndicatorWidth = 4
+
+// calculateItemPositions determines which items fit and their X positions.
+// Items that do not fit between the two ends of the dock are cut off, or
+// with config.DockWrap moved to a second row spanning the whole width.
 func (layout *DockLayout) calculateItemPositions(screenWidth int, allItems []DockItem) {
-	// Calculate total width of all items (including spaces between)
-	totalItemsWidth := 0
-	for i, item := range allItems {
-		totalItemsWidth += item.Width
-		if i > 0 {
-			totalItemsWidth++ // Space between items
-		}
+	layout.Rows = 1
+	layout.VisibleItems = allItems
+	layout.TruncatedCount = 0
+	layout.ItemPositions = make([]ItemPosition, 0, len(allItems))
+
+	// The items are centered between the mode and workspace info and the
+	// status widgets, whichever end of the dock each is on
+	startX := layout.LeftWidth
+	if config.DockStatusSide == "left" {
+		startX = layout.RightWidth
 	}
+	centerWidth := screenWidth - layout.LeftWidth - layout.RightWidth
 
-	// Calculate available space for dock items
-	availableSpace := screenWidth - layout.LeftWidth - layout.RightWidth - totalItemsWidth
-	if availableSpace < 0 {
-		// Items don't fit - need to truncate
-		layout.truncateItems(screenWidth, allItems)
+	if dockItemsWidth(allItems) <= centerWidth {
+		layout.placeRow(allItems, 0, startX, centerWidth)
 		return
 	}
 
-	// All items fit - calculate center positioning
-	leftSpacer := max(availableSpace/2, 0)
+	if config.DockWrap {
+		first := fittingDockItems(allItems, centerWidth-4)
+		wrapped := allItems[first:]
+		if dockItemsWidth(wrapped) > screenWidth {
+			keep := fittingDockItems(wrapped, screenWidth-truncationIndicatorWidth-1)
+			layout.TruncatedCount = len(wrapped) - keep
+			wrapped = wrapped[:keep]
+		}
+		layout.Rows = 2
+		layout.VisibleItems = allItems[:first+len(wrapped)]
+		layout.placeRow(allItems[:first], 0, startX, centerWidth)
+		layout.placeRow(wrapped, 1, 0, screenWidth)
+		return
+	}
 
-	layout.CenterStartX = layout.LeftWidth + leftSpacer
-	layout.VisibleItems = allItems
-	layout.TruncatedCount = 0
+	keep := fittingDockItems(allItems, centerWidth-truncationIndicatorWidth-4)
+	layout.VisibleItems = allItems[:keep]
+	layout.TruncatedCount = len(allItems) - keep
+	layout.placeRow(layout.VisibleItems, 0, startX, centerWidth)
+}
 
-	// Calculate position of each item
-	currentX := layout.CenterStartX
-	layout.ItemPositions = make([]ItemPosition, 0, len(allItems))
+// placeRow centers items, followed by the truncation indicator on the last
+// row when items were cut off, in the width columns of dock row row that
+// start at column x.
+func (layout *DockLayout) placeRow(items []DockItem, row, x, width int) {
+	truncated := layout.TruncatedCount > 0 && row == layout.Rows-1
+	totalWidth := dockItemsWidth(items)
+	if truncated {
+		totalWidth += 1 + truncationIndicatorWidth // space + "..."
+	}
+	currentX := x + max((width-totalWidth)/2, 0)
+	if row == 0 {
+		layout.CenterStartX = currentX
+	}
 
-	for i, item := range allItems {
+	for i, item := range items {
 		// Add space before item (except first)
 		if i > 0 {
 			currentX++
@@ -281,73 +366,51 @@ func (layout *DockLayout) calculateItemPositions(screenWidth int, allItems []Doc
 			StartX:      currentX,
 			EndX:        currentX + item.Width,
 			WindowIndex: item.WindowIndex,
+			Row:         row,
 		})
 
 		currentX += item.Width
 	}
+	if truncated {
+		layout.OverflowX = currentX
+	}
 }
 
-// truncateItems calculates which items fit when space is limited
-func (layout *DockLayout) truncateItems(screenWidth int, allItems []DockItem) {
-	const truncationIndicatorWidth = 4 // " ..." width
-
-	// Calculate max width available for items
-	maxItemsWidth := max(screenWidth-layout.LeftWidth-layout.RightWidth-truncationIndicatorWidth-4, 0)
+// dockItemsWidth returns the width of items side by side, a space apart.
+func dockItemsWidth(items []DockItem) int {
+	width := 0
+	for i, item := range items {
+		width += item.Width
+		if i > 0 {
+			width++ // Space between items
+		}
+	}
+	return width
+}
 
-	// Find how many complete items fit
+// fittingDockItems returns how many of the first items fit side by side in
+// width columns.
+func fittingDockItems(items []DockItem, width int) int {
 	currentWidth := 0
-	visibleCount := 0
-
-	for i, item := range allItems {
+	for i, item := range items {
 		itemWidthWithSpace := item.Width
 		if i > 0 {
 			itemWidthWithSpace++ // Space before item
 		}
-
-		if currentWidth+itemWidthWithSpace <= maxItemsWidth {
-			currentWidth += itemWidthWithSpace
-			visibleCount++
-		} else {
-			break
+		if currentWidth+itemWidthWithSpace > width {
+			return i
 		}
+		currentWidth += itemWidthWithSpace
 	}
+	return len(items)
+}
 
-	// Set visible items
-	if visibleCount > 0 {
-		layout.VisibleItems = allItems[:visibleCount]
-	} else {
-		layout.VisibleItems = []DockItem{}
-	}
-	layout.TruncatedCount = len(allItems) - visibleCount
-
-	// Recalculate total width including truncation indicator
-	totalWidth := currentWidth
-	if layout.TruncatedCount > 0 {
-		totalWidth += 1 + truncationIndicatorWidth // space + "..."
-	}
-
-	// Calculate center positioning
-	availableSpace := screenWidth - layout.LeftWidth - layout.RightWidth - totalWidth
-	leftSpacer := max(availableSpace/2, 0)
-
-	layout.CenterStartX = layout.LeftWidth + leftSpacer
-
-	// Calculate positions
-	currentX := layout.CenterStartX
-	layout.ItemPositions = make([]ItemPosition, 0, len(layout.VisibleItems))
-
-	for i, item := range layout.VisibleItems {
-		// Add space before item (except first)
-		if i > 0 {
-			currentX++
-		}
-
-		layout.ItemPositions = append(layout.ItemPositions, ItemPosition{
-			StartX:      currentX,
-			EndX:        currentX + item.Width,
-			WindowIndex: item.WindowIndex,
-		})
-
-		currentX += item.Width
+// IsDockOverflowAt reports whether the screen position is on the "..." shown
+// after the dock items when not all minimized windows fit.
+func (m *OS) IsDockOverflowAt(x, y int) bool {
+	layout := m.CalculateDockLayout()
+	if layout.TruncatedCount == 0 || y != m.DockRowY(layout.Rows-1) {
+		return false
 	}
+	return x >= layout.OverflowX && x < layout.OverflowX+truncationIndicatorWidth
 }
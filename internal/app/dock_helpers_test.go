@@ -0,0 +1,127 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestDockWrap(t *testing.T) {
+	prevWrap, prevSide, prevPosition := config.DockWrap, config.DockStatusSide, config.DockbarPosition
+	t.Cleanup(func() {
+		config.DockWrap, config.DockStatusSide, config.DockbarPosition = prevWrap, prevSide, prevPosition
+	})
+	config.DockbarPosition = "bottom"
+
+	m := &OS{Width: 100, Height: 30, CurrentWorkspace: 1, NumWorkspaces: 9, FocusedWindow: -1}
+	for i := range 7 {
+		m.Windows = append(m.Windows, &terminal.Window{
+			ID: fmt.Sprintf("w%d", i), CustomName: fmt.Sprintf("win-%d", i), Workspace: 1, Minimized: true, MinimizeOrder: int64(i),
+		})
+	}
+
+	// Without wrapping the pills that do not fit are cut off
+	layout := m.CalculateDockLayout()
+	if layout.Rows != 1 || layout.TruncatedCount == 0 || m.DockHeight() != config.DockHeight {
+		t.Fatalf("got %d rows with %d cut off, want one row cut off", layout.Rows, layout.TruncatedCount)
+	}
+
+	config.DockWrap = true
+	layout = m.CalculateDockLayout()
+	if layout.Rows != 2 || layout.TruncatedCount != 0 || len(layout.VisibleItems) != 7 {
+		t.Fatalf("got %d rows showing %d items with %d cut off, want every item on two rows", layout.Rows, len(layout.VisibleItems), layout.TruncatedCount)
+	}
+	first, last := layout.ItemPositions[0], layout.ItemPositions[6]
+	if first.Row != 0 || first.StartX < layout.LeftWidth || last.Row != 1 {
+		t.Errorf("first item on row %d at %d, last on row %d; want the first next to the mode, the last wrapped", first.Row, first.StartX, last.Row)
+	}
+	if m.DockHeight() != config.DockHeight+1 || m.GetUsableHeight() != 30-config.DockHeight-1 {
+		t.Errorf("dock height %d, usable height %d; want the dock one row taller", m.DockHeight(), m.GetUsableHeight())
+	}
+	if dock := m.renderDock(); dock.GetHeight() != 3 || dock.GetY() != 27 {
+		t.Errorf("rendered dock has %d lines at row %d, want 3 at row 27", dock.GetHeight(), dock.GetY())
+	}
+
+	// The bar row stays at the screen edge with the wrapped row above it
+	if m.DockRowY(0) != 29 || m.DockRowY(1) != 28 {
+		t.Errorf("dock rows at %d and %d, want 29 and 28", m.DockRowY(0), m.DockRowY(1))
+	}
+
+	// With the status on the left the pills start after it
+	config.DockWrap = false
+	config.DockStatusSide = "left"
+	if layout := m.CalculateDockLayout(); layout.ItemPositions[0].StartX < layout.RightWidth {
+		t.Errorf("first item at %d, want it after the %d columns of the status", layout.ItemPositions[0].StartX, layout.RightWidth)
+	}
+}
+
+func TestDockHeightFollowsMinimizedWindows(t *testing.T) {
+	prevWrap, prevPosition := config.DockWrap, config.DockbarPosition
+	t.Cleanup(func() { config.DockWrap, config.DockbarPosition = prevWrap, prevPosition })
+	config.DockWrap, config.DockbarPosition = true, "bottom"
+
+	m := &OS{Width: 100, Height: 30, CurrentWorkspace: 1, NumWorkspaces: 9, FocusedWindow: -1}
+	for i := range 7 {
+		m.Windows = append(m.Windows, &terminal.Window{
+			ID: fmt.Sprintf("window-%d-0000", i), CustomName: fmt.Sprintf("win-%d", i), Workspace: 1,
+			X: 10, Y: 25, Width: 30, Height: 5, Minimized: i < 3, MinimizeOrder: int64(i),
+		})
+	}
+	floating := m.Windows[6]
+	if m.DockHeight() != config.DockHeight || m.FitWindowsToDock() {
+		t.Fatalf("dock height %d, want one row of pills laid out without moving windows", m.DockHeight())
+	}
+
+	// Minimizing windows wraps the pills, and the next tick moves the
+	// windows the taller dock now covers
+	for i := 3; i < 6; i++ {
+		m.minimizeWindow(i)
+	}
+	if m.DockHeight() != config.DockHeight+1 {
+		t.Fatalf("dock height %d after minimizing, want %d", m.DockHeight(), config.DockHeight+1)
+	}
+	if !m.FitWindowsToDock() || floating.Y != m.GetUsableHeight()-3 {
+		t.Errorf("floating window at row %d, want it clamped to row %d above the dock", floating.Y, m.GetUsableHeight()-3)
+	}
+	if m.FitWindowsToDock() {
+		t.Error("windows fitted again without the dock changing")
+	}
+
+	// Closing minimized windows frees the row again
+	m.DeleteWindow(5)
+	m.DeleteWindow(4)
+	m.DeleteWindow(3)
+	if m.DockHeight() != config.DockHeight || !m.FitWindowsToDock() {
+		t.Errorf("dock height %d after closing, want the wrapped row freed", m.DockHeight())
+	}
+}
+
+func TestDockShowsWorkspaceModes(t *testing.T) {
+	prevASCII := config.UseASCIIOnly
+	t.Cleanup(func() { config.UseASCIIOnly = prevASCII })
+	config.UseASCIIOnly = true
+
+	m := &OS{Width: 100, Height: 30, CurrentWorkspace: 1, NumWorkspaces: 9, FocusedWindow: -1}
+	m.Windows = []*terminal.Window{{ID: "window-one-0000", Workspace: 1}}
+	if modes := m.dockWorkspaceModes(); modes != "" {
+		t.Errorf("expected no workspace modes with one workspace in use, got %q", modes)
+	}
+
+	m.Windows = append(m.Windows, &terminal.Window{ID: "window-two-0000", Workspace: 2}, &terminal.Window{ID: "window-four-000", Workspace: 4})
+	m.WorkspaceTiling = map[int]bool{2: true}
+	if modes := m.dockWorkspaceModes(); modes != "1 2# 4" {
+		t.Errorf("workspace modes = %q, want the tiled workspace 2 marked", modes)
+	}
+	before := m.dockStateKey(m.CalculateDockLayout())
+	m.WorkspaceTiling[4] = true
+	layout := m.CalculateDockLayout()
+	if !strings.Contains(layout.LeftText, "1 2# 4# ") {
+		t.Errorf("dock left text %q does not show the workspace modes", layout.LeftText)
+	}
+	if m.dockStateKey(layout) == before {
+		t.Error("expected the dock cache key to change with a workspace's tiling mode")
+	}
+}
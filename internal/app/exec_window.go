@@ -0,0 +1,75 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// execShells are the shells whose exec builtin ExecInWindow types. Windows
+// running another program, or a shell without exec such as PowerShell, are
+// refused.
+var execShells = []string{"sh", "bash", "zsh", "dash", "ash", "ksh", "mksh", "oksh", "yash", "busybox", "fish", "nu", "elvish"}
+
+// ExecArgs splits the arguments of ExecInWindow into the window selector,
+// given as a window=<window> argument, and the command. A single command
+// argument is taken as a whole command line; several are quoted for the
+// shell as separate arguments.
+func ExecArgs(args []string) (selector, command string) {
+	if len(args) > 0 {
+		if value, ok := strings.CutPrefix(args[0], "window="); ok {
+			selector, args = value, args[1:]
+		}
+	}
+	if len(args) == 1 {
+		return selector, args[0]
+	}
+	return selector, ShellJoin(args)
+}
+
+// ExecInWindow replaces the shell of the windows a selector picks, or of the
+// focused window when it is empty, with command, as the shell's exec does:
+// each window keeps its ID, scrollback and geometry, and closes when the
+// command exits. Split windows run it in their active pane. The shell has to
+// be one of execShells and in front, so the command line is not typed into
+// another program. It returns how many windows run the command.
+func (m *OS) ExecInWindow(selector, command string) (int, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return 0, fmt.Errorf("no command given")
+	}
+	windows := m.SelectWindows(selector)
+	if selector == "" {
+		windows = nil
+		if w := m.GetFocusedWindow(); w != nil {
+			windows = append(windows, w)
+		}
+	}
+	if len(windows) == 0 {
+		return 0, fmt.Errorf("no window matches %s", selector)
+	}
+
+	for _, w := range windows {
+		pane := w.ActivePane()
+		if pane == nil || m.IsDebugConsole(w) || pane.Pty == nil && pane.PTYID == "" {
+			return 0, fmt.Errorf("window %s has no shell", m.getWindowDisplayName(w))
+		}
+		// Daemon windows' shells run in the daemon, which does not say which
+		if pane.Cmd != nil && !slices.Contains(execShells, strings.TrimSuffix(filepath.Base(pane.Cmd.Path), ".exe")) {
+			return 0, fmt.Errorf("%s runs %s, not a shell that can exec a command", m.getWindowDisplayName(w), filepath.Base(pane.Cmd.Path))
+		}
+		if program := m.runningProgram(pane); program != "" {
+			return 0, fmt.Errorf("%s is running in %s, only its shell can exec a command", program, m.getWindowDisplayName(w))
+		}
+	}
+	for _, w := range windows {
+		// Ctrl+U first drops anything typed at the prompt
+		if err := w.ActivePane().SendInput([]byte("\x15exec " + command + "\r")); err != nil {
+			return 0, fmt.Errorf("failed to start command: %w", err)
+		}
+		w.Command = commandName(command)
+		m.LogInfo("Replaced the shell of %s with %s", m.getWindowDisplayName(w), command)
+	}
+	return len(windows), nil
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestExecInWindow(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.CurrentWorkspace = 1
+	var typed []string
+	shell := terminal.NewDaemonWindow("window-one-0000", "shell", 0, 0, 40, 20, 0, "pty-one")
+	shell.DaemonWriteFunc = func(input []byte) error {
+		typed = append(typed, string(input))
+		return nil
+	}
+	shell.Workspace = 1
+	m.Windows = []*terminal.Window{shell}
+	m.FocusedWindow = 0
+
+	if _, err := m.ExecInWindow("", "  "); err == nil {
+		t.Error("expected an error without a command")
+	}
+	if _, err := m.ExecInWindow("nope", "ssh host"); err == nil {
+		t.Error("expected an error for a selector matching no window")
+	}
+	count, err := m.ExecInWindow("", "ssh host")
+	if err != nil || count != 1 {
+		t.Fatalf("ExecInWindow() = %d, %v", count, err)
+	}
+	if len(typed) != 1 || typed[0] != "\x15exec ssh host\r" {
+		t.Errorf("expected the prompt cleared and exec typed, got %q", typed)
+	}
+	if shell.Command != "ssh" || m.Windows[0] != shell {
+		t.Errorf("expected the same window running ssh, got command %q", shell.Command)
+	}
+
+	if _, err := m.ExecInWindow("shell", "htop"); err != nil || len(typed) != 2 {
+		t.Errorf("expected the window picked by name, got %v (%q)", err, typed)
+	}
+}
+
+func TestExecArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args              []string
+		selector, command string
+	}{
+		{[]string{"ssh host"}, "", "ssh host"},
+		{[]string{"ssh", "host"}, "", "ssh host"},
+		{[]string{"window=tag:db", "psql"}, "tag:db", "psql"},
+		{[]string{"window=ssh", "grep", "a b", "f"}, "ssh", "grep 'a b' f"},
+		{[]string{"window=ssh"}, "ssh", ""},
+		{nil, "", ""},
+	} {
+		selector, command := ExecArgs(tt.args)
+		if selector != tt.selector || command != tt.command {
+			t.Errorf("ExecArgs(%q) = %q, %q; want %q, %q", tt.args, selector, command, tt.selector, tt.command)
+		}
+	}
+}
+
+func TestExecInWindowRefusesRunningProgram(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs POSIX job control")
+	}
+	m := NewOS(OSOptions{})
+	m.CurrentWorkspace = 1
+	// With job control the shell hands the terminal to sleep
+	busy := terminal.NewCommandWindow("window-busy-000", "busy", 0, 0, 40, 10, 0, make(chan string, 1), "", []string{"sh", "-c", "set -m; sleep 30; :"})
+	if busy == nil {
+		t.Skip("cannot start a shell")
+	}
+	defer busy.Close()
+	busy.Workspace = 1
+	m.Windows = []*terminal.Window{busy}
+	m.FocusedWindow = 0
+
+	deadline := time.Now().Add(5 * time.Second)
+	for busy.ForegroundProcessName() == "" {
+		if time.Now().After(deadline) {
+			t.Skip("the foreground process cannot be inspected")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	_, err := m.ExecInWindow("", "htop")
+	if err == nil || !strings.Contains(err.Error(), "sleep is running in busy") {
+		t.Errorf("ExecInWindow() = %v, want it refused while sleep runs", err)
+	}
+
+	// A window running a program that is no shell is refused too
+	pager := terminal.NewCommandWindow("window-pager-00", "pager", 0, 0, 40, 10, 0, make(chan string, 1), "", []string{"sleep", "30"})
+	if pager == nil {
+		t.Skip("cannot start sleep")
+	}
+	defer pager.Close()
+	pager.Workspace = 1
+	m.Windows = append(m.Windows, pager)
+	if _, err := m.ExecInWindow("pager", "htop"); err == nil || !strings.Contains(err.Error(), "not a shell") {
+		t.Errorf("ExecInWindow() = %v, want it refused for a window running sleep", err)
+	}
+}
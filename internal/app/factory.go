@@ -1,6 +1,8 @@
 package app
 
 import (
+	"time"
+
 	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/session"
 )
@@ -29,9 +31,16 @@ type OSOptions struct {
 	// SessionName is the name of the daemon session.
 	SessionName string
 
+	// ReadOnly attaches as a mirror that renders the session but discards input.
+	ReadOnly bool
+
 	// IsSSHMode indicates this is an SSH session.
 	IsSSHMode bool
 
+	// Nested indicates TUIOS runs inside a window of another TUIOS, as
+	// reported by RunningInsideTUIOS.
+	Nested bool
+
 	// EnableGraphicsPassthrough enables Kitty/Sixel graphics passthrough.
 	// This should be true for terminal sessions, false for web.
 	EnableGraphicsPassthrough bool
@@ -70,6 +79,7 @@ func NewOS(opts OSOptions) *OS {
 		ShowKeys:          opts.ShowKeys,
 		RecentKeys:        []KeyEvent{},
 		KeyHistoryMaxSize: 5,
+		LastInputTime:     time.Now(),
 
 		// Dimensions
 		Width:  opts.Width,
@@ -78,10 +88,12 @@ func NewOS(opts OSOptions) *OS {
 		// Mode flags
 		IsDaemonSession: opts.IsDaemonSession,
 		IsSSHMode:       opts.IsSSHMode,
+		Nested:          opts.Nested,
 
 		// Daemon connection
 		DaemonClient: opts.DaemonClient,
 		SessionName:  opts.SessionName,
+		ReadOnly:     opts.ReadOnly,
 	}
 
 	// Initialize graphics passthrough if enabled
@@ -89,6 +101,14 @@ func NewOS(opts OSOptions) *OS {
 		os.KittyRenderer = NewKittyRenderer()
 		os.KittyPassthrough = NewKittyPassthrough()
 		os.SixelPassthrough = NewSixelPassthrough()
+
+		caps := GetHostCapabilities()
+		os.LogInfo("Host terminal %q: truecolor=%v kitty=%v sixel=%v sync=%v clipboard=%v hyperlinks=%v (see tuios doctor)",
+			caps.TerminalName, caps.TrueColor, caps.KittyGraphics, caps.SixelGraphics, caps.SyncOutput, caps.Clipboard, caps.Hyperlinks)
+	}
+
+	if opts.Nested {
+		os.ShowNotification("Running inside TUIOS: "+os.KeyPassthroughHint()+" in the outer TUIOS sends it your prefix keys", "info", nestedNotificationDuration)
 	}
 
 	// Initialize PTY subscription tracking for daemon sessions
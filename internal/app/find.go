@@ -0,0 +1,184 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// screenMatch is where text was found on the screen of a pane.
+type screenMatch struct {
+	Row, Start, End int    // Visible row and first and last cell column of the match
+	Text            string // Text matched, as shown
+}
+
+// StartFindPrompt opens the prompt asking for the text to find on screen.
+func (m *OS) StartFindPrompt() {
+	m.FindPrompt.Open("Find on screen", "Upper case matches case  Enter: find  Esc: cancel", m.LastFindQuery)
+}
+
+// CancelFindPrompt closes the find prompt without searching.
+func (m *OS) CancelFindPrompt() {
+	m.FindPrompt.Close()
+}
+
+// SubmitFindPrompt closes the find prompt and finds the text typed.
+func (m *OS) SubmitFindPrompt() {
+	query := m.FindPrompt.Close()
+	if query == "" {
+		return
+	}
+	if _, err := m.FindOnScreen(query); err != nil {
+		m.ShowNotification(err.Error(), "warning", config.NotificationDuration)
+	}
+}
+
+// FindOnScreen searches what the windows of the current workspace show for
+// query, focuses the first window showing it and highlights the match. The
+// search starts after the focused window, so finding the same text again
+// moves on to the next window showing it. It is case-insensitive unless the
+// query has an upper case letter. It returns the ID of the window found.
+func (m *OS) FindOnScreen(query string) (string, error) {
+	if query == "" {
+		return "", fmt.Errorf("nothing to find")
+	}
+	m.LastFindQuery = query
+	n := len(m.Windows)
+	start := max(m.FocusedWindow, -1) + 1
+	for k := range n {
+		i := (start + k) % n
+		w := m.Windows[i]
+		if w.Workspace != m.CurrentWorkspace || w.Minimized || w.Minimizing {
+			continue
+		}
+		panes := w.PaneWindows()
+		if panes == nil {
+			panes = []*terminal.Window{w.ActivePane()}
+		}
+		for _, pane := range panes {
+			match, ok := findInPane(pane, query)
+			if !ok {
+				continue
+			}
+			m.showScreenMatch(i, pane, match)
+			m.ShowNotification("Found in "+m.getWindowDisplayName(w), "info", config.NotificationDuration)
+			return w.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%q is not on screen", query)
+}
+
+// showScreenMatch focuses window i and the pane a match was found in, and
+// highlights the match like a mouse selection, until the next click or key.
+func (m *OS) showScreenMatch(i int, pane *terminal.Window, match screenMatch) {
+	if old := m.MouseSelection; old != nil {
+		old.IsSelecting = false
+		old.SelectedText = ""
+	}
+	m.FocusWindow(i)
+	w := m.Windows[i]
+	if w.HasPanes() {
+		for _, p := range w.Panes.Panes {
+			if p.Window == pane {
+				w.Panes.Active = p.ID
+			}
+		}
+	}
+
+	pane.IsSelecting = false
+	pane.SelectionMode = 0
+	pane.SelectionStart.X, pane.SelectionStart.Y = match.Start, match.Row
+	pane.SelectionEnd.X, pane.SelectionEnd.Y = match.End, match.Row
+	pane.SelectedText = match.Text
+	m.MouseSelection = pane
+	pane.InvalidateCache()
+	m.MarkAllDirty()
+}
+
+// findInPane returns the first match of query in the rows a pane shows,
+// top to bottom, including the scrollback lines it is scrolled back to.
+func findInPane(pane *terminal.Window, query string) (screenMatch, bool) {
+	if pane == nil || pane.Terminal == nil {
+		return screenMatch{}, false
+	}
+	fold := !strings.ContainsFunc(query, unicode.IsUpper)
+	needle := []rune(query)
+	if fold {
+		needle = foldRunes(needle)
+	}
+	for y := range pane.Terminal.Height() {
+		runes, cols, widths := visibleRow(pane, y)
+		haystack := runes
+		if fold {
+			haystack = foldRunes(runes)
+		}
+		if at := indexRunes(haystack, needle); at >= 0 {
+			last := at + len(needle) - 1
+			return screenMatch{Row: y, Start: cols[at], End: cols[last] + widths[last] - 1, Text: string(runes[at : last+1])}, true
+		}
+	}
+	return screenMatch{}, false
+}
+
+// visibleRow returns the characters of visible row y of a pane with the cell
+// column each one is drawn in and the number of cells it covers.
+func visibleRow(pane *terminal.Window, y int) ([]rune, []int, []int) {
+	var runes []rune
+	var cols, widths []int
+	for x := range pane.Terminal.Width() {
+		content, width := visibleCell(pane, x, y)
+		if content == "" {
+			if width == 0 {
+				continue // Right half of a wide character
+			}
+			content = " "
+		}
+		for _, r := range content {
+			runes = append(runes, r)
+			cols = append(cols, x)
+			widths = append(widths, max(width, 1))
+		}
+	}
+	return runes, cols, widths
+}
+
+// visibleCell returns the content and width of the cell a pane shows at
+// (x, y), taken from scrollback on the rows it is scrolled back to.
+func visibleCell(pane *terminal.Window, x, y int) (string, int) {
+	var cell *uv.Cell
+	if offset := pane.ScrollbackOffset; y < offset {
+		if line := pane.ScrollbackLine(pane.ScrollbackLen() - offset + y); x < len(line) {
+			cell = &line[x]
+		}
+	} else {
+		cell = pane.Terminal.CellAt(x, y-offset)
+	}
+	if cell == nil {
+		return "", 1
+	}
+	return cell.Content, cell.Width
+}
+
+// foldRunes returns runes in lower case.
+func foldRunes(runes []rune) []rune {
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		folded[i] = unicode.ToLower(r)
+	}
+	return folded
+}
+
+// indexRunes returns the index of the first instance of needle in haystack,
+// or -1.
+func indexRunes(haystack, needle []rune) int {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if string(haystack[i:i+len(needle)]) == string(needle) {
+			return i
+		}
+	}
+	return -1
+}
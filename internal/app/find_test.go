@@ -0,0 +1,95 @@
+package app
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestFindOnScreen(t *testing.T) {
+	screen := func(id, text string) *terminal.Window {
+		emu := vt.NewEmulator(20, 5)
+		_, _ = emu.Write([]byte(text))
+		return &terminal.Window{ID: id, Terminal: emu, Width: 22, Height: 7, Workspace: 1}
+	}
+	m := NewOS(OSOptions{})
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		screen("window-one-0000", "ok\r\n"),
+		screen("window-two-0000", "build\r\n  Error: 世界 x\r\n"),
+		screen("window-tri-0000", "error again\r\n"),
+		screen("window-far-0000", "error elsewhere\r\n"),
+	}
+	m.Windows[3].Workspace = 2
+	m.FocusedWindow = 0
+
+	// Lower case matches any case; columns count wide characters as two cells
+	id, err := m.FindOnScreen("error: 世界")
+	if err != nil || id != "window-two-0000" || m.FocusedWindow != 1 {
+		t.Fatalf("FindOnScreen() = %q, %v, focused %d; want window two", id, err, m.FocusedWindow)
+	}
+	w := m.Windows[1]
+	if w.SelectionStart.X != 2 || w.SelectionEnd.X != 12 || w.SelectionStart.Y != 1 || w.SelectedText != "Error: 世界" {
+		t.Errorf("highlighted %v-%v %q, want columns 2-12 of row 1", w.SelectionStart, w.SelectionEnd, w.SelectedText)
+	}
+	if m.MouseSelection != w {
+		t.Error("match not cleared by the next click or key")
+	}
+
+	// Searching again moves on to the next window, skipping other workspaces
+	if id, _ := m.FindOnScreen("error"); id != "window-tri-0000" || w.SelectedText != "" {
+		t.Errorf("second FindOnScreen() = %q, want window three with the first match cleared", id)
+	}
+	if id, _ := m.FindOnScreen("error"); id != "window-two-0000" {
+		t.Errorf("third FindOnScreen() = %q, want to wrap around to window two", id)
+	}
+
+	// Upper case only matches that case
+	if _, err := m.FindOnScreen("ERROR"); err == nil {
+		t.Error("expected upper case query to match case")
+	}
+}
+
+func TestFindPromptTakesAnyScript(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.LastFindQuery = "err"
+	m.StartFindPrompt()
+	if !m.FindPrompt.Active || m.FindPrompt.Buffer != "err" {
+		t.Fatalf("prompt = %+v, want it open with the last query", m.FindPrompt)
+	}
+
+	for _, key := range []tea.KeyPressMsg{
+		{Code: 'o', Text: "o"},
+		{Code: 'r', Text: "r"},
+		{Code: tea.KeySpace, Text: " "},
+		{Code: '世', Text: "世"},
+		{Code: 'é', Text: "é"},
+		{Code: 'x'},
+	} {
+		if !m.FindPrompt.Edit(key) {
+			t.Errorf("Edit(%q) ignored", key.String())
+		}
+	}
+	if !m.FindPrompt.Edit(tea.KeyPressMsg{Code: tea.KeyBackspace}) {
+		t.Error("backspace ignored")
+	}
+	for _, key := range []tea.KeyPressMsg{
+		{Code: 'c', Mod: tea.ModCtrl},
+		{Code: tea.KeyTab},
+		{Code: tea.KeyUp},
+	} {
+		if m.FindPrompt.Edit(key) {
+			t.Errorf("Edit(%q) changed the text", key.String())
+		}
+	}
+	if got := m.FindPrompt.Buffer; got != "error 世é" {
+		t.Errorf("buffer = %q, want %q", got, "error 世é")
+	}
+
+	m.CancelFindPrompt()
+	if m.FindPrompt.Active || m.FindPrompt.Buffer != "" {
+		t.Errorf("prompt = %+v after cancel, want it closed", m.FindPrompt)
+	}
+}
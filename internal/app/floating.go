@@ -0,0 +1,60 @@
+package app
+
+import (
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// ToggleFloating takes the focused window out of the tiling layout or puts
+// a floating window back into it. A window that floats again returns to the
+// geometry it last had while floating; the first time it takes three fifths
+// of the screen, placed by appearance.window_placement.
+func (m *OS) ToggleFloating() {
+	window := m.GetFocusedWindow()
+	if window == nil || window.Minimized || window.PiP {
+		return
+	}
+
+	if window.Floating {
+		m.RememberWindowGeometry(window)
+		window.FloatX, window.FloatY = window.X, window.Y
+		window.FloatWidth, window.FloatHeight = window.Width, window.Height
+		window.Floating = false
+		if m.AutoTiling {
+			m.AddWindowToBSPTree(window)
+		}
+		m.ShowNotification("Window tiled", "info", config.NotificationDuration)
+	} else {
+		window.Floating = true
+		if m.AutoTiling {
+			m.RemoveWindowFromBSPTree(window)
+			m.placeFloatingWindow(window)
+		}
+		m.ShowNotification("Window floating", "info", config.NotificationDuration)
+	}
+
+	// Restack so floating windows stay above the tiled ones
+	m.FocusWindow(m.FocusedWindow)
+	m.LogInfo("Window %s floating: %v", window.ID[:8], window.Floating)
+}
+
+// placeFloatingWindow moves a window that just left the tiling layout to its
+// remembered floating geometry, kept within the screen.
+func (m *OS) placeFloatingWindow(window *terminal.Window) {
+	screenWidth, screenHeight := m.GetRenderWidth(), m.GetUsableHeight()
+	width, height := window.FloatWidth, window.FloatHeight
+	x, y := window.FloatX, window.FloatY
+	if width <= 0 || height <= 0 {
+		width, height = screenWidth*3/5, screenHeight*3/5
+		x, y = m.newWindowPosition(window, screenWidth, screenHeight, width, height)
+	}
+	minWidth, minHeight := window.MinimumSize()
+	width = max(min(width, screenWidth), minWidth)
+	height = max(min(height, screenHeight), minHeight)
+	x = max(0, min(x, screenWidth-width))
+	y = max(m.GetTopMargin(), min(y, m.GetTopMargin()+screenHeight-height))
+
+	window.X, window.Y = x, y
+	window.Resize(width, height)
+	window.MarkPositionDirty()
+}
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestToggleFloating(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", Width: 40, Height: 20, Workspace: 1},
+	}
+	m.FocusWindow(1)
+	m.ToggleAutoTiling()
+	m.CompleteAllAnimations()
+
+	tiled := m.Windows[1]
+	m.ToggleFloating()
+	if !tiled.Floating || m.WorkspaceTrees[1].HasWindow(m.getWindowIntID(tiled.ID)) {
+		t.Fatal("expected the window to float outside the tiling tree")
+	}
+	if tiled.Z <= m.Windows[0].Z {
+		t.Error("expected the floating window above the tiled one")
+	}
+	m.FocusWindow(0)
+	if tiled.Z <= m.Windows[0].Z {
+		t.Error("expected the floating window to stay above the focused tiled window")
+	}
+
+	// Tiling again remembers the floating geometry for the next float
+	m.FocusWindow(1)
+	tiled.X, tiled.Y, tiled.Width, tiled.Height = 10, 5, 50, 20
+	m.ToggleFloating()
+	m.CompleteAllAnimations()
+	if tiled.Floating || !m.WorkspaceTrees[1].HasWindow(m.getWindowIntID(tiled.ID)) {
+		t.Fatal("expected the window back in the tiling tree")
+	}
+	m.ToggleFloating()
+	if tiled.X != 10 || tiled.Y != 5 || tiled.FloatWidth != 50 {
+		t.Errorf("expected the window to float at 10,5 again, got %d,%d", tiled.X, tiled.Y)
+	}
+
+	state := m.BuildSessionState()
+	if !state.Windows[1].Floating || state.Windows[1].FloatX != 10 {
+		t.Errorf("expected floating state in the session, got %+v", state.Windows[1])
+	}
+}
+
+func TestNewWindowPosition(t *testing.T) {
+	defer func(placement string) { config.WindowPlacement = placement }(config.WindowPlacement)
+
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 100, 40
+	m.CurrentWorkspace = 1
+	screenWidth, screenHeight := m.GetRenderWidth(), m.GetUsableHeight()
+	top := m.GetTopMargin()
+	m.LastMouseX, m.LastMouseY = 90, 10
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", X: 0, Y: top, Width: 50, Height: screenHeight, Workspace: 1, Z: 0},
+		{ID: "window-two-0000", X: 20, Y: top + 5, Width: 20, Height: 10, Workspace: 1, Z: 1},
+	}
+
+	tests := []struct {
+		placement string
+		wantX     int
+		wantY     int
+	}{
+		{"cursor", screenWidth - 40, 10},
+		{"center", (screenWidth - 40) / 2, top + (screenHeight-20)/2},
+		{"cascade", 22, top + 6},
+		{"smart", screenWidth - 40, top},
+	}
+	for _, tt := range tests {
+		t.Run(tt.placement, func(t *testing.T) {
+			config.WindowPlacement = tt.placement
+			x, y := m.newWindowPosition(nil, screenWidth, screenHeight, 40, 20)
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("got %d,%d, want %d,%d", x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+
+	// Cascading starts over when the next window would leave the screen
+	config.WindowPlacement = "cascade"
+	m.Windows[1].X = screenWidth - 41
+	if x, y := m.newWindowPosition(nil, screenWidth, screenHeight, 40, 20); x != 0 || y != top {
+		t.Errorf("got %d,%d, want the top left corner", x, y)
+	}
+}
+
+func TestPiPSessionState(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 100, 40
+	m.CurrentWorkspace = 1
+	window := &terminal.Window{ID: "window-pip-0000", X: 10, Y: 5, Width: 50, Height: 20, Workspace: 1}
+	m.Windows = []*terminal.Window{window}
+	m.FocusedWindow = 0
+	m.TogglePiP()
+	if !window.PiP {
+		t.Fatal("expected the window in picture-in-picture")
+	}
+
+	ws := m.BuildSessionState().Windows[0]
+	restored := &terminal.Window{}
+	restorePiP(restored, &ws)
+	if !restored.PiP || restored.PrePiPX != 10 || restored.PrePiPY != 5 || restored.PrePiPWidth != 50 || restored.PrePiPHeight != 20 {
+		t.Errorf("restored picture-in-picture state = %t %d,%d %dx%d, want true 10,5 50x20",
+			restored.PiP, restored.PrePiPX, restored.PrePiPY, restored.PrePiPWidth, restored.PrePiPHeight)
+	}
+}
+
+func TestMinVisibleColumns(t *testing.T) {
+	defer func(n int) { config.MinVisibleColumns = n }(config.MinVisibleColumns)
+	config.MinVisibleColumns = 20
+
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	top := m.GetTopMargin()
+
+	// A window narrower than the setting can still reach either edge
+	if x, _ := m.clampMovePosition(-5, top+5, 12, 6); x != 0 {
+		t.Errorf("12 column window moved past the left edge at x %d, want 0", x)
+	}
+	if x, _ := m.clampMovePosition(115, top+5, 12, 6); x != 108 {
+		t.Errorf("12 column window moved past the right edge at x %d, want 108", x)
+	}
+	if x, _ := m.clampMovePosition(-50, top+5, 40, 6); x != -20 {
+		t.Errorf("40 column window moved past the left edge at x %d, want 20 columns kept on screen", x)
+	}
+
+	narrow := &terminal.Window{ID: "window-thin-000", X: 118, Y: top + 5, Width: 30, Height: 10, Workspace: 1}
+	m.Windows = []*terminal.Window{narrow}
+	m.ClampWindowsToView()
+	if narrow.X != 100 {
+		t.Errorf("window past the right edge clamped to x %d, want 100", narrow.X)
+	}
+
+	// 0 lets windows leave the screen entirely
+	config.MinVisibleColumns = 0
+	if x, _ := m.clampMovePosition(-60, top+5, 40, 6); x != -60 {
+		t.Errorf("window held at x %d with the clamp off, want -60", x)
+	}
+	narrow.X = 130
+	m.ClampWindowsToView()
+	if narrow.X != 130 {
+		t.Errorf("window clamped to x %d with the clamp off, want 130", narrow.X)
+	}
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// SyncTerminalFocus tells embedded applications that enabled focus reporting
+// (?1004) when they gain or lose focus. The focused pane of the focused window
+// has focus while the host terminal does; switching windows, panes, tabs or
+// workspaces and the host losing focus all move it. It is cheap to call
+// whenever focus may have changed.
+func (m *OS) SyncTerminalFocus() {
+	var focused *terminal.Window
+	if !m.HostBlurred {
+		focused = m.GetFocusedPane()
+		if focused != nil && (focused.Minimized || focused.Workspace != m.CurrentWorkspace) {
+			focused = nil
+		}
+	}
+	if focused == m.focusReported {
+		return
+	}
+
+	if old := m.focusReported; old != nil && !old.ProcessExited {
+		reportFocus(old, false)
+	}
+	if focused != nil {
+		reportFocus(focused, true)
+	}
+	m.focusReported = focused
+}
+
+// reportFocus sends a focus-in or focus-out report to the window's app if it
+// enabled focus reporting. Daemon windows drop their emulator's responses, so
+// the report is written to the PTY directly.
+func reportFocus(w *terminal.Window, focused bool) {
+	if w.Terminal == nil {
+		return
+	}
+	if !w.DaemonMode {
+		if focused {
+			w.Terminal.Focus()
+		} else {
+			w.Terminal.Blur()
+		}
+		return
+	}
+	if !w.Terminal.FocusEventsEnabled() {
+		return
+	}
+	seq := ansi.Blur
+	if focused {
+		seq = ansi.Focus
+	}
+	_ = w.SendInput([]byte(seq))
+}
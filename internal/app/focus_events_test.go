@@ -0,0 +1,75 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+// focusReports collects the focus reports an emulator sends to its app.
+func focusReports(t *testing.T, emu *vt.Emulator) <-chan string {
+	t.Helper()
+	reports := make(chan string, 8)
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, err := emu.Read(buf)
+			if err != nil {
+				return
+			}
+			reports <- string(buf[:n])
+		}
+	}()
+	return reports
+}
+
+func expectReport(t *testing.T, reports <-chan string, want string) {
+	t.Helper()
+	select {
+	case got := <-reports:
+		if got != want {
+			t.Errorf("focus report = %q, want %q", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timeout waiting for focus report %q", want)
+	}
+}
+
+func TestSyncTerminalFocus(t *testing.T) {
+	first, second := vt.NewEmulator(80, 24), vt.NewEmulator(80, 24)
+	for _, emu := range []*vt.Emulator{first, second} {
+		_, _ = emu.Write([]byte("\x1b[?1004h"))
+	}
+	firstReports, secondReports := focusReports(t, first), focusReports(t, second)
+
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "first-window-00", Terminal: first, Width: 40, Height: 20, Workspace: 1},
+		{ID: "second-window-0", Terminal: second, Width: 40, Height: 20, Workspace: 1},
+	}
+	m.FocusedWindow = 0
+
+	m.SyncTerminalFocus()
+	expectReport(t, firstReports, "\x1b[I")
+
+	// Switching windows moves focus
+	m.FocusWindow(1)
+	m.SyncTerminalFocus()
+	expectReport(t, firstReports, "\x1b[O")
+	expectReport(t, secondReports, "\x1b[I")
+
+	// The host losing focus blurs the focused app
+	m.HostBlurred = true
+	m.SyncTerminalFocus()
+	expectReport(t, secondReports, "\x1b[O")
+
+	// Nothing is reported again while focus stays put
+	m.SyncTerminalFocus()
+	select {
+	case got := <-secondReports:
+		t.Errorf("unexpected focus report %q", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
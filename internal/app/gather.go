@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// GatherWindows pulls the floating windows of every workspace whose title
+// bar is not entirely on screen back into view, moving each as little as it
+// takes to fit the window on screen without resizing it. It returns the
+// number of windows moved.
+func (m *OS) GatherWindows() int {
+	area := m.windowArea()
+	gathered := 0
+	for _, w := range m.Windows {
+		floating := w.Floating || !m.WorkspaceAutoTiling(w.Workspace)
+		if !floating || w.Minimized || w.Minimizing || w.PiP {
+			continue
+		}
+		if w.X >= 0 && w.X+w.Width <= area.width && w.Y >= area.top && w.Y < area.top+area.height {
+			continue
+		}
+		w.X = min(max(w.X, 0), max(area.width-w.Width, 0))
+		w.Y = min(max(w.Y, area.top), max(area.top+area.height-w.Height, area.top))
+		w.MarkPositionDirty()
+		m.RememberWindowGeometry(w)
+		gathered++
+	}
+	if gathered > 0 {
+		m.MarkAllDirty()
+		m.SyncStateToDaemon()
+	}
+	return gathered
+}
+
+// GatherWindowsNotify gathers the windows off screen and reports how many
+// were moved.
+func (m *OS) GatherWindowsNotify() {
+	switch n := m.GatherWindows(); n {
+	case 0:
+		m.ShowNotification("No windows off screen", "info", config.NotificationDuration)
+	case 1:
+		m.ShowNotification("Gathered 1 window", "info", config.NotificationDuration)
+	default:
+		m.ShowNotification(fmt.Sprintf("Gathered %d windows", n), "info", config.NotificationDuration)
+	}
+}
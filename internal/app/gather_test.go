@@ -0,0 +1,36 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestGatherWindows(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	top := m.GetTopMargin()
+	bottom := top + m.GetUsableHeight()
+	left := &terminal.Window{ID: "window-left-000", X: -30, Y: top + 5, Width: 40, Height: 10, Workspace: 1}
+	below := &terminal.Window{ID: "window-below-00", X: 100, Y: bottom + 2, Width: 40, Height: 10, Workspace: 2}
+	inView := &terminal.Window{ID: "window-view-000", X: 10, Y: top + 2, Width: 40, Height: 10, Workspace: 1}
+	minimized := &terminal.Window{ID: "window-mini-000", X: -30, Y: top, Width: 40, Height: 10, Workspace: 1, Minimized: true}
+	m.Windows = []*terminal.Window{left, below, inView, minimized}
+
+	if n := m.GatherWindows(); n != 2 {
+		t.Errorf("GatherWindows() = %d, want 2", n)
+	}
+	if left.X != 0 || left.Y != top+5 {
+		t.Errorf("window off the left edge at %d,%d, want 0,%d", left.X, left.Y, top+5)
+	}
+	if below.X != 80 || below.Y != bottom-10 || below.Width != 40 {
+		t.Errorf("window below the screen at %d,%d width %d, want 80,%d width 40", below.X, below.Y, below.Width, bottom-10)
+	}
+	if inView.X != 10 || minimized.X != -30 {
+		t.Error("windows in view or minimized were moved")
+	}
+	if n := m.GatherWindows(); n != 0 {
+		t.Errorf("GatherWindows() again = %d, want 0", n)
+	}
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/ui"
+	"github.com/adrg/xdg"
+)
+
+// windowGeometry is the floating position and size of a window.
+type windowGeometry struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// geometrySaveDelay is how long the remembered geometry has to stay the same
+// before it is written, so moving windows around writes the file once.
+const geometrySaveDelay = 2 * time.Second
+
+// GeometrySavedMsg reports the outcome of writing the remembered geometry.
+type GeometrySavedMsg struct {
+	Err error
+}
+
+// geometryMemory maps command executables to the geometry of their last
+// floating window.
+type geometryMemory map[string]windowGeometry
+
+// getGeometryFilePath returns the path of the file that keeps the floating
+// geometry of each command's last window.
+func getGeometryFilePath() (string, error) {
+	path, err := xdg.StateFile("tuios/geometry.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to get geometry file path: %w", err)
+	}
+	return path, nil
+}
+
+// commandName returns the executable of a shell command line, the key its
+// windows' geometry is remembered under.
+func commandName(command string) string {
+	for _, field := range strings.Fields(command) {
+		// Skip leading variable assignments such as FOO=1
+		if strings.Contains(field, "=") {
+			continue
+		}
+		return filepath.Base(field)
+	}
+	return ""
+}
+
+// loadRememberedGeometry reads the remembered geometry on first use. A
+// missing or unreadable file leaves nothing remembered.
+func (m *OS) loadRememberedGeometry() {
+	if m.rememberedGeometry != nil {
+		return
+	}
+	m.rememberedGeometry = make(geometryMemory)
+	path, err := getGeometryFilePath()
+	if err != nil {
+		m.LogError("%v", err)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			m.LogError("Failed to read geometry file: %v", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &m.rememberedGeometry); err != nil {
+		m.LogError("Failed to parse geometry file: %v", err)
+	}
+}
+
+// hasFloatingGeometry reports whether a window is placed freely: floating,
+// or any window while tiling is off.
+func (m *OS) hasFloatingGeometry(w *terminal.Window) bool {
+	return (w.Floating || !m.AutoTiling) && !w.Minimized && !w.Minimizing && !w.PiP
+}
+
+// geometryCommand returns the command a window's geometry is remembered
+// under: the program running in front of its shell, or else the command the
+// window was started with.
+func (m *OS) geometryCommand(w *terminal.Window) string {
+	if program := m.runningProgram(w); program != "" && program != unnamedProgram {
+		return program
+	}
+	return w.Command
+}
+
+// RememberWindowGeometry records the geometry of a window running a
+// command, when appearance.remember_geometry is set and the window is placed
+// freely, so the command's next window opens in the same place. The file is
+// written by SaveRememberedGeometry once the geometry settles.
+func (m *OS) RememberWindowGeometry(w *terminal.Window) {
+	if !config.RememberGeometry || w == nil || !m.hasFloatingGeometry(w) {
+		return
+	}
+	command := m.geometryCommand(w)
+	if command == "" {
+		return
+	}
+	m.loadRememberedGeometry()
+	geometry := windowGeometry{X: w.X, Y: w.Y, Width: w.Width, Height: w.Height}
+	if m.rememberedGeometry[command] == geometry {
+		return
+	}
+	m.rememberedGeometry[command] = geometry
+	m.geometrySaveAt = time.Now().Add(geometrySaveDelay)
+}
+
+// SaveRememberedGeometry returns a command that writes the remembered
+// geometry once it has not changed for geometrySaveDelay, or nil while
+// nothing is waiting to be written.
+func (m *OS) SaveRememberedGeometry(now time.Time) tea.Cmd {
+	if m.geometrySaveAt.IsZero() || now.Before(m.geometrySaveAt) {
+		return nil
+	}
+	m.geometrySaveAt = time.Time{}
+	data, err := json.MarshalIndent(m.rememberedGeometry, "", "  ")
+	return func() tea.Msg {
+		if err == nil {
+			err = writeGeometryFile(data)
+		}
+		return GeometrySavedMsg{Err: err}
+	}
+}
+
+// flushRememberedGeometry writes geometry still waiting to be saved right
+// away, when TUIOS exits.
+func (m *OS) flushRememberedGeometry() {
+	if m.geometrySaveAt.IsZero() {
+		return
+	}
+	if msg, ok := m.SaveRememberedGeometry(m.geometrySaveAt)().(GeometrySavedMsg); ok && msg.Err != nil {
+		m.LogError("Failed to save window geometry: %v", msg.Err)
+	}
+}
+
+// writeGeometryFile replaces the geometry file with data.
+func writeGeometryFile(data []byte) error {
+	path, err := getGeometryFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write geometry file: %w", err)
+	}
+	return nil
+}
+
+// restoreRememberedGeometry moves a window just started with a command to
+// the geometry the command's last window had, kept within the screen. In
+// tiling mode the window floats. It reports whether a geometry was applied.
+func (m *OS) restoreRememberedGeometry(w *terminal.Window) bool {
+	if !config.RememberGeometry || w.Command == "" {
+		return false
+	}
+	m.loadRememberedGeometry()
+	geometry, ok := m.rememberedGeometry[w.Command]
+	if !ok || geometry.Width <= 0 || geometry.Height <= 0 {
+		return false
+	}
+
+	// Drop the animation that would tile the window
+	m.Animations = slices.DeleteFunc(m.Animations, func(anim *ui.Animation) bool {
+		return anim.Window == w
+	})
+	if m.AutoTiling && !w.Floating {
+		w.Floating = true
+		m.RemoveWindowFromBSPTree(w)
+	}
+	w.FloatX, w.FloatY = geometry.X, geometry.Y
+	w.FloatWidth, w.FloatHeight = geometry.Width, geometry.Height
+	m.placeFloatingWindow(w)
+
+	// Restack so a floating window stays above the tiled ones
+	for i, win := range m.Windows {
+		if win == w {
+			m.FocusWindow(i)
+			break
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/adrg/xdg"
+)
+
+func TestRememberedGeometry(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	defer func(remember bool) { config.RememberGeometry = remember }(config.RememberGeometry)
+	config.RememberGeometry = true
+
+	if got := commandName("TERM=xterm /usr/bin/btop --utf-force"); got != "btop" {
+		t.Errorf("commandName() = %q, want btop", got)
+	}
+
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	top := m.GetTopMargin()
+	btop := &terminal.Window{ID: "window-btop-000", Command: "btop", X: 30, Y: top + 4, Width: 60, Height: 20, Workspace: 1}
+	m.Windows = []*terminal.Window{btop}
+	m.RememberWindowGeometry(btop)
+	if m.SaveRememberedGeometry(time.Now()) != nil {
+		t.Error("expected the geometry to be written only once it settles")
+	}
+	if msg := m.SaveRememberedGeometry(time.Now().Add(geometrySaveDelay))(); msg.(GeometrySavedMsg).Err != nil {
+		t.Fatalf("failed to save the geometry: %v", msg.(GeometrySavedMsg).Err)
+	}
+
+	// A new session reads the geometry back from the state file
+	m = NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-btop-001", Command: "btop", Width: 40, Height: 20, Workspace: 1},
+	}
+	m.ToggleAutoTiling()
+	m.CompleteAllAnimations()
+
+	reopened := m.Windows[1]
+	if !m.restoreRememberedGeometry(reopened) {
+		t.Fatal("expected the remembered geometry to be applied")
+	}
+	if !reopened.Floating || reopened.X != 30 || reopened.Y != top+4 || reopened.FloatWidth != 60 || reopened.FloatHeight != 20 {
+		t.Errorf("expected btop floating at 30,%d 60x20, got floating=%v %d,%d %dx%d",
+			top+4, reopened.Floating, reopened.X, reopened.Y, reopened.FloatWidth, reopened.FloatHeight)
+	}
+	if m.restoreRememberedGeometry(m.Windows[0]) {
+		t.Error("expected no geometry for a window without a command")
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"slices"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/ui"
+)
+
+// freeFocusedWindow returns the index of the focused window if it is placed
+// freely (any window while tiling is off, or a floating one), or -1.
+func (m *OS) freeFocusedWindow() int {
+	w := m.GetFocusedWindow()
+	if w == nil || w.Workspace != m.CurrentWorkspace || !m.hasFloatingGeometry(w) {
+		return -1
+	}
+	return m.FocusedWindow
+}
+
+// SnapFocused snaps the focused window to a part of the screen if it is
+// placed freely. It reports whether the window was snapped.
+func (m *OS) SnapFocused(quarter SnapQuarter) bool {
+	i := m.freeFocusedWindow()
+	if i < 0 {
+		return false
+	}
+	m.Snap(i, quarter)
+	return true
+}
+
+// animateWindowTo moves the window at index i to the given geometry, grown
+// to its minimum size.
+func (m *OS) animateWindowTo(i, x, y, width, height int) {
+	w := m.Windows[i]
+	minWidth, minHeight := w.MinimumSize()
+	width, height = max(width, minWidth), max(height, minHeight)
+	if anim := ui.NewSnapAnimation(w, x, y, width, height, config.GetAnimationDuration()); anim != nil {
+		m.Animations = append(m.Animations, anim)
+	}
+}
+
+// CenterFocusedWindow moves the focused window to the middle of the screen,
+// keeping its size, if it is placed freely.
+func (m *OS) CenterFocusedWindow() {
+	i := m.freeFocusedWindow()
+	if i < 0 {
+		return
+	}
+	m.CompleteWindowAnimations(i)
+	w := m.Windows[i]
+	x := max(0, (m.GetRenderWidth()-w.Width)/2)
+	y := m.GetTopMargin() + max(0, (m.GetUsableHeight()-w.Height)/2)
+	m.animateWindowTo(i, x, y, w.Width, w.Height)
+}
+
+// geometryPresetBounds returns the screen geometry of a preset.
+func (m *OS) geometryPresetBounds(preset config.GeometryPreset) (x, y, width, height int) {
+	screenWidth, screenHeight := m.GetRenderWidth(), m.GetUsableHeight()
+	return preset.X * screenWidth / 100, m.GetTopMargin() + preset.Y*screenHeight/100,
+		preset.Width * screenWidth / 100, preset.Height * screenHeight / 100
+}
+
+// CycleGeometryPreset moves the focused window, if it is placed freely, to
+// the geometry preset after the one it is at, starting over with the first.
+func (m *OS) CycleGeometryPreset() {
+	i := m.freeFocusedWindow()
+	if i < 0 {
+		return
+	}
+	m.CompleteWindowAnimations(i)
+	w := m.Windows[i]
+	presets := config.ActiveGeometryPresets()
+	current := slices.IndexFunc(presets, func(preset config.GeometryPreset) bool {
+		x, y, width, height := m.geometryPresetBounds(preset)
+		return w.X == x && w.Y == y && w.Width == width && w.Height == height
+	})
+	preset := presets[(current+1)%len(presets)]
+	x, y, width, height := m.geometryPresetBounds(preset)
+	m.animateWindowTo(i, x, y, width, height)
+	if preset.Name != "" {
+		m.ShowNotification("Geometry: "+preset.Name, "info", config.NotificationDuration)
+	}
+}
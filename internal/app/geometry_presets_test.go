@@ -0,0 +1,51 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestGeometryPresets(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	top := m.GetTopMargin()
+	screenWidth, screenHeight := m.GetRenderWidth(), m.GetUsableHeight()
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", X: 3, Y: top + 2, Width: 40, Height: 20, Workspace: 1},
+	}
+	m.FocusWindow(0)
+	w := m.Windows[0]
+
+	m.CenterFocusedWindow()
+	m.CompleteAllAnimations()
+	if w.X != (screenWidth-40)/2 || w.Y != top+(screenHeight-20)/2 || w.Width != 40 {
+		t.Errorf("expected the window centered at its size, got %d,%d %dx%d", w.X, w.Y, w.Width, w.Height)
+	}
+
+	m.SnapFocused(SnapCenterThird)
+	m.CompleteAllAnimations()
+	if w.X != screenWidth/3 || w.Width != screenWidth-2*(screenWidth/3) || w.Height != screenHeight {
+		t.Errorf("expected the window in the middle third, got %d,%d %dx%d", w.X, w.Y, w.Width, w.Height)
+	}
+
+	// Presets cycle in order and start over after the last one
+	presets := config.ActiveGeometryPresets()
+	for i := range len(presets) + 1 {
+		m.CycleGeometryPreset()
+		m.CompleteAllAnimations()
+		x, y, width, height := m.geometryPresetBounds(presets[i%len(presets)])
+		if w.X != x || w.Y != y || w.Width != width || w.Height != height {
+			t.Errorf("cycle %d: expected preset %q, got %d,%d %dx%d", i, presets[i%len(presets)].Name, w.X, w.Y, w.Width, w.Height)
+		}
+	}
+
+	// Tiled windows keep their place
+	m.ToggleAutoTiling()
+	m.CompleteAllAnimations()
+	if m.SnapFocused(SnapLeftThird) {
+		t.Error("expected a tiled window not to snap")
+	}
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"hash/fnv"
+	"image/color"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+)
+
+// groupMembers returns the indices of the other windows in the group of the
+// window at index i.
+func (m *OS) groupMembers(i int) []int {
+	if i < 0 || i >= len(m.Windows) || m.Windows[i].Group == "" {
+		return nil
+	}
+	var members []int
+	for j, w := range m.Windows {
+		if j != i && w.Group == m.Windows[i].Group {
+			members = append(members, j)
+		}
+	}
+	return members
+}
+
+// GroupInputTargets returns the terminals that keys typed into the focused
+// window also go to: the active panes of the other windows of its group,
+// leaving out those with locked input.
+func (m *OS) GroupInputTargets() []*terminal.Window {
+	var targets []*terminal.Window
+	for _, j := range m.groupMembers(m.FocusedWindow) {
+		if pane := m.Windows[j].ActivePane(); pane != nil && !m.Windows[j].InputLocked && pane.Terminal != nil {
+			targets = append(targets, pane)
+		}
+	}
+	return targets
+}
+
+// GroupWindowWith adds the window at index to the group of the window with
+// ID targetID, creating the group if neither window is in one. A window
+// already in another group leaves it for the target's group.
+func (m *OS) GroupWindowWith(index int, targetID string) {
+	if index < 0 || index >= len(m.Windows) {
+		return
+	}
+	var target *terminal.Window
+	for _, w := range m.Windows {
+		if w.ID == targetID {
+			target = w
+			break
+		}
+	}
+	if target == nil {
+		return
+	}
+	window := m.Windows[index]
+	if window == target {
+		m.ShowNotification("Pick another window to group with", "info", config.NotificationDuration)
+		return
+	}
+
+	if target.Group == "" {
+		target.Group = window.Group
+		if target.Group == "" {
+			target.Group = createID()
+		}
+	}
+	old := window.Group
+	window.Group = target.Group
+	m.dissolveLoneGroup(old)
+
+	m.ShowNotification("Windows grouped", "success", config.NotificationDuration)
+	m.LogInfo("Grouped window %s with window %s", window.ID[:8], target.ID[:8])
+}
+
+// UngroupFocusedWindow removes the focused window from its group.
+func (m *OS) UngroupFocusedWindow() {
+	focused := m.GetFocusedWindow()
+	if focused == nil || focused.Group == "" {
+		m.ShowNotification("Window is not in a group", "info", config.NotificationDuration)
+		return
+	}
+	group := focused.Group
+	focused.Group = ""
+	m.dissolveLoneGroup(group)
+	m.ShowNotification("Window removed from group", "info", config.NotificationDuration)
+}
+
+// dissolveLoneGroup clears the group of the last window left in it.
+func (m *OS) dissolveLoneGroup(group string) {
+	if group == "" {
+		return
+	}
+	var last int
+	count := 0
+	for i, w := range m.Windows {
+		if w.Group == group {
+			last = i
+			count++
+		}
+	}
+	if count == 1 {
+		m.Windows[last].Group = ""
+	}
+}
+
+// CloseWindowAndGroup closes the window at index together with the other
+// windows of its group.
+func (m *OS) CloseWindowAndGroup(index int) {
+	if index < 0 || index >= len(m.Windows) {
+		return
+	}
+	closing := []*terminal.Window{m.Windows[index]}
+	for _, j := range m.groupMembers(index) {
+		closing = append(closing, m.Windows[j])
+	}
+	for _, window := range closing {
+		for i, w := range m.Windows {
+			if w == window {
+				m.DeleteWindow(i)
+				break
+			}
+		}
+	}
+}
+
+// groupAccentColor returns the accent color marking the windows of group in
+// the dock and sidebar, picked from the bright colors of the theme.
+func groupAccentColor(group string) color.Color {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(group))
+	palette := theme.GetANSIPalette()
+	// Bright red through bright cyan
+	return palette[9+int(h.Sum32()%6)]
+}
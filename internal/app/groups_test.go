@@ -0,0 +1,79 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestWindowGroups(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-three-00", Width: 40, Height: 20, Workspace: 1},
+	}
+	m.FocusedWindow = 0
+
+	m.GroupWindowWith(1, "window-one-0000")
+	if m.Windows[0].Group == "" || m.Windows[0].Group != m.Windows[1].Group {
+		t.Fatalf("expected windows one and two to share a group, got %q and %q", m.Windows[0].Group, m.Windows[1].Group)
+	}
+
+	m.MinimizeWindow(0)
+	if !m.Windows[0].Minimized || !m.Windows[1].Minimized || m.Windows[2].Minimized {
+		t.Errorf("expected only the group to be minimized")
+	}
+	m.RestoreWindow(1)
+	if m.Windows[0].Minimized || m.Windows[1].Minimized {
+		t.Errorf("expected the group to be restored together")
+	}
+	if m.FocusedWindow != 1 {
+		t.Errorf("expected the restored window to be focused, got %d", m.FocusedWindow)
+	}
+
+	m.MoveWindowToWorkspace(0, 2)
+	if m.Windows[0].Workspace != 2 || m.Windows[1].Workspace != 2 || m.Windows[2].Workspace != 1 {
+		t.Errorf("expected the group to move to workspace 2")
+	}
+
+	m.CloseWindowAndGroup(1)
+	if len(m.Windows) != 1 || m.Windows[0].ID != "window-three-00" {
+		t.Fatalf("expected only window three to remain, got %d windows", len(m.Windows))
+	}
+}
+
+func TestWindowGroupDissolves(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Workspace: 1, Group: "g"},
+		{ID: "window-two-0000", Workspace: 1, Group: "g"},
+	}
+	m.FocusedWindow = 0
+
+	m.UngroupFocusedWindow()
+	if m.Windows[0].Group != "" || m.Windows[1].Group != "" {
+		t.Errorf("expected a group left with one window to be dissolved, got %q and %q", m.Windows[0].Group, m.Windows[1].Group)
+	}
+}
+
+func TestGroupInputTargets(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Workspace: 1, Group: "g", Terminal: vt.NewEmulator(10, 2)},
+		{ID: "window-two-0000", Workspace: 1, Group: "g", Terminal: vt.NewEmulator(10, 2)},
+		{ID: "window-three-00", Workspace: 1, Group: "g", Terminal: vt.NewEmulator(10, 2), InputLocked: true},
+		{ID: "window-four-000", Workspace: 1, Terminal: vt.NewEmulator(10, 2)},
+	}
+	m.FocusedWindow = 0
+
+	targets := m.GroupInputTargets()
+	if len(targets) != 1 || targets[0] != m.Windows[1] {
+		t.Fatalf("expected keys to go to window two only, got %d targets", len(targets))
+	}
+	m.FocusedWindow = 3
+	if targets := m.GroupInputTargets(); len(targets) != 0 {
+		t.Errorf("expected no targets for an ungrouped window, got %d", len(targets))
+	}
+}
@@ -39,6 +39,7 @@ func GetHelpCategories(registry *config.KeybindRegistry) []HelpCategory {
 			Bindings: generateCategoryBindings(registry, "Window Management", []string{
 				"new_window", "close_window", "rename_window",
 				"minimize_window", "restore_all",
+				"minimize_all_windows", "close_all_windows",
 				"next_window", "prev_window",
 				"terminal_next_window", "terminal_prev_window",
 			}),
@@ -52,12 +53,14 @@ func GetHelpCategories(registry *config.KeybindRegistry) []HelpCategory {
 			Bindings: generateCategoryBindings(registry, "Layout", []string{
 				"snap_left", "snap_right", "snap_fullscreen", "unsnap",
 				"snap_corner_1", "snap_corner_2", "snap_corner_3", "snap_corner_4",
+				"snap_top", "snap_bottom", "snap_third_left", "snap_third_center", "snap_third_right",
+				"snap_two_thirds_left", "snap_two_thirds_right", "center_window", "cycle_geometry_preset",
 			}),
 		},
 		{
 			Name: "Tiling",
 			Bindings: generateCategoryBindings(registry, "Tiling", []string{
-				"toggle_tiling", "swap_left", "swap_right", "swap_up", "swap_down",
+				"toggle_tiling", "toggle_floating", "swap_left", "swap_right", "swap_up", "swap_down",
 				"resize_master_shrink", "resize_master_grow", "resize_height_shrink", "resize_height_grow",
 				"resize_master_shrink_left", "resize_master_grow_left", "resize_height_shrink_top", "resize_height_grow_top",
 			}),
@@ -66,6 +69,8 @@ func GetHelpCategories(registry *config.KeybindRegistry) []HelpCategory {
 			Name: "BSP",
 			Bindings: generateCategoryBindings(registry, "BSP", []string{
 				"split_horizontal", "split_vertical", "rotate_split",
+				"equalize_splits", "grow_tile", "shrink_tile",
+				"promote_master", "cycle_tiling_insert",
 			}),
 		},
 		{
@@ -76,7 +81,7 @@ func GetHelpCategories(registry *config.KeybindRegistry) []HelpCategory {
 			Name: "Modes",
 			Bindings: generateCategoryBindings(registry, "Modes", []string{
 				"enter_terminal_mode", "enter_window_mode",
-				"terminal_exit_mode",
+				"terminal_exit_mode", "terminal_exit_passthrough", "terminal_unlock_input",
 				"toggle_help", "quit",
 			}),
 		},
@@ -185,6 +190,8 @@ func generateDebugBindings() []HelpBinding {
 	return []HelpBinding{
 		{Keys: []string{config.LeaderKey + ", D, l"}, Description: "Toggle log viewer", Category: "Debug"},
 		{Keys: []string{config.LeaderKey + ", D, c"}, Description: "Toggle cache stats", Category: "Debug"},
+		{Keys: []string{config.LeaderKey + ", D, m"}, Description: "Toggle scrollback memory stats", Category: "Debug"},
+		{Keys: []string{config.LeaderKey + ", D, w"}, Description: "Open debug console window", Category: "Debug"},
 		{Keys: []string{config.LeaderKey + ", D, k"}, Description: "Toggle showkeys", Category: "Debug"},
 		{Keys: []string{config.LeaderKey + ", D, a"}, Description: "Toggle animations", Category: "Debug"},
 	}
@@ -228,9 +235,23 @@ func generatePrefixBindings(registry *config.KeybindRegistry) []HelpBinding {
 		"prefix_select_0", "prefix_select_1", "prefix_select_2",
 		"prefix_select_3", "prefix_select_4", "prefix_select_5",
 		"prefix_select_6", "prefix_select_7", "prefix_select_8", "prefix_select_9",
-		"prefix_toggle_tiling", "prefix_workspace", "prefix_minimize",
+		"prefix_toggle_tiling", "prefix_toggle_floating", "prefix_workspace", "prefix_minimize",
 		"prefix_window", "prefix_detach", "prefix_selection",
-		"prefix_help", "prefix_quit", "prefix_fullscreen",
+		"prefix_help", "prefix_quit", "prefix_fullscreen", "prefix_pip", "prefix_mouse_capture",
+		"prefix_window_info", "prefix_replay", "prefix_window_hints", "prefix_image_hints",
+		"prefix_key_passthrough", "prefix_lock_input",
+		"prefix_pin_min_size", "prefix_run_command", "prefix_copy_last_output", "prefix_open_last_output",
+		"prefix_command_history", "prefix_set_mark", "prefix_jump_mark", "prefix_last_window", "prefix_urgent_window", "prefix_tag_window",
+		"prefix_zoom_window", "prefix_find_on_screen", "prefix_move_window", "prefix_gather_windows",
+		"prefix_grow_tile", "prefix_shrink_tile", "prefix_promote_master",
+		"prefix_split_pane_vertical", "prefix_split_pane_horizontal",
+		"prefix_close_pane", "prefix_next_pane",
+		"prefix_pane_left", "prefix_pane_right", "prefix_pane_up", "prefix_pane_down",
+		"prefix_resize_pane_left", "prefix_resize_pane_right",
+		"prefix_resize_pane_up", "prefix_resize_pane_down",
+		"prefix_break_pane", "prefix_join_pane",
+		"prefix_new_tab", "prefix_next_tab", "prefix_prev_tab",
+		"prefix_group_window", "prefix_ungroup_window",
 	}
 
 	// Add debug commands (Leader Key + D ...)
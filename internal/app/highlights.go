@@ -0,0 +1,121 @@
+package app
+
+import (
+	"image/color"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// highlightStyle is a highlight rule with its colors resolved against the
+// theme.
+type highlightStyle struct {
+	re     *regexp.Regexp
+	fg, bg color.Color
+	bold   bool
+}
+
+// ruleColor resolves a highlight rule color: a name or a number below 16 is
+// the theme's ANSI color, anything else is passed to lipgloss. An empty
+// color is nil.
+func ruleColor(palette [16]color.Color, c string) color.Color {
+	if c == "" {
+		return nil
+	}
+	if i, ok := config.ANSIColorNames[strings.ToLower(c)]; ok {
+		return palette[i]
+	}
+	if n, err := strconv.Atoi(c); err == nil && n >= 0 && n < 16 {
+		return palette[n]
+	}
+	return lipgloss.Color(c)
+}
+
+// windowHighlights returns the highlight rules that paint a window's output.
+func windowHighlights(w *terminal.Window) []highlightStyle {
+	rules := config.HighlightRules(w.CustomName, w.Title)
+	if len(rules) == 0 {
+		return nil
+	}
+	palette := theme.GetANSIPalette()
+	styles := make([]highlightStyle, len(rules))
+	for i, rule := range rules {
+		styles[i] = highlightStyle{
+			re:   rule.Regexp,
+			fg:   ruleColor(palette, rule.Color),
+			bg:   ruleColor(palette, rule.Background),
+			bold: rule.Bold,
+		}
+	}
+	return styles
+}
+
+// rowHighlights matches the text of a row against the highlight rules and
+// returns the rule painting each column, or nil when no rule matches. The
+// first matching rule paints a column.
+func rowHighlights(styles []highlightStyle, width int, cellAt func(x int) *uv.Cell) []*highlightStyle {
+	var text strings.Builder
+	// Byte offset in text and column of each cell
+	starts := make([]int, 0, width)
+	columns := make([]int, 0, width)
+	for x := 0; x < width; {
+		cell := cellAt(x)
+		starts = append(starts, text.Len())
+		columns = append(columns, x)
+		if cell == nil || cell.Content == "" {
+			text.WriteByte(' ')
+			x++
+			continue
+		}
+		text.WriteString(cell.Content)
+		x += max(cell.Width, 1)
+	}
+	line := text.String()
+
+	var painted []*highlightStyle
+	for i := range styles {
+		for _, loc := range styles[i].re.FindAllStringIndex(line, -1) {
+			if painted == nil {
+				painted = make([]*highlightStyle, width)
+			}
+			for k := sort.SearchInts(starts, loc[0]); k < len(starts) && starts[k] < loc[1]; k++ {
+				end := width
+				if k+1 < len(columns) {
+					end = columns[k+1]
+				}
+				for x := columns[k]; x < end; x++ {
+					if painted[x] == nil {
+						painted[x] = &styles[i]
+					}
+				}
+			}
+		}
+	}
+	return painted
+}
+
+// paint returns a copy of the cell with the highlight's colors and
+// attributes.
+func (h *highlightStyle) paint(cell *uv.Cell) *uv.Cell {
+	c := uv.EmptyCell
+	if cell != nil {
+		c = *cell
+	}
+	if h.fg != nil {
+		c.Style.Fg = h.fg
+	}
+	if h.bg != nil {
+		c.Style.Bg = h.bg
+	}
+	if h.bold {
+		c.Style.Attrs |= uv.AttrBold
+	}
+	return &c
+}
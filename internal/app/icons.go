@@ -0,0 +1,67 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// appRefreshInterval is how often the programs running in windows are
+// looked up for their icons.
+const appRefreshInterval = time.Second
+
+// RefreshWindowApps looks up the program each window runs, at most once per
+// appRefreshInterval and only while icons are shown. It reports whether any
+// window's program changed.
+func (m *OS) RefreshWindowApps(now time.Time) bool {
+	if !config.ShowAppIcons || config.UseASCIIOnly || now.Sub(m.lastAppRefresh) < appRefreshInterval {
+		return false
+	}
+	m.lastAppRefresh = now
+	changed := false
+	for _, w := range m.Windows {
+		if app := windowApp(w.ActivePane()); app != w.App {
+			w.App = app
+			changed = true
+		}
+	}
+	if changed {
+		// Pills are as wide as their icons
+		m.invalidateDockHeight()
+	}
+	return changed
+}
+
+// windowApp returns the name of the program running in a terminal: the
+// foreground process, else the shell. Daemon windows have no local process,
+// so the first word of the title stands in, which is where most programs put
+// their name.
+func windowApp(w *terminal.Window) string {
+	if name := w.ForegroundProcessName(); name != "" {
+		return name
+	}
+	if w.Cmd != nil && w.Cmd.Path != "" {
+		return filepath.Base(w.Cmd.Path)
+	}
+	if fields := strings.Fields(w.Title); len(fields) > 0 && !isDefaultTitle(w.Title, w.ID) {
+		return filepath.Base(fields[0])
+	}
+	return ""
+}
+
+// windowIconPrefix returns the icon set for the window, else the icon of its
+// program, followed by a space, or an empty string when icons are hidden. A
+// set icon is shown even with appearance.show_icons off, but not in
+// ASCII-only mode.
+func windowIconPrefix(w *terminal.Window) string {
+	if w.Icon != "" && !config.UseASCIIOnly {
+		return w.Icon + " "
+	}
+	if icon := config.AppIcon(w.App); icon != "" {
+		return icon + " "
+	}
+	return ""
+}
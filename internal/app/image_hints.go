@@ -0,0 +1,146 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// imageExtensions are the file types the built-in image viewer opens.
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif"}
+
+// imageNamePunctuation is trimmed from both ends of the words image hints
+// look at.
+const imageNamePunctuation = "\"'`()[]{}<>,;:"
+
+// imageHint is an image file named on the screen of the focused pane.
+type imageHint struct {
+	Path     string // Absolute path of the image
+	Row, Col int    // Cell the name starts at on the pane's screen
+}
+
+// imageHintTargets returns the image files named on the screen of the
+// focused pane that exist, top to bottom, each once. Relative names are
+// taken from the pane's working directory.
+func (m *OS) imageHintTargets() []imageHint {
+	w := m.GetFocusedWindow()
+	if w == nil || w.ActivePane().Terminal == nil {
+		return nil
+	}
+	pane := w.ActivePane()
+	dir := pane.WorkingDirectory()
+
+	var hints []imageHint
+	for y := range pane.Terminal.Height() {
+		runes, cols, _ := visibleRow(pane, y)
+		for start := 0; start < len(runes); {
+			if unicode.IsSpace(runes[start]) {
+				start++
+				continue
+			}
+			end := start
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			// Names are often quoted or end a sentence, so trim
+			// quotes and brackets around them and a final period
+			at, word := start, runes[start:end]
+			for len(word) > 0 && strings.ContainsRune(imageNamePunctuation, word[0]) {
+				at, word = at+1, word[1:]
+			}
+			for len(word) > 0 && strings.ContainsRune(imageNamePunctuation+".", word[len(word)-1]) {
+				word = word[:len(word)-1]
+			}
+			start = end
+
+			name := string(word)
+			if name == "" || !slices.Contains(imageExtensions, strings.ToLower(filepath.Ext(name))) {
+				continue
+			}
+			path := config.ExpandHome(name)
+			if !filepath.IsAbs(path) {
+				if dir == "" {
+					continue
+				}
+				path = filepath.Join(dir, path)
+			}
+			if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if slices.ContainsFunc(hints, func(h imageHint) bool { return h.Path == path }) {
+				continue
+			}
+			hints = append(hints, imageHint{Path: path, Row: y, Col: cols[at]})
+			if len(hints) == len(windowHintLabels) {
+				return hints
+			}
+		}
+	}
+	return hints
+}
+
+// ToggleImageHints shows or hides a label on every image file named on the
+// screen of the focused pane.
+func (m *OS) ToggleImageHints() {
+	if m.ImageHints != nil {
+		m.ImageHints = nil
+		return
+	}
+	hints := m.imageHintTargets()
+	if len(hints) == 0 {
+		m.ShowNotification("No image files on screen", "info", config.NotificationDuration)
+		return
+	}
+	m.ImageHints = hints
+}
+
+// SelectImageHint opens the image labelled key in the image viewer and
+// hides the labels. It reports whether an image had that label.
+func (m *OS) SelectImageHint(key string) bool {
+	hints := m.ImageHints
+	m.ImageHints = nil
+	if len(key) != 1 {
+		return false
+	}
+	n := strings.Index(windowHintLabels, key)
+	if n < 0 || n >= len(hints) {
+		return false
+	}
+	if _, err := m.OpenImage(hints[n].Path); err != nil {
+		m.ShowNotification(err.Error(), "error", config.NotificationDuration)
+		return false
+	}
+	return true
+}
+
+// renderImageHints renders a label over the start of every image file name
+// that can be opened.
+func (m *OS) renderImageHints() []*lipgloss.Layer {
+	w := m.GetFocusedWindow()
+	if w == nil {
+		return nil
+	}
+	rect := w.ActivePaneRect()
+	zoom := 1
+	if w.Zoomed {
+		zoom = 2
+	}
+	hintStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#000000")).
+		Background(lipgloss.Color("11"))
+
+	var layers []*lipgloss.Layer
+	for n, hint := range m.ImageHints {
+		x := m.ScreenX(w.X + 1 + rect.X + hint.Col*zoom)
+		y := w.Y + 1 + rect.Y + hint.Row*zoom
+		layers = append(layers, lipgloss.NewLayer(hintStyle.Render(string(windowHintLabels[n]))).
+			X(x).Y(y).Z(config.ZIndexLogs).ID("image-hint-"+hint.Path))
+	}
+	return layers
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestImageHints(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"cat.png", "dog.JPG", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	emu := vt.NewEmulator(40, 5)
+	_, _ = emu.Write([]byte("\x1b]7;file://" + dir + "\x07"))
+	_, _ = emu.Write([]byte("notes.txt cat.png gone.png\r\nsaved 'dog.JPG'. cat.png\r\n"))
+
+	m := NewOS(OSOptions{})
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{{ID: "window-one-0000", Terminal: emu, Width: 42, Height: 7, Workspace: 1}}
+	m.FocusedWindow = 0
+
+	// Only existing images are labelled, once each, where their names start
+	m.ToggleImageHints()
+	want := []imageHint{
+		{Path: filepath.Join(dir, "cat.png"), Row: 0, Col: 10},
+		{Path: filepath.Join(dir, "dog.JPG"), Row: 1, Col: 7},
+	}
+	if len(m.ImageHints) != len(want) {
+		t.Fatalf("ImageHints = %+v, want %+v", m.ImageHints, want)
+	}
+	for i := range want {
+		if m.ImageHints[i] != want[i] {
+			t.Errorf("ImageHints[%d] = %+v, want %+v", i, m.ImageHints[i], want[i])
+		}
+	}
+	if layers := m.renderImageHints(); len(layers) != 2 {
+		t.Errorf("expected 2 labels, got %d", len(layers))
+	}
+
+	if m.SelectImageHint("3") || m.ImageHints != nil || len(m.Windows) != 1 {
+		t.Error("expected an unused label to hide the labels without opening a window")
+	}
+}
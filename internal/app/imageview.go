@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Gaurav-Gosain/tuios/internal/imageview"
+)
+
+// OpenImage opens a new window showing the image at path with the built-in
+// viewer. Images are drawn with Kitty graphics when the host terminal
+// supports them and with colored half blocks otherwise. The viewer is the
+// window's command, so the window closes when it quits. It returns the ID of
+// the new window.
+func (m *OS) OpenImage(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open image: %w", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("failed to open image: %w", err)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate tuios executable: %w", err)
+	}
+
+	kitty := m.KittyPassthrough != nil && m.KittyPassthrough.IsEnabled()
+	before := len(m.Windows)
+	m.AddCommandWindow("", imageViewerArgs(exe, path, kitty), filepath.Dir(path))
+	if len(m.Windows) == before {
+		return "", fmt.Errorf("failed to start image viewer")
+	}
+	window := m.Windows[len(m.Windows)-1]
+	window.CustomName = filepath.Base(path)
+	m.MarkAllDirty()
+	return window.ID, nil
+}
+
+// imageViewerArgs returns the command line that runs the viewer on path.
+func imageViewerArgs(exe, path string, kitty bool) []string {
+	protocol := imageview.ProtocolBlocks
+	if kitty {
+		protocol = imageview.ProtocolKitty
+	}
+	args := []string{exe, "view-image", "--protocol", protocol}
+	caps := GetHostCapabilities()
+	if caps.CellWidth > 0 && caps.CellHeight > 0 {
+		args = append(args, "--cell-size", fmt.Sprintf("%dx%d", caps.CellWidth, caps.CellHeight))
+	}
+	return append(args, path)
+}
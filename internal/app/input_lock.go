@@ -0,0 +1,54 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// InputLockedWindow returns the focused window if its input is locked, or
+// nil.
+func (m *OS) InputLockedWindow() *terminal.Window {
+	if w := m.GetFocusedWindow(); w != nil && w.InputLocked {
+		return w
+	}
+	return nil
+}
+
+// UnlockInputHint returns the keys that unlock a window's input, for display.
+func (m *OS) UnlockInputHint() string {
+	if m.KeybindRegistry != nil {
+		if keys := m.KeybindRegistry.GetKeysForDisplay("terminal_unlock_input"); keys != "" {
+			return keys
+		}
+	}
+	return "ctrl+alt+u"
+}
+
+// IsUnlockInputKey reports whether key is bound to terminal_unlock_input.
+func (m *OS) IsUnlockInputKey(key string) bool {
+	if m.KeybindRegistry == nil {
+		return strings.EqualFold(key, "ctrl+alt+u")
+	}
+	return m.KeybindRegistry.GetTerminalModeAction(key) == "terminal_unlock_input"
+}
+
+// ToggleInputLock locks or unlocks the focused window's input. While a
+// locked window is focused every key is ignored except the unlock chord, so
+// stray keystrokes cannot reach the program running in it. The mouse still
+// works, so other windows can be focused and used.
+func (m *OS) ToggleInputLock() {
+	w := m.GetFocusedWindow()
+	if w == nil {
+		m.ShowNotification("No window to lock", "warning", config.NotificationDuration)
+		return
+	}
+	w.InputLocked = !w.InputLocked
+	w.InvalidateCache()
+	if w.InputLocked {
+		m.ShowNotification("Input locked: "+m.UnlockInputHint()+" to unlock", "warning", config.NotificationDuration*2)
+	} else {
+		m.ShowNotification("Input unlocked", "info", config.NotificationDuration)
+	}
+}
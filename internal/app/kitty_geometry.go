@@ -101,3 +101,113 @@ func TransformPlacements(
 
 	return result
 }
+
+// cellRect is a rectangle in host terminal cells.
+type cellRect struct {
+	X, Y, W, H int
+}
+
+func (r cellRect) empty() bool {
+	return r.W <= 0 || r.H <= 0
+}
+
+func (r cellRect) intersect(o cellRect) cellRect {
+	x, y := max(r.X, o.X), max(r.Y, o.Y)
+	right, bottom := min(r.X+r.W, o.X+o.W), min(r.Y+r.H, o.Y+o.H)
+	return cellRect{X: x, Y: y, W: right - x, H: bottom - y}
+}
+
+// subtract returns the largest part of r that does not overlap o. Images can
+// only be drawn as rectangles, so a window covering the middle of an image
+// hides everything on the smaller side of it.
+func (r cellRect) subtract(o cellRect) cellRect {
+	in := r.intersect(o)
+	if in.empty() {
+		return r
+	}
+	candidates := []cellRect{
+		{X: r.X, Y: r.Y, W: r.W, H: in.Y - r.Y},
+		{X: r.X, Y: in.Y + in.H, W: r.W, H: r.Y + r.H - in.Y - in.H},
+		{X: r.X, Y: r.Y, W: in.X - r.X, H: r.H},
+		{X: in.X + in.W, Y: r.Y, W: r.X + r.W - in.X - in.W, H: r.H},
+	}
+	best := cellRect{}
+	for _, c := range candidates {
+		if !c.empty() && c.W*c.H > best.W*best.H {
+			best = c
+		}
+	}
+	return best
+}
+
+// clippedPlacement is the part of a passthrough placement that is drawn on
+// the host terminal.
+type clippedPlacement struct {
+	Visible  cellRect // Host cells the image is drawn in
+	Cols     int      // Width of the whole image after scaling
+	Rows     int      // Height of the whole image after scaling
+	ClipTop  int      // Rows of the scaled image cut off above Visible
+	ClipLeft int      // Columns of the scaled image cut off left of Visible
+	Scale    float64  // Displayed size relative to the placed size
+}
+
+// clipPlacement fits a placement to its window. Images wider than the window
+// are scaled down to its width, and the result is clipped to the window's
+// viewport, the screen bounds and any higher windows in occluders. It reports
+// false when no part of the image is left visible.
+func clipPlacement(p *PassthroughPlacement, info *WindowPositionInfo, occluders []cellRect) (clippedPlacement, bool) {
+	return clipImage(p.GuestX, p.AbsoluteLine, p.Cols, p.Rows, info, occluders)
+}
+
+// clipImage is clipPlacement for an image cols x rows guest cells large at
+// guest column guestX and scrollback line absLine. In a zoomed window every
+// guest cell covers info.Zoom host cells each way, and the image is scaled
+// with it.
+func clipImage(guestX, absLine, cols, rows int, info *WindowPositionInfo, occluders []cellRect) (clippedPlacement, bool) {
+	viewportWidth := info.Width - 2
+	viewportHeight := info.Height - 2
+	if viewportWidth <= 0 || viewportHeight <= 0 || cols <= 0 {
+		return clippedPlacement{}, false
+	}
+
+	zoom := max(info.Zoom, 1)
+	guestX *= zoom
+	cols, rows = cols*zoom, max(rows, 1)*zoom
+	scale := float64(zoom)
+	if available := viewportWidth - guestX; cols > available {
+		if available <= 0 {
+			return clippedPlacement{}, false
+		}
+		scale *= float64(available) / float64(cols)
+		rows = max(int(float64(rows)*float64(available)/float64(cols)+0.5), 1)
+		cols = available
+	}
+
+	originX := info.WindowX + info.ContentOffsetX
+	originY := info.WindowY + info.ContentOffsetY
+	relativeY := (absLine - (info.ScrollbackLen - info.ScrollOffset)) * zoom
+	image := cellRect{X: originX + guestX, Y: originY + relativeY, W: cols, H: rows}
+
+	visible := image.intersect(cellRect{X: originX, Y: originY, W: viewportWidth, H: viewportHeight})
+	if info.BoundsWidth > 0 && info.BoundsHeight > 0 {
+		visible = visible.intersect(cellRect{X: info.BoundsX, Y: info.BoundsY, W: info.BoundsWidth, H: info.BoundsHeight})
+	}
+	for _, o := range occluders {
+		if visible.empty() {
+			break
+		}
+		visible = visible.subtract(o)
+	}
+	if visible.empty() {
+		return clippedPlacement{}, false
+	}
+
+	return clippedPlacement{
+		Visible:  visible,
+		Cols:     cols,
+		Rows:     rows,
+		ClipTop:  visible.Y - image.Y,
+		ClipLeft: visible.X - image.X,
+		Scale:    scale,
+	}, true
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestClipPlacement(t *testing.T) {
+	window := func(x, y, w, h int) *WindowPositionInfo {
+		return &WindowPositionInfo{
+			WindowX: x, WindowY: y, Width: w, Height: h,
+			ContentOffsetX: 1, ContentOffsetY: 1, Visible: true,
+			BoundsWidth: 80, BoundsHeight: 23,
+		}
+	}
+	image := &PassthroughPlacement{GuestX: 2, Cols: 10, Rows: 4}
+
+	tests := []struct {
+		name      string
+		info      *WindowPositionInfo
+		occluders []cellRect
+		want      clippedPlacement
+		visible   bool
+	}{
+		{
+			name:    "fully visible",
+			info:    window(10, 5, 40, 20),
+			want:    clippedPlacement{Visible: cellRect{X: 13, Y: 6, W: 10, H: 4}, Cols: 10, Rows: 4, Scale: 1},
+			visible: true,
+		},
+		{
+			name:    "scaled to a narrow window",
+			info:    window(10, 5, 9, 20),
+			want:    clippedPlacement{Visible: cellRect{X: 13, Y: 6, W: 5, H: 2}, Cols: 5, Rows: 2, Scale: 0.5},
+			visible: true,
+		},
+		{
+			name:    "clipped at the left screen edge",
+			info:    window(-6, 5, 40, 20),
+			want:    clippedPlacement{Visible: cellRect{X: 0, Y: 6, W: 7, H: 4}, Cols: 10, Rows: 4, ClipLeft: 3, Scale: 1},
+			visible: true,
+		},
+		{
+			name:    "clipped above the dock",
+			info:    window(10, 20, 40, 20),
+			want:    clippedPlacement{Visible: cellRect{X: 13, Y: 21, W: 10, H: 2}, Cols: 10, Rows: 4, Scale: 1},
+			visible: true,
+		},
+		{
+			name:      "clipped by a higher window",
+			info:      window(10, 5, 40, 20),
+			occluders: []cellRect{{X: 18, Y: 0, W: 20, H: 20}},
+			want:      clippedPlacement{Visible: cellRect{X: 13, Y: 6, W: 5, H: 4}, Cols: 10, Rows: 4, Scale: 1},
+			visible:   true,
+		},
+		{
+			name:    "doubled in a zoomed window",
+			info:    &WindowPositionInfo{WindowX: 10, WindowY: 5, Width: 40, Height: 20, ContentOffsetX: 1, ContentOffsetY: 1, Visible: true, Zoom: 2},
+			want:    clippedPlacement{Visible: cellRect{X: 15, Y: 6, W: 20, H: 8}, Cols: 20, Rows: 8, Scale: 2},
+			visible: true,
+		},
+		{
+			name:      "covered by a higher window",
+			info:      window(10, 5, 40, 20),
+			occluders: []cellRect{{X: 0, Y: 0, W: 40, H: 20}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, visible := clipPlacement(image, tt.info, tt.occluders)
+			if visible != tt.visible {
+				t.Fatalf("visible = %v, want %v", visible, tt.visible)
+			}
+			if visible && got != tt.want {
+				t.Errorf("clipPlacement() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSixelForClip(t *testing.T) {
+	// A 4x12 pixel image: two bands of color 1.
+	raw := []byte("0;1;0q#1;2;100;0;0#1~~~~-~~~~")
+	p := &SixelPassthroughPlacement{Width: 4, Height: 12, RawSequence: raw}
+
+	whole := clippedPlacement{Visible: cellRect{W: 2, H: 2}, Cols: 2, Rows: 2, Scale: 1}
+	if got := sixelForClip(p, whole, 2, 6); string(got) != string(raw) {
+		t.Errorf("unclipped image was re-encoded: %q", got)
+	}
+
+	// Scaled to half size and cut to its top cell row.
+	clip := clippedPlacement{Visible: cellRect{W: 1, H: 1}, Cols: 1, Rows: 1, Scale: 0.5}
+	img := vt.DecodeSixel(vt.ParseSixelCommand(sixelForClip(p, clip, 2, 3)))
+	if img == nil || img.Width != 2 || img.Height != 3 {
+		t.Fatalf("clipped image = %+v, want 2x3 pixels", img)
+	}
+}
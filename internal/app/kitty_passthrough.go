@@ -5,23 +5,16 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
-	"time"
 
+	"github.com/Gaurav-Gosain/tuios/internal/debuglog"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 	"github.com/Gaurav-Gosain/tuios/internal/vt"
 )
 
 func kittyPassthroughLog(format string, args ...any) {
-	if os.Getenv("TUIOS_DEBUG_INTERNAL") != "1" {
-		return
-	}
-	f, err := os.OpenFile("/tmp/tuios-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return
-	}
-	defer func() { _ = f.Close() }()
-	_, _ = fmt.Fprintf(f, "[%s] KITTY-PASSTHROUGH: %s\n", time.Now().Format("15:04:05.000"), fmt.Sprintf(format, args...))
+	debuglog.Logf(debuglog.Debug, "KITTY-PASSTHROUGH", format, args...)
 }
 
 // isKittyResponse checks if data looks like a kitty graphics protocol response
@@ -116,8 +109,9 @@ type PassthroughPlacement struct {
 	ClipBottom      int
 	ClipLeft        int
 	ClipRight       int
-	MaxShowable     int // Max rows that can be shown in current viewport
-	MaxShowableCols int // Max cols that can be shown in current viewport
+	MaxShowable     int     // Max rows that can be shown in current viewport
+	MaxShowableCols int     // Max cols that can be shown in current viewport
+	Scale           float64 // Display size relative to Cols/Rows when the window is narrower than the image
 }
 
 type WindowPositionInfo struct {
@@ -128,11 +122,19 @@ type WindowPositionInfo struct {
 	Width              int
 	Height             int
 	Visible            bool
-	ScrollbackLen      int  // Total scrollback lines
+	ScrollbackLen      int  // Lines ever pushed to scrollback (vt.Emulator.ScrollbackPushed)
 	ScrollOffset       int  // Current scroll offset (0 = at bottom)
 	IsBeingManipulated bool // True when window is being dragged/resized
 	WindowZ            int  // Window z-index for occlusion detection
 	IsAltScreen        bool // True when alternate screen is active (vim, less, etc.)
+	Zoom               int  // Host cells per guest cell in each direction (2 when zoomed)
+
+	// Screen area images may be drawn in (excludes the dock). A zero size
+	// leaves images unbounded.
+	BoundsX      int
+	BoundsY      int
+	BoundsWidth  int
+	BoundsHeight int
 }
 
 func NewKittyPassthrough() *KittyPassthrough {
@@ -316,7 +318,7 @@ func (kp *KittyPassthrough) ForwardCommand(
 
 	case vt.KittyActionDelete:
 		kittyPassthroughLog("ForwardCommand: handling DELETE, d=%c, imageID=%d", cmd.Delete, cmd.ImageID)
-		kp.forwardDelete(cmd, windowID)
+		kp.forwardDelete(cmd, windowID, scrollbackLen, isAltScreen)
 
 	default:
 		kittyPassthroughLog("ForwardCommand: UNKNOWN action %c", cmd.Action)
@@ -425,11 +427,6 @@ func (kp *KittyPassthrough) forwardDirectTransmit(cmd *vt.KittyCommand, windowID
 		kittyPassthroughLog("forwardDirectTransmit: virtual placement detected, converting to regular deferred placement")
 	}
 
-	// Clear existing placements for this window before placing new image
-	if andPlace {
-		kp.deleteAllWindowPlacements(windowID, false)
-	}
-
 	// Allocate a unique host ID
 	hostID := kp.allocateHostID()
 	if kp.imageIDMap[windowID] == nil {
@@ -544,6 +541,7 @@ func (kp *KittyPassthrough) forwardDirectTransmit(cmd *vt.KittyCommand, windowID
 
 	// Store placement for tracking (placement will be done by RefreshAllPlacements)
 	if andPlace {
+		kp.deleteOverlappingPlacements(windowID, pending.CursorX, pending.ScrollbackLen+pending.CursorY, imgCols, imgRows, pending.IsAltScreen)
 		if kp.placements[windowID] == nil {
 			kp.placements[windowID] = make(map[uint32]*PassthroughPlacement)
 		}
@@ -555,7 +553,7 @@ func (kp *KittyPassthrough) forwardDirectTransmit(cmd *vt.KittyCommand, windowID
 			AbsoluteLine:      pending.ScrollbackLen + pending.CursorY,
 			HostX:             hostX,
 			HostY:             hostY,
-			Cols:              displayCols,
+			Cols:              imgCols,
 			Rows:              imgRows,
 			DisplayRows:       displayRows,
 			SourceX:           pending.SourceX,
@@ -600,11 +598,6 @@ func (kp *KittyPassthrough) forwardFileTransmit(cmd *vt.KittyCommand, windowID s
 
 	kittyPassthroughLog("forwardFileTransmit: read %d bytes from %s, andPlace=%v", len(data), filePath, andPlace)
 
-	// Clear existing placements for this window before placing new image
-	if andPlace {
-		kp.deleteAllWindowPlacements(windowID, false)
-	}
-
 	if cmd.Medium == vt.KittyMediumSharedMemory || cmd.Medium == vt.KittyMediumTempFile {
 		_ = os.Remove(filePath)
 	}
@@ -722,6 +715,7 @@ func (kp *KittyPassthrough) forwardFileTransmit(cmd *vt.KittyCommand, windowID s
 	}
 
 	// Store placement using hostID as key (cmd.ImageID is often 0 for new images)
+	kp.deleteOverlappingPlacements(windowID, cursorX, scrollbackLen+cursorY, imgCols, imgRows, isAltScreen)
 	if kp.placements[windowID] == nil {
 		kp.placements[windowID] = make(map[uint32]*PassthroughPlacement)
 	}
@@ -733,7 +727,7 @@ func (kp *KittyPassthrough) forwardFileTransmit(cmd *vt.KittyCommand, windowID s
 		AbsoluteLine:      scrollbackLen + cursorY,
 		HostX:             hostX,
 		HostY:             hostY,
-		Cols:              displayCols,
+		Cols:              imgCols,     // Original image cols (scaled to fit on refresh)
 		Rows:              imgRows,     // Original image rows (for scroll clipping)
 		DisplayRows:       displayRows, // Capped rows for initial display
 		SourceX:           cmd.SourceX,
@@ -841,7 +835,7 @@ func (kp *KittyPassthrough) forwardPlace(
 		AbsoluteLine: scrollbackLen + cursorY,
 		HostX:        hostX,
 		HostY:        hostY,
-		Cols:         displayCols,
+		Cols:         imgCols,     // Original image columns
 		Rows:         imgRows,     // Original image rows
 		DisplayRows:  displayRows, // Capped for initial display
 		SourceX:      cmd.SourceX,
@@ -868,12 +862,62 @@ func (kp *KittyPassthrough) deleteAllWindowPlacements(windowID string, clearImag
 	}
 }
 
-func (kp *KittyPassthrough) forwardDelete(cmd *vt.KittyCommand, windowID string) {
+// deleteScreenPlacements removes the window's placements that extend onto the
+// visible screen starting at absolute line screenTop, on the given screen.
+// Placements that have scrolled entirely into the scrollback are kept so they
+// show again when the window is scrolled up.
+func (kp *KittyPassthrough) deleteScreenPlacements(windowID string, screenTop int, altScreen bool) {
+	for id, p := range kp.placements[windowID] {
+		if p.PlacedOnAltScreen != altScreen || p.AbsoluteLine+max(p.Rows, 1) <= screenTop {
+			continue
+		}
+		if !p.Hidden {
+			kp.deleteOnePlacement(p)
+		}
+		delete(kp.placements[windowID], id)
+	}
+}
+
+// deleteOverlappingPlacements removes the window's placements that cover any
+// cell of a new cols x rows image at guestX on absolute line absLine. A new
+// image replaces the ones under it, while earlier images elsewhere (such as
+// ones scrolled up into the scrollback) are kept.
+func (kp *KittyPassthrough) deleteOverlappingPlacements(windowID string, guestX, absLine, cols, rows int, altScreen bool) {
+	image := cellRect{X: guestX, Y: absLine, W: max(cols, 1), H: max(rows, 1)}
+	for id, p := range kp.placements[windowID] {
+		if p.PlacedOnAltScreen != altScreen {
+			continue
+		}
+		if image.intersect(cellRect{X: p.GuestX, Y: p.AbsoluteLine, W: max(p.Cols, 1), H: max(p.Rows, 1)}).empty() {
+			continue
+		}
+		if !p.Hidden {
+			kp.deleteOnePlacement(p)
+		}
+		delete(kp.placements[windowID], id)
+	}
+}
+
+// ClearScreen removes a window's placements on the visible screen, such as
+// when the screen is erased. See deleteScreenPlacements.
+func (kp *KittyPassthrough) ClearScreen(windowID string, screenTop int, altScreen bool) {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	if !kp.enabled {
+		return
+	}
+	kittyPassthroughLog("ClearScreen: windowID=%s, screenTop=%d, altScreen=%v", windowID[:min(8, len(windowID))], screenTop, altScreen)
+	kp.deleteScreenPlacements(windowID, screenTop, altScreen)
+}
+
+func (kp *KittyPassthrough) forwardDelete(cmd *vt.KittyCommand, windowID string, scrollbackLen int, isAltScreen bool) {
 	kittyPassthroughLog("forwardDelete: delete=%c, imageID=%d, windowID=%s", cmd.Delete, cmd.ImageID, windowID[:8])
 
 	switch cmd.Delete {
 	case vt.KittyDeleteAll, 0:
-		kp.deleteAllWindowPlacements(windowID, false)
+		// Only the placements on screen; images in the scrollback stay
+		kp.deleteScreenPlacements(windowID, scrollbackLen, isAltScreen)
 
 	case vt.KittyDeleteByID:
 		if windowMap := kp.imageIDMap[windowID]; windowMap != nil {
@@ -990,28 +1034,22 @@ func (kp *KittyPassthrough) ClearWindow(windowID string) {
 	kp.placements[windowID] = nil
 }
 
-// rectsOverlap checks if two rectangles overlap
-func rectsOverlap(x1, y1, w1, h1, x2, y2, w2, h2 int) bool {
-	return x1 < x2+w2 && x1+w1 > x2 && y1 < y2+h2 && y1+h1 > y2
-}
-
-// isOccludedByHigherWindow checks if an image region is fully occluded by a window with higher z-index
-func (kp *KittyPassthrough) isOccludedByHigherWindow(
-	screenX, screenY, width, height, windowZ int,
-	allWindows map[string]*WindowPositionInfo,
-	excludeWindowID string,
-) bool {
+// higherWindowRects returns the screen rectangles of the windows stacked above
+// windowZ, lowest first, so images can be clipped where they would cover them.
+func higherWindowRects(windowZ int, allWindows map[string]*WindowPositionInfo, excludeWindowID string) []cellRect {
+	var above []*WindowPositionInfo
 	for id, info := range allWindows {
-		if id == excludeWindowID || info.WindowZ <= windowZ {
-			continue
-		}
-		// Check if higher-z window overlaps the image region
-		if rectsOverlap(screenX, screenY, width, height,
-			info.WindowX, info.WindowY, info.Width, info.Height) {
-			return true
+		if id != excludeWindowID && info.WindowZ > windowZ {
+			above = append(above, info)
 		}
 	}
-	return false
+	sort.Slice(above, func(i, j int) bool { return above[i].WindowZ < above[j].WindowZ })
+
+	rects := make([]cellRect, len(above))
+	for i, info := range above {
+		rects[i] = cellRect{X: info.WindowX, Y: info.WindowY, W: info.Width, H: info.Height}
+	}
+	return rects
 }
 
 func (kp *KittyPassthrough) RefreshAllPlacements(getAllWindows func() map[string]*WindowPositionInfo) {
@@ -1067,9 +1105,9 @@ func (kp *KittyPassthrough) RefreshAllPlacements(getAllWindows func() map[string
 		kittyPassthroughLog("RefreshAllPlacements: windowID=%s, IsAltScreen=%v, visible=%v", windowID[:8], info.IsAltScreen, info.Visible)
 
 		// Calculate viewport dimensions (accounting for window borders)
-		viewportTop := info.ScrollbackLen - info.ScrollOffset
 		viewportHeight := info.Height - 2
 		viewportWidth := info.Width - 2
+		occluders := higherWindowRects(info.WindowZ, allWindows, windowID)
 
 		// Collect IDs to delete (for altscreen cleanup)
 		var idsToDelete []uint32
@@ -1095,81 +1133,13 @@ func (kp *KittyPassthrough) RefreshAllPlacements(getAllWindows func() map[string
 				continue
 			}
 
-			// Calculate new position (where top-left of image would be)
-			relativeY := p.AbsoluteLine - viewportTop
-
-			// Calculate where the FULL image would end (for visibility check)
-			fullImageBottom := relativeY + p.Rows
-			fullImageRight := p.GuestX + p.Cols
-
-			// Check if ANY part of the image is visible in the viewport
-			// Image is visible if: top < viewportHeight AND bottom > 0 AND left < viewportWidth AND right > 0
-			anyPartVisible := info.Visible &&
-				relativeY < viewportHeight && fullImageBottom > 0 &&
-				p.GuestX < viewportWidth && fullImageRight > 0
-
-			// Calculate vertical clipping based on FULL image dimensions
-			clipTop := 0
-			clipBottom := 0
-			if anyPartVisible {
-				if relativeY < 0 {
-					clipTop = -relativeY // Clip rows above viewport
-				}
-				if fullImageBottom > viewportHeight {
-					clipBottom = fullImageBottom - viewportHeight // Clip rows below viewport
-				}
-			}
-
-			// For horizontal: if image is wider than viewport, hide it (simpler approach for now)
-			// TODO: implement proper horizontal clipping later
-			if fullImageRight > viewportWidth {
-				anyPartVisible = false
-			}
-
-			// Calculate how many rows we CAN show after clipping
-			maxShowableRows := min(p.Rows-clipTop-clipBottom, viewportHeight)
-			if maxShowableRows <= 0 {
-				maxShowableRows = 1
-			}
-
-			// Calculate actual host position (after clipping adjustment)
-			actualRelativeY := relativeY
-			if clipTop > 0 {
-				actualRelativeY = 0 // Start at top of viewport
-			}
-			newHostX := info.WindowX + info.ContentOffsetX + p.GuestX
-			newHostY := info.WindowY + info.ContentOffsetY + actualRelativeY
-
-			// Calculate image dimensions in cells for occlusion check (same units as window dimensions)
-			imageCellWidth := p.Cols
-			imageCellHeight := maxShowableRows
-
-			// Check if image is occluded by a higher-z window
-			if anyPartVisible && kp.isOccludedByHigherWindow(
-				newHostX, newHostY, imageCellWidth, imageCellHeight,
-				info.WindowZ, allWindows, windowID,
-			) {
-				kittyPassthroughLog("RefreshPlacement: image occluded by higher-z window, hiding")
-				anyPartVisible = false
-			}
-
-			// Hide images when host position would be negative or outside screen bounds
-			// This prevents issues when windows are partially off-screen
-			if anyPartVisible && (newHostX < 0 || newHostY < 0) {
-				kittyPassthroughLog("RefreshPlacement: image at negative position (%d,%d), hiding", newHostX, newHostY)
-				anyPartVisible = false
-			}
+			clip, visible := clipPlacement(p, info, occluders)
+			visible = visible && info.Visible
 
-			// Also hide if the window itself is partially off the left or top edge
-			if anyPartVisible && (info.WindowX < 0 || info.WindowY < 0) {
-				kittyPassthroughLog("RefreshPlacement: window at negative position (%d,%d), hiding image", info.WindowX, info.WindowY)
-				anyPartVisible = false
-			}
+			kittyPassthroughLog("RefreshPlacement: absLine=%d, origRows=%d, origCols=%d, vpH=%d, vpW=%d, clip=%+v, visible=%v",
+				p.AbsoluteLine, p.Rows, p.Cols, viewportHeight, viewportWidth, clip, visible)
 
-			kittyPassthroughLog("RefreshPlacement: relY=%d, origRows=%d, origCols=%d, vpH=%d, vpW=%d, clipTop=%d, clipBot=%d, maxRows=%d, visible=%v",
-				relativeY, p.Rows, p.Cols, viewportHeight, viewportWidth, clipTop, clipBottom, maxShowableRows, anyPartVisible)
-
-			if !anyPartVisible {
+			if !visible {
 				// Completely hidden
 				if !p.Hidden {
 					kp.deleteOnePlacement(p)
@@ -1181,11 +1151,15 @@ func (kp *KittyPassthrough) RefreshAllPlacements(getAllWindows func() map[string
 				if !p.Hidden {
 					kp.deleteOnePlacement(p)
 				}
-				p.HostX = newHostX
-				p.HostY = newHostY
-				p.ClipTop = clipTop
-				p.ClipBottom = clipBottom
-				p.MaxShowable = maxShowableRows
+				p.HostX = clip.Visible.X
+				p.HostY = clip.Visible.Y
+				p.ClipTop = clip.ClipTop
+				p.ClipBottom = clip.Rows - clip.ClipTop - clip.Visible.H
+				p.ClipLeft = clip.ClipLeft
+				p.ClipRight = clip.Cols - clip.ClipLeft - clip.Visible.W
+				p.MaxShowable = clip.Visible.H
+				p.MaxShowableCols = clip.Visible.W
+				p.Scale = clip.Scale
 				kp.placeOne(p)
 				p.Hidden = false
 			}
@@ -1223,10 +1197,18 @@ func (kp *KittyPassthrough) deleteOnePlacement(p *PassthroughPlacement) {
 
 func (kp *KittyPassthrough) placeOne(p *PassthroughPlacement) {
 	caps := GetHostCapabilities()
+	cellWidth := caps.CellWidth
+	if cellWidth <= 0 {
+		cellWidth = 9 // Fallback
+	}
 	cellHeight := caps.CellHeight
 	if cellHeight <= 0 {
 		cellHeight = 20 // Fallback
 	}
+	scale := p.Scale
+	if scale <= 0 {
+		scale = 1
+	}
 
 	var buf bytes.Buffer
 	buf.WriteString("\x1b7") // Save cursor position
@@ -1237,8 +1219,7 @@ func (kp *KittyPassthrough) placeOne(p *PassthroughPlacement) {
 		fmt.Fprintf(&buf, ",p=%d", p.PlacementID)
 	}
 
-	// MaxShowable is already calculated as: p.Rows - clipTop - clipBottom
-	// So it already accounts for clipping and is the number of rows to display
+	// MaxShowable/MaxShowableCols are the rows and cols left after clipping
 	visibleRows := p.MaxShowable
 	if visibleRows <= 0 {
 		visibleRows = p.DisplayRows
@@ -1249,39 +1230,50 @@ func (kp *KittyPassthrough) placeOne(p *PassthroughPlacement) {
 	if visibleRows <= 0 {
 		visibleRows = 1 // Minimum 1 row to avoid issues
 	}
+	visibleCols := p.MaxShowableCols
+	if visibleCols <= 0 {
+		visibleCols = p.Cols
+	}
 
-	kittyPassthroughLog("placeOne: hostID=%d, pos=(%d,%d), origRows=%d, origCols=%d, clipTop=%d, clipBot=%d, visibleRows=%d",
-		p.HostImageID, p.HostX, p.HostY, p.Rows, p.Cols, p.ClipTop, p.ClipBottom, visibleRows)
+	kittyPassthroughLog("placeOne: hostID=%d, pos=(%d,%d), origRows=%d, origCols=%d, clip=(t%d,b%d,l%d,r%d), visible=%dx%d, scale=%.2f",
+		p.HostImageID, p.HostX, p.HostY, p.Rows, p.Cols, p.ClipTop, p.ClipBottom, p.ClipLeft, p.ClipRight, visibleCols, visibleRows, scale)
 
-	// Use original cols (no horizontal clipping for now)
-	if p.Cols > 0 {
-		fmt.Fprintf(&buf, ",c=%d", p.Cols)
+	if visibleCols > 0 {
+		fmt.Fprintf(&buf, ",c=%d", visibleCols)
 	}
 	if visibleRows > 0 {
 		fmt.Fprintf(&buf, ",r=%d", visibleRows)
 	}
 
-	// Calculate source Y offset (in pixels) - includes original SourceY plus clipping
-	sourceY := p.SourceY
-	if p.ClipTop > 0 {
-		sourceY += p.ClipTop * cellHeight
-	}
+	// Source offsets (in pixels) include the original source rect plus clipping.
+	// Clipped cells are converted back to source pixels using the scale, so a
+	// scaled image is clipped in the same proportions it is displayed in.
+	sourceX := p.SourceX + int(float64(p.ClipLeft*cellWidth)/scale)
+	sourceY := p.SourceY + int(float64(p.ClipTop*cellHeight)/scale)
 
-	// Calculate source height (in pixels) for proper vertical clipping
+	// Source height (in pixels) for proper vertical clipping.
 	// This is critical: without setting h, Kitty will SCALE the image to fit r rows
 	// With h set, Kitty will CLIP to show only h pixels of height
-	sourceHeight := visibleRows * cellHeight
+	sourceHeight := int(float64(visibleRows*cellHeight) / scale)
+
+	// Source width is only needed when clipping horizontally; otherwise the
+	// full width is scaled into c cols.
+	sourceWidth := p.SourceWidth
+	if p.ClipLeft > 0 || p.ClipRight > 0 {
+		sourceWidth = int(float64(visibleCols*cellWidth) / scale)
+		if p.SourceWidth > 0 {
+			sourceWidth = min(sourceWidth, p.SourceWidth-(sourceX-p.SourceX))
+		}
+	}
 
-	// Include source clipping parameters
-	if p.SourceX > 0 {
-		fmt.Fprintf(&buf, ",x=%d", p.SourceX)
+	if sourceX > 0 {
+		fmt.Fprintf(&buf, ",x=%d", sourceX)
 	}
 	if sourceY > 0 {
 		fmt.Fprintf(&buf, ",y=%d", sourceY)
 	}
-	// Use original source width if specified (no horizontal clipping for now)
-	if p.SourceWidth > 0 {
-		fmt.Fprintf(&buf, ",w=%d", p.SourceWidth)
+	if sourceWidth > 0 {
+		fmt.Fprintf(&buf, ",w=%d", sourceWidth)
 	}
 	// Always set h for vertical clipping
 	if sourceHeight > 0 {
@@ -1302,6 +1294,51 @@ func (kp *KittyPassthrough) placeOne(p *PassthroughPlacement) {
 	kp.pendingOutput = append(kp.pendingOutput, buf.Bytes()...)
 }
 
+// windowPositionInfo describes where a window's graphics passthrough images
+// are drawn on the host terminal.
+func (m *OS) windowPositionInfo(w *terminal.Window) *WindowPositionInfo {
+	scrollbackLen := 0
+	if w.Terminal != nil {
+		scrollbackLen = w.Terminal.ScrollbackPushed()
+	}
+	z := LayerZ(w)
+	zoom := 1
+	if w.Zoomed {
+		zoom = 2
+	}
+	return &WindowPositionInfo{
+		WindowX:            m.ScreenX(w.X),
+		WindowY:            w.Y,
+		ContentOffsetX:     1,
+		ContentOffsetY:     1,
+		Width:              w.Width,
+		Height:             w.Height,
+		Visible:            true,
+		ScrollbackLen:      scrollbackLen,
+		ScrollOffset:       w.ScrollbackOffset,
+		IsBeingManipulated: w.IsBeingManipulated,
+		WindowZ:            z,
+		IsAltScreen:        w.IsAltScreen,
+		Zoom:               zoom,
+		BoundsX:            m.ScreenX(0),
+		BoundsY:            m.GetTopMargin(),
+		BoundsWidth:        m.GetRenderWidth(),
+		BoundsHeight:       m.GetUsableHeight(),
+	}
+}
+
+// passthroughWindows returns the position of every window shown on the
+// current workspace, for placing and occluding passthrough images.
+func (m *OS) passthroughWindows() map[string]*WindowPositionInfo {
+	result := make(map[string]*WindowPositionInfo)
+	for _, w := range m.Windows {
+		if w.Workspace == m.CurrentWorkspace && !w.Minimized {
+			result[w.ID] = m.windowPositionInfo(w)
+		}
+	}
+	return result
+}
+
 func (m *OS) setupKittyPassthrough(window *terminal.Window) {
 	if m.KittyPassthrough == nil || window == nil || window.Terminal == nil {
 		return
@@ -1314,10 +1351,13 @@ func (m *OS) setupKittyPassthrough(window *terminal.Window) {
 	window.Terminal.KittyState().SetClearCallback(func() {
 		kp.ClearWindow(win.ID)
 	})
+	window.Terminal.KittyState().SetScreenClearCallback(func(screenTop int) {
+		kp.ClearScreen(win.ID, screenTop, win.Terminal.IsAltScreen())
+	})
 
 	window.Terminal.SetKittyPassthroughFunc(func(cmd *vt.KittyCommand, rawData []byte) {
 		cursorPos := win.Terminal.CursorPosition()
-		scrollbackLen := win.Terminal.ScrollbackLen()
+		scrollbackLen := win.Terminal.ScrollbackPushed()
 		result := kp.ForwardCommand(
 			cmd, rawData, win.ID,
 			win.X, win.Y,
@@ -0,0 +1,32 @@
+package app
+
+import "testing"
+
+func TestPassthroughKeepsScrollbackPlacements(t *testing.T) {
+	kp := &KittyPassthrough{
+		enabled:    true,
+		placements: map[string]map[uint32]*PassthroughPlacement{},
+	}
+	scrolled := &PassthroughPlacement{HostImageID: 1, AbsoluteLine: 0, Cols: 10, Rows: 5}
+	onScreen := &PassthroughPlacement{HostImageID: 2, AbsoluteLine: 8, Cols: 10, Rows: 5}
+	kp.placements["w"] = map[uint32]*PassthroughPlacement{1: scrolled, 2: onScreen}
+
+	// A new image only replaces the images it covers
+	kp.deleteOverlappingPlacements("w", 0, 12, 10, 5, false)
+	if _, ok := kp.placements["w"][2]; ok {
+		t.Error("expected the image under the new one to be replaced")
+	}
+	if _, ok := kp.placements["w"][1]; !ok {
+		t.Fatal("expected the image in the scrollback to be kept")
+	}
+
+	// Clearing the screen (which starts at line 6) keeps images above it
+	kp.placements["w"][2] = onScreen
+	kp.ClearScreen("w", 6, false)
+	if _, ok := kp.placements["w"][2]; ok {
+		t.Error("expected the image on screen to be cleared")
+	}
+	if _, ok := kp.placements["w"][1]; !ok {
+		t.Error("expected the image in the scrollback to survive clearing the screen")
+	}
+}
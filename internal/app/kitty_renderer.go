@@ -1,25 +1,15 @@
 package app
 
 import (
-	"fmt"
-	"os"
 	"sync"
-	"time"
 
+	"github.com/Gaurav-Gosain/tuios/internal/debuglog"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 	"github.com/Gaurav-Gosain/tuios/internal/vt"
 )
 
 func rendererDebugLog(format string, args ...any) {
-	if os.Getenv("TUIOS_DEBUG_INTERNAL") != "1" {
-		return
-	}
-	f, err := os.OpenFile("/tmp/tuios-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return
-	}
-	defer func() { _ = f.Close() }()
-	_, _ = fmt.Fprintf(f, "[%s] RENDERER: %s\n", time.Now().Format("15:04:05.000"), fmt.Sprintf(format, args...))
+	debuglog.Logf(debuglog.Debug, "RENDERER", format, args...)
 }
 
 type KittyRenderer struct {
@@ -96,7 +86,7 @@ func (r *KittyRenderer) GetRenderOutput(
 			continue
 		}
 
-		scrollbackLen := w.ScrollbackLen()
+		scrollbackLen := w.Terminal.ScrollbackPushed()
 		visible := TransformPlacements(
 			placements,
 			w.X, w.Y,
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// ApplyLayoutFile creates the workspaces and windows described by a layout
+// file and switches to the first workspace in it. It returns the number of
+// windows created.
+func (m *OS) ApplyLayoutFile(lf *config.LayoutFile) int {
+	if lf == nil || len(lf.Workspaces) == 0 {
+		return 0
+	}
+	if lf.Tiling != nil {
+		for _, ws := range lf.Workspaces {
+			m.setWorkspaceAutoTiling(ws.Number, *lf.Tiling)
+		}
+	}
+
+	created := 0
+	for _, ws := range lf.Workspaces {
+		created += m.applyWorkspaceLayout(ws, ws.Number)
+	}
+	m.SwitchToWorkspace(lf.Workspaces[0].Number)
+
+	m.MarkAllDirty()
+	m.SyncStateToDaemon()
+	m.LogInfo("Applied layout file: %d workspaces, %d windows", len(lf.Workspaces), created)
+	return created
+}
+
+// ApplyLayoutToCurrentWorkspace adds the windows of the first workspace in
+// a layout file to the current workspace. It returns the number of windows
+// created.
+func (m *OS) ApplyLayoutToCurrentWorkspace(lf *config.LayoutFile) int {
+	if lf == nil || len(lf.Workspaces) == 0 {
+		return 0
+	}
+	if lf.Tiling != nil {
+		m.AutoTiling = *lf.Tiling
+	}
+
+	created := m.applyWorkspaceLayout(lf.Workspaces[0], m.CurrentWorkspace)
+	m.MarkAllDirty()
+	m.SyncStateToDaemon()
+	return created
+}
+
+// applyWorkspaceLayout creates the windows of one layout workspace on the
+// given workspace. Windows are created floating at their requested geometry
+// and, in tiling mode, the tiling tree is then built from that geometry so
+// the percentages become split ratios.
+func (m *OS) applyWorkspaceLayout(ws config.LayoutWorkspace, workspace int) int {
+	m.markWorkspacePopulated(workspace) // The layout takes the place of its template
+	m.SwitchToWorkspace(workspace)
+	hadWindows := m.GetWorkspaceWindowCount(workspace) > 0
+
+	tiling := m.AutoTiling
+	m.AutoTiling = false
+
+	focusIndex := -1
+	created := 0
+	for _, spec := range ws.Windows {
+		before := len(m.Windows)
+		var argv []string
+		if spec.Command != "" {
+			argv = shellArgv(spec.Command)
+		}
+		m.AddCommandWindow(spec.Name, argv, config.ExpandHome(spec.Cwd))
+		if len(m.Windows) == before {
+			continue
+		}
+		window := m.Windows[len(m.Windows)-1]
+		window.CustomName = spec.Name
+		created++
+
+		if spec.HasGeometry() {
+			m.setLayoutGeometry(window, spec)
+		}
+
+		if focusIndex < 0 || (ws.Focus != "" && spec.Name == ws.Focus) {
+			focusIndex = len(m.Windows) - 1
+		}
+	}
+
+	m.AutoTiling = tiling
+	if tiling && created > 0 {
+		if hadWindows || !layoutHasGeometry(ws) {
+			// Rebuild the default spiral, as when tiling is switched on
+			m.AutoTiling = false
+			m.ToggleAutoTiling()
+		} else {
+			m.BuildBSPTreeFromCurrentLayout()
+		}
+		m.placeBSPLayout()
+	}
+
+	if focusIndex >= 0 {
+		m.FocusWindow(focusIndex)
+	}
+	return created
+}
+
+// setLayoutGeometry positions a window from percentages of the usable screen.
+func (m *OS) setLayoutGeometry(window *terminal.Window, spec config.LayoutWindow) {
+	screenWidth, screenHeight := m.GetRenderWidth(), m.GetUsableHeight()
+	if screenWidth <= 0 || screenHeight <= 0 {
+		return
+	}
+
+	window.X = *spec.X * screenWidth / 100
+	window.Y = m.GetTopMargin() + *spec.Y*screenHeight/100
+	width := max(*spec.Width*screenWidth/100, config.MinWindowWidth)
+	height := max(*spec.Height*screenHeight/100, config.MinWindowHeight)
+	window.Resize(width, height)
+}
+
+// placeBSPLayout moves the windows of the current workspace straight to
+// their tiled positions. Unlike ApplyBSPLayout it does not animate, so the
+// positions survive the workspace switches made while applying a layout.
+func (m *OS) placeBSPLayout() {
+	m.Animations = m.Animations[:0]
+	tree := m.GetOrCreateBSPTree()
+	if tree == nil || tree.IsEmpty() {
+		return
+	}
+
+	for windowIntID, rect := range m.bspLayout(tree) {
+		win := m.getWindowByIntID(windowIntID)
+		if win == nil || win.Workspace != m.CurrentWorkspace || win.Minimized {
+			continue
+		}
+		win.X, win.Y = rect.X, rect.Y
+		win.Resize(rect.W, rect.H)
+	}
+}
+
+// layoutHasGeometry reports whether every window of a workspace has geometry.
+func layoutHasGeometry(ws config.LayoutWorkspace) bool {
+	for _, w := range ws.Windows {
+		if !w.HasGeometry() {
+			return false
+		}
+	}
+	return len(ws.Windows) > 0
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+func TestLayoutWindowsRunTheirCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	t.Setenv("SHELL", "/bin/sh")
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	dir := t.TempDir()
+	lf := &config.LayoutFile{Workspaces: []config.LayoutWorkspace{{
+		Number: 1,
+		Windows: []config.LayoutWindow{
+			{Name: "server", Command: "sleep 30", Cwd: dir},
+			{Name: "shell"},
+		},
+	}}}
+	if created := m.ApplyLayoutFile(lf); created != 2 {
+		t.Fatalf("ApplyLayoutFile() = %d, want 2 windows", created)
+	}
+	for _, w := range m.Windows {
+		defer w.Close()
+	}
+
+	server := m.Windows[0]
+	if server.Cmd == nil || !slices.Equal(server.Cmd.Args, []string{"/bin/sh", "-c", "sleep 30"}) || server.Cmd.Dir != dir {
+		t.Errorf("server window runs %v in %q, want the command as its own in %s", server.Cmd.Args, server.Cmd.Dir, dir)
+	}
+	if shell := m.Windows[1]; shell.Cmd == nil || slices.Contains(shell.Cmd.Args, "-c") {
+		t.Errorf("expected a window without a command to run the shell, got %v", shell.Cmd.Args)
+	}
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// Mark prompts, set while waiting for the mark letter.
+const (
+	markPromptSet  = "set"
+	markPromptJump = "jump"
+)
+
+// isMarkName reports whether key can name a mark: a single ASCII letter.
+func isMarkName(key string) bool {
+	return len(key) == 1 && (key[0] >= 'a' && key[0] <= 'z' || key[0] >= 'A' && key[0] <= 'Z')
+}
+
+// StartMarkPrompt waits for the letter of a mark to set on the focused
+// window or to jump to.
+func (m *OS) StartMarkPrompt(jump bool) {
+	if jump {
+		if len(m.WindowMarks) == 0 {
+			m.ShowNotification("No marks set", "info", config.NotificationDuration)
+			return
+		}
+		m.MarkPrompt = markPromptJump
+		m.ShowNotification("Jump to mark: press a letter", "info", 0) // Persistent until a mark is picked
+		return
+	}
+	if m.GetFocusedWindow() == nil {
+		return
+	}
+	m.MarkPrompt = markPromptSet
+	m.ShowNotification("Set mark: press a letter", "info", 0) // Persistent until a mark is picked
+}
+
+// HandleMarkKey sets or jumps to the mark named key, depending on the
+// active prompt. Other keys cancel the prompt.
+func (m *OS) HandleMarkKey(key string) {
+	prompt := m.MarkPrompt
+	m.MarkPrompt = ""
+	m.ShowNotification("", "info", 0)
+	if !isMarkName(key) {
+		return
+	}
+	if prompt == markPromptSet {
+		m.SetMark(key)
+		return
+	}
+	if !m.JumpToMark(key) {
+		m.ShowNotification(fmt.Sprintf("Mark '%s' is not set", key), "warning", config.NotificationDuration)
+	}
+}
+
+// SetMark names the focused window with a mark, moving the mark if another
+// window had it.
+func (m *OS) SetMark(name string) {
+	window := m.GetFocusedWindow()
+	if window == nil {
+		return
+	}
+	if m.WindowMarks == nil {
+		m.WindowMarks = make(map[string]string)
+	}
+	m.WindowMarks[name] = window.ID
+	m.ShowNotification(fmt.Sprintf("Mark '%s' set on %s", name, m.getWindowDisplayName(window)), "info", config.NotificationDuration)
+	m.SyncStateToDaemon()
+}
+
+// JumpToMark focuses the window with the mark, switching to its workspace
+// and restoring it if needed. It reports false if no open window has it.
+func (m *OS) JumpToMark(name string) bool {
+	id, ok := m.WindowMarks[name]
+	if !ok {
+		return false
+	}
+	for i, w := range m.Windows {
+		if w.ID == id {
+			m.JumpToWindow(i)
+			return true
+		}
+	}
+	// The window was closed
+	delete(m.WindowMarks, name)
+	return false
+}
+
+// JumpToWindow focuses window i wherever it is, switching to its workspace
+// and restoring it if it is minimized. The window focused before becomes the
+// last focused window, even though switching workspaces focuses another
+// window on the way.
+func (m *OS) JumpToWindow(i int) {
+	if i < 0 || i >= len(m.Windows) {
+		return
+	}
+	window := m.Windows[i]
+	origin := m.GetFocusedWindow()
+	if window.Workspace != m.CurrentWorkspace {
+		m.SwitchToWorkspace(window.Workspace)
+	}
+	if window.Minimized {
+		m.RestoreWindow(i)
+		if m.AutoTiling {
+			m.TileAllWindows()
+		}
+	}
+	m.FocusWindow(i)
+	if origin != nil && origin != window {
+		m.LastFocusedWindowID = origin.ID
+	}
+	m.MarkAllDirty()
+}
+
+// FocusLastWindow switches between the focused window and the one focused
+// before it, across workspaces.
+func (m *OS) FocusLastWindow() {
+	for i, w := range m.Windows {
+		if w.ID == m.LastFocusedWindowID && i != m.FocusedWindow {
+			m.JumpToWindow(i)
+			return
+		}
+	}
+	m.ShowNotification("No previous window", "info", config.NotificationDuration)
+}
+
+// WindowMarkNames returns the sorted marks set on the window with the ID.
+func (m *OS) WindowMarkNames(id string) []string {
+	var names []string
+	for name, windowID := range m.WindowMarks {
+		if windowID == id {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// windowMarksState returns the marks of open windows for the session state.
+func (m *OS) windowMarksState() map[string]string {
+	if len(m.WindowMarks) == 0 {
+		return nil
+	}
+	open := make(map[string]bool, len(m.Windows))
+	for _, w := range m.Windows {
+		open[w.ID] = true
+	}
+	marks := make(map[string]string)
+	for name, id := range m.WindowMarks {
+		if open[id] {
+			marks[name] = id
+		}
+	}
+	return marks
+}
+
+// formatMarks formats mark names for display, e.g. "'a 'b".
+func formatMarks(names []string) string {
+	for i, name := range names {
+		names[i] = "'" + name
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestWindowMarks(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.NumWorkspaces = 9
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", Width: 40, Height: 20, Workspace: 2},
+	}
+	m.FocusedWindow = 0
+
+	m.StartMarkPrompt(true)
+	if m.MarkPrompt != "" {
+		t.Error("expected no jump prompt without marks")
+	}
+
+	m.StartMarkPrompt(false)
+	m.HandleMarkKey("a")
+	if m.WindowMarks["a"] != "window-one-0000" || m.MarkPrompt != "" {
+		t.Fatalf("expected mark a on window one, got %v", m.WindowMarks)
+	}
+	m.WindowMarks["b"] = "window-two-0000"
+
+	if !m.JumpToMark("b") || m.FocusedWindow != 1 || m.CurrentWorkspace != 2 {
+		t.Errorf("expected jump to window two on workspace 2, focused %d on %d", m.FocusedWindow, m.CurrentWorkspace)
+	}
+
+	// Marks of closed windows are dropped from the session state
+	m.WindowMarks["c"] = "window-gone-000"
+	state := m.BuildSessionState()
+	if len(state.Marks) != 2 || state.Marks["a"] != "window-one-0000" {
+		t.Errorf("expected marks a and b in the session state, got %v", state.Marks)
+	}
+	if m.JumpToMark("c") {
+		t.Error("expected a mark of a closed window not to jump")
+	}
+}
+
+func TestFocusLastWindow(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.NumWorkspaces = 9
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", Width: 40, Height: 20, Workspace: 2},
+		{ID: "window-three-00", Width: 40, Height: 20, Workspace: 2},
+	}
+	m.FocusedWindow = 0
+	m.WorkspaceFocus[2] = 2
+
+	m.FocusLastWindow()
+	if m.FocusedWindow != 0 {
+		t.Fatalf("expected focus to stay without a last window, got %d", m.FocusedWindow)
+	}
+
+	// Switching to workspace 2 focuses window three on the way to window two,
+	// but the last window must still be window one
+	m.JumpToWindow(1)
+	if m.FocusedWindow != 1 || m.LastFocusedWindowID != "window-one-0000" {
+		t.Fatalf("expected window two focused with window one last, got %d and %q", m.FocusedWindow, m.LastFocusedWindowID)
+	}
+
+	m.FocusLastWindow()
+	if m.FocusedWindow != 0 || m.CurrentWorkspace != 1 {
+		t.Errorf("expected to bounce back to window one on workspace 1, got %d on %d", m.FocusedWindow, m.CurrentWorkspace)
+	}
+	m.FocusLastWindow()
+	if m.FocusedWindow != 1 || m.CurrentWorkspace != 2 {
+		t.Errorf("expected to bounce to window two on workspace 2, got %d on %d", m.FocusedWindow, m.CurrentWorkspace)
+	}
+}
+
+func TestJumpToUrgentWindow(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.NumWorkspaces = 9
+	m.CurrentWorkspace = 1
+	now := time.Now()
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", Width: 40, Height: 20, Workspace: 2, Bells: 1, UrgentSince: now},
+		{ID: "window-three-00", Width: 40, Height: 20, Workspace: 3, UrgentSince: now.Add(-time.Minute)},
+	}
+	m.FocusedWindow = 0
+
+	if urgent := m.UrgentWindows(); len(urgent) != 2 || urgent[0] != 2 {
+		t.Fatalf("expected windows three and two urgent, oldest first, got %v", urgent)
+	}
+
+	m.JumpToUrgentWindow()
+	if m.FocusedWindow != 2 || m.CurrentWorkspace != 3 {
+		t.Fatalf("expected the oldest urgent window three on workspace 3, got %d on %d", m.FocusedWindow, m.CurrentWorkspace)
+	}
+	m.JumpToUrgentWindow()
+	if m.FocusedWindow != 1 || m.CurrentWorkspace != 2 || m.Windows[1].Bells != 0 {
+		t.Errorf("expected window two next with its bells cleared, got %d on %d", m.FocusedWindow, m.CurrentWorkspace)
+	}
+	if urgent := m.UrgentWindows(); len(urgent) != 0 {
+		t.Errorf("expected no urgent windows left, got %v", urgent)
+	}
+}
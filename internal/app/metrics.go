@@ -0,0 +1,43 @@
+package app
+
+import (
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/profiling"
+)
+
+// metricsInterval is how often session metrics are published while a
+// metrics endpoint is served.
+const metricsInterval = time.Second
+
+// PublishMetrics publishes the windows with their output and scrollback for
+// the metrics endpoint. It is called on every tick, does nothing unless an
+// endpoint is served and throttles itself to metricsInterval.
+func (m *OS) PublishMetrics() {
+	if !profiling.MetricsEnabled() {
+		return
+	}
+	now := time.Now()
+	if now.Sub(m.LastMetricsUpdate) < metricsInterval {
+		return
+	}
+	m.LastMetricsUpdate = now
+
+	metrics := profiling.SessionMetrics{
+		Workspace: m.CurrentWorkspace,
+		Windows:   make([]profiling.WindowMetrics, 0, len(m.Windows)),
+	}
+	for _, w := range m.Windows {
+		window := profiling.WindowMetrics{ID: w.ID, Name: m.getWindowDisplayName(w), Workspace: w.Workspace}
+		// A split or tabbed window counts the output and scrollback of all its terminals
+		for _, t := range badgeTerminals(w) {
+			window.OutputBytes += t.TotalOutput()
+			if t.Terminal != nil {
+				window.ScrollbackBytes += t.Terminal.ScrollbackMemory()
+				window.ScrollbackLines += t.Terminal.ScrollbackLen()
+			}
+		}
+		metrics.Windows = append(metrics.Windows, window)
+	}
+	profiling.SetSessionMetrics(metrics)
+}
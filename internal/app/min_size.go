@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/layout"
+)
+
+// bspMinSizes returns the minimum sizes of the tiled windows in the current
+// workspace, keyed by their BSP tree IDs.
+func (m *OS) bspMinSizes() map[int]layout.Rect {
+	sizes := make(map[int]layout.Rect)
+	for _, w := range m.Windows {
+		if w.Workspace != m.CurrentWorkspace || w.Minimized || w.Floating {
+			continue
+		}
+		width, height := w.MinimumSize()
+		sizes[m.getWindowIntID(w.ID)] = layout.Rect{W: width, H: height}
+	}
+	return sizes
+}
+
+// TogglePinnedMinSize pins the focused window's current terminal size as its
+// minimum, so tiling and resizing never shrink it below, or clears the pin.
+// Minimums set by window rules stay in effect either way.
+func (m *OS) TogglePinnedMinSize() {
+	w := m.GetFocusedWindow()
+	if w == nil {
+		m.ShowNotification("No window to pin", "warning", config.NotificationDuration)
+		return
+	}
+	if w.MinWidth > 0 || w.MinHeight > 0 {
+		w.MinWidth, w.MinHeight = 0, 0
+		m.ShowNotification("Minimum size cleared", "info", config.NotificationDuration)
+	} else {
+		w.MinWidth, w.MinHeight = max(w.Width-2, 1), max(w.Height-2, 1)
+		m.ShowNotification(fmt.Sprintf("Minimum size: %dx%d", w.MinWidth, w.MinHeight), "info", config.NotificationDuration)
+	}
+	if m.AutoTiling && !w.Floating {
+		m.ApplyBSPLayout()
+	}
+	m.SyncStateToDaemon()
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// minimizedMenuItems returns the indices of the minimized windows of every
+// workspace, by workspace and then in the order they were minimized.
+func (m *OS) minimizedMenuItems() []int {
+	var items []int
+	for i, w := range m.Windows {
+		if w.Minimized && w.SwallowedBy == "" {
+			items = append(items, i)
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool {
+		wa, wb := m.Windows[items[a]], m.Windows[items[b]]
+		if wa.Workspace != wb.Workspace {
+			return wa.Workspace < wb.Workspace
+		}
+		return wa.MinimizeOrder < wb.MinimizeOrder
+	})
+	return items
+}
+
+// ToggleMinimizedMenu shows or hides the menu of minimized windows. The
+// entry of the current workspace's oldest minimized window starts selected.
+func (m *OS) ToggleMinimizedMenu() {
+	if m.ShowMinimizedMenu {
+		m.ShowMinimizedMenu = false
+		return
+	}
+	items := m.minimizedMenuItems()
+	if len(items) == 0 {
+		m.ShowNotification("No minimized windows", "info", config.NotificationDuration)
+		return
+	}
+	m.MinimizedMenuSelection = 0
+	for n, i := range items {
+		if m.Windows[i].Workspace == m.CurrentWorkspace {
+			m.MinimizedMenuSelection = n
+			break
+		}
+	}
+	m.ShowMinimizedMenu = true
+}
+
+// MoveMinimizedMenuSelection moves the menu highlight by delta entries,
+// wrapping around at either end.
+func (m *OS) MoveMinimizedMenuSelection(delta int) {
+	n := len(m.minimizedMenuItems())
+	if n == 0 {
+		return
+	}
+	m.MinimizedMenuSelection = ((m.MinimizedMenuSelection+delta)%n + n) % n
+}
+
+// RestoreMinimizedMenuEntry restores the nth menu entry, switching to its
+// workspace, and closes the menu.
+func (m *OS) RestoreMinimizedMenuEntry(n int) {
+	items := m.minimizedMenuItems()
+	if n < 0 || n >= len(items) {
+		return
+	}
+	m.ShowMinimizedMenu = false
+	m.JumpToWindow(items[n])
+}
+
+// renderMinimizedMenu renders the minimized windows grouped by workspace,
+// with the number of minimized windows of each.
+func (m *OS) renderMinimizedMenu() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true)
+	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Background(lipgloss.Color("#4865f2")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	items := m.minimizedMenuItems()
+	counts := make(map[int]int)
+	for _, i := range items {
+		counts[m.Windows[i].Workspace]++
+	}
+
+	lines := []string{titleStyle.Render(fmt.Sprintf("Minimized Windows (%d)", len(items)))}
+	workspace := 0
+	for n, i := range items {
+		w := m.Windows[i]
+		if w.Workspace != workspace {
+			workspace = w.Workspace
+			header := fmt.Sprintf("Workspace %d (%d)", workspace, counts[workspace])
+			if workspace == m.CurrentWorkspace {
+				header += " - current"
+			}
+			lines = append(lines, "", headerStyle.Render(header))
+		}
+		label := ansi.Truncate(m.getWindowDisplayName(w), 40, "…")
+		if n < 9 {
+			label = fmt.Sprintf("%d  %s", n+1, label)
+		} else {
+			label = "   " + label
+		}
+		if n == m.MinimizedMenuSelection {
+			lines = append(lines, selectedStyle.Render(" "+label+" "))
+		} else {
+			lines = append(lines, itemStyle.Render(" "+label+" "))
+		}
+	}
+	lines = append(lines, "", dimStyle.Render("j/k to move, Enter or 1-9 to restore, q/Esc to close"))
+
+	return lipgloss.NewStyle().
+		Border(getBorder()).
+		BorderForeground(lipgloss.Color("13")).
+		Padding(1, 2).
+		Background(lipgloss.Color("#1a1a2a")).
+		Render(strings.Join(lines, "\n"))
+}
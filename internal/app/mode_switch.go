@@ -1,7 +1,11 @@
 package app
 
 import (
+	"os"
+	"time"
+
 	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/tape"
 )
 
@@ -36,3 +40,50 @@ func (m *OS) ExitTerminalMode() tea.Cmd {
 	// Raw reader disabled - Bubbletea handles all input correctly
 	return nil
 }
+
+// nestedNotificationDuration is how long the notice that TUIOS runs nested
+// stays up, long enough to read the passthrough hint.
+const nestedNotificationDuration = 6 * time.Second
+
+// RunningInsideTUIOS reports whether this process runs in a window of
+// another TUIOS, which exports TUIOS_WINDOW_ID to the shells of its windows.
+func RunningInsideTUIOS() bool {
+	return os.Getenv("TUIOS_WINDOW_ID") != ""
+}
+
+// KeyPassthroughHint returns the keys that turn key passthrough on, for
+// display.
+func (m *OS) KeyPassthroughHint() string {
+	keys := "N"
+	if m.KeybindRegistry != nil {
+		if bound := m.KeybindRegistry.GetKeysForDisplay("prefix_key_passthrough"); bound != "" {
+			keys = bound
+		}
+	}
+	return config.LeaderKey + ", " + keys
+}
+
+// ToggleKeyPassthrough starts or stops sending every key, the prefix key
+// included, to the focused window. This lets a TUIOS running in the window
+// receive its own prefix commands. Passthrough works in terminal mode, which
+// it switches to, and is stopped with terminal_exit_passthrough.
+func (m *OS) ToggleKeyPassthrough() {
+	if m.KeyPassthrough {
+		m.KeyPassthrough = false
+		m.ShowNotification("Key passthrough off", "info", config.NotificationDuration)
+		return
+	}
+	if m.GetFocusedWindow() == nil {
+		m.ShowNotification("No window to pass keys to", "warning", config.NotificationDuration)
+		return
+	}
+	m.EnterTerminalMode()
+	m.KeyPassthrough = true
+	exit := "F12"
+	if m.KeybindRegistry != nil {
+		if keys := m.KeybindRegistry.GetKeysForDisplay("terminal_exit_passthrough"); keys != "" {
+			exit = keys
+		}
+	}
+	m.ShowNotification("Key passthrough on: "+exit+" to stop", "info", config.NotificationDuration*2)
+}
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// ToggleMouseCapture switches whether mouse events over the focused pane go
+// to its application or are handled by tuios. By default they go to the
+// application whenever it requests mouse reporting; capturing keeps them in
+// tuios for moving, resizing and selecting instead.
+func (m *OS) ToggleMouseCapture() {
+	pane := m.GetFocusedPane()
+	if pane == nil {
+		return
+	}
+	pane.MouseCapture = !pane.MouseCapture
+	if pane.MouseCapture {
+		m.ShowNotification("Mouse: handled by tuios", "info", config.NotificationDuration)
+	} else {
+		m.ShowNotification("Mouse: passed to application", "info", config.NotificationDuration)
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"fmt"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+)
+
+// moveModeKeys maps the keys of move mode to the direction they move the
+// window in. The second half of the keys, with Shift, move it
+// config.MoveStepLarge cells at a time.
+var moveModeKeys = map[string][3]int{
+	"h": {-1, 0, 0}, "left": {-1, 0, 0},
+	"l": {1, 0, 0}, "right": {1, 0, 0},
+	"k": {0, -1, 0}, "up": {0, -1, 0},
+	"j": {0, 1, 0}, "down": {0, 1, 0},
+	"H": {-1, 0, 1}, "shift+h": {-1, 0, 1}, "shift+left": {-1, 0, 1},
+	"L": {1, 0, 1}, "shift+l": {1, 0, 1}, "shift+right": {1, 0, 1},
+	"K": {0, -1, 1}, "shift+k": {0, -1, 1}, "shift+up": {0, -1, 1},
+	"J": {0, 1, 1}, "shift+j": {0, 1, 1}, "shift+down": {0, 1, 1},
+}
+
+// StartMoveMode starts moving the focused window with the keyboard, the
+// counterpart of dragging it by its title bar. Only windows placed freely
+// can be moved: any window while tiling is off, or a floating one.
+func (m *OS) StartMoveMode() {
+	i := m.freeFocusedWindow()
+	if i < 0 {
+		m.ShowNotification("Only floating windows can be moved", "warning", config.NotificationDuration)
+		return
+	}
+	m.CompleteWindowAnimations(i)
+	w := m.Windows[i]
+	m.MoveMode = true
+	m.moveModeOrigin = [2]int{w.X, w.Y}
+	m.MarkAllDirty()
+}
+
+// HandleMoveModeKey moves the focused window by the step of a move key.
+// Enter leaves move mode, Esc leaves it and puts the window back where it
+// was; other keys are ignored.
+func (m *OS) HandleMoveModeKey(key string) {
+	i := m.freeFocusedWindow()
+	if i < 0 {
+		m.endMoveMode()
+		return
+	}
+	w := m.Windows[i]
+	switch key {
+	case "enter", "q":
+		m.endMoveMode()
+		return
+	case "esc":
+		w.X, w.Y = m.moveModeOrigin[0], m.moveModeOrigin[1]
+		w.MarkPositionDirty()
+		m.endMoveMode()
+		return
+	}
+	dir, ok := moveModeKeys[key]
+	if !ok {
+		return
+	}
+	step := config.MoveStep
+	if dir[2] == 1 {
+		step = config.MoveStepLarge
+	}
+	w.X, w.Y = m.clampMovePosition(w.X+dir[0]*step, w.Y+dir[1]*step, w.Width, w.Height)
+	w.MarkPositionDirty()
+	m.MarkAllDirty()
+}
+
+// clampMovePosition keeps a moved window within the bounds dragging it
+// does: partly off screen, but with some of it and its title bar in view.
+func (m *OS) clampMovePosition(x, y, width, height int) (int, int) {
+	minVisibleX, minVisibleY := config.VisibleColumns(width), 3
+	topMargin := m.GetTopMargin()
+	if minVisibleX > 0 {
+		x = min(max(x, -(width-minVisibleX)), m.GetRenderWidth()-minVisibleX)
+	}
+	y = min(max(y, topMargin-(height-minVisibleY)), topMargin+m.GetUsableHeight()-minVisibleY)
+	return x, y
+}
+
+// renderMoveReadout draws the position and size of the window being moved
+// and the keys of move mode, shown over the window.
+func (m *OS) renderMoveReadout() (string, int, int) {
+	w := m.GetFocusedWindow()
+	if w == nil {
+		return "", 0, 0
+	}
+	rows := []string{
+		lipgloss.NewStyle().Foreground(theme.HelpTabActive()).Bold(true).Render(
+			fmt.Sprintf("%d,%d  %dx%d", w.X, w.Y-m.GetTopMargin(), w.Width, w.Height)),
+		lipgloss.NewStyle().Foreground(theme.HelpGray()).Italic(true).Render(
+			fmt.Sprintf("hjkl/arrows  Shift: %d cells  Enter: done  Esc: cancel", config.MoveStepLarge)),
+	}
+	readout := lipgloss.NewStyle().
+		Border(getBorder()).
+		BorderForeground(theme.HelpBorder()).
+		Padding(0, 1).
+		Align(lipgloss.Center).
+		Render(lipgloss.JoinVertical(lipgloss.Center, rows...))
+	return readout, lipgloss.Width(readout), lipgloss.Height(readout)
+}
+
+// endMoveMode leaves move mode and keeps the window where it is.
+func (m *OS) endMoveMode() {
+	m.MoveMode = false
+	m.MarkAllDirty()
+	if w := m.GetFocusedWindow(); w != nil {
+		m.RememberWindowGeometry(w)
+	}
+	m.SyncStateToDaemon()
+}
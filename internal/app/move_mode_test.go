@@ -0,0 +1,63 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/adrg/xdg"
+)
+
+func TestMoveMode(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	defer func(step, large int) { config.MoveStep, config.MoveStepLarge = step, large }(config.MoveStep, config.MoveStepLarge)
+	config.MoveStep, config.MoveStepLarge = 2, 10
+
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	top := m.GetTopMargin()
+	w := &terminal.Window{ID: "window-one-0000", X: 30, Y: top + 5, Width: 40, Height: 20, Workspace: 1}
+	m.Windows = []*terminal.Window{w}
+	m.FocusWindow(0)
+
+	m.StartMoveMode()
+	if !m.MoveMode {
+		t.Fatal("expected move mode to start on a floating window")
+	}
+	for _, key := range []string{"l", "right", "j", "shift+left", "x"} {
+		m.HandleMoveModeKey(key)
+	}
+	if w.X != 24 || w.Y != top+7 {
+		t.Errorf("after moving, window at %d,%d, want 24,%d", w.X, w.Y, top+7)
+	}
+
+	// Moves stop where dragging would
+	for range 20 {
+		m.HandleMoveModeKey("K")
+	}
+	if w.Y != top-17 {
+		t.Errorf("after moving up, window at y %d, want %d", w.Y, top-17)
+	}
+
+	m.HandleMoveModeKey("esc")
+	if m.MoveMode || w.X != 30 || w.Y != top+5 {
+		t.Errorf("after esc, move mode %v and window at %d,%d, want it back at 30,%d", m.MoveMode, w.X, w.Y, top+5)
+	}
+
+	m.StartMoveMode()
+	m.HandleMoveModeKey("h")
+	m.HandleMoveModeKey("enter")
+	if m.MoveMode || w.X != 28 {
+		t.Errorf("after enter, move mode %v and window at x %d, want it kept at 28", m.MoveMode, w.X)
+	}
+
+	m.ToggleAutoTiling()
+	m.CompleteAllAnimations()
+	m.StartMoveMode()
+	if m.MoveMode {
+		t.Error("expected move mode to refuse a tiled window")
+	}
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// RefreshWindowNames names windows after the command running in them, as
+// the naming rules say, at most once per appRefreshInterval. A window keeps
+// following the rules, and loses its rule name once no rule matches, until
+// it is renamed by hand; clearing its name hands it back to the rules. It
+// reports whether any window's name changed.
+func (m *OS) RefreshWindowNames(now time.Time) bool {
+	if !config.HasNamingRules() || now.Sub(m.lastNameRefresh) < appRefreshInterval {
+		return false
+	}
+	m.lastNameRefresh = now
+	changed := false
+	for _, w := range m.Windows {
+		if w.CustomName != "" && w.CustomName != w.RuleName {
+			continue
+		}
+		name, _ := config.RuleName(windowCommandLine(w.ActivePane()))
+		if name != w.CustomName {
+			w.CustomName, w.RuleName = name, name
+			changed = true
+		}
+	}
+	if changed {
+		m.MarkAllDirty()
+		m.SyncStateToDaemon()
+	}
+	return changed
+}
+
+// RefreshScrollbackLimits applies the scrollback limits the window rules give
+// the current names of the windows and their panes, about once a second.
+// Titles change from the terminal's output, so the rules are evaluated here
+// rather than as the title arrives.
+func (m *OS) RefreshScrollbackLimits(now time.Time) {
+	if len(config.WindowRules) == 0 || now.Sub(m.lastScrollbackRefresh) < appRefreshInterval {
+		return
+	}
+	m.lastScrollbackRefresh = now
+	for _, w := range m.terminalsWithPanes() {
+		w.RefreshScrollbackLimit(now)
+	}
+}
+
+// windowCommandLine returns the command line running in a terminal instead
+// of its shell. Daemon windows have no local process, so their title stands
+// in, which most shells set to the command they run.
+func windowCommandLine(w *terminal.Window) string {
+	if args := w.ForegroundCommandLine(); len(args) > 0 {
+		return strings.Join(args, " ")
+	}
+	if w.Pty == nil && !isDefaultTitle(w.Title, w.ID) {
+		return w.Title
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestWindowNamingRules(t *testing.T) {
+	config.SetNamingRules([]config.NamingRule{
+		{Command: `^ssh (?:-\S+ )*(?:\S+@)?(\S+)`, Name: "$1"},
+		{Command: `^(\S+) -invalid(`, Name: "never"},
+		{Command: `^kubectl .*-n (?P<ns>\S+)`, Name: "k8s ${ns}"},
+	})
+	defer config.SetNamingRules(nil)
+
+	m := NewOS(OSOptions{})
+	w := &terminal.Window{ID: "window-one-0000", Title: "ssh -A admin@web01"}
+	m.Windows = []*terminal.Window{w}
+	now := time.Now()
+
+	if !m.RefreshWindowNames(now) || w.CustomName != "web01" {
+		t.Fatalf("expected the window named web01, got %q", w.CustomName)
+	}
+	now = now.Add(appRefreshInterval)
+	w.Title = "kubectl logs -f -n prod api"
+	if !m.RefreshWindowNames(now) || w.CustomName != "k8s prod" {
+		t.Fatalf("expected the name to follow the command, got %q", w.CustomName)
+	}
+	now = now.Add(appRefreshInterval)
+	w.Title = "zsh"
+	if !m.RefreshWindowNames(now) || w.CustomName != "" {
+		t.Fatalf("expected the rule name dropped once no rule matches, got %q", w.CustomName)
+	}
+
+	// A window renamed by hand keeps its name
+	w.CustomName = "mine"
+	w.Title = "ssh web02"
+	now = now.Add(appRefreshInterval)
+	if m.RefreshWindowNames(now) || w.CustomName != "mine" {
+		t.Errorf("expected a renamed window to keep its name, got %q", w.CustomName)
+	}
+}
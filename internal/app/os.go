@@ -5,18 +5,21 @@ import (
 	"fmt"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"charm.land/lipgloss/v2"
 	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/debuglog"
 	"github.com/Gaurav-Gosain/tuios/internal/layout"
 	"github.com/Gaurav-Gosain/tuios/internal/session"
 	"github.com/Gaurav-Gosain/tuios/internal/tape"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 	"github.com/Gaurav-Gosain/tuios/internal/ui"
 	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/google/uuid"
 )
 
@@ -30,7 +33,9 @@ const (
 	TerminalMode
 )
 
-// ResizeCorner identifies which corner is being used for window resizing.
+// ResizeCorner identifies which corner or edge is being used for window
+// resizing. The top edge is the title bar, which moves the window, so it is
+// only resized from its corners.
 type ResizeCorner int
 
 const (
@@ -42,8 +47,34 @@ const (
 	BottomLeft
 	// BottomRight represents the bottom-right corner for resizing.
 	BottomRight
+	// Left represents the left edge for resizing.
+	Left
+	// Right represents the right edge for resizing.
+	Right
+	// Bottom represents the bottom edge for resizing.
+	Bottom
 )
 
+// MovesLeft reports whether resizing from c moves the left edge.
+func (c ResizeCorner) MovesLeft() bool {
+	return c == TopLeft || c == BottomLeft || c == Left
+}
+
+// MovesRight reports whether resizing from c moves the right edge.
+func (c ResizeCorner) MovesRight() bool {
+	return c == TopRight || c == BottomRight || c == Right
+}
+
+// MovesTop reports whether resizing from c moves the top edge.
+func (c ResizeCorner) MovesTop() bool {
+	return c == TopLeft || c == TopRight
+}
+
+// MovesBottom reports whether resizing from c moves the bottom edge.
+func (c ResizeCorner) MovesBottom() bool {
+	return c == BottomLeft || c == BottomRight || c == Bottom
+}
+
 // SnapQuarter represents window snapping positions.
 type SnapQuarter int
 
@@ -66,6 +97,20 @@ const (
 	SnapFullScreen
 	// Unsnap restores window to its previous position.
 	Unsnap
+	// SnapTop snaps window to top half of screen.
+	SnapTop
+	// SnapBottom snaps window to bottom half of screen.
+	SnapBottom
+	// SnapLeftThird snaps window to left third of screen.
+	SnapLeftThird
+	// SnapCenterThird snaps window to middle third of screen.
+	SnapCenterThird
+	// SnapRightThird snaps window to right third of screen.
+	SnapRightThird
+	// SnapLeftTwoThirds snaps window to left two thirds of screen.
+	SnapLeftTwoThirds
+	// SnapRightTwoThirds snaps window to right two thirds of screen.
+	SnapRightTwoThirds
 )
 
 // WindowLayout stores a window's position and size for workspace persistence
@@ -117,48 +162,127 @@ type OS struct {
 	LastCPUUpdate      time.Time                  // Last time CPU was updated
 	RAMUsage           float64                    // Cached RAM usage percentage
 	LastRAMUpdate      time.Time                  // Last time RAM was updated
+	LastScrollbackTrim time.Time                  // Last time scrollback memory budgets were enforced
+	LastAutosave       time.Time                  // Last time the layout autosave was checked
+	LastMetricsUpdate  time.Time                  // Last time session metrics were published
+	lastAutosaveState  []byte                     // Layout written by the last autosave (skip unchanged writes)
+	ConfigPath         string                     // Config file watched for live reload (empty disables reload)
+	ConfigOverrides    config.Overrides           // CLI flags that keep precedence over the reloaded config
+	LastConfigCheck    time.Time                  // Last time the config file was checked for changes
+	configModTime      time.Time                  // Modification time of the config file when last loaded
 	AutoTiling         bool                       // Automatic tiling mode enabled
 	MasterRatio        float64                    // Master window width ratio for tiling (0.3-0.7)
 	// BSP tiling state
-	WorkspaceTrees        map[int]*layout.BSPTree // BSP tree per workspace
-	PreselectionDir       layout.PreselectionDir  // Pending preselection direction (0 = none)
-	TilingScheme          layout.AutoScheme       // Default auto-insertion scheme
-	SplitTargetWindowID   string                  // Window ID to split (set before AddWindow for splits)
-	WindowToBSPID         map[string]int          // Maps window UUID to stable BSP integer ID
-	NextBSPWindowID       int                     // Next BSP window ID to assign (starts at 1)
-	RenamingWindow        bool                    // True when renaming a window
-	RenameBuffer          string                  // Buffer for new window name
-	PrefixActive          bool                    // True when prefix key was pressed (tmux-style)
-	WorkspacePrefixActive bool                    // True when Ctrl+B, w was pressed (workspace sub-prefix)
-	MinimizePrefixActive  bool                    // True when Ctrl+B, m was pressed (minimize sub-prefix)
-	TilingPrefixActive    bool                    // True when Ctrl+B, t was pressed (tiling/window sub-prefix)
-	DebugPrefixActive     bool                    // True when Ctrl+B, D was pressed (debug sub-prefix)
-	LastPrefixTime        time.Time               // Time when prefix was activated
-	HelpScrollOffset      int                     // Scroll offset for help menu
-	HelpCategory          int                     // Current help category index (for left/right navigation)
-	HelpSearchMode        bool                    // True when help search is active
-	HelpSearchQuery       string                  // Current search query in help menu
-	CurrentWorkspace      int                     // Current active workspace (1-9)
-	NumWorkspaces         int                     // Total number of workspaces
-	WorkspaceFocus        map[int]int             // Remembers focused window per workspace
-	WorkspaceLayouts      map[int][]WindowLayout  // Stores custom layouts per workspace
-	WorkspaceHasCustom    map[int]bool            // Tracks if workspace has custom layout
-	WorkspaceMasterRatio  map[int]float64         // Stores master ratio per workspace
-	ShowLogs              bool                    // True when showing log overlay
-	LogMessages           []LogMessage            // Store log messages
-	LogScrollOffset       int                     // Scroll offset for log viewer
-	Notifications         []Notification          // Active notifications
-	SelectionMode         bool                    // True when in text selection mode
-	ClipboardContent      string                  // Store clipboard content from tea.ClipboardMsg
-	ShowCacheStats        bool                    // True when showing style cache statistics overlay
-	ShowQuitConfirm       bool                    // True when showing quit confirmation dialog
-	QuitConfirmSelection  int                     // 0 = Yes (left), 1 = No (right)
+	WorkspaceTrees         map[int]*layout.BSPTree // BSP tree per workspace
+	PreselectionDir        layout.PreselectionDir  // Pending preselection direction (0 = none)
+	TilingScheme           layout.AutoScheme       // Default auto-insertion scheme
+	SplitTargetWindowID    string                  // Window ID to split (set before AddWindow for splits)
+	WindowToBSPID          map[string]int          // Maps window UUID to stable BSP integer ID
+	NextBSPWindowID        int                     // Next BSP window ID to assign (starts at 1)
+	RenamingWindow         bool                    // True when renaming a window
+	RenameBuffer           string                  // Buffer for new window name
+	PrefixActive           bool                    // True when prefix key was pressed (tmux-style)
+	WorkspacePrefixActive  bool                    // True when Ctrl+B, w was pressed (workspace sub-prefix)
+	MinimizePrefixActive   bool                    // True when Ctrl+B, m was pressed (minimize sub-prefix)
+	TilingPrefixActive     bool                    // True when Ctrl+B, t was pressed (tiling/window sub-prefix)
+	DebugPrefixActive      bool                    // True when Ctrl+B, D was pressed (debug sub-prefix)
+	LastPrefixTime         time.Time               // Time when prefix was activated
+	KeyPassthrough         bool                    // True while every key, prefix included, goes to the focused window
+	Nested                 bool                    // True when running inside a window of another TUIOS
+	LastInputTime          time.Time               // Time of the last key or mouse input, for the screensaver
+	Screensaver            *Screensaver            // Screensaver shown over the session (nil when not shown)
+	lastAppRefresh         time.Time               // When the programs running in windows were last looked up
+	lastNameRefresh        time.Time               // When the naming rules were last applied to the windows
+	lastScrollbackRefresh  time.Time               // When the window rules' scrollback limits were last applied
+	lastTitleRefresh       time.Time               // When the processes shown in title bars were last sampled
+	titleSampling          bool                    // Whether a sample of the processes shown in title bars is running
+	titleProcesses         map[string]titleProcess // Last sampled title fields by pane ID
+	rememberedGeometry     geometryMemory          // Last floating geometry of each command's window, loaded on first use
+	geometrySaveAt         time.Time               // When the remembered geometry is due to be written (zero = saved)
+	HelpScrollOffset       int                     // Scroll offset for help menu
+	HelpCategory           int                     // Current help category index (for left/right navigation)
+	HelpSearchMode         bool                    // True when help search is active
+	HelpSearchQuery        string                  // Current search query in help menu
+	CurrentWorkspace       int                     // Current active workspace (1-9)
+	NumWorkspaces          int                     // Total number of workspaces
+	PreviousWorkspace      int                     // Workspace active before the current one (0 if none)
+	WorkspaceFocus         map[int]int             // Remembers focused window per workspace
+	WorkspaceLayouts       map[int][]WindowLayout  // Stores custom layouts per workspace
+	WorkspaceHasCustom     map[int]bool            // Tracks if workspace has custom layout
+	WorkspaceMasterRatio   map[int]float64         // Stores master ratio per workspace
+	WorkspaceTiling        map[int]bool            // Tiling mode of the other workspaces (AutoTiling is the current one's)
+	WorkspaceNames         map[int]string          // Names given to workspaces, shown next to their numbers
+	PopulatedWorkspaces    map[int]bool            // Workspaces their workspace template was considered for
+	Outputs                []*VirtualOutput        // Side-by-side outputs of a split screen (nil when not split)
+	ActiveOutput           int                     // Output CurrentWorkspace is shown on
+	ShowLogs               bool                    // True when showing log overlay
+	LogMessages            []LogMessage            // Store log messages
+	LogScrollOffset        int                     // Scroll offset for log viewer
+	Notifications          []Notification          // Active notifications
+	SelectionMode          bool                    // True when in text selection mode
+	MouseSelection         *terminal.Window        // Pane holding a text selection dragged with the mouse override modifier
+	ScrollbarDrag          *terminal.Window        // Window whose scrollbar is being dragged
+	ClipboardContent       string                  // Store clipboard content from tea.ClipboardMsg
+	PrimarySelection       string                  // Text last selected with the mouse, pasted by a middle click
+	ShowCacheStats         bool                    // True when showing style cache statistics overlay
+	ShowScrollbackStats    bool                    // True when showing scrollback memory overlay
+	ShowWindowInfo         bool                    // True when showing the focused window's info overlay
+	WindowInfoEditing      bool                    // True while typing a scrollback limit in the window info overlay
+	WindowInfoBuffer       string                  // Scrollback limit being typed
+	windowInfo             *windowInfoState        // Processes of the pane shown in the window info overlay
+	ShowWindowHints        bool                    // True while jump labels are shown on the windows
+	ImageHints             []imageHint             // Labelled image files on the focused pane's screen, while shown
+	ShowMinimizedMenu      bool                    // True when listing the minimized windows of every workspace
+	MinimizedMenuSelection int                     // Highlighted entry of the minimized windows menu
+	WindowMarks            map[string]string       // Mark letter -> window ID
+	MarkPrompt             string                  // "set" or "jump" while waiting for a mark letter
+	MoveMode               bool                    // True while the focused window is moved with the keyboard
+	moveModeOrigin         [2]int                  // Position the window had when move mode started
+	CompactMode            bool                    // True while the screen is small enough for the compact layout
+	compactDockbarPosition string                  // Dock position the compact layout hides, put back when it ends
+	secureWindows          map[string]bool         // IDs of the windows whose title bar shows secure input
+	LastFocusedWindowID    string                  // Window focused before the current one, in any workspace
+	ShowQuitConfirm        bool                    // True when showing quit confirmation dialog
+	QuitConfirmSelection   int                     // 0 = Yes (left), 1 = No (right)
+	QuitRunning            []string                // Windows running programs when the quit dialog opened
+	ShowPasteConfirm       bool                    // True when confirming a multi-line paste into a shell
+	PasteConfirmSelection  int                     // 0 = Yes (left), 1 = No (right)
+	PendingPaste           string                  // Paste content waiting for confirmation
+	PendingPasteWindowID   string                  // Window the pending paste targets
+	ShowCloseAllConfirm    bool                    // True when confirming closing several windows
+	Popup                  *Popup                  // Popup shown over IPC (nil when none)
+	RunPrompt              Prompt                  // Prompt for a command to run in a popup
+	TagPrompt              Prompt                  // Prompt editing the focused window's tags
+	FindPrompt             Prompt                  // Prompt for text to find on screen
+	LastFindQuery          string                  // Text last found on screen, to search again
+	TagView                *session.TagViewState   // Windows gathered by tag onto one workspace (nil when not shown)
+	CloseAllSelection      int                     // 0 = Yes, 1 = No
+	CloseAllRunning        map[string]string       // Programs running in the windows, by ID, when the close-all dialog opened
+	CloseAllTargets        []*terminal.Window      // Windows the close-all dialog closes
+	CloseAllTitle          string                  // Question the close-all dialog asks
+	closeAllFromSidebar    bool                    // The close-all dialog closes windows marked in the sidebar
+	ShowRestorePrompt      bool                    // True when offering to restore a layout from the recovery file
+	RestorePromptSelection int                     // 0 = Yes, 1 = No
+	PendingRecovery        *RecoveryState          // Recovered layout waiting for the user's decision
+	PendingLayout          *config.LayoutFile      // Layout file applied once the terminal size is known
+	PendingBundle          *SessionBundle          // Session bundle imported once the terminal size is known
 	// Pending resize tracking for debouncing PTY resize during mouse drag
 	PendingResizes map[string][2]int // windowID -> [width, height] of pending PTY resize
+	// OSC 52 clipboard writes confirmed in a popup, sent on the next tick
+	approvedClipboard []terminal.ClipboardWrite
+	// Popups waiting for the one shown to close, oldest first
+	popupQueue []*Popup
 	// Performance optimization caches
 	cachedSeparator      string // Cached dock separator string
 	cachedSeparatorWidth int    // Width of cached separator
 	workspaceActiveStyle *lipgloss.Style
+	dockCache            layerCache // Dock layer reused until its state changes
+	dockRows             int        // Rows of the wrapped dock, 0 until they are counted again
+	fittedDockHeight     int        // Dock height the windows were last laid out for
+	sidebarCache         layerCache // Sidebar layer reused until its state changes
+	inactiveOutput       bool       // True while rendering an output other than the active one
+	// Headless mode: new windows get no process, for repeatable input replays
+	Headless bool
 	// SSH mode fields
 	SSHSession ssh.Session // SSH session reference (nil in local mode)
 	IsSSHMode  bool        // True when running over SSH
@@ -166,6 +290,8 @@ type OS struct {
 	IsDaemonSession   bool               // True when running as part of a persistent daemon session
 	DaemonClient      *session.TUIClient // Client for daemon communication (nil in local mode)
 	SessionName       string             // Name of the daemon session (if attached)
+	ReadOnly          bool               // Mirror client: render the session but discard input and state changes
+	RemoteFocus       map[string]string  // Client ID -> window focused by that client (with config.IndependentFocus)
 	RestoredFromState bool               // True after RestoreFromState, cleared after first resize
 	SubscribedPTYs    map[string]bool    // Tracks which PTY IDs are currently subscribed (for visibility optimization)
 	// Multi-client effective size (min of all clients in session)
@@ -173,6 +299,9 @@ type OS struct {
 	EffectiveHeight int // Effective height for rendering (min of all clients, 0 = use terminal size)
 	// Keyboard enhancement support (Kitty protocol)
 	KeyboardEnhancementsEnabled bool // True when terminal supports keyboard enhancements
+	// Focus reporting to embedded applications
+	HostBlurred   bool             // True while the host terminal window does not have focus
+	focusReported *terminal.Window // Pane last told it has focus (see SyncTerminalFocus)
 	// Keybind registry for user-configurable keybindings
 	KeybindRegistry *config.KeybindRegistry
 	// Showkeys feature
@@ -205,10 +334,28 @@ type OS struct {
 	// Sixel Graphics passthrough for forwarding to host terminal
 	SixelPassthrough *SixelPassthrough
 	// Sidebar state for browser-style tab switching
-	SidebarVisible       bool // True when sidebar is shown
-	SidebarHoverTrigger  bool // True when mouse is hovering left edge (for auto-show)
-	SidebarSelectedIndex int  // Currently highlighted item for keyboard nav (-1 = none)
-	SidebarFocused       bool // True when sidebar has keyboard focus
+	SidebarVisible       bool   // True when sidebar is shown
+	SidebarHoverTrigger  bool   // True when mouse is hovering left edge (for auto-show)
+	SidebarSelectedIndex int    // Currently highlighted item for keyboard nav (-1 = none)
+	SidebarFocused       bool   // True when sidebar has keyboard focus
+	SidebarJoinTarget    string // ID of the window the selected window is merged into ("" when selecting a window to focus)
+	SidebarGroupTarget   string // ID of the window the selected window is grouped with ("" when selecting a window to focus)
+	// IDs of the windows marked in the sidebar for a batch action
+	SidebarMarked map[string]bool
+	// Tag the sidebar lists the windows of ("" lists every window)
+	SidebarTag string
+	// True when the sidebar lists every window by CPU use instead of by workspace
+	SidebarSortCPU bool
+	// Last sample of the CPU and memory used by the windows
+	usage usageSampler
+	// Command history browser
+	CommandHistory          []terminal.CommandRecord // Commands run in every window's shell, oldest first
+	ShowCommandHistory      bool                     // True when the command history browser is shown
+	CommandHistoryQuery     string                   // Text the history is filtered by
+	CommandHistorySelection int                      // Highlighted entry among the matches, newest first
+
+	// Debug consoles by window ID
+	DebugConsoles map[string]*debugConsole
 }
 
 // Notification represents a temporary notification message.
@@ -244,6 +391,7 @@ func createID() string {
 // Log adds a new log message to the log buffer.
 func (m *OS) Log(level, format string, args ...any) {
 	message := fmt.Sprintf(format, args...)
+	debuglog.Record(debuglog.ParseLevel(level), "tuios", message)
 	logMsg := LogMessage{
 		Time:    time.Now(),
 		Level:   level,
@@ -429,24 +577,31 @@ func (m *OS) FocusWindow(i int) *OS {
 	}
 
 	oldFocused := m.FocusedWindow
+	if oldFocused >= 0 && oldFocused < len(m.Windows) {
+		m.LastFocusedWindowID = m.Windows[oldFocused].ID
+	}
 
 	// ATOMIC: Set focus and Z-index in one operation
 	m.FocusedWindow = i
+	m.Windows[i].LastViewed = time.Now()
 
 	// Save focus for current workspace
 	if m.Windows[i].Workspace == m.CurrentWorkspace {
 		m.WorkspaceFocus[m.CurrentWorkspace] = i
 	}
 
-	// Simple Z-index assignment: focused window gets highest Z
-	highestZ := len(m.Windows) - 1
-	m.Windows[i].Z = highestZ
-
-	// Assign Z-indices to other windows in order
+	// Simple Z-index assignment: focused window gets highest Z, other windows
+	// follow in order. In tiling mode floating windows stay above tiled ones.
 	z := 0
-	for j := range m.Windows {
-		if j != i {
-			m.Windows[j].Z = z
+	for _, floating := range []bool{false, true} {
+		for j, w := range m.Windows {
+			if j != i && (m.AutoTiling && w.Floating) == floating {
+				w.Z = z
+				z++
+			}
+		}
+		if (m.AutoTiling && m.Windows[i].Floating) == floating {
+			m.Windows[i].Z = z
 			z++
 		}
 	}
@@ -466,9 +621,16 @@ func (m *OS) FocusWindow(i int) *OS {
 // AddWindow adds a new window to the current workspace.
 // In daemon mode, this creates a daemon-managed PTY and window.
 func (m *OS) AddWindow(title string) *OS {
+	return m.AddCommandWindow(title, nil, "")
+}
+
+// AddCommandWindow creates a new window like AddWindow that runs argv in dir
+// instead of the shell, so the window closes when the command exits. An
+// empty argv starts the shell.
+func (m *OS) AddCommandWindow(title string, argv []string, dir string) *OS {
 	// In daemon mode, use daemon PTY management
 	if m.IsDaemonSession && m.DaemonClient != nil {
-		return m.AddDaemonWindow(title)
+		return m.addDaemonWindow(title, argv, dir)
 	}
 
 	newID := createID()
@@ -492,34 +654,21 @@ func (m *OS) AddWindow(title string) *OS {
 	width := screenWidth / 2
 	height := screenHeight / 2
 
-	// In floating mode, spawn at cursor position
+	// In floating mode, place the window by appearance.window_placement
 	// In tiling mode, position doesn't matter as it will be auto-tiled
-	var x, y int
-	if !m.AutoTiling && m.LastMouseX > 0 && m.LastMouseY > 0 {
-		// Spawn at cursor position, but ensure window stays on screen
-		x = m.LastMouseX
-		y = m.LastMouseY
-
-		// Adjust if window would go off screen
-		if x+width > screenWidth {
-			x = screenWidth - width
-		}
-		if y+height > screenHeight {
-			y = screenHeight - height
-		}
-		if x < 0 {
-			x = 0
-		}
-		if y < 0 {
-			y = 0
-		}
-	} else {
-		// Center the window (default behavior for tiling mode or no cursor position)
-		x = screenWidth / 4
-		y = screenHeight / 4
+	x, y := screenWidth/4, screenHeight/4
+	if !m.AutoTiling {
+		x, y = m.newWindowPosition(nil, screenWidth, screenHeight, width, height)
 	}
 
-	window := terminal.NewWindow(newID, title, x, y, width, height, len(m.Windows), m.WindowExitChan)
+	var window *terminal.Window
+	if m.Headless {
+		// Input to a window without a process goes nowhere
+		window = terminal.NewDaemonWindow(newID, title, x, y, width, height, len(m.Windows), "")
+		window.DaemonWriteFunc = func([]byte) error { return nil }
+	} else {
+		window = terminal.NewCommandWindow(newID, title, x, y, width, height, len(m.Windows), m.WindowExitChan, dir, argv, windowEnv(m.CurrentWorkspace)...)
+	}
 	if window == nil {
 		m.LogError("Failed to create window %s (PTY creation failed)", title)
 		return m // Failed to create window
@@ -532,11 +681,19 @@ func (m *OS) AddWindow(title string) *OS {
 
 	window.Workspace = m.CurrentWorkspace
 
+	m.placeWindow(window)
+	m.LogInfo("Window created successfully: %s (ID: %s, total windows: %d)", title, newID[:8], len(m.Windows))
+
+	return m
+}
+
+// placeWindow adds a window to the window list, focuses it and tiles it in
+// tiling mode.
+func (m *OS) placeWindow(window *terminal.Window) {
 	m.setupKittyPassthrough(window)
 	m.setupSixelPassthrough(window)
 
 	m.Windows = append(m.Windows, window)
-	m.LogInfo("Window created successfully: %s (ID: %s, total windows: %d)", title, newID[:8], len(m.Windows))
 
 	// Focus the new window, which will bring it to the front
 	m.FocusWindow(len(m.Windows) - 1)
@@ -552,8 +709,6 @@ func (m *OS) AddWindow(title string) *OS {
 			m.TileAllWindows()
 		}
 	}
-
-	return m
 }
 
 // UpdateAllWindowThemes updates the terminal colors for all windows when the theme changes
@@ -562,13 +717,38 @@ func (m *OS) UpdateAllWindowThemes() {
 	for _, window := range m.Windows {
 		if window != nil {
 			window.UpdateThemeColors()
+			for _, pane := range append(window.PaneWindows(), window.Tabs...) {
+				if pane != window {
+					pane.UpdateThemeColors()
+				}
+			}
 		}
 	}
+	m.SyncColorsToDaemon()
+}
+
+// SyncColorsToDaemon gives the daemon the colors of the current theme and
+// host, which its terminal emulators answer color queries from applications
+// with. It does nothing outside daemon sessions.
+func (m *OS) SyncColorsToDaemon() {
+	if !m.IsDaemonSession || m.DaemonClient == nil || m.ReadOnly {
+		return
+	}
+	if err := m.DaemonClient.SetColors(session.NewSetColorsPayload(terminal.ThemeColors())); err != nil {
+		m.LogError("[DAEMON] Failed to send colors: %v", err)
+	}
 }
 
 // DeleteWindow removes the window at the specified index.
 // In daemon mode, this also cleans up the daemon-managed PTY.
 func (m *OS) DeleteWindow(i int) *OS {
+	return m.removeWindow(i, false)
+}
+
+// removeWindow removes the window at index i from the window list. Unless
+// keepProcess is set the window is closed; otherwise its process keeps
+// running so the window can be reused, for example as a pane.
+func (m *OS) removeWindow(i int, keepProcess bool) *OS {
 	if len(m.Windows) == 0 || i < 0 || i >= len(m.Windows) {
 		m.LogWarn("Cannot delete window: invalid index %d (total windows: %d)", i, len(m.Windows))
 		return m
@@ -576,13 +756,16 @@ func (m *OS) DeleteWindow(i int) *OS {
 
 	// Clean up window resources
 	deletedWindow := m.Windows[i]
+	m.invalidateDockHeight()
+	if !keepProcess {
+		m.RememberWindowGeometry(deletedWindow)
+	}
 	m.LogInfo("Deleting window: %s (index: %d, ID: %s)", deletedWindow.Title, i, deletedWindow.ID[:8])
 
-	// In daemon mode, clean up daemon-managed PTY
-	if deletedWindow.DaemonMode && deletedWindow.PTYID != "" && m.DaemonClient != nil {
-		m.DaemonClient.UnsubscribePTY(deletedWindow.PTYID)
-		if err := m.DaemonClient.ClosePTY(deletedWindow.PTYID); err != nil {
-			m.LogError("Failed to close daemon PTY: %v", err)
+	// In daemon mode, clean up the daemon-managed PTYs of the window and its panes
+	if !keepProcess {
+		for _, t := range withPanes(deletedWindow) {
+			m.closeDaemonPTY(t)
 		}
 	}
 
@@ -599,7 +782,10 @@ func (m *OS) DeleteWindow(i int) *OS {
 		m.KittyPassthrough.OnWindowClose(deletedWindow.ID)
 	}
 
-	deletedWindow.Close()
+	if !keepProcess {
+		deletedWindow.Close()
+		removeTempFile(deletedWindow)
+	}
 
 	// Remove any animations referencing this window to prevent memory leaks
 	cleanedAnimations := make([]*ui.Animation, 0, len(m.Animations))
@@ -627,6 +813,12 @@ func (m *OS) DeleteWindow(i int) *OS {
 
 	m.Windows = slices.Delete(m.Windows, i, i+1)
 
+	// A group left with a single window is dissolved
+	m.dissolveLoneGroup(deletedWindow.Group)
+
+	// Windows it swallowed come back in its place once focus is settled
+	swallowed := m.releaseSwallowed(deletedWindow)
+
 	// Explicitly clear the deleted window pointer to help GC
 	deletedWindow = nil
 
@@ -677,6 +869,8 @@ func (m *OS) DeleteWindow(i int) *OS {
 		}
 	}
 
+	m.restoreSwallowed(swallowed)
+
 	// Sync state to daemon after window deletion
 	m.SyncStateToDaemon()
 
@@ -699,8 +893,9 @@ func (m *OS) Snap(i int, quarter SnapQuarter) *OS {
 		_, _, targetWidth, targetHeight := m.calculateSnapBounds(quarter)
 
 		// Enforce minimum size
-		targetWidth = max(targetWidth, config.DefaultWindowWidth)
-		targetHeight = max(targetHeight, config.DefaultWindowHeight)
+		minWidth, minHeight := win.MinimumSize()
+		targetWidth = max(targetWidth, minWidth)
+		targetHeight = max(targetHeight, minHeight)
 
 		// Make sure terminal is properly sized even if no animation
 		if win.Width != targetWidth || win.Height != targetHeight {
@@ -716,6 +911,7 @@ func (m *OS) calculateSnapBounds(quarter SnapQuarter) (x, y, width, height int)
 	renderWidth := m.GetRenderWidth()
 	halfWidth := renderWidth / 2
 	halfHeight := usableHeight / 2
+	thirdWidth := renderWidth / 3
 	topMargin := m.GetTopMargin()
 
 	switch quarter {
@@ -733,6 +929,20 @@ func (m *OS) calculateSnapBounds(quarter SnapQuarter) (x, y, width, height int)
 		return halfWidth, halfHeight + topMargin, halfWidth, usableHeight - halfHeight
 	case SnapFullScreen:
 		return 0, topMargin, renderWidth, usableHeight
+	case SnapTop:
+		return 0, topMargin, renderWidth, halfHeight
+	case SnapBottom:
+		return 0, halfHeight + topMargin, renderWidth, usableHeight - halfHeight
+	case SnapLeftThird:
+		return 0, topMargin, thirdWidth, usableHeight
+	case SnapCenterThird:
+		return thirdWidth, topMargin, renderWidth - 2*thirdWidth, usableHeight
+	case SnapRightThird:
+		return renderWidth - thirdWidth, topMargin, thirdWidth, usableHeight
+	case SnapLeftTwoThirds:
+		return 0, topMargin, renderWidth - thirdWidth, usableHeight
+	case SnapRightTwoThirds:
+		return thirdWidth, topMargin, renderWidth - thirdWidth, usableHeight
 	case Unsnap:
 		return renderWidth / 4, usableHeight/4 + topMargin, halfWidth, halfHeight
 	default:
@@ -824,8 +1034,7 @@ func (m *OS) ClampWindowsToView() {
 	usableHeight := m.GetUsableHeight()
 	renderWidth := m.GetRenderWidth()
 	topMargin := m.GetTopMargin()
-	minVisibleX := 20 // Minimum visible horizontal pixels (matches mouse.go)
-	minVisibleY := 3  // Minimum visible vertical rows (matches mouse.go)
+	minVisibleY := 3 // Minimum visible vertical rows (matches mouse.go)
 	clampedCount := 0
 
 	for _, win := range m.Windows {
@@ -856,12 +1065,15 @@ func (m *OS) ClampWindowsToView() {
 			needsResize = true
 		}
 
-		// Clamp X position: ensure at least minVisibleX pixels are visible
-		if win.X+win.Width < minVisibleX {
-			win.X = minVisibleX - win.Width
-		}
-		if win.X > renderWidth-minVisibleX {
-			win.X = renderWidth - minVisibleX
+		// Clamp X position: ensure at least minVisibleX columns are visible
+		// (matches mouse.go)
+		if minVisibleX := config.VisibleColumns(win.Width); minVisibleX > 0 {
+			if win.X+win.Width < minVisibleX {
+				win.X = minVisibleX - win.Width
+			}
+			if win.X > renderWidth-minVisibleX {
+				win.X = renderWidth - minVisibleX
+			}
 		}
 
 		// Clamp Y position: ensure at least minVisibleY rows visible, and can't go behind dock
@@ -900,8 +1112,16 @@ func (m *OS) GetFocusedWindow() *terminal.Window {
 	return nil
 }
 
-// MinimizeWindow minimizes the window at the specified index.
+// MinimizeWindow minimizes the window at the specified index together with
+// the other windows of its group.
 func (m *OS) MinimizeWindow(i int) {
+	for _, j := range m.groupMembers(i) {
+		m.minimizeWindow(j)
+	}
+	m.minimizeWindow(i)
+}
+
+func (m *OS) minimizeWindow(i int) {
 	if i >= 0 && i < len(m.Windows) && !m.Windows[i].Minimized && !m.Windows[i].Minimizing {
 		// Get pointer to the actual window (not a copy)
 		window := m.Windows[i]
@@ -917,6 +1137,7 @@ func (m *OS) MinimizeWindow(i int) {
 		window.Minimized = true
 		window.Minimizing = false
 		window.MinimizeOrder = now.UnixNano() // Track order for dock sorting
+		m.invalidateDockHeight()
 
 		// Set highlight timestamp for dock tab
 		window.MinimizeHighlightUntil = now.Add(1 * time.Second)
@@ -942,10 +1163,20 @@ func (m *OS) MinimizeWindow(i int) {
 	}
 }
 
-// RestoreWindow restores a minimized window at the specified index.
+// RestoreWindow restores a minimized window at the specified index together
+// with the other windows of its group, focusing the window itself.
 func (m *OS) RestoreWindow(i int) {
+	for _, j := range m.groupMembers(i) {
+		m.restoreWindow(j)
+	}
+	m.restoreWindow(i)
+}
+
+func (m *OS) restoreWindow(i int) {
 	if i >= 0 && i < len(m.Windows) && m.Windows[i].Minimized {
 		window := m.Windows[i]
+		window.SwallowedBy = ""
+		m.invalidateDockHeight()
 
 		// In tiling mode, skip animation and let TileAllWindows() handle positioning
 		// This prevents incorrect tiling calculations when restoring multiple windows
@@ -996,7 +1227,7 @@ func (m *OS) RestoreMinimizedByIndex(index int) {
 	// Find the nth minimized window in current workspace
 	minimizedCount := 0
 	for i, window := range m.Windows {
-		if window.Workspace == m.CurrentWorkspace && window.Minimized {
+		if window.Workspace == m.CurrentWorkspace && window.Minimized && window.SwallowedBy == "" {
 			if minimizedCount == index {
 				m.RestoreWindow(i)
 				return
@@ -1026,29 +1257,95 @@ func (m *OS) FocusNextVisibleWindow() {
 // HasMinimizedWindows returns true if there are any minimized windows.
 func (m *OS) HasMinimizedWindows() bool {
 	for _, w := range m.Windows {
-		if w.Workspace == m.CurrentWorkspace && w.Minimized {
+		if w.Workspace == m.CurrentWorkspace && w.Minimized && w.SwallowedBy == "" {
 			return true
 		}
 	}
 	return false
 }
 
+// OpenQuitConfirm shows the quit confirmation, noting which windows run
+// programs other than their shell.
+func (m *OS) OpenQuitConfirm() {
+	m.QuitRunning = nil
+	programs := m.RunningPrograms(m.Windows)
+	for _, w := range m.Windows {
+		if program, ok := programs[w.ID]; ok {
+			m.QuitRunning = append(m.QuitRunning, ansi.Truncate(m.getWindowDisplayName(w), 30, "…")+": "+program)
+		}
+	}
+	m.ShowQuitConfirm = true
+	m.QuitConfirmSelection = 0 // Default to Yes
+}
+
 // GetTopMargin returns the margin at the top (possibly reserved space for the dockbar)
 func (m *OS) GetTopMargin() int {
 	if config.DockbarPosition == "top" {
-		return config.DockHeight
+		return m.DockHeight()
 	}
 
 	return 0
 }
 
+// DockHeight returns the number of rows the dock takes: its separator and
+// bar, and the second row of window pills while they wrap onto one. The
+// rows are counted again only after invalidateDockHeight.
+func (m *OS) DockHeight() int {
+	if !config.DockWrap {
+		return config.DockHeight
+	}
+	if m.inactiveOutput {
+		// Other outputs show other workspaces, and so other pills
+		return config.DockHeight + m.CalculateDockLayout().Rows - 1
+	}
+	if m.dockRows == 0 {
+		m.dockRows = m.CalculateDockLayout().Rows
+	}
+	return config.DockHeight + m.dockRows - 1
+}
+
+// invalidateDockHeight has the dock rows counted again, for changes to the
+// minimized windows of the current workspace or to the screen.
+func (m *OS) invalidateDockHeight() {
+	m.dockRows = 0
+}
+
+// FitWindowsToDock retiles, or clamps the floating windows, when the dock
+// has taken or freed a row since the windows were last laid out. It reports
+// whether the windows were moved.
+func (m *OS) FitWindowsToDock() bool {
+	height := m.DockHeight()
+	if config.DockbarPosition == "hidden" || height == m.fittedDockHeight {
+		return false
+	}
+	first := m.fittedDockHeight == 0
+	m.fittedDockHeight = height
+	if first {
+		return false
+	}
+	if m.AutoTiling {
+		m.TileAllWindows()
+	} else {
+		m.ClampWindowsToView()
+	}
+	m.MarkAllDirty()
+	return true
+}
+
 // GetDockbarContentYPosition returns the Y position of the dockbar
 func (m *OS) GetDockbarContentYPosition() int {
+	return m.DockRowY(0)
+}
+
+// DockRowY returns the Y position of a row of the dockbar. Row 0, with the
+// mode and status, is at the screen edge and the wrapped row of window
+// pills next to it, toward the windows.
+func (m *OS) DockRowY(row int) int {
 	if config.DockbarPosition == "top" {
-		return 0
+		return row
 	}
 
-	return m.Height - 1
+	return m.Height - 1 - row
 }
 
 // GetTimeYPosition returns the Y position of the time display
@@ -1066,13 +1363,22 @@ func (m *OS) GetUsableHeight() int {
 		return m.GetRenderHeight()
 	}
 	// Reserve space for the dock (at top or bottom)
-	return m.GetRenderHeight() - config.DockHeight
+	return m.GetRenderHeight() - m.DockHeight()
 }
 
 // GetRenderWidth returns the width to use for rendering.
 // In multi-client mode, this is the minimum of the terminal width and
-// the effective session width (min of all connected clients).
+// the effective session width (min of all connected clients). On a split
+// screen it is the width of the active output.
 func (m *OS) GetRenderWidth() int {
+	if n := len(m.Outputs); n >= 2 {
+		return m.outputX(m.ActiveOutput+1) - m.outputX(m.ActiveOutput)
+	}
+	return m.screenWidth()
+}
+
+// screenWidth returns the width of the whole screen.
+func (m *OS) screenWidth() int {
 	// If terminal size not yet known, use effective size if available
 	if m.Width == 0 {
 		if m.EffectiveWidth > 0 {
@@ -1105,7 +1411,7 @@ func (m *OS) GetRenderHeight() int {
 	return m.Height
 }
 
-// MarkAllDirty marks all windows as dirty for re-rendering.
+// MarkAllDirty marks all windows, the dock and the sidebar as dirty for re-rendering.
 func (m *OS) MarkAllDirty() {
 	m.terminalMu.Lock()
 	defer m.terminalMu.Unlock()
@@ -1113,6 +1419,7 @@ func (m *OS) MarkAllDirty() {
 		m.Windows[i].Dirty = true
 		m.Windows[i].ContentDirty = true
 	}
+	m.InvalidateChromeCache()
 }
 
 // MarkTerminalsWithNewContent marks terminals that have new content as dirty.
@@ -1156,6 +1463,20 @@ func (m *OS) MarkTerminalsWithNewContent() bool {
 			continue
 		}
 
+		// Drop intermediate frames for windows flooding output; the emulator
+		// keeps absorbing data and the next rendered frame shows the latest state
+		if window.ShouldSkipFrame() {
+			debuglog.Logf(debuglog.Debug, "RENDER", "skipped frame of %s: output flood", window.ID[:8])
+			continue
+		}
+
+		// Keep showing the previous frame while the app is in the middle of a
+		// synchronized update (DEC 2026) so half-drawn screens never appear,
+		// in any of its panes
+		if window.SynchronizedOutput() {
+			continue
+		}
+
 		// Smart content updating with throttling
 		isFocused := i == focusedWindowIndex
 
@@ -1165,8 +1486,9 @@ func (m *OS) MarkTerminalsWithNewContent() bool {
 			hasChanges = true
 		} else {
 			// For background windows, throttle updates to reduce CPU usage
+			// Windows whose emulator reports no damage keep their cached layer
 			window.UpdateCounter++
-			if window.UpdateCounter%3 == 0 { // Update every 3rd cycle (~20Hz instead of 60Hz)
+			if window.UpdateCounter%3 == 0 && window.HasDamage() { // Update every 3rd cycle (~20Hz instead of 60Hz)
 				window.MarkContentDirty()
 				hasChanges = true
 			}
@@ -1281,6 +1603,7 @@ func (m *OS) MoveSelectionCursor(window *terminal.Window, dx, dy int, extending
 			// Start selection
 			window.IsSelecting = true
 			window.SelectionStart = window.SelectionCursor
+			window.SelectionMode = 0 // Character mode
 		}
 		window.SelectionEnd = window.SelectionCursor
 
@@ -1299,6 +1622,7 @@ func (m *OS) MoveSelectionCursor(window *terminal.Window, dx, dy int, extending
 		// Start new selection at cursor position
 		window.SelectionStart = window.SelectionCursor
 		window.SelectionEnd = window.SelectionCursor
+		window.SelectionMode = 0 // Character mode
 		window.IsSelecting = true
 	}
 
@@ -1357,7 +1681,24 @@ func (m *OS) extractSelectedText(window *terminal.Window) string {
 	return strings.Join(selectedLines, "\n")
 }
 
-// Cleanup performs cleanup operations when the application exits.
+// windowEnv returns the environment variables that tell the programs in a
+// new window which workspace it opened on. The window ID is exported by the
+// terminal package itself.
+func windowEnv(workspace int) []string {
+	return []string{"TUIOS_WORKSPACE=" + strconv.Itoa(workspace)}
+}
+
+// Cleanup performs cleanup operations when the application exits. It stops
+// the processes of local windows and waits for them to exit, killing those
+// still running after appearance.shutdown_grace_ms. Windows of a daemon
+// session are left running. Calling it again does nothing.
 func (m *OS) Cleanup() {
-	// Reserved for future cleanup operations
+	m.flushRememberedGeometry()
+	for _, w := range m.Windows {
+		if !w.DaemonMode {
+			w.Close()
+			removeTempFile(w)
+		}
+	}
+	terminal.WaitForShutdowns()
 }
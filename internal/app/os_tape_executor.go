@@ -2,13 +2,15 @@ package app
 
 import (
 	"fmt"
-	"image/color"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/layout"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
 	"github.com/Gaurav-Gosain/tuios/internal/tape"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 	"github.com/Gaurav-Gosain/tuios/internal/theme"
@@ -26,8 +28,17 @@ func (m *OS) getWindowDisplayName(w *terminal.Window) string {
 }
 
 // findWindowsByName returns all windows matching the given name (checks CustomName first, then Title).
+// A "tag:NAME" selector matches every window with that tag.
 func (m *OS) findWindowsByName(name string) []*terminal.Window {
 	var matches []*terminal.Window
+	if tag, ok := strings.CutPrefix(name, tagSelectorPrefix); ok {
+		for _, w := range m.Windows {
+			if windowHasTag(w, strings.TrimPrefix(tag, "#")) {
+				matches = append(matches, w)
+			}
+		}
+		return matches
+	}
 	for _, w := range m.Windows {
 		displayName := m.getWindowDisplayName(w)
 		if displayName == name {
@@ -110,6 +121,88 @@ func (m *OS) CreateNewWindowReturningID(name string) (windowID string, displayNa
 	return newWindow.ID, m.getWindowDisplayName(newWindow), nil
 }
 
+// RunInNewWindow creates a new window and runs command in its shell, after
+// changing to dir when it is not empty. With appearance.remember_geometry the
+// window opens where the command's last floating window was. It returns the
+// window's ID and display name.
+func (m *OS) RunInNewWindow(command, dir string) (windowID string, displayName string, err error) {
+	windowID, displayName, err = m.CreateNewWindowReturningID("")
+	if err != nil {
+		return "", "", err
+	}
+	window := m.Windows[len(m.Windows)-1]
+	window.Command = commandName(command)
+	m.restoreRememberedGeometry(window)
+	if input := shellStartupInput(command, dir); input != "" {
+		if err := m.findTerminal(windowID).SendInput([]byte(input)); err != nil {
+			return "", "", fmt.Errorf("failed to start command: %w", err)
+		}
+	}
+	return windowID, displayName, nil
+}
+
+// shellStartupInput returns the text typed into a new window's shell to
+// change to dir and run command.
+func shellStartupInput(command, dir string) string {
+	var parts []string
+	if dir != "" {
+		parts = append(parts, "cd "+shellQuote(config.ExpandHome(dir)))
+	}
+	if command != "" {
+		parts = append(parts, command)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, " && ") + "\r"
+}
+
+// unnamedProgram stands in for the program running in front of a shell when
+// its name cannot be determined.
+const unnamedProgram = "a program"
+
+// runningProgram returns the name of the program a terminal runs in front
+// of its shell, or an empty string while the shell is in front.
+func (m *OS) runningProgram(pane *terminal.Window) string {
+	return m.RunningPrograms([]*terminal.Window{pane})[pane.ID]
+}
+
+// RunningPrograms returns the name of the program each of the terminals runs
+// in front of its shell by terminal ID, leaving out those where the shell is
+// in front. The daemon, which owns the processes of daemon windows, is asked
+// about those once.
+func (m *OS) RunningPrograms(terminals []*terminal.Window) map[string]string {
+	programs := make(map[string]string)
+	var ptys []session.PTYInfo
+	listed := false
+	for _, pane := range terminals {
+		if !pane.DaemonMode {
+			if name := pane.ForegroundProcessName(); name != "" {
+				programs[pane.ID] = name
+			} else if pane.HasForegroundProcess() {
+				programs[pane.ID] = unnamedProgram
+			}
+			continue
+		}
+		if m.DaemonClient == nil {
+			continue
+		}
+		if !listed {
+			listed = true
+			var err error
+			if ptys, err = m.DaemonClient.ListPTYs(); err != nil {
+				m.LogError("Failed to list the processes of the windows: %v", err)
+			}
+		}
+		for _, pty := range ptys {
+			if pty.ID == pane.PTYID && len(pty.Command) > 0 {
+				programs[pane.ID] = filepath.Base(pty.Command[0])
+			}
+		}
+	}
+	return programs
+}
+
 // getWindowInfo returns detailed information about a window.
 func (m *OS) getWindowInfo(w *terminal.Window, isFocused bool) map[string]any {
 	info := map[string]any{
@@ -119,6 +212,7 @@ func (m *OS) getWindowInfo(w *terminal.Window, isFocused bool) map[string]any {
 		"workspace":      w.Workspace,
 		"focused":        isFocused,
 		"minimized":      w.Minimized,
+		"input_locked":   w.InputLocked,
 		"fullscreen":     w.Width == m.Width && w.Height == m.GetUsableHeight(),
 		"x":              w.X,
 		"y":              w.Y,
@@ -137,6 +231,17 @@ func (m *OS) getWindowInfo(w *terminal.Window, isFocused bool) map[string]any {
 		info["pty_id"] = w.PTYID
 	}
 
+	if w.Icon != "" {
+		info["icon"] = w.Icon
+	}
+
+	if len(w.Tags) > 0 {
+		info["tags"] = w.Tags
+	}
+
+	// Minimum size from window rules or pinned interactively, borders included
+	info["min_width"], info["min_height"] = w.MinimumSize()
+
 	// Get cursor info from terminal emulator
 	if w.Terminal != nil {
 		cursorPos := w.Terminal.CursorPosition()
@@ -250,6 +355,15 @@ func (m *OS) GetSessionInfoData() map[string]any {
 		"num_workspaces":     m.NumWorkspaces,
 	}
 
+	// Workspace names, 1 first, "" for unnamed workspaces
+	if len(m.WorkspaceNames) > 0 {
+		names := make([]string, m.NumWorkspaces)
+		for i := range names {
+			names[i] = m.WorkspaceNames[i+1]
+		}
+		info["workspace_names"] = names
+	}
+
 	// Script playback info
 	if m.ScriptMode {
 		info["script_mode"] = true
@@ -267,6 +381,8 @@ func (m *OS) GetSessionInfoData() map[string]any {
 
 	// Add prefix key state
 	info["prefix_active"] = m.PrefixActive
+	info["key_passthrough"] = m.KeyPassthrough
+	info["nested"] = m.Nested
 
 	return info
 }
@@ -328,8 +444,7 @@ func (m *OS) CloseWindowByName(name string) error {
 
 	// Close in reverse order to avoid index shifting issues
 	for i := len(m.Windows) - 1; i >= 0; i-- {
-		displayName := m.getWindowDisplayName(m.Windows[i])
-		if displayName == name {
+		if slices.Contains(matches, m.Windows[i]) {
 			m.DeleteWindow(i)
 		}
 	}
@@ -431,6 +546,19 @@ func (m *OS) RenameWindowByID(windowID, name string) error {
 	return nil
 }
 
+// SetWindowIconByID sets the icon shown for a window in the dock and sidebar
+// in place of its program's icon. An empty icon restores the program's.
+func (m *OS) SetWindowIconByID(windowID, icon string) error {
+	for _, w := range m.Windows {
+		if w.ID == windowID {
+			w.Icon = icon
+			m.MarkAllDirty()
+			return nil
+		}
+	}
+	return fmt.Errorf("window not found: %s", windowID)
+}
+
 // RenameWindowByName renames a window by its current name. Errors if multiple windows match.
 func (m *OS) RenameWindowByName(oldName, newName string) error {
 	win, err := m.findSingleWindowByName(oldName)
@@ -452,8 +580,12 @@ func (m *OS) MinimizeWindowByID(windowID string) error {
 	return nil
 }
 
-// MinimizeWindowByName minimizes a window by name. Errors if multiple windows match.
+// MinimizeWindowByName minimizes a window by name. Errors if multiple windows match,
+// except for a "tag:" selector, which minimizes every window with the tag.
 func (m *OS) MinimizeWindowByName(name string) error {
+	if strings.HasPrefix(name, tagSelectorPrefix) {
+		return m.forEachTagged(name, m.MinimizeWindowByID)
+	}
 	win, err := m.findSingleWindowByName(name)
 	if err != nil {
 		return err
@@ -473,8 +605,12 @@ func (m *OS) RestoreWindowByID(windowID string) error {
 	return nil
 }
 
-// RestoreWindowByName restores a minimized window by name. Errors if multiple windows match.
+// RestoreWindowByName restores a minimized window by name. Errors if multiple windows match,
+// except for a "tag:" selector, which restores every window with the tag.
 func (m *OS) RestoreWindowByName(name string) error {
+	if strings.HasPrefix(name, tagSelectorPrefix) {
+		return m.forEachTagged(name, m.RestoreWindowByID)
+	}
 	win, err := m.findSingleWindowByName(name)
 	if err != nil {
 		return err
@@ -482,6 +618,20 @@ func (m *OS) RestoreWindowByName(name string) error {
 	return m.RestoreWindowByID(win.ID)
 }
 
+// forEachTagged runs fn on the ID of every window a "tag:" selector picks.
+func (m *OS) forEachTagged(selector string, fn func(windowID string) error) error {
+	matches := m.findWindowsByName(selector)
+	if len(matches) == 0 {
+		return fmt.Errorf("no window found with tag: %s", strings.TrimPrefix(selector, tagSelectorPrefix))
+	}
+	for _, w := range matches {
+		if err := fn(w.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // EnableTiling enables tiling mode.
 func (m *OS) EnableTiling() error {
 	if !m.AutoTiling {
@@ -685,17 +835,7 @@ func (m *OS) SetTheme(themeName string) error {
 	// Update terminal colors for all windows
 	for _, w := range m.Windows {
 		if w != nil && w.Terminal != nil {
-			if theme.IsEnabled() {
-				w.Terminal.SetThemeColors(
-					theme.TerminalFg(),
-					nil, // Always use transparent background
-					theme.TerminalCursor(),
-					theme.GetANSIPalette(),
-				)
-			} else {
-				// Disable theme colors
-				w.Terminal.SetThemeColors(nil, nil, nil, [16]color.Color{})
-			}
+			w.UpdateThemeColors()
 			w.InvalidateCache()
 		}
 	}
@@ -709,6 +849,12 @@ func (m *OS) SetTheme(themeName string) error {
 func (m *OS) SetDockbarPosition(position string) error {
 	switch position {
 	case "top", "bottom", "hidden":
+		if m.CompactMode {
+			// The compact layout keeps the dock hidden until it ends
+			m.compactDockbarPosition = position
+			m.ShowNotification(fmt.Sprintf("Dockbar: %s after the compact layout", position), "info", config.NotificationDuration)
+			return nil
+		}
 		config.DockbarPosition = position
 		m.ShowNotification(fmt.Sprintf("Dockbar: %s", position), "info", config.NotificationDuration)
 		m.MarkAllDirty()
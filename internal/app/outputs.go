@@ -0,0 +1,275 @@
+package app
+
+import (
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// VirtualOutput is one column of a split screen. Each output shows its own
+// workspace with its own dock and focused window, like a monitor of its
+// own. Window coordinates are relative to the output showing the window.
+type VirtualOutput struct {
+	Workspace int        // Workspace shown on the output
+	dockCache layerCache // The output's dock layer
+
+	// Layers of an output other than the active one, rendered in Update
+	windowLayers []*lipgloss.Layer
+	dockLayer    *lipgloss.Layer
+}
+
+// SplitOutputs splits the screen into n side-by-side outputs, or joins it
+// back into one for n < 2. The current workspace stays on the first output
+// and the others show the lowest numbered workspaces left.
+func (m *OS) SplitOutputs(n int) {
+	n = min(n, m.NumWorkspaces)
+	if n < 2 && len(m.Outputs) < 2 || n == len(m.Outputs) {
+		return
+	}
+	oldArea := m.windowArea()
+
+	if n < 2 {
+		for _, out := range m.Outputs {
+			if out.Workspace != m.CurrentWorkspace && m.IsDaemonSession && m.DaemonClient != nil {
+				m.UnsubscribeWorkspaceWindows(out.Workspace)
+			}
+		}
+		m.Outputs = nil
+		m.ActiveOutput = 0
+		m.LogInfo("Joined the virtual outputs")
+	} else {
+		m.Outputs = []*VirtualOutput{{Workspace: m.CurrentWorkspace}}
+		m.ActiveOutput = 0
+		for ws := 1; ws <= m.NumWorkspaces && len(m.Outputs) < n; ws++ {
+			if ws == m.CurrentWorkspace {
+				continue
+			}
+			m.Outputs = append(m.Outputs, &VirtualOutput{Workspace: ws})
+			if m.IsDaemonSession && m.DaemonClient != nil {
+				m.SubscribeWorkspaceWindows(ws)
+			}
+		}
+		m.LogInfo("Split the screen into %d virtual outputs", n)
+	}
+
+	if m.AutoTiling {
+		m.TileAllWindows()
+	}
+	m.fitLayoutToResize(oldArea)
+	m.retileOutputs()
+	m.MarkAllDirty()
+}
+
+// ToggleOutputs splits the screen into config.VirtualOutputs outputs, or
+// joins it again when it is split.
+func (m *OS) ToggleOutputs() {
+	if len(m.Outputs) >= 2 {
+		m.SplitOutputs(1)
+		m.ShowNotification("Virtual outputs joined", "info", config.NotificationDuration)
+		return
+	}
+	m.SplitOutputs(config.VirtualOutputs)
+	if len(m.Outputs) >= 2 {
+		m.ShowNotification("Screen split into virtual outputs", "info", config.NotificationDuration)
+	}
+}
+
+// FocusOutput makes output i the active one, switching to its workspace.
+func (m *OS) FocusOutput(i int) {
+	if len(m.Outputs) < 2 || i < 0 || i >= len(m.Outputs) || i == m.ActiveOutput {
+		return
+	}
+	m.ActiveOutput = i
+	m.SwitchToWorkspace(m.Outputs[i].Workspace)
+	m.MarkAllDirty()
+}
+
+// FocusNextOutput makes the output right of the active one active, wrapping
+// around to the first.
+func (m *OS) FocusNextOutput() {
+	if len(m.Outputs) < 2 {
+		return
+	}
+	m.FocusOutput((m.ActiveOutput + 1) % len(m.Outputs))
+}
+
+// MoveWindowToNextOutput moves the focused window and the other windows of
+// its group to the workspace of the next output, and follows it there.
+func (m *OS) MoveWindowToNextOutput() {
+	if len(m.Outputs) < 2 || m.FocusedWindow < 0 || m.FocusedWindow >= len(m.Windows) {
+		return
+	}
+	index := m.FocusedWindow
+	next := (m.ActiveOutput + 1) % len(m.Outputs)
+	workspace := m.Outputs[next].Workspace
+
+	m.MoveWindowToWorkspace(index, workspace)
+	m.WorkspaceFocus[workspace] = index
+	m.FocusOutput(next)
+	m.FocusWindow(index)
+	if m.AutoTiling {
+		m.TileVisibleWorkspaceWindows()
+		m.SaveCurrentLayout()
+		m.WorkspaceHasCustom[m.CurrentWorkspace] = false
+	}
+}
+
+// WorkspaceVisible reports whether a workspace is shown: it is the current
+// one, or another output of a split screen shows it.
+func (m *OS) WorkspaceVisible(workspace int) bool {
+	if workspace == m.CurrentWorkspace {
+		return true
+	}
+	for _, out := range m.Outputs {
+		if out.Workspace == workspace {
+			return true
+		}
+	}
+	return false
+}
+
+// showOnActiveOutput records that the active output now shows workspace.
+// An output that already showed it takes over the workspace being left,
+// so every workspace stays on at most one output. It reports whether the
+// workspace being left is still shown on another output.
+func (m *OS) showOnActiveOutput(workspace, left int) bool {
+	if len(m.Outputs) < 2 {
+		return false
+	}
+	stillShown := false
+	for i, out := range m.Outputs {
+		if i == m.ActiveOutput {
+			continue
+		}
+		if out.Workspace == workspace {
+			out.Workspace = left
+			for _, w := range m.Windows {
+				if w.Workspace == left {
+					w.MarkPositionDirty()
+				}
+			}
+		}
+		stillShown = stillShown || out.Workspace == left
+	}
+	m.Outputs[m.ActiveOutput].Workspace = workspace
+	return stillShown
+}
+
+// OutputAt returns the output showing screen column x.
+func (m *OS) OutputAt(x int) int {
+	for i := len(m.Outputs) - 1; i > 0; i-- {
+		if x >= m.outputX(i) {
+			return i
+		}
+	}
+	return 0
+}
+
+// outputX returns the screen column output i starts at; outputX(n) is the
+// width of the screen split into n outputs.
+func (m *OS) outputX(i int) int {
+	if len(m.Outputs) < 2 {
+		return 0
+	}
+	return i * m.screenWidth() / len(m.Outputs)
+}
+
+// ScreenX converts a column of the active output to a screen column.
+func (m *OS) ScreenX(x int) int {
+	return x + m.outputX(m.ActiveOutput)
+}
+
+// OutputMouseX converts the screen column of a mouse event to a column of
+// the active output. A click first makes the output under it active, unless
+// it lands on the sidebar.
+func (m *OS) OutputMouseX(x int, click bool) int {
+	if len(m.Outputs) < 2 {
+		return x
+	}
+	if click && !(m.SidebarVisible && x < m.GetSidebarWidth()) {
+		m.FocusOutput(m.OutputAt(x))
+	}
+	return x - m.outputX(m.ActiveOutput)
+}
+
+// withOutput runs fn as if output i were the active one: its workspace is
+// the current one and its focused window is focused.
+func (m *OS) withOutput(i int, fn func()) {
+	if i == m.ActiveOutput || i < 0 || i >= len(m.Outputs) {
+		fn()
+		return
+	}
+	workspace, focused, active := m.CurrentWorkspace, m.FocusedWindow, m.ActiveOutput
+	shown := m.Outputs[i].Workspace
+
+	m.swapWorkspaceTiling(workspace, shown)
+	m.CurrentWorkspace = shown
+	m.FocusedWindow = m.outputFocus(shown)
+	m.ActiveOutput = i
+	m.inactiveOutput = true
+	defer func() {
+		m.inactiveOutput = false
+		m.ActiveOutput = active
+		m.FocusedWindow = focused
+		m.CurrentWorkspace = workspace
+		m.swapWorkspaceTiling(shown, workspace)
+	}()
+	fn()
+}
+
+// renderInactiveOutputs renders the windows and docks of the outputs other
+// than the active one for GetCanvas to draw. Each is rendered with the
+// state switched to its output, so this runs at the end of Update, never
+// while a frame is drawn.
+func (m *OS) renderInactiveOutputs() {
+	for i, out := range m.Outputs {
+		out.windowLayers, out.dockLayer = out.windowLayers[:0], nil
+		if i == m.ActiveOutput {
+			continue
+		}
+		m.withOutput(i, func() {
+			out.windowLayers = m.appendWindowLayers(out.windowLayers)
+			if config.DockbarPosition != "hidden" {
+				out.dockLayer = m.renderDock()
+			}
+		})
+	}
+}
+
+// outputFocus returns the window focused on the output showing workspace:
+// the one last focused there, or else its first visible window.
+func (m *OS) outputFocus(workspace int) int {
+	if i, ok := m.WorkspaceFocus[workspace]; ok && i >= 0 && i < len(m.Windows) {
+		if w := m.Windows[i]; w.Workspace == workspace && !w.Minimized {
+			return i
+		}
+	}
+	for i, w := range m.Windows {
+		if w.Workspace == workspace && !w.Minimized && !w.Minimizing {
+			return i
+		}
+	}
+	return -1
+}
+
+// retileOutputs retiles the tiled workspaces shown on the outputs other
+// than the active one.
+func (m *OS) retileOutputs() {
+	for i := range m.Outputs {
+		if i == m.ActiveOutput {
+			continue
+		}
+		m.withOutput(i, func() {
+			if m.AutoTiling {
+				m.TileAllWindows()
+			}
+		})
+	}
+}
+
+// outputDockCache returns the cache of the active output's dock.
+func (m *OS) outputDockCache() *layerCache {
+	if len(m.Outputs) >= 2 {
+		return &m.Outputs[m.ActiveOutput].dockCache
+	}
+	return &m.dockCache
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestVirtualOutputs(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40+config.DockHeight
+	m.NumWorkspaces = 9
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", X: 10, Y: 5, Width: 40, Height: 20, Workspace: 1, Terminal: vt.NewEmulator(38, 18)},
+		{ID: "window-two-0000", X: 0, Y: 0, Width: 30, Height: 10, Workspace: 2, Terminal: vt.NewEmulator(28, 8)},
+	}
+	m.FocusedWindow = 0
+
+	m.SplitOutputs(2)
+	if len(m.Outputs) != 2 || m.Outputs[0].Workspace != 1 || m.Outputs[1].Workspace != 2 {
+		t.Fatalf("expected workspaces 1 and 2 on the outputs, got %+v %+v", m.Outputs[0], m.Outputs[1])
+	}
+	if width := m.GetRenderWidth(); width != 60 {
+		t.Errorf("expected outputs 60 columns wide, got %d", width)
+	}
+	if w := m.Windows[0]; w.X != 5 || w.Width != 20 {
+		t.Errorf("expected the floating window scaled to the output, got x=%d width=%d", w.X, w.Width)
+	}
+
+	// A click makes the output under it active and is relative to it
+	if x := m.OutputMouseX(90, true); x != 30 {
+		t.Errorf("expected column 90 to be column 30 of the second output, got %d", x)
+	}
+	if m.ActiveOutput != 1 || m.CurrentWorkspace != 2 || m.FocusedWindow != 1 {
+		t.Errorf("expected the second output active on workspace 2, got output %d workspace %d focus %d",
+			m.ActiveOutput, m.CurrentWorkspace, m.FocusedWindow)
+	}
+
+	// Switching to a workspace shown on the other output swaps the two
+	m.SwitchToWorkspace(1)
+	if m.Outputs[0].Workspace != 2 || m.Outputs[1].Workspace != 1 {
+		t.Errorf("expected the outputs to swap workspaces, got %d and %d", m.Outputs[0].Workspace, m.Outputs[1].Workspace)
+	}
+	if !m.WorkspaceVisible(2) || m.WorkspaceVisible(3) {
+		t.Error("expected only workspaces 1 and 2 visible")
+	}
+
+	m.renderInactiveOutputs()
+	m.GetCanvas(true)
+	if m.CurrentWorkspace != 1 || m.ActiveOutput != 1 {
+		t.Errorf("expected drawing to leave output 1 on workspace 1, got output %d workspace %d", m.ActiveOutput, m.CurrentWorkspace)
+	}
+	if layer := m.Windows[0].CachedLayer; layer == nil || layer.GetX() != 65 {
+		t.Errorf("expected workspace 1's window drawn on the second output, got %v", layer)
+	}
+	if layer := m.Windows[1].CachedLayer; layer == nil || layer.GetX() != 0 {
+		t.Errorf("expected workspace 2's window drawn on the first output, got %v", layer)
+	}
+
+	m.SplitOutputs(1)
+	if m.Outputs != nil || m.GetRenderWidth() != 120 || m.CurrentWorkspace != 1 {
+		t.Errorf("expected the screen joined on workspace 1, got %d outputs on workspace %d", len(m.Outputs), m.CurrentWorkspace)
+	}
+}
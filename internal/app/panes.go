@@ -0,0 +1,322 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/layout"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// GetFocusedPane returns the terminal that receives input in the focused
+// window: its active pane when the window is split, otherwise the window.
+func (m *OS) GetFocusedPane() *terminal.Window {
+	window := m.GetFocusedWindow()
+	if window == nil {
+		return nil
+	}
+	return window.ActivePane()
+}
+
+// SplitFocusedPaneHorizontal splits the focused pane into top and bottom
+// panes, starting a new shell in the bottom one.
+func (m *OS) SplitFocusedPaneHorizontal() {
+	m.splitFocusedPane(layout.SplitHorizontal)
+}
+
+// SplitFocusedPaneVertical splits the focused pane into left and right panes,
+// starting a new shell in the right one.
+func (m *OS) SplitFocusedPaneVertical() {
+	m.splitFocusedPane(layout.SplitVertical)
+}
+
+func (m *OS) splitFocusedPane(split layout.SplitType) {
+	window := m.GetFocusedWindow()
+	if window == nil {
+		return
+	}
+	if window.HasTabs() {
+		m.ShowNotification("Tabbed windows cannot be split into panes", "warning", config.NotificationDuration)
+		return
+	}
+	if window.DaemonMode && (m.DaemonClient == nil || m.ReadOnly) {
+		return
+	}
+
+	active := window.ActivePane()
+	rect := window.ActivePaneRect()
+	id := createID()
+	title := fmt.Sprintf("Terminal %s", id[:8])
+	var pane *terminal.Window
+	if window.DaemonMode {
+		pane = m.newDaemonPane(id, title, rect.W, rect.H, window.Workspace)
+	} else {
+		pane = terminal.NewWindowInDir(id, title, 0, 0, rect.W+2, rect.H+2, 0, m.WindowExitChan, active.WorkingDirectory(), windowEnv(window.Workspace)...)
+	}
+	if pane == nil {
+		m.LogError("Failed to create pane in window %s (PTY creation failed)", window.ID[:8])
+		return
+	}
+
+	caps := GetHostCapabilities()
+	if caps.CellWidth > 0 && caps.CellHeight > 0 {
+		pane.SetCellPixelDimensions(caps.CellWidth, caps.CellHeight)
+	}
+	pane.Workspace = window.Workspace
+
+	window.SplitPane(pane, split)
+	if pane.DaemonMode {
+		m.subscribeToPTY(pane)
+	}
+	m.LogInfo("Split window %s into %d panes", window.ID[:8], len(window.Panes.Panes))
+}
+
+// newDaemonPane starts a shell on a new daemon PTY of cols x rows cells for a
+// pane with the given ID, or returns nil when the daemon cannot create it.
+func (m *OS) newDaemonPane(id, title string, cols, rows, workspace int) *terminal.Window {
+	env := append([]string{"TUIOS_WINDOW_ID=" + id}, windowEnv(workspace)...)
+	ptyID, err := m.DaemonClient.CreatePTY(title, cols, rows, env...)
+	if err != nil {
+		m.LogError("[DAEMON] Failed to create PTY in daemon: %v", err)
+		return nil
+	}
+	pane := terminal.NewDaemonWindow(id, title, 0, 0, cols+2, rows+2, 0, ptyID)
+	if pane == nil {
+		_ = m.DaemonClient.ClosePTY(ptyID)
+		return nil
+	}
+	m.connectDaemonTerminal(pane)
+	return pane
+}
+
+// restoreDaemonPanes splits a window restored from the session state into
+// its saved panes, each on the daemon PTY it was left on. The caller connects
+// the pane terminals like the window's own.
+func (m *OS) restoreDaemonPanes(window *terminal.Window, ws *session.WindowState) {
+	if len(ws.Panes) < 2 || ws.PaneTree == nil {
+		return
+	}
+	caps := GetHostCapabilities()
+	panes := make(map[int]*terminal.Window, len(ws.Panes))
+	for _, ps := range ws.Panes {
+		if ps.WindowID == window.ID {
+			panes[ps.ID] = window
+			continue
+		}
+		if ps.WindowID == "" || ps.PTYID == "" {
+			continue
+		}
+		pane := terminal.NewDaemonWindow(ps.WindowID, ps.Title, 0, 0, window.Width, window.Height, 0, ps.PTYID)
+		if pane == nil {
+			continue
+		}
+		if caps.CellWidth > 0 && caps.CellHeight > 0 {
+			pane.SetCellPixelDimensions(caps.CellWidth, caps.CellHeight)
+		}
+		pane.Workspace = window.Workspace
+		panes[ps.ID] = pane
+	}
+	tree := (&layout.SerializedBSPTree{Root: convertSessionBSPNode(ws.PaneTree)}).Deserialize()
+	window.RestorePanes(tree, panes, ws.ActivePane)
+}
+
+// closeDaemonPTY unsubscribes from and closes the daemon PTY of a window or
+// pane terminal. Local terminals are left alone.
+func (m *OS) closeDaemonPTY(t *terminal.Window) {
+	if !t.DaemonMode || t.PTYID == "" || m.DaemonClient == nil {
+		return
+	}
+	m.DaemonClient.UnsubscribePTY(t.PTYID)
+	if err := m.DaemonClient.ClosePTY(t.PTYID); err != nil {
+		m.LogError("Failed to close daemon PTY: %v", err)
+	}
+}
+
+// withPanes returns the window's terminal followed by those of its other
+// panes.
+func withPanes(w *terminal.Window) []*terminal.Window {
+	terminals := []*terminal.Window{w}
+	for _, pane := range w.PaneWindows() {
+		if pane != w {
+			terminals = append(terminals, pane)
+		}
+	}
+	return terminals
+}
+
+// terminalsWithPanes returns the terminal of every window followed by those
+// of its other panes.
+func (m *OS) terminalsWithPanes() []*terminal.Window {
+	var terminals []*terminal.Window
+	for _, w := range m.Windows {
+		terminals = append(terminals, withPanes(w)...)
+	}
+	return terminals
+}
+
+// CloseFocusedPane closes the active pane or tab of the focused window.
+// Closing the last pane or tab closes the window.
+func (m *OS) CloseFocusedPane() {
+	window := m.GetFocusedWindow()
+	if window == nil {
+		return
+	}
+	if window.HasTabs() {
+		window.CloseTab(window.ActiveTab())
+		if !window.HasTabs() && window.ProcessExited {
+			m.DeleteWindow(m.FocusedWindow)
+		}
+		return
+	}
+	if !window.HasPanes() {
+		m.DeleteWindow(m.FocusedWindow)
+		return
+	}
+	m.closeDaemonPTY(window.ActivePane())
+	window.ClosePane(window.ActivePane())
+	if !window.HasPanes() && window.ProcessExited {
+		m.DeleteWindow(m.FocusedWindow)
+	}
+}
+
+// CycleFocusedPane activates the next (delta > 0) or previous (delta < 0)
+// pane of the focused window.
+func (m *OS) CycleFocusedPane(delta int) {
+	if window := m.GetFocusedWindow(); window != nil {
+		window.CyclePane(delta)
+	}
+}
+
+// FocusPaneDirection activates the pane beside the focused pane in the
+// direction (dx, dy).
+func (m *OS) FocusPaneDirection(dx, dy int) {
+	if window := m.GetFocusedWindow(); window != nil {
+		window.FocusPaneDirection(dx, dy)
+	}
+}
+
+// ResizeFocusedPane moves the split next to the focused pane in the
+// direction (dx, dy).
+func (m *OS) ResizeFocusedPane(dx, dy int) {
+	if window := m.GetFocusedWindow(); window != nil {
+		window.ResizePane(dx, dy, config.PaneResizeStep)
+	}
+}
+
+// BreakFocusedPane moves the focused pane out of its window into a new
+// window of its own, keeping its process running.
+func (m *OS) BreakFocusedPane() {
+	window := m.GetFocusedWindow()
+	if window == nil || !window.HasPanes() {
+		m.ShowNotification("Window has no panes to break out", "info", config.NotificationDuration)
+		return
+	}
+
+	// Breaking out the window's own terminal leaves it in this window and
+	// moves the other panes to the new one instead
+	keepFocus := window.ActivePane() == window
+	broken := window.BreakPane()
+
+	broken.X = window.X + 2
+	broken.Y = window.Y + 1
+	broken.Z = len(m.Windows)
+	broken.Workspace = window.Workspace
+	broken.Resize(window.Width, window.Height)
+	m.placeWindow(broken)
+
+	if keepFocus {
+		for i, w := range m.Windows {
+			if w == window {
+				m.FocusWindow(i)
+				break
+			}
+		}
+	}
+	m.LogInfo("Broke pane out of window %s into window %s", window.ID[:8], broken.ID[:8])
+}
+
+// JoinWindowAsPane moves the window at index into the window with ID
+// targetID as a new pane, keeping its process running.
+func (m *OS) JoinWindowAsPane(index int, targetID string) {
+	if index < 0 || index >= len(m.Windows) {
+		return
+	}
+	source := m.Windows[index]
+	var target *terminal.Window
+	for _, w := range m.Windows {
+		if w.ID == targetID {
+			target = w
+			break
+		}
+	}
+
+	switch {
+	case target == nil:
+		return
+	case source == target:
+		m.ShowNotification("Pick another window to merge", "info", config.NotificationDuration)
+		return
+	case source.HasPanes() || source.HasTabs():
+		m.ShowNotification("Windows with panes or tabs cannot be merged", "warning", config.NotificationDuration)
+		return
+	case target.HasTabs():
+		m.ShowNotification("Tabbed windows cannot be split into panes", "warning", config.NotificationDuration)
+		return
+	}
+
+	m.removeWindow(index, true)
+
+	// Images are placed relative to the window list, not to panes
+	source.KittyPassthroughFunc = nil
+	source.SixelPassthroughFunc = nil
+	source.Group = ""
+	source.Minimized = false
+	source.Minimizing = false
+	source.IsBeingManipulated = false
+
+	// Split along the longer side of the focused pane; cells are about
+	// twice as tall as they are wide
+	split := layout.SplitHorizontal
+	if rect := target.ActivePaneRect(); rect.W >= rect.H*2 {
+		split = layout.SplitVertical
+	}
+	target.SplitPane(source, split)
+
+	for i, w := range m.Windows {
+		if w == target {
+			m.FocusWindow(i)
+			break
+		}
+	}
+	m.LogInfo("Merged window %s into window %s as a pane", source.ID[:8], target.ID[:8])
+}
+
+// closeExitedPanes removes the panes and tabs of the window whose processes
+// exited.
+func (m *OS) closeExitedPanes(window *terminal.Window) {
+	for _, pane := range window.PaneWindows() {
+		if pane.ProcessExited {
+			window.ClosePane(pane)
+		}
+	}
+	for _, tab := range slices.Clone(window.Tabs) {
+		if tab.ProcessExited {
+			window.CloseTab(tab)
+		}
+	}
+}
+
+// findTerminal returns the window or pane with the given ID, or nil.
+func (m *OS) findTerminal(id string) *terminal.Window {
+	for _, w := range m.Windows {
+		if w.ID == id {
+			return w
+		}
+		if pane := w.FindPane(id); pane != nil {
+			return pane
+		}
+	}
+	return nil
+}
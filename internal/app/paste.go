@@ -0,0 +1,160 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// PasteChunkMsg reports the progress of a paste that is streamed into a
+// window's PTY in chunks.
+type PasteChunkMsg struct {
+	WindowID  string
+	Remaining []byte // Bytes still to be written
+	Total     int    // Size of the original clipboard content
+	Err       error  // Set if writing a chunk failed
+}
+
+// PasteNeedsConfirmation reports whether pasting content into the window
+// should be confirmed first: multi-line content typed straight into a shell
+// prompt without bracketed paste would execute every line immediately.
+func PasteNeedsConfirmation(window *terminal.Window, content string) bool {
+	if !config.ConfirmMultilinePaste || window == nil {
+		return false
+	}
+	if !strings.ContainsAny(strings.TrimRight(content, "\r\n"), "\r\n") {
+		return false
+	}
+	if window.IsAltScreen || (window.Terminal != nil && window.Terminal.BracketedPasteEnabled()) {
+		return false
+	}
+	return !window.HasForegroundProcess()
+}
+
+// Paste sends content to the window, asking for confirmation first when
+// PasteNeedsConfirmation reports the paste as risky.
+func (m *OS) Paste(window *terminal.Window, content string) tea.Cmd {
+	if window == nil {
+		return nil
+	}
+	if PasteNeedsConfirmation(window, content) {
+		m.ShowPasteConfirm = true
+		m.PasteConfirmSelection = 1 // Default to No
+		m.PendingPaste = content
+		m.PendingPasteWindowID = window.ID
+		return nil
+	}
+	return m.sendPaste(window, content)
+}
+
+// ConfirmPendingPaste sends the paste that is waiting for confirmation.
+func (m *OS) ConfirmPendingPaste() tea.Cmd {
+	content, windowID := m.PendingPaste, m.PendingPasteWindowID
+	m.CancelPendingPaste()
+
+	if w := m.findTerminal(windowID); w != nil {
+		return m.sendPaste(w, content)
+	}
+	m.ShowNotification("Paste target window is gone", "warning", config.NotificationDuration)
+	return nil
+}
+
+// CancelPendingPaste discards the paste that is waiting for confirmation.
+func (m *OS) CancelPendingPaste() {
+	m.ShowPasteConfirm = false
+	m.PasteConfirmSelection = 0
+	m.PendingPaste = ""
+	m.PendingPasteWindowID = ""
+}
+
+// sendPaste writes content to the window's PTY. Content larger than
+// config.PasteChunkSize is streamed in chunks from a command so the UI stays
+// responsive while the application consumes the input.
+func (m *OS) sendPaste(window *terminal.Window, content string) tea.Cmd {
+	// Build paste content with bracketed paste sequences if the app has enabled it.
+	// We use SendInput() instead of Terminal.Paste() because in daemon mode,
+	// Terminal.Paste() writes to an internal pipe that gets drained by
+	// StartDaemonResponseReader() - the data never reaches the PTY.
+	// SendInput() properly routes through DaemonWriteFunc in daemon mode.
+	data := content
+	if window.Terminal != nil && window.Terminal.BracketedPasteEnabled() {
+		data = "\x1b[200~" + data + "\x1b[201~"
+	}
+
+	if len(data) <= config.PasteChunkSize {
+		if err := window.SendInput([]byte(data)); err != nil {
+			m.ShowNotification("Paste failed", "error", config.NotificationDuration)
+			return nil
+		}
+		m.ShowNotification(fmt.Sprintf("Pasted %d characters", len(content)), "success", config.NotificationDuration)
+		return nil
+	}
+
+	m.ShowNotification(fmt.Sprintf("Pasting %d characters...", len(content)), "info", config.NotificationDuration)
+	return pasteChunkCmd(window, []byte(data), len(content))
+}
+
+// pasteChunkCmd writes the next chunk of a paste. PTY writes block while the
+// application is not reading, which throttles the stream without stalling
+// the update loop.
+func pasteChunkCmd(window *terminal.Window, data []byte, total int) tea.Cmd {
+	return func() tea.Msg {
+		n := min(len(data), config.PasteChunkSize)
+		if err := window.SendInput(data[:n]); err != nil {
+			return PasteChunkMsg{WindowID: window.ID, Total: total, Err: err}
+		}
+		return PasteChunkMsg{WindowID: window.ID, Remaining: data[n:], Total: total}
+	}
+}
+
+// handlePasteChunk schedules the next chunk of a streamed paste.
+func (m *OS) handlePasteChunk(msg PasteChunkMsg) tea.Cmd {
+	if msg.Err != nil {
+		m.LogError("Paste into window %s failed: %v", msg.WindowID, msg.Err)
+		m.ShowNotification("Paste failed", "error", config.NotificationDuration)
+		return nil
+	}
+	if len(msg.Remaining) == 0 {
+		m.ShowNotification(fmt.Sprintf("Pasted %d characters", msg.Total), "success", config.NotificationDuration)
+		return nil
+	}
+
+	window := m.findTerminal(msg.WindowID)
+	if window == nil {
+		return nil
+	}
+
+	next := pasteChunkCmd(window, msg.Remaining, msg.Total)
+	return tea.Tick(config.PasteChunkInterval, func(time.Time) tea.Msg {
+		return next()
+	})
+}
+
+// MouseSelected records text selected with the mouse as the selection a
+// middle click pastes. With config.CopyOnSelect it is also copied to the
+// clipboard and the host's primary selection right away; otherwise it
+// returns nil.
+func (m *OS) MouseSelected(text string) tea.Cmd {
+	m.PrimarySelection = text
+	if !config.CopyOnSelect {
+		return nil
+	}
+	return tea.Batch(tea.SetPrimaryClipboard(text), m.CopyToClipboard(text, fmt.Sprintf("Copied %d chars", len(text))))
+}
+
+// CopyToClipboard copies text to the host clipboard through OSC 52 and
+// shows message as a notification. When the host is not known to support
+// OSC 52 the copy is still sent, but the notification says it may not have
+// arrived, rather than reporting a success that never happened.
+func (m *OS) CopyToClipboard(text, message string) tea.Cmd {
+	if GetHostCapabilities().Clipboard {
+		m.ShowNotification(message, "success", config.NotificationDuration)
+	} else {
+		m.ShowNotification(message+" (terminal may lack OSC 52 support, see tuios doctor)", "warning", config.NotificationDuration)
+	}
+	return tea.SetClipboard(text)
+}
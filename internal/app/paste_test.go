@@ -0,0 +1,90 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestPasteNeedsConfirmation(t *testing.T) {
+	shell := &terminal.Window{ID: "shell", Terminal: vt.NewEmulator(80, 24)}
+
+	bracketed := &terminal.Window{ID: "bracketed", Terminal: vt.NewEmulator(80, 24)}
+	_, _ = bracketed.Terminal.Write([]byte("\x1b[?2004h"))
+
+	fullscreen := &terminal.Window{ID: "fullscreen", Terminal: vt.NewEmulator(80, 24), IsAltScreen: true}
+
+	tests := []struct {
+		name    string
+		window  *terminal.Window
+		content string
+		want    bool
+	}{
+		{"single line", shell, "echo hi", false},
+		{"single line with trailing newline", shell, "echo hi\n", false},
+		{"multi line into shell", shell, "echo one\necho two", true},
+		{"carriage returns into shell", shell, "echo one\recho two", true},
+		{"multi line with bracketed paste", bracketed, "echo one\necho two", false},
+		{"multi line into alt screen app", fullscreen, "line one\nline two", false},
+		{"nil window", nil, "a\nb", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PasteNeedsConfirmation(tt.window, tt.content); got != tt.want {
+				t.Errorf("PasteNeedsConfirmation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("disabled by config", func(t *testing.T) {
+		prev := config.ConfirmMultilinePaste
+		config.ConfirmMultilinePaste = false
+		defer func() { config.ConfirmMultilinePaste = prev }()
+
+		if PasteNeedsConfirmation(shell, "a\nb") {
+			t.Error("expected no confirmation when disabled")
+		}
+	})
+}
+
+func TestPasteQueuesConfirmation(t *testing.T) {
+	m := &OS{}
+	shell := &terminal.Window{ID: "shell", Terminal: vt.NewEmulator(80, 24)}
+	m.Windows = []*terminal.Window{shell}
+
+	if cmd := m.Paste(shell, "rm -rf build\nmake"); cmd != nil {
+		t.Error("expected no command while confirmation is pending")
+	}
+	if !m.ShowPasteConfirm || m.PendingPasteWindowID != "shell" {
+		t.Fatal("expected paste confirmation dialog for shell window")
+	}
+	if m.PasteConfirmSelection != 1 {
+		t.Error("expected confirmation to default to No")
+	}
+
+	m.CancelPendingPaste()
+	if m.ShowPasteConfirm || m.PendingPaste != "" {
+		t.Error("expected pending paste to be cleared after cancel")
+	}
+}
+
+func TestCopyToClipboardWarnsWithoutOSC52(t *testing.T) {
+	defer ResetHostCapabilities()
+	for _, supported := range []bool{true, false} {
+		SetClientCapabilities(&HostCapabilities{Clipboard: supported})
+		m := NewOS(OSOptions{})
+		if cmd := m.CopyToClipboard("text", "Copied"); cmd == nil {
+			t.Fatal("expected the copy to be sent either way")
+		}
+		want := "success"
+		if !supported {
+			want = "warning"
+		}
+		if len(m.Notifications) != 1 || m.Notifications[0].Type != want {
+			t.Errorf("clipboard supported=%v: got notifications %+v, want one %s", supported, m.Notifications, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// TogglePiP shrinks the focused window into a picture-in-picture miniature
+// pinned to the bottom-right corner, above all other windows. When the
+// current workspace already has one, it is popped back to its previous size
+// and focused instead, so the same key brings it back from any window.
+func (m *OS) TogglePiP() {
+	for i, w := range m.Windows {
+		if w.PiP && w.Workspace == m.CurrentWorkspace {
+			m.exitPiP(i)
+			return
+		}
+	}
+
+	window := m.GetFocusedWindow()
+	if window == nil || window.Minimized {
+		return
+	}
+
+	window.PrePiPX, window.PrePiPY = window.X, window.Y
+	window.PrePiPWidth, window.PrePiPHeight = window.Width, window.Height
+	window.PiP = true
+
+	width := min(config.PiPWidth, m.GetRenderWidth())
+	height := min(config.PiPHeight, m.GetUsableHeight())
+	window.X = m.GetRenderWidth() - width
+	window.Y = m.GetTopMargin() + m.GetUsableHeight() - height
+	window.Resize(width, height)
+	window.MarkPositionDirty()
+
+	// Keep working in the windows behind the miniature
+	for i, w := range m.Windows {
+		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing && !w.PiP {
+			m.FocusWindow(i)
+			break
+		}
+	}
+	if m.AutoTiling {
+		m.TileAllWindows()
+	}
+	m.LogInfo("Window %s moved to picture-in-picture", window.ID[:8])
+}
+
+// exitPiP returns the picture-in-picture window at index to the size and
+// position it had before and focuses it.
+func (m *OS) exitPiP(i int) {
+	window := m.Windows[i]
+	window.PiP = false
+	window.X, window.Y = window.PrePiPX, window.PrePiPY
+	window.Resize(window.PrePiPWidth, window.PrePiPHeight)
+	window.MarkPositionDirty()
+
+	m.FocusWindow(i)
+	if m.AutoTiling {
+		m.TileAllWindows()
+	}
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// Cascaded windows open this far below and to the right of the topmost one.
+const (
+	cascadeOffsetX = 2
+	cascadeOffsetY = 1
+)
+
+// newWindowPosition returns where a new floating window of the given size
+// opens on a screen of the given usable size, following
+// appearance.window_placement. The window is kept on screen. placing is the
+// window being placed when it is already in the window list, or nil.
+func (m *OS) newWindowPosition(placing *terminal.Window, screenWidth, screenHeight, width, height int) (int, int) {
+	top := m.GetTopMargin()
+	x, y := (screenWidth-width)/2, top+(screenHeight-height)/2
+
+	switch config.WindowPlacement {
+	case "center":
+	case "cascade":
+		x, y = m.cascadePosition(placing, screenWidth, screenHeight, width, height)
+	case "smart":
+		x, y = m.smartPosition(placing, screenWidth, screenHeight, width, height, x, y)
+	default:
+		if m.LastMouseX > 0 && m.LastMouseY > 0 {
+			x, y = m.LastMouseX, m.LastMouseY
+		}
+	}
+
+	x = max(0, min(x, screenWidth-width))
+	y = max(top, min(y, top+screenHeight-height))
+	return x, y
+}
+
+// placementObstacles returns the windows a new window should avoid: the
+// visible windows of the current workspace other than placing. In tiling
+// mode only floating windows count, since the tiles cover the whole screen.
+func (m *OS) placementObstacles(placing *terminal.Window) []*terminal.Window {
+	var windows []*terminal.Window
+	for _, w := range m.Windows {
+		if w == placing || w.Workspace != m.CurrentWorkspace || w.Minimized || w.Minimizing {
+			continue
+		}
+		if !m.AutoTiling || w.Floating {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// cascadePosition offsets the new window from the topmost visible window,
+// starting over at the top left corner when it would leave the screen.
+func (m *OS) cascadePosition(placing *terminal.Window, screenWidth, screenHeight, width, height int) (int, int) {
+	top := m.GetTopMargin()
+	var topmost *terminal.Window
+	for _, w := range m.placementObstacles(placing) {
+		if topmost == nil || w.Z > topmost.Z {
+			topmost = w
+		}
+	}
+	if topmost == nil {
+		return 0, top
+	}
+	x, y := topmost.X+cascadeOffsetX, topmost.Y+cascadeOffsetY
+	if x+width > screenWidth || y+height > top+screenHeight {
+		return 0, top
+	}
+	return x, y
+}
+
+// smartPosition returns the position where the new window overlaps the
+// visible windows least. Candidates are the screen edges and the positions
+// next to each window's edges; ties keep the earlier candidate, starting
+// with the centered position (centerX, centerY).
+func (m *OS) smartPosition(placing *terminal.Window, screenWidth, screenHeight, width, height, centerX, centerY int) (int, int) {
+	top := m.GetTopMargin()
+	others := m.placementObstacles(placing)
+
+	xs := []int{0, screenWidth - width}
+	ys := []int{top, top + screenHeight - height}
+	for _, w := range others {
+		xs = append(xs, w.X+w.Width, w.X-width)
+		ys = append(ys, w.Y+w.Height, w.Y-height)
+	}
+
+	overlap := func(x, y int) int {
+		total := 0
+		for _, w := range others {
+			dx := min(x+width, w.X+w.Width) - max(x, w.X)
+			dy := min(y+height, w.Y+w.Height) - max(y, w.Y)
+			if dx > 0 && dy > 0 {
+				total += dx * dy
+			}
+		}
+		return total
+	}
+
+	bestX, bestY := centerX, centerY
+	best := overlap(centerX, centerY)
+	for _, y := range ys {
+		if y < top || y+height > top+screenHeight {
+			continue
+		}
+		for _, x := range xs {
+			if x < 0 || x+width > screenWidth {
+				continue
+			}
+			if score := overlap(x, y); score < best {
+				bestX, bestY, best = x, y, score
+			}
+		}
+	}
+	return bestX, bestY
+}
@@ -0,0 +1,195 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// PopupDefaultTimeout is how long a popup without choices stays up when no
+// timeout is given. Popups with choices wait until answered by default.
+const PopupDefaultTimeout = 10 * time.Second
+
+// popupMaxWidth is the width popup bodies are wrapped at.
+const popupMaxWidth = 60
+
+// Popup is a text overlay shown over IPC, optionally asking the user to pick
+// one of its choices.
+type Popup struct {
+	Title     string
+	Body      string
+	Choices   []string  // Buttons to pick from (none for an informational popup)
+	Selection int       // Highlighted choice
+	Deadline  time.Time // When the popup closes by itself (zero = never)
+	RequestID string    // Remote request answered with the picked choice ("" when nobody waits)
+	Output    bool      // Body is command output: shown unwrapped, closed by any key
+	Guarded   bool      // Typing cannot answer it: no digit or Enter shortcuts, Space picks
+	OnClose   func(int) // Called with the picked choice, or -1 when dismissed or timed out
+
+	timeout time.Duration // Time the popup stays up once shown (zero = until answered)
+}
+
+// ShowPopup shows a popup and returns it. While another popup is up, it is
+// queued and shown once the popups before it close. A timeout of zero keeps
+// the popup up until it is answered or dismissed; otherwise the timeout
+// runs from when it shows. When requestID is set, the request is answered
+// once the popup closes.
+func (m *OS) ShowPopup(title, body string, timeout time.Duration, choices []string, requestID string) *Popup {
+	popup := &Popup{Title: title, Body: body, Choices: choices, RequestID: requestID, timeout: timeout}
+	m.popupQueue = append(m.popupQueue, popup)
+	m.showNextPopup()
+	return popup
+}
+
+// showNextPopup shows the first queued popup when no popup is up.
+func (m *OS) showNextPopup() {
+	if m.Popup != nil || len(m.popupQueue) == 0 {
+		return
+	}
+	popup := m.popupQueue[0]
+	m.popupQueue = m.popupQueue[1:]
+	if popup.timeout > 0 {
+		popup.Deadline = time.Now().Add(popup.timeout)
+	}
+	m.Popup = popup
+}
+
+// ClosePopup closes the popup, answering its request with the choice at
+// index, or as dismissed when index is not a choice.
+func (m *OS) ClosePopup(index int) {
+	popup := m.Popup
+	if popup == nil {
+		return
+	}
+	m.Popup = nil
+	defer m.showNextPopup()
+	if index >= len(popup.Choices) {
+		index = -1
+	}
+	if popup.OnClose != nil {
+		popup.OnClose(index)
+	}
+	if popup.RequestID == "" || m.DaemonClient == nil {
+		return
+	}
+	if index < 0 {
+		_ = m.DaemonClient.SendCommandResultWithData(popup.RequestID, false, "popup dismissed",
+			map[string]any{"index": -1})
+		return
+	}
+	_ = m.DaemonClient.SendCommandResultWithData(popup.RequestID, true, popup.Choices[index],
+		map[string]any{"choice": popup.Choices[index], "index": index})
+}
+
+// ExpirePopup closes the popup once its timeout has passed.
+func (m *OS) ExpirePopup() {
+	if m.Popup == nil || m.Popup.Deadline.IsZero() || time.Now().Before(m.Popup.Deadline) {
+		return
+	}
+	popup := m.Popup
+	m.Popup = nil
+	defer m.showNextPopup()
+	if popup.OnClose != nil {
+		popup.OnClose(-1)
+	}
+	if popup.RequestID != "" && m.DaemonClient != nil {
+		_ = m.DaemonClient.SendCommandResultWithData(popup.RequestID, false, "popup timed out",
+			map[string]any{"index": -1, "timed_out": true})
+	}
+}
+
+// PopupSelect moves the popup's highlighted choice by delta, wrapping around.
+func (m *OS) PopupSelect(delta int) {
+	if m.Popup == nil || len(m.Popup.Choices) == 0 {
+		return
+	}
+	n := len(m.Popup.Choices)
+	m.Popup.Selection = ((m.Popup.Selection+delta)%n + n) % n
+}
+
+// fitPopupOutput cuts command output to the screen: long lines are
+// truncated and only the last lines that fit are kept.
+func (m *OS) fitPopupOutput(output string) string {
+	width := max(m.GetRenderWidth()-10, 20)
+	height := max(m.GetRenderHeight()-10, 3)
+	lines := strings.Split(output, "\n")
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+		lines[0] = "…"
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(strings.ReplaceAll(line, "\t", "    "), width, "…")
+	}
+	// Pad to a block so the lines stay left-aligned in the dialog
+	block := strings.Join(lines, "\n")
+	return lipgloss.NewStyle().Width(lipgloss.Width(block)).Render(block)
+}
+
+// renderPopupDialog renders the popup, returning it with its width and
+// height.
+func (m *OS) renderPopupDialog() (string, int, int) {
+	popup := m.Popup
+	borderColor := theme.HelpBorder()
+	selectedColor := theme.HelpTabActive()
+	unselectedColor := theme.HelpGray()
+
+	width := min(popupMaxWidth, max(m.GetRenderWidth()-10, 20))
+	var rows []string
+	if popup.Title != "" {
+		rows = append(rows, lipgloss.NewStyle().Foreground(selectedColor).Bold(true).Render(popup.Title))
+	}
+	if popup.Body != "" {
+		if len(rows) > 0 {
+			rows = append(rows, "")
+		}
+		if popup.Output {
+			rows = append(rows, m.fitPopupOutput(popup.Body))
+		} else {
+			body := lipgloss.NewStyle().Width(min(lipgloss.Width(popup.Body), width))
+			rows = append(rows, body.Render(popup.Body))
+		}
+	}
+
+	hint := "Enter/Esc: close"
+	if popup.Output {
+		hint = "Any key: close"
+	}
+	if len(popup.Choices) > 0 {
+		buttons := make([]string, 0, 2*len(popup.Choices))
+		for i, choice := range popup.Choices {
+			style := lipgloss.NewStyle().
+				Foreground(unselectedColor).
+				Border(lipgloss.NormalBorder()).
+				BorderForeground(unselectedColor).
+				Padding(0, 1)
+			if i == popup.Selection {
+				style = style.Foreground(selectedColor).Bold(true).BorderForeground(selectedColor)
+			}
+			if i > 0 {
+				buttons = append(buttons, "  ")
+			}
+			label := choice
+			if i < 9 && !popup.Guarded {
+				label = strconv.Itoa(i+1) + " " + choice
+			}
+			buttons = append(buttons, style.Render(label))
+		}
+		rows = append(rows, "", lipgloss.JoinHorizontal(lipgloss.Center, buttons...))
+		hint = "←/→: choose  Enter: select  Esc: dismiss"
+		if popup.Guarded {
+			hint = "←/→: choose  Space: select  Esc: dismiss"
+		}
+	}
+	rows = append(rows, "", lipgloss.NewStyle().Foreground(unselectedColor).Italic(true).Render(hint))
+
+	dialog := lipgloss.NewStyle().
+		Border(getBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Center, rows...))
+	return dialog, lipgloss.Width(dialog), lipgloss.Height(dialog)
+}
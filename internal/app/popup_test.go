@@ -0,0 +1,41 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShowPopupCommand(t *testing.T) {
+	m := NewOS(OSOptions{})
+	show := func(args ...string) {
+		m.Update(RemoteCommandMsg{CommandType: "tape_command", TapeCommand: "ShowPopup", TapeArgs: args})
+	}
+
+	show("Deploy", "Deploy to prod?", "", "Yes", "No")
+	if m.Popup == nil || len(m.Popup.Choices) != 2 || !m.Popup.Deadline.IsZero() {
+		t.Fatalf("expected a popup with two choices waiting until answered, got %+v", m.Popup)
+	}
+	m.PopupSelect(-1)
+	if m.Popup.Selection != 1 {
+		t.Errorf("expected the selection to wrap to the last choice, got %d", m.Popup.Selection)
+	}
+	m.ClosePopup(m.Popup.Selection)
+	if m.Popup != nil {
+		t.Error("expected the popup closed after a choice")
+	}
+
+	show("Backup", "Backup finished")
+	if m.Popup == nil || m.Popup.Deadline.IsZero() {
+		t.Fatalf("expected an informational popup with the default timeout, got %+v", m.Popup)
+	}
+	m.Popup.Deadline = time.Now().Add(-time.Second)
+	m.ExpirePopup()
+	if m.Popup != nil {
+		t.Error("expected the popup closed once its timeout passed")
+	}
+
+	show("Bad", "timeout", "soon")
+	if m.Popup != nil {
+		t.Error("expected no popup for an invalid timeout")
+	}
+}
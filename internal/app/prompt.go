@@ -0,0 +1,100 @@
+package app
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Prompt is a one-line text input shown in a dialog over the windows, such
+// as the run, tag and find prompts.
+type Prompt struct {
+	Active bool   // True while the prompt is shown
+	Title  string // Heading of the dialog
+	Hint   string // Keys the prompt takes, shown under the input
+	Buffer string // Text typed so far
+}
+
+// Open shows the prompt with text already typed.
+func (p *Prompt) Open(title, hint, text string) {
+	*p = Prompt{Active: true, Title: title, Hint: hint, Buffer: text}
+}
+
+// Close hides the prompt and returns the text that was typed.
+func (p *Prompt) Close() string {
+	text := p.Buffer
+	*p = Prompt{}
+	return text
+}
+
+// Edit applies a key typed at the prompt: Backspace deletes the last
+// character and printable characters, in any script, are added. It reports
+// whether the key changed the text.
+func (p *Prompt) Edit(msg tea.KeyPressMsg) bool {
+	key := msg.String()
+	switch key {
+	case "backspace":
+		if p.Buffer == "" {
+			return false
+		}
+		_, size := utf8.DecodeLastRuneInString(p.Buffer)
+		p.Buffer = p.Buffer[:len(p.Buffer)-size]
+		return true
+	case "space":
+		p.Buffer += " "
+		return true
+	}
+	text := msg.Text
+	if text == "" && utf8.RuneCountInString(key) == 1 {
+		// Keys made without their text, such as those replayed from tapes
+		text = key
+	}
+	if text == "" || msg.Mod&(tea.ModCtrl|tea.ModAlt|tea.ModSuper) != 0 ||
+		strings.ContainsFunc(text, func(r rune) bool { return !unicode.IsPrint(r) }) {
+		return false
+	}
+	p.Buffer += text
+	return true
+}
+
+// renderPrompt renders a prompt dialog, returning it with its width and
+// height. The end of text too long for the dialog is kept in view.
+func (m *OS) renderPrompt(p *Prompt) (string, int, int) {
+	width := min(popupMaxWidth, max(m.GetRenderWidth()-10, 20))
+	input := p.Buffer + "█"
+	if excess := ansi.StringWidth(input) - width; excess > 0 {
+		input = ansi.TruncateLeft(input, excess+1, "…")
+	}
+	rows := []string{
+		lipgloss.NewStyle().Foreground(theme.HelpTabActive()).Bold(true).Render(ansi.Truncate(p.Title, width, "…")),
+		"",
+		lipgloss.NewStyle().Width(width).Render(input),
+		"",
+		lipgloss.NewStyle().Foreground(theme.HelpGray()).Italic(true).Render(p.Hint),
+	}
+	dialog := lipgloss.NewStyle().
+		Border(getBorder()).
+		BorderForeground(theme.HelpBorder()).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	return dialog, lipgloss.Width(dialog), lipgloss.Height(dialog)
+}
+
+// appendPromptLayer appends the layer of a prompt dialog, centered, while
+// the prompt is shown.
+func (m *OS) appendPromptLayer(layers []*lipgloss.Layer, p *Prompt, id string) []*lipgloss.Layer {
+	if !p.Active {
+		return layers
+	}
+	content, width, height := m.renderPrompt(p)
+	x := (m.GetRenderWidth() - width) / 2
+	y := (m.GetRenderHeight() - height) / 2
+	return append(layers, lipgloss.NewLayer(content).
+		X(x).Y(y).Z(config.ZIndexHelp+1).ID(id))
+}
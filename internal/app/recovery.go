@@ -0,0 +1,365 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/adrg/xdg"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// RecoveryState is the session layout written to the recovery file on panic
+// and by periodic autosave, so it can be offered for restore on next start
+// if tuios did not exit cleanly.
+type RecoveryState struct {
+	SavedAt time.Time             `json:"saved_at"`
+	Reason  string                `json:"reason"` // "panic" or "autosave"
+	State   *session.SessionState `json:"state"`
+	path    string                // Recovery file the state was read from
+}
+
+// recoveryPID returns the process ID the recovery file of this instance is
+// named after. Tests replace it to play another instance.
+var recoveryPID = os.Getpid
+
+// GetRecoveryFilePath returns the path of the crash recovery file of this
+// instance. Every instance has its own, named after its process ID, so a
+// clean exit never removes the recovery data of another instance.
+func GetRecoveryFilePath() (string, error) {
+	path, err := xdg.StateFile(fmt.Sprintf("tuios/recovery/%d.json", recoveryPID()))
+	if err != nil {
+		return "", fmt.Errorf("failed to get recovery file path: %w", err)
+	}
+	return path, nil
+}
+
+// recoveryFiles returns the recovery files left by instances no longer
+// running, which are the ones that did not exit cleanly.
+func recoveryFiles() ([]string, error) {
+	own, err := GetRecoveryFilePath()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(filepath.Dir(own), "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recovery files: %w", err)
+	}
+	var orphans []string
+	for _, path := range paths {
+		pid, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+		if running, err := process.PidExists(int32(pid)); err == nil && running {
+			continue
+		}
+		orphans = append(orphans, path)
+	}
+	return orphans, nil
+}
+
+// BuildRecoveryState captures the current layout together with each
+// window's working directory.
+func (m *OS) BuildRecoveryState() *session.SessionState {
+	state := m.BuildSessionState()
+	for i := range state.Windows {
+		if w := m.findTerminal(state.Windows[i].ID); w != nil {
+			state.Windows[i].Cwd = w.WorkingDirectory()
+		}
+		state.Windows[i].PTYID = ""
+	}
+	return state
+}
+
+// SaveRecoveryState writes the current layout to the recovery file. It is
+// safe to call after a panic: a failure to capture the state is returned as
+// an error rather than panicking again.
+func (m *OS) SaveRecoveryState(reason string) (path string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to capture session state: %v", r)
+		}
+	}()
+
+	data, err := encodeRecoveryState(reason, m.BuildRecoveryState())
+	if err != nil {
+		return "", err
+	}
+	return writeRecoveryFile(data)
+}
+
+// encodeRecoveryState encodes a layout as recovery file contents.
+func encodeRecoveryState(reason string, state *session.SessionState) ([]byte, error) {
+	recovery := RecoveryState{
+		SavedAt: time.Now(),
+		Reason:  reason,
+		State:   state,
+	}
+	data, err := json.MarshalIndent(recovery, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode recovery state: %w", err)
+	}
+	return data, nil
+}
+
+// writeRecoveryFile atomically replaces the recovery file with data.
+func writeRecoveryFile(data []byte) (string, error) {
+	path, err := GetRecoveryFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first so a crash mid-write never leaves a
+	// truncated recovery file behind.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".recovery-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create recovery file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write recovery file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write recovery file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to save recovery file: %w", err)
+	}
+	return path, nil
+}
+
+// AutosaveLayout writes the layout to the recovery file every
+// config.AutosaveInterval so that it survives power loss or kill -9. It is
+// called on every tick and only writes when the layout changed. The write is
+// synchronous so that it can never land after a clean exit removed the file.
+// Only local sessions are autosaved; daemon sessions already outlive the client.
+func (m *OS) AutosaveLayout() {
+	if config.AutosaveInterval <= 0 || m.IsDaemonSession || m.IsSSHMode || m.ShowRestorePrompt {
+		return
+	}
+	now := time.Now()
+	if now.Sub(m.LastAutosave) < config.AutosaveInterval {
+		return
+	}
+	m.LastAutosave = now
+
+	state := m.BuildRecoveryState()
+	fingerprint, err := json.Marshal(state)
+	if err != nil || bytes.Equal(fingerprint, m.lastAutosaveState) {
+		return
+	}
+
+	data, err := encodeRecoveryState("autosave", state)
+	if err == nil {
+		_, err = writeRecoveryFile(data)
+	}
+	if err != nil {
+		m.LogError("Autosave failed: %v", err)
+		return
+	}
+	m.lastAutosaveState = fingerprint
+}
+
+// LoadRecoveryState reads the newest recovery file left by an instance that
+// is no longer running. It returns nil without an error if there is nothing
+// to recover.
+func LoadRecoveryState() (*RecoveryState, error) {
+	paths, err := recoveryFiles()
+	if err != nil {
+		return nil, err
+	}
+	var newest *RecoveryState
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recovery file: %w", err)
+		}
+
+		var recovery RecoveryState
+		if err := json.Unmarshal(data, &recovery); err != nil {
+			return nil, fmt.Errorf("failed to parse recovery file: %w", err)
+		}
+		if recovery.State == nil || len(recovery.State.Windows) == 0 {
+			continue
+		}
+		if newest == nil || recovery.SavedAt.After(newest.SavedAt) {
+			recovery.path = path
+			newest = &recovery
+		}
+	}
+	return newest, nil
+}
+
+// RemoveRecoveryState deletes the recovery file of this instance if it
+// exists.
+func RemoveRecoveryState() error {
+	path, err := GetRecoveryFilePath()
+	if err != nil {
+		return err
+	}
+	return removeRecoveryFile(path)
+}
+
+// removeRecoveryFile deletes a recovery file if it exists.
+func removeRecoveryFile(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove recovery file: %w", err)
+	}
+	return nil
+}
+
+// OfferRecovery shows a prompt asking whether to restore a recovered layout.
+func (m *OS) OfferRecovery(recovery *RecoveryState) {
+	if recovery == nil {
+		return
+	}
+	m.PendingRecovery = recovery
+	m.ShowRestorePrompt = true
+	m.RestorePromptSelection = 0 // Default to Yes
+}
+
+// AcceptRecovery restores the pending recovered layout and removes the
+// recovery file.
+func (m *OS) AcceptRecovery() {
+	recovery := m.PendingRecovery
+	m.dismissRecovery()
+	if recovery == nil {
+		return
+	}
+
+	restored := m.RestoreLocalLayout(recovery.State)
+	m.ShowNotification(fmt.Sprintf("Restored %d windows", restored), "success", 3*time.Second)
+}
+
+// DeclineRecovery discards the pending recovered layout.
+func (m *OS) DeclineRecovery() {
+	m.dismissRecovery()
+}
+
+func (m *OS) dismissRecovery() {
+	recovery := m.PendingRecovery
+	m.ShowRestorePrompt = false
+	m.RestorePromptSelection = 0
+	m.PendingRecovery = nil
+	if recovery == nil || recovery.path == "" {
+		return
+	}
+	if err := removeRecoveryFile(recovery.path); err != nil {
+		m.LogError("%v", err)
+	}
+}
+
+// RestoreLocalLayout recreates the windows of a saved layout with fresh
+// local shells, started in each window's saved working directory. Windows
+// with a command to run, from session bundles, run it in place of the
+// shell. Window geometry is scaled to the current screen size. It returns
+// the number of windows created.
+func (m *OS) RestoreLocalLayout(state *session.SessionState) int {
+	if state == nil {
+		return 0
+	}
+
+	screenWidth, screenHeight := m.GetRenderWidth(), m.GetRenderHeight()
+	scale := func(v, from, to int) int {
+		if from <= 0 || to <= 0 || from == to {
+			return v
+		}
+		return v * to / from
+	}
+
+	created := 0
+	for _, ws := range state.Windows {
+		x := scale(ws.X, state.Width, screenWidth)
+		y := scale(ws.Y, state.Height, screenHeight)
+		width := max(scale(ws.Width, state.Width, screenWidth), 10)
+		height := max(scale(ws.Height, state.Height, screenHeight), 5)
+
+		var argv []string
+		if ws.Run != "" {
+			argv = shellArgv(ws.Run)
+		}
+		window := terminal.NewCommandWindow(ws.ID, "", x, y, width, height, len(m.Windows), m.WindowExitChan, ws.Cwd, argv, windowEnv(ws.Workspace)...)
+		if window == nil {
+			m.LogError("Failed to restore window %s", ws.ID)
+			continue
+		}
+
+		caps := GetHostCapabilities()
+		if caps.CellWidth > 0 && caps.CellHeight > 0 {
+			window.SetCellPixelDimensions(caps.CellWidth, caps.CellHeight)
+		}
+
+		window.CustomName = ws.CustomName
+		window.RuleName = ws.RuleName
+		window.Group = ws.Group
+		window.Icon = ws.Icon
+		window.Tags = ws.Tags
+		window.Workspace = ws.Workspace
+		window.Minimized = ws.Minimized
+		window.PreMinimizeX = scale(ws.PreMinimizeX, state.Width, screenWidth)
+		window.PreMinimizeY = scale(ws.PreMinimizeY, state.Height, screenHeight)
+		window.PreMinimizeWidth = scale(ws.PreMinimizeW, state.Width, screenWidth)
+		window.PreMinimizeHeight = scale(ws.PreMinimizeH, state.Height, screenHeight)
+		window.Floating = ws.Floating
+		window.FloatX = scale(ws.FloatX, state.Width, screenWidth)
+		window.FloatY = scale(ws.FloatY, state.Height, screenHeight)
+		window.FloatWidth = scale(ws.FloatW, state.Width, screenWidth)
+		window.FloatHeight = scale(ws.FloatH, state.Height, screenHeight)
+		window.SetZoomed(ws.Zoomed)
+
+		m.setupKittyPassthrough(window)
+		m.setupSixelPassthrough(window)
+
+		m.Windows = append(m.Windows, window)
+		created++
+	}
+
+	m.MasterRatio = state.MasterRatio
+	m.restoreWorkspaceTiling(state)
+	m.restoreTilingState(state)
+	m.WindowMarks = maps.Clone(state.Marks)
+	m.WorkspaceNames = maps.Clone(state.WorkspaceNames)
+	m.restorePopulatedWorkspaces(state.PopulatedWorkspaces)
+	m.restoreTagView(state.TagView)
+
+	for workspace, windowID := range state.WorkspaceFocus {
+		for i, w := range m.Windows {
+			if w.ID == windowID {
+				m.WorkspaceFocus[workspace] = i
+				break
+			}
+		}
+	}
+
+	m.SwitchToWorkspace(state.CurrentWorkspace)
+	for i, w := range m.Windows {
+		if w.ID == state.FocusedWindowID {
+			m.FocusWindow(i)
+			break
+		}
+	}
+
+	if m.AutoTiling {
+		m.TileAllWindows()
+	}
+	m.MarkAllDirty()
+	m.LogInfo("Restored %d windows from recovery file", created)
+	return created
+}
@@ -0,0 +1,168 @@
+package app
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/adrg/xdg"
+)
+
+// crashedInstancePID is the process ID of an instance that is not running,
+// whose recovery files are offered on start.
+const crashedInstancePID = 999999999
+
+// playInstance makes the recovery files written in a test those of the
+// instance with process ID pid.
+func playInstance(t *testing.T, pid int) {
+	prev := recoveryPID
+	recoveryPID = func() int { return pid }
+	t.Cleanup(func() { recoveryPID = prev })
+}
+
+func TestRecoveryStateRoundTrip(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	playInstance(t, crashedInstancePID)
+
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", CustomName: "editor", X: 1, Y: 2, Width: 40, Height: 20, Workspace: 1, PTYID: "pty-1"},
+		{ID: "window-two-0000", X: 41, Y: 2, Width: 40, Height: 20, Workspace: 3, Minimized: true},
+	}
+	m.FocusedWindow = 0
+
+	if recovery, err := LoadRecoveryState(); err != nil || recovery != nil {
+		t.Fatalf("expected no recovery state initially, got %v, %v", recovery, err)
+	}
+
+	if _, err := m.SaveRecoveryState("panic"); err != nil {
+		t.Fatalf("SaveRecoveryState failed: %v", err)
+	}
+
+	recovery, err := LoadRecoveryState()
+	if err != nil || recovery == nil {
+		t.Fatalf("LoadRecoveryState = %v, %v", recovery, err)
+	}
+	if recovery.Reason != "panic" {
+		t.Errorf("reason = %q, want panic", recovery.Reason)
+	}
+	state := recovery.State
+	if len(state.Windows) != 2 || state.FocusedWindowID != "window-one-0000" {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+	if w := state.Windows[0]; w.CustomName != "editor" || w.Width != 40 || w.PTYID != "" {
+		t.Errorf("unexpected window state: %+v", w)
+	}
+	if w := state.Windows[1]; w.Workspace != 3 || !w.Minimized {
+		t.Errorf("unexpected window state: %+v", w)
+	}
+
+	m.OfferRecovery(recovery)
+	if !m.ShowRestorePrompt {
+		t.Fatal("expected restore prompt to be shown")
+	}
+	m.DeclineRecovery()
+	if m.ShowRestorePrompt || m.PendingRecovery != nil {
+		t.Error("expected restore prompt to be dismissed")
+	}
+	if recovery, _ := LoadRecoveryState(); recovery != nil {
+		t.Error("expected recovery file to be removed after declining")
+	}
+}
+
+func TestAutosaveLayout(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	playInstance(t, crashedInstancePID)
+
+	prev := config.AutosaveInterval
+	config.AutosaveInterval = time.Second
+	t.Cleanup(func() { config.AutosaveInterval = prev })
+
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1}}
+
+	m.AutosaveLayout()
+	path, _ := GetRecoveryFilePath()
+	first, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected autosave to write %s: %v", path, err)
+	}
+
+	// Unchanged layout is not rewritten, even once the interval has passed
+	m.LastAutosave = time.Time{}
+	m.AutosaveLayout()
+	if second, _ := os.Stat(path); !second.ModTime().Equal(first.ModTime()) {
+		t.Error("expected unchanged layout to be skipped")
+	}
+
+	// Changes are written on the next interval only
+	m.Windows[0].Width = 60
+	m.AutosaveLayout()
+	if recovery, _ := LoadRecoveryState(); recovery.State.Windows[0].Width != 40 {
+		t.Error("expected autosave to wait for the interval")
+	}
+	m.LastAutosave = time.Time{}
+	m.AutosaveLayout()
+	recovery, err := LoadRecoveryState()
+	if err != nil || recovery.Reason != "autosave" || recovery.State.Windows[0].Width != 60 {
+		t.Errorf("expected updated autosave, got %+v, %v", recovery, err)
+	}
+
+	// Daemon sessions are not autosaved
+	_ = RemoveRecoveryState()
+	m.IsDaemonSession = true
+	m.LastAutosave = time.Time{}
+	m.AutosaveLayout()
+	if recovery, _ := LoadRecoveryState(); recovery != nil {
+		t.Error("expected daemon session not to be autosaved")
+	}
+}
+
+func TestRecoveryFilesPerInstance(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1}}
+
+	// This test process stands in for an instance that is still running
+	playInstance(t, os.Getpid())
+	if _, err := m.SaveRecoveryState("autosave"); err != nil {
+		t.Fatalf("SaveRecoveryState failed: %v", err)
+	}
+	running, _ := GetRecoveryFilePath()
+	if recovery, _ := LoadRecoveryState(); recovery != nil {
+		t.Error("expected the recovery file of a running instance not to be offered")
+	}
+
+	playInstance(t, crashedInstancePID)
+	if _, err := m.SaveRecoveryState("panic"); err != nil {
+		t.Fatalf("SaveRecoveryState failed: %v", err)
+	}
+	recovery, err := LoadRecoveryState()
+	if err != nil || recovery == nil || recovery.Reason != "panic" {
+		t.Fatalf("expected the crashed instance's layout, got %+v, %v", recovery, err)
+	}
+
+	// Another instance exiting cleanly keeps both files
+	playInstance(t, crashedInstancePID+1)
+	_ = RemoveRecoveryState()
+	if _, err := os.Stat(running); err != nil {
+		t.Errorf("expected the running instance's recovery file to be kept: %v", err)
+	}
+	m.OfferRecovery(recovery)
+	m.DeclineRecovery()
+	if _, err := os.Stat(running); err != nil {
+		t.Errorf("expected declining to leave the running instance's file alone: %v", err)
+	}
+	if recovery, _ := LoadRecoveryState(); recovery != nil {
+		t.Error("expected the declined recovery file to be removed")
+	}
+}
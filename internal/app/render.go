@@ -3,11 +3,13 @@ package app
 import (
 	"image/color"
 	"os"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/pool"
+	"github.com/Gaurav-Gosain/tuios/internal/profiling"
 	"github.com/Gaurav-Gosain/tuios/internal/theme"
 )
 
@@ -18,6 +20,53 @@ func (m *OS) GetCanvas(render bool) *lipgloss.Canvas {
 	layers := (*layersPtr)[:0]
 	defer pool.PutLayerSlice(layersPtr)
 
+	layers = m.appendWindowLayers(layers)
+	// The other outputs of a split screen show their own workspaces,
+	// rendered by renderInactiveOutputs
+	for i, out := range m.Outputs {
+		if i != m.ActiveOutput {
+			layers = append(layers, out.windowLayers...)
+		}
+	}
+
+	if render {
+		overlays := m.renderOverlays()
+		if offset := m.outputX(m.ActiveOutput); offset != 0 {
+			for _, overlay := range overlays {
+				// The screensaver covers every output
+				if overlay.GetID() != "screensaver" {
+					overlay.X(overlay.GetX() + offset)
+				}
+			}
+		}
+		layers = append(layers, overlays...)
+
+		if config.DockbarPosition != "hidden" {
+			layers = append(layers, m.renderDock())
+			for i, out := range m.Outputs {
+				if i != m.ActiveOutput && out.dockLayer != nil {
+					layers = append(layers, out.dockLayer)
+				}
+			}
+		}
+
+		// Render sidebar if visible
+		if m.SidebarVisible {
+			sidebarLayer := m.renderSidebar()
+			if sidebarLayer != nil {
+				layers = append(layers, sidebarLayer)
+			}
+		}
+	}
+
+	canvas.AddLayers(layers...)
+	return canvas
+}
+
+// appendWindowLayers appends the layers of the current workspace's windows,
+// placed on the active output.
+func (m *OS) appendWindowLayers(layers []*lipgloss.Layer) []*lipgloss.Layer {
+	offset := m.outputX(m.ActiveOutput)
 	topMargin := m.GetTopMargin()
 	viewportWidth := m.GetRenderWidth()
 	viewportHeight := m.GetUsableHeight()
@@ -29,6 +78,8 @@ func (m *OS) GetCanvas(render bool) *lipgloss.Canvas {
 		Border(getBorder()).
 		BorderTop(false)
 
+	seeThrough := m.seeThroughWindows()
+
 	for i := range m.Windows {
 		window := m.Windows[i]
 
@@ -69,7 +120,9 @@ func (m *OS) GetCanvas(render bool) *lipgloss.Canvas {
 			window.X+window.Width <= viewportWidth &&
 			window.Y+window.Height <= viewportHeight+topMargin
 
-		isFocused := m.FocusedWindow == i && m.FocusedWindow >= 0 && m.FocusedWindow < len(m.Windows)
+		// The focused window of an output other than the active one keeps a
+		// focused border but gets no cursor
+		isFocused := m.FocusedWindow == i && m.FocusedWindow >= 0 && m.FocusedWindow < len(m.Windows) && !m.inactiveOutput
 		var borderColorObj color.Color
 		if isFocused {
 			if m.Mode == TerminalMode {
@@ -77,9 +130,16 @@ func (m *OS) GetCanvas(render bool) *lipgloss.Canvas {
 			} else {
 				borderColorObj = theme.BorderFocusedWindow()
 			}
+		} else if m.inactiveOutput && m.FocusedWindow == i {
+			borderColorObj = theme.BorderFocusedWindow()
+		} else if len(m.RemoteFocus) > 0 && m.IsRemotelyFocused(window.ID) {
+			borderColorObj = theme.BorderRemoteFocus()
 		} else {
 			borderColorObj = theme.BorderUnfocused()
 		}
+		if window.InputLocked {
+			borderColorObj = inputLockBorderColor
+		}
 
 		if window.CachedLayer != nil && !window.Dirty && !window.ContentDirty && !window.PositionDirty {
 			layers = append(layers, window.CachedLayer)
@@ -88,7 +148,7 @@ func (m *OS) GetCanvas(render bool) *lipgloss.Canvas {
 
 		needsRedraw := window.CachedLayer == nil ||
 			window.Dirty || window.ContentDirty || window.PositionDirty ||
-			window.CachedLayer.GetX() != window.X ||
+			window.CachedLayer.GetX() != window.X+offset ||
 			window.CachedLayer.GetY() != window.Y ||
 			window.CachedLayer.GetZ() != window.Z
 
@@ -97,9 +157,29 @@ func (m *OS) GetCanvas(render bool) *lipgloss.Canvas {
 			continue
 		}
 
-		content := m.renderTerminal(window, isFocused, m.Mode == TerminalMode)
+		var content string
+		switch {
+		case window.HasPanes():
+			content = m.renderPanes(window, isFocused, m.Mode == TerminalMode, borderColorObj)
+		case window.HasTabs():
+			content = m.renderTabs(window, isFocused, m.Mode == TerminalMode, borderColorObj)
+		case window.Zoomed:
+			content = zoomContent(m.renderTerminal(window, isFocused, m.Mode == TerminalMode))
+		default:
+			content = m.renderTerminal(window, isFocused, m.Mode == TerminalMode)
+		}
+		if window.InputLocked {
+			content = addInputLockBanner(content, m.UnlockInputHint(), window.Width-2)
+		}
 
-		isRenaming := m.RenamingWindow && i == m.FocusedWindow
+		// Title bars are kept up to date by RefreshTitleBars on every tick
+		title := window.TitleBar
+		if title == "" {
+			title = m.titleBarText(window)
+		}
+		if m.RenamingWindow && i == m.FocusedWindow && !m.inactiveOutput {
+			title = m.RenameBuffer + "_"
+		}
 
 		boxContent := addToBorder(
 			box.Width(window.Width).
@@ -107,13 +187,17 @@ func (m *OS) GetCanvas(render bool) *lipgloss.Canvas {
 				BorderForeground(borderColorObj).
 				Render(content),
 			borderColorObj,
-			window,
-			isRenaming,
-			m.RenameBuffer,
+			title,
 			m.AutoTiling,
 		)
+		if pane := scrolledPane(window); pane != nil {
+			boxContent = addScrollbar(boxContent, window, pane, borderColorObj)
+		}
+		if below := seeThrough[window]; len(below) > 0 {
+			boxContent = m.showThrough(boxContent, window, below)
+		}
 
-		zIndex := window.Z
+		zIndex := LayerZ(window)
 		if isAnimating {
 			zIndex = config.ZIndexAnimating
 		}
@@ -124,38 +208,20 @@ func (m *OS) GetCanvas(render bool) *lipgloss.Canvas {
 			viewportWidth, viewportHeight+topMargin,
 		)
 
-		window.CachedLayer = lipgloss.NewLayer(clippedContent).X(finalX).Y(finalY).Z(zIndex).ID(window.ID)
+		window.CachedLayer = lipgloss.NewLayer(clippedContent).X(finalX + offset).Y(finalY).Z(zIndex).ID(window.ID)
 		layers = append(layers, window.CachedLayer)
 
 		window.ClearDirtyFlags()
 	}
-
-	if render {
-		overlays := m.renderOverlays()
-		layers = append(layers, overlays...)
-
-		if config.DockbarPosition != "hidden" {
-			dockLayer := m.renderDock()
-			layers = append(layers, dockLayer)
-		}
-
-		// Render sidebar if visible
-		if m.SidebarVisible {
-			sidebarLayer := m.renderSidebar()
-			if sidebarLayer != nil {
-				layers = append(layers, sidebarLayer)
-			}
-		}
-	}
-
-	canvas.AddLayers(layers...)
-	return canvas
+	return layers
 }
 
 func (m *OS) View() tea.View {
 	var view tea.View
 
+	start := time.Now()
 	content := lipgloss.Sprint(m.GetCanvas(true).Render())
+	profiling.RecordFrame(time.Since(start))
 
 	view.SetContent(content)
 
@@ -175,32 +241,7 @@ func (m *OS) GetKittyGraphicsCmd() tea.Cmd {
 
 	// Always refresh placements if there are any - this handles window movement
 	if m.KittyPassthrough.HasPlacements() {
-		m.KittyPassthrough.RefreshAllPlacements(func() map[string]*WindowPositionInfo {
-			result := make(map[string]*WindowPositionInfo)
-			for _, w := range m.Windows {
-				if w.Workspace == m.CurrentWorkspace && !w.Minimized {
-					scrollbackLen := 0
-					if w.Terminal != nil {
-						scrollbackLen = w.Terminal.ScrollbackLen()
-					}
-					result[w.ID] = &WindowPositionInfo{
-						WindowX:            w.X,
-						WindowY:            w.Y,
-						ContentOffsetX:     1,
-						ContentOffsetY:     1,
-						Width:              w.Width,
-						Height:             w.Height,
-						Visible:            true,
-						ScrollbackLen:      scrollbackLen,
-						ScrollOffset:       w.ScrollbackOffset,
-						IsBeingManipulated: w.IsBeingManipulated,
-						WindowZ:            w.Z,
-						IsAltScreen:        w.IsAltScreen,
-					}
-				}
-			}
-			return result
-		})
+		m.KittyPassthrough.RefreshAllPlacements(m.passthroughWindows)
 	}
 
 	// Always flush pending output - this includes delete commands even after placements are removed
@@ -225,31 +266,7 @@ func (m *OS) GetSixelGraphicsCmd() tea.Cmd {
 
 	// Refresh placements for all windows
 	if m.SixelPassthrough.PlacementCount() > 0 {
-		m.SixelPassthrough.RefreshAllPlacements(func(windowID string) *WindowPositionInfo {
-			for _, w := range m.Windows {
-				if w.ID == windowID && w.Workspace == m.CurrentWorkspace && !w.Minimized {
-					scrollbackLen := 0
-					if w.Terminal != nil {
-						scrollbackLen = w.Terminal.ScrollbackLen()
-					}
-					return &WindowPositionInfo{
-						WindowX:            w.X,
-						WindowY:            w.Y,
-						ContentOffsetX:     1,
-						ContentOffsetY:     1,
-						Width:              w.Width,
-						Height:             w.Height,
-						Visible:            true,
-						ScrollbackLen:      scrollbackLen,
-						ScrollOffset:       w.ScrollbackOffset,
-						IsBeingManipulated: w.IsBeingManipulated,
-						WindowZ:            w.Z,
-						IsAltScreen:        w.IsAltScreen,
-					}
-				}
-			}
-			return nil
-		})
+		m.SixelPassthrough.RefreshAllPlacements(m.passthroughWindows)
 	}
 
 	// Get pending sixel output and write to /dev/tty (like Kitty passthrough)
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// layerCache holds a rendered layer together with a fingerprint of the state
+// it was rendered from. The layer is reused until the fingerprint changes.
+type layerCache struct {
+	key   string
+	layer *lipgloss.Layer
+}
+
+// get returns the cached layer if it was rendered from the given state.
+func (c *layerCache) get(key string) *lipgloss.Layer {
+	if c.layer != nil && c.key == key {
+		return c.layer
+	}
+	return nil
+}
+
+// set stores a freshly rendered layer for the given state.
+func (c *layerCache) set(key string, layer *lipgloss.Layer) *lipgloss.Layer {
+	c.key = key
+	c.layer = layer
+	return layer
+}
+
+// invalidate drops the cached layer so the next frame renders it again.
+func (c *layerCache) invalidate() {
+	c.key = ""
+	c.layer = nil
+}
+
+// InvalidateChromeCache forces the dock and sidebar to be rendered again on the
+// next frame, for changes that are not captured by their state fingerprints
+// (border or icon changes, for example). MarkAllDirty calls it.
+func (m *OS) InvalidateChromeCache() {
+	m.invalidateDockHeight()
+	m.dockCache.invalidate()
+	for _, out := range m.Outputs {
+		out.dockCache.invalidate()
+	}
+	m.sidebarCache.invalidate()
+}
+
+// dockStateKey fingerprints everything the dock rendering depends on.
+func (m *OS) dockStateKey(layout DockLayout) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d|%d|%d|%s|%s|%s|%s|%d|%d|%d|", m.outputX(m.ActiveOutput), m.GetRenderWidth(), m.GetRenderHeight(),
+		config.DockbarPosition, config.DockStatusSide, layout.LeftText, layout.ModeInfo.Color,
+		m.FocusedWindow, layout.TruncatedCount, layout.Rows)
+	for ws := 1; ws <= m.NumWorkspaces; ws++ {
+		fmt.Fprintf(&b, "%t,", m.WorkspaceAutoTiling(ws))
+	}
+
+	now := time.Now()
+	for _, item := range layout.VisibleItems {
+		w := m.Windows[item.WindowIndex]
+		fmt.Fprintf(&b, "%d:%s:%s:%t:%t:%s;", item.WindowIndex, item.Label, item.Badge,
+			now.Before(w.MinimizeHighlightUntil), w.Minimizing, w.Group)
+	}
+
+	if focused := m.GetFocusedPane(); focused != nil && focused.CopyMode != nil && focused.CopyMode.Active {
+		fmt.Fprintf(&b, "|copy:%d", focused.CopyMode.State)
+	} else {
+		b.WriteString("|" + m.GetCPUGraph() + " " + m.GetRAMUsage())
+	}
+	return b.String()
+}
+
+// sidebarStateKey fingerprints everything the sidebar rendering depends on.
+func (m *OS) sidebarStateKey() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d|%d|%d|%s|%d|%d|%t|%d|%s|", m.GetSidebarWidth(), m.GetRenderHeight(),
+		m.GetTopMargin(), config.DockbarPosition, m.CurrentWorkspace, m.FocusedWindow,
+		m.SidebarFocused, m.SidebarSelectedIndex, m.SidebarJoinTarget+m.SidebarGroupTarget+"#"+m.SidebarTag)
+	fmt.Fprintf(&b, "%t|%d|", m.SidebarSortCPU, m.usage.at.UnixNano())
+	for ws := 1; ws <= m.NumWorkspaces; ws++ {
+		fmt.Fprintf(&b, "%t:%s,", m.WorkspaceAutoTiling(ws), m.WorkspaceNames[ws])
+	}
+	for _, w := range m.Windows {
+		fmt.Fprintf(&b, "%d:%t:%t:%t:%s:%s:%s:%s:%s:%s:%s;", w.Workspace, w.Minimized, w.Floating, m.SidebarMarked[w.ID], w.CustomName, w.Title, w.Group, w.App, w.Icon, windowBadge(w), strings.Join(w.Tags, ","))
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// TestSidebarCacheReuse verifies the sidebar layer is reused for unchanged
+// state and rebuilt once the window list or selection changes.
+func TestSidebarCacheReuse(t *testing.T) {
+	m := &OS{
+		Width:                120,
+		Height:               40,
+		CurrentWorkspace:     1,
+		SidebarVisible:       true,
+		SidebarSelectedIndex: -1,
+		Windows: []*terminal.Window{
+			{ID: "a", Title: "shell", Workspace: 1},
+		},
+	}
+
+	first := m.renderSidebar()
+	if first == nil {
+		t.Fatal("expected sidebar layer")
+	}
+	if again := m.renderSidebar(); again != first {
+		t.Error("expected cached sidebar layer for unchanged state")
+	}
+
+	tests := []struct {
+		name   string
+		mutate func()
+	}{
+		{"window renamed", func() { m.Windows[0].CustomName = "editor" }},
+		{"window added", func() { m.Windows = append(m.Windows, &terminal.Window{ID: "b", Workspace: 2}) }},
+		{"selection moved", func() { m.SidebarFocused = true; m.SidebarSelectedIndex = 1 }},
+		{"window marked", func() { m.SidebarMarked = map[string]bool{"a": true} }},
+		{"workspace switched", func() { m.CurrentWorkspace = 2 }},
+		{"invalidated", m.InvalidateChromeCache},
+	}
+
+	prev := first
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mutate()
+			next := m.renderSidebar()
+			if next == prev {
+				t.Errorf("expected sidebar to re-render after %s", tt.name)
+			}
+			prev = next
+		})
+	}
+}
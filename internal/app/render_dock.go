@@ -1,6 +1,7 @@
 package app
 
 import (
+	"slices"
 	"strings"
 	"time"
 
@@ -12,6 +13,12 @@ import (
 func (m *OS) renderDock() *lipgloss.Layer {
 	layout := m.CalculateDockLayout()
 
+	cacheKey := m.dockStateKey(layout)
+	cache := m.outputDockCache()
+	if cached := cache.get(cacheKey); cached != nil {
+		return cached
+	}
+
 	sysInfoStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#808090")).
 		MarginRight(2)
@@ -100,12 +107,18 @@ func (m *OS) renderDock() *lipgloss.Layer {
 			Render(workspacePart)
 	}
 
-	var dockItemsStr string
-	itemNumber := 1
+	// Pills of each dock row, starting at the column the layout placed the
+	// row's first item at
+	rowPills := make([]string, layout.Rows)
+	rowStartX := make([]int, layout.Rows)
+	for i := range rowStartX {
+		rowStartX[i] = -1
+	}
 
-	for _, dockItem := range layout.VisibleItems {
+	for i, dockItem := range layout.VisibleItems {
 		windowIndex := dockItem.WindowIndex
 		window := m.Windows[windowIndex]
+		row := layout.ItemPositions[i].Row
 
 		bgColor := "#2a2a3e"
 		fgColor := "#a0a0a8"
@@ -126,28 +139,43 @@ func (m *OS) renderDock() *lipgloss.Layer {
 			Foreground(lipgloss.Color(bgColor)).
 			Render(config.GetDockPillLeftChar())
 
-		nameLabel := lipgloss.NewStyle().
+		nameStyle := lipgloss.NewStyle().
 			Background(lipgloss.Color(bgColor)).
 			Foreground(lipgloss.Color(fgColor)).
-			Bold(isHighlighted || windowIndex == m.FocusedWindow).
-			Render(labelText)
+			Bold(isHighlighted || windowIndex == m.FocusedWindow)
+		// Windows of a group share their accent color
+		if window.Group != "" && !isHighlighted && windowIndex != m.FocusedWindow {
+			nameStyle = nameStyle.Foreground(groupAccentColor(window.Group))
+		}
+		nameLabel := nameStyle.Render(labelText)
+		if dockItem.Badge != "" {
+			nameLabel += lipgloss.NewStyle().
+				Background(lipgloss.Color(bgColor)).
+				Foreground(lipgloss.Color(windowBadgeColor(window))).
+				Bold(true).
+				Render(dockItem.Badge + " ")
+		}
 
 		rightCircle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color(bgColor)).
 			Render(config.GetDockPillRightChar())
 
-		if itemNumber > 1 {
-			dockItemsStr += " "
+		if rowStartX[row] < 0 {
+			rowStartX[row] = layout.ItemPositions[i].StartX
+		} else {
+			rowPills[row] += " "
 		}
-		dockItemsStr += leftCircle + nameLabel + rightCircle
-
-		itemNumber++
+		rowPills[row] += leftCircle + nameLabel + rightCircle
 	}
 
 	if layout.TruncatedCount > 0 {
+		last := layout.Rows - 1
+		if rowStartX[last] < 0 {
+			rowStartX[last] = layout.OverflowX
+		}
 		truncStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#808090"))
-		dockItemsStr += truncStyle.Render(" ...")
+		rowPills[last] += truncStyle.Render(" ...")
 	}
 
 	leftInfo := lipgloss.JoinHorizontal(lipgloss.Top,
@@ -156,7 +184,7 @@ func (m *OS) renderDock() *lipgloss.Layer {
 	)
 
 	var rightInfo string
-	focusedWindow := m.GetFocusedWindow()
+	focusedWindow := m.GetFocusedPane()
 
 	inCopyMode := focusedWindow != nil && focusedWindow.CopyMode != nil && focusedWindow.CopyMode.Active
 	if inCopyMode {
@@ -170,6 +198,8 @@ func (m *OS) renderDock() *lipgloss.Layer {
 			helpText = "hjkl:extend w/b/e:word f/F/t/T:char ;,:repeat {/}:para %:bracket y:yank Esc:cancel"
 		case terminal.CopyModeVisualLine:
 			helpText = "jk:extend  y:yank  Esc:cancel"
+		case terminal.CopyModeVisualBlock:
+			helpText = "hjkl:extend w/b/e:word {/}:para y:yank Esc:cancel"
 		}
 
 		helpStyle := lipgloss.NewStyle().
@@ -183,34 +213,38 @@ func (m *OS) renderDock() *lipgloss.Layer {
 		rightInfo = sysInfoStyle.Render(cpuGraph + " " + ramUsage)
 	}
 
-	actualLeftWidth := lipgloss.Width(leftInfo)
-	centerWidth := lipgloss.Width(dockItemsStr)
+	renderWidth := m.GetRenderWidth()
 	rightWidth := layout.RightWidth
 
-	availableSpace := m.GetRenderWidth() - actualLeftWidth - rightWidth - centerWidth
-	leftSpacer := availableSpace / 2
-	rightSpacer := availableSpace - leftSpacer
-
-	if leftSpacer < 0 {
-		leftSpacer = 0
-		rightSpacer = 0
-	}
-	if rightSpacer < 0 {
-		rightSpacer = 0
+	// The status widgets are kept at their reserved width at either end
+	startInfo := leftInfo
+	endInfo := lipgloss.NewStyle().Width(rightWidth).Align(lipgloss.Right).Render(rightInfo)
+	if config.DockStatusSide == "left" {
+		startInfo = lipgloss.NewStyle().Width(rightWidth).Render(rightInfo)
+		endInfo = leftInfo
 	}
 
-	paddedRightInfo := lipgloss.NewStyle().Width(rightWidth).Align(lipgloss.Right).Render(rightInfo)
+	startWidth := lipgloss.Width(startInfo)
+	leftSpacer := 0
+	if rowStartX[0] >= 0 {
+		leftSpacer = max(rowStartX[0]-startWidth, 0)
+	}
+	rightSpacer := max(renderWidth-startWidth-leftSpacer-lipgloss.Width(rowPills[0])-lipgloss.Width(endInfo), 0)
 
 	dockBar := lipgloss.JoinHorizontal(
 		lipgloss.Top,
-		leftInfo,
+		startInfo,
 		lipgloss.NewStyle().Width(leftSpacer).Render(""),
-		lipgloss.NewStyle().Render(dockItemsStr),
+		lipgloss.NewStyle().Render(rowPills[0]),
 		lipgloss.NewStyle().Width(rightSpacer).Render(""),
-		paddedRightInfo,
+		endInfo,
 	)
+	dockRows := []string{dockBar}
+	if layout.Rows > 1 {
+		wrapped := strings.Repeat(" ", max(rowStartX[1], 0)) + rowPills[1]
+		dockRows = append(dockRows, lipgloss.NewStyle().Width(renderWidth).MaxWidth(renderWidth).Render(wrapped))
+	}
 
-	renderWidth := m.GetRenderWidth()
 	if m.cachedSeparatorWidth != renderWidth {
 		m.cachedSeparator = strings.Repeat(config.GetWindowSeparatorChar(), renderWidth)
 		m.cachedSeparatorWidth = renderWidth
@@ -221,13 +255,15 @@ func (m *OS) renderDock() *lipgloss.Layer {
 		Foreground(lipgloss.Color("#303040")).
 		Render(m.cachedSeparator)
 
-	dockbarYPos := m.GetRenderHeight() - config.DockHeight
-	dockbarParts := []string{separator, dockBar}
+	// The first row is at the screen edge, the separator toward the windows
+	dockbarYPos := m.GetRenderHeight() - config.DockHeight - len(dockRows) + 1
+	dockbarParts := append(dockRows, separator)
 	if config.DockbarPosition == "top" {
 		dockbarYPos = 0
-		dockbarParts[0], dockbarParts[1] = dockbarParts[1], dockbarParts[0]
+	} else {
+		slices.Reverse(dockbarParts)
 	}
 
 	fullDock := lipgloss.JoinVertical(lipgloss.Left, dockbarParts...)
-	return lipgloss.NewLayer(fullDock).X(0).Y(dockbarYPos).Z(config.ZIndexDock).ID("dock")
+	return cache.set(cacheKey, lipgloss.NewLayer(fullDock).X(m.outputX(m.ActiveOutput)).Y(dockbarYPos).Z(config.ZIndexDock).ID("dock"))
 }
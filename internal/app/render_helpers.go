@@ -1,8 +1,10 @@
 package app
 
 import (
+	"fmt"
 	"image/color"
 	"strings"
+	"time"
 
 	"charm.land/lipgloss/v2"
 	"github.com/Gaurav-Gosain/tuios/internal/config"
@@ -19,7 +21,11 @@ const (
 )
 
 var (
-	baseButtonStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#000000"))
+	baseButtonStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#000000"))
+	timestampGutterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	replayStatusStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#000000")).Background(lipgloss.Color("11"))
+	inputLockStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Background(lipgloss.Color("9")).Bold(true)
+	inputLockBorderColor = lipgloss.Color("9")
 )
 
 func getBorder() lipgloss.Border {
@@ -62,21 +68,9 @@ func isDefaultTitle(title, windowID string) bool {
 	return title == "Terminal "+windowID[:8]
 }
 
-// getWindowTitle returns the display name for a window, truncated to fit within maxWidth.
-// Returns empty string if title should be hidden or doesn't fit.
-func getWindowTitle(window *terminal.Window, isRenaming bool, renameBuffer string, maxWidth int) string {
-	windowName := ""
-	if window.CustomName != "" {
-		windowName = window.CustomName
-	} else if window.Title != "" && !isDefaultTitle(window.Title, window.ID) {
-		// Only show terminal-set title if it's not the default "Terminal <id>" format
-		windowName = window.Title
-	}
-
-	if isRenaming {
-		windowName = renameBuffer + "_"
-	}
-
+// fitWindowTitle truncates a window title to fit within maxWidth. Returns
+// an empty string if the title doesn't fit at all.
+func fitWindowTitle(windowName string, maxWidth int) string {
 	if windowName == "" {
 		return ""
 	}
@@ -100,8 +94,9 @@ func getWindowTitle(window *terminal.Window, isRenaming bool, renameBuffer strin
 	return windowName
 }
 
-// renderTitleWithButtons renders a title badge on the left with buttons on the right of a border line.
-func renderTitleWithButtons(windowName string, buttons string, width int, color color.Color, isTop bool) string {
+// renderTitleWithButtons renders a title badge, placed as align says (left
+// by default), with buttons on the right of a border line.
+func renderTitleWithButtons(windowName string, buttons string, width int, color color.Color, isTop bool, align string) string {
 	style := pool.GetStyle()
 	defer pool.PutStyle(style)
 	borderStyle := style.Foreground(color)
@@ -128,21 +123,29 @@ func renderTitleWithButtons(windowName string, buttons string, width int, color
 	buttonsWidth := lipgloss.Width(buttons)
 
 	// Calculate padding between title and buttons
-	middlePadding := width - nameBadgeWidth - buttonsWidth
-	if middlePadding < 0 {
+	padding := width - nameBadgeWidth - buttonsWidth
+	if padding < 0 {
 		// Not enough space, just show buttons
 		return RightString(buttons, width, color)
 	}
+	leftPadding := 0
+	switch align {
+	case "center":
+		leftPadding = min((width-nameBadgeWidth)/2, padding)
+	case "right":
+		leftPadding = max(padding-1, 0) // Keep a border cell before the buttons
+	}
 
-	return borderStyle.Render(borderLeft) +
+	return borderStyle.Render(borderLeft+strings.Repeat(borderChar, leftPadding)) +
 		nameBadge +
-		borderStyle.Render(strings.Repeat(borderChar, middlePadding)) +
+		borderStyle.Render(strings.Repeat(borderChar, padding-leftPadding)) +
 		buttons +
 		borderStyle.Render(borderRight)
 }
 
-// renderTitleBadge renders a centered title badge on a border line.
-func renderTitleBadge(windowName string, width int, color color.Color, isTop bool) string {
+// renderTitleBadge renders a title badge on a border line, placed as align
+// says (centered by default).
+func renderTitleBadge(windowName string, width int, color color.Color, isTop bool, align string) string {
 	style := pool.GetStyle()
 	defer pool.PutStyle(style)
 	borderStyle := style.Foreground(color)
@@ -176,6 +179,12 @@ func renderTitleBadge(windowName string, width int, color color.Color, isTop boo
 	}
 
 	leftPadding := totalPadding / 2
+	switch align {
+	case "left":
+		leftPadding = 0
+	case "right":
+		leftPadding = totalPadding
+	}
 	rightPadding := totalPadding - leftPadding
 
 	return borderStyle.Render(borderLeft+strings.Repeat(borderChar, leftPadding)) +
@@ -183,7 +192,9 @@ func renderTitleBadge(windowName string, width int, color color.Color, isTop boo
 		borderStyle.Render(strings.Repeat(borderChar, rightPadding)+borderRight)
 }
 
-func addToBorder(content string, color color.Color, window *terminal.Window, isRenaming bool, renameBuffer string, isTiling bool) string {
+// addToBorder draws the top and bottom borders of a window, with its buttons
+// and its title where appearance.window_title_position puts it.
+func addToBorder(content string, color color.Color, title string, isTiling bool) string {
 	width := max(lipgloss.Width(content)-2, 0)
 	titlePos := config.WindowTitlePosition
 
@@ -221,7 +232,7 @@ func addToBorder(content string, color color.Color, window *terminal.Window, isR
 
 	windowName := ""
 	if titlePos != "hidden" {
-		windowName = getWindowTitle(window, isRenaming, renameBuffer, titleMaxWidth)
+		windowName = fitWindowTitle(title, titleMaxWidth)
 	}
 
 	borderStyle := style.Foreground(color)
@@ -230,7 +241,7 @@ func addToBorder(content string, color color.Color, window *terminal.Window, isR
 	var topBorder string
 	if titlePos == "top" && windowName != "" {
 		// Title on top with buttons on the right
-		topBorder = renderTitleWithButtons(windowName, buttons, width, color, true)
+		topBorder = renderTitleWithButtons(windowName, buttons, width, color, true, config.TitleAlign)
 	} else {
 		// Normal top border with buttons on right
 		topBorder = RightString(buttons, width, color)
@@ -239,7 +250,7 @@ func addToBorder(content string, color color.Color, window *terminal.Window, isR
 	// Build bottom border
 	var bottomBorder string
 	if titlePos == "bottom" && windowName != "" {
-		bottomBorder = renderTitleBadge(windowName, width, color, false)
+		bottomBorder = renderTitleBadge(windowName, width, color, false, config.TitleAlign)
 	} else {
 		bottomBorder = borderStyle.Render(config.GetWindowBorderBottomLeft() + strings.Repeat(config.GetWindowBorderBottom(), width) + config.GetWindowBorderBottomRight())
 	}
@@ -301,6 +312,94 @@ func renderStyledText(style lipgloss.Style, text string) string {
 	return prefix + text + suffix
 }
 
+// fitCellContent returns the text to draw for a cell's content so that it
+// takes as many columns, measured the way lipgloss lays out lines, as the
+// emulator gave the cell. The emulator's ambiguous and emoji widths can
+// differ from that: narrower content is padded with spaces, and skip is the
+// number of following columns that wider content covers.
+func fitCellContent(content string, width int) (text string, skip int) {
+	if len(content) == 1 && width == 1 {
+		return content, 0
+	}
+	measured := ansi.StringWidth(content)
+	if measured < width {
+		return content + strings.Repeat(" ", width-measured), 0
+	}
+	return content, measured - width
+}
+
+// withAppColors returns cell with the default colors an application set with
+// OSC 10 and OSC 11 filled in where it uses the default colors. The cell is
+// copied rather than modified. A nil cell becomes a blank one.
+func withAppColors(cell *uv.Cell, fg, bg color.Color) *uv.Cell {
+	needsFg := fg != nil && (cell == nil || cell.Style.Fg == nil)
+	needsBg := bg != nil && (cell == nil || cell.Style.Bg == nil)
+	if !needsFg && !needsBg {
+		return cell
+	}
+
+	c := uv.EmptyCell
+	if cell != nil {
+		c = *cell
+	}
+	if needsFg {
+		c.Style.Fg = fg
+	}
+	if needsBg {
+		c.Style.Bg = bg
+	}
+	return &c
+}
+
+// renderTimestampGutter renders the time gutter for a line first written at
+// t: the time of day for lines from today, the date for older lines and
+// blanks when the time is unknown.
+func renderTimestampGutter(t time.Time, now time.Time) string {
+	label := ""
+	if !t.IsZero() {
+		label = t.Format("15:04:05")
+		if ty, tm, td := t.Date(); ty != now.Year() || tm != now.Month() || td != now.Day() {
+			label = t.Format("Jan 02")
+		}
+	}
+	return timestampGutterStyle.Render(fmt.Sprintf("%-*s", terminal.TimestampGutterWidth, label))
+}
+
+// renderReplayStatus renders the status line drawn over the last row of a
+// replaying window.
+func renderReplayStatus(r *terminal.Replay, width int) string {
+	state := "▶"
+	if r.Paused {
+		state = "⏸"
+	}
+	status := fmt.Sprintf(" %s REPLAY %s / %s  %gx  %s",
+		state, formatReplayTime(r.Position), formatReplayTime(r.Duration), r.Speed,
+		r.Time().Format("15:04:05"))
+	if r.Truncated {
+		status += " (older output dropped)"
+	}
+	status += "  space pause · h/l seek · -/+ speed · q quit"
+	status = ansi.Truncate(status, width, "…")
+	return replayStatusStyle.Render(status + strings.Repeat(" ", max(width-ansi.StringWidth(status), 0)))
+}
+
+// addInputLockBanner draws the input lock banner over the first row of a
+// window's content.
+func addInputLockBanner(content, unlockHint string, width int) string {
+	status := ansi.Truncate(" INPUT LOCKED  "+unlockHint+" to unlock", width, "…")
+	banner := inputLockStyle.Render(status + strings.Repeat(" ", max(width-ansi.StringWidth(status), 0)))
+	if _, rest, ok := strings.Cut(content, "\n"); ok {
+		return banner + "\n" + rest
+	}
+	return banner
+}
+
+// formatReplayTime formats a replay position as minutes and seconds.
+func formatReplayTime(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
 func shouldApplyStyle(cell *uv.Cell) bool {
 	if cell == nil {
 		return false
@@ -541,6 +640,11 @@ func (m *OS) isPositionInSelection(window *terminal.Window, x, y int) bool {
 	startX, startY := window.SelectionStart.X, window.SelectionStart.Y
 	endX, endY := window.SelectionEnd.X, window.SelectionEnd.Y
 
+	if window.SelectionMode == 3 {
+		// Block selection: the same columns of every line
+		return y >= min(startY, endY) && y <= max(startY, endY) && x >= min(startX, endX) && x <= max(startX, endX)
+	}
+
 	if startY > endY || (startY == endY && startX > endX) {
 		startX, endX = endX, startX
 		startY, endY = endY, startY
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestFitCellContent verifies cell content is drawn as wide as the emulator
+// made the cell when its ambiguous and emoji widths differ from lipgloss.
+func TestFitCellContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		width    int
+		wantText string
+		wantSkip int
+	}{
+		{"ascii", "a", 1, "a", 0},
+		{"wide ambiguous", "○", 2, "○ ", 0},
+		{"wide emoji", "😀", 2, "😀", 0},
+		{"narrow emoji", "😀", 1, "😀", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, skip := fitCellContent(tt.content, tt.width)
+			if text != tt.wantText || skip != tt.wantSkip {
+				t.Errorf("fitCellContent(%q, %d) = %q, %d; want %q, %d", tt.content, tt.width, text, skip, tt.wantText, tt.wantSkip)
+			}
+			if got := ansi.StringWidth(text); got != tt.width+skip {
+				t.Errorf("text measures %d columns, want %d", got, tt.width+skip)
+			}
+		})
+	}
+}
+
+// TestRenderTerminalHyperlinks verifies OSC 8 links reach hosts that support
+// them and are left out for others.
+func TestRenderTerminalHyperlinks(t *testing.T) {
+	t.Cleanup(ResetHostCapabilities)
+	link := ansi.SetHyperlink("https://example.com")
+	for _, supported := range []bool{true, false} {
+		SetClientCapabilities(&HostCapabilities{Hyperlinks: supported})
+		emu := vt.NewEmulator(10, 2)
+		_, _ = emu.Write([]byte("a" + link + "link" + ansi.ResetHyperlink() + "b"))
+		w := &terminal.Window{Terminal: emu, Width: 12, Height: 4, ContentDirty: true}
+
+		content := (&OS{}).renderTerminal(w, false, false)
+		if got := strings.Contains(content, link+"link"+ansi.ResetHyperlink()); got != supported {
+			t.Errorf("hyperlinks supported %v: link in output %v, content %q", supported, got, content)
+		}
+	}
+}
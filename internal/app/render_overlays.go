@@ -10,6 +10,7 @@ import (
 	"github.com/Gaurav-Gosain/tuios/internal/tape"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 	"github.com/Gaurav-Gosain/tuios/internal/theme"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func (m *OS) renderOverlays() []*lipgloss.Layer {
@@ -17,8 +18,8 @@ func (m *OS) renderOverlays() []*lipgloss.Layer {
 
 	isRecording := m.TapeRecorder != nil && m.TapeRecorder.IsRecording()
 
-	// Show clock/status unless hidden (but always show if recording or prefix active)
-	if !config.HideClock || isRecording || m.PrefixActive {
+	// Show clock/status unless hidden (but always show if recording, prefix or passthrough active)
+	if !config.HideClock || isRecording || m.PrefixActive || m.KeyPassthrough {
 		currentTime := time.Now().Format("15:04:05")
 		var statusText string
 
@@ -26,6 +27,8 @@ func (m *OS) renderOverlays() []*lipgloss.Layer {
 			statusText = config.TapeRecordingIndicator + " | " + currentTime
 		} else if m.PrefixActive {
 			statusText = "PREFIX | " + currentTime
+		} else if m.KeyPassthrough {
+			statusText = "PASSTHROUGH | " + currentTime
 		} else {
 			statusText = currentTime
 		}
@@ -43,6 +46,10 @@ func (m *OS) renderOverlays() []*lipgloss.Layer {
 			timeStyle = timeStyle.
 				Background(lipgloss.Color("#ff6b6b")).
 				Foreground(lipgloss.Color("#ffffff"))
+		} else if m.KeyPassthrough {
+			timeStyle = timeStyle.
+				Background(lipgloss.Color("#4865f2")).
+				Foreground(lipgloss.Color("#ffffff"))
 		} else {
 			timeStyle = timeStyle.
 				Background(lipgloss.Color("#1a1a2e"))
@@ -115,6 +122,56 @@ func (m *OS) renderOverlays() []*lipgloss.Layer {
 		layers = append(layers, quitLayer)
 	}
 
+	if m.ShowPasteConfirm {
+		pasteContent, width, height := m.renderPasteConfirmDialog()
+		x := (m.GetRenderWidth() - width) / 2
+		y := (m.GetRenderHeight() - height) / 2
+		pasteLayer := lipgloss.NewLayer(pasteContent).
+			X(x).Y(y).Z(config.ZIndexHelp + 1).ID("paste-confirm")
+		layers = append(layers, pasteLayer)
+	}
+
+	if m.ShowCloseAllConfirm {
+		closeContent, width, height := m.renderCloseAllConfirmDialog()
+		x := (m.GetRenderWidth() - width) / 2
+		y := (m.GetRenderHeight() - height) / 2
+		closeLayer := lipgloss.NewLayer(closeContent).
+			X(x).Y(y).Z(config.ZIndexHelp + 1).ID("close-all-confirm")
+		layers = append(layers, closeLayer)
+	}
+
+	if m.Popup != nil {
+		popupContent, width, height := m.renderPopupDialog()
+		x := (m.GetRenderWidth() - width) / 2
+		y := (m.GetRenderHeight() - height) / 2
+		popupLayer := lipgloss.NewLayer(popupContent).
+			X(x).Y(y).Z(config.ZIndexHelp + 1).ID("popup")
+		layers = append(layers, popupLayer)
+	}
+
+	layers = m.appendPromptLayer(layers, &m.RunPrompt, "run-prompt")
+	layers = m.appendPromptLayer(layers, &m.TagPrompt, "tag-prompt")
+
+	if w := m.GetFocusedWindow(); m.MoveMode && w != nil {
+		readout, width, height := m.renderMoveReadout()
+		x := min(max(w.X+(w.Width-width)/2, 0), max(m.GetRenderWidth()-width, 0))
+		y := min(max(w.Y+(w.Height-height)/2, 0), max(m.GetRenderHeight()-height, 0))
+		readoutLayer := lipgloss.NewLayer(readout).
+			X(x).Y(y).Z(config.ZIndexHelp + 1).ID("move-readout")
+		layers = append(layers, readoutLayer)
+	}
+
+	layers = m.appendPromptLayer(layers, &m.FindPrompt, "find-prompt")
+
+	if m.ShowRestorePrompt {
+		restoreContent, width, height := m.renderRestorePromptDialog()
+		x := (m.GetRenderWidth() - width) / 2
+		y := (m.GetRenderHeight() - height) / 2
+		restoreLayer := lipgloss.NewLayer(restoreContent).
+			X(x).Y(y).Z(config.ZIndexHelp + 1).ID("restore-prompt")
+		layers = append(layers, restoreLayer)
+	}
+
 	if m.ShowHelp {
 		helpContent := m.RenderHelpMenu(m.GetRenderWidth(), m.GetRenderHeight())
 
@@ -205,6 +262,59 @@ func (m *OS) renderOverlays() []*lipgloss.Layer {
 		layers = append(layers, statsLayer)
 	}
 
+	if m.ShowScrollbackStats {
+		centeredStats := lipgloss.Place(m.GetRenderWidth(), m.GetRenderHeight(),
+			lipgloss.Center, lipgloss.Center, m.renderScrollbackStats())
+
+		statsLayer := lipgloss.NewLayer(centeredStats).
+			X(0).Y(0).Z(config.ZIndexLogs).ID("scrollback-stats")
+
+		layers = append(layers, statsLayer)
+	}
+
+	if m.ShowWindowHints {
+		layers = append(layers, m.renderWindowHints()...)
+	}
+
+	if m.ImageHints != nil {
+		layers = append(layers, m.renderImageHints()...)
+	}
+
+	if m.ShowWindowInfo {
+		centeredInfo := lipgloss.Place(m.GetRenderWidth(), m.GetRenderHeight(),
+			lipgloss.Center, lipgloss.Center, m.renderWindowInfo())
+
+		infoLayer := lipgloss.NewLayer(centeredInfo).
+			X(0).Y(0).Z(config.ZIndexLogs).ID("window-info")
+
+		layers = append(layers, infoLayer)
+	}
+
+	if m.Screensaver != nil {
+		layers = append(layers, lipgloss.NewLayer(m.Screensaver.Render()).
+			X(0).Y(0).Z(config.ZIndexScreensaver).ID("screensaver"))
+	}
+
+	if m.ShowMinimizedMenu {
+		centeredMenu := lipgloss.Place(m.GetRenderWidth(), m.GetRenderHeight(),
+			lipgloss.Center, lipgloss.Center, m.renderMinimizedMenu())
+
+		menuLayer := lipgloss.NewLayer(centeredMenu).
+			X(0).Y(0).Z(config.ZIndexLogs).ID("minimized-menu")
+
+		layers = append(layers, menuLayer)
+	}
+
+	if m.ShowCommandHistory {
+		centeredHistory := lipgloss.Place(m.GetRenderWidth(), m.GetRenderHeight(),
+			lipgloss.Center, lipgloss.Center, m.renderCommandHistory())
+
+		historyLayer := lipgloss.NewLayer(centeredHistory).
+			X(0).Y(0).Z(config.ZIndexLogs).ID("command-history")
+
+		layers = append(layers, historyLayer)
+	}
+
 	if m.ShowLogs {
 		logTitle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("14")).
@@ -575,7 +685,7 @@ func (m *OS) renderOverlays() []*lipgloss.Layer {
 		}
 	}
 
-	focusedWindow := m.GetFocusedWindow()
+	focusedWindow := m.GetFocusedPane()
 	if focusedWindow != nil && focusedWindow.CopyMode != nil &&
 		focusedWindow.CopyMode.Active &&
 		focusedWindow.CopyMode.State == terminal.CopyModeSearch {
@@ -621,7 +731,7 @@ func (m *OS) renderOverlays() []*lipgloss.Layer {
 			rightMargin := 2
 			dockOffset := 0
 			if config.DockbarPosition == "bottom" {
-				dockOffset = config.DockHeight
+				dockOffset = m.DockHeight()
 			}
 
 			x := m.GetRenderWidth() - contentWidth - rightMargin
@@ -645,7 +755,60 @@ func (m *OS) renderOverlays() []*lipgloss.Layer {
 	return layers
 }
 
+// quitListLimit is how many running programs the quit confirmation lists
+// before summarizing the rest.
+const quitListLimit = 8
+
+// renderQuitConfirmDialog renders the quit confirmation with a summary of
+// the windows that will close and the programs running in them.
 func (m *OS) renderQuitConfirmDialog() (string, int, int) {
+	running := m.QuitRunning
+	var details []string
+	if len(m.Windows) > 0 {
+		details = append(details, fmt.Sprintf("%d windows will close", len(m.Windows)))
+	}
+	if len(running) > 0 {
+		details = append(details, fmt.Sprintf("%d are running programs:", len(running)), "")
+		for n, line := range running {
+			if n == quitListLimit {
+				details = append(details, fmt.Sprintf("and %d more", len(running)-quitListLimit))
+				break
+			}
+			details = append(details, line)
+		}
+	}
+	if m.IsDaemonSession {
+		details = append(details, "", "The session "+m.SessionName+" will end.")
+	}
+	return renderConfirmDialog("Quit TUIOS?", details, m.QuitConfirmSelection)
+}
+
+func (m *OS) renderRestorePromptDialog() (string, int, int) {
+	var details []string
+	if r := m.PendingRecovery; r != nil && r.State != nil {
+		workspaces := make(map[int]bool)
+		for _, w := range r.State.Windows {
+			workspaces[w.Workspace] = true
+		}
+		details = append(details,
+			fmt.Sprintf("%d windows on %d workspaces", len(r.State.Windows), len(workspaces)),
+			"Saved "+r.SavedAt.Format("Jan 2 15:04:05")+" ("+r.Reason+")")
+	}
+	return renderConfirmDialog("Restore previous session?", details, m.RestorePromptSelection)
+}
+
+func (m *OS) renderPasteConfirmDialog() (string, int, int) {
+	lines := strings.Count(strings.TrimRight(m.PendingPaste, "\r\n"), "\n") + 1
+	details := []string{
+		fmt.Sprintf("%d lines, %d characters", lines, len(m.PendingPaste)),
+		"Each line will run as a shell command.",
+	}
+	return renderConfirmDialog("Paste multiple lines?", details, m.PasteConfirmSelection)
+}
+
+// renderConfirmDialog renders a centered yes/no dialog with optional detail
+// lines below the title. selection is 0 for yes and 1 for no.
+func renderConfirmDialog(titleText string, details []string, selection int) (string, int, int) {
 	borderColor := theme.HelpBorder()
 	selectedColor := theme.HelpTabActive()
 	unselectedColor := theme.HelpGray()
@@ -653,14 +816,14 @@ func (m *OS) renderQuitConfirmDialog() (string, int, int) {
 	title := lipgloss.NewStyle().
 		Foreground(selectedColor).
 		Bold(true).
-		Render("Quit TUIOS?")
+		Render(titleText)
 
 	yesButtonContent := "yes"
 	noButtonContent := "no"
 
 	var yesButton, noButton string
 
-	if m.QuitConfirmSelection == 0 {
+	if selection == 0 {
 		yesButton = lipgloss.NewStyle().
 			Foreground(selectedColor).
 			Bold(true).
@@ -694,12 +857,17 @@ func (m *OS) renderQuitConfirmDialog() (string, int, int) {
 
 	buttonRow := lipgloss.JoinHorizontal(lipgloss.Center, yesButton, "   ", noButton)
 
-	dialogContent := lipgloss.JoinVertical(
-		lipgloss.Center,
-		title,
-		"",
-		buttonRow,
-	)
+	rows := []string{title}
+	if len(details) > 0 {
+		detailStyle := lipgloss.NewStyle().Foreground(unselectedColor)
+		rows = append(rows, "")
+		for _, line := range details {
+			rows = append(rows, detailStyle.Render(line))
+		}
+	}
+	rows = append(rows, "", buttonRow)
+
+	dialogContent := lipgloss.JoinVertical(lipgloss.Center, rows...)
 
 	dialogBox := lipgloss.NewStyle().
 		Border(getBorder()).
@@ -712,3 +880,168 @@ func (m *OS) renderQuitConfirmDialog() (string, int, int) {
 
 	return dialogBox, width, height
 }
+
+// renderScrollbackStats renders the per-window scrollback memory overlay.
+func (m *OS) renderScrollbackStats() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	limit := func(n int) string {
+		if n <= 0 {
+			return "unlimited"
+		}
+		return formatBytes(n)
+	}
+
+	stats, total := m.ScrollbackStats()
+	maxRows := max(m.GetRenderHeight()-14, 1)
+
+	lines := []string{
+		titleStyle.Render("Scrollback Memory"),
+		"",
+		labelStyle.Render("Total:      ") + valueStyle.Render(formatBytes(total)) +
+			dimStyle.Render(" / "+limit(config.ScrollbackMemoryBudget)),
+		labelStyle.Render("Per window: ") + valueStyle.Render(limit(config.ScrollbackWindowMemoryLimit)),
+		"",
+		labelStyle.Render(fmt.Sprintf("%-20s %10s %16s %10s", "Window", "Memory", "Lines", "Viewed")),
+	}
+
+	now := time.Now()
+	for i, usage := range stats {
+		if i == maxRows {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("... and %d more", len(stats)-maxRows)))
+			break
+		}
+		name := usage.Name
+		if name == "" {
+			name = usage.WindowID[:min(8, len(usage.WindowID))]
+		}
+		if usage.Focused {
+			name = "* " + name
+		}
+		viewed := "never"
+		if !usage.LastViewed.IsZero() {
+			viewed = now.Sub(usage.LastViewed).Truncate(time.Second).String() + " ago"
+		}
+		row := fmt.Sprintf("%-20s %10s %16s %10s", ansi.Truncate(name, 20, "…"), formatBytes(usage.Bytes),
+			fmt.Sprintf("%d/%d", usage.Lines, usage.MaxLines), viewed)
+		lines = append(lines, row)
+	}
+	if len(stats) == 0 {
+		lines = append(lines, dimStyle.Render("No windows"))
+	}
+
+	lines = append(lines, "", dimStyle.Render("Press 'q'/'esc' to exit, 't' to trim now"))
+
+	return lipgloss.NewStyle().
+		Border(getBorder()).
+		BorderForeground(lipgloss.Color("13")).
+		Padding(1, 2).
+		Background(lipgloss.Color("#1a1a2a")).
+		Render(strings.Join(lines, "\n"))
+}
+
+// renderWindowInfo renders the info overlay for the focused pane.
+func (m *OS) renderWindowInfo() string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	window := m.GetFocusedWindow()
+	if window == nil || window.ActivePane().Terminal == nil {
+		return ""
+	}
+	pane := window.ActivePane()
+
+	limit, source := pane.ScrollbackLimit()
+	sourceLabel := map[string]string{
+		"window": "set for this window",
+		"rule":   "from window rule",
+		"global": "global limit",
+	}[source]
+	scrollback := fmt.Sprintf("%d / %d lines", pane.Terminal.ScrollbackLen(), limit)
+	if limit == 0 {
+		scrollback = "disabled"
+	}
+
+	lines := []string{
+		titleStyle.Render("Window Info"),
+		"",
+		labelStyle.Render("Name:       ") + valueStyle.Render(ansi.Truncate(m.getWindowDisplayName(pane), 40, "…")),
+		labelStyle.Render("Size:       ") + valueStyle.Render(fmt.Sprintf("%dx%d", pane.Terminal.Width(), pane.Terminal.Height())),
+		labelStyle.Render("Workspace:  ") + valueStyle.Render(fmt.Sprint(window.Workspace)),
+	}
+	if cwd := shortenHome(pane.WorkingDirectory()); cwd != "" {
+		if excess := ansi.StringWidth(cwd) - 40; excess > 0 {
+			cwd = ansi.TruncateLeft(cwd, excess+1, "…")
+		}
+		lines = append(lines, labelStyle.Render("Directory:  ")+valueStyle.Render(cwd))
+	}
+	if flags := m.windowFlags(window); flags != "" {
+		lines = append(lines, labelStyle.Render("Flags:      ")+valueStyle.Render(flags))
+	}
+	if marks := m.WindowMarkNames(window.ID); len(marks) > 0 {
+		lines = append(lines, labelStyle.Render("Marks:      ")+valueStyle.Render(formatMarks(marks)))
+	}
+	if len(window.Tags) > 0 {
+		lines = append(lines, labelStyle.Render("Tags:       ")+valueStyle.Render(ansi.Truncate(formatTags(window.Tags), 40, "…")))
+	}
+	lines = append(lines,
+		"",
+		labelStyle.Render("Scrollback: ")+valueStyle.Render(scrollback)+dimStyle.Render(" ("+sourceLabel+")"),
+		labelStyle.Render("Memory:     ")+valueStyle.Render(formatBytes(pane.Terminal.ScrollbackMemory())),
+		labelStyle.Render("Output:     ")+valueStyle.Render(formatBytes(int(pane.TotalOutput()))),
+	)
+	if usage, ok := m.windowUsage(window.ID); ok {
+		lines = append(lines, labelStyle.Render("Usage:      ")+valueStyle.Render(formatCPU(usage.CPU)+" CPU, "+formatBytes(int(usage.Memory)))+
+			dimStyle.Render(" (all processes)"))
+	}
+	lines = append(lines, "")
+	lines = append(lines, m.windowInfoProcessLines(labelStyle, valueStyle, dimStyle)...)
+	if m.WindowInfoEditing {
+		lines = append(lines,
+			labelStyle.Render("Scrollback limit: ")+valueStyle.Render(m.WindowInfoBuffer+"█"),
+			dimStyle.Render("Enter to apply (empty for default), Esc to cancel"))
+	} else {
+		lines = append(lines, dimStyle.Render("Press 's' to set the scrollback limit, 'q'/'esc' to close"))
+	}
+
+	return lipgloss.NewStyle().
+		Border(getBorder()).
+		BorderForeground(lipgloss.Color("13")).
+		Padding(1, 2).
+		Background(lipgloss.Color("#1a1a2a")).
+		Render(strings.Join(lines, "\n"))
+}
+
+// windowInfoProcessLines renders the shell of the pane in the window info
+// overlay, when it started and the processes running below it.
+func (m *OS) windowInfoProcessLines(labelStyle, valueStyle, dimStyle lipgloss.Style) []string {
+	info := m.windowInfo
+	if info == nil || info.PID <= 0 {
+		return []string{labelStyle.Render("PID:        ") + dimStyle.Render("unknown"), ""}
+	}
+	lines := []string{labelStyle.Render("PID:        ") + valueStyle.Render(fmt.Sprint(info.PID))}
+	if !info.Started.IsZero() {
+		lines = append(lines, labelStyle.Render("Started:    ")+valueStyle.Render(info.Started.Format("Jan 2 15:04:05"))+
+			dimStyle.Render(fmt.Sprintf(" (%s ago)", time.Since(info.Started).Truncate(time.Second))))
+	}
+	if len(info.Processes) > 0 {
+		lines = append(lines, labelStyle.Render("Processes:"))
+		for _, proc := range info.Processes {
+			name := ansi.Truncate(proc.Name, 24, "…")
+			details := fmt.Sprintf(" %d  %s", proc.PID, formatBytes(int(proc.Memory)))
+			if cpu, ok := m.processCPU(proc.PID); ok {
+				details += "  " + formatCPU(cpu)
+			}
+			lines = append(lines, "  "+strings.Repeat("  ", proc.Depth)+valueStyle.Render(name)+dimStyle.Render(details))
+		}
+		if info.More {
+			lines = append(lines, dimStyle.Render("  …"))
+		}
+	}
+	return append(lines, "")
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"image/color"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/layout"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+)
+
+// renderPanes renders the content area of a split window: each pane's
+// terminal at its position, with separators between them. Separators next
+// to the active pane use the window's border color.
+func (m *OS) renderPanes(window *terminal.Window, isFocused bool, inTerminalMode bool, borderColor color.Color) string {
+	if window.IsBeingManipulated && m.Resizing {
+		return m.renderResizeIndicator(window)
+	}
+
+	width := max(window.Width-2, 1)
+	height := max(window.Height-2, 1)
+	activePane := window.ActivePane()
+	active := window.ActivePaneRect()
+
+	layers := []*lipgloss.Layer{
+		lipgloss.NewLayer(renderPaneSeparators(window.Panes.Panes, width, height, active, borderColor)),
+	}
+	for _, pane := range window.Panes.Panes {
+		content := m.renderTerminal(pane.Window, isFocused && pane.Window == activePane, inTerminalMode)
+		if pane.Window != window {
+			pane.Window.ClearDirtyFlags()
+		}
+		layers = append(layers, lipgloss.NewLayer(content).X(pane.Rect.X).Y(pane.Rect.Y).Z(1))
+	}
+	return lipgloss.NewCanvas(layers...).Render()
+}
+
+// renderPaneSeparators draws the lines between panes over a width x height
+// area, leaving the cells covered by panes blank.
+func renderPaneSeparators(panes []*terminal.Pane, width, height int, active layout.Rect, borderColor color.Color) string {
+	inPane := func(x, y int) bool {
+		for _, pane := range panes {
+			r := pane.Rect
+			if x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H {
+				return true
+			}
+		}
+		return false
+	}
+	nearActive := func(x, y int) bool {
+		return x >= active.X-1 && x <= active.X+active.W && y >= active.Y-1 && y <= active.Y+active.H
+	}
+
+	activeStyle := lipgloss.NewStyle().Foreground(borderColor)
+	inactiveStyle := lipgloss.NewStyle().Foreground(theme.BorderUnfocused())
+
+	var b strings.Builder
+	for y := range height {
+		for x := range width {
+			if inPane(x, y) {
+				b.WriteByte(' ')
+				continue
+			}
+			char := "─"
+			if inPane(x-1, y) || inPane(x+1, y) {
+				char = "│"
+			}
+			if nearActive(x, y) {
+				b.WriteString(activeStyle.Render(char))
+			} else {
+				b.WriteString(inactiveStyle.Render(char))
+			}
+		}
+		if y < height-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
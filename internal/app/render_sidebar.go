@@ -2,6 +2,7 @@ package app
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -56,18 +57,7 @@ func (m *OS) CalculateSidebarLayout() SidebarLayout {
 		WorkspaceY:    make(map[int]int),
 	}
 
-	// Group windows by workspace
-	workspaceWindows := make(map[int][]int) // workspace -> window indices
-	for i, w := range m.Windows {
-		workspaceWindows[w.Workspace] = append(workspaceWindows[w.Workspace], i)
-	}
-
-	// Get sorted workspace numbers
-	workspaces := make([]int, 0, len(workspaceWindows))
-	for ws := range workspaceWindows {
-		workspaces = append(workspaces, ws)
-	}
-	sort.Ints(workspaces)
+	sections := m.sidebarSections()
 
 	// Match renderSidebar exactly:
 	// Line 0: Title "Windows"
@@ -75,15 +65,13 @@ func (m *OS) CalculateSidebarLayout() SidebarLayout {
 	// +1 for border
 	currentY := 3
 
-	for wsIdx, ws := range workspaces {
-		indices := workspaceWindows[ws]
-
+	for sectionIdx, section := range sections {
 		// Workspace header line
-		layout.WorkspaceY[ws] = currentY
+		layout.WorkspaceY[section.Workspace] = currentY
 		currentY++
 
 		// Window items in this workspace
-		for _, idx := range indices {
+		for _, idx := range section.Windows {
 			layout.ItemPositions = append(layout.ItemPositions, SidebarItemPosition{
 				WindowIndex: idx,
 				StartY:      currentY,
@@ -93,7 +81,7 @@ func (m *OS) CalculateSidebarLayout() SidebarLayout {
 		}
 
 		// Gap between workspaces (except after last)
-		if wsIdx < len(workspaces)-1 {
+		if sectionIdx < len(sections)-1 {
 			currentY++
 		}
 	}
@@ -101,6 +89,46 @@ func (m *OS) CalculateSidebarLayout() SidebarLayout {
 	return layout
 }
 
+// sidebarSection is a heading of the sidebar and the windows listed below it.
+type sidebarSection struct {
+	Workspace int   // Workspace whose windows are listed (0 for every window by CPU use)
+	Windows   []int // Indices of the windows in m.Windows, in list order
+}
+
+// sidebarSections returns what the sidebar lists: the windows of each
+// workspace under its heading, or every window by CPU use under a single
+// heading.
+func (m *OS) sidebarSections() []sidebarSection {
+	if m.SidebarSortCPU {
+		if indices := m.sidebarByCPU(); len(indices) > 0 {
+			return []sidebarSection{{Windows: indices}}
+		}
+		return nil
+	}
+
+	// Group windows by workspace
+	workspaceWindows := make(map[int][]int) // workspace -> window indices
+	for i, w := range m.Windows {
+		if !m.sidebarShows(w) {
+			continue
+		}
+		workspaceWindows[w.Workspace] = append(workspaceWindows[w.Workspace], i)
+	}
+
+	// Get sorted workspace numbers
+	workspaces := make([]int, 0, len(workspaceWindows))
+	for ws := range workspaceWindows {
+		workspaces = append(workspaces, ws)
+	}
+	sort.Ints(workspaces)
+
+	sections := make([]sidebarSection, len(workspaces))
+	for i, ws := range workspaces {
+		sections[i] = sidebarSection{Workspace: ws, Windows: workspaceWindows[ws]}
+	}
+	return sections
+}
+
 // renderSidebar renders the browser-style sidebar with window list
 // Uses same design language as dock and help overlays
 func (m *OS) renderSidebar() *lipgloss.Layer {
@@ -108,6 +136,11 @@ func (m *OS) renderSidebar() *lipgloss.Layer {
 		return nil
 	}
 
+	cacheKey := m.sidebarStateKey()
+	if cached := m.sidebarCache.get(cacheKey); cached != nil {
+		return cached
+	}
+
 	sidebarWidth := m.GetSidebarWidth()
 	sidebarHeight := m.GetRenderHeight()
 	topMargin := m.GetTopMargin()
@@ -143,40 +176,43 @@ func (m *OS) renderSidebar() *lipgloss.Layer {
 	var lines []string
 
 	// Title
-	lines = append(lines, titleStyle.Render("Windows"))
-	lines = append(lines, "")
-
-	// Group windows by workspace
-	workspaceWindows := make(map[int][]int)
-	for i, w := range m.Windows {
-		workspaceWindows[w.Workspace] = append(workspaceWindows[w.Workspace], i)
+	title := "Windows"
+	if m.SidebarJoinTarget != "" {
+		title = "Merge into window"
+	} else if m.SidebarGroupTarget != "" {
+		title = "Group with window"
+	} else if m.SidebarTag != "" {
+		title = "Windows #" + m.SidebarTag
 	}
+	lines = append(lines, titleStyle.Render(title))
+	lines = append(lines, "")
 
-	// Sort workspaces
-	workspaces := make([]int, 0, len(workspaceWindows))
-	for ws := range workspaceWindows {
-		workspaces = append(workspaces, ws)
-	}
-	sort.Ints(workspaces)
+	sections := m.sidebarSections()
 
 	// Pill characters from config (same as dock)
 	leftPill := config.GetDockPillLeftChar()
 	rightPill := config.GetDockPillRightChar()
 
 	// Render each workspace
-	for wsIdx, ws := range workspaces {
-		indices := workspaceWindows[ws]
+	for sectionIdx, section := range sections {
+		ws := section.Workspace
 
 		// Workspace header
 		wsMarker := ""
 		if ws == m.CurrentWorkspace {
 			wsMarker = "*"
 		}
-		wsHeader := fmt.Sprintf(" Workspace %d %s", ws, wsMarker)
+		if m.WorkspaceAutoTiling(ws) {
+			wsMarker += config.GetDockModeIconTiling()
+		}
+		wsHeader := fmt.Sprintf(" Workspace %s %s", m.workspaceLabel(ws), wsMarker)
+		if ws == 0 {
+			wsHeader = " By CPU use"
+		}
 		lines = append(lines, workspaceStyle.Render(wsHeader))
 
 		// Window items
-		for _, idx := range indices {
+		for _, idx := range section.Windows {
 			w := m.Windows[idx]
 
 			// Get display name
@@ -188,10 +224,26 @@ func (m *OS) renderSidebar() *lipgloss.Layer {
 				displayName = "terminal"
 			}
 
-			// Truncate if needed
-			maxLen := sidebarWidth - 12
-			if len(displayName) > maxLen {
-				displayName = displayName[:maxLen-1] + "…"
+			// Truncate if needed, leaving room for the group marker, icon, badge and CPU use
+			icon := windowIconPrefix(w)
+			maxLen := sidebarWidth - 12 - len([]rune(icon))
+			if badge := windowBadge(w); badge != "" {
+				maxLen -= len([]rune(badge)) + 1
+			}
+			cpu := ""
+			if usage, ok := m.windowUsage(w.ID); ok {
+				cpu = formatCPU(usage.CPU)
+				maxLen -= len(cpu) + 1
+			}
+			if w.Group != "" {
+				maxLen -= 2
+			}
+			marked := m.SidebarMarked[w.ID]
+			if marked {
+				maxLen -= 2
+			}
+			if len([]rune(displayName)) > maxLen {
+				displayName = string([]rune(displayName)[:maxLen-1]) + "…"
 			}
 
 			// Determine colors based on state
@@ -242,27 +294,43 @@ func (m *OS) renderSidebar() *lipgloss.Layer {
 				Foreground(lipgloss.Color(textFg)).
 				Bold(isBold)
 
-			// Add minimized marker
+			// Add batch, group and minimized markers
 			prefix := ""
+			if marked {
+				prefix = lipgloss.NewStyle().Foreground(lipgloss.Color("#50fa7b")).Bold(true).Render(sidebarMarkIcon()) + " "
+			}
+			if w.Group != "" {
+				prefix += lipgloss.NewStyle().Foreground(groupAccentColor(w.Group)).Render("●") + " "
+			}
 			if w.Minimized {
-				prefix = "[m] "
+				prefix += "[m] "
+			}
+			if w.Floating {
+				prefix += "[f] "
 			}
+			prefix += icon
 
 			itemLine := fmt.Sprintf(" %s%s%s %s%s",
 				leftCircle, numLabel, rightCircle,
 				prefix, nameStyle.Render(displayName))
+			if badge := windowBadge(w); badge != "" {
+				itemLine += " " + lipgloss.NewStyle().Foreground(lipgloss.Color(windowBadgeColor(w))).Render(badge)
+			}
+			if cpu != "" {
+				itemLine += " " + lipgloss.NewStyle().Foreground(mutedColor).Render(cpu)
+			}
 
 			lines = append(lines, itemLine)
 		}
 
 		// Spacing between workspaces
-		if wsIdx < len(workspaces)-1 {
+		if sectionIdx < len(sections)-1 {
 			lines = append(lines, "")
 		}
 	}
 
 	// Empty state
-	if len(m.Windows) == 0 {
+	if len(sections) == 0 {
 		emptyStyle := lipgloss.NewStyle().
 			Width(sidebarWidth-4).
 			Foreground(mutedColor).
@@ -281,7 +349,17 @@ func (m *OS) renderSidebar() *lipgloss.Layer {
 		Foreground(mutedColor).
 		Italic(true).
 		Padding(0, 1)
-	lines = append(lines, footerStyle.Render("j/k:nav  Enter:select  q:close"))
+	footer := "j/k:nav  Enter:select  Space:mark  t:tag  c:by CPU  q:close"
+	if m.SidebarSortCPU {
+		footer = "j/k:nav  Enter:select  Space:mark  c:by workspace  q:close"
+	}
+	if m.SidebarTag != "" {
+		footer = "j/k:nav  Enter:select  t:next tag  v:tag view  q:close"
+	}
+	if marked := len(m.SidebarMarkedWindows()); marked > 0 {
+		footer = fmt.Sprintf("%d marked  x:close  m:minimize  g:group  Shift+1-9:move  Esc:unmark", marked)
+	}
+	lines = append(lines, footerStyle.Render(footer))
 
 	content := strings.Join(lines, "\n")
 	sidebar := containerStyle.Render(content)
@@ -289,10 +367,10 @@ func (m *OS) renderSidebar() *lipgloss.Layer {
 	// Position
 	yPos := topMargin
 	if config.DockbarPosition == "top" {
-		yPos = config.DockHeight
+		yPos = m.DockHeight()
 	}
 
-	return lipgloss.NewLayer(sidebar).X(0).Y(yPos).Z(ZIndexSidebar).ID("sidebar")
+	return m.sidebarCache.set(cacheKey, lipgloss.NewLayer(sidebar).X(0).Y(yPos).Z(ZIndexSidebar).ID("sidebar"))
 }
 
 // ToggleSidebar toggles the sidebar visibility
@@ -302,33 +380,101 @@ func (m *OS) ToggleSidebar() {
 		m.SidebarFocused = true
 		// Select current focused window in sidebar
 		m.SidebarSelectedIndex = m.FocusedWindow
-		m.ShowNotification("Sidebar: ↑↓ navigate, Enter select, Esc close", "info", config.NotificationDuration)
+		m.ShowNotification("Sidebar: ↑↓ navigate, Enter select, Space mark, Esc close", "info", config.NotificationDuration)
 	} else {
 		m.SidebarFocused = false
 		m.SidebarSelectedIndex = -1
+		m.SidebarJoinTarget = ""
+		m.SidebarGroupTarget = ""
+		m.SidebarMarked = nil
+	}
+}
+
+// OpenSidebarToJoin shows the sidebar to pick a window to merge into the
+// focused window as a pane.
+func (m *OS) OpenSidebarToJoin() {
+	focused := m.GetFocusedWindow()
+	if focused == nil {
+		return
 	}
+	m.SidebarVisible = true
+	m.SidebarFocused = true
+	m.SidebarSelectedIndex = m.FocusedWindow
+	m.SidebarJoinTarget = focused.ID
+	m.ShowNotification("Merge: ↑↓ pick a window, Enter merge, Esc cancel", "info", config.NotificationDuration)
+}
+
+// OpenSidebarToGroup shows the sidebar to pick a window to group with the
+// focused window.
+func (m *OS) OpenSidebarToGroup() {
+	focused := m.GetFocusedWindow()
+	if focused == nil {
+		return
+	}
+	m.SidebarVisible = true
+	m.SidebarFocused = true
+	m.SidebarSelectedIndex = m.FocusedWindow
+	m.SidebarGroupTarget = focused.ID
+	m.ShowNotification("Group: ↑↓ pick a window, Enter group, Esc cancel", "info", config.NotificationDuration)
+}
+
+// SidebarPicking reports whether the sidebar is open to pick a window for
+// the focused window to merge or group with rather than to focus.
+func (m *OS) SidebarPicking() bool {
+	return m.SidebarJoinTarget != "" || m.SidebarGroupTarget != ""
 }
 
-// SidebarSelectNext moves sidebar selection down
+// SidebarSelectNext moves sidebar selection down, skipping the windows a
+// tag filter hides
 func (m *OS) SidebarSelectNext() {
-	if len(m.Windows) == 0 {
+	if m.SidebarSortCPU {
+		m.sidebarStep(1)
 		return
 	}
-	m.SidebarSelectedIndex++
-	if m.SidebarSelectedIndex >= len(m.Windows) {
-		m.SidebarSelectedIndex = 0 // wrap around
+	for range m.Windows {
+		m.SidebarSelectedIndex++
+		if m.SidebarSelectedIndex >= len(m.Windows) {
+			m.SidebarSelectedIndex = 0 // wrap around
+		}
+		if m.sidebarShows(m.Windows[m.SidebarSelectedIndex]) {
+			return
+		}
 	}
 }
 
-// SidebarSelectPrev moves sidebar selection up
+// SidebarSelectPrev moves sidebar selection up, skipping the windows a tag
+// filter hides
 func (m *OS) SidebarSelectPrev() {
-	if len(m.Windows) == 0 {
+	if m.SidebarSortCPU {
+		m.sidebarStep(-1)
+		return
+	}
+	for range m.Windows {
+		m.SidebarSelectedIndex--
+		if m.SidebarSelectedIndex < 0 {
+			m.SidebarSelectedIndex = len(m.Windows) - 1 // wrap around
+		}
+		if m.sidebarShows(m.Windows[m.SidebarSelectedIndex]) {
+			return
+		}
+	}
+}
+
+// sidebarStep moves the sidebar selection by delta entries in the order the
+// sidebar lists the windows, wrapping around.
+func (m *OS) sidebarStep(delta int) {
+	var order []int
+	for _, section := range m.sidebarSections() {
+		order = append(order, section.Windows...)
+	}
+	if len(order) == 0 {
 		return
 	}
-	m.SidebarSelectedIndex--
-	if m.SidebarSelectedIndex < 0 {
-		m.SidebarSelectedIndex = len(m.Windows) - 1 // wrap around
+	at := slices.Index(order, m.SidebarSelectedIndex)
+	if at < 0 && delta < 0 {
+		at = 0
 	}
+	m.SidebarSelectedIndex = order[((at+delta)%len(order)+len(order))%len(order)]
 }
 
 // SidebarConfirmSelection switches to the selected window
@@ -339,6 +485,19 @@ func (m *OS) SidebarConfirmSelection() {
 
 	selectedWindow := m.Windows[m.SidebarSelectedIndex]
 
+	if m.SidebarJoinTarget != "" {
+		index, target := m.SidebarSelectedIndex, m.SidebarJoinTarget
+		m.CloseSidebar()
+		m.JoinWindowAsPane(index, target)
+		return
+	}
+	if m.SidebarGroupTarget != "" {
+		index, target := m.SidebarSelectedIndex, m.SidebarGroupTarget
+		m.CloseSidebar()
+		m.GroupWindowWith(index, target)
+		return
+	}
+
 	// Switch workspace if needed
 	if selectedWindow.Workspace != m.CurrentWorkspace {
 		m.SwitchToWorkspace(selectedWindow.Workspace)
@@ -358,6 +517,7 @@ func (m *OS) SidebarConfirmSelection() {
 	// Close sidebar and enter terminal mode
 	m.SidebarVisible = false
 	m.SidebarFocused = false
+	m.SidebarMarked = nil
 	m.Mode = TerminalMode
 }
 
@@ -366,6 +526,9 @@ func (m *OS) CloseSidebar() {
 	m.SidebarVisible = false
 	m.SidebarFocused = false
 	m.SidebarSelectedIndex = -1
+	m.SidebarJoinTarget = ""
+	m.SidebarGroupTarget = ""
+	m.SidebarMarked = nil
 }
 
 // FindSidebarItemClicked returns the window index if a sidebar item was clicked, -1 otherwise
@@ -385,40 +548,30 @@ func (m *OS) FindSidebarItemClicked(x, y int) int {
 	// The sidebar starts after top margin, has 1 border, title, blank, then items
 	topMargin := m.GetTopMargin()
 	if config.DockbarPosition == "top" {
-		topMargin = config.DockHeight
+		topMargin = m.DockHeight()
 	}
 
 	// Sidebar content starts at: topMargin + 1 (border) + 1 (title) + 1 (blank) = topMargin + 3
 	// Then workspace headers and items
 	// For simplicity, just iterate and find by position
 
-	// Group windows by workspace (same as render)
-	workspaceWindows := make(map[int][]int)
-	for i, w := range m.Windows {
-		workspaceWindows[w.Workspace] = append(workspaceWindows[w.Workspace], i)
-	}
-
-	workspaces := make([]int, 0, len(workspaceWindows))
-	for ws := range workspaceWindows {
-		workspaces = append(workspaces, ws)
-	}
-	sort.Ints(workspaces)
+	// Same sections as render
+	sections := m.sidebarSections()
 
 	// Calculate Y positions matching render
 	currentY := topMargin + 3 // border + title + blank
 
-	for wsIdx, ws := range workspaces {
-		indices := workspaceWindows[ws]
+	for sectionIdx, section := range sections {
 		currentY++ // workspace header
 
-		for _, idx := range indices {
+		for _, idx := range section.Windows {
 			if y == currentY {
 				return idx
 			}
 			currentY++
 		}
 
-		if wsIdx < len(workspaces)-1 {
+		if sectionIdx < len(sections)-1 {
 			currentY++ // gap
 		}
 	}
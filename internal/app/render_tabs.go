@@ -0,0 +1,49 @@
+package app
+
+import (
+	"image/color"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// renderTabs renders the content area of a tabbed window: a one-line tab
+// strip above the active tab's terminal. The active tab's label is drawn like
+// the window's title badge.
+func (m *OS) renderTabs(window *terminal.Window, isFocused bool, inTerminalMode bool, borderColor color.Color) string {
+	if window.IsBeingManipulated && m.Resizing {
+		return m.renderResizeIndicator(window)
+	}
+
+	active := window.ActiveTab()
+	content := m.renderTerminal(active, isFocused, inTerminalMode)
+	if active != window {
+		active.ClearDirtyFlags()
+	}
+	return renderTabStrip(window, max(window.Width-2, 1), borderColor) + "\n" + content
+}
+
+// renderTabStrip draws the labels of the window's tabs, cut to width.
+func renderTabStrip(window *terminal.Window, width int, borderColor color.Color) string {
+	activeStyle := baseButtonStyle.Background(borderColor)
+	inactiveStyle := lipgloss.NewStyle().Foreground(theme.BorderUnfocused())
+
+	labels := tabLabels(window)
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		if i == window.TabIndex {
+			parts[i] = activeStyle.Render(label)
+		} else {
+			parts[i] = inactiveStyle.Render(label)
+		}
+	}
+
+	strip := ansi.Truncate(strings.Join(parts, " "), width, "…")
+	if pad := width - ansi.StringWidth(strip); pad > 0 {
+		strip += strings.Repeat(" ", pad)
+	}
+	return strip
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"image/color"
 	"strings"
+	"time"
 
 	"charm.land/lipgloss/v2"
 	"github.com/Gaurav-Gosain/tuios/internal/pool"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
 	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalMode bool) string {
@@ -33,6 +36,10 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 	}
 
 	screen := window.Terminal
+	if window.Replay != nil {
+		// Replay shows its own emulator in place of live output
+		screen = window.Replay.Emulator
+	}
 	if screen == nil {
 		window.CachedContent = "No screen"
 		return window.CachedContent
@@ -53,6 +60,9 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 
 	useOptimizedRendering := !isFocused && !inTerminalMode
 
+	// Default colors the app set with OSC 10/11 replace the host's
+	appFg, appBg := screen.AppColors()
+
 	scrollbackLen := window.ScrollbackLen()
 	inScrollbackMode := window.ScrollbackOffset > 0
 
@@ -112,9 +122,7 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 		}
 	}
 
-	inVisualMode := inCopyMode &&
-		(window.CopyMode.State == terminal.CopyModeVisualChar ||
-			window.CopyMode.State == terminal.CopyModeVisualLine)
+	inVisualMode := inCopyMode && window.CopyMode.Visual()
 
 	if inVisualMode {
 		visualSelection = pool.GetHighlightGrid()
@@ -123,6 +131,8 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 
 		start := window.CopyMode.VisualStart
 		end := window.CopyMode.VisualEnd
+		block := window.CopyMode.State == terminal.CopyModeVisualBlock
+		blockLeft, blockRight := min(start.X, end.X), max(start.X, end.X)
 
 		if start.Y > end.Y || (start.Y == end.Y && start.X > end.X) {
 			start, end = end, start
@@ -157,6 +167,9 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 				if absY == end.Y {
 					endX = end.X
 				}
+				if block {
+					startX, endX = blockLeft, blockRight
+				}
 
 				for x := startX; x <= endX && x < maxX; x++ {
 					visualSelection.Set(viewportY, x)
@@ -168,18 +181,29 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 	var batchBuilder strings.Builder
 	var currentStyle lipgloss.Style
 	var batchHasStyle bool
+	var batchLink uv.Link
 	var prevCell *uv.Cell
 	var prevIsCursor, prevIsSelected, prevIsSelectionCursor bool
 
+	// OSC 8 links of the program are passed on to hosts that support them
+	withLinks := GetHostCapabilities().Hyperlinks
+
 	flushBatch := func(lineBuilder *strings.Builder) {
 		if batchBuilder.Len() > 0 {
+			if batchLink.URL != "" {
+				lineBuilder.WriteString(ansi.SetHyperlink(batchLink.URL, batchLink.Params))
+			}
 			if batchHasStyle {
 				lineBuilder.WriteString(renderStyledText(currentStyle, batchBuilder.String()))
 			} else {
 				lineBuilder.WriteString(batchBuilder.String())
 			}
+			if batchLink.URL != "" {
+				lineBuilder.WriteString(ansi.ResetHyperlink())
+			}
 			batchBuilder.Reset()
 			batchHasStyle = false
+			batchLink = uv.Link{}
 		}
 	}
 
@@ -210,7 +234,59 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 			prevIsSelectionCursor == isSelectionCursor &&
 			safeColorEquals(prevCell.Style.Fg, cell.Style.Fg) &&
 			safeColorEquals(prevCell.Style.Bg, cell.Style.Bg) &&
-			prevCell.Style.Attrs == cell.Style.Attrs
+			prevCell.Style.Attrs == cell.Style.Attrs &&
+			(!withLinks || prevCell.Link == cell.Link)
+	}
+
+	// Damage-region rendering: rows untouched since the previous frame are
+	// reused from the line cache instead of being rendered cell by cell.
+	// Overlays drawn on top of the content (copy mode, scrollback, selections)
+	// are not tracked by the emulator, so those frames are always rendered fully.
+	damage := screen.TakeDamage()
+	canReuseLines := !inCopyMode && !inScrollbackMode && window.Replay == nil &&
+		!window.IsSelecting && window.SelectedText == "" &&
+		!(m.SelectionMode && !inTerminalMode && isFocused)
+
+	// Time gutter shown while viewing scrollback with timestamps on
+	gutter := window.TimestampGutter()
+	now := time.Now()
+
+	// Highlight rules paint matching text as rows are rendered
+	highlights := windowHighlights(window)
+	viewCell := func(x, y int) *uv.Cell {
+		if !inScrollbackMode {
+			return screen.CellAt(x, y)
+		}
+		if y < window.ScrollbackOffset {
+			index := scrollbackLen - window.ScrollbackOffset + y
+			if index < 0 || index >= scrollbackLen {
+				return nil
+			}
+			if line := window.ScrollbackLine(index); x < len(line) {
+				return &line[x]
+			}
+			return nil
+		}
+		if screenY := y - window.ScrollbackOffset; screenY < screen.Height() {
+			return screen.CellAt(x, screenY)
+		}
+		return nil
+	}
+
+	cursorRow := -1
+	if !useRealCursor && isFocused && inTerminalMode && !screen.IsCursorHidden() {
+		cursorRow = cursorY
+	}
+
+	var dirtyRows []bool
+	var lines []string
+	if canReuseLines {
+		dirtyRows = damagedRows(window.LineCache, damage, maxX, maxY, useOptimizedRendering, cursorRow)
+		if dirtyRows != nil {
+			lines = window.LineCache.Lines
+		} else {
+			lines = make([]string, maxY)
+		}
 	}
 
 	for y := range maxY {
@@ -218,6 +294,11 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 			builder.WriteRune('\n')
 		}
 
+		if dirtyRows != nil && !dirtyRows[y] {
+			builder.WriteString(lines[y])
+			continue
+		}
+
 		lineBuilder := pool.GetStringBuilder()
 		defer pool.PutStringBuilder(lineBuilder)
 
@@ -225,6 +306,11 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 		batchHasStyle = false
 		prevCell = nil
 
+		var painted []*highlightStyle
+		if len(highlights) > 0 {
+			painted = rowHighlights(highlights, maxX, func(x int) *uv.Cell { return viewCell(x, y) })
+		}
+
 		lineEndX := maxX - 1
 		if inVisualMode && visualSelection != nil && visualSelection.HasRow(y) {
 			if inScrollbackMode {
@@ -314,16 +400,9 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 					Foreground(lipgloss.Color("#000000")).
 					Bold(true)
 
-				if batchBuilder.Len() > 0 {
-					if batchHasStyle {
-						lineBuilder.WriteString(renderStyledText(currentStyle, batchBuilder.String()))
-					} else {
-						lineBuilder.WriteString(batchBuilder.String())
-					}
-					batchBuilder.Reset()
-					batchHasStyle = false
-				}
+				flushBatch(lineBuilder)
 
+				char, skip := fitCellContent(char, charWidth)
 				lineBuilder.WriteString(renderStyledText(cursorStyle, char))
 
 				prevCell = nil
@@ -331,7 +410,7 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 				prevIsSelected = false
 				prevIsSelectionCursor = false
 
-				x += charWidth
+				x += charWidth + skip
 				continue
 			}
 
@@ -353,11 +432,22 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 			} else {
 				cell = screen.CellAt(x, y)
 			}
+			if appFg != nil || appBg != nil {
+				cell = withAppColors(cell, appFg, appBg)
+			}
+			if painted != nil && painted[x] != nil {
+				cell = painted[x].paint(cell)
+			}
 
 			char := " "
 			if cell != nil && cell.Content != "" {
 				char = string(cell.Content)
 			}
+			cellWidth := 1
+			if cell != nil && cell.Width > 1 {
+				cellWidth = cell.Width
+			}
+			char, skip := fitCellContent(char, cellWidth)
 
 			if inVisualMode && visualSelection != nil && visualSelection.Get(y, x) && x <= lineEndX {
 				selStyle := lipgloss.NewStyle().
@@ -365,26 +455,14 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 					Foreground(lipgloss.Color("#FFFFFF")).
 					Bold(true)
 
-				if batchBuilder.Len() > 0 {
-					if batchHasStyle {
-						lineBuilder.WriteString(renderStyledText(currentStyle, batchBuilder.String()))
-					} else {
-						lineBuilder.WriteString(batchBuilder.String())
-					}
-					batchBuilder.Reset()
-					batchHasStyle = false
-				}
+				flushBatch(lineBuilder)
 
 				lineBuilder.WriteString(renderStyledText(selStyle, char))
 				prevCell = cell
 				prevIsCursor = false
 				prevIsSelected = false
 				prevIsSelectionCursor = false
-				cellWidth := 1
-				if cell != nil && cell.Width > 1 {
-					cellWidth = cell.Width
-				}
-				x += cellWidth
+				x += cellWidth + skip
 				continue
 			}
 
@@ -395,26 +473,14 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 						Foreground(lipgloss.Color("#000000")).
 						Bold(true)
 
-					if batchBuilder.Len() > 0 {
-						if batchHasStyle {
-							lineBuilder.WriteString(renderStyledText(currentStyle, batchBuilder.String()))
-						} else {
-							lineBuilder.WriteString(batchBuilder.String())
-						}
-						batchBuilder.Reset()
-						batchHasStyle = false
-					}
+					flushBatch(lineBuilder)
 
 					lineBuilder.WriteString(renderStyledText(matchStyle, char))
 					prevCell = cell
 					prevIsCursor = false
 					prevIsSelected = false
 					prevIsSelectionCursor = false
-					cellWidth := 1
-					if cell != nil && cell.Width > 1 {
-						cellWidth = cell.Width
-					}
-					x += cellWidth
+					x += cellWidth + skip
 					continue
 				}
 
@@ -423,26 +489,14 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 						Background(lipgloss.Color("#FF8700")).
 						Foreground(lipgloss.Color("#000000"))
 
-					if batchBuilder.Len() > 0 {
-						if batchHasStyle {
-							lineBuilder.WriteString(renderStyledText(currentStyle, batchBuilder.String()))
-						} else {
-							lineBuilder.WriteString(batchBuilder.String())
-						}
-						batchBuilder.Reset()
-						batchHasStyle = false
-					}
+					flushBatch(lineBuilder)
 
 					lineBuilder.WriteString(renderStyledText(matchStyle, char))
 					prevCell = cell
 					prevIsCursor = false
 					prevIsSelected = false
 					prevIsSelectionCursor = false
-					cellWidth := 1
-					if cell != nil && cell.Width > 1 {
-						cellWidth = cell.Width
-					}
-					x += cellWidth
+					x += cellWidth + skip
 					continue
 				}
 			}
@@ -459,6 +513,9 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 			if x > 0 && !styleMatches(cell, isCursorPos, isSelected, isSelectionCursor) {
 				flushBatch(lineBuilder)
 			}
+			if withLinks && batchBuilder.Len() == 0 && cell != nil {
+				batchLink = cell.Link
+			}
 
 			if needsStyling {
 				if batchBuilder.Len() == 0 {
@@ -496,15 +553,33 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 			prevIsSelected = isSelected
 			prevIsSelectionCursor = isSelectionCursor
 
-			cellWidth := 1
-			if cell != nil && cell.Width > 1 {
-				cellWidth = cell.Width
-			}
-			x += cellWidth
+			x += cellWidth + skip
 		}
 
 		flushBatch(lineBuilder)
-		builder.WriteString(lineBuilder.String())
+		line := lineBuilder.String()
+		if gutter > 0 {
+			// The gutter takes the leftmost columns, the line's tail is hidden
+			line = renderTimestampGutter(window.ViewLineTime(y), now) + ansi.Truncate(line, maxX-gutter, "")
+		}
+		if window.Replay != nil && y == maxY-1 {
+			line = renderReplayStatus(window.Replay, maxX)
+		}
+		builder.WriteString(line)
+		if lines != nil {
+			lines[y] = line
+		}
+	}
+
+	if canReuseLines {
+		window.LineCache = &terminal.LineCache{
+			Lines:     lines,
+			Width:     maxX,
+			Optimized: useOptimizedRendering,
+			CursorRow: cursorRow,
+		}
+	} else {
+		window.LineCache = nil
 	}
 
 	content := builder.String()
@@ -513,6 +588,35 @@ func (m *OS) renderTerminal(window *terminal.Window, isFocused bool, inTerminalM
 	return content
 }
 
+// damagedRows reports which rows of a window must be rendered again, given its
+// line cache and the damage collected from its emulator. A nil result means
+// the cache cannot be used and every row must be rendered.
+func damagedRows(cache *terminal.LineCache, damage []vt.Damage, width, height int, optimized bool, cursorRow int) []bool {
+	if cache == nil || cache.Width != width || len(cache.Lines) != height || cache.Optimized != optimized {
+		return nil
+	}
+
+	rows := make([]bool, height)
+	for _, d := range damage {
+		if _, ok := d.(vt.ScreenDamage); ok {
+			return nil
+		}
+		bounds := d.Bounds()
+		for y := max(bounds.Min.Y, 0); y < min(bounds.Max.Y, height); y++ {
+			rows[y] = true
+		}
+	}
+
+	// The cursor is drawn into the row content, so both its old and new rows
+	// need rendering even when the cells underneath did not change.
+	for _, y := range []int{cache.CursorRow, cursorRow} {
+		if y >= 0 && y < height {
+			rows[y] = true
+		}
+	}
+	return rows
+}
+
 func (m *OS) renderResizeIndicator(window *terminal.Window) string {
 	termWidth := max(window.Width-2, 1)
 	termHeight := max(window.Height-2, 1)
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// ToggleReplay starts or stops replaying the recorded output of the focused
+// pane.
+func (m *OS) ToggleReplay() {
+	pane := m.GetFocusedPane()
+	if pane == nil {
+		return
+	}
+	if pane.Replay != nil {
+		pane.StopReplay()
+		m.ShowNotification("Replay closed", "info", config.NotificationDuration)
+		return
+	}
+
+	if err := pane.StartReplay(); err != nil {
+		if errors.Is(err, terminal.ErrNothingRecorded) && config.ReplayBufferBytes == 0 {
+			m.ShowNotification("Output recording is disabled (appearance.replay_buffer_kb)", "warning", config.NotificationDuration)
+			return
+		}
+		m.ShowNotification(fmt.Sprintf("Cannot replay: %v", err), "warning", config.NotificationDuration)
+		return
+	}
+	m.ShowNotification("Replaying recorded output", "info", config.NotificationDuration)
+}
+
+// AdvanceReplays moves every active replay forward. It reports whether any
+// replay changed what its window shows.
+func (m *OS) AdvanceReplays() bool {
+	now := time.Now()
+	changed := false
+	for _, w := range m.Windows {
+		panes := []*terminal.Window{w}
+		for _, pane := range append(w.PaneWindows(), w.Tabs...) {
+			if pane != w {
+				panes = append(panes, pane)
+			}
+		}
+		for _, pane := range panes {
+			if pane.Replay != nil && pane.Replay.Advance(now) {
+				pane.MarkContentDirty()
+				pane.InvalidateCache()
+				w.InvalidateCache()
+				changed = true
+			}
+		}
+	}
+	return changed
+}
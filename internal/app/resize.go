@@ -0,0 +1,123 @@
+package app
+
+import (
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// screenArea is the part of the screen windows are laid out in: below the
+// dock when it is at the top and above it otherwise.
+type screenArea struct {
+	top, width, height int
+}
+
+// windowArea returns the screen area windows are currently laid out in.
+func (m *OS) windowArea() screenArea {
+	return screenArea{top: m.GetTopMargin(), width: m.GetRenderWidth(), height: m.GetUsableHeight()}
+}
+
+// fitLayoutToResize lays the windows out again after the window area
+// changed from old: the saved layouts of the other tiled workspaces are
+// dropped so they are retiled when switched to, and floating windows on
+// every workspace are rescaled or kept at their nearest edges, as
+// config.ResizeBehavior says. The current workspace's tiled windows are
+// left to the caller to retile.
+func (m *OS) fitLayoutToResize(old screenArea) {
+	area := m.windowArea()
+	if area == old || area.width <= 0 || area.height <= 0 {
+		return
+	}
+	if old.width <= 0 || old.height <= 0 {
+		m.ClampWindowsToView()
+		return
+	}
+
+	for ws := 1; ws <= m.NumWorkspaces; ws++ {
+		if ws != m.CurrentWorkspace && m.WorkspaceAutoTiling(ws) {
+			delete(m.WorkspaceLayouts, ws)
+			m.WorkspaceHasCustom[ws] = false
+		}
+	}
+
+	proportional := config.ResizeBehavior == "proportional"
+	moved := 0
+	for _, w := range m.Windows {
+		floating := w.Floating || !m.WorkspaceAutoTiling(w.Workspace)
+		switch {
+		case w.PiP:
+			// The miniature stays pinned to its corner, and what it returns
+			// to follows the screen like any floating window
+			if m.fitWindow(w, old, area, false) {
+				moved++
+			}
+			w.PrePiPX, w.PrePiPY, w.PrePiPWidth, w.PrePiPHeight =
+				fitRect(w.PrePiPX, w.PrePiPY, w.PrePiPWidth, w.PrePiPHeight, old, area, proportional)
+		case !floating:
+			// Tiled windows minimized away are retiled when restored
+		case w.Minimized || w.Minimizing:
+			w.PreMinimizeX, w.PreMinimizeY, w.PreMinimizeWidth, w.PreMinimizeHeight =
+				fitRect(w.PreMinimizeX, w.PreMinimizeY, w.PreMinimizeWidth, w.PreMinimizeHeight, old, area, proportional)
+		default:
+			if m.fitWindow(w, old, area, proportional) {
+				moved++
+			}
+		}
+	}
+
+	if moved > 0 {
+		m.LogInfo("[RESIZE] Fitted %d floating windows from %dx%d to %dx%d (%s)",
+			moved, old.width, old.height, area.width, area.height, config.ResizeBehavior)
+	}
+	m.SyncStateToDaemon()
+}
+
+// fitWindow fits a window's geometry to the new area and reports whether
+// it changed.
+func (m *OS) fitWindow(w *terminal.Window, old, area screenArea, proportional bool) bool {
+	x, y, width, height := fitRect(w.X, w.Y, w.Width, w.Height, old, area, proportional)
+	if x == w.X && y == w.Y && width == w.Width && height == w.Height {
+		return false
+	}
+	w.X, w.Y = x, y
+	if width != w.Width || height != w.Height {
+		w.Resize(width, height)
+	}
+	w.MarkPositionDirty()
+	return true
+}
+
+// fitRect maps a window's geometry from the old area to the new one.
+// Proportional scales position and size with the area, so a window covering
+// the right half still covers the right half. Otherwise the size is kept and
+// the window keeps its distance to the nearest horizontal and vertical
+// edges. Either way the result lies entirely within the new area.
+func fitRect(x, y, width, height int, old, area screenArea, proportional bool) (int, int, int, int) {
+	relY := y - old.top
+	if proportional {
+		// Scale both edges, so windows sharing an edge still do
+		right, bottom := x+width, relY+height
+		x = scaleCoord(x, old.width, area.width)
+		relY = scaleCoord(relY, old.height, area.height)
+		width = scaleCoord(right, old.width, area.width) - x
+		height = scaleCoord(bottom, old.height, area.height) - relY
+	} else {
+		if 2*x+width > old.width {
+			x = area.width - (old.width - x)
+		}
+		if 2*relY+height > old.height {
+			relY = area.height - (old.height - relY)
+		}
+	}
+
+	width = min(max(width, config.DefaultWindowWidth), area.width)
+	height = min(max(height, config.DefaultWindowHeight), area.height)
+	x = min(max(x, 0), area.width-width)
+	relY = min(max(relY, 0), area.height-height)
+	return x, area.top + relY, width, height
+}
+
+// scaleCoord scales a coordinate from one length to another, rounding to
+// the nearest cell.
+func scaleCoord(v, from, to int) int {
+	return (2*v*to + from) / (2 * from)
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestResizeFitsFloatingWindows(t *testing.T) {
+	defer func(behavior string) { config.ResizeBehavior = behavior }(config.ResizeBehavior)
+	usable := func(m *OS) int { return m.GetUsableHeight() }
+
+	for _, tt := range []struct {
+		behavior            string
+		width               int // Terminal width after the resize; the height doubles
+		wantX, wantW, wantH int
+	}{
+		{"proportional", 240, 120, 120, 40},
+		{"anchored", 240, 180, 60, 20},
+		{"proportional", 60, 30, 30, 40},
+		{"anchored", 60, 0, 60, 20},
+	} {
+		config.ResizeBehavior = tt.behavior
+		m := NewOS(OSOptions{})
+		m.Width, m.Height = 120, 40
+		dock := m.Height - usable(m)
+		m.Height += dock // 40 rows to lay windows out in
+		m.CurrentWorkspace = 1
+		right := &terminal.Window{ID: "window-right-00", X: 60, Y: 0, Width: 60, Height: 20, Workspace: 1, Terminal: vt.NewEmulator(58, 18)}
+		other := &terminal.Window{ID: "window-other-00", X: 100, Y: 30, Width: 20, Height: 10, Workspace: 2, Terminal: vt.NewEmulator(18, 8)}
+		m.Windows = []*terminal.Window{right, other}
+		m.WorkspaceTiling = map[int]bool{3: true}
+		m.WorkspaceLayouts[3] = []WindowLayout{{WindowID: "gone", Width: 120}}
+
+		m.Update(tea.WindowSizeMsg{Width: tt.width, Height: 80 + dock})
+		if right.X != tt.wantX || right.Width != tt.wantW || right.Height != tt.wantH {
+			t.Errorf("%s to %d: got x=%d w=%d h=%d, want x=%d w=%d h=%d", tt.behavior, tt.width,
+				right.X, right.Width, right.Height, tt.wantX, tt.wantW, tt.wantH)
+		}
+		if other.X+other.Width > m.GetRenderWidth() || other.Y+other.Height > usable(m) {
+			t.Errorf("%s to %d: window on another workspace left off screen at %d,%d", tt.behavior, tt.width, other.X, other.Y)
+		}
+		if _, ok := m.WorkspaceLayouts[3]; ok {
+			t.Errorf("%s: expected the saved layout of a tiled workspace to be dropped", tt.behavior)
+		}
+	}
+}
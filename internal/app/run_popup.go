@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// RunCommandTimeout is how long a command run in a popup may take before it
+// is killed.
+const RunCommandTimeout = 30 * time.Second
+
+// RunCommandDoneMsg carries the output of a command run from the run prompt.
+type RunCommandDoneMsg struct {
+	Command string
+	Output  string
+	Err     error // Set if the command failed or could not be started
+}
+
+// StartRunPrompt opens the prompt for a shell command whose output is shown
+// in a popup.
+func (m *OS) StartRunPrompt() {
+	m.RunPrompt.Open("Run command", "Enter: run  Esc: cancel", "")
+}
+
+// CancelRunPrompt closes the run prompt without running anything.
+func (m *OS) CancelRunPrompt() {
+	m.RunPrompt.Close()
+}
+
+// SubmitRunPrompt closes the run prompt and returns a command running what
+// was typed, in the focused window's working directory.
+func (m *OS) SubmitRunPrompt() tea.Cmd {
+	command := strings.TrimSpace(m.RunPrompt.Close())
+	if command == "" {
+		return nil
+	}
+	dir := ""
+	if w := m.GetFocusedWindow(); w != nil {
+		dir = w.WorkingDirectory()
+	}
+	return func() tea.Msg {
+		return runPopupCommand(command, dir)
+	}
+}
+
+// runPopupCommand runs a command line with the user's shell and collects its
+// combined output.
+func runPopupCommand(command, dir string) RunCommandDoneMsg {
+	ctx, cancel := context.WithTimeout(context.Background(), RunCommandTimeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = ctx.Err()
+	}
+	return RunCommandDoneMsg{Command: command, Output: string(output), Err: err}
+}
+
+// shellCommand returns a command running a command line with the user's
+// shell.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	argv := shellArgv(command)
+	return exec.CommandContext(ctx, argv[0], argv[1:]...)
+}
+
+// shellArgv returns the arguments that run a command line with the user's
+// shell.
+func shellArgv(command string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/C", command}
+	}
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	return []string{shell, "-c", command}
+}
+
+// ShowRunOutput shows the output of a command run from the run prompt in a
+// popup that closes on any key.
+func (m *OS) ShowRunOutput(msg RunCommandDoneMsg) {
+	body := strings.TrimRight(strings.ReplaceAll(msg.Output, "\r\n", "\n"), "\n")
+	if msg.Err != nil {
+		if body != "" {
+			body += "\n\n"
+		}
+		body += "[" + msg.Err.Error() + "]"
+	}
+	if body == "" {
+		body = "(no output)"
+	}
+	m.ShowPopup("$ "+msg.Command, body, 0, nil, "").Output = true
+}
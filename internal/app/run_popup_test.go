@@ -0,0 +1,35 @@
+package app
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunPromptShowsOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs a POSIX shell command")
+	}
+	t.Setenv("SHELL", "/bin/sh")
+	m := NewOS(OSOptions{})
+
+	m.StartRunPrompt()
+	if m.SubmitRunPrompt() != nil || m.RunPrompt.Active {
+		t.Fatal("expected an empty prompt to close without running anything")
+	}
+
+	m.StartRunPrompt()
+	m.RunPrompt.Buffer = "echo hello; exit 3"
+	cmd := m.SubmitRunPrompt()
+	if cmd == nil || m.RunPrompt.Active {
+		t.Fatal("expected the prompt closed with a command to run")
+	}
+	m.Update(cmd())
+	if m.Popup == nil || !m.Popup.Output || !m.Popup.Deadline.IsZero() {
+		t.Fatalf("expected an output popup without a timeout, got %+v", m.Popup)
+	}
+	if m.Popup.Title != "$ echo hello; exit 3" || !strings.Contains(m.Popup.Body, "hello") ||
+		!strings.Contains(m.Popup.Body, "exit status 3") {
+		t.Errorf("expected the command's output and exit status, got %q: %q", m.Popup.Title, m.Popup.Body)
+	}
+}
@@ -0,0 +1,407 @@
+package app
+
+import (
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// How often each screensaver animation advances.
+var screensaverFrameIntervals = map[string]time.Duration{
+	"clock":  time.Second,
+	"matrix": 60 * time.Millisecond,
+	"pipes":  30 * time.Millisecond,
+}
+
+const (
+	// screensaverClockMoveInterval is how often the clock moves to another
+	// spot, so it does not burn into the screen.
+	screensaverClockMoveInterval = 30 * time.Second
+
+	// screensaverPipes is the number of pipes growing at once.
+	screensaverPipes = 4
+
+	// screensaverLockHintDuration is how long the unlock hint stays up after
+	// a key is pressed on the lock screen.
+	screensaverLockHintDuration = 3 * time.Second
+)
+
+// screensaverBackground is the color behind every screensaver.
+const screensaverBackground = ansi.IndexedColor(16)
+
+// screensaverCell is one cell of a screensaver frame. A zero rune is blank.
+type screensaverCell struct {
+	r  rune
+	fg ansi.IndexedColor
+}
+
+// matrixDrop is a falling stream of characters in one column.
+type matrixDrop struct {
+	head   int // Row of the leading character, negative before it enters
+	length int
+	speed  int // Steps per row, 1 is the fastest
+}
+
+// screensaverPipe is a pipe growing across the screen.
+type screensaverPipe struct {
+	x, y  int
+	dir   int // 0 up, 1 right, 2 down, 3 left
+	color ansi.IndexedColor
+}
+
+// Screensaver is the animation shown over the session after
+// config.ScreensaverIdle without input.
+type Screensaver struct {
+	Kind    string
+	Started time.Time
+
+	width, height int
+	cells         []screensaverCell
+	rng           *rand.Rand
+	lastStep      time.Time
+	steps         int
+	hint          string // Shown at the bottom until hintUntil
+	hintUntil     time.Time
+
+	glyphs []rune // Matrix characters, changed in place as they fall
+	drops  []matrixDrop
+	pipes  []screensaverPipe
+
+	clockX, clockY int
+	clockMoved     time.Time
+}
+
+// newScreensaver creates a screensaver of the given kind filling a screen of
+// the given size.
+func newScreensaver(kind string, width, height int, now time.Time) *Screensaver {
+	s := &Screensaver{
+		Kind:     kind,
+		Started:  now,
+		width:    max(width, 1),
+		height:   max(height, 1),
+		rng:      rand.New(rand.NewPCG(uint64(now.UnixNano()), 0)),
+		lastStep: now,
+	}
+	s.cells = make([]screensaverCell, s.width*s.height)
+	switch kind {
+	case "matrix":
+		s.glyphs = make([]rune, len(s.cells))
+		for i := range s.glyphs {
+			s.glyphs[i] = s.randomGlyph()
+		}
+		s.drops = make([]matrixDrop, s.width)
+		for x := range s.drops {
+			s.resetDrop(x, true)
+		}
+	case "pipes":
+		s.resetPipes()
+	default:
+		s.clockMoved = now
+		s.moveClock()
+	}
+	s.draw(now)
+	return s
+}
+
+// NoteInput records user input for the idle timer. While the screensaver is
+// up the input dismisses it instead of reaching the session, and true is
+// returned. With config.ScreensaverLock only the unlock chord dismisses it.
+func (m *OS) NoteInput(msg tea.Msg) bool {
+	m.LastInputTime = time.Now()
+	if m.Screensaver == nil {
+		return false
+	}
+	if config.ScreensaverLock {
+		key, ok := msg.(tea.KeyPressMsg)
+		if !ok {
+			return true
+		}
+		if !m.IsUnlockInputKey(key.String()) {
+			m.Screensaver.hint = "Locked: " + m.UnlockInputHint() + " to unlock"
+			m.Screensaver.hintUntil = m.LastInputTime.Add(screensaverLockHintDuration)
+			m.Screensaver.draw(m.LastInputTime)
+			return true
+		}
+	}
+	m.DismissScreensaver()
+	return true
+}
+
+// DismissScreensaver hides the screensaver and restarts the idle timer.
+func (m *OS) DismissScreensaver() {
+	if m.Screensaver == nil {
+		return
+	}
+	m.Screensaver = nil
+	m.LastInputTime = time.Now()
+	m.MarkAllDirty()
+}
+
+// UpdateScreensaver starts the screensaver once the session has been idle
+// long enough and advances its animation. It reports whether the screen
+// changed.
+func (m *OS) UpdateScreensaver(now time.Time) bool {
+	if m.Screensaver == nil {
+		if config.ScreensaverIdle <= 0 || m.LastInputTime.IsZero() || now.Sub(m.LastInputTime) < config.ScreensaverIdle {
+			return false
+		}
+		m.Screensaver = newScreensaver(config.Screensaver, m.Width, m.Height, now)
+		return true
+	}
+	s := m.Screensaver
+	if s.width != max(m.Width, 1) || s.height != max(m.Height, 1) {
+		m.Screensaver = newScreensaver(s.Kind, m.Width, m.Height, now)
+		return true
+	}
+	interval, ok := screensaverFrameIntervals[s.Kind]
+	if !ok || now.Sub(s.lastStep) < interval {
+		return false
+	}
+	s.lastStep = now
+	s.step(now)
+	s.draw(now)
+	return true
+}
+
+// step advances the animation by one frame.
+func (s *Screensaver) step(now time.Time) {
+	s.steps++
+	switch s.Kind {
+	case "matrix":
+		for x := range s.drops {
+			d := &s.drops[x]
+			if s.steps%d.speed == 0 {
+				d.head++
+			}
+			if d.head-d.length > s.height {
+				s.resetDrop(x, false)
+			}
+		}
+		// Flicker a few characters as they fall
+		for range len(s.glyphs)/50 + 1 {
+			s.glyphs[s.rng.IntN(len(s.glyphs))] = s.randomGlyph()
+		}
+	case "pipes":
+		// Start over once the screen has filled up
+		if s.steps > s.width*s.height/2 {
+			s.resetPipes()
+		}
+		for i := range s.pipes {
+			s.growPipe(&s.pipes[i])
+		}
+	default:
+		if now.Sub(s.clockMoved) >= screensaverClockMoveInterval {
+			s.clockMoved = now
+			s.moveClock()
+		}
+	}
+}
+
+// randomGlyph returns a random matrix character: half-width katakana and
+// digits, or letters and digits in ASCII-only mode.
+func (s *Screensaver) randomGlyph() rune {
+	if config.UseASCIIOnly {
+		const chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+		return rune(chars[s.rng.IntN(len(chars))])
+	}
+	if s.rng.IntN(4) == 0 {
+		return rune('0' + s.rng.IntN(10))
+	}
+	return rune(0xFF66 + s.rng.IntN(0xFF9D-0xFF66+1))
+}
+
+// resetDrop restarts the drop of column x above the screen. Initial drops
+// are spread over the screen so it does not start empty.
+func (s *Screensaver) resetDrop(x int, initial bool) {
+	d := &s.drops[x]
+	d.length = 4 + s.rng.IntN(max(s.height/2, 4))
+	d.speed = 1 + s.rng.IntN(3)
+	if initial {
+		d.head = s.rng.IntN(s.height*2) - s.height
+	} else {
+		d.head = -s.rng.IntN(s.height)
+	}
+}
+
+// resetPipes clears the screen and starts new pipes at random spots.
+func (s *Screensaver) resetPipes() {
+	clear(s.cells)
+	s.steps = 0
+	s.pipes = make([]screensaverPipe, screensaverPipes)
+	for i := range s.pipes {
+		s.pipes[i] = screensaverPipe{
+			x:     s.rng.IntN(s.width),
+			y:     s.rng.IntN(s.height),
+			dir:   s.rng.IntN(4),
+			color: ansi.IndexedColor(9 + s.rng.IntN(6)),
+		}
+	}
+}
+
+// pipeGlyphs maps the two sides a pipe cell connects, as a bit set of
+// directions, to the glyph drawing it.
+var pipeGlyphs = map[int]rune{
+	1<<0 | 1<<2: '┃',
+	1<<1 | 1<<3: '━',
+	1<<0 | 1<<1: '┗',
+	1<<1 | 1<<2: '┏',
+	1<<2 | 1<<3: '┓',
+	1<<3 | 1<<0: '┛',
+}
+
+// growPipe extends a pipe by one cell, turning now and then and wrapping
+// around the screen edges.
+func (s *Screensaver) growPipe(p *screensaverPipe) {
+	dir := p.dir
+	if s.rng.IntN(8) == 0 {
+		dir = (dir + 1 + 2*s.rng.IntN(2)) % 4
+	}
+	from := (p.dir + 2) % 4
+	glyph := pipeGlyphs[1<<from|1<<dir]
+	if config.UseASCIIOnly {
+		switch {
+		case from%2 == dir%2 && dir%2 == 0:
+			glyph = '|'
+		case from%2 == dir%2:
+			glyph = '-'
+		default:
+			glyph = '+'
+		}
+	}
+	s.cells[p.y*s.width+p.x] = screensaverCell{r: glyph, fg: p.color}
+
+	p.dir = dir
+	switch dir {
+	case 0:
+		p.y = (p.y - 1 + s.height) % s.height
+	case 1:
+		p.x = (p.x + 1) % s.width
+	case 2:
+		p.y = (p.y + 1) % s.height
+	default:
+		p.x = (p.x - 1 + s.width) % s.width
+	}
+}
+
+// clockDigits are the 3x5 shapes of the clock's digits and colon, drawn with
+// every column doubled.
+var clockDigits = map[rune][5]string{
+	'0': {"###", "# #", "# #", "# #", "###"},
+	'1': {"  #", "  #", "  #", "  #", "  #"},
+	'2': {"###", "  #", "###", "#  ", "###"},
+	'3': {"###", "  #", "###", "  #", "###"},
+	'4': {"# #", "# #", "###", "  #", "  #"},
+	'5': {"###", "#  ", "###", "  #", "###"},
+	'6': {"###", "#  ", "###", "# #", "###"},
+	'7': {"###", "  #", "  #", "  #", "  #"},
+	'8': {"###", "# #", "###", "# #", "###"},
+	'9': {"###", "# #", "###", "  #", "###"},
+	':': {" ", "#", " ", "#", " "},
+}
+
+// clockSize returns the width and height of the clock with its date line.
+func clockSize() (int, int) {
+	// Four digits and a colon, each doubled and followed by a space
+	return 4*(3*2+1) + 1*2 + 1, 7
+}
+
+// moveClock moves the clock to a random spot on the screen.
+func (s *Screensaver) moveClock() {
+	w, h := clockSize()
+	s.clockX = s.rng.IntN(max(s.width-w, 0) + 1)
+	s.clockY = s.rng.IntN(max(s.height-h, 0) + 1)
+}
+
+// draw renders the current state into the frame cells.
+func (s *Screensaver) draw(now time.Time) {
+	switch s.Kind {
+	case "matrix":
+		clear(s.cells)
+		for x, d := range s.drops {
+			for y := max(d.head-d.length+1, 0); y <= d.head && y < s.height; y++ {
+				fg := ansi.IndexedColor(22)
+				switch dist := d.head - y; {
+				case dist == 0:
+					fg = 231
+				case dist < d.length/3:
+					fg = 46
+				case dist < 2*d.length/3:
+					fg = 34
+				}
+				i := y*s.width + x
+				s.cells[i] = screensaverCell{r: s.glyphs[i], fg: fg}
+			}
+		}
+	case "pipes":
+		// Pipes draw into the cells as they grow
+	default:
+		clear(s.cells)
+		block := '█'
+		if config.UseASCIIOnly {
+			block = '#'
+		}
+		x := s.clockX
+		for _, c := range now.Format("15:04") {
+			shape := clockDigits[c]
+			for row, line := range shape {
+				for col, ch := range line {
+					if ch == '#' {
+						s.set(x+col*2, s.clockY+row, block, 14)
+						s.set(x+col*2+1, s.clockY+row, block, 14)
+					}
+				}
+			}
+			x += len(shape[0])*2 + 1
+		}
+		s.text(s.clockX, s.clockY+6, now.Format("Monday, January 2"), 8)
+	}
+	if now.Before(s.hintUntil) {
+		s.text(max((s.width-len(s.hint))/2, 0), s.height-1, s.hint, 11)
+	}
+}
+
+// set draws a cell if it is on the screen.
+func (s *Screensaver) set(x, y int, r rune, fg ansi.IndexedColor) {
+	if x >= 0 && x < s.width && y >= 0 && y < s.height {
+		s.cells[y*s.width+x] = screensaverCell{r: r, fg: fg}
+	}
+}
+
+// text draws a line of single-width text.
+func (s *Screensaver) text(x, y int, str string, fg ansi.IndexedColor) {
+	for _, r := range str {
+		s.set(x, y, r, fg)
+		x++
+	}
+}
+
+// Render returns the frame as styled lines.
+func (s *Screensaver) Render() string {
+	var b strings.Builder
+	blank := ansi.Style{}.BackgroundColor(screensaverBackground).String()
+	for y := range s.height {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(blank)
+		var fg ansi.IndexedColor
+		for x := range s.width {
+			c := s.cells[y*s.width+x]
+			if c.r == 0 {
+				b.WriteByte(' ')
+				continue
+			}
+			if c.fg != fg {
+				fg = c.fg
+				b.WriteString(ansi.Style{}.ForegroundColor(fg).String())
+			}
+			b.WriteRune(c.r)
+		}
+		b.WriteString(ansi.ResetStyle)
+	}
+	return b.String()
+}
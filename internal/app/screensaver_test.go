@@ -0,0 +1,69 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestScreensaver(t *testing.T) {
+	defer func(idle time.Duration, kind string, lock bool) {
+		config.ScreensaverIdle, config.Screensaver, config.ScreensaverLock = idle, kind, lock
+	}(config.ScreensaverIdle, config.Screensaver, config.ScreensaverLock)
+	config.ScreensaverIdle = time.Minute
+
+	for _, kind := range config.Screensavers {
+		t.Run(kind, func(t *testing.T) {
+			config.Screensaver = kind
+			config.ScreensaverLock = false
+			m := NewOS(OSOptions{Width: 80, Height: 24})
+			now := m.LastInputTime
+
+			if m.UpdateScreensaver(now.Add(30 * time.Second)) {
+				t.Fatal("expected no screensaver before the idle time")
+			}
+			if !m.UpdateScreensaver(now.Add(time.Minute)) || m.Screensaver == nil {
+				t.Fatal("expected the screensaver after the idle time")
+			}
+			m.UpdateScreensaver(now.Add(time.Minute + 2*time.Second))
+
+			lines := strings.Split(m.Screensaver.Render(), "\n")
+			if len(lines) != 24 {
+				t.Fatalf("expected 24 lines, got %d", len(lines))
+			}
+			for i, line := range lines {
+				if w := ansi.StringWidth(line); w != 80 {
+					t.Fatalf("line %d is %d cells wide, want 80", i, w)
+				}
+			}
+
+			if !m.NoteInput(tea.KeyPressMsg{Code: 'a', Text: "a"}) || m.Screensaver != nil {
+				t.Error("expected a key to dismiss the screensaver without reaching the session")
+			}
+			if m.NoteInput(tea.KeyPressMsg{Code: 'a', Text: "a"}) {
+				t.Error("expected input to pass through without a screensaver")
+			}
+		})
+	}
+
+	t.Run("lock", func(t *testing.T) {
+		config.Screensaver = "clock"
+		config.ScreensaverLock = true
+		m := NewOS(OSOptions{Width: 80, Height: 24})
+		m.UpdateScreensaver(m.LastInputTime.Add(time.Minute))
+
+		m.NoteInput(tea.KeyPressMsg{Code: 'a', Text: "a"})
+		m.NoteInput(tea.MouseClickMsg{X: 1, Y: 1})
+		if m.Screensaver == nil {
+			t.Fatal("expected the lock screen to ignore other input")
+		}
+		m.NoteInput(tea.KeyPressMsg{Code: 'u', Mod: tea.ModCtrl | tea.ModAlt})
+		if m.Screensaver != nil {
+			t.Error("expected the unlock chord to dismiss the lock screen")
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// ScrollbackUsage describes the scrollback memory held by one window.
+type ScrollbackUsage struct {
+	WindowID   string
+	Name       string
+	Lines      int       // Lines currently stored
+	MaxLines   int       // Capacity of the scrollback buffer
+	Bytes      int       // Estimated memory held by the stored lines
+	LastViewed time.Time // Last time the window was focused or visible
+	Focused    bool
+}
+
+// ScrollbackStats returns the scrollback memory usage of all windows, largest
+// first, together with the total across windows.
+func (m *OS) ScrollbackStats() ([]ScrollbackUsage, int) {
+	focused := m.GetFocusedWindow()
+	stats := make([]ScrollbackUsage, 0, len(m.Windows))
+	total := 0
+	for _, w := range m.Windows {
+		if w.Terminal == nil {
+			continue
+		}
+		usage := ScrollbackUsage{
+			WindowID:   w.ID,
+			Name:       m.getWindowDisplayName(w),
+			Lines:      w.Terminal.ScrollbackLen(),
+			Bytes:      w.Terminal.ScrollbackMemory(),
+			LastViewed: w.LastViewed,
+			Focused:    w == focused,
+		}
+		if sb := w.Terminal.Scrollback(); sb != nil {
+			usage.MaxLines = sb.MaxLines()
+		}
+		stats = append(stats, usage)
+		total += usage.Bytes
+	}
+	slices.SortStableFunc(stats, func(a, b ScrollbackUsage) int {
+		return cmp.Compare(b.Bytes, a.Bytes)
+	})
+	return stats, total
+}
+
+// EnforceScrollbackBudget trims scrollback so that no window exceeds
+// config.ScrollbackWindowMemoryLimit and all windows together stay within
+// config.ScrollbackMemoryBudget. It is called on every tick and throttles
+// itself to config.ScrollbackBudgetCheckInterval.
+func (m *OS) EnforceScrollbackBudget() {
+	now := time.Now()
+	if now.Sub(m.LastScrollbackTrim) < config.ScrollbackBudgetCheckInterval {
+		return
+	}
+	m.LastScrollbackTrim = now
+	m.enforceScrollbackBudget(now)
+}
+
+// TrimScrollbackNow enforces the scrollback budgets immediately and returns
+// the number of lines discarded.
+func (m *OS) TrimScrollbackNow() int {
+	m.LastScrollbackTrim = time.Now()
+	return m.enforceScrollbackBudget(m.LastScrollbackTrim)
+}
+
+// enforceScrollbackBudget performs a single budget check and returns the
+// number of scrollback lines that were discarded.
+func (m *OS) enforceScrollbackBudget(now time.Time) int {
+	focused := m.GetFocusedWindow()
+	var candidates []*terminal.Window
+	for _, w := range m.Windows {
+		if w.Terminal == nil {
+			continue
+		}
+		if w == focused || (w.Workspace == m.CurrentWorkspace && !w.Minimized) {
+			w.LastViewed = now
+		}
+		// Trimming shifts line indices, so leave windows alone while their
+		// scrollback is being browsed.
+		if w.ScrollbackMode || (w.CopyMode != nil && w.CopyMode.Active) {
+			continue
+		}
+		candidates = append(candidates, w)
+	}
+
+	trimmed := 0
+	if limit := config.ScrollbackWindowMemoryLimit; limit > 0 {
+		for _, w := range candidates {
+			if used := w.Terminal.ScrollbackMemory(); used > limit {
+				trimmed += trimScrollbackBytes(w, used-limit, 0)
+			}
+		}
+	}
+
+	budget := config.ScrollbackMemoryBudget
+	if budget <= 0 {
+		return m.logScrollbackTrim(trimmed)
+	}
+	total := 0
+	for _, w := range m.Windows {
+		if w.Terminal != nil {
+			total += w.Terminal.ScrollbackMemory()
+		}
+	}
+	if total <= budget {
+		return m.logScrollbackTrim(trimmed)
+	}
+
+	// Reclaim memory from the windows that were viewed least recently first.
+	slices.SortStableFunc(candidates, func(a, b *terminal.Window) int {
+		return a.LastViewed.Compare(b.LastViewed)
+	})
+	for _, w := range candidates {
+		if total <= budget {
+			break
+		}
+		before := w.Terminal.ScrollbackMemory()
+		trimmed += trimScrollbackBytes(w, total-budget, config.ScrollbackTrimMinLines)
+		total -= before - w.Terminal.ScrollbackMemory()
+	}
+	return m.logScrollbackTrim(trimmed)
+}
+
+// logScrollbackTrim records a budget check that discarded lines.
+func (m *OS) logScrollbackTrim(lines int) int {
+	if lines > 0 {
+		m.LogInfo("Scrollback budget: discarded %d old lines", lines)
+	}
+	return lines
+}
+
+// trimScrollbackBytes discards the oldest lines of a window's scrollback until
+// at least the given number of bytes has been released, keeping at least
+// keepLines lines. It returns the number of lines discarded.
+func trimScrollbackBytes(w *terminal.Window, bytes, keepLines int) int {
+	lines := w.Terminal.ScrollbackLen()
+	used := w.Terminal.ScrollbackMemory()
+	if lines <= keepLines || used == 0 {
+		return 0
+	}
+	perLine := max(used/lines, 1)
+	n := min((bytes+perLine-1)/perLine, lines-keepLines)
+	return w.Terminal.TrimScrollback(n)
+}
+
+// formatBytes formats a byte count using binary units.
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := unit, 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+// newScrollbackWindow creates a background window whose scrollback holds the
+// given number of lines.
+func newScrollbackWindow(id string, lines int, lastViewed time.Time) *terminal.Window {
+	emu := vt.NewEmulator(20, 5)
+	emu.SetScrollbackMaxLines(lines * 2)
+	_, _ = emu.Write([]byte(strings.Repeat("line\r\n", lines+5)))
+	return &terminal.Window{ID: id, Terminal: emu, Workspace: 2, LastViewed: lastViewed}
+}
+
+func withScrollbackLimits(t *testing.T, budget, perWindow int) {
+	t.Helper()
+	prevBudget, prevWindow := config.ScrollbackMemoryBudget, config.ScrollbackWindowMemoryLimit
+	config.ScrollbackMemoryBudget, config.ScrollbackWindowMemoryLimit = budget, perWindow
+	t.Cleanup(func() {
+		config.ScrollbackMemoryBudget, config.ScrollbackWindowMemoryLimit = prevBudget, prevWindow
+	})
+}
+
+func TestEnforceScrollbackBudgetTrimsLeastRecentlyViewed(t *testing.T) {
+	now := time.Now()
+	older := newScrollbackWindow("older", 1000, now.Add(-time.Hour))
+	newer := newScrollbackWindow("newer", 1000, now.Add(-time.Minute))
+	m := &OS{Windows: []*terminal.Window{newer, older}, FocusedWindow: -1, CurrentWorkspace: 1}
+
+	perWindow := older.Terminal.ScrollbackMemory()
+	fullLen := newer.Terminal.ScrollbackLen()
+	withScrollbackLimits(t, perWindow*3/2, 0)
+
+	if trimmed := m.enforceScrollbackBudget(now); trimmed == 0 {
+		t.Fatal("expected lines to be trimmed")
+	}
+	if got := newer.Terminal.ScrollbackLen(); got != fullLen {
+		t.Errorf("most recently viewed window should keep its scrollback, got %d lines", got)
+	}
+	if got := older.Terminal.ScrollbackLen(); got >= fullLen || got < config.ScrollbackTrimMinLines {
+		t.Errorf("least recently viewed window should be trimmed to at least %d lines, got %d",
+			config.ScrollbackTrimMinLines, got)
+	}
+	if _, total := m.ScrollbackStats(); total > config.ScrollbackMemoryBudget {
+		t.Errorf("total %d still above budget %d", total, config.ScrollbackMemoryBudget)
+	}
+}
+
+func TestEnforceScrollbackBudgetPerWindowLimit(t *testing.T) {
+	now := time.Now()
+	w := newScrollbackWindow("big", 1000, now)
+	m := &OS{Windows: []*terminal.Window{w}, FocusedWindow: -1, CurrentWorkspace: 1}
+
+	withScrollbackLimits(t, 0, w.Terminal.ScrollbackMemory()/4)
+
+	m.enforceScrollbackBudget(now)
+	if got := w.Terminal.ScrollbackMemory(); got > config.ScrollbackWindowMemoryLimit {
+		t.Errorf("window uses %d bytes, limit is %d", got, config.ScrollbackWindowMemoryLimit)
+	}
+	if got := w.Terminal.ScrollbackLen(); got == 0 {
+		t.Error("expected the newest lines to be kept")
+	}
+}
+
+func TestEnforceScrollbackBudgetSkipsBrowsedWindows(t *testing.T) {
+	now := time.Now()
+	w := newScrollbackWindow("browsing", 1000, now.Add(-time.Hour))
+	w.ScrollbackMode = true
+	m := &OS{Windows: []*terminal.Window{w}, FocusedWindow: -1, CurrentWorkspace: 1}
+
+	withScrollbackLimits(t, 1, 1)
+
+	if trimmed := m.enforceScrollbackBudget(now); trimmed != 0 {
+		t.Errorf("expected no trimming while scrollback is browsed, trimmed %d", trimmed)
+	}
+}
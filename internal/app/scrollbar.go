@@ -0,0 +1,90 @@
+package app
+
+import (
+	"image/color"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// scrolledPane returns the terminal of a window that is scrolled back into
+// its scrollback, which is the active pane of a split window, or nil when
+// the window shows its live screen.
+func scrolledPane(w *terminal.Window) *terminal.Window {
+	pane := w.ActivePane()
+	if pane == nil || pane.Terminal == nil || pane.ScrollbackOffset <= 0 {
+		return nil
+	}
+	return pane
+}
+
+// scrollbarThumb returns the first row and the number of rows of the
+// scrollbar thumb of a track height rows high, for a view scrolled offset
+// lines back into scrollback lines.
+func scrollbarThumb(offset, scrollback, height int) (top, size int) {
+	if height <= 0 {
+		return 0, 0
+	}
+	size = min(max(height*height/(scrollback+height), 1), height)
+	top = (scrollback - min(offset, scrollback)) * (height - size) / max(scrollback, 1)
+	return top, size
+}
+
+// addScrollbar draws the scrollbar of a scrolled back window in the last
+// column of its content, next to the right border so that border still
+// resizes the window: the thumb shows which part of the scrollback is in
+// view.
+func addScrollbar(box string, w, pane *terminal.Window, borderColor color.Color) string {
+	lines := strings.Split(box, "\n")
+	height := min(w.Height-2, len(lines)-2)
+	top, size := scrollbarThumb(pane.ScrollbackOffset, pane.ScrollbackLen(), height)
+
+	thumbChar := "┃"
+	if config.UseASCIIOnly {
+		thumbChar = "#"
+	}
+	thumb := lipgloss.NewStyle().Foreground(borderColor).Bold(true).Render(thumbChar)
+	for row := top; row < top+size; row++ {
+		i := row + 1 // Below the top border
+		lines[i] = ansi.Truncate(lines[i], w.Width-2, "") + thumb + ansi.TruncateLeft(lines[i], w.Width-1, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// OnScrollbar reports whether screen position (x, y) is on the scrollbar of
+// the window, which is drawn in the last column of its content while it is
+// scrolled back.
+func (m *OS) OnScrollbar(w *terminal.Window, x, y int) bool {
+	return scrolledPane(w) != nil && x == w.X+w.Width-2 && y > w.Y && y < w.Y+w.Height-1
+}
+
+// SeekScrollbar scrolls the window to the part of its scrollback shown at
+// screen row y of its scrollbar: the top row shows the oldest lines and the
+// bottom row the live screen. A window that is not in copy or scrollback
+// mode enters copy mode, as when it is scrolled with the wheel.
+func (m *OS) SeekScrollbar(w *terminal.Window, y int) {
+	pane := w.ActivePane()
+	if pane == nil || pane.Terminal == nil {
+		return
+	}
+	inCopyMode := pane.CopyMode != nil && pane.CopyMode.Active
+	if !inCopyMode && !pane.ScrollbackMode && !m.SelectionMode {
+		pane.EnterCopyMode()
+		inCopyMode = true
+	}
+
+	height := max(w.Height-2, 1)
+	row := min(max(y-w.Y-1, 0), height-1)
+	scrollback := pane.ScrollbackLen()
+	offset := scrollback - row*scrollback/max(height-1, 1)
+
+	if inCopyMode {
+		pane.CopyMode.ScrollOffset = offset
+	}
+	pane.ScrollbackOffset = offset
+	pane.InvalidateCache()
+	w.MarkContentDirty()
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestScrollbarSeek(t *testing.T) {
+	w := newScrollbackWindow("scrolled", 100, time.Now())
+	w.X, w.Y, w.Width, w.Height, w.Workspace = 0, 0, 22, 7, 1
+	m := &OS{Windows: []*terminal.Window{w}, FocusedWindow: 0, CurrentWorkspace: 1}
+	if m.OnScrollbar(w, 20, 3) {
+		t.Fatal("scrollbar shown on a window viewing its live screen")
+	}
+
+	// Dragging from the top of the scrollbar seeks to the oldest lines
+	w.EnterCopyMode()
+	w.CopyMode.ScrollOffset, w.ScrollbackOffset = 1, 1
+	if !m.OnScrollbar(w, 20, 3) {
+		t.Fatal("no scrollbar on a scrolled back window")
+	}
+	if m.OnScrollbar(w, 21, 3) {
+		t.Error("scrollbar on the right border, which resizes the window")
+	}
+	m.SeekScrollbar(w, 1)
+	if got := w.ScrollbackOffset; got != w.ScrollbackLen() || w.CopyMode.ScrollOffset != got {
+		t.Errorf("top of the scrollbar scrolled to %d, want %d", got, w.ScrollbackLen())
+	}
+	if top, _ := scrollbarThumb(w.ScrollbackOffset, w.ScrollbackLen(), 5); top != 0 {
+		t.Errorf("thumb at row %d for the oldest lines, want 0", top)
+	}
+
+	// The bottom row is the live screen
+	m.SeekScrollbar(w, 10)
+	if w.ScrollbackOffset != 0 {
+		t.Errorf("bottom of the scrollbar scrolled to %d, want 0", w.ScrollbackOffset)
+	}
+}
+
+func TestScrollbarKeepsBorder(t *testing.T) {
+	w := newScrollbackWindow("scrolled", 100, time.Now())
+	w.Width, w.Height = 8, 4
+	w.ScrollbackOffset = 1
+	box := strings.Join([]string{"╭──────╮", "│abcdef│", "│ghijkl│", "╰──────╯"}, "\n")
+	lines := strings.Split(ansi.Strip(addScrollbar(box, w, w, nil)), "\n")
+	for i, line := range lines[1:3] {
+		if ansi.StringWidth(line) != w.Width || !strings.HasSuffix(line, "│") {
+			t.Errorf("row %d = %q, want the right border kept", i+1, line)
+		}
+	}
+	if lines[1] != "│abcde┃│" {
+		t.Errorf("expected the thumb in the last content column, got %q", lines[1])
+	}
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// secureInput reports whether the program of a window, or of any of its
+// panes and tabs, reads a password with echo off.
+func secureInput(w *terminal.Window) bool {
+	for _, t := range badgeTerminals(w) {
+		if t.SecureInput() {
+			return true
+		}
+	}
+	return false
+}
+
+// secureInputMark returns the mark the title bar of a window reading a
+// password starts with.
+func secureInputMark() string {
+	if config.UseASCIIOnly {
+		return "[secure]"
+	}
+	return "\uf023"
+}
+
+// RefreshSecureInput samples the echo setting of the local windows' PTYs
+// and marks the windows whose programs started or stopped reading a
+// password for redrawing, so their title bar shows the secure input mark
+// while they do. It reports whether any window changed.
+func (m *OS) RefreshSecureInput() bool {
+	changed := false
+	for _, w := range m.Windows {
+		for _, t := range badgeTerminals(w) {
+			t.SampleSecureInput()
+		}
+		if secureInput(w) == m.secureWindows[w.ID] {
+			continue
+		}
+		if m.secureWindows == nil {
+			m.secureWindows = make(map[string]bool)
+		}
+		if secureInput(w) {
+			m.secureWindows[w.ID] = true
+		} else {
+			delete(m.secureWindows, w.ID)
+		}
+		w.Dirty = true
+		changed = true
+	}
+	return changed
+}
+
+// SecureInputFocused reports whether keys typed now go to a program reading
+// a password. Tape recording and the showkeys overlay leave such keys out.
+func (m *OS) SecureInputFocused() bool {
+	if m.Mode != TerminalMode {
+		return false
+	}
+	w := m.GetFocusedWindow()
+	if w == nil {
+		return false
+	}
+	pane := w.ActivePane()
+	return pane != nil && pane.SecureInput()
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// seeThroughColor is the color the windows below a floating window are
+// drawn in where it is see-through.
+var seeThroughColor = lipgloss.Color("#5a5a6e")
+
+// LayerZ returns the z-index a window is drawn at: picture-in-picture
+// windows above all others, the rest by their Z.
+func LayerZ(w *terminal.Window) int {
+	if w.PiP {
+		return config.ZIndexPiP
+	}
+	return w.Z
+}
+
+// seeThroughWindows returns, with config.SeeThroughFloats, the floating
+// windows of the current workspace that cover part of other windows, each
+// with the windows it covers, topmost first. A window over one that changed
+// is marked for a redraw, as what shows through it changed too.
+func (m *OS) seeThroughWindows() map[*terminal.Window][]*terminal.Window {
+	if !config.SeeThroughFloats {
+		return nil
+	}
+	var shown []*terminal.Window
+	for _, w := range m.Windows {
+		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing {
+			shown = append(shown, w)
+		}
+	}
+
+	var covering map[*terminal.Window][]*terminal.Window
+	for _, w := range shown {
+		if m.AutoTiling && !w.Floating && !w.PiP {
+			continue
+		}
+		var below []*terminal.Window
+		for _, other := range shown {
+			if LayerZ(other) < LayerZ(w) && windowsOverlap(w, other) {
+				below = append(below, other)
+			}
+		}
+		if len(below) == 0 {
+			continue
+		}
+		slices.SortFunc(below, func(a, b *terminal.Window) int { return LayerZ(b) - LayerZ(a) })
+		if covering == nil {
+			covering = make(map[*terminal.Window][]*terminal.Window)
+		}
+		covering[w] = below
+		if slices.ContainsFunc(below, func(b *terminal.Window) bool { return b.Dirty || b.ContentDirty || b.PositionDirty }) {
+			w.Dirty = true
+		}
+	}
+	return covering
+}
+
+// windowsOverlap reports whether two windows share any screen cell.
+func windowsOverlap(a, b *terminal.Window) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width && a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
+
+// showThrough draws the content of the windows below a floating window,
+// dimmed, in the cells of its rendered box that show nothing: blank cells
+// without a background color. Borders stay opaque.
+func (m *OS) showThrough(box string, w *terminal.Window, below []*terminal.Window) string {
+	height := lipgloss.Height(box)
+	buf := uv.NewScreenBuffer(w.Width, height)
+	uv.NewStyledString(box).Draw(buf, buf.Bounds())
+
+	m.terminalMu.Lock()
+	defer m.terminalMu.Unlock()
+	style := uv.Style{Fg: seeThroughColor, Attrs: uv.AttrFaint}
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < w.Width-1; x++ {
+			cell := buf.CellAt(x, y)
+			if cell == nil || cell.Width != 1 || strings.TrimSpace(cell.Content) != "" || cell.Style.Bg != nil || cell.Style.Attrs&uv.AttrReverse != 0 {
+				continue
+			}
+			if content := contentBeneath(below, w.X+x, w.Y+y); content != "" {
+				buf.SetCell(x, y, &uv.Cell{Content: content, Width: 1, Style: style})
+			}
+		}
+	}
+	return strings.ReplaceAll(buf.Render(), "\r\n", "\n")
+}
+
+// contentBeneath returns the character the topmost of the windows below
+// shows at screen position (x, y), or "" for none: a blank, a wide
+// character or a border.
+func contentBeneath(below []*terminal.Window, x, y int) string {
+	for _, w := range below {
+		if x < w.X || x >= w.X+w.Width || y < w.Y || y >= w.Y+w.Height {
+			continue
+		}
+		cx, cy := x-w.X-1, y-w.Y-1
+		if cx < 0 || cy < 0 || cx >= w.Width-2 || cy >= w.Height-2 {
+			return ""
+		}
+		pane, px, py := w.PaneAt(cx, cy)
+		if pane == nil || pane.Terminal == nil {
+			return ""
+		}
+		if content, width := visibleCell(pane, px, py); width == 1 && strings.TrimSpace(content) != "" {
+			return content
+		}
+		return ""
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSeeThroughFloats(t *testing.T) {
+	prev := config.SeeThroughFloats
+	t.Cleanup(func() { config.SeeThroughFloats = prev })
+
+	lowerTerm := vt.NewEmulator(18, 4)
+	_, _ = lowerTerm.Write([]byte("hello world"))
+	lower := &terminal.Window{ID: "lower", X: 0, Y: 0, Width: 20, Height: 6, Z: 0, Workspace: 1, Terminal: lowerTerm}
+	upper := &terminal.Window{ID: "upper", X: 4, Y: 0, Width: 10, Height: 4, Z: 1, Workspace: 1}
+	apart := &terminal.Window{ID: "apart", X: 30, Y: 0, Width: 10, Height: 4, Z: 2, Workspace: 1}
+	m := &OS{Windows: []*terminal.Window{lower, upper, apart}, CurrentWorkspace: 1}
+
+	if m.seeThroughWindows() != nil {
+		t.Fatal("windows see-through with the option off")
+	}
+	config.SeeThroughFloats = true
+	lower.MarkContentDirty()
+	covering := m.seeThroughWindows()
+	if len(covering) != 1 || len(covering[upper]) != 1 || !upper.Dirty {
+		t.Fatalf("covering = %v, want only the upper window over the lower one, marked dirty", covering)
+	}
+
+	// The blank cells of the upper window show the lower window's text; its
+	// border and own text stay
+	box := "╭────────╮\n│ab      │\n│        │\n╰────────╯"
+	got := ansi.Strip(m.showThrough(box, upper, covering[upper]))
+	want := "╭────────╮\n│abworld │\n│        │\n╰────────╯"
+	if got != want {
+		t.Errorf("showThrough() = %q, want %q", got, want)
+	}
+}
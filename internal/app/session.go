@@ -6,6 +6,7 @@ import (
 	"maps"
 	"os"
 
+	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/layout"
 	"github.com/Gaurav-Gosain/tuios/internal/session"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
@@ -78,8 +79,13 @@ func (m *OS) BuildSessionState() *session.SessionState {
 	}
 
 	// Build window states
-	state.Windows = make([]session.WindowState, len(m.Windows))
-	for i, w := range m.Windows {
+	state.Windows = make([]session.WindowState, 0, len(m.Windows))
+	for _, w := range m.Windows {
+		// Debug consoles have no PTY to come back to
+		if m.IsDebugConsole(w) {
+			continue
+		}
+
 		// Start with current values
 		x, y, width, height := w.X, w.Y, w.Width, w.Height
 
@@ -92,24 +98,9 @@ func (m *OS) BuildSessionState() *session.SessionState {
 			height = anim.EndHeight
 		}
 
-		state.Windows[i] = session.WindowState{
-			ID:           w.ID,
-			Title:        w.Title,
-			CustomName:   w.CustomName,
-			X:            x,
-			Y:            y,
-			Width:        width,
-			Height:       height,
-			Z:            w.Z,
-			Workspace:    w.Workspace,
-			Minimized:    w.Minimized,
-			PreMinimizeX: w.PreMinimizeX,
-			PreMinimizeY: w.PreMinimizeY,
-			PreMinimizeW: w.PreMinimizeWidth,
-			PreMinimizeH: w.PreMinimizeHeight,
-			PTYID:        w.PTYID,
-			IsAltScreen:  w.IsAltScreen, // Save alt screen state for mouse forwarding on restore
-		}
+		ws := windowState(w)
+		ws.X, ws.Y, ws.Width, ws.Height = x, y, width, height
+		state.Windows = append(state.Windows, ws)
 	}
 
 	// Set focused window ID
@@ -123,12 +114,24 @@ func (m *OS) BuildSessionState() *session.SessionState {
 			state.WorkspaceFocus[workspace] = m.Windows[windowIdx].ID
 		}
 	}
+	state.Marks = m.windowMarksState()
+	state.WorkspaceNames = maps.Clone(m.WorkspaceNames)
+	state.PopulatedWorkspaces = m.populatedWorkspacesState()
+	state.TagView = m.tagViewState()
 
-	// Serialize BSP trees for each workspace
-	if m.WorkspaceTrees != nil && m.AutoTiling {
+	// Record the tiling mode of every workspace
+	state.WorkspaceTiling = make(map[int]bool)
+	for ws := 1; ws <= m.NumWorkspaces; ws++ {
+		if m.WorkspaceAutoTiling(ws) {
+			state.WorkspaceTiling[ws] = true
+		}
+	}
+
+	// Serialize BSP trees for each tiled workspace
+	if m.WorkspaceTrees != nil {
 		state.WorkspaceTrees = make(map[int]*session.SerializedBSPTree)
 		for ws, tree := range m.WorkspaceTrees {
-			if tree != nil {
+			if tree != nil && m.WorkspaceAutoTiling(ws) {
 				serialized := tree.Serialize()
 				if serialized != nil {
 					state.WorkspaceTrees[ws] = &session.SerializedBSPTree{
@@ -180,7 +183,7 @@ func (m *OS) RestoreFromState(state *session.SessionState) error {
 	m.SessionName = state.Name
 	m.CurrentWorkspace = state.CurrentWorkspace
 	m.MasterRatio = state.MasterRatio
-	m.AutoTiling = state.AutoTiling
+	m.restoreWorkspaceTiling(state)
 	m.Mode = Mode(state.Mode)
 
 	// Set effective dimensions from state - this is the min of all connected clients
@@ -225,13 +228,26 @@ func (m *OS) RestoreFromState(state *session.SessionState) error {
 		}
 
 		window.CustomName = ws.CustomName
+		window.RuleName = ws.RuleName
+		window.Group = ws.Group
+		window.SwallowedBy = ws.SwallowedBy
 		window.Workspace = ws.Workspace
 		window.Minimized = ws.Minimized
 		window.PreMinimizeX = ws.PreMinimizeX
 		window.PreMinimizeY = ws.PreMinimizeY
 		window.PreMinimizeWidth = ws.PreMinimizeW
 		window.PreMinimizeHeight = ws.PreMinimizeH
+		restoreFloating(window, &ws)
+		restorePiP(window, &ws)
+		window.SetZoomed(ws.Zoomed)
 		window.IsAltScreen = ws.IsAltScreen // Restore alt screen state for mouse event forwarding
+		window.ScrollbackOverride = ws.Scrollback
+		window.MinWidth, window.MinHeight = ws.MinWidth, ws.MinHeight
+		window.Command = ws.Command
+		window.Icon = ws.Icon
+		window.Tags = ws.Tags
+		window.ClipboardPolicy = ws.Clipboard
+		window.ApplyScrollbackLimit()
 
 		// CRITICAL: Suppress callbacks during restoration to prevent race condition
 		// where buffered PTY output overwrites the restored IsAltScreen state
@@ -240,6 +256,10 @@ func (m *OS) RestoreFromState(state *session.SessionState) error {
 
 		m.setupKittyPassthrough(window)
 		m.setupSixelPassthrough(window)
+		m.restoreDaemonPanes(window, &ws)
+		for _, pane := range withPanes(window)[1:] {
+			pane.DisableCallbacks()
+		}
 
 		m.Windows = append(m.Windows, window)
 		m.LogInfo("[RESTORE] Window %d created: DaemonMode=%v, PTYID=%s", i, window.DaemonMode, window.PTYID[:8])
@@ -267,6 +287,69 @@ func (m *OS) RestoreFromState(state *session.SessionState) error {
 		}
 	}
 
+	m.restoreTilingState(state)
+	m.WindowMarks = maps.Clone(state.Marks)
+	m.WorkspaceNames = maps.Clone(state.WorkspaceNames)
+	m.restorePopulatedWorkspaces(state.PopulatedWorkspaces)
+	m.restoreTagView(state.TagView)
+
+	m.MarkAllDirty()
+	m.LogInfo("[RESTORE] Restored session state: %d windows, FocusedWindow=%d, AutoTiling=%v", len(m.Windows), m.FocusedWindow, m.AutoTiling)
+
+	// Mark that we restored from state - this prevents the first resize from retiling
+	// and allows the layout to be preserved as the user left it
+	m.RestoredFromState = true
+
+	// If we have windows and a focused window, switch to terminal mode
+	// This ensures mouse events are forwarded to terminals after restore
+	if len(m.Windows) > 0 && m.FocusedWindow >= 0 {
+		m.Mode = TerminalMode
+	}
+
+	return nil
+}
+
+// restoreFloating restores whether a window floats and its last floating
+// geometry from its saved state.
+func restoreFloating(w *terminal.Window, ws *session.WindowState) {
+	w.Floating = ws.Floating
+	w.FloatX, w.FloatY = ws.FloatX, ws.FloatY
+	w.FloatWidth, w.FloatHeight = ws.FloatW, ws.FloatH
+}
+
+// restorePiP restores a window's picture-in-picture state from a saved
+// state.
+func restorePiP(w *terminal.Window, ws *session.WindowState) {
+	w.PiP = ws.PiP
+	w.PrePiPX, w.PrePiPY = ws.PrePiPX, ws.PrePiPY
+	w.PrePiPWidth, w.PrePiPHeight = ws.PrePiPW, ws.PrePiPH
+}
+
+// stateWorkspaceTiling reports whether a workspace is tiled in a saved state.
+// States saved before tiling was per workspace have one mode for all.
+func stateWorkspaceTiling(state *session.SessionState, workspace int) bool {
+	if state.WorkspaceTiling == nil {
+		return state.AutoTiling
+	}
+	return state.WorkspaceTiling[workspace]
+}
+
+// restoreWorkspaceTiling restores the tiling mode of every workspace from a
+// saved state. m.CurrentWorkspace must already be set.
+func (m *OS) restoreWorkspaceTiling(state *session.SessionState) {
+	m.WorkspaceTiling = make(map[int]bool)
+	for ws := 1; ws <= m.NumWorkspaces; ws++ {
+		if stateWorkspaceTiling(state, ws) {
+			m.WorkspaceTiling[ws] = true
+		}
+	}
+	m.AutoTiling = stateWorkspaceTiling(state, m.CurrentWorkspace)
+	delete(m.WorkspaceTiling, m.CurrentWorkspace)
+}
+
+// restoreTilingState restores the BSP tiling trees and window ID mapping from
+// a SessionState.
+func (m *OS) restoreTilingState(state *session.SessionState) {
 	// Restore window to BSP ID mapping FIRST (before BSP trees)
 	// This ensures getWindowIntID() returns correct IDs when we deserialize trees
 	if state.WindowToBSPID != nil {
@@ -281,10 +364,10 @@ func (m *OS) RestoreFromState(state *session.SessionState) error {
 	m.LogInfo("[RESTORE] NextBSPWindowID=%d, TilingScheme=%d", m.NextBSPWindowID, m.TilingScheme)
 
 	// Restore BSP trees
-	if state.WorkspaceTrees != nil && state.AutoTiling {
+	if state.WorkspaceTrees != nil {
 		m.WorkspaceTrees = make(map[int]*layout.BSPTree)
 		for ws, serialized := range state.WorkspaceTrees {
-			if serialized != nil {
+			if serialized != nil && stateWorkspaceTiling(state, ws) {
 				// Convert session.SerializedBSPTree to layout.SerializedBSPTree
 				layoutSerialized := &layout.SerializedBSPTree{
 					Root:         convertSessionBSPNode(serialized.Root),
@@ -300,30 +383,66 @@ func (m *OS) RestoreFromState(state *session.SessionState) error {
 			}
 		}
 	}
+}
 
-	m.MarkAllDirty()
-	m.LogInfo("[RESTORE] Restored session state: %d windows, FocusedWindow=%d, AutoTiling=%v", len(m.Windows), m.FocusedWindow, m.AutoTiling)
+// ApplyStateSync applies a state update from another client.
+// This handles window creation, deletion, and property updates.
+func (m *OS) ApplyStateSync(state *session.SessionState) error {
+	return m.applyStateSync(state, false)
+}
 
-	// Mark that we restored from state - this prevents the first resize from retiling
-	// and allows the layout to be preserved as the user left it
-	m.RestoredFromState = true
+// ApplyRemoteStateSync applies a state update sent by another client. With
+// config.IndependentFocus, this client keeps its own focus, workspace and mode
+// and only records which window the other client has focused.
+func (m *OS) ApplyRemoteStateSync(state *session.SessionState, sourceID string) error {
+	if !config.IndependentFocus || sourceID == "" || state == nil {
+		return m.ApplyStateSync(state)
+	}
+	if m.RemoteFocus == nil {
+		m.RemoteFocus = make(map[string]string)
+	}
+	m.RemoteFocus[sourceID] = state.FocusedWindowID
+	return m.applyStateSync(state, true)
+}
 
-	// If we have windows and a focused window, switch to terminal mode
-	// This ensures mouse events are forwarded to terminals after restore
-	if len(m.Windows) > 0 && m.FocusedWindow >= 0 {
-		m.Mode = TerminalMode
+// ForgetRemoteFocus drops the focus indicator of a client that left.
+func (m *OS) ForgetRemoteFocus(clientID string) {
+	if _, ok := m.RemoteFocus[clientID]; ok {
+		delete(m.RemoteFocus, clientID)
+		m.MarkAllDirty()
 	}
+}
 
-	return nil
+// IsRemotelyFocused reports whether another client has the window focused.
+func (m *OS) IsRemotelyFocused(windowID string) bool {
+	for _, id := range m.RemoteFocus {
+		if id == windowID {
+			return true
+		}
+	}
+	return false
 }
 
-// ApplyStateSync applies a state update from another client.
-// This handles window creation, deletion, and property updates.
-func (m *OS) ApplyStateSync(state *session.SessionState) error {
+func (m *OS) applyStateSync(state *session.SessionState, keepLocalFocus bool) error {
 	if state == nil {
 		return nil
 	}
 
+	// Remember local focus by window ID, since indices change below
+	var localFocusID string
+	localWorkspaceFocus := make(map[int]string)
+	localWorkspace, localMode := m.CurrentWorkspace, m.Mode
+	if keepLocalFocus {
+		if w := m.GetFocusedWindow(); w != nil {
+			localFocusID = w.ID
+		}
+		for workspace, idx := range m.WorkspaceFocus {
+			if idx >= 0 && idx < len(m.Windows) {
+				localWorkspaceFocus[workspace] = m.Windows[idx].ID
+			}
+		}
+	}
+
 	// Build maps for efficient lookup
 	incomingByID := make(map[string]*session.WindowState)
 	for i := range state.Windows {
@@ -366,13 +485,19 @@ func (m *OS) ApplyStateSync(state *session.SessionState) error {
 	m.SessionName = state.Name
 	m.CurrentWorkspace = state.CurrentWorkspace
 	m.MasterRatio = state.MasterRatio
-	m.AutoTiling = state.AutoTiling
+
+	focusID, workspaceFocus := state.FocusedWindowID, state.WorkspaceFocus
+	if keepLocalFocus {
+		m.CurrentWorkspace = localWorkspace
+		focusID, workspaceFocus = localFocusID, localWorkspaceFocus
+	}
+	m.restoreWorkspaceTiling(state)
 
 	// Update focused window index
 	m.FocusedWindow = -1
-	if state.FocusedWindowID != "" {
+	if focusID != "" {
 		for i, w := range m.Windows {
-			if w.ID == state.FocusedWindowID {
+			if w.ID == focusID {
 				m.FocusedWindow = i
 				break
 			}
@@ -381,7 +506,7 @@ func (m *OS) ApplyStateSync(state *session.SessionState) error {
 
 	// Update workspace focus map
 	m.WorkspaceFocus = make(map[int]int)
-	for workspace, windowID := range state.WorkspaceFocus {
+	for workspace, windowID := range workspaceFocus {
 		for i, w := range m.Windows {
 			if w.ID == windowID {
 				m.WorkspaceFocus[workspace] = i
@@ -390,6 +515,11 @@ func (m *OS) ApplyStateSync(state *session.SessionState) error {
 		}
 	}
 
+	m.WindowMarks = maps.Clone(state.Marks)
+	m.WorkspaceNames = maps.Clone(state.WorkspaceNames)
+	m.restorePopulatedWorkspaces(state.PopulatedWorkspaces)
+	m.restoreTagView(state.TagView)
+
 	// Update BSP state
 	if state.WindowToBSPID != nil {
 		m.WindowToBSPID = make(map[string]int)
@@ -399,10 +529,10 @@ func (m *OS) ApplyStateSync(state *session.SessionState) error {
 	m.TilingScheme = layout.AutoScheme(state.TilingScheme)
 
 	// Update BSP trees
-	if state.WorkspaceTrees != nil && state.AutoTiling {
+	if state.WorkspaceTrees != nil {
 		m.WorkspaceTrees = make(map[int]*layout.BSPTree)
 		for ws, serialized := range state.WorkspaceTrees {
-			if serialized != nil {
+			if serialized != nil && stateWorkspaceTiling(state, ws) {
 				layoutSerialized := &layout.SerializedBSPTree{
 					Root:         convertSessionBSPNode(serialized.Root),
 					AutoScheme:   serialized.AutoScheme,
@@ -415,6 +545,9 @@ func (m *OS) ApplyStateSync(state *session.SessionState) error {
 
 	// Sync mode from other client
 	m.Mode = Mode(state.Mode)
+	if keepLocalFocus {
+		m.Mode = localMode
+	}
 
 	// If auto-tiling is enabled and the synced state has different dimensions,
 	// retile to fit our effective render size. This handles the case where
@@ -449,6 +582,9 @@ func (m *OS) updateWindowFromState(w *terminal.Window, ws *session.WindowState)
 	// Update all properties
 	w.Title = ws.Title
 	w.CustomName = ws.CustomName
+	w.RuleName = ws.RuleName
+	w.Group = ws.Group
+	w.SwallowedBy = ws.SwallowedBy
 	w.X = ws.X
 	w.Y = ws.Y
 	w.Width = ws.Width
@@ -460,9 +596,23 @@ func (m *OS) updateWindowFromState(w *terminal.Window, ws *session.WindowState)
 	w.PreMinimizeY = ws.PreMinimizeY
 	w.PreMinimizeWidth = ws.PreMinimizeW
 	w.PreMinimizeHeight = ws.PreMinimizeH
+	restoreFloating(w, ws)
+	restorePiP(w, ws)
+	w.SetZoomed(ws.Zoomed)
 	w.IsAltScreen = ws.IsAltScreen
-
-	if sizeChanged {
+	w.ScrollbackOverride = ws.Scrollback
+	w.MinWidth, w.MinHeight = ws.MinWidth, ws.MinHeight
+	w.Command = ws.Command
+	w.Icon = ws.Icon
+	w.Tags = ws.Tags
+	w.ClipboardPolicy = ws.Clipboard
+	w.ApplyScrollbackLimit()
+
+	if sizeChanged && (w.HasPanes() || w.Zoomed) {
+		// Laying out the panes resizes each pane's PTY to its share, and
+		// a zoomed terminal gets half the content area
+		w.Resize(ws.Width, ws.Height)
+	} else if sizeChanged {
 		// Resize terminal emulator
 		if w.Terminal != nil {
 			termWidth := max(ws.Width-2, 1)
@@ -507,61 +657,117 @@ func (m *OS) createWindowFromSync(ws *session.WindowState) *terminal.Window {
 	}
 
 	window.CustomName = ws.CustomName
+	window.RuleName = ws.RuleName
+	window.Group = ws.Group
+	window.SwallowedBy = ws.SwallowedBy
 	window.Workspace = ws.Workspace
 	window.Minimized = ws.Minimized
 	window.PreMinimizeX = ws.PreMinimizeX
 	window.PreMinimizeY = ws.PreMinimizeY
 	window.PreMinimizeWidth = ws.PreMinimizeW
 	window.PreMinimizeHeight = ws.PreMinimizeH
+	restoreFloating(window, ws)
+	restorePiP(window, ws)
+	window.SetZoomed(ws.Zoomed)
 	window.IsAltScreen = ws.IsAltScreen
+	window.ScrollbackOverride = ws.Scrollback
+	window.MinWidth, window.MinHeight = ws.MinWidth, ws.MinHeight
+	window.Command = ws.Command
+	window.Icon = ws.Icon
+	window.Tags = ws.Tags
+	window.ClipboardPolicy = ws.Clipboard
+	window.ApplyScrollbackLimit()
 
 	m.setupKittyPassthrough(window)
 	m.setupSixelPassthrough(window)
+	m.restoreDaemonPanes(window, ws)
 
 	// Set up PTY handlers if we have a daemon client
 	if m.DaemonClient != nil {
-		ptyID := ws.PTYID
+		for _, t := range withPanes(window) {
+			m.connectDaemonTerminal(t)
 
-		window.DaemonWriteFunc = func(data []byte) error {
-			return m.DaemonClient.WritePTY(ptyID, data)
-		}
-
-		window.DaemonResizeFunc = func(width, height int) error {
-			return m.DaemonClient.ResizePTY(ptyID, width, height)
-		}
-
-		window.StartDaemonResponseReader()
-
-		// Only subscribe to PTY output if window is in current workspace
-		// Windows in other workspaces will be subscribed when switching to them
-		if ws.Workspace == m.CurrentWorkspace {
-			m.subscribeToPTY(window)
-
-			// Get terminal content for visible windows
-			termState, err := m.DaemonClient.GetTerminalState(ptyID, true)
-			if err == nil && termState != nil {
-				m.restoreTerminalContent(window, termState)
-			}
-		}
+			// Only subscribe to PTY output if window is in current workspace
+			// Windows in other workspaces will be subscribed when switching to them
+			if ws.Workspace == m.CurrentWorkspace {
+				m.subscribeToPTY(t)
 
-		// Register exit handler (always needed regardless of workspace)
-		windowID := window.ID
-		m.DaemonClient.OnPTYClosed(ptyID, func() {
-			if m.WindowExitChan != nil {
-				m.WindowExitChan <- windowID
+				// Get terminal content for visible windows
+				termState, err := m.DaemonClient.GetTerminalState(t.PTYID, true)
+				if err == nil && termState != nil {
+					m.restoreTerminalContent(t, termState)
+				}
 			}
-		})
 
-		window.EnableCallbacks()
+			t.EnableCallbacks()
+		}
 	}
 
 	return window
 }
 
+// windowState returns the serializable state of a window.
+func windowState(w *terminal.Window) session.WindowState {
+	ws := session.WindowState{
+		ID:           w.ID,
+		Title:        w.Title,
+		CustomName:   w.CustomName,
+		Group:        w.Group,
+		SwallowedBy:  w.SwallowedBy,
+		X:            w.X,
+		Y:            w.Y,
+		Width:        w.Width,
+		Height:       w.Height,
+		Z:            w.Z,
+		Workspace:    w.Workspace,
+		Minimized:    w.Minimized,
+		PreMinimizeX: w.PreMinimizeX,
+		PreMinimizeY: w.PreMinimizeY,
+		PreMinimizeW: w.PreMinimizeWidth,
+		PreMinimizeH: w.PreMinimizeHeight,
+		Floating:     w.Floating,
+		FloatX:       w.FloatX,
+		FloatY:       w.FloatY,
+		FloatW:       w.FloatWidth,
+		FloatH:       w.FloatHeight,
+		PiP:          w.PiP,
+		PrePiPX:      w.PrePiPX,
+		PrePiPY:      w.PrePiPY,
+		PrePiPW:      w.PrePiPWidth,
+		PrePiPH:      w.PrePiPHeight,
+		Zoomed:       w.Zoomed,
+		PTYID:        w.PTYID,
+		IsAltScreen:  w.IsAltScreen, // Save alt screen state for mouse forwarding on restore
+		Scrollback:   w.ScrollbackOverride,
+		MinWidth:     w.MinWidth,
+		MinHeight:    w.MinHeight,
+		Command:      w.Command,
+		Icon:         w.Icon,
+		Tags:         w.Tags,
+		RuleName:     w.RuleName,
+		Clipboard:    w.ClipboardPolicy,
+	}
+	if tree := w.PaneLayout(); tree != nil {
+		ws.PaneTree = convertBSPNode(tree.Root)
+		ws.ActivePane = w.Panes.Active
+		for _, pane := range w.Panes.Panes {
+			ws.Panes = append(ws.Panes, session.PaneState{
+				ID:       pane.ID,
+				WindowID: pane.Window.ID,
+				Title:    pane.Window.Title,
+				PTYID:    pane.Window.PTYID,
+			})
+		}
+	}
+	return ws
+}
+
 // closeWindowFromSync closes a window that was deleted by another client
 func (m *OS) closeWindowFromSync(w *terminal.Window) {
-	if m.DaemonClient != nil && w.PTYID != "" {
-		m.unsubscribeFromPTY(w)
+	if m.DaemonClient != nil {
+		for _, t := range withPanes(w) {
+			m.unsubscribeFromPTY(t)
+		}
 	}
 	w.Close()
 }
@@ -587,7 +793,7 @@ func (m *OS) RestoreTerminalStates() error {
 		return nil
 	}
 
-	for _, w := range m.Windows {
+	for _, w := range m.terminalsWithPanes() {
 		if w.DaemonMode && w.PTYID != "" {
 			state, err := m.DaemonClient.GetTerminalState(w.PTYID, true)
 			if err != nil {
@@ -615,6 +821,11 @@ func (m *OS) RestoreTerminalStates() error {
 // This fixes the issue where PTY dimensions become out of sync after detach/reattach.
 func (m *OS) SyncDaemonPTYDimensions() {
 	for _, w := range m.Windows {
+		if w.HasPanes() {
+			// Laying out the panes resizes each pane's PTY to its share
+			w.Resize(w.Width, w.Height)
+			continue
+		}
 		if w.DaemonMode && w.DaemonResizeFunc != nil {
 			termWidth := max(w.Width-2, 1)
 			termHeight := max(w.Height-2, 1)
@@ -675,6 +886,11 @@ func (m *OS) restoreTerminalContent(w *terminal.Window, state *session.TerminalS
 		m.LogInfo("Restored %d terminal modes for window %s", len(state.Modes), w.ID[:8])
 	}
 
+	// Restore the Kitty keyboard flags so editors keep receiving disambiguated keys
+	if state.KeyboardFlags != 0 {
+		w.Terminal.SetKittyKeyboardFlags(state.KeyboardFlags)
+	}
+
 	// Set the window's IsAltScreen flag for mouse event forwarding
 	w.IsAltScreen = state.IsAltScreen
 	m.LogInfo("Set window IsAltScreen=%v for window %s", state.IsAltScreen, w.ID[:8])
@@ -729,43 +945,53 @@ func (m *OS) SetupPTYOutputHandlers() error {
 
 	for i, w := range m.Windows {
 		m.LogInfo("[SETUP] Window %d: DaemonMode=%v, PTYID=%s, Workspace=%d", i, w.DaemonMode, w.PTYID, w.Workspace)
-		if w.DaemonMode && w.PTYID != "" {
-			// Capture window and ptyID for closures
-			window := w
-			ptyID := w.PTYID
-
-			// Set up the daemon write function for input
-			window.DaemonWriteFunc = func(data []byte) error {
-				return m.DaemonClient.WritePTY(ptyID, data)
-			}
-
-			// Set up the daemon resize function
-			window.DaemonResizeFunc = func(width, height int) error {
-				return m.DaemonClient.ResizePTY(ptyID, width, height)
+		for _, t := range withPanes(w) {
+			if !t.DaemonMode || t.PTYID == "" {
+				continue
 			}
-
-			// Start the response reader to handle DA queries and other terminal responses
-			window.StartDaemonResponseReader()
+			m.connectDaemonTerminal(t)
 
 			// Only subscribe to PTYs for windows in the current workspace
 			// Windows in other workspaces will be subscribed when switching to them
 			if w.Workspace == m.CurrentWorkspace {
-				m.subscribeToPTY(window)
+				m.subscribeToPTY(t)
 			}
-
-			// Register handler for when PTY process exits
-			windowID := window.ID
-			m.DaemonClient.OnPTYClosed(ptyID, func() {
-				if m.WindowExitChan != nil {
-					m.WindowExitChan <- windowID
-				}
-			})
 		}
 	}
 
 	return nil
 }
 
+// connectDaemonTerminal routes a window or pane terminal's input, resizes and
+// terminal responses to its daemon PTY, and reports the PTY's exit through
+// WindowExitChan.
+func (m *OS) connectDaemonTerminal(window *terminal.Window) {
+	ptyID := window.PTYID
+
+	// Set up the daemon write function for input
+	window.DaemonWriteFunc = func(data []byte) error {
+		return m.DaemonClient.WritePTY(ptyID, data)
+	}
+
+	// Set up the daemon resize function
+	window.DaemonResizeFunc = func(width, height int) error {
+		return m.DaemonClient.ResizePTY(ptyID, width, height)
+	}
+
+	// Start the response reader to handle DA queries and other terminal responses
+	window.StartDaemonResponseReader()
+
+	// Register handler for when PTY process exits (e.g., Ctrl+D). A pane is
+	// marked exited so its window closes just that pane.
+	windowID := window.ID
+	m.DaemonClient.OnPTYClosed(ptyID, func() {
+		window.ProcessExited = true
+		if m.WindowExitChan != nil {
+			m.WindowExitChan <- windowID
+		}
+	})
+}
+
 // subscribeToPTY subscribes to PTY output for a window.
 // Safe to call multiple times - will not double-subscribe.
 func (m *OS) subscribeToPTY(window *terminal.Window) {
@@ -786,6 +1012,9 @@ func (m *OS) subscribeToPTY(window *terminal.Window) {
 	}
 
 	m.LogInfo("[SUBSCRIBE] Subscribing to PTY %s for window %s", ptyID[:8], window.ID[:8])
+	// The daemon reports secure input only when it changes, starting off
+	window.SetSecureInput(false)
+	m.DaemonClient.OnPTYSecureInput(ptyID, window.SetSecureInput)
 	err := m.DaemonClient.SubscribePTY(ptyID, func(data []byte) {
 		// Pass through cursor style sequences directly to parent terminal
 		// since the VT emulator absorbs them
@@ -827,10 +1056,13 @@ func (m *OS) SubscribeWorkspaceWindows(workspace int) {
 
 	m.LogInfo("[WORKSPACE] Subscribing to windows in workspace %d", workspace)
 
-	for _, w := range m.Windows {
-		if w.DaemonMode && w.PTYID != "" && w.Workspace == workspace {
+	for _, window := range m.Windows {
+		if window.Workspace != workspace {
+			continue
+		}
+		for _, w := range withPanes(window) {
 			// Only subscribe if not already subscribed
-			if !m.SubscribedPTYs[w.PTYID] {
+			if w.DaemonMode && w.PTYID != "" && !m.SubscribedPTYs[w.PTYID] {
 				// Fetch terminal state first to populate the buffer
 				termState, err := m.DaemonClient.GetTerminalState(w.PTYID, true)
 				if err == nil && termState != nil {
@@ -853,9 +1085,14 @@ func (m *OS) UnsubscribeWorkspaceWindows(workspace int) {
 
 	m.LogInfo("[WORKSPACE] Unsubscribing from windows in workspace %d", workspace)
 
-	for _, w := range m.Windows {
-		if w.DaemonMode && w.PTYID != "" && w.Workspace == workspace {
-			m.unsubscribeFromPTY(w)
+	for _, window := range m.Windows {
+		if window.Workspace != workspace {
+			continue
+		}
+		for _, w := range withPanes(window) {
+			if w.DaemonMode && w.PTYID != "" {
+				m.unsubscribeFromPTY(w)
+			}
 		}
 	}
 }
@@ -863,12 +1100,21 @@ func (m *OS) UnsubscribeWorkspaceWindows(workspace int) {
 // AddDaemonWindow creates a new window using a daemon-managed PTY.
 // This is the daemon-mode equivalent of AddWindow.
 func (m *OS) AddDaemonWindow(title string) *OS {
+	return m.addDaemonWindow(title, nil, "")
+}
+
+// addDaemonWindow creates a daemon-mode window whose PTY runs argv in dir,
+// or the shell when argv is empty.
+func (m *OS) addDaemonWindow(title string, argv []string, dir string) *OS {
 	m.LogInfo("[DAEMON] AddDaemonWindow called, DaemonClient=%v", m.DaemonClient != nil)
 
 	if m.DaemonClient == nil {
 		m.LogError("Cannot add daemon window: not connected to daemon")
 		return m
 	}
+	if m.ReadOnly {
+		return m
+	}
 
 	newID := createID()
 	if title == "" {
@@ -891,25 +1137,9 @@ func (m *OS) AddDaemonWindow(title string) *OS {
 	height := screenHeight / 2
 
 	// Calculate position
-	var x, y int
-	if !m.AutoTiling && m.LastMouseX > 0 && m.LastMouseY > 0 {
-		x = m.LastMouseX
-		y = m.LastMouseY
-		if x+width > screenWidth {
-			x = screenWidth - width
-		}
-		if y+height > screenHeight {
-			y = screenHeight - height
-		}
-		if x < 0 {
-			x = 0
-		}
-		if y < 0 {
-			y = 0
-		}
-	} else {
-		x = screenWidth / 4
-		y = screenHeight / 4
+	x, y := screenWidth/4, screenHeight/4
+	if !m.AutoTiling {
+		x, y = m.newWindowPosition(nil, screenWidth, screenHeight, width, height)
 	}
 
 	// Calculate terminal dimensions (accounting for borders)
@@ -918,7 +1148,8 @@ func (m *OS) AddDaemonWindow(title string) *OS {
 
 	// Create PTY in daemon
 	m.LogInfo("[DAEMON] Calling CreatePTY(%s, %d, %d)", title, termWidth, termHeight)
-	ptyID, err := m.DaemonClient.CreatePTY(title, termWidth, termHeight)
+	env := append([]string{"TUIOS_WINDOW_ID=" + newID}, windowEnv(m.CurrentWorkspace)...)
+	ptyID, err := m.DaemonClient.CreateCommandPTY(title, termWidth, termHeight, argv, dir, env...)
 	if err != nil {
 		m.LogError("[DAEMON] Failed to create PTY in daemon: %v", err)
 		return m
@@ -941,33 +1172,12 @@ func (m *OS) AddDaemonWindow(title string) *OS {
 
 	m.setupKittyPassthrough(window)
 	m.setupSixelPassthrough(window)
-
-	// Set up the daemon write function for input
-	window.DaemonWriteFunc = func(data []byte) error {
-		return m.DaemonClient.WritePTY(ptyID, data)
-	}
-
-	// Set up the daemon resize function
-	window.DaemonResizeFunc = func(width, height int) error {
-		return m.DaemonClient.ResizePTY(ptyID, width, height)
-	}
-
-	// Start the response reader to handle DA queries and other terminal responses
-	window.StartDaemonResponseReader()
+	m.connectDaemonTerminal(window)
 
 	// Subscribe to PTY output (new windows are always in current workspace, so always subscribe)
 	// Use the helper function to track the subscription
 	m.subscribeToPTY(window)
 
-	// Register handler for when PTY process exits (e.g., Ctrl+D)
-	windowID := window.ID
-	m.DaemonClient.OnPTYClosed(ptyID, func() {
-		// Send window exit notification through the channel
-		if m.WindowExitChan != nil {
-			m.WindowExitChan <- windowID
-		}
-	})
-
 	m.Windows = append(m.Windows, window)
 	m.LogInfo("Daemon window created: %s (PTY: %s)", title, ptyID[:8])
 
@@ -998,24 +1208,15 @@ func (m *OS) DeleteDaemonWindow(i int) *OS {
 		return m
 	}
 
-	window := m.Windows[i]
-
-	// Close PTY in daemon if this is a daemon window
-	if window.DaemonMode && window.PTYID != "" && m.DaemonClient != nil {
-		m.DaemonClient.UnsubscribePTY(window.PTYID)
-		if err := m.DaemonClient.ClosePTY(window.PTYID); err != nil {
-			m.LogError("Failed to close PTY in daemon: %v", err)
-		}
-	}
-
-	// Use existing DeleteWindow logic for the rest
+	// Use existing DeleteWindow logic, which closes the daemon PTYs of the
+	// window and its panes
 	return m.DeleteWindow(i)
 }
 
 // SyncStateToDaemon sends the current state to the daemon.
 // This should be called after state-changing operations.
 func (m *OS) SyncStateToDaemon() {
-	if m.DaemonClient == nil || !m.IsDaemonSession {
+	if m.DaemonClient == nil || !m.IsDaemonSession || m.ReadOnly {
 		return
 	}
 
@@ -1036,7 +1237,7 @@ func (m *OS) SendInputToDaemon(window *terminal.Window, data []byte) error {
 
 // ResizeDaemonPTY resizes a daemon-managed PTY.
 func (m *OS) ResizeDaemonPTY(window *terminal.Window, width, height int) error {
-	if m.DaemonClient == nil || !window.DaemonMode {
+	if m.DaemonClient == nil || !window.DaemonMode || m.ReadOnly {
 		return nil
 	}
 
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// TestApplyRemoteStateSyncIndependentFocus tests that with independent focus a
+// client keeps its own focus and records the other client's focus instead
+func TestApplyRemoteStateSyncIndependentFocus(t *testing.T) {
+	defer func(v bool) { config.IndependentFocus = v }(config.IndependentFocus)
+	config.IndependentFocus = true
+
+	win1ID := "win-1234-5678-90ab-cdef-000000000001"
+	win2ID := "win-1234-5678-90ab-cdef-000000000002"
+
+	m := &OS{
+		NumWorkspaces:    9,
+		CurrentWorkspace: 1,
+		WorkspaceFocus:   map[int]int{1: 0},
+		Mode:             TerminalMode,
+		Windows: []*terminal.Window{
+			{ID: win1ID, Width: 80, Height: 24, Workspace: 1},
+			{ID: win2ID, Width: 80, Height: 24, Workspace: 1},
+		},
+		FocusedWindow: 0,
+	}
+
+	state := &session.SessionState{
+		CurrentWorkspace: 2,
+		FocusedWindowID:  win2ID,
+		Mode:             int(WindowManagementMode),
+		WorkspaceFocus:   map[int]string{1: win1ID},
+		Windows: []session.WindowState{
+			// Order changed by the other client: indices shift
+			{ID: win2ID, Width: 80, Height: 24, Workspace: 1},
+			{ID: win1ID, Width: 80, Height: 24, Workspace: 1},
+		},
+	}
+
+	if err := m.ApplyRemoteStateSync(state, "client-b"); err != nil {
+		t.Fatalf("ApplyRemoteStateSync failed: %v", err)
+	}
+
+	if w := m.GetFocusedWindow(); w == nil || w.ID != win1ID {
+		t.Errorf("local focus moved to %v, want %s", w, win1ID)
+	}
+	if m.CurrentWorkspace != 1 || m.Mode != TerminalMode {
+		t.Errorf("workspace/mode = %d/%d, want 1/%d", m.CurrentWorkspace, m.Mode, TerminalMode)
+	}
+	if !m.IsRemotelyFocused(win2ID) || m.IsRemotelyFocused(win1ID) {
+		t.Errorf("RemoteFocus = %v, want client-b on %s", m.RemoteFocus, win2ID)
+	}
+
+	m.ForgetRemoteFocus("client-b")
+	if m.IsRemotelyFocused(win2ID) {
+		t.Error("remote focus should be cleared when the client leaves")
+	}
+}
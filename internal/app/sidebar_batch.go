@@ -0,0 +1,158 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// sidebarMarkIcon returns the marker shown before windows marked in the
+// sidebar.
+func sidebarMarkIcon() string {
+	if config.UseASCIIOnly {
+		return "+"
+	}
+	return "✓"
+}
+
+// SidebarToggleMark marks the selected sidebar window for a batch action, or
+// unmarks it, and moves the selection to the next window.
+func (m *OS) SidebarToggleMark() {
+	if m.SidebarPicking() || m.SidebarSelectedIndex < 0 || m.SidebarSelectedIndex >= len(m.Windows) {
+		return
+	}
+	id := m.Windows[m.SidebarSelectedIndex].ID
+	if m.SidebarMarked[id] {
+		delete(m.SidebarMarked, id)
+	} else {
+		if m.SidebarMarked == nil {
+			m.SidebarMarked = make(map[string]bool)
+		}
+		m.SidebarMarked[id] = true
+	}
+	m.SidebarSelectNext()
+}
+
+// SidebarMarkedWindows returns the windows marked in the sidebar, in window
+// order.
+func (m *OS) SidebarMarkedWindows() []*terminal.Window {
+	var marked []*terminal.Window
+	for _, w := range m.Windows {
+		if m.SidebarMarked[w.ID] {
+			marked = append(marked, w)
+		}
+	}
+	return marked
+}
+
+// ClearSidebarMarks unmarks every window and reports whether any was marked.
+func (m *OS) ClearSidebarMarks() bool {
+	had := len(m.SidebarMarkedWindows()) > 0
+	m.SidebarMarked = nil
+	return had
+}
+
+// sidebarBatch returns the windows a sidebar batch action applies to: the
+// marked windows, or the selected window when none is marked.
+func (m *OS) sidebarBatch() []*terminal.Window {
+	if marked := m.SidebarMarkedWindows(); len(marked) > 0 {
+		return marked
+	}
+	if m.SidebarSelectedIndex >= 0 && m.SidebarSelectedIndex < len(m.Windows) {
+		return []*terminal.Window{m.Windows[m.SidebarSelectedIndex]}
+	}
+	return nil
+}
+
+// finishSidebarBatch clears the marks after a batch action and keeps the
+// sidebar selection on an existing window.
+func (m *OS) finishSidebarBatch() {
+	m.SidebarMarked = nil
+	if len(m.Windows) == 0 {
+		m.CloseSidebar()
+		m.Mode = WindowManagementMode
+		return
+	}
+	m.SidebarSelectedIndex = min(max(m.SidebarSelectedIndex, 0), len(m.Windows)-1)
+	m.MarkAllDirty()
+	m.SyncStateToDaemon()
+}
+
+// SidebarCloseMarked closes the marked windows, or the selected one. While
+// programs run in any of them it asks for confirmation first.
+func (m *OS) SidebarCloseMarked() {
+	batch := m.sidebarBatch()
+	if len(batch) == 0 {
+		return
+	}
+	if running := m.RunningPrograms(batch); len(running) > 0 {
+		m.requestCloseWindows(batch, running, fmt.Sprintf("Close %d windows?", len(batch)))
+		m.closeAllFromSidebar = true
+		return
+	}
+	m.closeWindows(batch)
+	m.finishSidebarBatch()
+	m.ShowNotification(fmt.Sprintf("Closed %d windows", len(batch)), "info", config.NotificationDuration)
+}
+
+// SidebarMinimizeMarked minimizes the marked windows, or the selected one,
+// together with the other windows of their groups.
+func (m *OS) SidebarMinimizeMarked() {
+	batch := m.sidebarBatch()
+	if len(batch) == 0 {
+		return
+	}
+	for _, window := range batch {
+		if i := slices.Index(m.Windows, window); i >= 0 {
+			m.MinimizeWindow(i)
+		}
+	}
+	m.finishSidebarBatch()
+	m.ShowNotification(fmt.Sprintf("Minimized %d windows", len(batch)), "info", config.NotificationDuration)
+}
+
+// SidebarMoveMarked moves the marked windows, or the selected one, together
+// with the other windows of their groups to the specified workspace.
+func (m *OS) SidebarMoveMarked(workspace int) {
+	batch := m.sidebarBatch()
+	if len(batch) == 0 || workspace < 1 || workspace > m.NumWorkspaces {
+		return
+	}
+	for _, window := range batch {
+		if i := slices.Index(m.Windows, window); i >= 0 {
+			m.MoveWindowToWorkspace(i, workspace)
+		}
+	}
+	m.finishSidebarBatch()
+	m.ShowNotification(fmt.Sprintf("Moved %d windows to workspace %d", len(batch), workspace), "info", config.NotificationDuration)
+}
+
+// SidebarGroupMarked puts the marked windows into one group, joining the
+// group of the first marked window that has one. Windows leaving another
+// group dissolve it if a single window is left there.
+func (m *OS) SidebarGroupMarked() {
+	marked := m.SidebarMarkedWindows()
+	if len(marked) < 2 {
+		m.ShowNotification("Mark at least two windows to group", "info", config.NotificationDuration)
+		return
+	}
+	group := ""
+	for _, w := range marked {
+		if w.Group != "" {
+			group = w.Group
+			break
+		}
+	}
+	if group == "" {
+		group = createID()
+	}
+	for _, w := range marked {
+		old := w.Group
+		w.Group = group
+		m.dissolveLoneGroup(old)
+	}
+	m.finishSidebarBatch()
+	m.ShowNotification(fmt.Sprintf("Grouped %d windows", len(marked)), "success", config.NotificationDuration)
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestSidebarBatchActions(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-three-00", Width: 40, Height: 20, Workspace: 1},
+	}
+	m.FocusedWindow = 0
+	m.SidebarVisible, m.SidebarFocused = true, true
+	m.SidebarSelectedIndex = 0
+
+	m.SidebarToggleMark()
+	m.SidebarSelectNext()
+	m.SidebarToggleMark()
+	if len(m.SidebarMarkedWindows()) != 2 || !m.SidebarMarked["window-one-0000"] || !m.SidebarMarked["window-three-00"] {
+		t.Fatalf("expected windows one and three marked, got %v", m.SidebarMarked)
+	}
+
+	m.SidebarGroupMarked()
+	if m.Windows[0].Group == "" || m.Windows[0].Group != m.Windows[2].Group || m.Windows[1].Group != "" {
+		t.Errorf("expected windows one and three grouped, got %q, %q and %q", m.Windows[0].Group, m.Windows[1].Group, m.Windows[2].Group)
+	}
+	if len(m.SidebarMarked) != 0 {
+		t.Errorf("expected the marks cleared after a batch action")
+	}
+
+	m.SidebarSelectedIndex = 1
+	m.SidebarToggleMark()
+	m.SidebarMoveMarked(3)
+	if m.Windows[1].Workspace != 3 || m.Windows[0].Workspace != 1 {
+		t.Errorf("expected only window two moved to workspace 3")
+	}
+
+	// Without marks the selected window is acted on, leaving its group
+	m.SidebarSelectedIndex = 2
+	m.SidebarCloseMarked()
+	if m.ShowCloseAllConfirm {
+		t.Fatal("expected windows running only their shell to close without asking")
+	}
+	if len(m.Windows) != 2 || m.Windows[0].ID != "window-one-0000" || m.Windows[1].ID != "window-two-0000" {
+		t.Fatalf("expected only window three closed, got %d windows", len(m.Windows))
+	}
+	if m.SidebarSelectedIndex != 1 {
+		t.Errorf("expected the selection kept on an existing window, got %d", m.SidebarSelectedIndex)
+	}
+}
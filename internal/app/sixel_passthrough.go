@@ -4,22 +4,14 @@ import (
 	"fmt"
 	"os"
 	"sync"
-	"time"
 
+	"github.com/Gaurav-Gosain/tuios/internal/debuglog"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 	"github.com/Gaurav-Gosain/tuios/internal/vt"
 )
 
 func sixelPassthroughLog(format string, args ...any) {
-	if os.Getenv("TUIOS_DEBUG_INTERNAL") != "1" {
-		return
-	}
-	f, err := os.OpenFile("/tmp/tuios-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return
-	}
-	defer func() { _ = f.Close() }()
-	_, _ = fmt.Fprintf(f, "[%s] SIXEL-PASSTHROUGH: %s\n", time.Now().Format("15:04:05.000"), fmt.Sprintf(format, args...))
+	debuglog.Logf(debuglog.Debug, "SIXEL-PASSTHROUGH", format, args...)
 }
 
 // SixelPassthrough handles forwarding sixel graphics to the host terminal.
@@ -57,10 +49,9 @@ type SixelPassthroughPlacement struct {
 	// Visibility state
 	Hidden bool
 
-	// Track if currently placed and at what position (to avoid re-rendering every frame)
-	PlacedAtX int
-	PlacedAtY int
-	IsPlaced  bool
+	// Track if currently placed and how it was cut (to avoid re-rendering every frame)
+	IsPlaced bool
+	placed   clippedPlacement
 
 	// Clipping state
 	ClipTop    int
@@ -68,8 +59,10 @@ type SixelPassthroughPlacement struct {
 	ClipLeft   int
 	ClipRight  int
 
-	// The raw sixel data for re-rendering
+	// The raw sixel data for re-rendering, and its decoded raster once the
+	// image has had to be scaled or cropped
 	RawSequence []byte
+	raster      *vt.SixelImage
 
 	// Track which screen the image was placed on
 	PlacedOnAltScreen bool
@@ -173,7 +166,7 @@ func (sp *SixelPassthrough) ClearAltScreenPlacements(windowID string) {
 
 // RefreshAllPlacements updates visibility and positions for all placements.
 // This is called during each render cycle.
-func (sp *SixelPassthrough) RefreshAllPlacements(getWindowInfo func(windowID string) *WindowPositionInfo) {
+func (sp *SixelPassthrough) RefreshAllPlacements(getAllWindows func() map[string]*WindowPositionInfo) {
 	sp.mu.Lock()
 	defer sp.mu.Unlock()
 
@@ -193,20 +186,12 @@ func (sp *SixelPassthrough) RefreshAllPlacements(getWindowInfo func(windowID str
 		cellHeight = 20
 	}
 
-	hostHeight := caps.Rows
-	hostWidth := caps.Cols
+	allWindows := getAllWindows()
 
 	for windowID, placements := range sp.placements {
-		info := getWindowInfo(windowID)
-		if info == nil {
-			for _, p := range placements {
-				if !p.Hidden {
-					sp.hidePlacement(p)
-				}
-			}
-			continue
-		}
-		if !info.Visible {
+		info := allWindows[windowID]
+		if info == nil || !info.Visible || info.IsBeingManipulated {
+			// During window manipulation (drag/resize), hide all images
 			for _, p := range placements {
 				if !p.Hidden {
 					sp.hidePlacement(p)
@@ -219,23 +204,7 @@ func (sp *SixelPassthrough) RefreshAllPlacements(getWindowInfo func(windowID str
 			windowID[:min(8, len(windowID))], info.WindowX, info.WindowY, info.Width, info.Height,
 			info.ScrollbackLen, info.ScrollOffset)
 
-		// During window manipulation (drag/resize), hide all images
-		if info.IsBeingManipulated {
-			for _, p := range placements {
-				if !p.Hidden {
-					sp.hidePlacement(p)
-				}
-			}
-			continue
-		}
-
-		// Calculate viewport boundaries
-		// When scrollbackLen < height, content starts at top of window (viewportTop = 0)
-		viewportTop := 0
-		if info.ScrollbackLen > info.Height {
-			viewportTop = info.ScrollbackLen - info.ScrollOffset - info.Height
-		}
-		viewportBottom := info.ScrollbackLen - info.ScrollOffset
+		occluders := higherWindowRects(info.WindowZ, allWindows, windowID)
 
 		for _, p := range placements {
 			// Check if placement matches current screen mode
@@ -246,77 +215,31 @@ func (sp *SixelPassthrough) RefreshAllPlacements(getWindowInfo func(windowID str
 				continue
 			}
 
-			// Calculate visibility
-			placementBottom := p.AbsoluteLine + p.Rows
-
-			// Check if any part is visible
-			anyPartVisible := placementBottom > viewportTop && p.AbsoluteLine < viewportBottom
+			// The image covers as many cells as its pixels need at the
+			// current cell size, which changes with the host font.
+			p.Cols = (p.Width + cellWidth - 1) / cellWidth
+			p.Rows = (p.Height + cellHeight - 1) / cellHeight
 
-			if !anyPartVisible {
+			clip, visible := clipImage(p.GuestX, p.AbsoluteLine, p.Cols, p.Rows, info, occluders)
+			if !visible {
 				if !p.Hidden {
 					sp.hidePlacement(p)
 				}
 				continue
 			}
 
-			// Calculate clipping
-			clipTop := 0
-			clipBottom := 0
-
-			if p.AbsoluteLine < viewportTop {
-				clipTop = viewportTop - p.AbsoluteLine
-			}
-			if placementBottom > viewportBottom {
-				clipBottom = placementBottom - viewportBottom
-			}
-
-			visibleRows := p.Rows - clipTop - clipBottom
-			if visibleRows <= 0 {
-				if !p.Hidden {
-					sp.hidePlacement(p)
-				}
-				continue
-			}
-
-			// Calculate host position
-			relativeY := max(0, p.AbsoluteLine-viewportTop)
-
-			hostX := info.WindowX + info.ContentOffsetX + p.GuestX
-			hostY := info.WindowY + info.ContentOffsetY + relativeY
-
-			sixelPassthroughLog("Placement: absLine=%d viewportTop=%d relativeY=%d guestX=%d -> hostPos=(%d,%d)",
-				p.AbsoluteLine, viewportTop, relativeY, p.GuestX, hostX, hostY)
-
-			// Check horizontal bounds
-			if hostX < 0 || hostX >= hostWidth {
-				if !p.Hidden {
-					sp.hidePlacement(p)
-				}
-				continue
-			}
-
-			// Check if we're within host terminal bounds
-			if hostY < 0 || hostY >= hostHeight {
-				if !p.Hidden {
-					sp.hidePlacement(p)
-				}
-				continue
-			}
-
-			// Check if position changed - only re-render if needed
-			positionChanged := !p.IsPlaced || p.PlacedAtX != hostX || p.PlacedAtY != hostY
-
-			// Update placement state
-			p.HostX = hostX
-			p.HostY = hostY
-			p.ClipTop = clipTop
-			p.ClipBottom = clipBottom
-
-			// Only place the sixel image if position changed or not yet placed
-			if positionChanged {
-				sp.placeSixel(p, cellWidth, cellHeight)
-				p.PlacedAtX = hostX
-				p.PlacedAtY = hostY
+			sixelPassthroughLog("Placement: absLine=%d guestX=%d -> clip=%+v", p.AbsoluteLine, p.GuestX, clip)
+
+			// Only re-render if the image moved or is cut differently
+			if !p.IsPlaced || p.placed != clip {
+				p.HostX = clip.Visible.X
+				p.HostY = clip.Visible.Y
+				p.ClipTop = clip.ClipTop
+				p.ClipBottom = clip.Rows - clip.ClipTop - clip.Visible.H
+				p.ClipLeft = clip.ClipLeft
+				p.ClipRight = clip.Cols - clip.ClipLeft - clip.Visible.W
+				sp.placeSixel(p, sixelForClip(p, clip, cellWidth, cellHeight))
+				p.placed = clip
 				p.IsPlaced = true
 			}
 			p.Hidden = false
@@ -324,6 +247,25 @@ func (sp *SixelPassthrough) RefreshAllPlacements(getWindowInfo func(windowID str
 	}
 }
 
+// sixelForClip returns the DCS body that draws the clipped part of a
+// placement. Sixel data can only be drawn whole, so when the image is scaled
+// or cut, it is decoded, scaled to the cells it covers and cropped to the
+// visible cells.
+func sixelForClip(p *SixelPassthroughPlacement, clip clippedPlacement, cellWidth, cellHeight int) []byte {
+	if clip.Scale == 1 && clip.ClipTop == 0 && clip.ClipLeft == 0 &&
+		clip.Visible.W == clip.Cols && clip.Visible.H == clip.Rows {
+		return p.RawSequence
+	}
+	if p.raster == nil {
+		p.raster = vt.DecodeSixel(vt.ParseSixelCommand(p.RawSequence))
+		if p.raster == nil {
+			return nil
+		}
+	}
+	scaled := p.raster.Scale(int(float64(p.raster.Width)*clip.Scale+0.5), int(float64(p.raster.Height)*clip.Scale+0.5))
+	return scaled.Crop(clip.ClipLeft*cellWidth, clip.ClipTop*cellHeight, clip.Visible.W*cellWidth, clip.Visible.H*cellHeight).Encode()
+}
+
 // hidePlacement hides a sixel placement.
 // Since sixels don't have delete commands like Kitty, we rely on
 // the terminal to naturally overwrite the area or redraw.
@@ -334,10 +276,10 @@ func (sp *SixelPassthrough) hidePlacement(p *SixelPassthroughPlacement) {
 	// naturally cleared when the terminal redraws
 }
 
-// placeSixel writes a sixel image to the host terminal at the specified position.
-// cellWidth and cellHeight are provided for potential future clipping support.
-func (sp *SixelPassthrough) placeSixel(p *SixelPassthroughPlacement, _, _ int) {
-	if len(p.RawSequence) == 0 {
+// placeSixel writes the DCS body data to the host terminal at the
+// placement's host position.
+func (sp *SixelPassthrough) placeSixel(p *SixelPassthroughPlacement, data []byte) {
+	if len(data) == 0 {
 		return
 	}
 
@@ -353,7 +295,7 @@ func (sp *SixelPassthrough) placeSixel(p *SixelPassthroughPlacement, _, _ int) {
 	// Write the DCS sixel sequence
 	// Format: ESC P <params> q <data> ESC \
 	buf = append(buf, "\x1bP"...)
-	buf = append(buf, p.RawSequence...)
+	buf = append(buf, data...)
 	buf = append(buf, "\x1b\\"...)
 
 	// Restore cursor position
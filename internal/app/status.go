@@ -0,0 +1,56 @@
+package app
+
+import (
+	"slices"
+)
+
+// GetStatusData returns the one-line summary of the session printed by
+// tuios status: the workspace, the focused window, window counts and the
+// urgent windows.
+func (m *OS) GetStatusData() map[string]any {
+	mode := "window_management"
+	if m.Mode == TerminalMode {
+		mode = "terminal"
+	}
+
+	workspaceWindows, minimized := 0, 0
+	for _, w := range m.Windows {
+		if w.Minimized {
+			minimized++
+		}
+		if w.Workspace == m.CurrentWorkspace {
+			workspaceWindows++
+		}
+	}
+
+	urgent := m.UrgentWindows()
+	urgentWorkspaces := []int{}
+	for _, i := range urgent {
+		urgentWorkspaces = append(urgentWorkspaces, m.Windows[i].Workspace)
+	}
+	slices.Sort(urgentWorkspaces)
+
+	status := map[string]any{
+		"session":           m.SessionName,
+		"workspace":         m.CurrentWorkspace,
+		"workspace_name":    m.WorkspaceNames[m.CurrentWorkspace],
+		"mode":              mode,
+		"tiling":            m.AutoTiling,
+		"windows":           len(m.Windows),
+		"workspace_windows": workspaceWindows,
+		"minimized":         minimized,
+		"urgent":            len(urgent),
+		"urgent_workspaces": slices.Compact(urgentWorkspaces),
+		"focused_id":        "",
+		"focused_name":      "",
+		"focused_tags":      []string{},
+	}
+	if w := m.GetFocusedWindow(); w != nil {
+		status["focused_id"] = w.ID
+		status["focused_name"] = m.getWindowDisplayName(w)
+		if w.Tags != nil {
+			status["focused_tags"] = w.Tags
+		}
+	}
+	return status
+}
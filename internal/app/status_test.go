@@ -0,0 +1,39 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestGetStatusData(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.CurrentWorkspace = 2
+	m.SessionName = "work"
+	m.WorkspaceNames = map[int]string{2: "logs"}
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", CustomName: "vim", Workspace: 2, Tags: []string{"dev"}},
+		{ID: "window-two-0000", Title: "htop", Workspace: 2, Minimized: true},
+		{ID: "window-three-00", Title: "build", Workspace: 4, UrgentSince: time.Now()},
+	}
+	m.FocusedWindow = 0
+
+	status := m.GetStatusData()
+	want := map[string]any{
+		"session": "work", "workspace": 2, "workspace_name": "logs",
+		"windows": 3, "workspace_windows": 2, "minimized": 1, "urgent": 1,
+		"focused_id": "window-one-0000", "focused_name": "vim",
+	}
+	for key, value := range want {
+		if status[key] != value {
+			t.Errorf("status[%q] = %v, want %v", key, status[key], value)
+		}
+	}
+	if ws, _ := status["urgent_workspaces"].([]int); len(ws) != 1 || ws[0] != 4 {
+		t.Errorf("urgent_workspaces = %v, want [4]", status["urgent_workspaces"])
+	}
+	if tags, _ := status["focused_tags"].([]string); len(tags) != 1 || tags[0] != "dev" {
+		t.Errorf("focused_tags = %v, want [dev]", status["focused_tags"])
+	}
+}
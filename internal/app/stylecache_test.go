@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
 	uv "github.com/charmbracelet/ultraviolet"
 )
 
@@ -336,3 +339,55 @@ func BenchmarkStyleNoCacheBaseline(b *testing.B) {
 		buildCellStyle(cell, false)
 	}
 }
+
+func TestRowHighlights(t *testing.T) {
+	config.SetHighlightRules([]config.HighlightRule{
+		{Pattern: `ERROR`, Color: "red", Bold: true},
+		{Pattern: `\d+\.\d+\.\d+\.\d+`, Color: "#00ffff"},
+		{Pattern: `R+`, Background: "1"},
+		{Pattern: `x*`, Color: "red"}, // Matches empty text, ignored
+		{Pattern: `at`, Color: "blue", Window: "logs*"},
+	})
+	defer config.SetHighlightRules(nil)
+
+	w := &terminal.Window{Title: "shell"}
+	styles := windowHighlights(w)
+	if len(styles) != 3 {
+		t.Fatalf("expected the three valid global rules, got %d", len(styles))
+	}
+
+	text := []rune("ERROR at 10.0.0.1 日")
+	cells := make([]uv.Cell, 0, len(text)+1)
+	for _, r := range text {
+		width := 1
+		if r == '日' {
+			width = 2
+		}
+		cells = append(cells, uv.Cell{Content: string(r), Width: width})
+		if width == 2 {
+			cells = append(cells, uv.Cell{})
+		}
+	}
+	painted := rowHighlights(styles, len(cells), func(x int) *uv.Cell { return &cells[x] })
+	for x := range cells {
+		var want *highlightStyle
+		switch {
+		case x < 5:
+			want = &styles[0] // The first matching rule wins over R+
+		case x >= 9 && x < 17:
+			want = &styles[1]
+		}
+		if painted[x] != want {
+			t.Errorf("column %d: got %v, want %v", x, painted[x], want)
+		}
+	}
+
+	cell := styles[0].paint(&cells[0])
+	if cell.Style.Fg != theme.GetANSIPalette()[1] || cell.Style.Attrs&uv.AttrBold == 0 || cells[0].Style.Fg != nil {
+		t.Errorf("expected a red bold copy of the cell, got %+v", cell.Style)
+	}
+
+	if styles := windowHighlights(&terminal.Window{Title: "logs: api"}); len(styles) != 4 {
+		t.Errorf("expected the window rule to apply to matching windows, got %d rules", len(styles))
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// swallowParentWindow hides the window the window with ID childID was opened
+// from while the child is open: the window running the PTY sourcePTYID, or
+// else the one whose shell started process callerPID. The child takes the
+// parent's place and focus. It does nothing unless config.SwallowWindows is
+// set.
+func (m *OS) swallowParentWindow(sourcePTYID string, callerPID int, childID string) {
+	if !config.SwallowWindows || (sourcePTYID == "" && callerPID <= 0) {
+		return
+	}
+	parentIndex, childIndex := m.sourceWindowIndex(sourcePTYID, callerPID, childID), -1
+	for i, w := range m.Windows {
+		if w.ID == childID {
+			childIndex = i
+		}
+	}
+	if parentIndex < 0 || childIndex < 0 || m.Windows[parentIndex].Minimized {
+		return
+	}
+	parent, child := m.Windows[parentIndex], m.Windows[childIndex]
+
+	child.Workspace = parent.Workspace
+	if !m.AutoTiling {
+		child.X, child.Y = parent.X, parent.Y
+		child.Resize(parent.Width, parent.Height)
+		child.MarkPositionDirty()
+	}
+
+	m.minimizeWindow(parentIndex)
+	parent.SwallowedBy = child.ID
+	m.FocusWindow(childIndex)
+	m.LogInfo("Window %s swallowed by window %s", parent.ID[:8], child.ID[:8])
+}
+
+// sourceWindowIndex returns the index of the window, other than the one with
+// ID skip, that a process runs in: the window one of whose terminals runs the
+// PTY ptyID, or else the one one of whose shells is pid or an ancestor of it
+// in the process tree. It returns -1 when there is none.
+func (m *OS) sourceWindowIndex(ptyID string, pid int, skip string) int {
+	shells := make(map[int32]int) // Shell process ID -> window index
+	for i, w := range m.Windows {
+		if w.ID == skip {
+			continue
+		}
+		for _, t := range withPanes(w) {
+			if ptyID != "" && t.PTYID == ptyID {
+				return i
+			}
+			if pid <= 0 {
+				continue
+			}
+			if shell := m.shellPID(t); shell > 0 {
+				shells[int32(shell)] = i
+			}
+		}
+	}
+	if len(shells) == 0 {
+		return -1
+	}
+
+	// Walk up the process tree until a shell of a window is found
+	for current := int32(pid); current > 1; {
+		if i, ok := shells[current]; ok {
+			return i
+		}
+		proc, err := process.NewProcess(current)
+		if err != nil {
+			return -1
+		}
+		parent, err := proc.Ppid()
+		if err != nil || parent == current {
+			return -1
+		}
+		current = parent
+	}
+	return -1
+}
+
+// releaseSwallowed clears the windows swallowed by window, which is being
+// closed, and returns them. They are restored where window was.
+func (m *OS) releaseSwallowed(window *terminal.Window) []*terminal.Window {
+	var released []*terminal.Window
+	for _, w := range m.Windows {
+		if w.SwallowedBy != window.ID {
+			continue
+		}
+		w.SwallowedBy = ""
+		m.invalidateDockHeight()
+		w.PreMinimizeX, w.PreMinimizeY = window.X, window.Y
+		w.PreMinimizeWidth, w.PreMinimizeHeight = window.Width, window.Height
+		released = append(released, w)
+	}
+	return released
+}
+
+// restoreSwallowed restores windows returned by releaseSwallowed.
+func (m *OS) restoreSwallowed(windows []*terminal.Window) {
+	for _, window := range windows {
+		for i, w := range m.Windows {
+			if w == window {
+				m.restoreWindow(i)
+				break
+			}
+		}
+	}
+	if len(windows) > 0 && m.AutoTiling {
+		m.TileAllWindows()
+	}
+}
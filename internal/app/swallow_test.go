@@ -0,0 +1,77 @@
+package app
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/layout"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestSwallowParentWindow(t *testing.T) {
+	config.SwallowWindows = true
+	t.Cleanup(func() { config.SwallowWindows = false })
+
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "parent-window-0", PTYID: "pty-1", X: 5, Y: 3, Width: 50, Height: 20, Workspace: 1},
+		{ID: "other-window-00", PTYID: "pty-2", Width: 40, Height: 20, Workspace: 1},
+		{ID: "child-window-00", PTYID: "pty-3", Width: 40, Height: 20, Workspace: 1},
+	}
+	m.FocusedWindow = 1
+
+	m.swallowParentWindow("pty-1", 0, "child-window-00")
+	parent := m.Windows[0]
+	if !parent.Minimized || parent.SwallowedBy != "child-window-00" {
+		t.Fatalf("expected the parent to be swallowed, got minimized=%t swallowed by %q", parent.Minimized, parent.SwallowedBy)
+	}
+	if m.FocusedWindow != 2 {
+		t.Errorf("expected the child to be focused, got %d", m.FocusedWindow)
+	}
+	if m.HasMinimizedWindows() {
+		t.Error("expected a swallowed window to stay out of the dock")
+	}
+
+	m.DeleteWindow(2)
+	if parent.Minimized || parent.SwallowedBy != "" {
+		t.Errorf("expected the parent to come back when the child closes")
+	}
+	if parent.PreMinimizeX != 5 || parent.PreMinimizeY != 3 {
+		t.Errorf("expected the parent to return to the child's place, got %d,%d", parent.PreMinimizeX, parent.PreMinimizeY)
+	}
+}
+
+// TestSwallowFromProcessTree tests that without a source PTY the parent is
+// the window whose shell is an ancestor of the calling process
+func TestSwallowFromProcessTree(t *testing.T) {
+	config.SwallowWindows = true
+	t.Cleanup(func() { config.SwallowWindows = false })
+
+	// The test's parent process stands in for the shell of a pane
+	shell, err := os.FindProcess(os.Getppid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := &terminal.Window{ID: "parent-window-0", Width: 50, Height: 20, Workspace: 1}
+	host.SplitPane(&terminal.Window{ID: "parent-pane-000", Cmd: &exec.Cmd{Process: shell}}, layout.SplitVertical)
+
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "other-window-00", Width: 40, Height: 20, Workspace: 1},
+		host,
+		{ID: "child-window-00", Width: 40, Height: 20, Workspace: 1},
+	}
+
+	if got := m.sourceWindowIndex("", os.Getpid(), "child-window-00"); got != 1 {
+		t.Fatalf("sourceWindowIndex = %d, want the split window", got)
+	}
+	m.swallowParentWindow("", os.Getpid(), "child-window-00")
+	if !host.Minimized || host.SwallowedBy != "child-window-00" {
+		t.Errorf("expected the split window to be swallowed, got minimized=%t swallowed by %q", host.Minimized, host.SwallowedBy)
+	}
+	if m.Windows[0].Minimized {
+		t.Error("expected the other window to stay")
+	}
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// NewTabInFocusedWindow starts a new shell in a new tab of the focused
+// window, placed after the active tab.
+func (m *OS) NewTabInFocusedWindow() {
+	window := m.GetFocusedWindow()
+	if window == nil {
+		return
+	}
+	if window.DaemonMode {
+		m.ShowNotification("Tabs are not available in daemon sessions", "warning", config.NotificationDuration)
+		return
+	}
+	if window.HasPanes() {
+		m.ShowNotification("Windows with panes cannot have tabs", "warning", config.NotificationDuration)
+		return
+	}
+
+	id := createID()
+	tab := terminal.NewWindowInDir(id, fmt.Sprintf("Terminal %s", id[:8]), 0, 0, window.Width, window.Height-1, 0, m.WindowExitChan, window.ActiveTab().WorkingDirectory(), windowEnv(window.Workspace)...)
+	if tab == nil {
+		m.LogError("Failed to create tab in window %s (PTY creation failed)", window.ID[:8])
+		return
+	}
+
+	caps := GetHostCapabilities()
+	if caps.CellWidth > 0 && caps.CellHeight > 0 {
+		tab.SetCellPixelDimensions(caps.CellWidth, caps.CellHeight)
+	}
+	tab.Workspace = window.Workspace
+
+	window.AddTab(tab)
+	m.LogInfo("Added tab %d to window %s", len(window.Tabs), window.ID[:8])
+}
+
+// CycleFocusedTab activates the next (delta > 0) or previous (delta < 0) tab
+// of the focused window.
+func (m *OS) CycleFocusedTab(delta int) {
+	if window := m.GetFocusedWindow(); window != nil {
+		window.CycleTab(delta)
+	}
+}
+
+// SelectTabAt activates the tab whose label covers column x of the tab strip
+// of the window at index. It reports whether x was on a tab's label.
+func (m *OS) SelectTabAt(index, x int) bool {
+	if index < 0 || index >= len(m.Windows) || !m.Windows[index].HasTabs() {
+		return false
+	}
+	tab := tabAt(m.Windows[index], x)
+	if tab < 0 {
+		return false
+	}
+	m.Windows[index].SelectTab(tab)
+	return true
+}
+
+// tabLabels returns the label shown for each of the window's tabs in its tab
+// strip.
+func tabLabels(window *terminal.Window) []string {
+	labels := make([]string, len(window.Tabs))
+	for i, tab := range window.Tabs {
+		name := tab.CustomName
+		if name == "" {
+			name = tab.Title
+		}
+		if runes := []rune(name); len(runes) > config.TabLabelMaxWidth {
+			name = string(runes[:config.TabLabelMaxWidth-1]) + "…"
+		}
+		labels[i] = fmt.Sprintf(" %d %s ", i+1, name)
+	}
+	return labels
+}
+
+// tabAt returns the index of the tab whose label covers column x of the
+// window's tab strip, or -1.
+func tabAt(window *terminal.Window, x int) int {
+	start := 0
+	for i, label := range tabLabels(window) {
+		end := start + ansi.StringWidth(label)
+		if x >= start && x < end {
+			return i
+		}
+		start = end + 1
+	}
+	return -1
+}
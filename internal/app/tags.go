@@ -0,0 +1,298 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// tagSelectorPrefix starts a window selector that picks every window with a
+// tag, e.g. "tag:prod".
+const tagSelectorPrefix = "tag:"
+
+// ParseTags splits tags separated by spaces or commas, drops a leading # and
+// returns them sorted, each once.
+func ParseTags(args ...string) []string {
+	var tags []string
+	for _, arg := range args {
+		for _, tag := range strings.FieldsFunc(arg, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			if tag = strings.TrimPrefix(tag, "#"); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// windowHasTag reports whether a window carries tag.
+func windowHasTag(w *terminal.Window, tag string) bool {
+	return slices.Contains(w.Tags, tag)
+}
+
+// WindowTags returns the tags set on any window, sorted.
+func (m *OS) WindowTags() []string {
+	var tags []string
+	for _, w := range m.Windows {
+		tags = append(tags, w.Tags...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// SelectWindows returns the windows a selector picks: the window with that
+// ID, every window with the tag of a "tag:" selector, or else the windows
+// with that name.
+func (m *OS) SelectWindows(selector string) []*terminal.Window {
+	for _, w := range m.Windows {
+		if w.ID == selector {
+			return []*terminal.Window{w}
+		}
+	}
+	return m.findWindowsByName(selector)
+}
+
+// TagWindows adds tags to the windows a selector picks and returns how many
+// it picked.
+func (m *OS) TagWindows(selector string, tags []string) (int, error) {
+	if len(tags) == 0 {
+		return 0, fmt.Errorf("no tags given")
+	}
+	windows := m.SelectWindows(selector)
+	if len(windows) == 0 {
+		return 0, fmt.Errorf("no window matches %s", selector)
+	}
+	for _, w := range windows {
+		w.Tags = ParseTags(append(slices.Clone(w.Tags), tags...)...)
+	}
+	m.tagsChanged()
+	return len(windows), nil
+}
+
+// UntagWindows removes tags, or every tag when none are given, from the
+// windows a selector picks and returns how many it picked.
+func (m *OS) UntagWindows(selector string, tags []string) (int, error) {
+	windows := m.SelectWindows(selector)
+	if len(windows) == 0 {
+		return 0, fmt.Errorf("no window matches %s", selector)
+	}
+	for _, w := range windows {
+		if len(tags) == 0 {
+			w.Tags = nil
+			continue
+		}
+		w.Tags = slices.DeleteFunc(w.Tags, func(tag string) bool { return slices.Contains(tags, tag) })
+		if len(w.Tags) == 0 {
+			w.Tags = nil
+		}
+	}
+	m.tagsChanged()
+	return len(windows), nil
+}
+
+// tagsChanged redraws what shows tags and drops a sidebar filter on a tag
+// no window has anymore.
+func (m *OS) tagsChanged() {
+	if m.SidebarTag != "" && !slices.Contains(m.WindowTags(), m.SidebarTag) {
+		m.SidebarTag = ""
+	}
+	m.MarkAllDirty()
+	m.SyncStateToDaemon()
+}
+
+// StartTagPrompt opens the prompt editing the focused window's tags.
+func (m *OS) StartTagPrompt() {
+	w := m.GetFocusedWindow()
+	if w == nil {
+		return
+	}
+	text := strings.Join(w.Tags, " ")
+	if text != "" {
+		text += " "
+	}
+	m.TagPrompt.Open("Tags of "+m.getWindowDisplayName(w), "Space or comma between tags  Enter: save  Esc: cancel", text)
+}
+
+// CancelTagPrompt closes the tag prompt without changing any tags.
+func (m *OS) CancelTagPrompt() {
+	m.TagPrompt.Close()
+}
+
+// SubmitTagPrompt closes the tag prompt and sets the focused window's tags
+// to the ones typed.
+func (m *OS) SubmitTagPrompt() {
+	tags := ParseTags(m.TagPrompt.Close())
+	w := m.GetFocusedWindow()
+	if w == nil {
+		return
+	}
+	w.Tags = tags
+	if len(tags) == 0 {
+		w.Tags = nil
+	}
+	m.tagsChanged()
+}
+
+// sidebarShows reports whether the sidebar lists a window: every window, or
+// with a tag filter only those with the tag.
+func (m *OS) sidebarShows(w *terminal.Window) bool {
+	return m.SidebarTag == "" || windowHasTag(w, m.SidebarTag)
+}
+
+// CycleSidebarTag filters the sidebar by the next tag set on any window,
+// and back to every window after the last one.
+func (m *OS) CycleSidebarTag() {
+	tags := m.WindowTags()
+	if len(tags) == 0 {
+		m.SidebarTag = ""
+		m.ShowNotification("No tagged windows", "info", config.NotificationDuration)
+		return
+	}
+	switch i := slices.Index(tags, m.SidebarTag); {
+	case m.SidebarTag == "":
+		m.SidebarTag = tags[0]
+	case i >= 0 && i+1 < len(tags):
+		m.SidebarTag = tags[i+1]
+	default:
+		m.SidebarTag = ""
+	}
+	if m.SidebarSelectedIndex < 0 || m.SidebarSelectedIndex >= len(m.Windows) || !m.sidebarShows(m.Windows[m.SidebarSelectedIndex]) {
+		m.SidebarSelectedIndex = slices.IndexFunc(m.Windows, m.sidebarShows)
+	}
+}
+
+// ShowTagView gathers the windows with a tag from every workspace onto an
+// empty workspace, tiled, and switches to it. Showing the same tag again,
+// or HideTagView, puts every window back where it was.
+func (m *OS) ShowTagView(tag string) error {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if m.TagView != nil {
+		same := m.TagView.Tag == tag
+		m.HideTagView()
+		if same {
+			return nil
+		}
+	}
+	if tag == "" {
+		return fmt.Errorf("no tag given")
+	}
+
+	var tagged []int
+	for i, w := range m.Windows {
+		if windowHasTag(w, tag) {
+			tagged = append(tagged, i)
+		}
+	}
+	if len(tagged) == 0 {
+		return fmt.Errorf("no windows tagged %s", tag)
+	}
+	view := 0
+	for ws := 1; ws <= m.NumWorkspaces && view == 0; ws++ {
+		if m.GetWorkspaceWindowCount(ws) == 0 && !m.WorkspaceVisible(ws) {
+			view = ws
+		}
+	}
+	if view == 0 {
+		return fmt.Errorf("no empty workspace to show the windows tagged %s on", tag)
+	}
+
+	tv := &session.TagViewState{
+		Tag:       tag,
+		Workspace: view,
+		Origin:    m.CurrentWorkspace,
+		Name:      m.WorkspaceNames[view],
+		Tiling:    m.WorkspaceAutoTiling(view),
+		Windows:   make(map[string]session.TagViewOrigin, len(tagged)),
+	}
+	// Windows are moved while their workspace is shown, so the ones leaving
+	// it stop streaming until the view workspace is switched to
+	for _, i := range tagged {
+		w := m.Windows[i]
+		tv.Windows[w.ID] = session.TagViewOrigin{Workspace: w.Workspace, Minimized: w.Minimized, X: w.X, Y: w.Y, Width: w.Width, Height: w.Height}
+		m.moveWindowToWorkspace(i, view)
+		w.Minimized = false
+	}
+	if m.WorkspaceNames == nil {
+		m.WorkspaceNames = make(map[int]string)
+	}
+	m.WorkspaceNames[view] = "#" + tag
+	m.TagView = tv
+
+	m.SwitchToWorkspace(view)
+	m.AutoTiling = true
+	m.TileAllWindows()
+	m.MarkAllDirty()
+	m.ShowNotification(fmt.Sprintf("Showing %d windows tagged %s", len(tagged), tag), "info", config.NotificationDuration)
+	return nil
+}
+
+// HideTagView leaves the tag view: the windows it shows go back to their
+// workspaces, minimized again if they were, and the workspace active
+// before the view is switched back to. Windows moved out of the view in the
+// meantime stay where they are.
+func (m *OS) HideTagView() {
+	tv := m.TagView
+	if tv == nil {
+		return
+	}
+	m.TagView = nil
+
+	m.SwitchToWorkspace(tv.Workspace)
+	for i, w := range m.Windows {
+		origin, ok := tv.Windows[w.ID]
+		if !ok || w.Workspace != tv.Workspace {
+			continue
+		}
+		m.moveWindowToWorkspace(i, origin.Workspace)
+		if !m.WorkspaceAutoTiling(origin.Workspace) || w.Floating {
+			w.X, w.Y = origin.X, origin.Y
+			w.Resize(origin.Width, origin.Height)
+		}
+		w.Minimized = origin.Minimized
+	}
+	if tv.Name == "" {
+		delete(m.WorkspaceNames, tv.Workspace)
+	} else {
+		m.WorkspaceNames[tv.Workspace] = tv.Name
+	}
+
+	m.SwitchToWorkspace(tv.Origin)
+	m.setWorkspaceAutoTiling(tv.Workspace, tv.Tiling)
+	m.MarkAllDirty()
+}
+
+// tagViewState returns a copy of the tag view for the session state, or
+// nil when none is shown.
+func (m *OS) tagViewState() *session.TagViewState {
+	if m.TagView == nil {
+		return nil
+	}
+	tv := *m.TagView
+	tv.Windows = maps.Clone(tv.Windows)
+	return &tv
+}
+
+// restoreTagView takes over the tag view of a session state, so it can
+// still be left after reattaching.
+func (m *OS) restoreTagView(state *session.TagViewState) {
+	m.TagView = nil
+	if state != nil {
+		tv := *state
+		tv.Windows = maps.Clone(tv.Windows)
+		m.TagView = &tv
+	}
+}
+
+// formatTags formats tags for display, e.g. "#db #prod".
+func formatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "#" + tag
+	}
+	return strings.Join(formatted, " ")
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestWindowTags(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", CustomName: "api", X: 1, Y: 2, Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", CustomName: "db", Width: 40, Height: 20, Workspace: 2, Minimized: true},
+		{ID: "window-three-00", CustomName: "logs", Width: 40, Height: 20, Workspace: 1},
+	}
+	m.FocusedWindow = 0
+
+	if got := ParseTags("#prod, db", "prod"); len(got) != 2 || got[0] != "db" || got[1] != "prod" {
+		t.Fatalf("expected tags [db prod], got %v", got)
+	}
+	if _, err := m.TagWindows("api", []string{"prod"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.TagWindows("window-two-0000", []string{"prod", "db"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.SelectWindows("tag:prod"); len(got) != 2 {
+		t.Fatalf("expected tag:prod to pick 2 windows, got %d", len(got))
+	}
+	if _, err := m.TagWindows("nothing", []string{"prod"}); err == nil {
+		t.Errorf("expected an error tagging a window that does not exist")
+	}
+
+	// The sidebar filter lists only the windows with the tag
+	m.CycleSidebarTag()
+	if m.SidebarTag != "db" || len(m.CalculateSidebarLayout().ItemPositions) != 1 {
+		t.Fatalf("expected the sidebar to list the window tagged db, got %q", m.SidebarTag)
+	}
+	m.CycleSidebarTag()
+	m.CycleSidebarTag()
+	if m.SidebarTag != "" || len(m.CalculateSidebarLayout().ItemPositions) != 3 {
+		t.Errorf("expected the filter to cycle back to every window, got %q", m.SidebarTag)
+	}
+
+	// The tag view gathers the windows on an empty workspace and puts them back
+	if err := m.ShowTagView("prod"); err != nil {
+		t.Fatal(err)
+	}
+	view := m.CurrentWorkspace
+	if view != 3 || m.Windows[0].Workspace != 3 || m.Windows[1].Workspace != 3 || m.Windows[1].Minimized || m.Windows[2].Workspace != 1 {
+		t.Fatalf("expected the tagged windows on workspace 3, got workspace %d", view)
+	}
+	if m.WorkspaceNames[view] != "#prod" || !m.AutoTiling {
+		t.Errorf("expected the tag view tiled and named after the tag")
+	}
+
+	// The view is kept in the session state, so it can be left after a reattach
+	state := m.BuildSessionState()
+	m.TagView = nil
+	m.restoreTagView(state.TagView)
+	if m.TagView == nil || m.TagView.Tag != "prod" || len(m.TagView.Windows) != 2 {
+		t.Fatalf("expected the tag view restored from the session state, got %+v", m.TagView)
+	}
+	if err := m.ShowTagView("prod"); err != nil {
+		t.Fatal(err)
+	}
+	if m.TagView != nil || m.CurrentWorkspace != 1 || m.Windows[0].Workspace != 1 || m.Windows[1].Workspace != 2 || !m.Windows[1].Minimized {
+		t.Fatalf("expected the windows back where they were, on workspace %d", m.CurrentWorkspace)
+	}
+	if m.Windows[0].X != 1 || m.Windows[0].Y != 2 || m.WorkspaceNames[view] != "" || m.WorkspaceAutoTiling(view) {
+		t.Errorf("expected the geometry and the borrowed workspace restored")
+	}
+
+	if _, err := m.UntagWindows("tag:prod", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.WindowTags()) != 0 {
+		t.Errorf("expected no tags left, got %v", m.WindowTags())
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// MoveWindowToSession teleports a window to another session of the same
+// daemon. Its process keeps running and its screen and scrollback come
+// along: the window closes here and opens in the other session, right
+// away if a client is attached to it or else when one next attaches.
+func (m *OS) MoveWindowToSession(windowID, target string) error {
+	if !m.IsDaemonSession || m.DaemonClient == nil {
+		return fmt.Errorf("windows can only be moved between daemon sessions")
+	}
+	if target == "" {
+		return fmt.Errorf("no session to move the window to")
+	}
+	if target == m.SessionName {
+		return fmt.Errorf("window is already in session %s", target)
+	}
+
+	index := slices.IndexFunc(m.Windows, func(w *terminal.Window) bool { return w.ID == windowID })
+	if index < 0 {
+		return fmt.Errorf("window not found: %s", windowID)
+	}
+	w := m.Windows[index]
+	if w.PTYID == "" || m.IsDebugConsole(w) {
+		return fmt.Errorf("window %s has no process to move", m.getWindowDisplayName(w))
+	}
+	if w.Panes != nil || len(w.Tabs) > 1 {
+		return fmt.Errorf("windows with panes or tabs cannot be moved to another session")
+	}
+
+	if err := m.DaemonClient.MovePTY(w.PTYID, target, windowState(w)); err != nil {
+		return fmt.Errorf("failed to move window: %w", err)
+	}
+	m.LogInfo("Moved window %s to session %s", m.getWindowDisplayName(w), target)
+
+	// The daemon now streams the PTY to the other session only
+	w.Close()
+	m.removeWindow(index, true)
+	m.ShowNotification(fmt.Sprintf("Window moved to session %s", target), "success", config.NotificationDuration)
+	return nil
+}
+
+// AdoptWindow opens a window teleported in from another session on the
+// current workspace, fitted to the screen. It keeps its name, size and
+// content but not its group, which only exists in the session it left.
+func (m *OS) AdoptWindow(ws *session.WindowState) error {
+	if ws == nil || m.DaemonClient == nil {
+		return fmt.Errorf("no window to adopt")
+	}
+	if m.findTerminal(ws.ID) != nil {
+		return nil
+	}
+
+	state := *ws
+	state.Workspace = m.CurrentWorkspace
+	state.Z = len(m.Windows)
+	state.Minimized = false
+	state.Group, state.SwallowedBy = "", ""
+
+	screenWidth, screenHeight := m.GetRenderWidth(), m.GetUsableHeight()
+	if screenWidth > 0 && screenHeight > 0 {
+		state.Width = max(min(state.Width, screenWidth), config.MinWindowWidth)
+		state.Height = max(min(state.Height, screenHeight), config.MinWindowHeight)
+		state.X, state.Y = m.newWindowPosition(nil, screenWidth, screenHeight, state.Width, state.Height)
+	}
+
+	window := m.createWindowFromSync(&state)
+	if window == nil {
+		return fmt.Errorf("failed to open window %s", ws.ID)
+	}
+	m.placeWindow(window)
+	m.LogInfo("Adopted window %s from another session", m.getWindowDisplayName(window))
+	m.ShowNotification(fmt.Sprintf("Window moved in: %s", m.getWindowDisplayName(window)), "info", config.NotificationDuration)
+	return nil
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"slices"
+
 	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/layout"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
@@ -35,6 +37,10 @@ func (m *OS) calculateTilingLayout(n int) []tileLayout {
 	layouts := layout.CalculateTilingLayout(n, m.GetRenderWidth(), m.GetUsableHeight(), m.GetTopMargin(), m.MasterRatio)
 	result := make([]tileLayout, len(layouts))
 	for i, l := range layouts {
+		if m.CompactMode {
+			// The compact layout stacks every tile at full size
+			l.X, l.Y, l.Width, l.Height = 0, m.GetTopMargin(), m.GetRenderWidth(), m.GetUsableHeight()
+		}
 		result[i] = tileLayout{
 			x:      l.X,
 			y:      l.Y,
@@ -50,7 +56,7 @@ func (m *OS) TileAllWindows() {
 	// Get list of visible windows in current workspace (not minimized)
 	var visibleWindows []*terminal.Window
 	for _, w := range m.Windows {
-		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing {
+		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing && !w.PiP && !w.Floating {
 			visibleWindows = append(visibleWindows, w)
 		}
 	}
@@ -167,7 +173,7 @@ func (m *OS) ToggleAutoTiling() {
 		// Add all visible windows to the tree in order
 		var visibleWindows []*terminal.Window
 		for _, w := range m.Windows {
-			if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing {
+			if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing && !w.PiP && !w.Floating {
 				visibleWindows = append(visibleWindows, w)
 			}
 		}
@@ -355,7 +361,7 @@ func (m *OS) TileRemainingWindows(excludeIndex int) {
 	var visibleWindows []*terminal.Window
 	var visibleIndices []int
 	for i, w := range m.Windows {
-		if i != excludeIndex && w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing {
+		if i != excludeIndex && w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing && !w.PiP && !w.Floating {
 			visibleWindows = append(visibleWindows, w)
 			visibleIndices = append(visibleIndices, i)
 		}
@@ -401,6 +407,9 @@ func (m *OS) SwapWindow(dir Direction) {
 	}
 
 	focusedWindow := m.Windows[m.FocusedWindow]
+	if focusedWindow.Floating {
+		return
+	}
 	targetIndex := m.findAdjacentWindow(focusedWindow, dir)
 
 	if targetIndex >= 0 {
@@ -422,7 +431,7 @@ func (m *OS) findAdjacentWindow(focused *terminal.Window, dir Direction) int {
 	}
 
 	for i, window := range m.Windows {
-		if i == m.FocusedWindow || window.Workspace != m.CurrentWorkspace || window.Minimized || window.Minimizing {
+		if i == m.FocusedWindow || window.Workspace != m.CurrentWorkspace || window.Minimized || window.Minimizing || window.Floating {
 			continue
 		}
 
@@ -577,7 +586,7 @@ func (m *OS) ResizeFocusedWindowHeight(deltaPixels int) {
 	}
 
 	focusedWindow := m.Windows[m.FocusedWindow]
-	if focusedWindow.Workspace != m.CurrentWorkspace || focusedWindow.Minimized {
+	if focusedWindow.Workspace != m.CurrentWorkspace || focusedWindow.Minimized || focusedWindow.Floating {
 		return
 	}
 
@@ -606,7 +615,7 @@ func (m *OS) ResizeFocusedWindowWidth(deltaPixels int) {
 	}
 
 	focusedWindow := m.Windows[m.FocusedWindow]
-	if focusedWindow.Workspace != m.CurrentWorkspace || focusedWindow.Minimized {
+	if focusedWindow.Workspace != m.CurrentWorkspace || focusedWindow.Minimized || focusedWindow.Floating {
 		return
 	}
 
@@ -634,7 +643,7 @@ func (m *OS) ResizeFocusedWindowWidthLeft(deltaPixels int) {
 	}
 
 	focusedWindow := m.Windows[m.FocusedWindow]
-	if focusedWindow.Workspace != m.CurrentWorkspace || focusedWindow.Minimized {
+	if focusedWindow.Workspace != m.CurrentWorkspace || focusedWindow.Minimized || focusedWindow.Floating {
 		return
 	}
 
@@ -662,7 +671,7 @@ func (m *OS) ResizeFocusedWindowHeightTop(deltaPixels int) {
 	}
 
 	focusedWindow := m.Windows[m.FocusedWindow]
-	if focusedWindow.Workspace != m.CurrentWorkspace || focusedWindow.Minimized {
+	if focusedWindow.Workspace != m.CurrentWorkspace || focusedWindow.Minimized || focusedWindow.Floating {
 		return
 	}
 
@@ -708,8 +717,6 @@ func (m *OS) adjustTilingNeighborsGeneric(resized *terminal.Window, newX, newY,
 	newRight := newX + newWidth
 	newBottom := newY + newHeight
 
-	const minWidth = config.DefaultWindowWidth
-	const minHeight = config.DefaultWindowHeight
 	minY := m.GetTopMargin()
 	maxY := minY + m.GetUsableHeight()
 	renderWidth := m.GetRenderWidth()
@@ -720,7 +727,7 @@ func (m *OS) adjustTilingNeighborsGeneric(resized *terminal.Window, newX, newY,
 		leftWindows = removeWindowFromList(leftWindows, resized)
 		rightWindows = removeWindowFromList(rightWindows, resized)
 
-		constrainedRight := m.constrainVerticalSplit(newRight, leftWindows, rightWindows, minWidth, renderWidth)
+		constrainedRight := m.constrainVerticalSplit(newRight, append(slices.Clone(leftWindows), resized), rightWindows, renderWidth)
 
 		for _, win := range leftWindows {
 			resize(m, win, constrainedRight-win.X, win.Height)
@@ -742,7 +749,7 @@ func (m *OS) adjustTilingNeighborsGeneric(resized *terminal.Window, newX, newY,
 		leftWindows = removeWindowFromList(leftWindows, resized)
 		rightWindows = removeWindowFromList(rightWindows, resized)
 
-		constrainedX := m.constrainVerticalSplit(newX, leftWindows, rightWindows, minWidth, renderWidth)
+		constrainedX := m.constrainVerticalSplit(newX, leftWindows, append(slices.Clone(rightWindows), resized), renderWidth)
 
 		for _, win := range leftWindows {
 			resize(m, win, constrainedX-win.X, win.Height)
@@ -764,7 +771,7 @@ func (m *OS) adjustTilingNeighborsGeneric(resized *terminal.Window, newX, newY,
 		topWindows = removeWindowFromList(topWindows, resized)
 		bottomWindows = removeWindowFromList(bottomWindows, resized)
 
-		constrainedBottom := m.constrainHorizontalSplit(newBottom, topWindows, bottomWindows, minHeight, minY, maxY)
+		constrainedBottom := m.constrainHorizontalSplit(newBottom, append(slices.Clone(topWindows), resized), bottomWindows, minY, maxY)
 
 		for _, win := range topWindows {
 			resize(m, win, win.Width, constrainedBottom-win.Y)
@@ -786,7 +793,7 @@ func (m *OS) adjustTilingNeighborsGeneric(resized *terminal.Window, newX, newY,
 		topWindows = removeWindowFromList(topWindows, resized)
 		bottomWindows = removeWindowFromList(bottomWindows, resized)
 
-		constrainedY := m.constrainHorizontalSplit(newY, topWindows, bottomWindows, minHeight, minY, maxY)
+		constrainedY := m.constrainHorizontalSplit(newY, topWindows, append(slices.Clone(bottomWindows), resized), minY, maxY)
 
 		for _, win := range topWindows {
 			resize(m, win, win.Width, constrainedY-win.Y)
@@ -805,10 +812,12 @@ func (m *OS) adjustTilingNeighborsGeneric(resized *terminal.Window, newX, newY,
 	return newX, newY, newRight, newBottom
 }
 
-// constrainVerticalSplit calculates the valid position for a vertical split line
-func (m *OS) constrainVerticalSplit(requested int, leftWindows, rightWindows []*terminal.Window, minWidth, maxX int) int {
+// constrainVerticalSplit calculates the valid position for a vertical split line,
+// keeping every window on either side at least its minimum width
+func (m *OS) constrainVerticalSplit(requested int, leftWindows, rightWindows []*terminal.Window, maxX int) int {
 	minValidX := 0
 	for _, win := range leftWindows {
+		minWidth, _ := win.MinimumSize()
 		minRequired := win.X + minWidth
 		if minRequired > minValidX {
 			minValidX = minRequired
@@ -817,6 +826,7 @@ func (m *OS) constrainVerticalSplit(requested int, leftWindows, rightWindows []*
 
 	maxValidX := maxX
 	for _, win := range rightWindows {
+		minWidth, _ := win.MinimumSize()
 		maxAllowed := win.X + win.Width - minWidth
 		if maxAllowed < maxValidX {
 			maxValidX = maxAllowed
@@ -826,10 +836,12 @@ func (m *OS) constrainVerticalSplit(requested int, leftWindows, rightWindows []*
 	return max(minValidX, min(requested, maxValidX))
 }
 
-// constrainHorizontalSplit calculates the valid position for a horizontal split line
-func (m *OS) constrainHorizontalSplit(requested int, topWindows, bottomWindows []*terminal.Window, minHeight, minY, maxY int) int {
+// constrainHorizontalSplit calculates the valid position for a horizontal split line,
+// keeping every window on either side at least its minimum height
+func (m *OS) constrainHorizontalSplit(requested int, topWindows, bottomWindows []*terminal.Window, minY, maxY int) int {
 	minValidY := minY
 	for _, win := range topWindows {
+		_, minHeight := win.MinimumSize()
 		minRequired := win.Y + minHeight
 		if minRequired > minValidY {
 			minValidY = minRequired
@@ -838,6 +850,7 @@ func (m *OS) constrainHorizontalSplit(requested int, topWindows, bottomWindows [
 
 	maxValidY := maxY
 	for _, win := range bottomWindows {
+		_, minHeight := win.MinimumSize()
 		maxAllowed := win.Y + win.Height - minHeight
 		if maxAllowed < maxValidY {
 			maxValidY = maxAllowed
@@ -850,8 +863,7 @@ func (m *OS) constrainHorizontalSplit(requested int, topWindows, bottomWindows [
 // applyTilingResult updates the resized window with constrained values from adjustTilingNeighborsGeneric
 // and validates that the dimensions remain within bounds, clamping as a last resort.
 func (m *OS) applyTilingResult(resized *terminal.Window, finalX, finalY, finalRight, finalBottom int) {
-	const minWidth = config.DefaultWindowWidth
-	const minHeight = config.DefaultWindowHeight
+	minWidth, minHeight := resized.MinimumSize()
 	minY := m.GetTopMargin()
 	maxY := minY + m.GetUsableHeight()
 	renderWidth := m.GetRenderWidth()
@@ -999,7 +1011,7 @@ func (m *OS) BuildBSPTreeFromCurrentLayout() {
 	var windowIDs []int
 
 	for _, w := range m.Windows {
-		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing {
+		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing && !w.PiP && !w.Floating {
 			windows = append(windows, layout.Rect{
 				X: w.X,
 				Y: w.Y,
@@ -1080,12 +1092,9 @@ func (m *OS) ApplyBSPLayout() {
 		return
 	}
 
-	bounds := m.GetBSPBounds()
-	layouts := tree.ApplyLayout(bounds)
-
-	for windowIntID, rect := range layouts {
+	for windowIntID, rect := range m.bspLayout(tree) {
 		win := m.getWindowByIntID(windowIntID)
-		if win == nil || win.Workspace != m.CurrentWorkspace || win.Minimized {
+		if win == nil || win.Workspace != m.CurrentWorkspace || win.Minimized || win.PiP || win.Floating {
 			continue
 		}
 
@@ -1117,6 +1126,10 @@ func (m *OS) AddWindowToBSPTree(window *terminal.Window) {
 	if m.SplitTargetWindowID != "" {
 		targetIntID = m.getWindowIntID(m.SplitTargetWindowID)
 		m.LogInfo("BSP: Using explicit split target (int ID %d)", targetIntID)
+	} else if prevID, ok := m.WindowToBSPID[m.LastFocusedWindowID]; ok && config.TilingInsert == "after_focused" && tree.HasWindow(prevID) {
+		// The new window is already focused, so split the window focused before it
+		targetIntID = prevID
+		m.LogInfo("BSP: Using previously focused window as target (int ID %d)", targetIntID)
 	} else {
 		// Use the last window in the BSP tree as the target
 		// This ensures proper spiral pattern
@@ -1136,6 +1149,9 @@ func (m *OS) AddWindowToBSPTree(window *terminal.Window) {
 		m.PreselectionDir = layout.PreselectionNone // Clear preselection
 	} else {
 		tree.InsertWindow(windowIntID, targetIntID, layout.SplitNone, 0.5, bounds)
+		if config.TilingInsert == "master" && m.SplitTargetWindowID == "" {
+			tree.MoveWindowToFront(windowIntID)
+		}
 	}
 
 	m.LogInfo("BSP: Tree now has %d windows", tree.WindowCount())
@@ -1163,14 +1179,14 @@ func (m *OS) RemoveWindowFromBSPTree(window *terminal.Window) {
 // This should be called after mouse resize operations complete.
 func (m *OS) SyncBSPTreeFromGeometry() {
 	tree := m.WorkspaceTrees[m.CurrentWorkspace]
-	if tree == nil || tree.IsEmpty() {
-		return
+	if tree == nil || tree.IsEmpty() || m.CompactMode {
+		return // Stacked windows say nothing about the splits
 	}
 
 	// Build geometry map from current window positions
 	geometry := make(map[int]layout.Rect)
 	for _, win := range m.Windows {
-		if win.Workspace == m.CurrentWorkspace && !win.Minimized && !win.Minimizing {
+		if win.Workspace == m.CurrentWorkspace && !win.Minimized && !win.Minimizing && !win.PiP && !win.Floating {
 			windowIntID := m.getWindowIntID(win.ID)
 			geometry[windowIntID] = layout.Rect{
 				X: win.X,
@@ -1321,6 +1337,72 @@ func (m *OS) EqualizeSplits() {
 	m.ApplyBSPLayout()
 }
 
+// GrowFocusedTile grows the focused window's share of the tiled layout by
+// delta (a fraction of the enclosing splits), shrinking its neighbours to
+// match. Negative deltas shrink the window instead.
+func (m *OS) GrowFocusedTile(delta float64) {
+	if !m.AutoTiling {
+		return
+	}
+
+	focusedWindow := m.GetFocusedWindow()
+	if focusedWindow == nil {
+		return
+	}
+
+	tree := m.WorkspaceTrees[m.CurrentWorkspace]
+	if tree == nil {
+		return
+	}
+
+	if !tree.GrowWindow(m.getWindowIntID(focusedWindow.ID), delta) {
+		return
+	}
+	m.ApplyBSPLayout()
+}
+
+// PromoteFocusedToMaster swaps the focused window with the window in the
+// first slot of the tiling order. If the focused window is already first, it
+// swaps places with the second window instead.
+func (m *OS) PromoteFocusedToMaster() {
+	if !m.AutoTiling {
+		return
+	}
+
+	focusedWindow := m.GetFocusedWindow()
+	tree := m.WorkspaceTrees[m.CurrentWorkspace]
+	if focusedWindow == nil || tree == nil {
+		return
+	}
+
+	ids := tree.GetAllWindowIDs()
+	focusedID := m.getWindowIntID(focusedWindow.ID)
+	if len(ids) < 2 || !tree.HasWindow(focusedID) {
+		return
+	}
+	other := ids[0]
+	if other == focusedID {
+		other = ids[1]
+	}
+	tree.SwapWindows(focusedID, other)
+	m.ApplyBSPLayout()
+}
+
+// CycleTilingInsert steps to the next position new tiled windows are
+// inserted at.
+func (m *OS) CycleTilingInsert() {
+	positions := config.TilingInsertPositions
+	next := (slices.Index(positions, config.TilingInsert) + 1) % len(positions)
+	config.TilingInsert = positions[next]
+
+	labels := map[string]string{
+		"end":           "after the last window",
+		"after_focused": "after the focused window",
+		"master":        "as master",
+	}
+	m.ShowNotification("New windows: "+labels[config.TilingInsert], "info", config.NotificationDuration)
+}
+
 // SwapWindowsInBSPTree swaps two windows in the BSP tree
 func (m *OS) SwapWindowsInBSPTree(window1, window2 *terminal.Window) {
 	tree := m.WorkspaceTrees[m.CurrentWorkspace]
@@ -0,0 +1,270 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// titleRefreshInterval is how often the live fields of title bars, such as
+// the working directory, are sampled.
+const titleRefreshInterval = time.Second
+
+// titleProcessFields are the title format fields that need the processes of
+// a pane to be asked about.
+var titleProcessFields = []string{"cwd", "dir", "command"}
+
+// titleProcess holds the title fields of a pane that take system calls or a
+// round trip to the daemon to find out.
+type titleProcess struct {
+	Cwd     string // Working directory of the shell
+	Command string // Program running in place of the shell
+}
+
+// TitleProcessesMsg carries the title fields sampled for the panes in view,
+// by pane ID.
+type TitleProcessesMsg struct {
+	Processes map[string]titleProcess
+	Err       error
+}
+
+// windowName returns the name of a window: its custom name, else the title
+// its program set, or an empty string for the default title.
+func windowName(w *terminal.Window) string {
+	if w.CustomName != "" {
+		return w.CustomName
+	}
+	if w.Title != "" && !isDefaultTitle(w.Title, w.ID) {
+		return w.Title
+	}
+	return ""
+}
+
+// titleBarText returns the title bar text of a window:
+// appearance.title_format with its fields filled in.
+func (m *OS) titleBarText(w *terminal.Window) string {
+	text := m.titleBarFields(w)
+	if secureInput(w) {
+		text = strings.TrimSpace(secureInputMark() + " " + text)
+	}
+	return text
+}
+
+// titleBarFields returns the title bar text of a window without the secure
+// input mark.
+func (m *OS) titleBarFields(w *terminal.Window) string {
+	if tabs := m.compactTabs(w); tabs != "" {
+		return tabs
+	}
+	if config.TitleFormat == "{name}" {
+		return windowName(w)
+	}
+	return config.ExpandTitleFormat(config.TitleFormat, func(field string) string {
+		return m.titleField(w, field)
+	})
+}
+
+// titleField returns the value of a title format field for a window.
+func (m *OS) titleField(w *terminal.Window, field string) string {
+	switch field {
+	case "name":
+		return windowName(w)
+	case "title":
+		if isDefaultTitle(w.Title, w.ID) {
+			return ""
+		}
+		return w.Title
+	case "icon":
+		return strings.TrimSpace(windowIconPrefix(w))
+	case "cwd":
+		return shortenHome(m.titleWorkingDirectory(w.ActivePane()))
+	case "dir":
+		if cwd := m.titleWorkingDirectory(w.ActivePane()); cwd != "" {
+			return filepath.Base(cwd)
+		}
+	case "command":
+		return m.titleProcesses[w.ActivePane().ID].Command
+	case "flags":
+		return m.windowFlags(w)
+	case "badge":
+		return windowBadge(w)
+	case "scroll":
+		if offset := w.ActivePane().ScrollbackOffset; offset > 0 {
+			if config.UseASCIIOnly {
+				return "^" + strconv.Itoa(offset)
+			}
+			return "↑" + strconv.Itoa(offset)
+		}
+	case "size":
+		return fmt.Sprintf("%dx%d", max(w.Width-2, 0), max(w.Height-2, 0))
+	case "workspace":
+		return strconv.Itoa(w.Workspace)
+	case "id":
+		return w.ID[:min(len(w.ID), 8)]
+	}
+	return ""
+}
+
+// titleWorkingDirectory returns the working directory of a pane for its
+// title: the one its shell reported, else the last one sampled.
+func (m *OS) titleWorkingDirectory(pane *terminal.Window) string {
+	if cwd := pane.ReportedWorkingDirectory(); cwd != "" {
+		return cwd
+	}
+	return m.titleProcesses[pane.ID].Cwd
+}
+
+// windowFlags returns a letter for each mode a window is in: P pinned
+// picture-in-picture, F floating in a tiled workspace, B broadcasting typed
+// keys to its group, L input locked, M mouse kept by tuios, S viewing
+// scrollback, R replaying output, T keys logged to a tape recording and Z
+// zoomed.
+func (m *OS) windowFlags(w *terminal.Window) string {
+	broadcasting := w.Group != "" && slices.ContainsFunc(m.Windows, func(other *terminal.Window) bool {
+		return other != w && other.Group == w.Group
+	})
+	logging := m.TapeRecorder != nil && m.TapeRecorder.IsRecording() && w == m.GetFocusedWindow()
+	var flags strings.Builder
+	for _, flag := range []struct {
+		on     bool
+		letter byte
+	}{
+		{w.PiP, 'P'},
+		{w.Floating, 'F'},
+		{broadcasting, 'B'},
+		{w.InputLocked, 'L'},
+		{w.MouseCapture, 'M'},
+		{w.ScrollbackMode, 'S'},
+		{w.Replay != nil, 'R'},
+		{logging, 'T'},
+		{w.Zoomed, 'Z'},
+	} {
+		if flag.on {
+			flags.WriteByte(flag.letter)
+		}
+	}
+	return flags.String()
+}
+
+// shortenHome writes a path below the home directory relative to it.
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" || path == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + strings.TrimPrefix(path, home)
+	}
+	return path
+}
+
+// RefreshTitleBars recomputes the title bars of the windows in view and
+// marks those whose text changed for redrawing. It returns a command that
+// samples the working directories and commands of their panes about once a
+// second when the title format shows them, one sample at a time.
+func (m *OS) RefreshTitleBars(now time.Time) (bool, tea.Cmd) {
+	if config.WindowTitlePosition == "hidden" {
+		return false, nil
+	}
+	changed := false
+	for _, w := range m.Windows {
+		if w.Minimized || !m.WorkspaceVisible(w.Workspace) {
+			continue
+		}
+		if title := m.titleBarText(w); title != w.TitleBar {
+			w.TitleBar = title
+			w.Dirty = true
+			changed = true
+		}
+	}
+	return changed, m.sampleTitleProcesses(now)
+}
+
+// sampleTitleProcesses returns a command that finds out the working
+// directory and foreground command of the active pane of each window in
+// view, or nil when none is due. Local panes are asked through their
+// process, and the panes of a session through one PTY list from the daemon.
+func (m *OS) sampleTitleProcesses(now time.Time) tea.Cmd {
+	if m.titleSampling || now.Sub(m.lastTitleRefresh) < titleRefreshInterval {
+		return nil
+	}
+	if !slices.ContainsFunc(config.TitleFormatFields(config.TitleFormat), func(field string) bool {
+		return slices.Contains(titleProcessFields, field)
+	}) {
+		return nil
+	}
+	var local, remote []*terminal.Window
+	for _, w := range m.Windows {
+		if w.Minimized || !m.WorkspaceVisible(w.Workspace) {
+			continue
+		}
+		if pane := w.ActivePane(); pane.PTYID != "" {
+			remote = append(remote, pane)
+		} else {
+			local = append(local, pane)
+		}
+	}
+	if len(local) == 0 && len(remote) == 0 {
+		return nil
+	}
+	m.lastTitleRefresh = now
+	m.titleSampling = true
+	client := m.DaemonClient
+	return func() tea.Msg {
+		processes := make(map[string]titleProcess, len(local)+len(remote))
+		for _, pane := range local {
+			processes[pane.ID] = titleProcess{Cwd: pane.ShellWorkingDirectory(), Command: pane.ForegroundProcessName()}
+		}
+		if len(remote) == 0 || client == nil {
+			return TitleProcessesMsg{Processes: processes}
+		}
+		ptys, err := client.ListPTYs()
+		if err != nil {
+			return TitleProcessesMsg{Processes: processes, Err: err}
+		}
+		maps.Copy(processes, remoteTitleProcesses(remote, ptys))
+		return TitleProcessesMsg{Processes: processes}
+	}
+}
+
+// remoteTitleProcesses returns the title fields of the panes of a session
+// from the PTYs the daemon listed.
+func remoteTitleProcesses(panes []*terminal.Window, ptys []session.PTYInfo) map[string]titleProcess {
+	processes := make(map[string]titleProcess, len(panes))
+	for _, pane := range panes {
+		i := slices.IndexFunc(ptys, func(pty session.PTYInfo) bool { return pty.ID == pane.PTYID })
+		if i < 0 {
+			continue
+		}
+		process := titleProcess{Cwd: ptys[i].Cwd}
+		if len(ptys[i].Command) > 0 {
+			process.Command = filepath.Base(ptys[i].Command[0])
+		}
+		processes[pane.ID] = process
+	}
+	return processes
+}
+
+// ApplyTitleProcesses stores the title fields sampled by
+// sampleTitleProcesses; the next refresh shows them.
+func (m *OS) ApplyTitleProcesses(msg TitleProcessesMsg) {
+	m.titleSampling = false
+	if msg.Err != nil {
+		m.LogError("Failed to list PTYs: %v", msg.Err)
+		return
+	}
+	m.titleProcesses = msg.Processes
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/Gaurav-Gosain/tuios/internal/tape"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestTitleBarsUseSampledProcesses(t *testing.T) {
+	before := config.TitleFormat
+	defer func() { config.TitleFormat = before }()
+	config.TitleFormat = "{name}[ · {command}][ {flags}]"
+
+	m := NewOS(OSOptions{})
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", CustomName: "one", Workspace: 1, Group: "g", PTYID: "pty-one", Terminal: vt.NewEmulator(10, 2)},
+		{ID: "window-two-0000", CustomName: "two", Workspace: 1, Group: "g", PTYID: "pty-two", Terminal: vt.NewEmulator(10, 2)},
+	}
+	m.FocusedWindow = 0
+	m.TapeRecorder = tape.NewRecorder()
+	m.TapeRecorder.Start()
+
+	// The process fields come from one sample, not from the title itself
+	if changed, _ := m.RefreshTitleBars(time.Now()); !changed || m.Windows[0].TitleBar != "one BT" {
+		t.Fatalf("expected a title with the broadcast and tape flags, got %q", m.Windows[0].TitleBar)
+	}
+	ptys := []session.PTYInfo{
+		{ID: "pty-one", Command: []string{"/usr/bin/vim", "notes.txt"}, Cwd: "/tmp"},
+		{ID: "pty-two"},
+	}
+	m.ApplyTitleProcesses(TitleProcessesMsg{Processes: remoteTitleProcesses([]*terminal.Window{m.Windows[0].ActivePane(), m.Windows[1].ActivePane()}, ptys)})
+	if m.titleSampling {
+		t.Error("expected the sample to be finished")
+	}
+	m.Windows[0].Dirty = false
+	if changed, _ := m.RefreshTitleBars(time.Now()); !changed || !m.Windows[0].Dirty {
+		t.Error("expected the window with a new command marked for redrawing")
+	}
+	if got := m.Windows[0].TitleBar; got != "one · vim BT" {
+		t.Errorf("expected the daemon's command in the title, got %q", got)
+	}
+	if got := m.Windows[1].TitleBar; got != "two B" {
+		t.Errorf("expected no command for a window running its shell, got %q", got)
+	}
+	if m.titleWorkingDirectory(m.Windows[0].ActivePane()) != "/tmp" {
+		t.Error("expected the daemon's working directory")
+	}
+}
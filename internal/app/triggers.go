@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// triggerNotificationWidth is the width matched lines are cut to in trigger
+// notifications.
+const triggerNotificationWidth = 80
+
+// RunTriggers runs the actions of the triggers that window output matched
+// since the last call. It reports whether any window changed.
+func (m *OS) RunTriggers() bool {
+	changed := false
+	// Focusing a window restacks the windows
+	for _, w := range slices.Clone(m.Windows) {
+		for _, t := range badgeTerminals(w) {
+			for _, hit := range t.TakeTriggerHits() {
+				if !hit.Trigger.AppliesTo(w.CustomName, w.Title) {
+					continue
+				}
+				if m.runTrigger(w, hit) {
+					changed = true
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// runTrigger runs a trigger's action for a line a window printed. It reports
+// whether the window changed.
+func (m *OS) runTrigger(w *terminal.Window, hit terminal.TriggerHit) bool {
+	i := slices.Index(m.Windows, w)
+	if i < 0 {
+		return false
+	}
+	inView := i == m.FocusedWindow && w.Workspace == m.CurrentWorkspace && !w.Minimized
+	switch hit.Trigger.Action {
+	case "urgent":
+		// Only windows out of view keep a bell badge
+		if inView {
+			return false
+		}
+		w.Bells++
+		markUrgent(w)
+		return true
+	case "focus":
+		if inView {
+			return false
+		}
+		m.JumpToWindow(i)
+		return true
+	case "hook":
+		m.runTriggerHook(w, hit)
+		return false
+	default:
+		line := ansi.Truncate(hit.Line, triggerNotificationWidth, "…")
+		m.ShowNotification(fmt.Sprintf("%s: %s", m.getWindowDisplayName(w), line), "warning", config.NotificationDuration)
+		return true
+	}
+}
+
+// runTriggerHook starts a trigger's command in the window's working
+// directory, with the window and the matched line in its environment.
+func (m *OS) runTriggerHook(w *terminal.Window, hit terminal.TriggerHit) {
+	ctx, cancel := context.WithTimeout(context.Background(), RunCommandTimeout)
+	cmd := shellCommand(ctx, hit.Trigger.Command)
+	cmd.Dir = w.WorkingDirectory()
+	cmd.Env = append(os.Environ(),
+		"TUIOS_WINDOW_ID="+w.ID,
+		"TUIOS_WINDOW_NAME="+m.getWindowDisplayName(w),
+		"TUIOS_TRIGGER_LINE="+hit.Line,
+		"TUIOS_TRIGGER_MATCH="+hit.Match,
+	)
+	if err := cmd.Start(); err != nil {
+		cancel()
+		m.LogError("Failed to run trigger hook: %v", err)
+		return
+	}
+	go func() {
+		defer cancel()
+		_ = cmd.Wait()
+	}()
+}
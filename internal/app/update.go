@@ -2,12 +2,17 @@ package app
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/session"
 	"github.com/Gaurav-Gosain/tuios/internal/tape"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
 )
 
 // TickerMsg represents a periodic tick event for updating the UI.
@@ -39,6 +44,10 @@ type RemoteCommandMsg struct {
 	ConfigPath  string   // For set_config
 	ConfigValue string   // For set_config
 	RequestID   string   // For response tracking
+	SourcePTYID string   // PTY of the window the command was run from ("" when run from outside the session)
+	CallerPID   int      // Process that made the request, 0 when unknown
+
+	Window *session.WindowState // For adopt_window (window moved in from another session)
 }
 
 // RemoteKeyMsg represents a single key to be processed from a remote send-keys command.
@@ -138,6 +147,17 @@ func (m *OS) Init() tea.Cmd {
 		ListenForWindowExits(m.WindowExitChan),
 	}
 
+	// Ask the host for its colors so apps querying theirs get the right answer,
+	// and give the daemon the theme's until the host answers
+	cmds = append(cmds, tea.RequestForegroundColor, tea.RequestBackgroundColor)
+	m.SyncColorsToDaemon()
+
+	// Draw frames atomically when the startup probe found synchronized
+	// output (DEC 2026), over SSH too
+	if cmd := syncOutputCmd(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
 	// Listen for state sync from other clients (daemon/SSH/web mode)
 	if m.StateSyncChan != nil {
 		cmds = append(cmds, ListenForStateSync(m.StateSyncChan))
@@ -260,12 +280,20 @@ func TriggerAltScreenRedrawCmd() tea.Cmd {
 // Update handles all incoming messages and updates the application state.
 // It processes keyboard, mouse, and timer events, managing windows and UI updates.
 func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	// The outputs not in focus are drawn from the state this left
+	m.renderInactiveOutputs()
+	return model, cmd
+}
+
+func (m *OS) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case TickerMsg:
 		// Proactively check for exited processes and clean them up
 		// This ensures windows close even if the exit channel message was missed
 		for i := len(m.Windows) - 1; i >= 0; i-- {
-			if m.Windows[i].ProcessExited {
+			m.closeExitedPanes(m.Windows[i])
+			if m.Windows[i].ProcessExited && !m.Windows[i].HasPanes() && !m.Windows[i].HasTabs() {
 				m.DeleteWindow(i)
 			}
 		}
@@ -277,6 +305,24 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.UpdateCPUHistory()
 		m.UpdateRAMUsage()
 
+		// Keep scrollback memory within the configured budgets
+		m.EnforceScrollbackBudget()
+
+		// Periodically save the layout for crash recovery
+		m.AutosaveLayout()
+
+		// Publish session metrics for the metrics endpoint
+		m.PublishMetrics()
+
+		// Apply edits to the config file
+		m.CheckConfigReload()
+
+		// Report window, pane and workspace focus changes to apps
+		m.SyncTerminalFocus()
+
+		// Close a popup whose timeout has passed
+		m.ExpirePopup()
+
 		// Handle script playback if in script mode
 		cmds := []tea.Cmd{TickCmd()}
 		if m.ScriptMode && !m.ScriptPaused && m.ScriptPlayer != nil {
@@ -323,6 +369,60 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Adaptive polling - slower during interactions for better mouse responsiveness
 		hasChanges := m.MarkTerminalsWithNewContent()
+		if m.AdvanceReplays() {
+			hasChanges = true
+		}
+		if m.UpdateScreensaver(time.Time(msg)) {
+			hasChanges = true
+		}
+		if m.RefreshWindowApps(time.Time(msg)) {
+			hasChanges = true
+		}
+		if m.RefreshWindowNames(time.Time(msg)) {
+			hasChanges = true
+		}
+		m.RefreshScrollbackLimits(time.Time(msg))
+		titlesChanged, titleCmd := m.RefreshTitleBars(time.Time(msg))
+		if titlesChanged {
+			hasChanges = true
+		}
+		if titleCmd != nil {
+			cmds = append(cmds, titleCmd)
+		}
+		if m.UpdateWindowBadges() {
+			hasChanges = true
+		}
+		if m.RefreshSecureInput() {
+			hasChanges = true
+		}
+		if m.FitWindowsToDock() {
+			hasChanges = true
+		}
+		if infoCmd := m.RefreshWindowInfo(time.Time(msg)); infoCmd != nil {
+			cmds = append(cmds, infoCmd)
+		}
+		if usageCmd := m.SampleWindowUsage(time.Time(msg)); usageCmd != nil {
+			cmds = append(cmds, usageCmd)
+		}
+		if saveCmd := m.SaveRememberedGeometry(time.Time(msg)); saveCmd != nil {
+			cmds = append(cmds, saveCmd)
+		}
+		clipboardCmd, prompted := m.HandleClipboardWrites()
+		if clipboardCmd != nil {
+			cmds = append(cmds, clipboardCmd)
+		}
+		if prompted {
+			hasChanges = true
+		}
+		if m.RunTriggers() {
+			hasChanges = true
+		}
+		if m.CollectCommandHistory() {
+			hasChanges = true
+		}
+		if m.FeedDebugConsoles() {
+			hasChanges = true
+		}
 
 		// Check if we have active animations
 		hasAnimations := m.HasActiveAnimations()
@@ -357,10 +457,16 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case WindowExitMsg:
 		windowID := msg.WindowID
 		for i, w := range m.Windows {
-			if w.ID == windowID {
+			if w.ID != windowID && w.FindPane(windowID) == nil {
+				continue
+			}
+			// A split or tabbed window stays open until its last pane or
+			// tab exits
+			m.closeExitedPanes(w)
+			if !w.HasPanes() && !w.HasTabs() && (w.ID == windowID || w.ProcessExited) {
 				m.DeleteWindow(i)
-				break
 			}
+			break
 		}
 		// Ensure we're in window management mode if no windows remain
 		if len(m.Windows) == 0 {
@@ -373,7 +479,7 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// This prevents the race condition where buffered PTY output overwrites
 		// the restored IsAltScreen state
 		m.LogInfo("[CALLBACKS] Re-enabling callbacks for all windows")
-		for _, w := range m.Windows {
+		for _, w := range m.terminalsWithPanes() {
 			if w.DaemonMode {
 				w.EnableCallbacks()
 				m.LogInfo("[CALLBACKS] Enabled for window %s (IsAltScreen=%v)", w.ID[:8], w.IsAltScreen)
@@ -409,6 +515,10 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyPressMsg, tea.MouseClickMsg, tea.MouseMotionMsg,
 		tea.MouseReleaseMsg, tea.MouseWheelMsg, tea.ClipboardMsg,
 		tea.PasteMsg, tea.PasteStartMsg, tea.PasteEndMsg:
+		// Input first dismisses the screensaver
+		if m.NoteInput(msg) {
+			return m, nil
+		}
 		// Delegate to the registered input handler
 		if inputHandler != nil {
 			return inputHandler(msg, m)
@@ -417,9 +527,11 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.WindowSizeMsg:
 		oldWidth, oldHeight := m.Width, m.Height
+		oldArea := m.windowArea()
 		m.Width = msg.Width
 		m.Height = msg.Height
 		m.MarkAllDirty()
+		compactChanged := m.updateCompactMode()
 
 		// Notify daemon of our terminal size for multi-client size calculation
 		// This allows the daemon to compute effective size = min(all clients)
@@ -433,7 +545,7 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// However, if the size is the same (e.g., web reload), skip retiling to preserve layout.
 		if m.RestoredFromState {
 			m.RestoredFromState = false
-			sizeChanged := oldWidth != msg.Width || oldHeight != msg.Height
+			sizeChanged := oldWidth != msg.Width || oldHeight != msg.Height || compactChanged
 			if sizeChanged {
 				// In daemon mode, don't tile here - wait for SessionResizeMsg with the correct
 				// effective size (min of all clients). Tiling now would use stale EffectiveWidth/Height
@@ -464,13 +576,34 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Retile windows if in tiling mode
+		// Apply a --layout file now that window geometry can be computed
+		if m.PendingLayout != nil {
+			layout := m.PendingLayout
+			m.PendingLayout = nil
+			m.ApplyLayoutFile(layout)
+			return m, nil
+		}
+
+		// Import a --import session bundle now that window geometry can be computed
+		if m.PendingBundle != nil {
+			bundle := m.PendingBundle
+			m.PendingBundle = nil
+			m.ImportSessionBundle(bundle)
+			return m, nil
+		}
+
+		// Fill the workspace TUIOS starts on from its template, now that
+		// window geometry can be computed
+		if oldArea.width <= 0 {
+			m.populateWorkspace(m.CurrentWorkspace)
+		}
+
+		// Retile windows if in tiling mode, and fit the floating ones
 		if m.AutoTiling {
 			m.TileAllWindows()
-		} else if msg.Width < oldWidth || msg.Height < oldHeight {
-			// Terminal got smaller in floating mode - clamp windows back into view
-			m.ClampWindowsToView()
 		}
+		m.fitLayoutToResize(oldArea)
+		m.retileOutputs()
 
 		return m, nil
 
@@ -479,13 +612,27 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.FocusMsg:
-		// Terminal gained focus
-		// Could be used to refresh or resume operations
+		// Terminal gained focus, pass it on to the focused app
+		m.HostBlurred = false
+		m.SyncTerminalFocus()
 		return m, nil
 
 	case tea.BlurMsg:
-		// Terminal lost focus
-		// Could be used to pause expensive operations
+		// Terminal lost focus, pass it on to the focused app
+		m.HostBlurred = true
+		m.SyncTerminalFocus()
+		return m, nil
+
+	case tea.ForegroundColorMsg:
+		// Host foreground color, reported to apps when no theme is set
+		theme.SetHostColors(msg.Color, nil)
+		m.UpdateAllWindowThemes()
+		return m, nil
+
+	case tea.BackgroundColorMsg:
+		// Host background color, which windows are drawn over
+		theme.SetHostColors(nil, msg.Color)
+		m.UpdateAllWindowThemes()
 		return m, nil
 
 	case tea.KeyboardEnhancementsMsg:
@@ -506,7 +653,7 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			oldWindowCount := len(m.Windows)
 			oldWorkspace := m.CurrentWorkspace
 
-			if err := m.ApplyStateSync(msg.State); err != nil {
+			if err := m.ApplyRemoteStateSync(msg.State, msg.SourceID); err != nil {
 				m.LogError("Failed to apply state sync: %v", err)
 			} else {
 				// Show notifications for significant changes
@@ -547,6 +694,7 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ClientLeftMsg:
 		// Another client left the session
+		m.ForgetRemoteFocus(msg.ClientID)
 		m.ShowNotification(fmt.Sprintf("Client left (%d connected)", msg.ClientCount), "info", 2*time.Second)
 		// Continue listening for more client events
 		return m, ListenForClientEvents(m.ClientEventChan)
@@ -555,13 +703,17 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Effective session size changed (min of all clients)
 		// Set the effective size - GetRenderWidth/Height will use min(terminal, effective)
 		if m.EffectiveWidth != msg.Width || m.EffectiveHeight != msg.Height {
+			oldArea := m.windowArea()
 			m.EffectiveWidth = msg.Width
 			m.EffectiveHeight = msg.Height
 			m.MarkAllDirty()
-			// Retile if the effective render size changed
+			m.updateCompactMode()
+			// Retile if the effective render size changed, and fit the floating windows
 			if m.AutoTiling {
 				m.TileAllWindows()
 			}
+			m.fitLayoutToResize(oldArea)
+			m.retileOutputs()
 			// CRITICAL: Force sync all daemon PTY dimensions after tiling
 			// This ensures PTYs match the new window dimensions even if no animation was created
 			// (e.g., when window was already at target position but PTY had stale dimensions)
@@ -618,6 +770,13 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					_ = m.DaemonClient.SendCommandResultWithData(msg.RequestID, true, "command executed", resultData)
 				}
 				return m, nil
+			case "GetStatus":
+				// Return the status line summary (read-only, no notification)
+				resultData = m.GetStatusData()
+				if m.DaemonClient != nil && msg.RequestID != "" {
+					_ = m.DaemonClient.SendCommandResultWithData(msg.RequestID, true, "command executed", resultData)
+				}
+				return m, nil
 			case "GetWindow":
 				// Return info about a specific window (read-only, no notification)
 				if len(msg.TapeArgs) > 0 {
@@ -634,6 +793,214 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				return m, nil
+			case "ApplyLayout":
+				if len(msg.TapeArgs) == 0 {
+					err = fmt.Errorf("ApplyLayout requires a layout file path")
+					break
+				}
+				lf, loadErr := config.LoadLayoutFile(msg.TapeArgs[0])
+				if loadErr != nil {
+					err = loadErr
+					break
+				}
+				created := m.ApplyLayoutToCurrentWorkspace(lf)
+				resultData = map[string]any{"windows_created": created}
+			case "ExportSession":
+				if len(msg.TapeArgs) == 0 {
+					err = fmt.Errorf("ExportSession requires a file path")
+					break
+				}
+				windows, exportErr := m.ExportSessionBundle(msg.TapeArgs[0])
+				if exportErr != nil {
+					err = exportErr
+					break
+				}
+				resultData = map[string]any{"path": msg.TapeArgs[0], "windows": windows}
+			case "OpenImage":
+				if len(msg.TapeArgs) == 0 {
+					err = fmt.Errorf("OpenImage requires an image file path")
+					break
+				}
+				windowID, openErr := m.OpenImage(msg.TapeArgs[0])
+				if openErr != nil {
+					err = openErr
+					break
+				}
+				resultData = map[string]any{"window_id": windowID}
+			case "RunInNewWindow":
+				if len(msg.TapeArgs) == 0 {
+					err = fmt.Errorf("RunInNewWindow requires a command")
+					break
+				}
+				dir := ""
+				if len(msg.TapeArgs) > 1 {
+					dir = msg.TapeArgs[1]
+				}
+				windowID, displayName, runErr := m.RunInNewWindow(msg.TapeArgs[0], dir)
+				if runErr != nil {
+					err = runErr
+					break
+				}
+				m.swallowParentWindow(msg.SourcePTYID, msg.CallerPID, windowID)
+				resultData = map[string]any{"window_id": windowID, "name": displayName}
+			case "ExecInWindow":
+				// Args: window=<ID, name or tag: selector>, then the command
+				selector, command := ExecArgs(msg.TapeArgs)
+				if command == "" {
+					err = fmt.Errorf("ExecInWindow requires a command")
+					break
+				}
+				count, execErr := m.ExecInWindow(selector, command)
+				if execErr != nil {
+					err = execErr
+					break
+				}
+				resultData = map[string]any{"windows": count}
+			case "RenameWindowByID":
+				if len(msg.TapeArgs) < 2 {
+					err = fmt.Errorf("RenameWindowByID requires a window ID and a name")
+					break
+				}
+				if !slices.ContainsFunc(m.Windows, func(w *terminal.Window) bool { return w.ID == msg.TapeArgs[0] }) {
+					err = fmt.Errorf("window not found: %s", msg.TapeArgs[0])
+					break
+				}
+				err = m.RenameWindowByID(msg.TapeArgs[0], msg.TapeArgs[1])
+				if err == nil && len(msg.TapeArgs) > 2 {
+					err = m.SetWindowIconByID(msg.TapeArgs[0], msg.TapeArgs[2])
+				}
+			case "SetWindowIcon":
+				if len(msg.TapeArgs) < 2 {
+					err = fmt.Errorf("SetWindowIcon requires a window ID and an icon")
+					break
+				}
+				err = m.SetWindowIconByID(msg.TapeArgs[0], msg.TapeArgs[1])
+			case "SetClipboardPolicy":
+				if len(msg.TapeArgs) < 2 {
+					err = fmt.Errorf("SetClipboardPolicy requires a window ID and a policy")
+					break
+				}
+				policy := msg.TapeArgs[1]
+				if policy == "default" {
+					policy = ""
+				}
+				err = m.SetClipboardPolicy(msg.TapeArgs[0], policy)
+			case "ShowPopup":
+				// Args: title, body, timeout, then the choices
+				if len(msg.TapeArgs) < 2 {
+					err = fmt.Errorf("ShowPopup requires a title and a body")
+					break
+				}
+				choices := msg.TapeArgs[min(3, len(msg.TapeArgs)):]
+				timeout := time.Duration(0)
+				if len(choices) == 0 {
+					timeout = PopupDefaultTimeout
+				}
+				if len(msg.TapeArgs) > 2 && msg.TapeArgs[2] != "" {
+					var parseErr error
+					if timeout, parseErr = time.ParseDuration(msg.TapeArgs[2]); parseErr != nil || timeout < 0 {
+						err = fmt.Errorf("invalid timeout: %s", msg.TapeArgs[2])
+						break
+					}
+				}
+				if len(choices) == 0 {
+					m.ShowPopup(msg.TapeArgs[0], msg.TapeArgs[1], timeout, nil, "")
+					break
+				}
+				// The request is answered with the choice once the popup closes
+				m.ShowPopup(msg.TapeArgs[0], msg.TapeArgs[1], timeout, choices, msg.RequestID)
+				return m, nil
+			case "RenameWorkspace":
+				if len(msg.TapeArgs) == 0 {
+					err = fmt.Errorf("RenameWorkspace requires a name")
+					break
+				}
+				// With only a name, rename the workspace the command was run from
+				workspace, name := m.sourceWorkspace(msg.SourcePTYID), msg.TapeArgs[0]
+				if len(msg.TapeArgs) > 1 {
+					var convErr error
+					if workspace, convErr = strconv.Atoi(msg.TapeArgs[0]); convErr != nil {
+						err = fmt.Errorf("invalid workspace: %s", msg.TapeArgs[0])
+						break
+					}
+					name = msg.TapeArgs[1]
+				}
+				err = m.RenameWorkspace(workspace, name)
+			case "MoveWindowByID":
+				if len(msg.TapeArgs) < 2 {
+					err = fmt.Errorf("MoveWindowByID requires a window ID and a workspace")
+					break
+				}
+				workspace, convErr := strconv.Atoi(msg.TapeArgs[1])
+				if convErr != nil {
+					err = fmt.Errorf("invalid workspace: %s", msg.TapeArgs[1])
+					break
+				}
+				if strings.HasPrefix(msg.TapeArgs[0], tagSelectorPrefix) {
+					err = m.forEachTagged(msg.TapeArgs[0], func(windowID string) error {
+						return m.MoveWindowToWorkspaceByID(windowID, workspace)
+					})
+					break
+				}
+				err = m.MoveWindowToWorkspaceByID(msg.TapeArgs[0], workspace)
+			case "TagWindow":
+				// Args: a window ID, name or tag: selector, then the tags
+				if len(msg.TapeArgs) < 2 {
+					err = fmt.Errorf("TagWindow requires a window and at least one tag")
+					break
+				}
+				count, tagErr := m.TagWindows(msg.TapeArgs[0], ParseTags(msg.TapeArgs[1:]...))
+				if tagErr != nil {
+					err = tagErr
+					break
+				}
+				resultData = map[string]any{"windows": count}
+			case "UntagWindow":
+				// Args: a window ID, name or tag: selector, then the tags (none removes all)
+				if len(msg.TapeArgs) < 1 {
+					err = fmt.Errorf("UntagWindow requires a window")
+					break
+				}
+				count, tagErr := m.UntagWindows(msg.TapeArgs[0], ParseTags(msg.TapeArgs[1:]...))
+				if tagErr != nil {
+					err = tagErr
+					break
+				}
+				resultData = map[string]any{"windows": count}
+			case "ShowTagView":
+				if len(msg.TapeArgs) < 1 {
+					err = fmt.Errorf("ShowTagView requires a tag")
+					break
+				}
+				err = m.ShowTagView(msg.TapeArgs[0])
+			case "HideTagView":
+				m.HideTagView()
+			case "FindOnScreen":
+				if len(msg.TapeArgs) < 1 {
+					err = fmt.Errorf("FindOnScreen requires the text to find")
+					break
+				}
+				windowID, findErr := m.FindOnScreen(strings.Join(msg.TapeArgs, " "))
+				if findErr != nil {
+					err = findErr
+					break
+				}
+				resultData = map[string]any{"window_id": windowID}
+			case "GatherWindows":
+				resultData = map[string]any{"gathered": m.GatherWindows()}
+			case "MoveWindowToSession":
+				// Args: the session, or a window ID and the session
+				if len(msg.TapeArgs) == 0 {
+					err = fmt.Errorf("MoveWindowToSession requires a session name")
+					break
+				}
+				windowID, target := "", msg.TapeArgs[len(msg.TapeArgs)-1]
+				if len(msg.TapeArgs) > 1 {
+					windowID = msg.TapeArgs[0]
+				} else if focused := m.GetFocusedWindow(); focused != nil {
+					windowID = focused.ID
+				}
+				err = m.MoveWindowToSession(windowID, target)
 			default:
 				// Handle tape commands that return data specially
 				switch tape.CommandType(msg.TapeCommand) {
@@ -647,6 +1014,7 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if createErr != nil {
 						err = createErr
 					} else {
+						m.swallowParentWindow(msg.SourcePTYID, msg.CallerPID, windowID)
 						resultData = map[string]any{
 							"window_id": windowID,
 							"name":      displayName,
@@ -687,6 +1055,9 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.AutoTiling {
 				m.TileAllWindows()
 			}
+		case "adopt_window":
+			// A window was moved in from another session
+			err = m.AdoptWindow(msg.Window)
 		case "tape_script":
 			// Execute a full tape script
 			notificationMsg = "Remote: executing tape script"
@@ -852,6 +1223,31 @@ func (m *OS) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 		return m, doneCmd
 
+	case PasteChunkMsg:
+		return m, m.handlePasteChunk(msg)
+
+	case RunCommandDoneMsg:
+		m.ShowRunOutput(msg)
+		return m, nil
+
+	case TitleProcessesMsg:
+		m.ApplyTitleProcesses(msg)
+		return m, nil
+
+	case WindowInfoMsg:
+		m.ApplyWindowInfo(msg)
+		return m, nil
+
+	case WindowUsageMsg:
+		m.ApplyWindowUsage(msg)
+		return m, nil
+
+	case GeometrySavedMsg:
+		if msg.Err != nil {
+			m.LogError("Failed to save window geometry: %v", msg.Err)
+		}
+		return m, nil
+
 	case RemoteTapeScriptDoneMsg:
 		// All tape commands have been processed - do final cleanup
 		// Re-enable animations
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// windowHintLabels are the keys shown on windows in jump mode, in window
+// order. The digits match the prefix window selection keys.
+const windowHintLabels = "123456789abcdefghijklmnopqrstuvwxyz"
+
+// windowHintTargets returns the indices of the windows that get a jump
+// label: the visible windows of the current workspace, in window order.
+func (m *OS) windowHintTargets() []int {
+	var targets []int
+	for i, w := range m.Windows {
+		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing {
+			targets = append(targets, i)
+			if len(targets) == len(windowHintLabels) {
+				break
+			}
+		}
+	}
+	return targets
+}
+
+// ToggleWindowHints shows or hides a jump label on every visible window.
+func (m *OS) ToggleWindowHints() {
+	if m.ShowWindowHints {
+		m.ShowWindowHints = false
+		return
+	}
+	if len(m.windowHintTargets()) == 0 {
+		m.ShowNotification("No windows to jump to", "info", config.NotificationDuration)
+		return
+	}
+	m.ShowWindowHints = true
+}
+
+// SelectWindowHint focuses the window labelled key and leaves jump mode.
+// It reports whether a window had that label.
+func (m *OS) SelectWindowHint(key string) bool {
+	m.ShowWindowHints = false
+	if len(key) != 1 {
+		return false
+	}
+	n := strings.Index(windowHintLabels, key)
+	targets := m.windowHintTargets()
+	if n < 0 || n >= len(targets) {
+		return false
+	}
+	m.FocusWindow(targets[n])
+	m.MarkAllDirty()
+	return true
+}
+
+// renderWindowHints renders a large label centered on every window that can
+// be jumped to.
+func (m *OS) renderWindowHints() []*lipgloss.Layer {
+	hintStyle := lipgloss.NewStyle().
+		Border(lipgloss.ThickBorder()).
+		Padding(1, 3).
+		Bold(true).
+		Foreground(lipgloss.Color("#000000")).
+		Background(lipgloss.Color("11")).
+		BorderForeground(lipgloss.Color("11"))
+	focusedStyle := hintStyle.
+		Background(lipgloss.Color("14")).
+		BorderForeground(lipgloss.Color("14"))
+
+	var layers []*lipgloss.Layer
+	for n, i := range m.windowHintTargets() {
+		w := m.Windows[i]
+		style := hintStyle
+		if i == m.FocusedWindow {
+			style = focusedStyle
+		}
+		hint := style.Render(string(windowHintLabels[n]))
+		x := max(w.X+(w.Width-lipgloss.Width(hint))/2, 0)
+		y := max(w.Y+(w.Height-lipgloss.Height(hint))/2, 0)
+		layers = append(layers, lipgloss.NewLayer(hint).
+			X(x).Y(y).Z(config.ZIndexLogs).ID("window-hint-"+w.ID))
+	}
+	return layers
+}
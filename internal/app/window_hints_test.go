@@ -0,0 +1,40 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestWindowHints(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", Width: 40, Height: 20, Workspace: 2},
+		{ID: "window-three-00", Width: 40, Height: 20, Workspace: 1, Minimized: true},
+		{ID: "window-four-000", Width: 40, Height: 20, Workspace: 1},
+	}
+	m.FocusedWindow = 0
+
+	m.ToggleWindowHints()
+	if !m.ShowWindowHints {
+		t.Fatal("expected jump labels to be shown")
+	}
+	if layers := m.renderWindowHints(); len(layers) != 2 {
+		t.Errorf("expected labels on the 2 visible windows, got %d", len(layers))
+	}
+
+	// The second label belongs to the second visible window of the workspace
+	if !m.SelectWindowHint("2") || m.FocusedWindow != 3 {
+		t.Errorf("expected label 2 to focus window four, focused %d", m.FocusedWindow)
+	}
+	if m.ShowWindowHints {
+		t.Error("expected jump mode to end after a selection")
+	}
+
+	m.ToggleWindowHints()
+	if m.SelectWindowHint("3") || m.ShowWindowHints || m.FocusedWindow != 3 {
+		t.Error("expected an unused label to cancel jump mode without changing focus")
+	}
+}
@@ -0,0 +1,218 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// maxScrollbackLimit is the largest scrollback limit that can be set for a
+// window, matching the range allowed for appearance.scrollback_lines.
+const maxScrollbackLimit = 1000000
+
+// maxInfoProcesses is the number of processes the window info overlay lists
+// at most, the shell and what runs below it.
+const maxInfoProcesses = 12
+
+// windowInfoState holds the processes of the pane shown in the window info
+// overlay, looked up again about once a second while it is shown.
+type windowInfoState struct {
+	PID       int              // Process ID of the pane's shell (0 when unknown)
+	Started   time.Time        // When the shell started
+	Processes []infoProcess    // Shell and the processes below it, depth first
+	More      bool             // More processes run than are listed
+	refreshed time.Time        // When the processes were last looked up
+	looking   bool             // Whether a lookup is running
+	pane      *terminal.Window // Pane whose processes these are
+}
+
+// WindowInfoMsg carries the processes of the pane in the window info
+// overlay, looked up by RefreshWindowInfo.
+type WindowInfoMsg struct {
+	PaneID    string        // Pane whose processes these are
+	Started   time.Time     // When the shell started, zero when not looked up
+	Processes []infoProcess // Shell and the processes below it, depth first
+	More      bool          // More processes run than are listed
+	Err       error
+}
+
+// infoProcess is a process listed in the window info overlay.
+type infoProcess struct {
+	PID    int32
+	Name   string
+	Memory uint64 // Resident memory in bytes
+	Depth  int    // Levels below the shell
+}
+
+// ToggleWindowInfo shows or hides the info overlay for the focused pane.
+func (m *OS) ToggleWindowInfo() {
+	m.ShowWindowInfo = !m.ShowWindowInfo && m.GetFocusedPane() != nil
+	m.WindowInfoEditing = false
+	m.WindowInfoBuffer = ""
+	m.windowInfo = nil
+	if m.ShowWindowInfo {
+		// The processes are looked up from the next tick
+		pane := m.GetFocusedPane()
+		m.windowInfo = &windowInfoState{pane: pane, PID: m.shellPID(pane)}
+	}
+}
+
+// RefreshWindowInfo returns a command that looks up the processes of the
+// pane in the window info overlay when it opens and about once a second
+// while it is shown, or nil when no lookup is due. ApplyWindowInfo takes
+// the result.
+func (m *OS) RefreshWindowInfo(now time.Time) tea.Cmd {
+	pane := m.GetFocusedPane()
+	if !m.ShowWindowInfo || pane == nil {
+		m.windowInfo = nil
+		return nil
+	}
+	info := m.windowInfo
+	if info == nil || info.pane != pane {
+		info = &windowInfoState{pane: pane, PID: m.shellPID(pane)}
+		m.windowInfo = info
+	} else if info.looking || now.Sub(info.refreshed) < titleRefreshInterval {
+		return nil
+	}
+	if info.PID == 0 {
+		// The shell of a session's window is known once the window usage
+		// has been sampled
+		info.PID = m.shellPID(pane)
+	}
+	info.refreshed = now
+	info.looking = true
+	paneID, pid, started := pane.ID, int32(info.PID), !info.Started.IsZero()
+	return func() tea.Msg {
+		msg := WindowInfoMsg{PaneID: paneID}
+		children, err := processChildren()
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		msg.Processes, msg.More = processTree(pid, maxInfoProcesses, children)
+		if !started && pid > 0 {
+			if proc, err := process.NewProcess(pid); err == nil {
+				if created, err := proc.CreateTime(); err == nil {
+					msg.Started = time.UnixMilli(created)
+				}
+			}
+		}
+		return msg
+	}
+}
+
+// ApplyWindowInfo stores the processes looked up by RefreshWindowInfo, unless
+// the overlay has moved on to another pane since.
+func (m *OS) ApplyWindowInfo(msg WindowInfoMsg) {
+	info := m.windowInfo
+	if info == nil || info.pane.ID != msg.PaneID {
+		return
+	}
+	info.looking = false
+	if msg.Err != nil {
+		m.LogError("Failed to list processes: %v", msg.Err)
+		return
+	}
+	info.Processes, info.More = msg.Processes, msg.More
+	if !msg.Started.IsZero() {
+		info.Started = msg.Started
+	}
+}
+
+// shellPID returns the process ID of a pane's shell: the process tuios
+// started for local windows, or the one the daemon reported for the windows
+// of a session, which SampleWindowUsage asks for. It returns 0 when it is
+// not known yet.
+func (m *OS) shellPID(pane *terminal.Window) int {
+	if pane.Cmd != nil && pane.Cmd.Process != nil {
+		return pane.Cmd.Process.Pid
+	}
+	return m.usage.ptyPIDs[pane.PTYID]
+}
+
+// processTree returns the process pid and the processes below it, depth
+// first and by process ID, up to limit of them, given the children of every
+// process from processChildren. It reports whether more processes were left
+// out.
+func processTree(pid int32, limit int, children map[int32][]int32) ([]infoProcess, bool) {
+	if pid <= 0 {
+		return nil, false
+	}
+	if _, err := process.NewProcess(pid); err != nil {
+		return nil, false
+	}
+	var tree []infoProcess
+	more := false
+	var walk func(pid int32, depth int)
+	walk = func(pid int32, depth int) {
+		if len(tree) >= limit {
+			more = true
+			return
+		}
+		entry := infoProcess{PID: pid, Depth: depth}
+		if proc, err := process.NewProcess(pid); err == nil {
+			entry.Name, _ = proc.Name()
+			if mem, err := proc.MemoryInfo(); err == nil {
+				entry.Memory = mem.RSS
+			}
+		}
+		tree = append(tree, entry)
+		below := slices.Clone(children[pid])
+		slices.Sort(below)
+		for _, child := range below {
+			walk(child, depth+1)
+		}
+	}
+	walk(pid, 0)
+	return tree, more
+}
+
+// SetScrollbackOverride sets the scrollback limit of the focused pane. Zero
+// disables its scrollback and nil returns it to the window rules and the
+// global limit. Lowering the limit discards the oldest lines.
+func (m *OS) SetScrollbackOverride(lines *int) {
+	pane := m.GetFocusedPane()
+	if pane == nil {
+		return
+	}
+	pane.ScrollbackOverride = lines
+	pane.ApplyScrollbackLimit()
+	pane.InvalidateCache()
+
+	limit, source := pane.ScrollbackLimit()
+	switch {
+	case lines == nil:
+		m.ShowNotification(fmt.Sprintf("Scrollback: %d lines (%s)", limit, source), "info", config.NotificationDuration)
+	case limit == 0:
+		m.ShowNotification("Scrollback: disabled for this window", "info", config.NotificationDuration)
+	default:
+		m.ShowNotification(fmt.Sprintf("Scrollback: %d lines for this window", limit), "info", config.NotificationDuration)
+	}
+	m.SyncStateToDaemon()
+}
+
+// ApplyWindowInfoInput sets the scrollback limit typed in the window info
+// overlay. An empty input clears the window's own limit.
+func (m *OS) ApplyWindowInfoInput() {
+	input := strings.TrimSpace(m.WindowInfoBuffer)
+	m.WindowInfoEditing = false
+	m.WindowInfoBuffer = ""
+
+	if input == "" {
+		m.SetScrollbackOverride(nil)
+		return
+	}
+	lines, err := strconv.Atoi(input)
+	if err != nil || lines < 0 || lines > maxScrollbackLimit {
+		m.ShowNotification(fmt.Sprintf("Scrollback limit must be 0 to %d lines", maxScrollbackLimit), "error", config.NotificationDuration)
+		return
+	}
+	m.SetScrollbackOverride(&lines)
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestProcessTree(t *testing.T) {
+	children, err := processChildren()
+	if err != nil {
+		t.Skipf("Processes cannot be listed: %v", err)
+	}
+	pid := os.Getpid()
+	tree, _ := processTree(int32(pid), maxInfoProcesses, children)
+	if len(tree) == 0 {
+		t.Skip("Processes cannot be inspected")
+	}
+	if tree[0].PID != int32(pid) || tree[0].Depth != 0 || tree[0].Name == "" {
+		t.Errorf("expected the tree to start with this process, got %+v", tree[0])
+	}
+	if tree, more := processTree(int32(pid), 0, children); len(tree) != 0 || !more {
+		t.Errorf("expected no processes and more left out with a limit of 0, got %d, %v", len(tree), more)
+	}
+	if tree, _ := processTree(0, maxInfoProcesses, children); tree != nil {
+		t.Errorf("expected no processes for an unknown shell, got %v", tree)
+	}
+}
+
+func TestRefreshWindowInfoLooksUpInCommand(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.CurrentWorkspace = 1
+	pane := &terminal.Window{ID: "window-info-000", Terminal: vt.NewEmulator(20, 5), Workspace: 1}
+	m.Windows = []*terminal.Window{pane}
+	m.FocusedWindow = 0
+
+	if cmd := m.RefreshWindowInfo(time.Now()); cmd != nil {
+		t.Error("expected no lookup while the overlay is hidden")
+	}
+	m.ToggleWindowInfo()
+	now := time.Now()
+	cmd := m.RefreshWindowInfo(now)
+	if cmd == nil {
+		t.Fatal("expected a lookup once the overlay is shown")
+	}
+	if m.RefreshWindowInfo(now.Add(2*titleRefreshInterval)) != nil {
+		t.Error("expected no second lookup while one runs")
+	}
+
+	msg, ok := cmd().(WindowInfoMsg)
+	if !ok || msg.PaneID != pane.ID {
+		t.Fatalf("lookup returned %#v, want the processes of the pane", msg)
+	}
+	msg.Processes = []infoProcess{{PID: 42, Name: "sh"}}
+	m.Update(msg)
+	if info := m.windowInfo; len(info.Processes) != 1 || info.Processes[0].Name != "sh" || info.looking {
+		t.Errorf("window info = %+v, want the looked up processes", info)
+	}
+	if m.RefreshWindowInfo(now.Add(2*titleRefreshInterval)) == nil {
+		t.Error("expected the processes looked up again after the interval")
+	}
+
+	// A lookup for a pane no longer shown is dropped
+	m.Update(WindowInfoMsg{PaneID: "window-gone-000", Processes: []infoProcess{{PID: 7}}})
+	if m.windowInfo.Processes[0].PID != 42 {
+		t.Error("expected the result for another pane dropped")
+	}
+}
@@ -0,0 +1,223 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// usageSampleInterval is how often the CPU and memory use of the windows is
+// sampled while the sidebar or the window info overlay shows it.
+const usageSampleInterval = 2 * time.Second
+
+// WindowUsage is the CPU and memory used by the processes of a window: the
+// shells of its panes and tabs and everything running below them.
+type WindowUsage struct {
+	CPU    float64 // Percent of one CPU over the last sample interval
+	Memory uint64  // Resident memory in bytes
+}
+
+// usageSampler holds the last sample of the CPU and memory use of the
+// windows, and what the next sample needs to work out CPU use.
+type usageSampler struct {
+	at       time.Time              // When the last sample was taken
+	ready    bool                   // CPU use is known: the last sample had one before it
+	sampling bool                   // Whether a sample is being taken
+	cpuTime  map[int32]float64      // CPU seconds each process had used at the last sample
+	cpu      map[int32]float64      // CPU percent of each process over the last interval
+	windows  map[string]WindowUsage // Window ID -> usage
+	ptyPIDs  map[string]int         // PTY ID -> process ID of its shell, for the windows of a session
+}
+
+// WindowUsageMsg carries a sample of the CPU and memory use of the windows,
+// taken by SampleWindowUsage.
+type WindowUsageMsg struct {
+	At      time.Time              // When the sample was started
+	Ready   bool                   // CPU use is known: the sample had one before it
+	CPUTime map[int32]float64      // CPU seconds each process had used
+	CPU     map[int32]float64      // CPU percent of each process since the last sample
+	Windows map[string]WindowUsage // Window ID -> usage
+	PTYPIDs map[string]int         // PTY ID -> shell process ID, when the daemon was asked
+	Err     error
+}
+
+// usageShell is the shell of a pane or tab a usage sample looks at: its
+// process ID, or 0 with the PTY whose shell the daemon is asked for.
+type usageShell struct {
+	pid   int32
+	ptyID string
+}
+
+// SampleWindowUsage returns a command that samples the CPU and memory used
+// by the processes of every window every usageSampleInterval, while the
+// sidebar or the window info overlay is shown, or nil when no sample is due.
+// CPU use is known from the second sample on; after a pause the sampling
+// starts over. ApplyWindowUsage takes the result.
+func (m *OS) SampleWindowUsage(now time.Time) tea.Cmd {
+	if !m.SidebarVisible && !m.ShowWindowInfo {
+		return nil
+	}
+	s := &m.usage
+	gap := now.Sub(s.at)
+	if s.sampling || gap < usageSampleInterval {
+		return nil
+	}
+	first := s.at.IsZero() || gap > 2*usageSampleInterval
+	s.sampling = true
+
+	// The windows of a session learn their shells from the daemon, asked
+	// once for the PTYs it has not told about yet
+	shells := make(map[string][]usageShell, len(m.Windows))
+	var unknown []string
+	for _, w := range m.Windows {
+		for _, t := range badgeTerminals(w) {
+			pid := m.shellPID(t)
+			if _, asked := s.ptyPIDs[t.PTYID]; pid == 0 && m.DaemonClient != nil && t.PTYID != "" && !asked {
+				unknown = append(unknown, t.PTYID)
+			}
+			shells[w.ID] = append(shells[w.ID], usageShell{pid: int32(pid), ptyID: t.PTYID})
+		}
+	}
+	client, prev := m.DaemonClient, s.cpuTime
+	return func() tea.Msg {
+		msg := WindowUsageMsg{At: now, Ready: !first}
+		if len(unknown) > 0 {
+			ptys, err := client.ListPTYs()
+			if err != nil {
+				msg.Err = fmt.Errorf("failed to list PTYs: %w", err)
+				return msg
+			}
+			msg.PTYPIDs = make(map[string]int, len(ptys)+len(unknown))
+			for _, id := range unknown {
+				msg.PTYPIDs[id] = 0
+			}
+			for _, pty := range ptys {
+				msg.PTYPIDs[pty.ID] = pty.PID
+			}
+		}
+		children, err := processChildren()
+		if err != nil {
+			msg.Err = fmt.Errorf("failed to list processes: %w", err)
+			return msg
+		}
+		msg.CPUTime = make(map[int32]float64)
+		msg.CPU = make(map[int32]float64)
+		msg.Windows = make(map[string]WindowUsage, len(shells))
+		for id, list := range shells {
+			var usage WindowUsage
+			for _, shell := range list {
+				if shell.pid == 0 {
+					shell.pid = int32(msg.PTYPIDs[shell.ptyID])
+				}
+				for _, pid := range descendants(shell.pid, children) {
+					proc, err := process.NewProcess(pid)
+					if err != nil {
+						continue
+					}
+					if times, err := proc.Times(); err == nil {
+						msg.CPUTime[pid] = times.User + times.System
+						if before, ok := prev[pid]; ok && !first {
+							msg.CPU[pid] = max(msg.CPUTime[pid]-before, 0) / gap.Seconds() * 100
+							usage.CPU += msg.CPU[pid]
+						}
+					}
+					if mem, err := proc.MemoryInfo(); err == nil {
+						usage.Memory += mem.RSS
+					}
+				}
+			}
+			msg.Windows[id] = usage
+		}
+		return msg
+	}
+}
+
+// ApplyWindowUsage stores a sample taken by SampleWindowUsage. A failed
+// sample is logged and the sampling tries again after usageSampleInterval.
+func (m *OS) ApplyWindowUsage(msg WindowUsageMsg) {
+	s := &m.usage
+	s.sampling = false
+	s.at = msg.At
+	if msg.PTYPIDs != nil {
+		s.ptyPIDs = msg.PTYPIDs
+	}
+	if msg.Err != nil {
+		m.LogError("Failed to sample window usage: %v", msg.Err)
+		return
+	}
+	s.cpuTime, s.cpu, s.windows, s.ready = msg.CPUTime, msg.CPU, msg.Windows, msg.Ready
+}
+
+// windowUsage returns the CPU and memory a window used at the last sample
+// and whether its CPU use is known.
+func (m *OS) windowUsage(id string) (WindowUsage, bool) {
+	usage, ok := m.usage.windows[id]
+	return usage, ok && m.usage.ready
+}
+
+// processCPU returns the CPU percent a process used at the last sample and
+// whether it is known.
+func (m *OS) processCPU(pid int32) (float64, bool) {
+	cpu, ok := m.usage.cpu[pid]
+	return cpu, ok && m.usage.ready
+}
+
+// formatCPU formats a CPU percent for display, e.g. "12%".
+func formatCPU(cpu float64) string {
+	return fmt.Sprintf("%.0f%%", cpu)
+}
+
+// sidebarByCPU returns the windows the sidebar lists, the busiest first.
+// Windows whose CPU use is unknown go last, in their order.
+func (m *OS) sidebarByCPU() []int {
+	var indices []int
+	for i, w := range m.Windows {
+		if m.sidebarShows(w) {
+			indices = append(indices, i)
+		}
+	}
+	cpu := func(i int) float64 {
+		if usage, ok := m.windowUsage(m.Windows[i].ID); ok {
+			return usage.CPU
+		}
+		return -1
+	}
+	sort.SliceStable(indices, func(a, b int) bool { return cpu(indices[a]) > cpu(indices[b]) })
+	return indices
+}
+
+// ToggleSidebarSort switches the sidebar between listing the windows by
+// workspace and listing every window by CPU use, the busiest first.
+func (m *OS) ToggleSidebarSort() {
+	m.SidebarSortCPU = !m.SidebarSortCPU
+}
+
+// processChildren returns the child processes of every running process.
+func processChildren() (map[int32][]int32, error) {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[int32][]int32)
+	for _, proc := range procs {
+		if ppid, err := proc.Ppid(); err == nil && ppid != proc.Pid {
+			children[ppid] = append(children[ppid], proc.Pid)
+		}
+	}
+	return children, nil
+}
+
+// descendants returns the process pid and every process below it.
+func descendants(pid int32, children map[int32][]int32) []int32 {
+	if pid <= 0 {
+		return nil
+	}
+	pids := []int32{pid}
+	for i := 0; i < len(pids); i++ {
+		pids = append(pids, children[pids[i]]...)
+	}
+	return pids
+}
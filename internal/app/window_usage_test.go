@@ -0,0 +1,101 @@
+package app
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestSampleWindowUsageInCommand(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.CurrentWorkspace = 1
+	self, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &terminal.Window{ID: "window-usage-00", Terminal: vt.NewEmulator(20, 5), Workspace: 1, Cmd: &exec.Cmd{Process: self}}
+	m.Windows = []*terminal.Window{w}
+
+	now := time.Now()
+	if m.SampleWindowUsage(now) != nil {
+		t.Error("expected no sample while the sidebar is hidden")
+	}
+	m.SidebarVisible = true
+	cmd := m.SampleWindowUsage(now)
+	if cmd == nil {
+		t.Fatal("expected a sample once the sidebar is shown")
+	}
+	if m.SampleWindowUsage(now.Add(usageSampleInterval)) != nil {
+		t.Error("expected no second sample while one runs")
+	}
+
+	msg, ok := cmd().(WindowUsageMsg)
+	if !ok {
+		t.Fatalf("sample returned %#v, want a WindowUsageMsg", msg)
+	}
+	if msg.Err != nil {
+		t.Skipf("Processes cannot be listed: %v", msg.Err)
+	}
+	m.Update(msg)
+	if _, known := m.windowUsage(w.ID); known {
+		t.Error("expected the CPU use unknown after the first sample")
+	}
+	if usage := m.usage.windows[w.ID]; usage.Memory == 0 {
+		t.Errorf("usage = %+v, want the memory of this process", usage)
+	}
+
+	cmd = m.SampleWindowUsage(now.Add(usageSampleInterval))
+	if cmd == nil {
+		t.Fatal("expected a sample again after the interval")
+	}
+	m.Update(cmd())
+	if _, known := m.windowUsage(w.ID); !known {
+		t.Error("expected the CPU use known from the second sample")
+	}
+}
+
+func TestSidebarByCPU(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.Windows = []*terminal.Window{
+		{ID: "window-idle-000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-busy-000", Width: 40, Height: 20, Workspace: 2},
+		{ID: "window-new-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-some-000", Width: 40, Height: 20, Workspace: 1},
+	}
+	m.usage = usageSampler{ready: true, windows: map[string]WindowUsage{
+		"window-idle-000": {CPU: 0},
+		"window-busy-000": {CPU: 97},
+		"window-some-000": {CPU: 4},
+	}}
+	m.SidebarVisible, m.SidebarFocused = true, true
+
+	if sections := m.sidebarSections(); len(sections) != 2 || sections[0].Workspace != 1 {
+		t.Fatalf("expected the windows listed by workspace, got %v", sections)
+	}
+	m.ToggleSidebarSort()
+	sections := m.sidebarSections()
+	if len(sections) != 1 || !slices.Equal(sections[0].Windows, []int{1, 3, 0, 2}) {
+		t.Fatalf("expected the busiest window first and the unsampled one last, got %v", sections)
+	}
+
+	m.SidebarSelectedIndex = 1
+	m.SidebarSelectNext()
+	if m.SidebarSelectedIndex != 3 {
+		t.Errorf("expected the selection to follow the CPU order, got %d", m.SidebarSelectedIndex)
+	}
+	m.SidebarSelectedIndex = 1
+	m.SidebarSelectPrev()
+	if m.SidebarSelectedIndex != 2 {
+		t.Errorf("expected the selection to wrap to the last listed window, got %d", m.SidebarSelectedIndex)
+	}
+
+	tree := descendants(10, map[int32][]int32{10: {11, 12}, 12: {13}, 20: {21}})
+	if !slices.Equal(tree, []int32{10, 11, 12, 13}) {
+		t.Errorf("expected a shell and every process below it, got %v", tree)
+	}
+}
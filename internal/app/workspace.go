@@ -1,6 +1,11 @@
 package app
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
 	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/tape"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
@@ -9,6 +14,26 @@ import (
 
 // Workspace management methods
 
+// SelectWorkspace switches to a workspace picked by its number. With
+// config.WorkspaceBackAndForth, picking the current workspace switches back
+// to the previous one instead, like i3's workspace_auto_back_and_forth.
+func (m *OS) SelectWorkspace(workspace int) {
+	if workspace == m.CurrentWorkspace && config.WorkspaceBackAndForth {
+		m.SwitchToPreviousWorkspace()
+		return
+	}
+	m.SwitchToWorkspace(workspace)
+}
+
+// SwitchToPreviousWorkspace switches back to the workspace that was active
+// before the current one.
+func (m *OS) SwitchToPreviousWorkspace() {
+	if m.PreviousWorkspace < 1 || m.PreviousWorkspace > m.NumWorkspaces || m.PreviousWorkspace == m.CurrentWorkspace {
+		return
+	}
+	m.SwitchToWorkspace(m.PreviousWorkspace)
+}
+
 // SwitchToWorkspace switches to the specified workspace.
 func (m *OS) SwitchToWorkspace(workspace int) {
 	if workspace < 1 || workspace > m.NumWorkspaces {
@@ -39,7 +64,7 @@ func (m *OS) SwitchToWorkspace(workspace int) {
 		if m.AutoTiling {
 			visibleWindows := make([]int, 0)
 			for i, w := range m.Windows {
-				if w.Workspace == oldWorkspace && !w.Minimized && !w.Minimizing {
+				if w.Workspace == oldWorkspace && !w.Minimized && !w.Minimizing && !w.Floating {
 					visibleWindows = append(visibleWindows, i)
 				}
 			}
@@ -71,13 +96,20 @@ func (m *OS) SwitchToWorkspace(workspace int) {
 
 	// Unsubscribe from old workspace PTYs and subscribe to new workspace PTYs
 	// This optimization reduces network traffic by only streaming output for visible windows
+	// On a split screen the workspace being left may stay on another output
+	stillShown := m.showOnActiveOutput(workspace, oldWorkspace)
 	if m.IsDaemonSession && m.DaemonClient != nil {
-		m.UnsubscribeWorkspaceWindows(oldWorkspace)
+		if !stillShown {
+			m.UnsubscribeWorkspaceWindows(oldWorkspace)
+		}
 		m.SubscribeWorkspaceWindows(workspace)
 	}
 
-	// Switch to new workspace
+	// Switch to new workspace, taking over its tiling mode
+	m.swapWorkspaceTiling(oldWorkspace, workspace)
+	m.PreviousWorkspace = oldWorkspace
 	m.CurrentWorkspace = workspace
+	m.invalidateDockHeight()
 	m.RestoreWorkspaceLayout(workspace) // Restore layout after switching
 
 	// Try to restore previous focus for this workspace
@@ -143,12 +175,157 @@ func (m *OS) SwitchToWorkspace(workspace int) {
 		}
 	}
 
+	m.populateWorkspace(workspace)
+
 	// Sync state to daemon after workspace switch
 	m.SyncStateToDaemon()
 }
 
-// MoveWindowToWorkspace moves a window to the specified workspace without changing focus.
+// WorkspaceAutoTiling reports whether a workspace is in tiling mode.
+func (m *OS) WorkspaceAutoTiling(workspace int) bool {
+	if workspace == m.CurrentWorkspace {
+		return m.AutoTiling
+	}
+	return m.WorkspaceTiling[workspace]
+}
+
+// setWorkspaceAutoTiling sets the tiling mode flag of a workspace without
+// retiling it.
+func (m *OS) setWorkspaceAutoTiling(workspace int, on bool) {
+	if workspace == m.CurrentWorkspace {
+		m.AutoTiling = on
+		return
+	}
+	if m.WorkspaceTiling == nil {
+		m.WorkspaceTiling = make(map[int]bool)
+	}
+	m.WorkspaceTiling[workspace] = on
+}
+
+// RenameWorkspace names a workspace, the name being shown next to its number
+// in the dock and sidebar. An empty name clears it.
+func (m *OS) RenameWorkspace(workspace int, name string) error {
+	if workspace < 1 || workspace > m.NumWorkspaces {
+		return fmt.Errorf("workspace %d out of range (1-%d)", workspace, m.NumWorkspaces)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		delete(m.WorkspaceNames, workspace)
+	} else {
+		if m.WorkspaceNames == nil {
+			m.WorkspaceNames = make(map[int]string)
+		}
+		m.WorkspaceNames[workspace] = name
+	}
+	m.MarkAllDirty()
+	m.SyncStateToDaemon()
+	return nil
+}
+
+// populateWorkspace fills a workspace with the windows of its workspace
+// template the first time it is switched to in the session. A workspace that
+// already has windows then is left as it is, and so is one filled or
+// emptied before.
+func (m *OS) populateWorkspace(workspace int) {
+	if m.PopulatedWorkspaces[workspace] {
+		return
+	}
+	tmpl, ok := config.WorkspaceTemplateFor(workspace, m.WorkspaceNames[workspace])
+	if !ok {
+		return
+	}
+	m.markWorkspacePopulated(workspace)
+	if m.GetWorkspaceWindowCount(workspace) > 0 {
+		return
+	}
+
+	lf, err := config.LoadLayoutFile(tmpl.LayoutPath())
+	if err != nil {
+		m.LogError("Failed to load the template of workspace %d: %v", workspace, err)
+		m.ShowNotification(fmt.Sprintf("Workspace %d template: %v", workspace, err), "error", config.NotificationDuration)
+		return
+	}
+	if tmpl.Workspace != 0 && tmpl.Name != "" && m.WorkspaceNames[workspace] == "" {
+		_ = m.RenameWorkspace(workspace, tmpl.Name)
+	}
+	created := m.ApplyLayoutToCurrentWorkspace(lf)
+	m.LogInfo("Filled workspace %d with %d windows from its template %s", workspace, created, tmpl.Layout)
+}
+
+// markWorkspacePopulated records that a workspace no longer gets the
+// windows of its template.
+func (m *OS) markWorkspacePopulated(workspace int) {
+	if m.PopulatedWorkspaces == nil {
+		m.PopulatedWorkspaces = make(map[int]bool)
+	}
+	m.PopulatedWorkspaces[workspace] = true
+}
+
+// populatedWorkspacesState returns the populated workspaces for the
+// session state, sorted.
+func (m *OS) populatedWorkspacesState() []int {
+	var workspaces []int
+	for ws, populated := range m.PopulatedWorkspaces {
+		if populated {
+			workspaces = append(workspaces, ws)
+		}
+	}
+	slices.Sort(workspaces)
+	return workspaces
+}
+
+// restorePopulatedWorkspaces takes over the populated workspaces of a
+// session state, adding to the ones known.
+func (m *OS) restorePopulatedWorkspaces(workspaces []int) {
+	for _, ws := range workspaces {
+		m.markWorkspacePopulated(ws)
+	}
+}
+
+// sourceWorkspace returns the workspace of the window running the PTY a
+// remote command was run from, or the current workspace when it came from
+// outside the session.
+func (m *OS) sourceWorkspace(sourcePTYID string) int {
+	if sourcePTYID != "" {
+		for _, w := range m.Windows {
+			if w.PTYID == sourcePTYID {
+				return w.Workspace
+			}
+		}
+	}
+	return m.CurrentWorkspace
+}
+
+// workspaceLabel returns the number of a workspace followed by its name, if
+// it has one.
+func (m *OS) workspaceLabel(workspace int) string {
+	if name := m.WorkspaceNames[workspace]; name != "" {
+		return fmt.Sprintf("%d %s", workspace, name)
+	}
+	return strconv.Itoa(workspace)
+}
+
+// swapWorkspaceTiling stores the tiling mode of the workspace being left and
+// loads the one of the workspace being entered into m.AutoTiling.
+func (m *OS) swapWorkspaceTiling(from, to int) {
+	if m.WorkspaceTiling == nil {
+		m.WorkspaceTiling = make(map[int]bool)
+	}
+	m.WorkspaceTiling[from] = m.AutoTiling
+	m.AutoTiling = m.WorkspaceTiling[to]
+	delete(m.WorkspaceTiling, to)
+}
+
+// MoveWindowToWorkspace moves a window and the other windows of its group to
+// the specified workspace without changing focus.
 func (m *OS) MoveWindowToWorkspace(windowIndex int, workspace int) {
+	for _, j := range m.groupMembers(windowIndex) {
+		m.moveWindowToWorkspace(j, workspace)
+	}
+	m.moveWindowToWorkspace(windowIndex, workspace)
+}
+
+func (m *OS) moveWindowToWorkspace(windowIndex int, workspace int) {
 	if windowIndex < 0 || windowIndex >= len(m.Windows) {
 		m.LogWarn("Cannot move window: invalid index %d", windowIndex)
 		return
@@ -168,7 +345,7 @@ func (m *OS) MoveWindowToWorkspace(windowIndex int, workspace int) {
 	m.LogInfo("Moving window %s: workspace %d → %d", window.Title, oldWorkspace, workspace)
 
 	// If window is moving away from the current visible workspace, unsubscribe from its PTY
-	if m.IsDaemonSession && m.DaemonClient != nil && oldWorkspace == m.CurrentWorkspace {
+	if m.IsDaemonSession && m.DaemonClient != nil && oldWorkspace == m.CurrentWorkspace && !m.WorkspaceVisible(workspace) {
 		m.unsubscribeFromPTY(window)
 	}
 
@@ -194,7 +371,8 @@ func (m *OS) MoveWindowToWorkspace(windowIndex int, workspace int) {
 	}
 }
 
-// MoveWindowToWorkspaceAndFollow moves a window to the specified workspace and switches to that workspace.
+// MoveWindowToWorkspaceAndFollow moves a window and the other windows of its
+// group to the specified workspace and switches to that workspace.
 func (m *OS) MoveWindowToWorkspaceAndFollow(windowIndex int, workspace int) {
 	if windowIndex < 0 || windowIndex >= len(m.Windows) {
 		return
@@ -202,6 +380,9 @@ func (m *OS) MoveWindowToWorkspaceAndFollow(windowIndex int, workspace int) {
 	if workspace < 1 || workspace > m.NumWorkspaces {
 		return
 	}
+	for _, j := range m.groupMembers(windowIndex) {
+		m.moveWindowToWorkspace(j, workspace)
+	}
 
 	window := m.Windows[windowIndex]
 	oldWorkspace := window.Workspace
@@ -288,7 +469,7 @@ func (m *OS) TileVisibleWorkspaceWindows() {
 	// Only tile windows in current workspace
 	visibleWindows := make([]int, 0)
 	for i, w := range m.Windows {
-		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing {
+		if w.Workspace == m.CurrentWorkspace && !w.Minimized && !w.Minimizing && !w.Floating {
 			visibleWindows = append(visibleWindows, i)
 		}
 	}
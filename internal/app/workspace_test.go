@@ -0,0 +1,159 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/session"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+func TestPerWorkspaceTiling(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.NumWorkspaces = 9
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 1},
+		{ID: "window-two-0000", Width: 40, Height: 20, Workspace: 2},
+	}
+	m.FocusedWindow = 0
+	m.AutoTiling = true
+
+	m.SwitchToWorkspace(2)
+	if m.AutoTiling {
+		t.Error("expected workspace 2 to stay floating")
+	}
+	if !m.WorkspaceAutoTiling(1) || m.WorkspaceAutoTiling(2) {
+		t.Errorf("expected only workspace 1 tiled, got %v", m.WorkspaceTiling)
+	}
+
+	state := m.BuildSessionState()
+	if len(state.WorkspaceTiling) != 1 || !state.WorkspaceTiling[1] {
+		t.Errorf("expected workspace 1 tiled in the session state, got %v", state.WorkspaceTiling)
+	}
+
+	m.SwitchToWorkspace(1)
+	if !m.AutoTiling {
+		t.Error("expected workspace 1 to be tiled again")
+	}
+
+	// States saved before tiling was per workspace tile every workspace
+	m.restoreWorkspaceTiling(&session.SessionState{AutoTiling: true})
+	for ws := 1; ws <= m.NumWorkspaces; ws++ {
+		if !m.WorkspaceAutoTiling(ws) {
+			t.Errorf("expected workspace %d tiled from a legacy state", ws)
+		}
+	}
+}
+
+func TestWorkspaceBackAndForth(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.NumWorkspaces = 9
+	m.CurrentWorkspace = 1
+
+	m.SwitchToPreviousWorkspace()
+	if m.CurrentWorkspace != 1 {
+		t.Fatalf("expected no previous workspace yet, got workspace %d", m.CurrentWorkspace)
+	}
+
+	m.SelectWorkspace(3)
+	m.SelectWorkspace(3)
+	if m.CurrentWorkspace != 3 {
+		t.Errorf("expected workspace 3 to stay without back-and-forth, got %d", m.CurrentWorkspace)
+	}
+
+	saved := config.WorkspaceBackAndForth
+	config.WorkspaceBackAndForth = true
+	defer func() { config.WorkspaceBackAndForth = saved }()
+	m.SelectWorkspace(3)
+	if m.CurrentWorkspace != 1 || m.PreviousWorkspace != 3 {
+		t.Errorf("expected back to workspace 1 from 3, got %d (previous %d)", m.CurrentWorkspace, m.PreviousWorkspace)
+	}
+
+	m.SwitchToPreviousWorkspace()
+	if m.CurrentWorkspace != 3 {
+		t.Errorf("expected the previous workspace 3, got %d", m.CurrentWorkspace)
+	}
+}
+
+func TestRenameWorkspace(t *testing.T) {
+	m := NewOS(OSOptions{})
+	m.NumWorkspaces = 9
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", PTYID: "pty-one", Workspace: 3},
+	}
+
+	if err := m.RenameWorkspace(2, "  logs "); err != nil {
+		t.Fatalf("RenameWorkspace failed: %v", err)
+	}
+	if got := m.workspaceLabel(2); got != "2 logs" {
+		t.Errorf("expected label %q, got %q", "2 logs", got)
+	}
+	if got := m.workspaceLabel(1); got != "1" {
+		t.Errorf("expected unnamed workspace labelled by number, got %q", got)
+	}
+	if state := m.BuildSessionState(); state.WorkspaceNames[2] != "logs" {
+		t.Errorf("expected the name saved in the session state, got %v", state.WorkspaceNames)
+	}
+
+	if err := m.RenameWorkspace(2, ""); err != nil || m.WorkspaceNames[2] != "" {
+		t.Errorf("expected an empty name to clear the workspace name, got %v (%v)", m.WorkspaceNames, err)
+	}
+	if err := m.RenameWorkspace(10, "nope"); err == nil {
+		t.Error("expected an error for a workspace out of range")
+	}
+
+	if got := m.sourceWorkspace("pty-one"); got != 3 {
+		t.Errorf("expected the workspace of the calling window, got %d", got)
+	}
+	if got := m.sourceWorkspace(""); got != 1 {
+		t.Errorf("expected the current workspace for commands from outside, got %d", got)
+	}
+}
+
+func TestWorkspaceTemplates(t *testing.T) {
+	saved := config.WorkspaceTemplates
+	defer func() { config.WorkspaceTemplates = saved }()
+	config.WorkspaceTemplates = []config.WorkspaceTemplate{
+		{Workspace: 2, Layout: "/nonexistent/dev.toml"},
+		{Workspace: 3, Layout: "/nonexistent/busy.toml"},
+		{Name: "logs", Layout: "/nonexistent/logs.toml"},
+	}
+
+	m := NewOS(OSOptions{})
+	m.NumWorkspaces = 9
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-one-0000", Width: 40, Height: 20, Workspace: 3},
+	}
+	m.WorkspaceNames = map[int]string{4: "logs"}
+
+	// A workspace with windows is left as it is, and never filled later
+	m.SwitchToWorkspace(3)
+	if !m.PopulatedWorkspaces[3] || len(m.Notifications) != 0 {
+		t.Errorf("expected workspace 3 marked without loading its template, got %v %+v", m.PopulatedWorkspaces, m.Notifications)
+	}
+
+	// An empty workspace loads its template once, matched by number or name
+	m.SwitchToWorkspace(2)
+	m.SwitchToWorkspace(1)
+	m.SwitchToWorkspace(2)
+	m.SwitchToWorkspace(4)
+	if len(m.Notifications) != 2 {
+		t.Fatalf("expected one failed load each for workspaces 2 and 4, got %+v", m.Notifications)
+	}
+	if m.PopulatedWorkspaces[1] {
+		t.Error("expected workspace 1 without a template left unmarked")
+	}
+
+	state := m.BuildSessionState()
+	if got := state.PopulatedWorkspaces; len(got) != 3 || got[0] != 2 || got[1] != 3 || got[2] != 4 {
+		t.Errorf("expected workspaces 2, 3 and 4 in the session state, got %v", got)
+	}
+	restored := NewOS(OSOptions{})
+	restored.restorePopulatedWorkspaces(state.PopulatedWorkspaces)
+	if !restored.PopulatedWorkspaces[2] || restored.PopulatedWorkspaces[1] {
+		t.Errorf("expected the populated workspaces restored, got %v", restored.PopulatedWorkspaces)
+	}
+}
@@ -0,0 +1,155 @@
+package app
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ToggleZoom draws the focused window's content at double cell size, or at
+// normal size again, for reading small output without changing the host
+// terminal's font. The window keeps its size; its terminal gets half of it.
+func (m *OS) ToggleZoom() {
+	w := m.GetFocusedWindow()
+	if w == nil {
+		return
+	}
+	if !w.SetZoomed(!w.Zoomed) {
+		m.ShowNotification("Windows with panes or tabs cannot be zoomed", "warning", config.NotificationDuration)
+		return
+	}
+	if w.Zoomed {
+		m.ShowNotification("Zoom: 2x", "info", config.NotificationDuration)
+	} else {
+		m.ShowNotification("Zoom: off", "info", config.NotificationDuration)
+	}
+}
+
+// zoomContent draws rendered terminal content at double size: every cell
+// takes 2x2 cells, printable ASCII drawn with a pixel font of sextant
+// characters.
+func zoomContent(content string) string {
+	lines := strings.Split(content, "\n")
+	var b strings.Builder
+	b.Grow(len(content) * 6)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		top, bottom := zoomLine(line)
+		b.WriteString(top)
+		b.WriteByte('\n')
+		b.WriteString(bottom)
+	}
+	return b.String()
+}
+
+// zoomLine returns the two rows a rendered line takes at double size. Escape
+// sequences are kept on both, so the lower row has the same colors.
+func zoomLine(line string) (string, string) {
+	var top, bottom strings.Builder
+	var state byte
+	for len(line) > 0 {
+		seq, width, n, newState := ansi.DecodeSequence(line, state, nil)
+		state = newState
+		line = line[n:]
+		if width == 0 {
+			top.WriteString(seq)
+			bottom.WriteString(seq)
+			continue
+		}
+		upper, lower := zoomGlyph(seq, width)
+		top.WriteString(upper)
+		bottom.WriteString(lower)
+	}
+	return top.String(), bottom.String()
+}
+
+// zoomGlyphs holds the upper and lower halves of every zoomFont glyph drawn
+// over 2x2 cells.
+var zoomGlyphs = sextantGlyphs(zoomFont)
+
+// zoomBlocks holds the halves of the block and line drawing characters that
+// keep joining their neighbours at double size.
+var zoomBlocks = map[rune][2]string{
+	'█': {"██", "██"}, '░': {"░░", "░░"}, '▒': {"▒▒", "▒▒"}, '▓': {"▓▓", "▓▓"},
+	'▀': {"██", "  "}, '▄': {"  ", "██"}, '▌': {"█ ", "█ "}, '▐': {" █", " █"},
+	'─': {"──", "  "}, '│': {"│ ", "│ "}, '┼': {"┼─", "│ "},
+	'┌': {"┌─", "│ "}, '┐': {"┐ ", "│ "}, '└': {"└─", "  "}, '┘': {"┘ ", "  "},
+	'╭': {"╭─", "│ "}, '╮': {"╮ ", "│ "}, '╰': {"╰─", "  "}, '╯': {"╯ ", "  "},
+	'├': {"├─", "│ "}, '┤': {"┤ ", "│ "}, '┬': {"┬─", "│ "}, '┴': {"┴─", "  "},
+}
+
+// zoomGlyph returns the upper and lower halves of a glyph drawn over twice
+// its width and height. Glyphs without a zoomed form are drawn once in the
+// upper left cell.
+func zoomGlyph(glyph string, width int) (string, string) {
+	if r, size := utf8.DecodeRuneInString(glyph); width == 1 && size == len(glyph) {
+		if halves, ok := zoomGlyphs[r]; ok && !config.UseASCIIOnly {
+			return halves[0], halves[1]
+		}
+		if halves, ok := zoomBlocks[r]; ok {
+			return halves[0], halves[1]
+		}
+		if r == ' ' {
+			return "  ", "  "
+		}
+	}
+	return glyph + strings.Repeat(" ", width), strings.Repeat(" ", width*2)
+}
+
+// sextantGlyphs draws the glyphs of a pixel font over 2x2 cells, each cell
+// a sextant character showing 2x3 of the glyph's 4x6 pixels.
+func sextantGlyphs(font map[rune]string) map[rune][2]string {
+	glyphs := make(map[rune][2]string, len(font))
+	for r, pixels := range font {
+		rows := strings.Split(pixels, " ")
+		set := func(x, y int) bool {
+			return y < len(rows) && x < len(rows[y]) && rows[y][x] == '#'
+		}
+		var halves [2]string
+		for cy := range 2 {
+			var half []rune
+			for cx := range 2 {
+				bits := 0
+				for py := range 3 {
+					for px := range 2 {
+						if set(cx*2+px, cy*3+py) {
+							bits |= 1 << (py*2 + px)
+						}
+					}
+				}
+				half = append(half, sextant(bits))
+			}
+			halves[cy] = string(half)
+		}
+		glyphs[r] = halves
+	}
+	return glyphs
+}
+
+// sextant returns the character drawing the pixels set in bits of a cell
+// split into 2x3 pixels, bit 0 the top left one and bit 5 the bottom right.
+// The Unicode sextants leave out the patterns older block characters draw.
+func sextant(bits int) rune {
+	switch bits {
+	case 0:
+		return ' '
+	case 0b010101:
+		return '▌'
+	case 0b101010:
+		return '▐'
+	case 0b111111:
+		return '█'
+	}
+	index := bits - 1
+	if bits > 0b010101 {
+		index--
+	}
+	if bits > 0b101010 {
+		index--
+	}
+	return rune(0x1FB00 + index)
+}
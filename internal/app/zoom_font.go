@@ -0,0 +1,102 @@
+package app
+
+// zoomFont is the pixel font zoomed windows draw printable ASCII with. Each
+// glyph is 3 pixels wide and up to 6 high, its rows separated by spaces with
+// '#' for a set pixel; only descenders reach the sixth row. A glyph takes 2x2
+// sextant cells, 4x6 pixels, the fourth column keeping letters apart.
+var zoomFont = map[rune]string{
+	'!':  ".#. .#. .#. ... .#.",
+	'"':  "#.# #.#",
+	'#':  "#.# ### #.# ### #.#",
+	'$':  ".## ##. .#. .## ##.",
+	'%':  "#.. ..# .#. #.. ..#",
+	'&':  ".#. #.# .#. #.# .##",
+	'\'': ".#. .#.",
+	'(':  "..# .#. .#. .#. ..#",
+	')':  "#.. .#. .#. .#. #..",
+	'*':  "... #.# .#. #.#",
+	'+':  "... .#. ### .#.",
+	',':  "... ... ... ... .#. #..",
+	'-':  "... ... ###",
+	'.':  "... ... ... ... .#.",
+	'/':  "..# ..# .#. #.. #..",
+	'0':  "### #.# #.# #.# ###",
+	'1':  ".#. ##. .#. .#. ###",
+	'2':  "##. ..# .#. #.. ###",
+	'3':  "##. ..# .#. ..# ##.",
+	'4':  "#.# #.# ### ..# ..#",
+	'5':  "### #.. ##. ..# ##.",
+	'6':  ".## #.. ### #.# ###",
+	'7':  "### ..# .#. .#. .#.",
+	'8':  "### #.# ### #.# ###",
+	'9':  "### #.# ### ..# ##.",
+	':':  "... .#. ... .#.",
+	';':  "... .#. ... .#. #..",
+	'<':  "..# .#. #.. .#. ..#",
+	'=':  "... ### ... ###",
+	'>':  "#.. .#. ..# .#. #..",
+	'?':  "##. ..# .#. ... .#.",
+	'@':  ".#. #.# ### #.. .##",
+	'A':  ".#. #.# ### #.# #.#",
+	'B':  "##. #.# ##. #.# ##.",
+	'C':  ".## #.. #.. #.. .##",
+	'D':  "##. #.# #.# #.# ##.",
+	'E':  "### #.. ##. #.. ###",
+	'F':  "### #.. ##. #.. #..",
+	'G':  ".## #.. #.# #.# .##",
+	'H':  "#.# #.# ### #.# #.#",
+	'I':  "### .#. .#. .#. ###",
+	'J':  "..# ..# ..# #.# .#.",
+	'K':  "#.# #.# ##. #.# #.#",
+	'L':  "#.. #.. #.. #.. ###",
+	'M':  "#.# ### ### #.# #.#",
+	'N':  "#.# ### ### ### #.#",
+	'O':  ".#. #.# #.# #.# .#.",
+	'P':  "##. #.# ##. #.. #..",
+	'Q':  ".#. #.# #.# ### .##",
+	'R':  "##. #.# ##. #.# #.#",
+	'S':  ".## #.. .#. ..# ##.",
+	'T':  "### .#. .#. .#. .#.",
+	'U':  "#.# #.# #.# #.# ###",
+	'V':  "#.# #.# #.# .#. .#.",
+	'W':  "#.# #.# ### ### #.#",
+	'X':  "#.# #.# .#. #.# #.#",
+	'Y':  "#.# #.# .#. .#. .#.",
+	'Z':  "### ..# .#. #.. ###",
+	'[':  "##. #.. #.. #.. ##.",
+	'\\': "#.. #.. .#. ..# ..#",
+	']':  ".## ..# ..# ..# .##",
+	'^':  ".#. #.#",
+	'_':  "... ... ... ... ###",
+	'`':  "#.. .#.",
+	'a':  "... ##. .## #.# .##",
+	'b':  "#.. #.. ##. #.# ##.",
+	'c':  "... ... .## #.. .##",
+	'd':  "..# ..# .## #.# .##",
+	'e':  "... .## #.# ##. .##",
+	'f':  "..# .#. ### .#. .#.",
+	'g':  "... ... .## #.# .## ##.",
+	'h':  "#.. #.. ##. #.# #.#",
+	'i':  ".#. ... .#. .#. .#.",
+	'j':  "..# ... ..# ..# ..# ##.",
+	'k':  "#.. #.. #.# ##. #.#",
+	'l':  "##. .#. .#. .#. ###",
+	'm':  "... ... ### ### #.#",
+	'n':  "... ... ##. #.# #.#",
+	'o':  "... ... .#. #.# .#.",
+	'p':  "... ... ##. #.# ##. #..",
+	'q':  "... ... .## #.# .## ..#",
+	'r':  "... ... .## #.. #..",
+	's':  "... ... .## .#. ##.",
+	't':  ".#. ### .#. .#. .##",
+	'u':  "... ... #.# #.# .##",
+	'v':  "... ... #.# #.# .#.",
+	'w':  "... ... #.# ### ###",
+	'x':  "... ... #.# .#. #.#",
+	'y':  "... ... #.# #.# .## ##.",
+	'z':  "... ... ##. .#. .##",
+	'{':  "..# .#. ##. .#. ..#",
+	'|':  ".#. .#. .#. .#. .#.",
+	'}':  "#.. .#. .## .#. #..",
+	'~':  "... ##. .##",
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestZoomContent(t *testing.T) {
+	// H is drawn over 2x2 sextant cells and the line keeps joining
+	got := zoomContent("\x1b[1mH\x1b[0m ─")
+	want := "\x1b[1m" + string(sextant(0b110101)) + "▌\x1b[0m  ──\n" +
+		"\x1b[1m" + string(sextant(0b000101)) + string(sextant(0b000101)) + "\x1b[0m    "
+	if got != want {
+		t.Errorf("zoomContent() = %q, want %q", got, want)
+	}
+
+	for bits, want := range map[int]rune{0: ' ', 1: '\U0001FB00', 0b010101: '▌', 0b010110: '\U0001FB14', 0b111110: '\U0001FB3B', 0b111111: '█'} {
+		if got := sextant(bits); got != want {
+			t.Errorf("sextant(%06b) = %U, want %U", bits, got, want)
+		}
+	}
+}
+
+func TestZoomSurvivesReattach(t *testing.T) {
+	w := &terminal.Window{ID: "window-zoom-000", Width: 40, Height: 20, Terminal: vt.NewEmulator(38, 18)}
+	w.SetZoomed(true)
+	ws := windowState(w)
+	if !ws.Zoomed {
+		t.Fatal("expected the zoom kept in the window state")
+	}
+
+	restored := &terminal.Window{ID: w.ID, Terminal: vt.NewEmulator(38, 18)}
+	m := NewOS(OSOptions{})
+	m.updateWindowFromState(restored, &ws)
+	if !restored.Zoomed || restored.Terminal.Width() != 19 {
+		t.Errorf("expected the window zoomed again with a 19 column terminal, got %v %d", restored.Zoomed, restored.Terminal.Width())
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// ConfigIssue is a problem found by CheckConfig.
+type ConfigIssue struct {
+	Line    int    // 1-based line in the config file, 0 if unknown
+	Field   string // Config section, e.g. "appearance" or "window_management"
+	Key     string // Option name or key binding
+	Message string
+	Warning bool // Warnings are ignored by TUIOS; errors stop it from loading the config
+}
+
+// String formats the issue as "[field] key: message".
+func (i ConfigIssue) String() string {
+	var b strings.Builder
+	if i.Field != "" {
+		fmt.Fprintf(&b, "[%s] ", i.Field)
+	}
+	if i.Key != "" {
+		fmt.Fprintf(&b, "%s: ", i.Key)
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// CheckConfig reports every problem in config file contents: syntax errors,
+// unknown options, invalid key bindings and option values. Unlike
+// LoadUserConfig it reports all problems and locates each one in the file.
+func CheckConfig(data []byte) []ConfigIssue {
+	var issues []ConfigIssue
+
+	var cfg UserConfig
+	decoder := toml.NewDecoder(bytes.NewReader(data)).DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		var strictErr *toml.StrictMissingError
+		var decodeErr *toml.DecodeError
+		switch {
+		case errors.As(err, &strictErr):
+			for _, e := range strictErr.Errors {
+				row, _ := e.Position()
+				issues = append(issues, ConfigIssue{
+					Line:    row,
+					Key:     strings.Join(e.Key(), "."),
+					Message: "Unknown option, ignored",
+					Warning: true,
+				})
+			}
+		case errors.As(err, &decodeErr):
+			row, _ := decodeErr.Position()
+			return append(issues, ConfigIssue{Line: row, Message: err.Error()})
+		default:
+			return append(issues, ConfigIssue{Message: err.Error()})
+		}
+	}
+
+	// Validation expects default bindings for sections the file leaves out
+	fillMissingKeybinds(&cfg, DefaultConfig())
+
+	validation := ValidateConfig(&cfg)
+	for _, e := range validation.Errors {
+		issues = append(issues, newConfigIssue(data, e, false))
+	}
+	for _, e := range validation.Warnings {
+		issues = append(issues, newConfigIssue(data, e, true))
+	}
+
+	// File order, with problems that have no line (such as conflicts with
+	// default bindings) last
+	slices.SortStableFunc(issues, func(a, b ConfigIssue) int {
+		if a.Line == 0 || b.Line == 0 {
+			return cmp.Compare(b.Line, a.Line)
+		}
+		return cmp.Compare(a.Line, b.Line)
+	})
+	return issues
+}
+
+func newConfigIssue(data []byte, e ValidationError, warning bool) ConfigIssue {
+	table := e.Field
+	if table != "appearance" && table != "daemon" && table != "debug" && table != "keybindings" && table != "window_rules" {
+		table = "keybindings." + table
+	}
+	return ConfigIssue{
+		Line:    findConfigLine(data, table, e.Key),
+		Field:   e.Field,
+		Key:     e.Key,
+		Message: e.Message,
+		Warning: warning,
+	}
+}
+
+// findConfigLine returns the line in table that sets the option key or
+// binds the key string key, or 0 if it is not in the file (for example a
+// default binding).
+func findConfigLine(data []byte, table, key string) int {
+	current := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(text, "[") {
+			header, _, _ := strings.Cut(text, "#")
+			current = strings.Trim(header, "[] \t")
+			continue
+		}
+		if current != table {
+			continue
+		}
+		name, value, ok := strings.Cut(text, "=")
+		if !ok {
+			continue
+		}
+		if strings.Trim(strings.TrimSpace(name), `"'`) == key ||
+			strings.Contains(value, `"`+key+`"`) || strings.Contains(value, `'`+key+`'`) {
+			return line
+		}
+	}
+	return 0
+}
@@ -1,10 +1,14 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/adrg/xdg"
 )
 
 // =============================================================================
@@ -31,8 +35,8 @@ func TestDefaultConfig(t *testing.T) {
 		t.Error("Expected default dockbar position to be set")
 	}
 
-	if cfg.Appearance.ScrollbackLines < 100 {
-		t.Errorf("Expected scrollback lines >= 100, got %d", cfg.Appearance.ScrollbackLines)
+	if cfg.Appearance.ScrollbackLines == nil || *cfg.Appearance.ScrollbackLines < 100 {
+		t.Errorf("Expected scrollback lines >= 100, got %v", cfg.Appearance.ScrollbackLines)
 	}
 }
 
@@ -106,6 +110,22 @@ func TestKeybindRegistry_GetKeysForDisplay(t *testing.T) {
 	}
 }
 
+func TestKeybindRegistry_GetTerminalModeAction(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Keybindings.TerminalMode["terminal_unlock_input"] = []string{"ctrl+alt+u", "f9"}
+	registry := config.NewKeybindRegistry(cfg)
+
+	// Every bound key matches, whatever the case of its modifiers
+	for _, key := range []string{"ctrl+alt+u", "CTRL+ALT+U", "f9"} {
+		if action := registry.GetTerminalModeAction(key); action != "terminal_unlock_input" {
+			t.Errorf("GetTerminalModeAction(%q) = %q, want terminal_unlock_input", key, action)
+		}
+	}
+	if action := registry.GetTerminalModeAction("ctrl+u"); action != "" {
+		t.Errorf("GetTerminalModeAction(\"ctrl+u\") = %q, want none", action)
+	}
+}
+
 func TestKeybindRegistry_UnknownAction(t *testing.T) {
 	cfg := config.DefaultConfig()
 	registry := config.NewKeybindRegistry(cfg)
@@ -174,6 +194,8 @@ func TestKeyNormalizer_ValidateKey(t *testing.T) {
 		{"enter", true},
 		{"esc", true},
 		{"tab", true},
+		{"+", true},
+		{"ctrl+", false},
 		{"", false},
 	}
 
@@ -425,13 +447,45 @@ func TestApplyOverrides_ScrollbackLines(t *testing.T) {
 	// User config fallback
 	config.ScrollbackLines = 10000
 	userCfg := config.DefaultConfig()
-	userCfg.Appearance.ScrollbackLines = 20000
+	userLines := 20000
+	userCfg.Appearance.ScrollbackLines = &userLines
 	config.ApplyOverrides(config.Overrides{}, userCfg)
 	if config.ScrollbackLines != 20000 {
 		t.Errorf("Expected user config 20000, got %d", config.ScrollbackLines)
 	}
 }
 
+func TestScrollbackLinesConfig(t *testing.T) {
+	originalLines := config.ScrollbackLines
+	defer func() { config.ScrollbackLines = originalLines }()
+
+	tests := map[string]struct {
+		toml string
+		want int
+	}{
+		"missing":     {"", 10000},
+		"zero":        {"scrollback_lines = 0", 0},
+		"below 100":   {"scrollback_lines = 20", 100},
+		"in range":    {"scrollback_lines = 5000", 5000},
+		"above limit": {"scrollback_lines = 5000000", 1000000},
+		"negative":    {"scrollback_lines = -3", 10000},
+	}
+	for name, tt := range tests {
+		cfg, _, err := config.ParseUserConfig([]byte("[appearance]\n" + tt.toml + "\n"))
+		if err != nil {
+			t.Fatalf("%s: ParseUserConfig failed: %v", name, err)
+		}
+		if got := *cfg.Appearance.ScrollbackLines; got != tt.want {
+			t.Errorf("%s: scrollback_lines = %d, want %d", name, got, tt.want)
+		}
+		config.ScrollbackLines = 1234
+		config.ApplyOverrides(config.Overrides{}, cfg)
+		if config.ScrollbackLines != tt.want {
+			t.Errorf("%s: ScrollbackLines = %d after applying the config, want %d", name, config.ScrollbackLines, tt.want)
+		}
+	}
+}
+
 func TestApplyOverrides_NoAnimations(t *testing.T) {
 	// Save original value
 	originalEnabled := config.AnimationsEnabled
@@ -523,3 +577,572 @@ func TestApplyOverrides_HideClock(t *testing.T) {
 		t.Error("Expected HideClock to be true from user config (OR)")
 	}
 }
+
+// =============================================================================
+// Layout File Tests
+// =============================================================================
+
+func TestParseLayout(t *testing.T) {
+	layout, err := config.ParseLayout([]byte(`
+tiling = true
+
+[[workspace]]
+focus = "server"
+
+[[workspace.window]]
+name = "editor"
+command = "nvim"
+x = 0
+y = 0
+width = 50
+height = 100
+
+[[workspace.window]]
+name = "server"
+x = 50
+y = 0
+width = 50
+height = 100
+
+[[workspace]]
+number = 3
+
+[[workspace.window]]
+name = "logs"
+`))
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %v", err)
+	}
+	if layout.Tiling == nil || !*layout.Tiling {
+		t.Error("Expected tiling to be true")
+	}
+	if len(layout.Workspaces) != 2 {
+		t.Fatalf("Expected 2 workspaces, got %d", len(layout.Workspaces))
+	}
+	if layout.Workspaces[0].Number != 1 {
+		t.Errorf("Expected first workspace to default to number 1, got %d", layout.Workspaces[0].Number)
+	}
+	if layout.Workspaces[1].Number != 3 {
+		t.Errorf("Expected second workspace number 3, got %d", layout.Workspaces[1].Number)
+	}
+	if got := layout.Workspaces[0].Windows; len(got) != 2 || !got[0].HasGeometry() || got[0].Command != "nvim" {
+		t.Errorf("Unexpected windows in first workspace: %+v", got)
+	}
+	if layout.Workspaces[1].Windows[0].HasGeometry() {
+		t.Error("Expected window without geometry")
+	}
+
+	invalid := map[string]string{
+		"no workspaces":    `tiling = true`,
+		"partial geometry": "[[workspace]]\n[[workspace.window]]\nx = 10\n",
+		"out of range":     "[[workspace]]\n[[workspace.window]]\nx = 0\ny = 0\nwidth = 150\nheight = 50\n",
+		"zero size":        "[[workspace]]\n[[workspace.window]]\nx = 0\ny = 0\nwidth = 0\nheight = 50\n",
+		"bad number":       "[[workspace]]\nnumber = 10\n",
+		"duplicate":        "[[workspace]]\nnumber = 2\n[[workspace]]\nnumber = 2\n",
+	}
+	for name, data := range invalid {
+		if _, err := config.ParseLayout([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadLayoutFileYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dev.yaml")
+	if err := os.WriteFile(path, []byte(`
+tiling: false
+workspace:
+  - focus: server
+    window:
+      - name: editor
+        command: nvim
+        x: 0
+        y: 0
+        width: 50
+        height: 100
+      - name: server
+        cwd: ~/src
+  - number: 3
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	layout, err := config.LoadLayoutFile(path)
+	if err != nil {
+		t.Fatalf("LoadLayoutFile failed: %v", err)
+	}
+	if layout.Tiling == nil || *layout.Tiling {
+		t.Error("Expected tiling to be false")
+	}
+	if len(layout.Workspaces) != 2 || layout.Workspaces[0].Number != 1 || layout.Workspaces[1].Number != 3 {
+		t.Fatalf("Unexpected workspaces: %+v", layout.Workspaces)
+	}
+	if got := layout.Workspaces[0].Windows; len(got) != 2 || !got[0].HasGeometry() || got[0].Command != "nvim" || got[1].Cwd != "~/src" {
+		t.Errorf("Unexpected windows in first workspace: %+v", got)
+	}
+
+	// The same contents in a .toml file are not read as YAML
+	tomlPath := filepath.Join(dir, "dev.toml")
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(tomlPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.LoadLayoutFile(tomlPath); err == nil {
+		t.Error("Expected a YAML layout named .toml to fail")
+	}
+	if _, err := config.ParseLayoutYAML([]byte("workspace:\n  - number: 10\n")); err == nil {
+		t.Error("Expected a YAML layout with a bad workspace number to fail")
+	}
+}
+
+// =============================================================================
+// Config Check Tests
+// =============================================================================
+
+func TestCheckConfig(t *testing.T) {
+	issues := config.CheckConfig([]byte(`[appearance]
+border_style = "roundd"
+bogus = 1
+
+[keybindings.window_management]
+new_window = ["ctrl+zz+q"]
+`))
+
+	want := []struct {
+		line    int
+		key     string
+		warning bool
+	}{
+		{2, "border_style", true},
+		{3, "appearance.bogus", true},
+		{6, "ctrl+zz+q", false},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(want), len(issues), issues)
+	}
+	for i, w := range want {
+		if issues[i].Line != w.line || issues[i].Key != w.key || issues[i].Warning != w.warning {
+			t.Errorf("Issue %d = %+v, want line %d key %q warning %v", i, issues[i], w.line, w.key, w.warning)
+		}
+	}
+
+	issues = config.CheckConfig([]byte("[appearance\nborder_style = \"rounded\"\n"))
+	if len(issues) != 1 || issues[0].Line != 1 || issues[0].Warning {
+		t.Errorf("Expected one syntax error on line 1, got %v", issues)
+	}
+
+	if issues := config.CheckConfig([]byte("[appearance]\nborder_style = \"thick\"\n")); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+	issues = config.CheckConfig([]byte("[[triggers]]\npattern = \"panic:\"\naction = \"hook\"\n\n[[triggers]]\npattern = \"refused\"\naction = \"urgent\"\n"))
+	if len(issues) != 1 || issues[0].Field != "triggers" || issues[0].Key != "panic:" {
+		t.Errorf("Expected one issue for the hook trigger without a command, got %v", issues)
+	}
+}
+
+// =============================================================================
+// Profile Tests
+// =============================================================================
+
+func TestLoadUserConfigProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	t.Cleanup(func() { _ = config.SetProfile("") })
+
+	write := func(name, data string) {
+		t.Helper()
+		path := filepath.Join(dir, "tuios", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("config.toml", `[appearance]
+border_style = "thick"
+dockbar_position = "bottom"
+
+[keybindings.window_management]
+new_window = ["n"]
+close_window = ["x"]
+`)
+	write("profiles/work.toml", `[appearance]
+dockbar_position = "top"
+
+[keybindings.window_management]
+new_window = ["ctrl+t"]
+`)
+
+	if err := config.SetProfile("work"); err != nil {
+		t.Fatalf("SetProfile failed: %v", err)
+	}
+	cfg, err := config.LoadUserConfig()
+	if err != nil {
+		t.Fatalf("LoadUserConfig failed: %v", err)
+	}
+	if cfg.Appearance.DockbarPosition != "top" {
+		t.Errorf("DockbarPosition = %q, want top from the profile", cfg.Appearance.DockbarPosition)
+	}
+	if cfg.Appearance.BorderStyle != "thick" {
+		t.Errorf("BorderStyle = %q, want thick from the base config", cfg.Appearance.BorderStyle)
+	}
+	if got := cfg.Keybindings.WindowManagement["new_window"]; !slices.Equal(got, []string{"ctrl+t"}) {
+		t.Errorf("new_window = %v, want [ctrl+t]", got)
+	}
+	if got := cfg.Keybindings.WindowManagement["close_window"]; !slices.Equal(got, []string{"x"}) {
+		t.Errorf("close_window = %v, want [x]", got)
+	}
+
+	if err := config.SetProfile("missing"); err != nil {
+		t.Fatalf("SetProfile failed: %v", err)
+	}
+	if _, err := config.LoadUserConfig(); err == nil {
+		t.Error("Expected an error for a missing profile")
+	}
+	if err := config.SetProfile("../work"); err == nil {
+		t.Error("Expected an error for an invalid profile name")
+	}
+}
+
+// TestReloadUserConfigInvalidKeepsGlobals tests that a reload which parses but
+// fails validation leaves every global setting as it was
+func TestReloadUserConfigInvalidKeepsGlobals(t *testing.T) {
+	type globals struct {
+		TitleFormat, WhichKeyPosition, ConfirmQuit, TilingInsert, Screensaver string
+		MoveStep, PasteChunkSize, ScrollbackMemoryBudget                      int
+		AutosaveInterval, ScreensaverIdle                                     time.Duration
+		AnimationsEnabled, CopyOnSelect, IndependentFocus, SwallowWindows     bool
+	}
+	snapshot := func() globals {
+		return globals{
+			config.TitleFormat, config.WhichKeyPosition, config.ConfirmQuit, config.TilingInsert, config.Screensaver,
+			config.MoveStep, config.PasteChunkSize, config.ScrollbackMemoryBudget,
+			config.AutosaveInterval, config.ScreensaverIdle,
+			config.AnimationsEnabled, config.CopyOnSelect, config.IndependentFocus, config.SwallowWindows,
+		}
+	}
+	before := snapshot()
+	t.Cleanup(func() {
+		config.TitleFormat, config.WhichKeyPosition, config.ConfirmQuit = before.TitleFormat, before.WhichKeyPosition, before.ConfirmQuit
+		config.TilingInsert, config.Screensaver = before.TilingInsert, before.Screensaver
+		config.MoveStep, config.PasteChunkSize, config.ScrollbackMemoryBudget = before.MoveStep, before.PasteChunkSize, before.ScrollbackMemoryBudget
+		config.AutosaveInterval, config.ScreensaverIdle = before.AutosaveInterval, before.ScreensaverIdle
+		config.AnimationsEnabled, config.CopyOnSelect = before.AnimationsEnabled, before.CopyOnSelect
+		config.IndependentFocus, config.SwallowWindows = before.IndependentFocus, before.SwallowWindows
+	})
+
+	settings := `[appearance]
+title_format = "{index} {name}"
+which_key_position = "top-left"
+confirm_quit = "never"
+tiling_insert = "after_focused"
+screensaver = "matrix"
+screensaver_minutes = 5
+move_step = 3
+paste_chunk_size = 1024
+scrollback_budget_mb = 64
+autosave_interval = 5
+animations_enabled = false
+copy_on_select = true
+
+[daemon]
+independent_focus = true
+swallow_windows = true
+`
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(settings+"\n[keybindings.window_management]\nnew_window = [\"ctrl+zz+q\"]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.ReloadUserConfig(path); err == nil {
+		t.Fatal("Expected an error for an invalid keybinding")
+	}
+	if got := snapshot(); got != before {
+		t.Errorf("Invalid reload changed the globals:\n got %+v\nwant %+v", got, before)
+	}
+
+	if err := os.WriteFile(path, []byte(settings), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.ReloadUserConfig(path); err != nil {
+		t.Fatalf("ReloadUserConfig failed: %v", err)
+	}
+	if config.TitleFormat != "{index} {name}" || config.MoveStep != 3 || !config.SwallowWindows {
+		t.Errorf("Valid reload did not apply the settings: %+v", snapshot())
+	}
+}
+
+// =============================================================================
+// Keybinding Preset Tests
+// =============================================================================
+
+func TestKeybindPresetTmux(t *testing.T) {
+	cfg, validation, err := config.ParseUserConfig([]byte(`[keybindings]
+preset = "tmux"
+
+[keybindings.prefix_mode]
+prefix_quit = ["q"]
+prefix_sidebar = ["B"]
+`))
+	if err != nil {
+		t.Fatalf("ParseUserConfig failed: %v", err)
+	}
+	if len(validation.Errors) != 0 || len(validation.Warnings) != 0 {
+		t.Fatalf("Expected no validation issues, got %v %v", validation.Errors, validation.Warnings)
+	}
+
+	registry := config.NewKeybindRegistry(cfg)
+	tests := []struct {
+		key    string
+		action string
+	}{
+		{"%", "prefix_split_pane_vertical"},
+		{`"`, "prefix_split_pane_horizontal"},
+		{"x", "prefix_close_pane"},
+		{"&", "prefix_close_window"},
+		{"o", "prefix_next_pane"},
+		{"n", "prefix_next_window"},
+		{"c", "prefix_new_window"},
+		{"Q", "prefix_quit"},
+		{"q", ""},
+		{"-", "prefix_split_horizontal"},
+		// Bindings left at their defaults take the preset, customized ones are kept
+		{"B", "prefix_sidebar"},
+		{"s", ""},
+	}
+	for _, tt := range tests {
+		if got := registry.GetPrefixAction(tt.key); got != tt.action {
+			t.Errorf("GetPrefixAction(%q) = %q, want %q", tt.key, got, tt.action)
+		}
+	}
+
+	_, validation, err = config.ParseUserConfig([]byte("[keybindings]\npreset = \"screen\"\n"))
+	if err != nil {
+		t.Fatalf("ParseUserConfig failed: %v", err)
+	}
+	if len(validation.Warnings) != 1 || validation.Warnings[0].Key != "preset" {
+		t.Errorf("Expected a warning for an unknown preset, got %v", validation.Warnings)
+	}
+}
+
+func TestGetPrefixActionForSplit(t *testing.T) {
+	registry := config.NewKeybindRegistry(config.DefaultConfig())
+	if got := registry.GetPrefixActionForSplit("left", true); got != "prefix_pane_left" {
+		t.Errorf("left in a split window = %q, want prefix_pane_left", got)
+	}
+	if got := registry.GetPrefixActionForSplit("left", false); got != "" {
+		t.Errorf("left in an unsplit window = %q, want unbound", got)
+	}
+	if got := registry.GetPrefixActionForSplit("c", false); got != "prefix_new_window" {
+		t.Errorf("c in an unsplit window = %q, want prefix_new_window", got)
+	}
+
+	// A key shared with another action runs it while the window is not split
+	cfg, _, err := config.ParseUserConfig([]byte("[keybindings.prefix_mode]\nprefix_prev_window = [\"left\"]\n"))
+	if err != nil {
+		t.Fatalf("ParseUserConfig failed: %v", err)
+	}
+	registry = config.NewKeybindRegistry(cfg)
+	if got := registry.GetPrefixActionForSplit("left", false); got != "prefix_prev_window" {
+		t.Errorf("shared left in an unsplit window = %q, want prefix_prev_window", got)
+	}
+	if got := registry.GetPrefixActionForSplit("left", true); got != "prefix_pane_left" {
+		t.Errorf("shared left in a split window = %q, want prefix_pane_left", got)
+	}
+}
+
+// =============================================================================
+// Window Rule Tests
+// =============================================================================
+
+func TestWindowRulesScrollbackLines(t *testing.T) {
+	cfg, validation, err := config.ParseUserConfig([]byte(`[[window_rules]]
+match = "*HTOP*"
+scrollback_lines = 0
+
+[[window_rules]]
+match = "logs ?"
+scrollback_lines = 50000
+
+[[window_rules]]
+match = "*"
+scrollback_lines = -5
+
+[[window_rules]]
+match = "*/src/*"
+scrollback_lines = 300
+`))
+	if err != nil {
+		t.Fatalf("ParseUserConfig failed: %v", err)
+	}
+	if len(validation.Warnings) != 1 || validation.Warnings[0].Key != "scrollback_lines" {
+		t.Errorf("Expected a warning for the negative scrollback_lines, got %v", validation.Warnings)
+	}
+
+	original := config.WindowRules
+	defer func() { config.WindowRules = original }()
+	config.ApplyOverrides(config.Overrides{}, cfg)
+
+	tests := []struct {
+		names []string
+		lines int
+		ok    bool
+	}{
+		{[]string{"", "htop - load"}, 0, true},
+		{[]string{"logs 1", "zsh"}, 50000, true},
+		{[]string{"logs 12"}, 0, false},
+		{[]string{"user@host: ~/src/tuios"}, 300, true},
+		{[]string{"zsh"}, 0, false},
+	}
+	for _, tt := range tests {
+		lines, ok := config.RuleScrollbackLines(tt.names...)
+		if lines != tt.lines || ok != tt.ok {
+			t.Errorf("RuleScrollbackLines(%q) = %d, %v, want %d, %v", tt.names, lines, ok, tt.lines, tt.ok)
+		}
+	}
+}
+
+func TestWindowRulesMinSize(t *testing.T) {
+	cfg, validation, err := config.ParseUserConfig([]byte(`[[window_rules]]
+match = "k9s"
+min_width = 120
+
+[[window_rules]]
+match = "k*"
+min_width = 80
+min_height = 30
+
+[[window_rules]]
+match = "*"
+min_height = -1
+`))
+	if err != nil {
+		t.Fatalf("ParseUserConfig failed: %v", err)
+	}
+	if len(validation.Warnings) != 1 || validation.Warnings[0].Key != "min_height" {
+		t.Errorf("Expected a warning for the negative min_height, got %v", validation.Warnings)
+	}
+
+	original := config.WindowRules
+	defer func() { config.WindowRules = original }()
+	config.ApplyOverrides(config.Overrides{}, cfg)
+
+	tests := []struct {
+		names         []string
+		width, height int
+	}{
+		{[]string{"", "zsh", "k9s"}, 120, 30},
+		{[]string{"kubectl"}, 80, 30},
+		{[]string{"zsh"}, 0, 0},
+	}
+	for _, tt := range tests {
+		width, height := config.RuleMinSize(tt.names...)
+		if width != tt.width || height != tt.height {
+			t.Errorf("RuleMinSize(%q) = %d, %d, want %d, %d", tt.names, width, height, tt.width, tt.height)
+		}
+	}
+}
+
+func TestAppIcon(t *testing.T) {
+	cfg, _, err := config.ParseUserConfig([]byte(`
+[icons]
+vim = "V"
+zsh = ""
+default = "?"
+`))
+	if err != nil {
+		t.Fatalf("ParseUserConfig failed: %v", err)
+	}
+
+	original, originalASCII := config.AppIcons, config.UseASCIIOnly
+	defer func() { config.AppIcons, config.UseASCIIOnly = original, originalASCII }()
+	config.UseASCIIOnly = false
+	config.ApplyOverrides(config.Overrides{}, cfg)
+
+	tests := []struct {
+		name, icon string
+	}{
+		{"vim", "V"},
+		{"/usr/bin/NVIM", "\ue7c5"},
+		{"python3.12", "\ue73c"},
+		{"zsh", ""},
+		{"unknown", "?"},
+	}
+	for _, tt := range tests {
+		if icon := config.AppIcon(tt.name); icon != tt.icon {
+			t.Errorf("AppIcon(%q) = %q, want %q", tt.name, icon, tt.icon)
+		}
+	}
+
+	config.UseASCIIOnly = true
+	if icon := config.AppIcon("vim"); icon != "" {
+		t.Errorf("expected no icons in ASCII-only mode, got %q", icon)
+	}
+}
+
+func TestExpandTitleFormat(t *testing.T) {
+	values := map[string]string{"name": "api", "cwd": "~/src/api", "flags": ""}
+	value := func(field string) string { return values[field] }
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"{name}", "api"},
+		{"{name}[ · {cwd}]", "api · ~/src/api"},
+		{"{name}[ ({flags})]", "api"},
+		{"{name} {flags}", "api"},
+		{"[{flags} ]{name}", "api"},
+		{"{name} {nope}", "api {nope}"},
+		{"{name}[ ok]", "api ok"},
+		{"{name} [unclosed", "api [unclosed"},
+	}
+	for _, tt := range tests {
+		if got := config.ExpandTitleFormat(tt.format, value); got != tt.want {
+			t.Errorf("ExpandTitleFormat(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+	if got := config.TitleFormatFields("{name}[ · {cwd}] {nope}"); !slices.Equal(got, []string{"name", "cwd", "nope"}) {
+		t.Errorf("TitleFormatFields = %v", got)
+	}
+}
+
+func TestWorkspaceTemplateFor(t *testing.T) {
+	cfg, _, err := config.ParseUserConfig([]byte(`
+[[workspace_templates]]
+name = "logs"
+layout = "~/layouts/logs.toml"
+
+[[workspace_templates]]
+workspace = 2
+name = "dev"
+layout = "/layouts/dev.toml"
+
+[[workspace_templates]]
+workspace = 12
+layout = "/layouts/nope.toml"
+`))
+	if err != nil {
+		t.Fatalf("ParseUserConfig failed: %v", err)
+	}
+
+	original := config.WorkspaceTemplates
+	defer func() { config.WorkspaceTemplates = original }()
+	config.ApplyOverrides(config.Overrides{}, cfg)
+
+	if tmpl, ok := config.WorkspaceTemplateFor(2, "logs"); !ok || tmpl.LayoutPath() != "/layouts/dev.toml" {
+		t.Errorf("expected the number to win over the name, got %+v (%v)", tmpl, ok)
+	}
+	if tmpl, ok := config.WorkspaceTemplateFor(5, "logs"); !ok || tmpl.LayoutPath() != config.ExpandHome("~/layouts/logs.toml") {
+		t.Errorf("expected the template of the name, got %+v (%v)", tmpl, ok)
+	}
+	if _, ok := config.WorkspaceTemplateFor(5, ""); ok {
+		t.Error("expected no template for an unnamed workspace without one")
+	}
+	if _, ok := config.WorkspaceTemplateFor(12, ""); ok {
+		t.Error("expected the template with a workspace out of range ignored")
+	}
+}
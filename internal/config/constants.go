@@ -23,6 +23,18 @@ const (
 
 	// MinWindowHeight is the minimum height a window can be resized to
 	MinWindowHeight = 3
+
+	// PaneResizeStep is the split ratio change for each pane resize key press
+	PaneResizeStep = 0.05
+
+	// PiPWidth is the width of a picture-in-picture window
+	PiPWidth = 48
+
+	// PiPHeight is the height of a picture-in-picture window
+	PiPHeight = 14
+
+	// TabLabelMaxWidth is the widest a tab's name is shown in a tab strip
+	TabLabelMaxWidth = 20
 )
 
 // =============================================================================
@@ -62,6 +74,9 @@ const (
 
 	// ProcessShutdownTimeout is the timeout for graceful process shutdown
 	ProcessShutdownTimeout = 500 * time.Millisecond
+
+	// ConfigReloadCheckInterval is how often the config file is checked for changes
+	ConfigReloadCheckInterval = time.Second
 )
 
 // =============================================================================
@@ -80,6 +95,41 @@ const (
 	BackgroundWindowUpdateCycle = 3
 )
 
+// =============================================================================
+// PTY Output Flow Control
+// =============================================================================
+
+const (
+	// OutputFloodBytesPerFrame is the amount of PTY output received within a
+	// single frame above which a window is considered to be flooding
+	OutputFloodBytesPerFrame = 256 * 1024
+
+	// OutputFloodMaxSkippedFrames is the number of consecutive frames a flooding
+	// window may skip before it is rendered again (~15Hz at 60fps)
+	OutputFloodMaxSkippedFrames = 3
+
+	// OutputBatchMaxBytes caps how much queued daemon output is coalesced into
+	// a single emulator write
+	OutputBatchMaxBytes = 512 * 1024
+
+	// PasteChunkInterval is the delay between chunks of a streamed paste
+	PasteChunkInterval = 5 * time.Millisecond
+)
+
+// =============================================================================
+// Scrollback Memory Budget
+// =============================================================================
+
+const (
+	// ScrollbackBudgetCheckInterval is how often scrollback memory usage is
+	// checked against the configured budgets
+	ScrollbackBudgetCheckInterval = 5 * time.Second
+
+	// ScrollbackTrimMinLines is the number of lines a window always keeps when
+	// its scrollback is trimmed to satisfy the global budget
+	ScrollbackTrimMinLines = 500
+)
+
 // =============================================================================
 // UI Layout Dimensions
 // =============================================================================
@@ -170,6 +220,9 @@ const (
 	// DockIconWorkspaceCount is the icon for workspace count (Nerd Font: nf-fa-th_large - 2x2 grid)
 	DockIconWorkspaceCount = string(rune(0xf009)) //
 
+	// DockIconUrgentCount is the icon for urgent window count (Nerd Font: nf-fa-bell)
+	DockIconUrgentCount = string(rune(0xf0f3)) //
+
 	// DockSeparator is the separator between dock sections
 	DockSeparator = "  " // Two spaces for breathing room
 )
@@ -202,6 +255,9 @@ const (
 	// DockIconWorkspaceCountASCII is the ASCII fallback for workspace count
 	DockIconWorkspaceCountASCII = "ws"
 
+	// DockIconUrgentCountASCII is the ASCII fallback for urgent window count
+	DockIconUrgentCountASCII = "!"
+
 	// DockSeparatorASCII is the ASCII fallback separator
 	DockSeparatorASCII = " | "
 )
@@ -305,6 +361,19 @@ var BorderStyle = "rounded"
 // Set via --dockbar-position flag or appearance.dockbar_position config
 var DockbarPosition = "bottom"
 
+// DockWrap lets the window pills of the dock that do not fit next to the
+// mode and status wrap onto a second dock row instead of being cut off
+// Set via appearance.dock_wrap config
+var DockWrap bool
+
+// DockStatusSide is the end of the dock showing the status widgets (CPU and
+// memory, or the copy mode help); the mode and workspace are at the other
+// Set via appearance.dock_status_side config
+var DockStatusSide = "right"
+
+// DockStatusSides are the valid values of DockStatusSide
+var DockStatusSides = []string{"right", "left"}
+
 // HideWindowButtons controls whether to hide window control buttons
 // Set via --hide-window-buttons flag or appearance.hide_window_buttons config
 var HideWindowButtons = false
@@ -314,6 +383,23 @@ var HideWindowButtons = false
 // Set via --window-title-position flag or appearance.window_title_position config
 var WindowTitlePosition = "bottom"
 
+// TitleFormat is the template of window titles: text with {field}
+// placeholders, where a part in [brackets] is dropped when all of its
+// fields are empty
+// Set via appearance.title_format config
+var TitleFormat = "{name}"
+
+// TitleFields are the placeholders TitleFormat may use
+var TitleFields = []string{"name", "title", "icon", "cwd", "dir", "command", "flags", "badge", "scroll", "size", "workspace", "id"}
+
+// TitleAlign is where window titles sit on their border: left, center or
+// right ("" = left on top, centered at the bottom)
+// Set via appearance.title_align config
+var TitleAlign = ""
+
+// TitleAligns are the valid values of TitleAlign
+var TitleAligns = []string{"left", "center", "right"}
+
 // HideClock controls whether the clock overlay is hidden
 // Set via --hide-clock flag or appearance.hide_clock config
 var HideClock = false
@@ -322,6 +408,217 @@ var HideClock = false
 // Set via --scrollback-lines flag or appearance.scrollback_lines config
 var ScrollbackLines = 10000
 
+// WindowRules are the configured per-window settings, matched against window
+// names in order
+// Set via [[window_rules]] config
+var WindowRules []WindowRule
+
+// ConfirmMultilinePaste controls whether multi-line pastes into a shell prompt
+// without bracketed paste ask for confirmation first
+// Set via appearance.confirm_multiline_paste config
+var ConfirmMultilinePaste = true
+
+// ConfirmQuit is when quitting asks for confirmation first: whenever windows
+// are open (always), only while programs run in them (running) or never
+// Set via appearance.confirm_quit config
+var ConfirmQuit = "running"
+
+// ConfirmQuitModes are the valid values of ConfirmQuit
+var ConfirmQuitModes = []string{"always", "running", "never"}
+
+// ClipboardWrite is what happens when a program sets the clipboard with
+// OSC 52: the write is passed to the host terminal (allow), shown for
+// confirmation first (confirm) or dropped (deny)
+// Set via appearance.clipboard_write config, overridden by window rules
+var ClipboardWrite = "allow"
+
+// ClipboardWriteModes are the valid values of ClipboardWrite
+var ClipboardWriteModes = []string{"allow", "confirm", "deny"}
+
+// ClipboardWriteMaxBytes is the largest OSC 52 clipboard write passed on,
+// in bytes (0 = no limit)
+// Set via appearance.clipboard_write_max_kb config
+var ClipboardWriteMaxBytes = 1024 * 1024
+
+// PasteChunkSize is the size in bytes above which pastes are streamed into the
+// PTY in chunks instead of a single write
+// Set via appearance.paste_chunk_size config
+var PasteChunkSize = 4096
+
+// MouseOverrideModifier is the modifier that keeps mouse events in tuios while
+// the application under the mouse has mouse reporting enabled: shift, alt,
+// ctrl or none
+// Set via appearance.mouse_override_modifier config
+var MouseOverrideModifier = "shift"
+
+// CopyOnSelect copies text to the clipboard, and to the host's primary
+// selection, as soon as it is selected with the mouse
+// Set via appearance.copy_on_select config
+var CopyOnSelect bool
+
+// SeeThroughFloats draws the content of the windows below a floating
+// window, dimmed, in the empty cells of the floating window
+// Set via appearance.see_through_floats config
+var SeeThroughFloats bool
+
+// MiddleClickPaste makes a middle click paste the text last selected with
+// the mouse into the terminal under it
+// Set via appearance.middle_click_paste config
+var MiddleClickPaste bool
+
+// AmbiguousWidth is the number of cells East Asian ambiguous-width characters
+// such as "○" or "→" occupy in windows (1 or 2)
+// Set via appearance.ambiguous_width config
+var AmbiguousWidth = 1
+
+// EmojiWidth is the number of cells emoji occupy in windows (1 or 2)
+// Set via appearance.emoji_width config
+var EmojiWidth = 2
+
+// ScrollbackMemoryBudget is the total number of bytes all windows' scrollback
+// may use before the least recently viewed windows are trimmed (0 = unlimited)
+// Set via appearance.scrollback_memory_budget_mb config
+var ScrollbackMemoryBudget = 512 * 1024 * 1024
+
+// ScrollbackWindowMemoryLimit is the number of bytes a single window's
+// scrollback may use before its oldest lines are trimmed (0 = unlimited)
+// Set via appearance.scrollback_window_memory_mb config
+var ScrollbackWindowMemoryLimit = 128 * 1024 * 1024
+
+// AutosaveInterval is how often the layout of a local session is saved to the
+// recovery file (0 = disabled)
+// Set via appearance.autosave_interval config
+var AutosaveInterval = 30 * time.Second
+
+// ReplayBufferBytes is how much of its most recent output each window keeps
+// for replay (0 = disabled)
+// Set via appearance.replay_buffer_kb config
+var ReplayBufferBytes = 1024 * 1024
+
+// ShutdownGracePeriod is how long the programs of a closed window get to exit
+// after SIGHUP and SIGTERM before they are killed
+// Set via appearance.shutdown_grace_ms config
+var ShutdownGracePeriod = time.Second
+
+// TilingInsert is where new windows enter the tiling order: after the last
+// window (end), after the focused window (after_focused) or in the first
+// slot (master)
+// Set via appearance.tiling_insert config
+var TilingInsert = "end"
+
+// TilingInsertPositions are the valid values of TilingInsert, in the order
+// the cycle_tiling_insert action steps through them
+var TilingInsertPositions = []string{"end", "after_focused", "master"}
+
+// WindowPlacement is where new floating windows open: under the mouse
+// cursor (cursor), in the middle of the screen (center), offset from the
+// topmost window (cascade) or where they overlap other windows least (smart)
+// Set via appearance.window_placement config
+var WindowPlacement = "cursor"
+
+// WindowPlacements are the valid values of WindowPlacement
+var WindowPlacements = []string{"cursor", "center", "cascade", "smart"}
+
+// MoveStep is the number of cells a key moves the focused window in
+// keyboard move mode, and MoveStepLarge the number with Shift held
+// Set via appearance.move_step and appearance.move_step_large config
+var (
+	MoveStep      = 1
+	MoveStepLarge = 5
+)
+
+// MinVisibleColumns is the number of columns of a window's title bar kept on
+// screen when the window is dragged or moved past the left or right edge; 0
+// lets windows leave the screen entirely
+// Set via appearance.min_visible_columns config
+var MinVisibleColumns = 20
+
+// VisibleColumns returns how many columns of a window width columns wide are
+// kept on screen: MinVisibleColumns, but no more than the window has.
+func VisibleColumns(width int) int {
+	return max(min(MinVisibleColumns, width), 0)
+}
+
+// EdgeResistance is the number of cells past a screen edge or the dock the
+// mouse has to drag a window before the window leaves the edge; until then
+// it sticks at the edge. 0 disables the resistance
+// Set via appearance.edge_resistance config
+var EdgeResistance = 4
+
+// ResizeBehavior is how floating windows follow a resize of the host
+// terminal: scaled with the screen (proportional) or kept at their size and
+// distance to the nearest edges (anchored)
+// Set via appearance.resize_behavior config
+var ResizeBehavior = "proportional"
+
+// ResizeBehaviors are the valid values of ResizeBehavior
+var ResizeBehaviors = []string{"proportional", "anchored"}
+
+// CompactWidth and CompactHeight are the screen size below which the
+// compact layout is used: the tiled windows of a workspace are stacked at
+// full size like tabs and the dock is hidden. 0 disables a limit
+// Set via appearance.compact_width and appearance.compact_height config
+var (
+	CompactWidth  = 0
+	CompactHeight = 0
+)
+
+// CompactScreen reports whether a screen of the given size is small enough
+// for the compact layout.
+func CompactScreen(width, height int) bool {
+	if width <= 0 || height <= 0 {
+		return false
+	}
+	return width < CompactWidth || height < CompactHeight
+}
+
+// VirtualOutputs is the number of side-by-side virtual outputs the screen
+// is split into (2-4)
+// Set via appearance.virtual_outputs config
+var VirtualOutputs = 2
+
+// WorkspaceBackAndForth makes switching to the current workspace by number
+// switch back to the previously active workspace instead
+// Set via appearance.workspace_back_and_forth config
+var WorkspaceBackAndForth bool
+
+// ScreensaverIdle is how long TUIOS waits without input before it shows the
+// screensaver (0 = disabled)
+// Set via appearance.screensaver_minutes config
+var ScreensaverIdle time.Duration
+
+// Screensaver is the screensaver animation: a drifting clock (clock),
+// falling characters (matrix) or growing pipes (pipes)
+// Set via appearance.screensaver config
+var Screensaver = "clock"
+
+// Screensavers are the valid values of Screensaver
+var Screensavers = []string{"clock", "matrix", "pipes"}
+
+// ScreensaverLock makes the screensaver a lock screen that only the unlock
+// chord dismisses
+// Set via appearance.screensaver_lock config
+var ScreensaverLock bool
+
+// RememberGeometry makes windows started with a command reopen with the
+// floating geometry the command's window last had
+// Set via appearance.remember_geometry config
+var RememberGeometry bool
+
+// ReplayIdleLimit caps the pauses between output chunks during replay, so
+// long idle periods do not stall playback
+const ReplayIdleLimit = 2 * time.Second
+
+// IndependentFocus lets each client attached to a daemon session keep its own
+// focused window, workspace and mode instead of following the other clients
+// Set via daemon.independent_focus config
+var IndependentFocus = false
+
+// SwallowWindows hides a window of a daemon session while a window opened
+// from a process running in it is open, putting the new window in its place
+// Set via daemon.swallow_windows config
+var SwallowWindows = false
+
 // LeaderKey is the prefix key for commands (default: ctrl+b)
 // Set via appearance.leader_key config
 var LeaderKey = "ctrl+b"
@@ -382,6 +679,14 @@ func GetDockIconWorkspaceCount() string {
 	return DockIconWorkspaceCount
 }
 
+// GetDockIconUrgentCount returns the appropriate urgent window count icon based on UseASCIIOnly
+func GetDockIconUrgentCount() string {
+	if UseASCIIOnly {
+		return DockIconUrgentCountASCII
+	}
+	return DockIconUrgentCount
+}
+
 // GetDockSeparator returns the appropriate separator based on UseASCIIOnly
 func GetDockSeparator() string {
 	if UseASCIIOnly {
@@ -636,6 +941,10 @@ const (
 	// ZIndexBase is the base z-index for regular windows
 	ZIndexBase = 0
 
+	// ZIndexPiP is the z-index for picture-in-picture windows, above all
+	// other windows
+	ZIndexPiP = 998
+
 	// ZIndexAnimating is the z-index for windows currently animating
 	ZIndexAnimating = 999
 
@@ -656,6 +965,10 @@ const (
 
 	// ZIndexNotifications is the z-index for notifications
 	ZIndexNotifications = 2000
+
+	// ZIndexScreensaver is the z-index for the screensaver, which covers
+	// everything
+	ZIndexScreensaver = 3000
 )
 
 // =============================================================================
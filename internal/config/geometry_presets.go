@@ -0,0 +1,44 @@
+package config
+
+// GeometryPreset is a floating window geometry that cycle_geometry_preset
+// moves the focused window to, in percent of the usable screen.
+type GeometryPreset struct {
+	Name   string `toml:"name"`   // Name shown when the preset is applied
+	X      int    `toml:"x"`      // Left edge, percent of screen width
+	Y      int    `toml:"y"`      // Top edge, percent of screen height
+	Width  int    `toml:"width"`  // Percent of screen width
+	Height int    `toml:"height"` // Percent of screen height
+}
+
+// Valid reports whether the preset lies within the screen and has a size.
+func (p GeometryPreset) Valid() bool {
+	return p.X >= 0 && p.Y >= 0 && p.Width > 0 && p.Height > 0 &&
+		p.X+p.Width <= 100 && p.Y+p.Height <= 100
+}
+
+// defaultGeometryPresets are cycled through when no presets are configured:
+// centered windows of growing size.
+var defaultGeometryPresets = []GeometryPreset{
+	{Name: "small", X: 25, Y: 20, Width: 50, Height: 60},
+	{Name: "medium", X: 15, Y: 10, Width: 70, Height: 80},
+	{Name: "large", X: 5, Y: 5, Width: 90, Height: 90},
+}
+
+// GeometryPresets are the configured geometry presets, in cycle order.
+// Set via [[geometry_presets]] config
+var GeometryPresets []GeometryPreset
+
+// ActiveGeometryPresets returns the valid configured geometry presets, or the
+// defaults when none is configured.
+func ActiveGeometryPresets() []GeometryPreset {
+	var presets []GeometryPreset
+	for _, preset := range GeometryPresets {
+		if preset.Valid() {
+			presets = append(presets, preset)
+		}
+	}
+	if len(presets) == 0 {
+		return defaultGeometryPresets
+	}
+	return presets
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HighlightRule paints the text matching a pattern in window output. Rules
+// apply at render time and leave the output itself untouched.
+type HighlightRule struct {
+	Pattern    string `toml:"pattern"`    // Regular expression painted in window output, e.g. "ERROR"
+	Color      string `toml:"color"`      // Foreground: a color name such as red, an ANSI color number or #rrggbb
+	Background string `toml:"background"` // Background, in the same forms as color
+	Bold       bool   `toml:"bold"`       // Render matches bold
+	Window     string `toml:"window"`     // Glob limiting the rule to windows whose title or custom name matches (default: every window)
+}
+
+// ANSIColorNames maps the color names highlight rules accept to the ANSI
+// colors of the theme.
+var ANSIColorNames = map[string]int{
+	"black": 0, "red": 1, "green": 2, "yellow": 3, "blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+	"bright_black": 8, "bright_red": 9, "bright_green": 10, "bright_yellow": 11,
+	"bright_blue": 12, "bright_magenta": 13, "bright_cyan": 14, "bright_white": 15,
+}
+
+// CompiledHighlight is a valid highlight rule with its pattern compiled.
+type CompiledHighlight struct {
+	HighlightRule
+	Regexp *regexp.Regexp
+}
+
+// validRuleColor reports whether a highlight color is empty, a color name,
+// an ANSI color number or a #rrggbb color.
+func validRuleColor(c string) bool {
+	if c == "" {
+		return true
+	}
+	if _, ok := ANSIColorNames[strings.ToLower(c)]; ok {
+		return true
+	}
+	if n, err := strconv.Atoi(c); err == nil {
+		return n >= 0 && n <= 255
+	}
+	if len(c) != 7 || c[0] != '#' {
+		return false
+	}
+	_, err := strconv.ParseUint(c[1:], 16, 32)
+	return err == nil
+}
+
+// Valid reports whether the rule has a valid pattern that matches text, and
+// valid colors or bold to paint it with.
+func (r HighlightRule) Valid() bool {
+	if r.Pattern == "" || !validRuleColor(r.Color) || !validRuleColor(r.Background) {
+		return false
+	}
+	if r.Color == "" && r.Background == "" && !r.Bold {
+		return false
+	}
+	re, err := regexp.Compile(r.Pattern)
+	// A pattern matching empty text would paint nothing
+	return err == nil && !re.MatchString("")
+}
+
+// AppliesTo reports whether the rule paints a window with any of the given
+// names.
+func (r HighlightRule) AppliesTo(names ...string) bool {
+	if r.Window == "" {
+		return true
+	}
+	for _, name := range names {
+		if name != "" && globMatch(r.Window, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightRules are the compiled highlight rules.
+var highlightRules []CompiledHighlight
+
+// SetHighlightRules compiles the valid highlight rules and makes them active.
+// Set via [[highlights]] config
+func SetHighlightRules(rules []HighlightRule) {
+	highlightRules = nil
+	for _, r := range rules {
+		if r.Valid() {
+			highlightRules = append(highlightRules, CompiledHighlight{HighlightRule: r, Regexp: regexp.MustCompile(r.Pattern)})
+		}
+	}
+}
+
+// HighlightRules returns the highlight rules that paint a window with any of
+// the given names.
+func HighlightRules(names ...string) []CompiledHighlight {
+	var rules []CompiledHighlight
+	for _, r := range highlightRules {
+		if r.AppliesTo(names...) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DefaultAppIcon is the Nerd Font icon of programs without one of their own.
+const DefaultAppIcon = "\uf489"
+
+// defaultAppIcons maps program names to the Nerd Font icons shown for them in
+// the dock and the sidebar.
+var defaultAppIcons = map[string]string{
+	"bash": "\ue795",
+	"zsh":  "\ue795",
+	"fish": "\ue795",
+	"sh":   "\ue795",
+	"dash": "\ue795",
+	"nu":   "\ue795",
+	"pwsh": "\ue795",
+
+	"vim":   "\ue7c5",
+	"nvim":  "\ue7c5",
+	"vi":    "\ue7c5",
+	"emacs": "\ue632",
+	"nano":  "\uf044",
+	"hx":    "\uf044",
+	"micro": "\uf044",
+
+	"ssh":  "\U000f08c0",
+	"mosh": "\U000f08c0",
+
+	"docker":         "\uf308",
+	"docker-compose": "\uf308",
+	"podman":         "\uf308",
+	"kubectl":        "\U000f10fe",
+	"k9s":            "\U000f10fe",
+
+	"python":  "\ue73c",
+	"ipython": "\ue73c",
+	"node":    "\ue718",
+	"npm":     "\ue71e",
+	"bun":     "\ue718",
+	"deno":    "\ue718",
+	"go":      "\ue627",
+	"cargo":   "\ue7a8",
+	"rustc":   "\ue7a8",
+	"ruby":    "\ue739",
+	"irb":     "\ue739",
+	"lua":     "\ue620",
+
+	"git":     "\ue702",
+	"lazygit": "\ue702",
+	"tig":     "\ue702",
+
+	"htop": "\uf080",
+	"btop": "\uf080",
+	"top":  "\uf080",
+
+	"man":  "\uf02d",
+	"less": "\uf02d",
+
+	"psql":      "\ue76e",
+	"mysql":     "\ue76e",
+	"sqlite3":   "\ue76e",
+	"redis-cli": "\ue76e",
+
+	"tmux":  "\uebc8",
+	"tuios": "\uf489",
+}
+
+// AppIcons are the configured program icons, which take precedence over the
+// defaults. An empty icon hides the program's icon.
+// Set via [icons] config
+var AppIcons map[string]string
+
+// ShowAppIcons controls whether dock and sidebar entries show the icon of
+// the program running in the window
+// Set via appearance.show_icons config
+var ShowAppIcons = true
+
+// AppIcon returns the icon of the named program, looked up by its base name
+// in lower case and then without a trailing version ("python3.12" finds
+// "python"). Unknown programs get DefaultAppIcon. It returns an empty string
+// when icons are hidden, including in ASCII-only mode.
+func AppIcon(name string) string {
+	if !ShowAppIcons || UseASCIIOnly {
+		return ""
+	}
+	name = strings.ToLower(filepath.Base(strings.TrimSpace(name)))
+	for _, key := range []string{name, strings.TrimRight(name, "0123456789.-")} {
+		if icon, ok := AppIcons[key]; ok {
+			return icon
+		}
+		if icon, ok := defaultAppIcons[key]; ok {
+			return icon
+		}
+	}
+	if icon, ok := AppIcons["default"]; ok {
+		return icon
+	}
+	return DefaultAppIcon
+}
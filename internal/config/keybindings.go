@@ -24,6 +24,10 @@ func GetPrefixKeybindings(prefixType string, isDaemonSession ...bool) []Keybindi
 		return []Keybinding{
 			{"1-9", "Switch to workspace"},
 			{"Shift+1-9", "Move window to workspace"},
+			{"p", "Previous workspace"},
+			{"s", "Split or join virtual outputs"},
+			{"o", "Focus next output"},
+			{"m", "Move window to next output"},
 			{"Esc", "Cancel"},
 		}
 	case "minimize":
@@ -31,6 +35,8 @@ func GetPrefixKeybindings(prefixType string, isDaemonSession ...bool) []Keybindi
 			{"m", "Minimize focused window"},
 			{"1-9", "Restore window"},
 			{"Shift+M", "Restore all"},
+			{"l", "List minimized windows"},
+			{"a", "Minimize all"},
 			{"Esc", "Cancel"},
 		}
 	case "window":
@@ -41,12 +47,15 @@ func GetPrefixKeybindings(prefixType string, isDaemonSession ...bool) []Keybindi
 			{"Tab", "Next window"},
 			{"Shift+Tab", "Previous window"},
 			{"t", "Toggle tiling mode"},
+			{"X", "Close all in workspace"},
 			{"Esc", "Cancel"},
 		}
 	case "debug":
 		return []Keybinding{
 			{"l", "Toggle log viewer"},
 			{"c", "Toggle cache statistics"},
+			{"m", "Toggle scrollback memory stats"},
+			{"w", "Open debug console window"},
 			{"k", "Toggle showkeys overlay"},
 			{"a", "Toggle animations"},
 			{"Esc", "Cancel"},
@@ -65,10 +74,39 @@ func GetPrefixKeybindings(prefixType string, isDaemonSession ...bool) []Keybindi
 			{",", "Rename window"},
 			{"n", "Next window"},
 			{"p", "Previous window"},
+			{"l", "Last window"},
+			{"u", "Urgent window"},
+			{"#", "Tag window"},
+			{"Z", "Zoom window content"},
+			{"/", "Find on screen"},
+			{"v", "Move window"},
+			{"g", "Gather windows"},
 			{"0-9", "Jump to window"},
 			{"-", "Split horizontal (top/bottom)"},
 			{"|/\\", "Split vertical (left/right)"},
 			{"R", "Rotate split direction"},
+			{"+/_", "Grow / shrink tile"},
+			{"Enter", "Promote to master"},
+			{"f", "Float / tile window"},
+			{"%/\"", "Split pane (left/right, top/bottom)"},
+			{"o/Arrows", "Select pane"},
+			{"Ctrl+Arrows", "Resize pane"},
+			{"X", "Close pane"},
+			{"!/J", "Break out / merge pane"},
+			{"C/</>", "New / switch tab"},
+			{"G/U", "Group / ungroup window"},
+			{"P", "Picture-in-picture"},
+			{"M", "Mouse to app / tuios"},
+			{"i", "Window info"},
+			{"h", "Replay output"},
+			{"j", "Jump to window"},
+			{"N", "Pass keys to nested TUIOS"},
+			{"L", "Lock window input"},
+			{"S", "Pin minimum size"},
+			{":", "Run command in popup"},
+			{"y/Y", "Copy / open last output"},
+			{"H", "Command history"},
+			{"`/'", "Set / jump to mark"},
 			{"w", "Workspace commands..."},
 			{"m", "Minimize commands..."},
 			{"t", "Window commands..."},
@@ -127,6 +165,8 @@ func GetKeybindings(registry *KeybindRegistry) []KeybindingSection {
 	addBinding(&windowMgmt, registry, "rename_window", "Rename window")
 	addBinding(&windowMgmt, registry, "minimize_window", "Minimize window")
 	addBinding(&windowMgmt, registry, "restore_all", "Restore all")
+	addBinding(&windowMgmt, registry, "minimize_all_windows", "Minimize all")
+	addBinding(&windowMgmt, registry, "close_all_windows", "Close all")
 	addBinding(&windowMgmt, registry, "next_window", "Next window")
 	addBinding(&windowMgmt, registry, "prev_window", "Previous window")
 	if len(windowMgmt.Bindings) > 0 {
@@ -150,6 +190,7 @@ func GetKeybindings(registry *KeybindRegistry) []KeybindingSection {
 		descMove := fmt.Sprintf("Move to workspace %d and follow", i)
 		addBinding(&workspaces, registry, actionMove, descMove)
 	}
+	addBinding(&workspaces, registry, "previous_workspace", "Switch to the previous workspace")
 	if len(workspaces.Bindings) > 0 {
 		sections = append(sections, workspaces)
 	}
@@ -205,6 +246,10 @@ func getDefaultKeybindings() []KeybindingSection {
 				{"%s+Shift+1-9", "Move window and follow"}, // %s will be replaced with modifier key
 				{"Ctrl+B, w, 1-9", "Switch workspace (prefix)"},
 				{"Ctrl+B, w, Shift+1-9", "Move window (prefix)"},
+				{"Ctrl+B, w, p", "Previous workspace (prefix)"},
+				{"Ctrl+B, w, s", "Split or join virtual outputs"},
+				{"Ctrl+B, w, o", "Focus next output"},
+				{"Ctrl+B, w, m", "Move window to next output"},
 			},
 		},
 		{
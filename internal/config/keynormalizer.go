@@ -54,6 +54,7 @@ var macOptionNumberMap = map[string]string{
 	"opt+7": "¶", "option+7": "¶",
 	"opt+8": "•", "option+8": "•",
 	"opt+9": "ª", "option+9": "ª",
+	"opt+0": "º", "option+0": "º",
 }
 
 // macOS Option+Shift key mappings
@@ -165,6 +166,11 @@ func (kn *KeyNormalizer) ValidateKey(key string) (bool, string) {
 		return false, "key cannot be empty"
 	}
 
+	// A lone + is the plus key, not a modifier separator
+	if keyLower == "+" {
+		return true, ""
+	}
+
 	// On non-macOS systems, error on opt/option keys
 	if !kn.isMacOS {
 		if strings.Contains(keyLower, "opt+") || strings.Contains(keyLower, "option+") {
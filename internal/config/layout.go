@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// LayoutFile describes a set of workspaces and their windows that can be
+// loaded at startup with --layout or applied to a running session.
+type LayoutFile struct {
+	Tiling     *bool             `toml:"tiling" yaml:"tiling"` // Tile the windows, using their geometry as split sizes (default: current tiling mode)
+	Workspaces []LayoutWorkspace `toml:"workspace" yaml:"workspace"`
+}
+
+// LayoutWorkspace describes the windows of one workspace.
+type LayoutWorkspace struct {
+	Number  int            `toml:"number" yaml:"number"` // Workspace number (default: 1 for the first entry, 2 for the second, ...)
+	Focus   string         `toml:"focus" yaml:"focus"`   // Name of the window to focus (default: the first window)
+	Windows []LayoutWindow `toml:"window" yaml:"window"`
+}
+
+// LayoutWindow describes a single window. Geometry is given in percent of the
+// usable screen area so layouts work on any terminal size.
+type LayoutWindow struct {
+	Name    string `toml:"name" yaml:"name"`       // Window name shown in the title bar
+	Command string `toml:"command" yaml:"command"` // Command line the window runs with the shell instead of an interactive shell
+	Cwd     string `toml:"cwd" yaml:"cwd"`         // Directory to start in (~ is expanded)
+	X       *int   `toml:"x" yaml:"x"`             // Left edge, percent of screen width
+	Y       *int   `toml:"y" yaml:"y"`             // Top edge, percent of screen height
+	Width   *int   `toml:"width" yaml:"width"`     // Percent of screen width
+	Height  *int   `toml:"height" yaml:"height"`   // Percent of screen height
+}
+
+// HasGeometry reports whether the window specifies all of x, y, width and height.
+func (w LayoutWindow) HasGeometry() bool {
+	return w.X != nil && w.Y != nil && w.Width != nil && w.Height != nil
+}
+
+// LoadLayoutFile reads and validates a layout file. Files named .yaml or
+// .yml are read as YAML, others as TOML.
+func LoadLayoutFile(path string) (*LayoutFile, error) {
+	data, err := os.ReadFile(ExpandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layout file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseLayoutYAML(data)
+	}
+	return ParseLayout(data)
+}
+
+// ParseLayout parses and validates TOML layout file contents.
+func ParseLayout(data []byte) (*LayoutFile, error) {
+	var layout LayoutFile
+	if err := toml.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("failed to parse layout file: %w", err)
+	}
+	return validateLayout(&layout)
+}
+
+// ParseLayoutYAML parses and validates YAML layout file contents, which take
+// the same keys as TOML ones.
+func ParseLayoutYAML(data []byte) (*LayoutFile, error) {
+	var layout LayoutFile
+	if err := yaml.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("failed to parse layout file: %w", err)
+	}
+	return validateLayout(&layout)
+}
+
+// validateLayout fills in the default workspace numbers of a parsed layout
+// file and checks it.
+func validateLayout(layout *LayoutFile) (*LayoutFile, error) {
+	if len(layout.Workspaces) == 0 {
+		return nil, fmt.Errorf("layout file has no workspace entries")
+	}
+
+	seen := make(map[int]bool)
+	for i := range layout.Workspaces {
+		ws := &layout.Workspaces[i]
+		if ws.Number == 0 {
+			ws.Number = i + 1
+		}
+		if ws.Number < 1 || ws.Number > 9 {
+			return nil, fmt.Errorf("workspace %d: number must be between 1 and 9", ws.Number)
+		}
+		if seen[ws.Number] {
+			return nil, fmt.Errorf("workspace %d is defined more than once", ws.Number)
+		}
+		seen[ws.Number] = true
+
+		for j, w := range ws.Windows {
+			if err := validateLayoutWindow(w); err != nil {
+				return nil, fmt.Errorf("workspace %d, window %d: %w", ws.Number, j+1, err)
+			}
+		}
+	}
+	return layout, nil
+}
+
+func validateLayoutWindow(w LayoutWindow) error {
+	set := 0
+	for _, v := range []*int{w.X, w.Y, w.Width, w.Height} {
+		if v == nil {
+			continue
+		}
+		set++
+		if *v < 0 || *v > 100 {
+			return fmt.Errorf("geometry must be a percentage between 0 and 100")
+		}
+	}
+	if set != 0 && set != 4 {
+		return fmt.Errorf("specify all of x, y, width and height, or none")
+	}
+	if set == 4 && (*w.Width == 0 || *w.Height == 0) {
+		return fmt.Errorf("width and height must be greater than 0")
+	}
+	return nil
+}
+
+// ExpandHome replaces a leading ~ with the user's home directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
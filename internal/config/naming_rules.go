@@ -0,0 +1,64 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// NamingRule names the windows running a command that matches a pattern.
+type NamingRule struct {
+	Command string `toml:"command"` // Regular expression matched against the command line running in the window, e.g. "^ssh .*?(\\S+)$"
+	Name    string `toml:"name"`    // Name given to matching windows; $1 or ${name} insert what the pattern's groups matched
+}
+
+// compiledNamingRule is a valid naming rule with its pattern compiled.
+type compiledNamingRule struct {
+	NamingRule
+	Regexp *regexp.Regexp
+}
+
+// Valid reports whether the rule has a name and a valid pattern.
+func (r NamingRule) Valid() bool {
+	if r.Command == "" || strings.TrimSpace(r.Name) == "" {
+		return false
+	}
+	_, err := regexp.Compile(r.Command)
+	return err == nil
+}
+
+// namingRules holds the compiled naming rules.
+var namingRules []compiledNamingRule
+
+// SetNamingRules compiles the valid naming rules and makes them active.
+// Set via [[naming_rules]] config
+func SetNamingRules(rules []NamingRule) {
+	namingRules = nil
+	for _, r := range rules {
+		if r.Valid() {
+			namingRules = append(namingRules, compiledNamingRule{NamingRule: r, Regexp: regexp.MustCompile(r.Command)})
+		}
+	}
+}
+
+// HasNamingRules reports whether any naming rule is active.
+func HasNamingRules() bool {
+	return len(namingRules) > 0
+}
+
+// RuleName returns the name the first naming rule matching a command line
+// gives its window. Rules whose name comes out empty are skipped.
+func RuleName(commandLine string) (string, bool) {
+	if commandLine == "" {
+		return "", false
+	}
+	for _, r := range namingRules {
+		match := r.Regexp.FindStringSubmatchIndex(commandLine)
+		if match == nil {
+			continue
+		}
+		if name := strings.TrimSpace(string(r.Regexp.ExpandString(nil, r.Name, commandLine, match))); name != "" {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -90,8 +90,20 @@ func ApplyOverrides(overrides Overrides, userConfig *UserConfig) {
 			lines = 1000000
 		}
 		ScrollbackLines = lines
-	} else if userConfig != nil && userConfig.Appearance.ScrollbackLines > 0 {
-		ScrollbackLines = userConfig.Appearance.ScrollbackLines
+	} else if userConfig != nil && userConfig.Appearance.ScrollbackLines != nil {
+		ScrollbackLines = max(*userConfig.Appearance.ScrollbackLines, 0)
+	}
+
+	// Window and naming rules, icons, geometry presets, triggers, highlights
+	// and workspace templates - only from user config
+	if userConfig != nil {
+		WindowRules = userConfig.WindowRules
+		SetNamingRules(userConfig.NamingRules)
+		AppIcons = userConfig.Icons
+		GeometryPresets = userConfig.GeometryPresets
+		SetTriggers(userConfig.Triggers)
+		SetHighlightRules(userConfig.Highlights)
+		WorkspaceTemplates = userConfig.WorkspaceTemplates
 	}
 
 	// Leader Key - only from user config
@@ -104,10 +116,14 @@ func ApplyOverrides(overrides Overrides, userConfig *UserConfig) {
 		AnimationsEnabled = false
 	}
 
-	// Theme - initialize if specified
-	if overrides.ThemeName != "" {
-		if err := theme.Initialize(overrides.ThemeName); err != nil {
-			log.Printf("Warning: Failed to load theme '%s': %v", overrides.ThemeName, err)
+	// Theme - CLI flag takes precedence, otherwise use user config
+	themeName := overrides.ThemeName
+	if themeName == "" && userConfig != nil {
+		themeName = userConfig.Appearance.Theme
+	}
+	if themeName != "" {
+		if err := theme.Initialize(themeName); err != nil {
+			log.Printf("Warning: Failed to load theme '%s': %v", themeName, err)
 		}
 	}
 }
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"maps"
+	"slices"
+)
+
+// KeybindPresets lists the keybinding presets that can be selected with
+// keybindings.preset, keyed by name. Each preset only lists the bindings it
+// changes from the defaults.
+var KeybindPresets = map[string]KeybindingsConfig{
+	// tmux mirrors tmux's default prefix bindings
+	"tmux": {
+		PrefixMode: map[string][]string{
+			"prefix_close_pane":      {"x"},
+			"prefix_close_window":    {"&"},
+			"prefix_rename_window":   {","},
+			"prefix_next_window":     {"n"},
+			"prefix_prev_window":     {"p"},
+			"prefix_rotate_split":    {"ctrl+o"},
+			"prefix_equalize_splits": {"E"},
+			"prefix_sidebar":         {"s"},
+			"prefix_quit":            {"Q"}, // tmux has no quit binding, keep q free
+		},
+	},
+}
+
+// PresetNames returns the names of the keybinding presets, sorted.
+func PresetNames() []string {
+	return slices.Sorted(maps.Keys(KeybindPresets))
+}
+
+// applyKeybindPreset applies the preset selected in cfg to its prefix mode
+// bindings. A preset binding replaces the user's binding for the action when
+// it is missing or unchanged from the default, so explicit customizations
+// still win over the preset.
+func applyKeybindPreset(cfg, defaultCfg *UserConfig) {
+	preset, ok := KeybindPresets[cfg.Keybindings.Preset]
+	if !ok {
+		return
+	}
+	for action, keys := range preset.PrefixMode {
+		current, exists := cfg.Keybindings.PrefixMode[action]
+		if !exists || slices.Equal(current, defaultCfg.Keybindings.PrefixMode[action]) {
+			cfg.Keybindings.PrefixMode[action] = keys
+		}
+	}
+}
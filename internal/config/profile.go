@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/adrg/xdg"
+	"github.com/pelletier/go-toml/v2"
+)
+
+var (
+	profileName string
+	profileMu   sync.RWMutex
+
+	validProfileName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// SetProfile selects the config profile merged over the base config by
+// LoadUserConfig. An empty name uses the base config only.
+func SetProfile(name string) error {
+	if name != "" && !validProfileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '-' and '_'", name)
+	}
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	profileName = name
+	return nil
+}
+
+// GetProfile returns the selected config profile, or "" if none.
+func GetProfile() string {
+	profileMu.RLock()
+	defer profileMu.RUnlock()
+	return profileName
+}
+
+// GetProfilePath returns the path of the selected profile's file,
+// $XDG_CONFIG_HOME/tuios/profiles/<name>.toml, or "" if no profile is selected.
+func GetProfilePath() (string, error) {
+	name := GetProfile()
+	if name == "" {
+		return "", nil
+	}
+	path, err := xdg.ConfigFile("tuios/profiles/" + name + ".toml")
+	if err != nil {
+		return "", fmt.Errorf("failed to get profile path: %w", err)
+	}
+	return path, nil
+}
+
+// applyProfile merges the selected profile over base config file contents.
+// Tables are merged key by key, so a profile only needs the options it
+// changes; any other value, including key binding lists, replaces the base.
+func applyProfile(base []byte) ([]byte, error) {
+	path, err := GetProfilePath()
+	if err != nil || path == "" {
+		return base, err
+	}
+
+	// #nosec G304 - path is the user's profile file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile %q: %w", GetProfile(), err)
+	}
+
+	return mergeConfigData(base, data)
+}
+
+// mergeConfigData deep-merges the TOML document overlay over base.
+func mergeConfigData(base, overlay []byte) ([]byte, error) {
+	baseTree := map[string]any{}
+	if err := toml.Unmarshal(base, &baseTree); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	overlayTree := map[string]any{}
+	if err := toml.Unmarshal(overlay, &overlayTree); err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %w", err)
+	}
+
+	mergeTables(baseTree, overlayTree)
+	data, err := toml.Marshal(baseTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge profile: %w", err)
+	}
+	return data, nil
+}
+
+func mergeTables(dst, src map[string]any) {
+	for key, value := range src {
+		srcTable, srcIsTable := value.(map[string]any)
+		dstTable, dstIsTable := dst[key].(map[string]any)
+		if srcIsTable && dstIsTable {
+			mergeTables(dstTable, srcTable)
+			continue
+		}
+		dst[key] = value
+	}
+}
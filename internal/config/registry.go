@@ -1,6 +1,7 @@
 package config
 
 import (
+	"slices"
 	"strings"
 )
 
@@ -68,6 +69,35 @@ func (r *KeybindRegistry) GetPrefixAction(key string) string {
 	return r.lookupKeyInSection(key, r.config.Keybindings.PrefixMode)
 }
 
+// splitPaneActions are the prefix mode actions that only apply to a window
+// split into panes
+var splitPaneActions = []string{
+	"prefix_pane_left", "prefix_pane_right", "prefix_pane_up", "prefix_pane_down",
+	"prefix_resize_pane_left", "prefix_resize_pane_right", "prefix_resize_pane_up", "prefix_resize_pane_down",
+}
+
+// GetPrefixActionForSplit returns the action name for a given key in the main
+// prefix mode. The pane navigation and resize actions are only bound while
+// split is set, so their keys run any other action bound to them while the
+// focused window is not split.
+func (r *KeybindRegistry) GetPrefixActionForSplit(key string, split bool) string {
+	panes := make(map[string][]string)
+	others := make(map[string][]string)
+	for action, keys := range r.config.Keybindings.PrefixMode {
+		if slices.Contains(splitPaneActions, action) {
+			panes[action] = keys
+		} else {
+			others[action] = keys
+		}
+	}
+	if split {
+		if action := r.lookupKeyInSection(key, panes); action != "" {
+			return action
+		}
+	}
+	return r.lookupKeyInSection(key, others)
+}
+
 // GetWindowPrefixAction returns the action name for a given key in window prefix mode (Ctrl+B, t)
 func (r *KeybindRegistry) GetWindowPrefixAction(key string) string {
 	return r.lookupKeyInSection(key, r.config.Keybindings.WindowPrefix)
@@ -93,6 +123,12 @@ func (r *KeybindRegistry) GetTapePrefixAction(key string) string {
 	return r.lookupKeyInSection(key, r.config.Keybindings.TapePrefix)
 }
 
+// GetTerminalModeAction returns the action name for a given key among the
+// direct keybinds of terminal mode
+func (r *KeybindRegistry) GetTerminalModeAction(key string) string {
+	return r.lookupKeyInSection(key, r.config.Keybindings.TerminalMode)
+}
+
 // lookupKeyInSection looks up a key in a specific config section
 func (r *KeybindRegistry) lookupKeyInSection(key string, section map[string][]string) string {
 	// Build a temporary map for this section
@@ -231,22 +267,24 @@ func (r *KeybindRegistry) IsActionExplicitlyConfigured(action string) bool {
 // ActionDescriptions maps action names to their descriptions for help menu generation.
 var ActionDescriptions = map[string]string{
 	// Window Management
-	"new_window":      "New window",
-	"close_window":    "Close window",
-	"rename_window":   "Rename window",
-	"minimize_window": "Minimize window",
-	"restore_all":     "Restore all minimized",
-	"next_window":     "Next window",
-	"prev_window":     "Previous window",
-	"select_window_1": "Select window 1",
-	"select_window_2": "Select window 2",
-	"select_window_3": "Select window 3",
-	"select_window_4": "Select window 4",
-	"select_window_5": "Select window 5",
-	"select_window_6": "Select window 6",
-	"select_window_7": "Select window 7",
-	"select_window_8": "Select window 8",
-	"select_window_9": "Select window 9",
+	"new_window":           "New window",
+	"close_window":         "Close window",
+	"rename_window":        "Rename window",
+	"minimize_window":      "Minimize window",
+	"restore_all":          "Restore all minimized",
+	"close_all_windows":    "Close all windows in workspace",
+	"minimize_all_windows": "Minimize all windows in workspace",
+	"next_window":          "Next window",
+	"prev_window":          "Previous window",
+	"select_window_1":      "Select window 1",
+	"select_window_2":      "Select window 2",
+	"select_window_3":      "Select window 3",
+	"select_window_4":      "Select window 4",
+	"select_window_5":      "Select window 5",
+	"select_window_6":      "Select window 6",
+	"select_window_7":      "Select window 7",
+	"select_window_8":      "Select window 8",
+	"select_window_9":      "Select window 9",
 
 	// Workspaces
 	"switch_workspace_1": "Switch to workspace 1",
@@ -258,6 +296,7 @@ var ActionDescriptions = map[string]string{
 	"switch_workspace_7": "Switch to workspace 7",
 	"switch_workspace_8": "Switch to workspace 8",
 	"switch_workspace_9": "Switch to workspace 9",
+	"previous_workspace": "Switch to the previous workspace",
 	"move_and_follow_1":  "Move to workspace 1 and follow",
 	"move_and_follow_2":  "Move to workspace 2 and follow",
 	"move_and_follow_3":  "Move to workspace 3 and follow",
@@ -277,7 +316,17 @@ var ActionDescriptions = map[string]string{
 	"snap_corner_2":             "Snap to top-right",
 	"snap_corner_3":             "Snap to bottom-left",
 	"snap_corner_4":             "Snap to bottom-right",
+	"snap_top":                  "Snap to top half",
+	"snap_bottom":               "Snap to bottom half",
+	"snap_third_left":           "Snap to left third",
+	"snap_third_center":         "Snap to middle third",
+	"snap_third_right":          "Snap to right third",
+	"snap_two_thirds_left":      "Snap to left two thirds",
+	"snap_two_thirds_right":     "Snap to right two thirds",
+	"center_window":             "Center window",
+	"cycle_geometry_preset":     "Cycle geometry presets",
 	"toggle_tiling":             "Toggle tiling mode",
+	"toggle_floating":           "Float or tile focused window",
 	"swap_left":                 "Swap left",
 	"swap_right":                "Swap right",
 	"swap_up":                   "Swap up",
@@ -292,14 +341,18 @@ var ActionDescriptions = map[string]string{
 	"resize_height_grow_top":    "Increase focused window height from top edge",
 
 	// BSP Tiling
-	"split_horizontal": "Split window horizontally (top/bottom)",
-	"split_vertical":   "Split window vertically (left/right)",
-	"rotate_split":     "Rotate split direction",
-	"equalize_splits":  "Equalize all split ratios",
-	"preselect_left":   "Preselect left for next window",
-	"preselect_right":  "Preselect right for next window",
-	"preselect_up":     "Preselect up for next window",
-	"preselect_down":   "Preselect down for next window",
+	"split_horizontal":    "Split window horizontally (top/bottom)",
+	"split_vertical":      "Split window vertically (left/right)",
+	"rotate_split":        "Rotate split direction",
+	"equalize_splits":     "Equalize all split ratios",
+	"grow_tile":           "Grow focused window's share of the layout",
+	"shrink_tile":         "Shrink focused window's share of the layout",
+	"promote_master":      "Swap focused window with the master window",
+	"cycle_tiling_insert": "Cycle where new windows are tiled",
+	"preselect_left":      "Preselect left for next window",
+	"preselect_right":     "Preselect right for next window",
+	"preselect_up":        "Preselect up for next window",
+	"preselect_down":      "Preselect down for next window",
 
 	// Mode Control
 	"enter_terminal_mode": "Enter terminal mode",
@@ -332,6 +385,7 @@ var ActionDescriptions = map[string]string{
 	"prefix_select_8":         "Jump to window 8",
 	"prefix_select_9":         "Jump to window 9",
 	"prefix_toggle_tiling":    "Toggle tiling mode",
+	"prefix_toggle_floating":  "Float or tile focused window",
 	"prefix_workspace":        "Enter workspace prefix",
 	"prefix_minimize":         "Enter minimize prefix",
 	"prefix_window":           "Enter window prefix",
@@ -343,12 +397,62 @@ var ActionDescriptions = map[string]string{
 	"prefix_tape":             "Enter tape manager prefix",
 	"prefix_quit":             "Quit (daemon: kills session)",
 	"prefix_fullscreen":       "Fullscreen current window",
+	"prefix_pip":              "Toggle picture-in-picture",
+	"prefix_mouse_capture":    "Toggle mouse passthrough for the focused window",
+	"prefix_window_info":      "Show window info and set its scrollback limit",
+	"prefix_replay":           "Replay the focused window's recent output",
+	"prefix_window_hints":     "Label the visible windows and jump to one",
+	"prefix_image_hints":      "Label the image files named on screen and open one in the image viewer",
+	"prefix_key_passthrough":  "Send all keys, prefix included, to the focused window",
+	"prefix_lock_input":       "Lock the focused window's input",
+	"prefix_pin_min_size":     "Pin or clear the focused window's minimum size",
+	"prefix_run_command":      "Run a shell command and show its output in a popup",
+	"prefix_copy_last_output": "Copy the last command's output to the clipboard",
+	"prefix_open_last_output": "Open the last command's output in a new window",
+	"prefix_command_history":  "Search the commands run in every window",
+	"prefix_set_mark":         "Set a mark (a letter) on the focused window",
+	"prefix_jump_mark":        "Jump to the window with a mark",
+	"prefix_last_window":      "Switch to the last focused window",
+	"prefix_urgent_window":    "Jump to the oldest urgent window",
+	"prefix_tag_window":       "Edit the tags of the focused window",
+	"prefix_zoom_window":      "Draw the focused window's content at double size",
+	"prefix_find_on_screen":   "Find text shown in the windows and focus the window showing it",
+	"prefix_move_window":      "Move the focused floating window with the keyboard",
+	"prefix_gather_windows":   "Pull floating windows off screen back into view",
 	"prefix_split_horizontal": "Split window horizontally",
 	"prefix_split_vertical":   "Split window vertically",
 	"prefix_rotate_split":     "Rotate split direction",
 	"prefix_equalize_splits":  "Equalize all splits",
+	"prefix_grow_tile":        "Grow focused window's share of the layout",
+	"prefix_shrink_tile":      "Shrink focused window's share of the layout",
+	"prefix_promote_master":   "Swap focused window with the master window",
 	"prefix_sidebar":          "Toggle window sidebar",
 
+	// Panes
+	"prefix_split_pane_vertical":   "Split pane vertically (left/right)",
+	"prefix_split_pane_horizontal": "Split pane horizontally (top/bottom)",
+	"prefix_close_pane":            "Close current pane or tab",
+	"prefix_next_pane":             "Next pane",
+	"prefix_pane_left":             "Focus pane to the left",
+	"prefix_pane_right":            "Focus pane to the right",
+	"prefix_pane_up":               "Focus pane above",
+	"prefix_pane_down":             "Focus pane below",
+	"prefix_resize_pane_left":      "Move pane split left",
+	"prefix_resize_pane_right":     "Move pane split right",
+	"prefix_resize_pane_up":        "Move pane split up",
+	"prefix_resize_pane_down":      "Move pane split down",
+	"prefix_break_pane":            "Break pane out into its own window",
+	"prefix_join_pane":             "Merge another window into this one as a pane",
+
+	// Tabs
+	"prefix_new_tab":  "New tab in current window",
+	"prefix_next_tab": "Next tab",
+	"prefix_prev_tab": "Previous tab",
+
+	// Window groups
+	"prefix_group_window":   "Group another window with this one",
+	"prefix_ungroup_window": "Remove window from its group",
+
 	// Tape Prefix
 	"tape_prefix_manager": "Open tape manager",
 	"tape_prefix_record":  "Start recording",
@@ -365,11 +469,15 @@ var ActionDescriptions = map[string]string{
 	// Debug Prefix
 	"debug_prefix_logs":       "Toggle log viewer",
 	"debug_prefix_cache":      "Toggle cache statistics",
+	"debug_prefix_scrollback": "Toggle scrollback memory statistics",
+	"debug_prefix_console":    "Open debug console window",
 	"debug_prefix_animations": "Toggle animations",
 	"debug_prefix_cancel":     "Cancel debug prefix",
 
 	// Terminal Mode (direct keybinds, no prefix required)
-	"terminal_next_window": "Next window (terminal mode)",
-	"terminal_prev_window": "Previous window (terminal mode)",
-	"terminal_exit_mode":   "Exit terminal mode (to window mode)",
+	"terminal_next_window":      "Next window (terminal mode)",
+	"terminal_prev_window":      "Previous window (terminal mode)",
+	"terminal_exit_mode":        "Exit terminal mode (to window mode)",
+	"terminal_exit_passthrough": "Stop sending all keys to the focused window",
+	"terminal_unlock_input":     "Unlock the focused window's input",
 }
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// titleFieldPattern matches the {field} placeholders of a title format.
+var titleFieldPattern = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// TitleFormatFields returns the names of the fields a title format uses, in
+// order.
+func TitleFormatFields(format string) []string {
+	var fields []string
+	for _, match := range titleFieldPattern.FindAllStringSubmatch(format, -1) {
+		fields = append(fields, match[1])
+	}
+	return fields
+}
+
+// ExpandTitleFormat fills in the fields of a title format with the values
+// returned by value. Unknown fields are kept as written. A part in
+// [brackets] is dropped when all of its fields are empty, so separators
+// around a missing field go with it.
+func ExpandTitleFormat(format string, value func(field string) string) string {
+	var b strings.Builder
+	for format != "" {
+		start := strings.IndexByte(format, '[')
+		end := -1
+		if start >= 0 {
+			end = strings.IndexByte(format[start:], ']')
+		}
+		if start < 0 || end < 0 {
+			b.WriteString(expandTitleFields(format, value))
+			break
+		}
+		b.WriteString(expandTitleFields(format[:start], value))
+		part := format[start+1 : start+end]
+		if expanded, filled := expandTitlePart(part, value); filled {
+			b.WriteString(expanded)
+		}
+		format = format[start+end+1:]
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// expandTitlePart expands an optional part of a title format, reporting
+// whether it has a field with a value or no field at all.
+func expandTitlePart(part string, value func(string) string) (string, bool) {
+	filled, fields := false, 0
+	expanded := titleFieldPattern.ReplaceAllStringFunc(part, func(match string) string {
+		field := match[1 : len(match)-1]
+		if !slices.Contains(TitleFields, field) {
+			return match
+		}
+		fields++
+		v := value(field)
+		filled = filled || v != ""
+		return v
+	})
+	return expanded, filled || fields == 0
+}
+
+// expandTitleFields fills in the known fields of text.
+func expandTitleFields(text string, value func(string) string) string {
+	expanded, _ := expandTitlePart(text, value)
+	return expanded
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"regexp"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Trigger runs an action when a window prints a line matching a pattern.
+type Trigger struct {
+	Pattern string `toml:"pattern"` // Regular expression matched against each line of window output, e.g. "panic:"
+	Action  string `toml:"action"`  // notify, urgent, focus or hook (default: notify)
+	Command string `toml:"command"` // Shell command run by the hook action
+	Window  string `toml:"window"`  // Glob limiting the trigger to windows whose title or custom name matches (default: every window)
+}
+
+// TriggerActions are the actions a trigger can run.
+var TriggerActions = []string{"notify", "urgent", "focus", "hook"}
+
+// TriggerCooldown is how long a trigger stays quiet in a window after it
+// fired there, so a flood of matching lines runs its action once.
+const TriggerCooldown = 5 * time.Second
+
+// CompiledTrigger is a valid trigger with its pattern compiled.
+type CompiledTrigger struct {
+	Trigger
+	Regexp *regexp.Regexp
+}
+
+// Valid reports whether the trigger has a valid pattern and action, and a
+// command if it runs a hook.
+func (t Trigger) Valid() bool {
+	if t.Pattern == "" || (t.Action != "" && !slices.Contains(TriggerActions, t.Action)) {
+		return false
+	}
+	if t.Action == "hook" && t.Command == "" {
+		return false
+	}
+	_, err := regexp.Compile(t.Pattern)
+	return err == nil
+}
+
+// AppliesTo reports whether the trigger watches a window with any of the
+// given names.
+func (t Trigger) AppliesTo(names ...string) bool {
+	if t.Window == "" {
+		return true
+	}
+	for _, name := range names {
+		if name != "" && globMatch(t.Window, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// activeTriggers holds the compiled triggers. Window output is scanned from
+// the PTY reader goroutines while a config reload may replace them.
+var activeTriggers atomic.Pointer[[]CompiledTrigger]
+
+// SetTriggers compiles the valid triggers and makes them active.
+// Set via [[triggers]] config
+func SetTriggers(triggers []Trigger) {
+	var compiled []CompiledTrigger
+	for _, t := range triggers {
+		if !t.Valid() {
+			continue
+		}
+		if t.Action == "" {
+			t.Action = "notify"
+		}
+		compiled = append(compiled, CompiledTrigger{Trigger: t, Regexp: regexp.MustCompile(t.Pattern)})
+	}
+	activeTriggers.Store(&compiled)
+}
+
+// ActiveTriggers returns the triggers window output is matched against.
+func ActiveTriggers() []CompiledTrigger {
+	if triggers := activeTriggers.Load(); triggers != nil {
+		return *triggers
+	}
+	return nil
+}
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/adrg/xdg"
@@ -14,9 +17,24 @@ import (
 
 // UserConfig represents the user's custom configuration
 type UserConfig struct {
-	Appearance  AppearanceConfig  `toml:"appearance"`
-	Keybindings KeybindingsConfig `toml:"keybindings"`
-	Daemon      DaemonConfig      `toml:"daemon"`
+	Appearance      AppearanceConfig  `toml:"appearance"`
+	Keybindings     KeybindingsConfig `toml:"keybindings"`
+	Daemon          DaemonConfig      `toml:"daemon"`
+	Debug           DebugConfig       `toml:"debug"`
+	WindowRules     []WindowRule      `toml:"window_rules"`
+	NamingRules     []NamingRule      `toml:"naming_rules"`     // Names given to windows by the command running in them
+	Icons           map[string]string `toml:"icons"`            // Program name to Nerd Font icon, overriding the defaults
+	GeometryPresets []GeometryPreset  `toml:"geometry_presets"` // Floating geometries cycled through with cycle_geometry_preset
+	Triggers        []Trigger         `toml:"triggers"`         // Actions run when window output matches a pattern
+	Highlights      []HighlightRule   `toml:"highlights"`       // Patterns painted in window output at render time
+
+	WorkspaceTemplates []WorkspaceTemplate `toml:"workspace_templates"` // Layouts filling workspaces the first time they are switched to
+}
+
+// DebugConfig holds developer diagnostics settings
+type DebugConfig struct {
+	PprofAddr   string `toml:"pprof_addr"`   // Serve pprof and expvar metrics on this loopback address, e.g. localhost:6060 (default: disabled)
+	MetricsAddr string `toml:"metrics_addr"` // Serve Prometheus metrics at /metrics on this loopback address, e.g. localhost:9464 (default: disabled)
 }
 
 // DaemonConfig holds daemon-related settings
@@ -24,25 +42,67 @@ type DaemonConfig struct {
 	LogLevel     string `toml:"log_level"`     // Debug log level: off, errors, basic, messages, verbose, trace (default: off)
 	DefaultCodec string `toml:"default_codec"` // Default protocol codec: gob, json (default: gob)
 	SocketPath   string `toml:"socket_path"`   // Custom socket path (default: $XDG_RUNTIME_DIR/tuios/daemon.sock)
+	WindowSize   string `toml:"window_size"`   // Size of sessions shared by several clients: smallest, latest (default: smallest)
+
+	IndependentFocus bool `toml:"independent_focus"` // Keep this client's focus, workspace and mode when other clients change theirs (default: false)
+	SwallowWindows   bool `toml:"swallow_windows"`   // Hide a window while a window opened from its shell with run-command NewWindow is open (default: false)
 }
 
 // AppearanceConfig holds appearance-related settings
 type AppearanceConfig struct {
-	BorderStyle         string `toml:"border_style"`          // Border style: rounded, normal, thick, double, hidden, block, ascii, outer-half-block, inner-half-block (borderless mode not yet implemented)
-	HideWindowButtons   bool   `toml:"hide_window_buttons"`   // Hide window control buttons (minimize, maximize, close)
-	ScrollbackLines     int    `toml:"scrollback_lines"`      // Number of lines to keep in scrollback buffer (default: 10000, min: 100, max: 1000000)
-	DockbarPosition     string `toml:"dockbar_position"`      // Dockbar position: bottom, top, hidden
-	PreferredShell      string `toml:"preferred_shell"`       // Preferred shell: if empty, auto-detect based on platform.
-	AnimationsEnabled   *bool  `toml:"animations_enabled"`    // Enable UI animations (default: true). Set to false for instant transitions.
-	WhichKeyEnabled     *bool  `toml:"whichkey_enabled"`      // Show which-key popup after pressing leader key (default: true)
-	WhichKeyPosition    string `toml:"whichkey_position"`     // Which-key popup position: bottom-right, bottom-left, top-right, top-left, center (default: bottom-right)
-	WindowTitlePosition string `toml:"window_title_position"` // Window title position: bottom, top, hidden (default: bottom). Shows CustomName if set, else terminal title.
-	HideClock           bool   `toml:"hide_clock"`            // Hide the clock overlay (default: false)
+	BorderStyle           string `toml:"border_style"`                // Border style: rounded, normal, thick, double, hidden, block, ascii, outer-half-block, inner-half-block (borderless mode not yet implemented)
+	HideWindowButtons     bool   `toml:"hide_window_buttons"`         // Hide window control buttons (minimize, maximize, close)
+	ScrollbackLines       *int   `toml:"scrollback_lines"`            // Number of lines to keep in scrollback buffer (default: 10000, 0 = no scrollback, min: 100, max: 1000000)
+	DockbarPosition       string `toml:"dockbar_position"`            // Dockbar position: bottom, top, hidden
+	DockWrap              bool   `toml:"dock_wrap"`                   // Wrap the dock's window pills onto a second row when they do not fit on one (default: false)
+	DockStatusSide        string `toml:"dock_status_side"`            // End of the dock with the status widgets, the other has the mode and workspace: right, left (default: right)
+	PreferredShell        string `toml:"preferred_shell"`             // Preferred shell: if empty, auto-detect based on platform.
+	AnimationsEnabled     *bool  `toml:"animations_enabled"`          // Enable UI animations (default: true). Set to false for instant transitions.
+	WhichKeyEnabled       *bool  `toml:"whichkey_enabled"`            // Show which-key popup after pressing leader key (default: true)
+	WhichKeyPosition      string `toml:"whichkey_position"`           // Which-key popup position: bottom-right, bottom-left, top-right, top-left, center (default: bottom-right)
+	WindowTitlePosition   string `toml:"window_title_position"`       // Window title position: bottom, top, hidden (default: bottom). Shows CustomName if set, else terminal title.
+	TitleFormat           string `toml:"title_format"`                // Window title template with fields such as {name}, {cwd}, {command}, {flags} (default: {name})
+	TitleAlign            string `toml:"title_align"`                 // Window title alignment: left, center, right (default: left on top, centered at the bottom)
+	HideClock             bool   `toml:"hide_clock"`                  // Hide the clock overlay (default: false)
+	ConfirmMultilinePaste *bool  `toml:"confirm_multiline_paste"`     // Confirm multi-line pastes into a shell without bracketed paste (default: true)
+	ConfirmQuit           string `toml:"confirm_quit"`                // When quitting asks first: always, running, never (default: running)
+	ClipboardWrite        string `toml:"clipboard_write"`             // What to do when a program sets the clipboard with OSC 52: allow, confirm, deny (default: allow)
+	ClipboardWriteMaxKB   *int   `toml:"clipboard_write_max_kb"`      // Largest OSC 52 clipboard write in KiB; larger ones are dropped (default: 1024, 0 = unlimited)
+	PasteChunkSize        int    `toml:"paste_chunk_size"`            // Pastes larger than this many bytes are streamed in chunks (default: 4096)
+	MouseOverrideModifier string `toml:"mouse_override_modifier"`     // Modifier that keeps the mouse in tuios while an app captures it: shift, alt, ctrl, none (default: shift)
+	CopyOnSelect          bool   `toml:"copy_on_select"`              // Copy text to the clipboard as soon as it is selected with the mouse (default: false)
+	MiddleClickPaste      bool   `toml:"middle_click_paste"`          // Middle-click pastes the text last selected with the mouse (default: false)
+	AmbiguousWidth        int    `toml:"ambiguous_width"`             // Cells used by East Asian ambiguous-width characters: 1 or 2 (default: 1)
+	EmojiWidth            int    `toml:"emoji_width"`                 // Cells used by emoji: 1 or 2 (default: 2)
+	ScrollbackBudgetMB    *int   `toml:"scrollback_memory_budget_mb"` // Total scrollback memory across all windows in MiB; least recently viewed windows are trimmed first (default: 512, 0 = unlimited)
+	ScrollbackWindowMB    *int   `toml:"scrollback_window_memory_mb"` // Scrollback memory per window in MiB (default: 128, 0 = unlimited)
+	AutosaveInterval      *int   `toml:"autosave_interval"`           // Seconds between layout autosaves for crash recovery (default: 30, 0 = disabled)
+	ReplayBufferKB        *int   `toml:"replay_buffer_kb"`            // KiB of recent output each window records for replay (default: 1024, 0 = disabled, max: 65536)
+	ShutdownGraceMS       *int   `toml:"shutdown_grace_ms"`           // Milliseconds closed windows' programs get to exit before they are killed (default: 1000, max: 10000)
+	TilingInsert          string `toml:"tiling_insert"`               // Where new windows enter the tiling order: end, after_focused, master (default: end)
+	WindowPlacement       string `toml:"window_placement"`            // Where new floating windows open: cursor, center, cascade, smart (default: cursor)
+	MoveStep              int    `toml:"move_step"`                   // Cells a key moves a window in keyboard move mode (default: 1)
+	MoveStepLarge         int    `toml:"move_step_large"`             // Cells a key moves a window with Shift held in keyboard move mode (default: 5)
+	MinVisibleColumns     *int   `toml:"min_visible_columns"`         // Columns of a window's title bar kept on screen when it is dragged or moved past an edge (default: 20, 0 = disabled)
+	EdgeResistance        *int   `toml:"edge_resistance"`             // Cells a dragged window sticks at the screen edges and the dock before it is pushed past them (default: 4, 0 = disabled, max: 50)
+	ResizeBehavior        string `toml:"resize_behavior"`             // How floating windows follow a host terminal resize: proportional, anchored (default: proportional)
+	CompactWidth          int    `toml:"compact_width"`               // Screen columns below which the compact layout is used: tiled windows stacked as tabs, no dock (default: 0 = disabled)
+	CompactHeight         int    `toml:"compact_height"`              // Screen rows below which the compact layout is used (default: 0 = disabled)
+	VirtualOutputs        int    `toml:"virtual_outputs"`             // Number of side-by-side outputs Ctrl+B w s splits the screen into: 2-4 (default: 2)
+	WorkspaceBackAndForth bool   `toml:"workspace_back_and_forth"`    // Switching to the current workspace by number goes back to the previous one (default: false)
+	ScreensaverMinutes    int    `toml:"screensaver_minutes"`         // Minutes without input before the screensaver starts (default: 0 = disabled, max: 1440)
+	Screensaver           string `toml:"screensaver"`                 // Screensaver animation: clock, matrix, pipes (default: clock)
+	ScreensaverLock       bool   `toml:"screensaver_lock"`            // Only the unlock chord (terminal_unlock_input) dismisses the screensaver (default: false)
+	ShowIcons             *bool  `toml:"show_icons"`                  // Show the icon of each window's program in the dock and sidebar; needs a Nerd Font (default: true)
+	RememberGeometry      bool   `toml:"remember_geometry"`           // Reopen windows started with a command where that command's last floating window was (default: false)
+	SeeThroughFloats      bool   `toml:"see_through_floats"`          // Show the windows below a floating window, dimmed, where it shows nothing (default: false)
+	Theme                 string `toml:"theme"`                       // Color theme, e.g. dracula, nord (default: standard terminal colors). Overridden by --theme.
 }
 
 // KeybindingsConfig holds all keybinding configurations
 type KeybindingsConfig struct {
 	LeaderKey        string              `toml:"leader_key"` // Leader key for prefix commands (default: ctrl+b)
+	Preset           string              `toml:"preset"`     // Keybinding preset applied under your own bindings, e.g. "tmux" (default: none)
 	WindowManagement map[string][]string `toml:"window_management"`
 	Workspaces       map[string][]string `toml:"workspaces"`
 	Layout           map[string][]string `toml:"layout"`
@@ -61,11 +121,12 @@ type KeybindingsConfig struct {
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *UserConfig {
+	scrollbackLines := 10000
 	cfg := &UserConfig{
 		Appearance: AppearanceConfig{
 			BorderStyle:       "rounded",
 			HideWindowButtons: false,
-			ScrollbackLines:   10000,
+			ScrollbackLines:   &scrollbackLines,
 			DockbarPosition:   "bottom",
 			PreferredShell:    "",
 		},
@@ -73,26 +134,28 @@ func DefaultConfig() *UserConfig {
 			LogLevel:     "off",
 			DefaultCodec: "gob",
 			SocketPath:   "", // Empty means use default XDG path
+			WindowSize:   "smallest",
 		},
 		Keybindings: KeybindingsConfig{
 			LeaderKey: "ctrl+b",
 			WindowManagement: map[string][]string{
-				"new_window":      {"n"},
-				"close_window":    {"w", "x"},
-				"rename_window":   {"r"},
-				"minimize_window": {"m"},
-				"restore_all":     {"M"},
-				"next_window":     {"tab"},
-				"prev_window":     {"shift+tab"},
-				"select_window_1": {"1"},
-				"select_window_2": {"2"},
-				"select_window_3": {"3"},
-				"select_window_4": {"4"},
-				"select_window_5": {"5"},
-				"select_window_6": {"6"},
-				"select_window_7": {"7"},
-				"select_window_8": {"8"},
-				"select_window_9": {"9"},
+				"new_window":        {"n"},
+				"close_window":      {"w", "x"},
+				"rename_window":     {"r"},
+				"minimize_window":   {"m"},
+				"restore_all":       {"M"},
+				"close_all_windows": {"X"},
+				"next_window":       {"tab"},
+				"prev_window":       {"shift+tab"},
+				"select_window_1":   {"1"},
+				"select_window_2":   {"2"},
+				"select_window_3":   {"3"},
+				"select_window_4":   {"4"},
+				"select_window_5":   {"5"},
+				"select_window_6":   {"6"},
+				"select_window_7":   {"7"},
+				"select_window_8":   {"8"},
+				"select_window_9":   {"9"},
 			},
 			Workspaces: getDefaultWorkspaceKeybinds(),
 			Layout:     getDefaultLayoutKeybinds(),
@@ -144,6 +207,7 @@ func DefaultConfig() *UserConfig {
 				"prefix_select_8":         {"8"},
 				"prefix_select_9":         {"9"},
 				"prefix_toggle_tiling":    {"space"},
+				"prefix_toggle_floating":  {"f"},
 				"prefix_workspace":        {"w"},
 				"prefix_minimize":         {"m"},
 				"prefix_window":           {"t"},
@@ -154,20 +218,68 @@ func DefaultConfig() *UserConfig {
 				"prefix_tape":             {"T"},
 				"prefix_quit":             {"q"},
 				"prefix_fullscreen":       {"z"},
+				"prefix_pip":              {"P"},
+				"prefix_mouse_capture":    {"M"},
+				"prefix_window_info":      {"i"},
+				"prefix_replay":           {"h"},
+				"prefix_window_hints":     {"j"},
+				"prefix_image_hints":      {"I"},
+				"prefix_key_passthrough":  {"N"},
+				"prefix_lock_input":       {"L"},
+				"prefix_pin_min_size":     {"S"},
+				"prefix_run_command":      {":"},
+				"prefix_copy_last_output": {"y"},
+				"prefix_open_last_output": {"Y"},
+				"prefix_command_history":  {"H"},
+				"prefix_set_mark":         {"`"},
+				"prefix_jump_mark":        {"'"},
+				"prefix_last_window":      {"l"},
+				"prefix_urgent_window":    {"u"},
+				"prefix_tag_window":       {"#"},
+				"prefix_zoom_window":      {"Z"},
+				"prefix_find_on_screen":   {"/"},
+				"prefix_move_window":      {"v"},
+				"prefix_gather_windows":   {"g"},
 				"prefix_split_horizontal": {"-"},
 				"prefix_split_vertical":   {"|", "\\"},
 				"prefix_rotate_split":     {"R"},
 				"prefix_equalize_splits":  {"="},
+				"prefix_grow_tile":        {"+"},
+				"prefix_shrink_tile":      {"_"},
+				"prefix_promote_master":   {"enter"},
 				"prefix_sidebar":          {"b"},
+
+				"prefix_split_pane_vertical":   {"%"},
+				"prefix_split_pane_horizontal": {`"`},
+				"prefix_close_pane":            {"X"},
+				"prefix_next_pane":             {"o"},
+				"prefix_pane_left":             {"left"},
+				"prefix_pane_right":            {"right"},
+				"prefix_pane_up":               {"up"},
+				"prefix_pane_down":             {"down"},
+				"prefix_resize_pane_left":      {"ctrl+left"},
+				"prefix_resize_pane_right":     {"ctrl+right"},
+				"prefix_resize_pane_up":        {"ctrl+up"},
+				"prefix_resize_pane_down":      {"ctrl+down"},
+				"prefix_break_pane":            {"!"},
+				"prefix_join_pane":             {"J"},
+
+				"prefix_new_tab":  {"C"},
+				"prefix_next_tab": {">", "shift+."},
+				"prefix_prev_tab": {"<", "shift+,"},
+
+				"prefix_group_window":   {"G"},
+				"prefix_ungroup_window": {"U"},
 			},
 			WindowPrefix: map[string][]string{
-				"window_prefix_new":    {"n"},
-				"window_prefix_close":  {"x"},
-				"window_prefix_rename": {"r"},
-				"window_prefix_next":   {"tab"},
-				"window_prefix_prev":   {"shift+tab"},
-				"window_prefix_tiling": {"t"},
-				"window_prefix_cancel": {"esc"},
+				"window_prefix_new":       {"n"},
+				"window_prefix_close":     {"x"},
+				"window_prefix_rename":    {"r"},
+				"window_prefix_next":      {"tab"},
+				"window_prefix_prev":      {"shift+tab"},
+				"window_prefix_tiling":    {"t"},
+				"window_prefix_close_all": {"X"},
+				"window_prefix_cancel":    {"esc"},
 			},
 			MinimizePrefix: map[string][]string{
 				"minimize_prefix_focused":     {"m"},
@@ -181,6 +293,8 @@ func DefaultConfig() *UserConfig {
 				"minimize_prefix_restore_8":   {"8"},
 				"minimize_prefix_restore_9":   {"9"},
 				"minimize_prefix_restore_all": {"M"},
+				"minimize_prefix_menu":        {"l"},
+				"minimize_prefix_all":         {"a"},
 				"minimize_prefix_cancel":      {"esc"},
 			},
 			WorkspacePrefix: map[string][]string{
@@ -202,11 +316,17 @@ func DefaultConfig() *UserConfig {
 				"workspace_prefix_move_7":   {"&"},
 				"workspace_prefix_move_8":   {"*"},
 				"workspace_prefix_move_9":   {"("},
+				"workspace_prefix_split":    {"s"},
+				"workspace_prefix_output":   {"o", "tab"},
+				"workspace_prefix_move_out": {"m"},
+				"workspace_prefix_previous": {"p"},
 				"workspace_prefix_cancel":   {"esc"},
 			},
 			DebugPrefix: map[string][]string{
 				"debug_prefix_logs":       {"l"},
 				"debug_prefix_cache":      {"c"},
+				"debug_prefix_scrollback": {"m"},
+				"debug_prefix_console":    {"w"},
 				"debug_prefix_animations": {"a"},
 				"debug_prefix_cancel":     {"esc"},
 			},
@@ -227,15 +347,19 @@ func DefaultConfig() *UserConfig {
 func getDefaultTerminalModeKeybinds() map[string][]string {
 	if isMacOS() {
 		return map[string][]string{
-			"terminal_next_window": {"opt+tab"},
-			"terminal_prev_window": {"opt+shift+tab"},
-			"terminal_exit_mode":   {"opt+esc"},
+			"terminal_next_window":      {"opt+tab"},
+			"terminal_prev_window":      {"opt+shift+tab"},
+			"terminal_exit_mode":        {"opt+esc"},
+			"terminal_exit_passthrough": {"f12"},
+			"terminal_unlock_input":     {"ctrl+alt+u"},
 		}
 	}
 	return map[string][]string{
-		"terminal_next_window": {"alt+n"},
-		"terminal_prev_window": {"alt+p"},
-		"terminal_exit_mode":   {"alt+esc"},
+		"terminal_next_window":      {"alt+n"},
+		"terminal_prev_window":      {"alt+p"},
+		"terminal_exit_mode":        {"alt+esc"},
+		"terminal_exit_passthrough": {"f12"},
+		"terminal_unlock_input":     {"ctrl+alt+u"},
 	}
 }
 
@@ -258,6 +382,7 @@ func getDefaultWorkspaceKeybinds() map[string][]string {
 			"switch_workspace_7": {"opt+7"},
 			"switch_workspace_8": {"opt+8"},
 			"switch_workspace_9": {"opt+9"},
+			"previous_workspace": {"opt+0"},
 			"move_and_follow_1":  {"opt+shift+1"},
 			"move_and_follow_2":  {"opt+shift+2"},
 			"move_and_follow_3":  {"opt+shift+3"},
@@ -280,6 +405,7 @@ func getDefaultWorkspaceKeybinds() map[string][]string {
 			"switch_workspace_7": {"alt+7"},
 			"switch_workspace_8": {"alt+8"},
 			"switch_workspace_9": {"alt+9"},
+			"previous_workspace": {"alt+0"},
 			"move_and_follow_1":  {"alt+shift+1"},
 			"move_and_follow_2":  {"alt+shift+2"},
 			"move_and_follow_3":  {"alt+shift+3"},
@@ -307,7 +433,17 @@ func getDefaultLayoutKeybinds() map[string][]string {
 		"snap_corner_2":             {"2"},
 		"snap_corner_3":             {"3"},
 		"snap_corner_4":             {"4"},
+		"snap_top":                  {"k"},
+		"snap_bottom":               {"j"},
+		"snap_third_left":           {"a"},
+		"snap_third_center":         {"s"},
+		"snap_third_right":          {"d"},
+		"snap_two_thirds_left":      {"A"},
+		"snap_two_thirds_right":     {"D"},
+		"center_window":             {"c"},
+		"cycle_geometry_preset":     {"g"},
 		"toggle_tiling":             {"t"},
+		"toggle_floating":           {"F"},
 		"swap_left":                 {"H", "ctrl+left"},
 		"swap_right":                {"L", "ctrl+right"},
 		"swap_up":                   {"K", "ctrl+up"},
@@ -321,10 +457,14 @@ func getDefaultLayoutKeybinds() map[string][]string {
 		"resize_height_shrink_top":  {"["},
 		"resize_height_grow_top":    {"]"},
 		// BSP tiling
-		"split_horizontal": {"-"},
-		"split_vertical":   {"|", "\\"},
-		"rotate_split":     {"R"},
-		"equalize_splits":  {"="},
+		"split_horizontal":    {"-"},
+		"split_vertical":      {"|", "\\"},
+		"rotate_split":        {"R"},
+		"equalize_splits":     {"="},
+		"grow_tile":           {"+", "shift+="},
+		"shrink_tile":         {"_", "shift+-"},
+		"promote_master":      {"P"},
+		"cycle_tiling_insert": {"I"},
 	}
 
 	// Add platform-specific BSP preselect bindings
@@ -360,7 +500,13 @@ func LoadUserConfig() (*UserConfig, error) {
 	configPath, err := xdg.SearchConfigFile("tuios/config.toml")
 	if err != nil {
 		// Config doesn't exist, create default
-		return createDefaultConfig()
+		cfg, err := createDefaultConfig()
+		if err != nil || GetProfile() == "" {
+			return cfg, err
+		}
+		if configPath, err = GetConfigPath(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Read and parse config file
@@ -369,20 +515,14 @@ func LoadUserConfig() (*UserConfig, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
-
-	var cfg UserConfig
-	if err := toml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	if data, err = applyProfile(data); err != nil {
+		return nil, err
 	}
 
-	// Validate and fill in missing sections with defaults
-	defaultCfg := DefaultConfig()
-	fillMissingAppearance(&cfg, defaultCfg)
-	fillMissingDaemon(&cfg, defaultCfg)
-	fillMissingKeybinds(&cfg, defaultCfg)
-
-	// Validate configuration
-	validation := ValidateConfig(&cfg)
+	cfg, validation, err := ParseUserConfig(data)
+	if err != nil {
+		return nil, err
+	}
 	if validation.HasErrors() {
 		// Log all errors
 		for _, err := range validation.Errors {
@@ -398,7 +538,62 @@ func LoadUserConfig() (*UserConfig, error) {
 		}
 	}
 
-	return &cfg, nil
+	return cfg, nil
+}
+
+// ParseUserConfig parses config file contents, fills in missing settings
+// with defaults and validates the result. Parse errors include the line of
+// the offending value.
+func ParseUserConfig(data []byte) (*UserConfig, *ValidationResult, error) {
+	var cfg UserConfig
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, col := decodeErr.Position()
+			return nil, nil, fmt.Errorf("failed to parse config file: line %d, column %d: %w", row, col, err)
+		}
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	// Validate and fill in missing sections with defaults
+	defaultCfg := DefaultConfig()
+	fillMissingAppearance(&cfg, defaultCfg)
+	fillMissingDaemon(&cfg, defaultCfg)
+	fillMissingKeybinds(&cfg, defaultCfg)
+
+	// The globals are only touched once the whole file is valid, so a bad
+	// live reload leaves the running settings as they were
+	validation := ValidateConfig(&cfg)
+	if !validation.HasErrors() {
+		applyAppearance(&cfg)
+		applyDaemon(&cfg)
+	}
+	return &cfg, validation, nil
+}
+
+// ReloadUserConfig reads the config file at path, merged with the selected
+// profile, for a live reload. Unlike
+// LoadUserConfig it never creates the file or prints, and the first
+// validation error is returned as an error.
+func ReloadUserConfig(path string) (*UserConfig, error) {
+	// #nosec G304 - path is the user's config file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if data, err = applyProfile(data); err != nil {
+		return nil, err
+	}
+
+	cfg, validation, err := ParseUserConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if validation.HasErrors() {
+		first := validation.Errors[0]
+		return nil, fmt.Errorf("[%s] %s: %s", first.Field, first.Key, first.Message)
+	}
+	return cfg, nil
 }
 
 // createDefaultConfig creates a default config file in the user's config directory
@@ -448,7 +643,7 @@ func createDefaultConfig() (*UserConfig, error) {
 	sb.WriteString("#   Default: false\n")
 	sb.WriteString("#\n")
 	sb.WriteString("# scrollback_lines: Number of lines to keep in scrollback buffer\n")
-	sb.WriteString("#   Range: 100 to 1000000\n")
+	sb.WriteString("#   Range: 100 to 1000000, or 0 for no scrollback\n")
 	sb.WriteString("#   Default: 10000\n")
 	sb.WriteString("# ============================================================================\n\n")
 
@@ -477,15 +672,19 @@ func fillMissingAppearance(cfg, defaultCfg *UserConfig) {
 	// Note: HideWindowButtons defaults to false (zero value)
 	// In borderless mode, buttons are hidden automatically regardless of this setting
 
-	// Validate and set scrollback lines (min: 100, max: 1000000)
-	if cfg.Appearance.ScrollbackLines <= 0 {
+	// Validate and set scrollback lines (0 disables scrollback, others: min
+	// 100, max 1000000)
+	if lines := cfg.Appearance.ScrollbackLines; lines == nil || *lines < 0 {
 		cfg.Appearance.ScrollbackLines = defaultCfg.Appearance.ScrollbackLines
-	} else if cfg.Appearance.ScrollbackLines < 100 {
-		cfg.Appearance.ScrollbackLines = 100
-	} else if cfg.Appearance.ScrollbackLines > 1000000 {
-		cfg.Appearance.ScrollbackLines = 1000000
+	} else if *lines > 0 && *lines < 100 {
+		*lines = 100
+	} else if *lines > 1000000 {
+		*lines = 1000000
 	}
+}
 
+// applyAppearance sets the appearance globals from a filled and validated config
+func applyAppearance(cfg *UserConfig) {
 	// AnimationsEnabled defaults to true (nil means use default)
 	// Only set global if explicitly configured
 	if cfg.Appearance.AnimationsEnabled != nil {
@@ -503,16 +702,156 @@ func fillMissingAppearance(cfg, defaultCfg *UserConfig) {
 	}
 
 	// WindowTitlePosition defaults to bottom
-	// Only apply from config if not already set via flag (run.go sets this before fillMissingAppearance is called)
+	// Only apply from config if not already set via flag (run.go sets this before applyAppearance is called)
 	if cfg.Appearance.WindowTitlePosition != "" && WindowTitlePosition == "bottom" {
 		WindowTitlePosition = cfg.Appearance.WindowTitlePosition
 	}
 
+	// TitleFormat defaults to {name}
+	if cfg.Appearance.TitleFormat != "" {
+		TitleFormat = cfg.Appearance.TitleFormat
+	}
+
+	// TitleAlign defaults to the title position's own alignment
+	if slices.Contains(TitleAligns, cfg.Appearance.TitleAlign) {
+		TitleAlign = cfg.Appearance.TitleAlign
+	}
+
 	// HideClock defaults to false
-	// Only apply from config if not already set via flag (run.go sets this before fillMissingAppearance is called)
+	// Only apply from config if not already set via flag (run.go sets this before applyAppearance is called)
 	if !HideClock {
 		HideClock = cfg.Appearance.HideClock
 	}
+
+	// ConfirmMultilinePaste defaults to true (nil means use default)
+	if cfg.Appearance.ConfirmMultilinePaste != nil {
+		ConfirmMultilinePaste = *cfg.Appearance.ConfirmMultilinePaste
+	}
+
+	// ConfirmQuit defaults to running
+	if slices.Contains(ConfirmQuitModes, cfg.Appearance.ConfirmQuit) {
+		ConfirmQuit = cfg.Appearance.ConfirmQuit
+	}
+
+	// ClipboardWrite defaults to allow
+	if slices.Contains(ClipboardWriteModes, cfg.Appearance.ClipboardWrite) {
+		ClipboardWrite = cfg.Appearance.ClipboardWrite
+	}
+
+	// ClipboardWriteMaxBytes defaults to 1 MiB (nil means use default, 0
+	// removes the limit)
+	if cfg.Appearance.ClipboardWriteMaxKB != nil {
+		ClipboardWriteMaxBytes = max(*cfg.Appearance.ClipboardWriteMaxKB, 0) * 1024
+	}
+
+	// PasteChunkSize defaults to 4096 (min: 256)
+	if cfg.Appearance.PasteChunkSize > 0 {
+		PasteChunkSize = max(cfg.Appearance.PasteChunkSize, 256)
+	}
+
+	// MouseOverrideModifier defaults to shift
+	if cfg.Appearance.MouseOverrideModifier != "" {
+		MouseOverrideModifier = cfg.Appearance.MouseOverrideModifier
+	}
+
+	// AmbiguousWidth defaults to 1 and EmojiWidth to 2 (only 1 or 2 allowed)
+	if cfg.Appearance.AmbiguousWidth == 1 || cfg.Appearance.AmbiguousWidth == 2 {
+		AmbiguousWidth = cfg.Appearance.AmbiguousWidth
+	}
+	if cfg.Appearance.EmojiWidth == 1 || cfg.Appearance.EmojiWidth == 2 {
+		EmojiWidth = cfg.Appearance.EmojiWidth
+	}
+
+	// Scrollback memory limits default to 512 MiB total and 128 MiB per window
+	// (nil means use default, 0 disables the limit)
+	if cfg.Appearance.ScrollbackBudgetMB != nil {
+		ScrollbackMemoryBudget = max(*cfg.Appearance.ScrollbackBudgetMB, 0) * 1024 * 1024
+	}
+	if cfg.Appearance.ScrollbackWindowMB != nil {
+		ScrollbackWindowMemoryLimit = max(*cfg.Appearance.ScrollbackWindowMB, 0) * 1024 * 1024
+	}
+
+	// AutosaveInterval defaults to 30 seconds (nil means use default, 0 disables)
+	if cfg.Appearance.AutosaveInterval != nil {
+		AutosaveInterval = time.Duration(max(*cfg.Appearance.AutosaveInterval, 0)) * time.Second
+	}
+
+	// ReplayBufferBytes defaults to 1 MiB per window (nil means use default,
+	// 0 disables recording)
+	if cfg.Appearance.ReplayBufferKB != nil {
+		ReplayBufferBytes = min(max(*cfg.Appearance.ReplayBufferKB, 0), 65536) * 1024
+	}
+
+	// ShutdownGracePeriod defaults to 1 second (nil means use default, 0 kills
+	// right away)
+	if cfg.Appearance.ShutdownGraceMS != nil {
+		ShutdownGracePeriod = time.Duration(min(max(*cfg.Appearance.ShutdownGraceMS, 0), 10000)) * time.Millisecond
+	}
+
+	// TilingInsert defaults to end
+	if slices.Contains(TilingInsertPositions, cfg.Appearance.TilingInsert) {
+		TilingInsert = cfg.Appearance.TilingInsert
+	}
+
+	// WindowPlacement defaults to cursor
+	if slices.Contains(WindowPlacements, cfg.Appearance.WindowPlacement) {
+		WindowPlacement = cfg.Appearance.WindowPlacement
+	}
+
+	// MoveStep defaults to 1 and MoveStepLarge to 5
+	if cfg.Appearance.MoveStep > 0 {
+		MoveStep = cfg.Appearance.MoveStep
+	}
+	if cfg.Appearance.MoveStepLarge > 0 {
+		MoveStepLarge = cfg.Appearance.MoveStepLarge
+	}
+
+	// MinVisibleColumns defaults to 20 (nil means use default, 0 disables)
+	if cfg.Appearance.MinVisibleColumns != nil {
+		MinVisibleColumns = max(*cfg.Appearance.MinVisibleColumns, 0)
+	}
+
+	// EdgeResistance defaults to 4 cells (nil means use default, 0 disables)
+	if cfg.Appearance.EdgeResistance != nil {
+		EdgeResistance = min(max(*cfg.Appearance.EdgeResistance, 0), 50)
+	}
+
+	// ResizeBehavior defaults to proportional
+	if slices.Contains(ResizeBehaviors, cfg.Appearance.ResizeBehavior) {
+		ResizeBehavior = cfg.Appearance.ResizeBehavior
+	}
+
+	// The compact layout is disabled by default
+	CompactWidth = max(cfg.Appearance.CompactWidth, 0)
+	CompactHeight = max(cfg.Appearance.CompactHeight, 0)
+
+	// VirtualOutputs defaults to 2 (only 2 to 4 allowed)
+	if cfg.Appearance.VirtualOutputs >= 2 && cfg.Appearance.VirtualOutputs <= 4 {
+		VirtualOutputs = cfg.Appearance.VirtualOutputs
+	}
+	WorkspaceBackAndForth = cfg.Appearance.WorkspaceBackAndForth
+
+	// The screensaver is disabled by default and shows the clock
+	ScreensaverIdle = time.Duration(min(max(cfg.Appearance.ScreensaverMinutes, 0), 1440)) * time.Minute
+	if slices.Contains(Screensavers, cfg.Appearance.Screensaver) {
+		Screensaver = cfg.Appearance.Screensaver
+	}
+	ScreensaverLock = cfg.Appearance.ScreensaverLock
+
+	// ShowAppIcons defaults to true (nil means use default)
+	if cfg.Appearance.ShowIcons != nil {
+		ShowAppIcons = *cfg.Appearance.ShowIcons
+	}
+	RememberGeometry = cfg.Appearance.RememberGeometry
+	CopyOnSelect = cfg.Appearance.CopyOnSelect
+	MiddleClickPaste = cfg.Appearance.MiddleClickPaste
+	DockWrap = cfg.Appearance.DockWrap
+	SeeThroughFloats = cfg.Appearance.SeeThroughFloats
+
+	// DockStatusSide defaults to right
+	if slices.Contains(DockStatusSides, cfg.Appearance.DockStatusSide) {
+		DockStatusSide = cfg.Appearance.DockStatusSide
+	}
 }
 
 // fillMissingDaemon fills in any missing daemon settings with defaults
@@ -523,9 +862,18 @@ func fillMissingDaemon(cfg, defaultCfg *UserConfig) {
 	if cfg.Daemon.DefaultCodec == "" {
 		cfg.Daemon.DefaultCodec = defaultCfg.Daemon.DefaultCodec
 	}
+	if cfg.Daemon.WindowSize == "" {
+		cfg.Daemon.WindowSize = defaultCfg.Daemon.WindowSize
+	}
 	// SocketPath defaults to empty (use XDG default), so we don't override it
 }
 
+// applyDaemon sets the daemon globals from a filled and validated config
+func applyDaemon(cfg *UserConfig) {
+	IndependentFocus = cfg.Daemon.IndependentFocus
+	SwallowWindows = cfg.Daemon.SwallowWindows
+}
+
 // fillMissingKeybinds fills in any missing keybindings with defaults
 func fillMissingKeybinds(cfg, defaultCfg *UserConfig) {
 	// Initialize nil maps
@@ -577,6 +925,9 @@ func fillMissingKeybinds(cfg, defaultCfg *UserConfig) {
 		cfg.Keybindings.LeaderKey = defaultCfg.Keybindings.LeaderKey
 	}
 
+	// Preset bindings replace defaults before the remaining defaults are filled
+	applyKeybindPreset(cfg, defaultCfg)
+
 	// Fill in missing keys with defaults
 	fillMapDefaults(cfg.Keybindings.WindowManagement, defaultCfg.Keybindings.WindowManagement)
 	fillMapDefaults(cfg.Keybindings.Workspaces, defaultCfg.Keybindings.Workspaces)
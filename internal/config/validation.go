@@ -2,7 +2,11 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+
+	"github.com/Gaurav-Gosain/tuios/internal/theme"
 )
 
 // ValidationError represents a validation error or warning
@@ -88,6 +92,9 @@ func ValidateConfig(cfg *UserConfig) *ValidationResult {
 	validateSection("minimize_prefix", cfg.Keybindings.MinimizePrefix)
 	validateSection("workspace_prefix", cfg.Keybindings.WorkspacePrefix)
 
+	// Check option values that would otherwise silently fall back to defaults
+	result.Warnings = append(result.Warnings, validateValues(cfg)...)
+
 	// Check for keybinding conflicts (same key bound to multiple actions)
 	conflicts := findConflicts(cfg, normalizer)
 	for key, actions := range conflicts {
@@ -152,6 +159,154 @@ func ValidateConfig(cfg *UserConfig) *ValidationResult {
 	return result
 }
 
+// validateValues checks enumerated option values. Invalid values are
+// warnings because TUIOS ignores them and uses the default.
+func validateValues(cfg *UserConfig) []ValidationError {
+	var issues []ValidationError
+	check := func(field, key, value string, allowed ...string) {
+		if value == "" || slices.Contains(allowed, value) {
+			return
+		}
+		issues = append(issues, ValidationError{
+			Field:   field,
+			Key:     key,
+			Message: fmt.Sprintf("Invalid value '%s' (use: %s), using the default", value, strings.Join(allowed, ", ")),
+		})
+	}
+
+	check("appearance", "border_style", cfg.Appearance.BorderStyle,
+		"rounded", "normal", "thick", "double", "hidden", "block", "ascii", "outer-half-block", "inner-half-block")
+	check("appearance", "dockbar_position", cfg.Appearance.DockbarPosition, "bottom", "top", "hidden")
+	check("appearance", "dock_status_side", cfg.Appearance.DockStatusSide, DockStatusSides...)
+	check("appearance", "window_title_position", cfg.Appearance.WindowTitlePosition, "bottom", "top", "hidden")
+	check("appearance", "title_align", cfg.Appearance.TitleAlign, TitleAligns...)
+	for _, field := range TitleFormatFields(cfg.Appearance.TitleFormat) {
+		if !slices.Contains(TitleFields, field) {
+			issues = append(issues, ValidationError{
+				Field:   "appearance",
+				Key:     "title_format",
+				Message: fmt.Sprintf("Unknown field {%s} (use: %s), shown as is", field, strings.Join(TitleFields, ", ")),
+			})
+		}
+	}
+	check("appearance", "whichkey_position", cfg.Appearance.WhichKeyPosition,
+		"bottom-right", "bottom-left", "top-right", "top-left", "center")
+	check("appearance", "mouse_override_modifier", cfg.Appearance.MouseOverrideModifier, "shift", "alt", "ctrl", "none")
+	check("appearance", "tiling_insert", cfg.Appearance.TilingInsert, TilingInsertPositions...)
+	check("appearance", "confirm_quit", cfg.Appearance.ConfirmQuit, ConfirmQuitModes...)
+	check("appearance", "clipboard_write", cfg.Appearance.ClipboardWrite, ClipboardWriteModes...)
+	check("appearance", "window_placement", cfg.Appearance.WindowPlacement, WindowPlacements...)
+	check("appearance", "resize_behavior", cfg.Appearance.ResizeBehavior, ResizeBehaviors...)
+	check("appearance", "screensaver", cfg.Appearance.Screensaver, Screensavers...)
+	optionalInt := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+	check("appearance", "ambiguous_width", optionalInt(cfg.Appearance.AmbiguousWidth), "1", "2")
+	check("appearance", "emoji_width", optionalInt(cfg.Appearance.EmojiWidth), "1", "2")
+	check("appearance", "virtual_outputs", optionalInt(cfg.Appearance.VirtualOutputs), "2", "3", "4")
+	check("keybindings", "preset", cfg.Keybindings.Preset, PresetNames()...)
+	check("daemon", "default_codec", cfg.Daemon.DefaultCodec, "gob", "json")
+	check("daemon", "window_size", cfg.Daemon.WindowSize, "smallest", "latest")
+
+	for i, rule := range cfg.WindowRules {
+		if rule.Match == "" {
+			issues = append(issues, ValidationError{
+				Field:   "window_rules",
+				Key:     "match",
+				Message: fmt.Sprintf("Rule %d has no match pattern and applies to no window", i+1),
+			})
+		}
+		if rule.ScrollbackLines != nil && (*rule.ScrollbackLines < 0 || *rule.ScrollbackLines > 1000000) {
+			issues = append(issues, ValidationError{
+				Field:   "window_rules",
+				Key:     "scrollback_lines",
+				Message: fmt.Sprintf("Rule %d: invalid value %d (use: 0 to 1000000), ignoring it", i+1, *rule.ScrollbackLines),
+			})
+		}
+		if rule.ClipboardWrite != "" && !slices.Contains(ClipboardWriteModes, rule.ClipboardWrite) {
+			issues = append(issues, ValidationError{
+				Field:   "window_rules",
+				Key:     "clipboard_write",
+				Message: fmt.Sprintf("Rule %d: invalid value '%s' (use: %s), ignoring it", i+1, rule.ClipboardWrite, strings.Join(ClipboardWriteModes, ", ")),
+			})
+		}
+		for _, size := range []struct {
+			key   string
+			value *int
+		}{{"min_width", rule.MinWidth}, {"min_height", rule.MinHeight}} {
+			if size.value != nil && !validMinSize(size.value) {
+				issues = append(issues, ValidationError{
+					Field:   "window_rules",
+					Key:     size.key,
+					Message: fmt.Sprintf("Rule %d: invalid value %d (use: 0 to %d), ignoring it", i+1, *size.value, MaxRuleMinSize),
+				})
+			}
+		}
+	}
+
+	for i, preset := range cfg.GeometryPresets {
+		if !preset.Valid() {
+			issues = append(issues, ValidationError{
+				Field:   "geometry_presets",
+				Key:     preset.Name,
+				Message: fmt.Sprintf("Preset %d does not fit the screen (x + width and y + height must be at most 100), ignoring it", i+1),
+			})
+		}
+	}
+
+	for i, rule := range cfg.NamingRules {
+		if !rule.Valid() {
+			issues = append(issues, ValidationError{
+				Field:   "naming_rules",
+				Key:     rule.Command,
+				Message: fmt.Sprintf("Naming rule %d needs a valid regular expression command and a name, ignoring it", i+1),
+			})
+		}
+	}
+
+	for i, trigger := range cfg.Triggers {
+		if !trigger.Valid() {
+			issues = append(issues, ValidationError{
+				Field:   "triggers",
+				Key:     trigger.Pattern,
+				Message: fmt.Sprintf("Trigger %d needs a valid regular expression pattern, an action (use: %s) and a command for hooks, ignoring it", i+1, strings.Join(TriggerActions, ", ")),
+			})
+		}
+	}
+
+	for i, rule := range cfg.Highlights {
+		if !rule.Valid() {
+			issues = append(issues, ValidationError{
+				Field:   "highlights",
+				Key:     rule.Pattern,
+				Message: fmt.Sprintf("Highlight %d needs a valid regular expression pattern that cannot match empty text, and a color, background or bold (colors: a name such as red, 0 to 255 or #rrggbb), ignoring it", i+1),
+			})
+		}
+	}
+
+	for i, tmpl := range cfg.WorkspaceTemplates {
+		if !tmpl.Valid() {
+			issues = append(issues, ValidationError{
+				Field:   "workspace_templates",
+				Key:     tmpl.Layout,
+				Message: fmt.Sprintf("Workspace template %d needs a layout and a workspace number from 1 to 9 or a workspace name, ignoring it", i+1),
+			})
+		}
+	}
+
+	if cfg.Appearance.Theme != "" && !theme.Exists(cfg.Appearance.Theme) {
+		issues = append(issues, ValidationError{
+			Field:   "appearance",
+			Key:     "theme",
+			Message: fmt.Sprintf("Unknown theme '%s' (see tuios --list-themes), using the default theme", cfg.Appearance.Theme),
+		})
+	}
+	return issues
+}
+
 // findConflicts finds keys that are bound to multiple actions within the same context
 func findConflicts(cfg *UserConfig, normalizer *KeyNormalizer) map[string][]string {
 	// Define action groups by context - actions in different contexts can share keys
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WindowRule applies settings to the windows whose name matches a pattern.
+// Rules are checked in order and the first matching rule that sets an
+// option decides it.
+type WindowRule struct {
+	Match           string `toml:"match"`            // Glob matched against the window title and custom name, e.g. "*htop*" (case-insensitive)
+	ScrollbackLines *int   `toml:"scrollback_lines"` // Scrollback lines for matching windows, 0 disables scrollback (default: appearance.scrollback_lines)
+	MinWidth        *int   `toml:"min_width"`        // Width tiling and resizing never shrink matching windows below
+	MinHeight       *int   `toml:"min_height"`       // Height tiling and resizing never shrink matching windows below
+	ClipboardWrite  string `toml:"clipboard_write"`  // OSC 52 clipboard write policy for matching windows: allow, confirm, deny (default: appearance.clipboard_write)
+}
+
+// Matches reports whether any of the given window names matches the rule.
+func (r WindowRule) Matches(names ...string) bool {
+	for _, name := range names {
+		if name != "" && globMatch(r.Match, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleScrollbackLines returns the scrollback limit set by the first window
+// rule that matches one of the names and sets a valid scrollback_lines.
+func RuleScrollbackLines(names ...string) (int, bool) {
+	for _, rule := range WindowRules {
+		if rule.ScrollbackLines == nil || *rule.ScrollbackLines < 0 || *rule.ScrollbackLines > 1000000 {
+			continue
+		}
+		if rule.Matches(names...) {
+			return *rule.ScrollbackLines, true
+		}
+	}
+	return 0, false
+}
+
+// RuleClipboardWrite returns the clipboard write policy set by the first
+// window rule that matches one of the names and sets a valid
+// clipboard_write.
+func RuleClipboardWrite(names ...string) (string, bool) {
+	for _, rule := range WindowRules {
+		if !slices.Contains(ClipboardWriteModes, rule.ClipboardWrite) {
+			continue
+		}
+		if rule.Matches(names...) {
+			return rule.ClipboardWrite, true
+		}
+	}
+	return "", false
+}
+
+// MaxRuleMinSize is the largest min_width or min_height a window rule may set.
+const MaxRuleMinSize = 1000
+
+// RuleMinSize returns the minimum width and height set by the first window
+// rules that match one of the names and set a valid min_width and
+// min_height respectively, or 0 when none does.
+func RuleMinSize(names ...string) (width, height int) {
+	widthSet, heightSet := false, false
+	for _, rule := range WindowRules {
+		if widthSet && heightSet {
+			break
+		}
+		if (rule.MinWidth == nil || widthSet) && (rule.MinHeight == nil || heightSet) {
+			continue
+		}
+		if !rule.Matches(names...) {
+			continue
+		}
+		if !widthSet && validMinSize(rule.MinWidth) {
+			width, widthSet = *rule.MinWidth, true
+		}
+		if !heightSet && validMinSize(rule.MinHeight) {
+			height, heightSet = *rule.MinHeight, true
+		}
+	}
+	return width, height
+}
+
+// validMinSize reports whether a rule's min_width or min_height is set and
+// within range.
+func validMinSize(size *int) bool {
+	return size != nil && *size >= 0 && *size <= MaxRuleMinSize
+}
+
+// globMatch reports whether s matches pattern, where * matches any run of
+// characters (including /) and ? matches a single character. Matching is
+// case-insensitive.
+func globMatch(pattern, s string) bool {
+	starP, starS := -1, 0
+	p, i := 0, 0
+	for i < len(s) {
+		if p < len(pattern) {
+			switch pr, pw := utf8.DecodeRuneInString(pattern[p:]); {
+			case pr == '*':
+				starP, starS = p, i
+				p += pw
+				continue
+			case pr == '?':
+				_, sw := utf8.DecodeRuneInString(s[i:])
+				p, i = p+pw, i+sw
+				continue
+			default:
+				sr, sw := utf8.DecodeRuneInString(s[i:])
+				if unicode.ToLower(pr) == unicode.ToLower(sr) {
+					p, i = p+pw, i+sw
+					continue
+				}
+			}
+		}
+		if starP < 0 {
+			return false
+		}
+		// Let the last * absorb one more character and retry
+		_, sw := utf8.DecodeRuneInString(s[starS:])
+		starS += sw
+		p, i = starP+1, starS
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,54 @@
+package config
+
+import "path/filepath"
+
+// WorkspaceTemplate fills a workspace with the windows of a layout file the
+// first time the workspace is switched to in a session.
+type WorkspaceTemplate struct {
+	Workspace int    `toml:"workspace"` // Workspace number (1-9), or 0 to match the workspace by name
+	Name      string `toml:"name"`      // Workspace name matched without a number; with one, the name given to the workspace
+	Layout    string `toml:"layout"`    // Layout file whose first [[workspace]] fills it, relative to the config directory
+}
+
+// Valid reports whether the template has a layout and a workspace number
+// from 1 to 9 or a name.
+func (t WorkspaceTemplate) Valid() bool {
+	return t.Layout != "" && (t.Workspace >= 1 && t.Workspace <= 9 || t.Workspace == 0 && t.Name != "")
+}
+
+// LayoutPath returns the path of the template's layout file, with ~
+// expanded and a relative path taken from the config directory.
+func (t WorkspaceTemplate) LayoutPath() string {
+	path := ExpandHome(t.Layout)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(filepath.Dir(configPath), path)
+}
+
+// WorkspaceTemplates are the configured workspace templates.
+// Set via [[workspace_templates]] config
+var WorkspaceTemplates []WorkspaceTemplate
+
+// WorkspaceTemplateFor returns the template of a workspace: the first valid
+// template for its number, else the first for its name.
+func WorkspaceTemplateFor(workspace int, name string) (WorkspaceTemplate, bool) {
+	byName := -1
+	for i, t := range WorkspaceTemplates {
+		switch {
+		case !t.Valid():
+		case t.Workspace == workspace:
+			return t, true
+		case t.Workspace == 0 && name != "" && t.Name == name && byName < 0:
+			byName = i
+		}
+	}
+	if byName < 0 {
+		return WorkspaceTemplate{}, false
+	}
+	return WorkspaceTemplates[byName], true
+}
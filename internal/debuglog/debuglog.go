@@ -0,0 +1,148 @@
+// Package debuglog collects tuios's internal log stream: render decisions,
+// PTY events, graphics passthrough and the app log. Debug messages are
+// written to /tmp/tuios-debug.log when TUIOS_DEBUG_INTERNAL=1, and every
+// message is kept for the in-app debug console while one is open.
+package debuglog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FilePath is the file debug messages are appended to in debug mode.
+const FilePath = "/tmp/tuios-debug.log"
+
+// historySize is how many recent messages are kept for debug consoles.
+const historySize = 2000
+
+// Level is the severity of a message.
+type Level int
+
+// Message levels, from most to least verbose.
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case Debug:
+		return "DEBUG"
+	case Info:
+		return "INFO"
+	case Warn:
+		return "WARN"
+	case Error:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// ParseLevel parses a level name as used by the app log (INFO, WARN, ERROR),
+// defaulting to Debug.
+func ParseLevel(s string) Level {
+	switch strings.ToUpper(s) {
+	case "INFO":
+		return Info
+	case "WARN", "WARNING":
+		return Warn
+	case "ERROR":
+		return Error
+	default:
+		return Debug
+	}
+}
+
+// Entry is one message of the log stream.
+type Entry struct {
+	Seq     uint64 // Increases by one with every message
+	Time    time.Time
+	Level   Level
+	Source  string // Subsystem that logged the message, such as PTY or KITTY
+	Message string
+}
+
+var (
+	mu        sync.Mutex
+	history   []Entry
+	seq       uint64
+	listeners atomic.Int32
+)
+
+// fileEnabled reports whether debug messages go to the debug log file.
+func fileEnabled() bool {
+	return os.Getenv("TUIOS_DEBUG_INTERNAL") == "1"
+}
+
+// Enabled reports whether debug messages are wanted: debug mode is on or a
+// debug console is open. Callers use it to skip building costly messages.
+func Enabled() bool {
+	return listeners.Load() > 0 || fileEnabled()
+}
+
+// Logf logs a message from a subsystem when debug messages are wanted.
+func Logf(level Level, source, format string, args ...any) {
+	if !Enabled() {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+	now := time.Now()
+	if fileEnabled() {
+		if f, err := os.OpenFile(FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+			_, _ = fmt.Fprintf(f, "[%s] %s: %s\n", now.Format("15:04:05.000"), source, message)
+			_ = f.Close()
+		}
+	}
+	if listeners.Load() > 0 {
+		record(Entry{Time: now, Level: level, Source: source, Message: message})
+	}
+}
+
+// Record keeps a message for the debug consoles without writing it to the
+// debug log file. The app log goes through here, so a console opened later
+// still shows it.
+func Record(level Level, source, message string) {
+	record(Entry{Time: time.Now(), Level: level, Source: source, Message: message})
+}
+
+func record(e Entry) {
+	mu.Lock()
+	defer mu.Unlock()
+	seq++
+	e.Seq = seq
+	history = append(history, e)
+	if len(history) > historySize {
+		history = append(history[:0], history[len(history)-historySize:]...)
+	}
+}
+
+// Since returns the kept messages logged after the one numbered seq, oldest
+// first. Since(0) returns every kept message.
+func Since(seq uint64) []Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	for i, e := range history {
+		if e.Seq > seq {
+			return append([]Entry(nil), history[i:]...)
+		}
+	}
+	return nil
+}
+
+// Listen starts keeping debug messages for a debug console. The returned
+// function stops it again.
+func Listen() (stop func()) {
+	listeners.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { listeners.Add(-1) })
+	}
+}
@@ -0,0 +1,47 @@
+package debuglog
+
+import "testing"
+
+func TestLogfOnlyKeepsMessagesWhileListening(t *testing.T) {
+	t.Setenv("TUIOS_DEBUG_INTERNAL", "")
+	start := lastSeq()
+
+	Logf(Debug, "TEST", "dropped %d", 1)
+	if got := Since(start); len(got) != 0 {
+		t.Fatalf("expected nothing kept without a listener, got %v", got)
+	}
+
+	stop := Listen()
+	Logf(Warn, "TEST", "kept %d", 2)
+	Record(Info, "tuios", "app log")
+	stop()
+	stop() // Stopping twice must not drop another listener
+	Logf(Debug, "TEST", "dropped %d", 3)
+
+	got := Since(start)
+	if len(got) != 2 || got[0].Message != "kept 2" || got[0].Level != Warn || got[0].Source != "TEST" ||
+		got[1].Message != "app log" || got[1].Seq != got[0].Seq+1 {
+		t.Fatalf("expected the two messages logged while listening, got %+v", got)
+	}
+	if Enabled() {
+		t.Error("expected debug messages unwanted once the listener stopped")
+	}
+	if rest := Since(got[1].Seq); len(rest) != 0 {
+		t.Errorf("expected nothing after the last message, got %v", rest)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]Level{"INFO": Info, "warn": Warn, "ERROR": Error, "": Debug, "trace": Debug} {
+		if got := ParseLevel(name); got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// lastSeq returns the number of the last kept message.
+func lastSeq() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	return seq
+}
@@ -0,0 +1,80 @@
+package imageview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// kittyChunkSize is the largest base64 payload sent per graphics escape.
+const kittyChunkSize = 4096
+
+// scaleImage samples the src part of img into a w x h image using nearest
+// neighbor scaling. Transparent pixels are composited over black.
+func scaleImage(img image.Image, src image.Rectangle, w, h int) *image.RGBA {
+	src = src.Add(img.Bounds().Min)
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		sy := src.Min.Y + y*src.Dy()/h
+		for x := range w {
+			sx := src.Min.X + x*src.Dx()/w
+			r, g, b, _ := img.At(sx, sy).RGBA()
+			// RGBA() is alpha-premultiplied, so this is already over black
+			out.SetRGBA(x, y, color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0xff})
+		}
+	}
+	return out
+}
+
+// renderBlocks draws the src part of img as cols x rows cells of upper half
+// blocks, two pixels per cell, starting at the 1-based cell (x, y). It works
+// on any terminal with true color.
+func renderBlocks(img image.Image, src image.Rectangle, x, y, cols, rows int) string {
+	pixels := scaleImage(img, src, cols, rows*2)
+
+	var sb strings.Builder
+	for row := range rows {
+		fmt.Fprintf(&sb, "\x1b[%d;%dH", y+row, x)
+		for col := range cols {
+			top := pixels.RGBAAt(col, row*2)
+			bottom := pixels.RGBAAt(col, row*2+1)
+			fmt.Fprintf(&sb, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀",
+				top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+		}
+		sb.WriteString("\x1b[0m")
+	}
+	return sb.String()
+}
+
+// renderKitty draws the src part of img over cols x rows cells at the 1-based
+// cell (x, y) using the Kitty graphics protocol. The image is scaled to the
+// cells' pixel size first so only what is shown is transmitted.
+func renderKitty(img image.Image, src image.Rectangle, x, y, cols, rows, cellW, cellH int) (string, error) {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, scaleImage(img, src, cols*cellW, rows*cellH)); err != nil {
+		return "", fmt.Errorf("failed to encode image: %w", err)
+	}
+	data := base64.StdEncoding.EncodeToString(encoded.Bytes())
+
+	var sb strings.Builder
+	// Remove the previous frame before showing the new one
+	sb.WriteString("\x1b_Ga=d,d=a,q=2\x1b\\")
+	fmt.Fprintf(&sb, "\x1b[%d;%dH", y, x)
+	for i := 0; i < len(data); i += kittyChunkSize {
+		end := min(i+kittyChunkSize, len(data))
+		more := 0
+		if end < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&sb, "\x1b_Ga=T,f=100,c=%d,r=%d,C=1,q=2,m=%d;%s\x1b\\", cols, rows, more, data[i:end])
+		} else {
+			fmt.Fprintf(&sb, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+	return sb.String(), nil
+}
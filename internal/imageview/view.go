@@ -0,0 +1,72 @@
+// Package imageview provides the built-in image viewer shown in TUIOS windows.
+package imageview
+
+import (
+	"image"
+	"math"
+)
+
+const (
+	maxZoom   = 16.0
+	zoomStep  = 1.25
+	panFactor = 0.1 // Fraction of the visible area moved per pan key
+)
+
+// view tracks which part of an image is shown and how it is zoomed.
+type view struct {
+	imgW, imgH float64
+	zoom       float64 // 1 fits the whole image in the viewer
+	cx, cy     float64 // Center of the visible area in image pixels
+}
+
+func newView(img image.Image) *view {
+	b := img.Bounds()
+	v := &view{imgW: float64(b.Dx()), imgH: float64(b.Dy())}
+	v.reset()
+	return v
+}
+
+// reset fits the whole image in the viewer again.
+func (v *view) reset() {
+	v.zoom = 1
+	v.cx, v.cy = v.imgW/2, v.imgH/2
+}
+
+// zoomBy multiplies the zoom level by factor, never zooming out past the
+// fitted image.
+func (v *view) zoomBy(factor float64) {
+	v.zoom = min(max(v.zoom*factor, 1), maxZoom)
+}
+
+// pan moves the visible area by dx, dy steps of panFactor of the visible
+// width and height. src is the area currently shown.
+func (v *view) pan(dx, dy float64, src image.Rectangle) {
+	v.cx += dx * panFactor * float64(src.Dx())
+	v.cy += dy * panFactor * float64(src.Dy())
+}
+
+// layout returns the part of the image shown in a cols x rows area of
+// cellW x cellH pixel cells and the size in cells it is drawn at. The image
+// keeps its aspect ratio, and the center is clamped so panning stops at the
+// image edges.
+func (v *view) layout(cols, rows, cellW, cellH int) (src image.Rectangle, drawCols, drawRows int) {
+	if v.imgW <= 0 || v.imgH <= 0 || cols <= 0 || rows <= 0 {
+		return image.Rectangle{}, 0, 0
+	}
+
+	areaW, areaH := float64(cols*cellW), float64(rows*cellH)
+	scale := min(areaW/v.imgW, areaH/v.imgH) * v.zoom
+
+	visibleW := min(v.imgW, areaW/scale)
+	visibleH := min(v.imgH, areaH/scale)
+	v.cx = min(max(v.cx, visibleW/2), v.imgW-visibleW/2)
+	v.cy = min(max(v.cy, visibleH/2), v.imgH-visibleH/2)
+
+	x0 := int(math.Round(v.cx - visibleW/2))
+	y0 := int(math.Round(v.cy - visibleH/2))
+	src = image.Rect(x0, y0, x0+max(int(math.Round(visibleW)), 1), y0+max(int(math.Round(visibleH)), 1))
+
+	drawCols = min(max(int(math.Round(visibleW*scale/float64(cellW))), 1), cols)
+	drawRows = min(max(int(math.Round(visibleH*scale/float64(cellH))), 1), rows)
+	return src, drawCols, drawRows
+}
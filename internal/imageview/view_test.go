@@ -0,0 +1,38 @@
+package imageview
+
+import (
+	"image"
+	"testing"
+)
+
+func TestViewLayout(t *testing.T) {
+	v := newView(image.NewRGBA(image.Rect(0, 0, 400, 200)))
+
+	// A 2:1 image in a 40x20 area of 10x20 cells fits the width
+	src, cols, rows := v.layout(40, 20, 10, 20)
+	if src != image.Rect(0, 0, 400, 200) || cols != 40 || rows != 10 {
+		t.Fatalf("fitted layout = %v, %dx%d, want the whole image at 40x10", src, cols, rows)
+	}
+
+	// Zooming in shows the middle of the image, using the whole area
+	v.zoomBy(2)
+	src, cols, rows = v.layout(40, 20, 10, 20)
+	if src != image.Rect(100, 0, 300, 200) || cols != 40 || rows != 20 {
+		t.Errorf("zoomed layout = %v, %dx%d, want the middle half at 40x20", src, cols, rows)
+	}
+
+	// Panning stops at the image edge
+	for range 20 {
+		v.pan(1, 0, src)
+	}
+	src, _, _ = v.layout(40, 20, 10, 20)
+	if src.Max.X != 400 {
+		t.Errorf("panned layout = %v, want it to end at the right edge", src)
+	}
+
+	// Zooming out never goes past the fitted image
+	v.zoomBy(0.1)
+	if src, _, _ = v.layout(40, 20, 10, 20); src != image.Rect(0, 0, 400, 200) {
+		t.Errorf("zoomed out layout = %v, want the whole image", src)
+	}
+}
@@ -0,0 +1,168 @@
+package imageview
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF decoding
+	_ "image/jpeg" // Register JPEG decoding
+	_ "image/png"  // Register PNG decoding
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/term"
+)
+
+// Graphics protocols the viewer can draw with.
+const (
+	ProtocolKitty  = "kitty"
+	ProtocolBlocks = "blocks"
+)
+
+// resizePollInterval is how often the viewer checks for a new terminal size.
+const resizePollInterval = 200 * time.Millisecond
+
+// Options configures the image viewer.
+type Options struct {
+	Protocol   string // ProtocolKitty or ProtocolBlocks
+	CellWidth  int    // Cell size in pixels, used to keep the aspect ratio
+	CellHeight int
+}
+
+// Run shows the image at path in the terminal until the user quits.
+// Arrow keys or h/j/k/l pan, +/- zoom, 0 fits the image again and q or Esc quits.
+func Run(path string, opts Options) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open image: %w", err)
+	}
+	img, format, err := image.Decode(f)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if opts.CellWidth <= 0 || opts.CellHeight <= 0 {
+		opts.CellWidth, opts.CellHeight = 9, 20
+	}
+	if opts.Protocol == "" {
+		opts.Protocol = ProtocolBlocks
+	}
+
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	oldState, err := term.MakeRaw(inFd)
+	if err != nil {
+		return fmt.Errorf("failed to enable raw mode: %w", err)
+	}
+	defer func() { _ = term.Restore(inFd, oldState) }()
+
+	// Alternate screen, hidden cursor
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		if opts.Protocol == ProtocolKitty {
+			fmt.Print("\x1b_Ga=d,d=a,q=2\x1b\\")
+		}
+		fmt.Print("\x1b[0m\x1b[?25h\x1b[?1049l")
+	}()
+
+	keys := make(chan []byte)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	v := newView(img)
+	title := fmt.Sprintf("%s (%s %dx%d)", filepath.Base(path), format, img.Bounds().Dx(), img.Bounds().Dy())
+	ticker := time.NewTicker(resizePollInterval)
+	defer ticker.Stop()
+
+	var src image.Rectangle
+	lastCols, lastRows := -1, -1
+	redraw := true
+	for {
+		cols, rows, err := term.GetSize(outFd)
+		if err != nil {
+			return fmt.Errorf("failed to get terminal size: %w", err)
+		}
+		if cols != lastCols || rows != lastRows {
+			lastCols, lastRows = cols, rows
+			redraw = true
+		}
+		if redraw {
+			src, err = draw(img, v, title, cols, rows, opts)
+			if err != nil {
+				return err
+			}
+			redraw = false
+		}
+
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			switch string(key) {
+			case "q", "\x1b", "\x03":
+				return nil
+			case "h", "\x1b[D":
+				v.pan(-1, 0, src)
+			case "l", "\x1b[C":
+				v.pan(1, 0, src)
+			case "k", "\x1b[A":
+				v.pan(0, -1, src)
+			case "j", "\x1b[B":
+				v.pan(0, 1, src)
+			case "+", "=":
+				v.zoomBy(zoomStep)
+			case "-", "_":
+				v.zoomBy(1 / zoomStep)
+			case "0":
+				v.reset()
+			default:
+				continue
+			}
+			redraw = true
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw renders the current view centered above a one-line status bar and
+// returns the part of the image shown.
+func draw(img image.Image, v *view, title string, cols, rows int, opts Options) (image.Rectangle, error) {
+	areaRows := rows - 1
+	src, drawCols, drawRows := v.layout(cols, areaRows, opts.CellWidth, opts.CellHeight)
+
+	var sb strings.Builder
+	sb.WriteString("\x1b[0m\x1b[2J")
+	if drawCols > 0 && drawRows > 0 {
+		x := (cols-drawCols)/2 + 1
+		y := (areaRows-drawRows)/2 + 1
+		if opts.Protocol == ProtocolKitty {
+			out, err := renderKitty(img, src, x, y, drawCols, drawRows, opts.CellWidth, opts.CellHeight)
+			if err != nil {
+				return src, err
+			}
+			sb.WriteString(out)
+		} else {
+			sb.WriteString(renderBlocks(img, src, x, y, drawCols, drawRows))
+		}
+	}
+
+	status := fmt.Sprintf(" %s  %d%%  hjkl/arrows pan  +/- zoom  0 fit  q quit", title, int(v.zoom*100))
+	status = ansi.Truncate(status, cols, "")
+	status += strings.Repeat(" ", max(cols-ansi.StringWidth(status), 0))
+	fmt.Fprintf(&sb, "\x1b[%d;1H\x1b[7m%s\x1b[0m", rows, status)
+
+	_, err := os.Stdout.WriteString(sb.String())
+	return src, err
+}
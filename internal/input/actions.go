@@ -34,6 +34,8 @@ func (d *ActionDispatcher) registerHandlers() {
 	d.Register("rename_window", handleRenameWindow)
 	d.Register("minimize_window", handleMinimizeWindow)
 	d.Register("restore_all", handleRestoreAll)
+	d.Register("minimize_all_windows", handleMinimizeAllWindows)
+	d.Register("close_all_windows", handleCloseAllWindows)
 	d.Register("next_window", handleNextWindow)
 	d.Register("prev_window", handlePrevWindow)
 
@@ -48,6 +50,7 @@ func (d *ActionDispatcher) registerHandlers() {
 		d.Register("switch_workspace_"+string(rune('0'+i)), makeSwitchWorkspaceHandler(i))
 		d.Register("move_and_follow_"+string(rune('0'+i)), makeMoveAndFollowHandler(i))
 	}
+	d.Register("previous_workspace", handlePreviousWorkspace)
 
 	// Layout actions
 	d.Register("snap_left", handleSnapLeft)
@@ -58,6 +61,15 @@ func (d *ActionDispatcher) registerHandlers() {
 	d.Register("snap_corner_2", makeSnapCornerHandler(app.SnapTopRight))
 	d.Register("snap_corner_3", makeSnapCornerHandler(app.SnapBottomLeft))
 	d.Register("snap_corner_4", makeSnapCornerHandler(app.SnapBottomRight))
+	d.Register("snap_top", makeSnapHandler(app.SnapTop))
+	d.Register("snap_bottom", makeSnapHandler(app.SnapBottom))
+	d.Register("snap_third_left", makeSnapHandler(app.SnapLeftThird))
+	d.Register("snap_third_center", makeSnapHandler(app.SnapCenterThird))
+	d.Register("snap_third_right", makeSnapHandler(app.SnapRightThird))
+	d.Register("snap_two_thirds_left", makeSnapHandler(app.SnapLeftTwoThirds))
+	d.Register("snap_two_thirds_right", makeSnapHandler(app.SnapRightTwoThirds))
+	d.Register("center_window", handleCenterWindow)
+	d.Register("cycle_geometry_preset", handleCycleGeometryPreset)
 	d.Register("toggle_tiling", handleToggleTiling)
 	d.Register("swap_left", handleSwapLeft)
 	d.Register("swap_right", handleSwapRight)
@@ -77,6 +89,11 @@ func (d *ActionDispatcher) registerHandlers() {
 	d.Register("split_vertical", handleSplitVertical)
 	d.Register("rotate_split", handleRotateSplit)
 	d.Register("equalize_splits", handleEqualizeSplits)
+	d.Register("grow_tile", handleGrowTile)
+	d.Register("shrink_tile", handleShrinkTile)
+	d.Register("promote_master", handlePromoteMaster)
+	d.Register("toggle_floating", handleToggleFloating)
+	d.Register("cycle_tiling_insert", handleCycleTilingInsert)
 	d.Register("preselect_left", handlePreselectLeft)
 	d.Register("preselect_right", handlePreselectRight)
 	d.Register("preselect_up", handlePreselectUp)
@@ -162,7 +179,7 @@ func handleNewWindow(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 
 func handleCloseWindow(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	if len(o.Windows) > 0 && o.FocusedWindow >= 0 {
-		o.DeleteWindow(o.FocusedWindow)
+		o.CloseWindowAndGroup(o.FocusedWindow)
 	}
 	return o, nil
 }
@@ -203,15 +220,17 @@ func handleMinimizeWindow(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 
 func handleRestoreAll(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	// Restore all minimized windows in current workspace
-	for i := range o.Windows {
-		if o.Windows[i].Minimized && o.Windows[i].Workspace == o.CurrentWorkspace {
-			o.RestoreWindow(i)
-		}
-	}
-	// Retile if in tiling mode
-	if o.AutoTiling {
-		o.TileAllWindows()
-	}
+	o.RestoreAllWindows()
+	return o, nil
+}
+
+func handleMinimizeAllWindows(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.MinimizeAllWindows()
+	return o, nil
+}
+
+func handleCloseAllWindows(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.RequestCloseAllWindows()
 	return o, nil
 }
 
@@ -236,11 +255,16 @@ func makeSelectWindowHandler(_ int) ActionHandler {
 
 func makeSwitchWorkspaceHandler(workspace int) ActionHandler {
 	return func(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-		o.SwitchToWorkspace(workspace)
+		o.SelectWorkspace(workspace)
 		return o, nil
 	}
 }
 
+func handlePreviousWorkspace(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.SwitchToPreviousWorkspace()
+	return o, nil
+}
+
 func makeMoveAndFollowHandler(workspace int) ActionHandler {
 	return func(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		if o.FocusedWindow >= 0 && o.FocusedWindow < len(o.Windows) {
@@ -255,30 +279,40 @@ func makeMoveAndFollowHandler(workspace int) ActionHandler {
 // ============================================================================
 
 func handleSnapLeft(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-	if !o.AutoTiling && len(o.Windows) > 0 && o.FocusedWindow >= 0 {
-		o.Snap(o.FocusedWindow, app.SnapLeft)
-	}
+	o.SnapFocused(app.SnapLeft)
 	return o, nil
 }
 
 func handleSnapRight(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-	if !o.AutoTiling && len(o.Windows) > 0 && o.FocusedWindow >= 0 {
-		o.Snap(o.FocusedWindow, app.SnapRight)
-	}
+	o.SnapFocused(app.SnapRight)
 	return o, nil
 }
 
 func handleSnapFullscreen(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-	if !o.AutoTiling && len(o.Windows) > 0 && o.FocusedWindow >= 0 {
-		o.Snap(o.FocusedWindow, app.SnapFullScreen)
-	}
+	o.SnapFocused(app.SnapFullScreen)
 	return o, nil
 }
 
 func handleUnsnap(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-	if !o.AutoTiling && len(o.Windows) > 0 && o.FocusedWindow >= 0 {
-		o.Snap(o.FocusedWindow, app.Unsnap)
+	o.SnapFocused(app.Unsnap)
+	return o, nil
+}
+
+// makeSnapHandler snaps the focused window, floating or with tiling off
+func makeSnapHandler(position app.SnapQuarter) ActionHandler {
+	return func(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+		o.SnapFocused(position)
+		return o, nil
 	}
+}
+
+func handleCenterWindow(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.CenterFocusedWindow()
+	return o, nil
+}
+
+func handleCycleGeometryPreset(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.CycleGeometryPreset()
 	return o, nil
 }
 
@@ -422,6 +456,31 @@ func handleEqualizeSplits(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	return o, nil
 }
 
+func handleGrowTile(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.GrowFocusedTile(0.05) // Move the surrounding splits by 5%
+	return o, nil
+}
+
+func handleShrinkTile(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.GrowFocusedTile(-0.05)
+	return o, nil
+}
+
+func handleToggleFloating(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.ToggleFloating()
+	return o, nil
+}
+
+func handlePromoteMaster(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.PromoteFocusedToMaster()
+	return o, nil
+}
+
+func handleCycleTilingInsert(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	o.CycleTilingInsert()
+	return o, nil
+}
+
 func handlePreselectLeft(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	if o.AutoTiling {
 		o.SetPreselection(layout.PreselectionLeft)
@@ -511,12 +570,11 @@ func handleQuit(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		}
 		return o, nil
 	}
-	// Show quit confirmation dialog (only if there are terminals with foreground processes)
+	// Show quit confirmation dialog (as set by appearance.confirm_quit)
 	if shouldShowQuitDialog(o) {
-		o.ShowQuitConfirm = true
-		o.QuitConfirmSelection = 0 // Default to Yes
+		o.OpenQuitConfirm()
 	} else {
-		// No foreground processes - quit and kill daemon session
+		// No confirmation needed - quit and kill daemon session
 		if o.IsDaemonSession && o.DaemonClient != nil {
 			_ = o.DaemonClient.KillSession()
 		}
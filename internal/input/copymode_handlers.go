@@ -23,7 +23,7 @@ func HandleCopyModeKey(msg tea.KeyPressMsg, o *app.OS, window *terminal.Window)
 	switch cm.State {
 	case terminal.CopyModeSearch:
 		return handleSearchInput(msg, cm, window, o)
-	case terminal.CopyModeVisualChar, terminal.CopyModeVisualLine:
+	case terminal.CopyModeVisualChar, terminal.CopyModeVisualLine, terminal.CopyModeVisualBlock:
 		return handleVisualInput(msg, cm, window, o)
 	case terminal.CopyModeNormal:
 		return handleNormalInput(msg, cm, window, o)
@@ -314,6 +314,16 @@ func handleNormalInput(msg tea.KeyPressMsg, cm *terminal.CopyMode, window *termi
 		o.ShowNotification("Search cleared", "info", config.NotificationDuration)
 		window.InvalidateCache()
 		return o, nil
+	case "ctrl+t":
+		// Toggle the time gutter showing when each line was written
+		window.ShowTimestamps = !window.ShowTimestamps
+		if window.ShowTimestamps {
+			o.ShowNotification("Timestamps: ON", "info", config.NotificationDuration)
+		} else {
+			o.ShowNotification("Timestamps: OFF", "info", config.NotificationDuration)
+		}
+		window.InvalidateCache()
+		return o, nil
 
 	// Visual mode
 	case "v":
@@ -324,6 +334,10 @@ func handleNormalInput(msg tea.KeyPressMsg, cm *terminal.CopyMode, window *termi
 		enterVisualLine(cm, window)
 		o.ShowNotification("VISUAL LINE", "info", 0)
 		return o, nil
+	case "ctrl+v":
+		enterVisualBlock(cm, window)
+		o.ShowNotification("VISUAL BLOCK", "info", 0)
+		return o, nil
 	}
 
 	window.InvalidateCache()
@@ -436,9 +450,8 @@ func handleVisualInput(msg tea.KeyPressMsg, cm *terminal.CopyMode, window *termi
 	case "y", "c":
 		text := extractVisualText(cm, window)
 		cm.State = terminal.CopyModeNormal
-		o.ShowNotification(fmt.Sprintf("Yanked %d chars", len(text)), "success", config.NotificationDuration)
 		window.InvalidateCache()
-		return o, tea.SetClipboard(text)
+		return o, o.CopyToClipboard(text, fmt.Sprintf("Yanked %d chars", len(text)))
 
 	// Movement in visual mode extends selection - basic
 	case "h", "left":
@@ -595,6 +608,16 @@ func handleVisualInput(msg tea.KeyPressMsg, cm *terminal.CopyMode, window *termi
 			enterVisualLine(cm, window)
 			o.ShowNotification("VISUAL LINE", "info", 0)
 		}
+	case "ctrl+v":
+		// Pressing Ctrl+V switches to visual block mode, or exits it
+		if cm.State == terminal.CopyModeVisualBlock {
+			cm.State = terminal.CopyModeNormal
+			o.ShowNotification("", "info", 0)
+		} else {
+			cm.State = terminal.CopyModeVisualBlock
+			updateVisualEnd(cm, window)
+			o.ShowNotification("VISUAL BLOCK", "info", 0)
+		}
 	}
 
 	window.InvalidateCache()
@@ -604,8 +627,8 @@ func handleVisualInput(msg tea.KeyPressMsg, cm *terminal.CopyMode, window *termi
 // HandleCopyModeMouseClick handles mouse clicks in copy mode
 func HandleCopyModeMouseClick(cm *terminal.CopyMode, window *terminal.Window, clickX, clickY int) {
 	// Convert window-relative coordinates (with border) to terminal coordinates
-	terminalX := clickX - window.X - 1 // Account for left border
-	terminalY := clickY - window.Y - 1 // Account for top border (title bar)
+	terminalX := clickX - window.X - 1 - window.TimestampGutter() // Account for left border and time gutter
+	terminalY := clickY - window.Y - 1                            // Account for top border (title bar)
 
 	// Check bounds
 	if terminalX < 0 || terminalY < 0 || terminalX >= window.Width-2 || terminalY >= window.Height-2 {
@@ -627,17 +650,18 @@ func HandleCopyModeMouseClick(cm *terminal.CopyMode, window *terminal.Window, cl
 	}
 
 	// If in visual mode, update selection end
-	if cm.State == terminal.CopyModeVisualChar || cm.State == terminal.CopyModeVisualLine {
+	if cm.Visual() {
 		updateVisualEnd(cm, window)
 	}
 
 	window.InvalidateCache()
 }
 
-// HandleCopyModeMouseDrag handles mouse drag start in copy mode (initiates visual selection)
-func HandleCopyModeMouseDrag(cm *terminal.CopyMode, window *terminal.Window, startX, startY int) {
+// HandleCopyModeMouseDrag handles mouse drag start in copy mode (initiates visual selection).
+// With block set the drag selects a rectangle instead of characters.
+func HandleCopyModeMouseDrag(cm *terminal.CopyMode, window *terminal.Window, startX, startY int, block bool) {
 	// Convert window-relative coordinates to terminal coordinates
-	terminalX := startX - window.X - 1
+	terminalX := startX - window.X - 1 - window.TimestampGutter()
 	terminalY := startY - window.Y - 1 // Account for top border
 
 	// Check bounds
@@ -647,7 +671,7 @@ func HandleCopyModeMouseDrag(cm *terminal.CopyMode, window *terminal.Window, sta
 
 	// Always exit visual mode first if we're in it, then start fresh
 	// This ensures each click-and-drag creates a new selection
-	if cm.State == terminal.CopyModeVisualChar || cm.State == terminal.CopyModeVisualLine {
+	if cm.Visual() {
 		cm.State = terminal.CopyModeNormal
 	}
 
@@ -665,8 +689,12 @@ func HandleCopyModeMouseDrag(cm *terminal.CopyMode, window *terminal.Window, sta
 		cm.CursorX--
 	}
 
-	// Enter visual character mode for new selection
-	enterVisualChar(cm, window)
+	// Enter visual character or block mode for new selection
+	if block {
+		enterVisualBlock(cm, window)
+	} else {
+		enterVisualChar(cm, window)
+	}
 
 	window.InvalidateCache()
 }
@@ -674,12 +702,12 @@ func HandleCopyModeMouseDrag(cm *terminal.CopyMode, window *terminal.Window, sta
 // HandleCopyModeMouseMotion handles mouse motion during drag in copy mode
 func HandleCopyModeMouseMotion(cm *terminal.CopyMode, window *terminal.Window, mouseX, mouseY int) {
 	// Only handle if in visual mode
-	if cm.State != terminal.CopyModeVisualChar && cm.State != terminal.CopyModeVisualLine {
+	if !cm.Visual() {
 		return
 	}
 
 	// Convert window-relative coordinates to terminal coordinates
-	terminalX := mouseX - window.X - 1
+	terminalX := mouseX - window.X - 1 - window.TimestampGutter()
 	terminalY := mouseY - window.Y - 1 // Account for top border (title bar)
 
 	// Check bounds
@@ -30,12 +30,20 @@ func enterVisualLine(cm *terminal.CopyMode, window *terminal.Window) {
 	cm.VisualEnd = terminal.Position{X: endX, Y: absY}
 }
 
+// enterVisualBlock enters visual block (rectangular) selection mode
+func enterVisualBlock(cm *terminal.CopyMode, window *terminal.Window) {
+	cm.State = terminal.CopyModeVisualBlock
+	absY := getAbsoluteY(cm, window)
+	cm.VisualStart = terminal.Position{X: cm.CursorX, Y: absY}
+	cm.VisualEnd = cm.VisualStart
+}
+
 // updateVisualEnd updates the visual selection end position
 func updateVisualEnd(cm *terminal.CopyMode, window *terminal.Window) {
 	absY := getAbsoluteY(cm, window)
 
 	switch cm.State {
-	case terminal.CopyModeVisualChar:
+	case terminal.CopyModeVisualChar, terminal.CopyModeVisualBlock:
 		cm.VisualEnd = terminal.Position{X: cm.CursorX, Y: absY}
 	case terminal.CopyModeVisualLine:
 		// For visual line mode, we need to select entire lines
@@ -72,6 +80,9 @@ func updateVisualEnd(cm *terminal.CopyMode, window *terminal.Window) {
 
 // extractVisualText extracts the text from the current visual selection
 func extractVisualText(cm *terminal.CopyMode, window *terminal.Window) string {
+	if cm.State == terminal.CopyModeVisualBlock {
+		return extractVisualBlock(cm, window)
+	}
 	start, end := cm.VisualStart, cm.VisualEnd
 
 	// Normalize selection
@@ -203,6 +214,35 @@ func extractVisualText(cm *terminal.CopyMode, window *terminal.Window) string {
 	return strings.TrimSpace(text.String())
 }
 
+// extractVisualBlock extracts the columns between the visual start and end
+// from every line between them, one line of text per line, without the
+// trailing spaces of each.
+func extractVisualBlock(cm *terminal.CopyMode, window *terminal.Window) string {
+	left, right := min(cm.VisualStart.X, cm.VisualEnd.X), max(cm.VisualStart.X, cm.VisualEnd.X)
+	top, bottom := min(cm.VisualStart.Y, cm.VisualEnd.Y), max(cm.VisualStart.Y, cm.VisualEnd.Y)
+	scrollbackLen := window.ScrollbackLen()
+
+	lines := make([]string, 0, bottom-top+1)
+	for y := top; y <= bottom; y++ {
+		var cells []uv.Cell
+		if y < scrollbackLen {
+			cells = window.ScrollbackLine(y)
+		} else {
+			cells = getScreenLineCells(window.Terminal, y-scrollbackLen)
+		}
+		var line strings.Builder
+		for x := left; x <= right && x < len(cells); x++ {
+			if cells[x].Content != "" {
+				line.WriteString(cells[x].Content)
+			} else if cells[x].Width > 0 {
+				line.WriteRune(' ')
+			}
+		}
+		lines = append(lines, strings.TrimRight(line.String(), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // getLineContentBounds returns the X positions of the first and last non-empty characters on a line
 func getLineContentBounds(_ *terminal.CopyMode, window *terminal.Window, absY int) (int, int) {
 	scrollbackLen := window.ScrollbackLen()
@@ -4,7 +4,9 @@
 package input
 
 import (
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -20,6 +22,29 @@ func HandleInput(msg tea.Msg, o *app.OS) (tea.Model, tea.Cmd) {
 	var result tea.Model
 	var cmd tea.Cmd
 
+	if o.ReadOnly {
+		return handleReadOnlyInput(msg, o)
+	}
+
+	// Mouse events on a split screen are handled in the active output's
+	// coordinates
+	if len(o.Outputs) >= 2 {
+		switch m := msg.(type) {
+		case tea.MouseClickMsg:
+			m.X = o.OutputMouseX(m.X, true)
+			msg = m
+		case tea.MouseMotionMsg:
+			m.X = o.OutputMouseX(m.X, false)
+			msg = m
+		case tea.MouseReleaseMsg:
+			m.X = o.OutputMouseX(m.X, false)
+			msg = m
+		case tea.MouseWheelMsg:
+			m.X = o.OutputMouseX(m.X, false)
+			msg = m
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyPressMsg:
 		result, cmd = HandleKeyPress(msg, o)
@@ -39,17 +64,17 @@ func HandleInput(msg tea.Msg, o *app.OS) (tea.Model, tea.Cmd) {
 	case tea.PasteMsg:
 		// Handle bracketed paste from terminal (when pasting via Cmd+V in Ghostty, etc.)
 		// Only handle paste in terminal mode
-		if o.Mode == app.TerminalMode {
+		if o.Mode == app.TerminalMode && o.InputLockedWindow() == nil {
 			o.ClipboardContent = msg.Content
-			handleClipboardPaste(o)
+			return o, handleClipboardPaste(o)
 		}
 		return o, nil
 	case tea.ClipboardMsg:
 		// Handle OSC 52 clipboard read response (from tea.ReadClipboard)
 		// Only handle paste in terminal mode
-		if o.Mode == app.TerminalMode {
+		if o.Mode == app.TerminalMode && o.InputLockedWindow() == nil {
 			o.ClipboardContent = msg.Content
-			handleClipboardPaste(o)
+			return o, handleClipboardPaste(o)
 		}
 		return o, nil
 	default:
@@ -65,23 +90,51 @@ func HandleInput(msg tea.Msg, o *app.OS) (tea.Model, tea.Cmd) {
 	return result, cmd
 }
 
-// shouldShowQuitDialog checks if there are any terminals with active foreground processes
-// to show quit confirmation for. Returns true if any window has a foreground process
-// (besides the shell itself), or if we're unable to detect (falls back to true).
-func shouldShowQuitDialog(o *app.OS) bool {
-	// Check each window for active foreground processes
-	for _, win := range o.Windows {
-		if win != nil && win.HasForegroundProcess() {
-			return true
+// handleReadOnlyInput handles input on a read-only mirror client. Everything is
+// discarded except the leader key followed by d or q, which detaches.
+func handleReadOnlyInput(msg tea.Msg, o *app.OS) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return o, nil
+	}
+
+	keyStr := strings.ToLower(key.String())
+	if o.PrefixActive && time.Since(o.LastPrefixTime) < PrefixKeyTimeout {
+		o.PrefixActive = false
+		if keyStr == "d" || keyStr == "q" {
+			return o, tea.Quit
 		}
+		return o, nil
+	}
+
+	o.PrefixActive = keyStr == strings.ToLower(config.LeaderKey)
+	if o.PrefixActive {
+		o.LastPrefixTime = time.Now()
+	} else {
+		o.ShowNotification("Read-only session: "+config.LeaderKey+" d to detach", "info", config.NotificationDuration)
+	}
+	return o, nil
+}
+
+// shouldShowQuitDialog checks if quitting should ask for confirmation first, following
+// appearance.confirm_quit: whenever windows are open, only if any window has a
+// foreground process (besides the shell itself), or never.
+func shouldShowQuitDialog(o *app.OS) bool {
+	switch config.ConfirmQuit {
+	case "never":
+		return false
+	case "running":
+		// Daemon windows' processes are looked up in the daemon
+		return len(o.RunningPrograms(o.Windows)) > 0
+	default:
+		return len(o.Windows) > 0
 	}
-	return false
 }
 
 // HandleKeyPress handles all keyboard input and routes to mode-specific handlers
 func HandleKeyPress(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-	// Capture key event for showkeys overlay if enabled
-	if o.ShowKeys {
+	// Capture key event for showkeys overlay if enabled, except passwords
+	if o.ShowKeys && !o.SecureInputFocused() {
 		o.CaptureKeyEvent(msg)
 	}
 
@@ -141,9 +194,39 @@ func HandleKeyPress(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		return o, nil
 	}
 
+	// Handle popups shown over IPC
+	if o.Popup != nil {
+		return handlePopupKey(msg, o)
+	}
+
+	// Handle multi-line paste confirmation dialog
+	if o.ShowPasteConfirm {
+		return handlePasteConfirmKey(msg, o)
+	}
+
+	// Handle close-all confirmation dialog
+	if o.ShowCloseAllConfirm {
+		return handleCloseAllConfirmKey(msg, o)
+	}
+
+	// Handle crash recovery restore prompt
+	if o.ShowRestorePrompt {
+		return handleRestorePromptKey(msg, o)
+	}
+
+	// A focused window with locked input swallows every key but the unlock
+	// chord, whatever the mode, unless a dialog is waiting for an answer
+	if o.InputLockedWindow() != nil {
+		if o.IsUnlockInputKey(msg.String()) {
+			o.ToggleInputLock()
+		}
+		return o, nil
+	}
+
 	// Record keystrokes when recording is active (before any other handling)
 	// Only record in terminal mode - WM mode actions are recorded at dispatch time
-	if o.TapeRecorder != nil && o.TapeRecorder.IsRecording() && !o.ShowTapeManager {
+	// Passwords typed at a prompt with echo off are never recorded
+	if o.TapeRecorder != nil && o.TapeRecorder.IsRecording() && !o.ShowTapeManager && !o.SecureInputFocused() {
 		if o.Mode == app.TerminalMode {
 			keyStr := msg.String()
 			// Skip workspace switch keys - they're recorded by SwitchToWorkspace
@@ -177,6 +260,32 @@ func HandleKeyPress(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		return handleRenameMode(msg, o)
 	}
 
+	// Handle the run-command prompt
+	if o.RunPrompt.Active {
+		return handlePromptKey(msg, o, &o.RunPrompt, o.SubmitRunPrompt, o.CancelRunPrompt)
+	}
+
+	// Handle the window tag prompt
+	if o.TagPrompt.Active {
+		return handlePromptKey(msg, o, &o.TagPrompt, func() tea.Cmd {
+			o.SubmitTagPrompt()
+			return nil
+		}, o.CancelTagPrompt)
+	}
+
+	// Handle the find on screen prompt
+	if o.FindPrompt.Active {
+		return handlePromptKey(msg, o, &o.FindPrompt, func() tea.Cmd {
+			o.SubmitFindPrompt()
+			return nil
+		}, o.CancelFindPrompt)
+	}
+
+	// Handle the command history browser
+	if o.ShowCommandHistory {
+		return handleCommandHistoryKey(msg, o)
+	}
+
 	// Terminal mode handling
 	if o.Mode == app.TerminalMode {
 		return HandleTerminalModeKey(msg, o)
@@ -228,6 +337,109 @@ func HandleKeyPress(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	return HandleWindowManagementModeKey(msg, o)
 }
 
+// handlePasteConfirmKey handles keyboard input while the multi-line paste
+// confirmation dialog is showing. It mirrors the quit dialog controls.
+func handlePasteConfirmKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	switch msg.String() {
+	case "left", "h":
+		o.PasteConfirmSelection = 0 // Yes (left)
+	case "right", "l":
+		o.PasteConfirmSelection = 1 // No (right)
+	case "y":
+		return o, o.ConfirmPendingPaste()
+	case "n", "esc":
+		o.CancelPendingPaste()
+	case "enter":
+		if o.PasteConfirmSelection == 0 {
+			return o, o.ConfirmPendingPaste()
+		}
+		o.CancelPendingPaste()
+	}
+	return o, nil
+}
+
+// handleCloseAllConfirmKey handles keyboard input for the close-all confirmation dialog
+func handleCloseAllConfirmKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	switch msg.String() {
+	case "left", "h":
+		o.CloseAllSelection = 0 // Yes (left)
+	case "right", "l":
+		o.CloseAllSelection = 1 // No (right)
+	case "y":
+		o.CloseAllWindows()
+	case "n", "esc":
+		o.CancelCloseAllWindows()
+	case "enter":
+		if o.CloseAllSelection == 0 {
+			o.CloseAllWindows()
+		} else {
+			o.CancelCloseAllWindows()
+		}
+	}
+	return o, nil
+}
+
+// handlePopupKey handles keyboard input for a popup shown over IPC
+func handlePopupKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	key := msg.String()
+	if o.Popup.Output {
+		// Command output closes on any key
+		o.ClosePopup(-1)
+		return o, nil
+	}
+	if o.Popup.Guarded {
+		// Keys meant for a window must not answer it, so only the arrow
+		// keys choose and Space picks
+		switch key {
+		case "left":
+			o.PopupSelect(-1)
+		case "right":
+			o.PopupSelect(1)
+		case "space":
+			o.ClosePopup(o.Popup.Selection)
+		case "esc":
+			o.ClosePopup(-1)
+		}
+		return o, nil
+	}
+	switch key {
+	case "left", "h", "shift+tab":
+		o.PopupSelect(-1)
+	case "right", "l", "tab":
+		o.PopupSelect(1)
+	case "enter":
+		o.ClosePopup(o.Popup.Selection)
+	case "esc", "q":
+		o.ClosePopup(-1)
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if index := int(key[0] - '1'); index < len(o.Popup.Choices) {
+			o.ClosePopup(index)
+		}
+	}
+	return o, nil
+}
+
+// handleRestorePromptKey handles keyboard input for the crash recovery prompt
+func handleRestorePromptKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	switch msg.String() {
+	case "left", "h":
+		o.RestorePromptSelection = 0 // Yes (left)
+	case "right", "l":
+		o.RestorePromptSelection = 1 // No (right)
+	case "y":
+		o.AcceptRecovery()
+	case "n", "esc":
+		o.DeclineRecovery()
+	case "enter":
+		if o.RestorePromptSelection == 0 {
+			o.AcceptRecovery()
+		} else {
+			o.DeclineRecovery()
+		}
+	}
+	return o, nil
+}
+
 // handleRenameMode handles keyboard input during window renaming
 func handleRenameMode(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	switch msg.String() {
@@ -235,6 +447,7 @@ func handleRenameMode(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		// Apply the new name
 		if focusedWindow := o.GetFocusedWindow(); focusedWindow != nil {
 			focusedWindow.CustomName = o.RenameBuffer
+			focusedWindow.ApplyScrollbackLimit() // Window rules may match the new name
 			focusedWindow.InvalidateCache()
 		}
 		o.RenamingWindow = false
@@ -259,6 +472,55 @@ func handleRenameMode(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	}
 }
 
+// handlePromptKey handles keyboard input while a prompt is shown: Enter
+// submits what was typed, Esc cancels and other keys edit the text
+func handlePromptKey(msg tea.KeyPressMsg, o *app.OS, prompt *app.Prompt, submit func() tea.Cmd, cancel func()) (*app.OS, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		return o, submit()
+	case "esc":
+		cancel()
+	default:
+		prompt.Edit(msg)
+	}
+	return o, nil
+}
+
+// handleCommandHistoryKey handles keyboard input while the command history
+// browser is shown: typing filters the history
+func handleCommandHistoryKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "esc":
+		o.ShowCommandHistory = false
+	case "up", "ctrl+p", "ctrl+k":
+		o.MoveCommandHistorySelection(-1)
+	case "down", "ctrl+n", "ctrl+j":
+		o.MoveCommandHistorySelection(1)
+	case "pgup":
+		o.MoveCommandHistorySelection(-10)
+	case "pgdown":
+		o.MoveCommandHistorySelection(10)
+	case "enter":
+		o.UseCommandHistoryEntry(true)
+	case "tab":
+		o.UseCommandHistoryEntry(false)
+	case "backspace":
+		if query := o.CommandHistoryQuery; len(query) > 0 {
+			o.SetCommandHistoryQuery(query[:len(query)-1])
+		}
+	case "ctrl+u":
+		o.SetCommandHistoryQuery("")
+	case "space":
+		o.SetCommandHistoryQuery(o.CommandHistoryQuery + " ")
+	default:
+		// Add character to the query if it's a printable character
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
+			o.SetCommandHistoryQuery(o.CommandHistoryQuery + key)
+		}
+	}
+	return o, nil
+}
+
 // handlePrefixKey handles Ctrl+B prefix key activation
 func handlePrefixKey(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	// If prefix is already active, deactivate it (double leader key cancels)
@@ -272,54 +534,136 @@ func handlePrefixKey(_ tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	return o, nil
 }
 
+// defaultKeybinds resolves keys when the OS has no keybind registry
+var defaultKeybinds = sync.OnceValue(func() *config.KeybindRegistry {
+	return config.NewKeybindRegistry(config.DefaultConfig())
+})
+
+// prefixAction returns the prefix_mode action bound to the key pressed after
+// the leader key, or "" if the key is unbound
+func prefixAction(msg tea.KeyPressMsg, o *app.OS) string {
+	registry := o.KeybindRegistry
+	if registry == nil {
+		registry = defaultKeybinds()
+	}
+	window := o.GetFocusedWindow()
+	return registry.GetPrefixActionForSplit(msg.String(), window != nil && window.HasPanes())
+}
+
+// prefixSelectNumber returns the window number of a prefix_select_N action
+func prefixSelectNumber(action string) int {
+	num, _ := strconv.Atoi(strings.TrimPrefix(action, "prefix_select_"))
+	return num
+}
+
+// handlePaneAction runs a prefix_mode pane, tab or group action on the
+// focused window and reports whether action was one
+func handlePaneAction(action string, o *app.OS) bool {
+	switch action {
+	case "prefix_split_pane_vertical":
+		o.SplitFocusedPaneVertical()
+	case "prefix_split_pane_horizontal":
+		o.SplitFocusedPaneHorizontal()
+	case "prefix_close_pane":
+		o.CloseFocusedPane()
+		if len(o.Windows) == 0 {
+			o.Mode = app.WindowManagementMode
+		}
+	case "prefix_next_pane":
+		o.CycleFocusedPane(1)
+	case "prefix_pane_left":
+		o.FocusPaneDirection(-1, 0)
+	case "prefix_pane_right":
+		o.FocusPaneDirection(1, 0)
+	case "prefix_pane_up":
+		o.FocusPaneDirection(0, -1)
+	case "prefix_pane_down":
+		o.FocusPaneDirection(0, 1)
+	case "prefix_resize_pane_left":
+		o.ResizeFocusedPane(-1, 0)
+	case "prefix_resize_pane_right":
+		o.ResizeFocusedPane(1, 0)
+	case "prefix_resize_pane_up":
+		o.ResizeFocusedPane(0, -1)
+	case "prefix_resize_pane_down":
+		o.ResizeFocusedPane(0, 1)
+	case "prefix_break_pane":
+		o.BreakFocusedPane()
+	case "prefix_join_pane":
+		o.OpenSidebarToJoin()
+	case "prefix_new_tab":
+		o.NewTabInFocusedWindow()
+	case "prefix_next_tab":
+		o.CycleFocusedTab(1)
+	case "prefix_prev_tab":
+		o.CycleFocusedTab(-1)
+	case "prefix_group_window":
+		o.OpenSidebarToGroup()
+	case "prefix_ungroup_window":
+		o.UngroupFocusedWindow()
+	default:
+		return false
+	}
+	return true
+}
+
 // HandlePrefixCommand handles prefix commands (Ctrl+B followed by another key)
 func HandlePrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	// Deactivate prefix after handling command
 	o.PrefixActive = false
 
-	switch msg.String() {
-	case "w":
+	// Escape always just cancels, even though it is also bound to prefix_detach
+	if key := msg.String(); key == "esc" || key == "ctrl+c" {
+		return o, nil
+	}
+
+	action := prefixAction(msg, o)
+	if handlePaneAction(action, o) {
+		return o, nil
+	}
+	switch action {
+	case "prefix_workspace":
 		// Activate workspace prefix mode
 		o.WorkspacePrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
-	case "m":
+	case "prefix_minimize":
 		// Activate minimize prefix mode
 		o.MinimizePrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
-	case "t":
+	case "prefix_window":
 		// Activate tiling/window prefix mode
 		o.TilingPrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
-	case "D":
+	case "prefix_debug":
 		// Activate debug prefix mode (Ctrl+B, Shift+D)
 		o.DebugPrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
-	case "T":
+	case "prefix_tape":
 		// Activate tape prefix mode (Ctrl+B, Shift+T)
 		o.TapePrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
 	// Window management
-	case "c":
+	case "prefix_new_window":
 		// Create new window (like tmux)
 		o.AddWindow("")
 		return o, nil
-	case "x":
+	case "prefix_close_window":
 		// Close current window
 		if len(o.Windows) > 0 && o.FocusedWindow >= 0 {
-			o.DeleteWindow(o.FocusedWindow)
+			o.CloseWindowAndGroup(o.FocusedWindow)
 		}
 		return o, nil
-	case ",", "r":
+	case "prefix_rename_window":
 		// Rename window (like tmux with ',' or like normal mode with 'r')
 		// Skip if window titles are hidden
 		if config.WindowTitlePosition != "hidden" && len(o.Windows) > 0 && o.FocusedWindow >= 0 {
@@ -332,81 +676,184 @@ func HandlePrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		return o, nil
 
 	// Window navigation
-	case "n", "tab":
+	case "prefix_next_window":
 		// Next window
 		if len(o.Windows) > 0 {
 			o.CycleToNextVisibleWindow()
 		}
 		return o, nil
-	case "p", "shift+tab":
+	case "prefix_prev_window":
 		// Previous window (like tmux with 'p' or like normal mode with 'shift+tab')
 		if len(o.Windows) > 0 {
 			o.CycleToPreviousVisibleWindow()
 		}
 		return o, nil
-	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+	case "prefix_select_0", "prefix_select_1", "prefix_select_2", "prefix_select_3", "prefix_select_4",
+		"prefix_select_5", "prefix_select_6", "prefix_select_7", "prefix_select_8", "prefix_select_9":
 		// Jump to window by number
-		return handlePrefixWindowSelection(msg, o)
+		return handlePrefixWindowSelection(prefixSelectNumber(action), o)
 
 	// Layout commands
-	case "space":
+	case "prefix_toggle_tiling":
 		// Toggle tiling mode (like tmux)
 		o.AutoTiling = !o.AutoTiling
 		if o.AutoTiling {
 			o.TileAllWindows()
 		}
 		return o, nil
-	case "z":
+	case "prefix_toggle_floating":
+		// Take the focused window out of the tiling layout or put it back
+		o.ToggleFloating()
+		return o, nil
+	case "prefix_fullscreen":
 		// Toggle fullscreen for current window
 		if !o.AutoTiling && len(o.Windows) > 0 && o.FocusedWindow >= 0 {
 			o.Snap(o.FocusedWindow, app.SnapFullScreen)
 		}
 		return o, nil
-	case "-":
+	case "prefix_pip":
+		// Toggle picture-in-picture for current window
+		o.TogglePiP()
+		return o, nil
+	case "prefix_mouse_capture":
+		// Toggle whether the focused pane's app receives the mouse
+		o.ToggleMouseCapture()
+		return o, nil
+	case "prefix_window_info":
+		// Show info and the scrollback limit of the focused pane
+		o.ToggleWindowInfo()
+		return o, nil
+	case "prefix_replay":
+		// Play back the focused pane's recorded output
+		o.ToggleReplay()
+		return o, nil
+	case "prefix_window_hints":
+		// Label the visible windows, the next key picks one
+		o.ToggleWindowHints()
+		return o, nil
+	case "prefix_image_hints":
+		// Label the image files on screen, the next key opens one
+		o.ToggleImageHints()
+		return o, nil
+	case "prefix_key_passthrough":
+		// Send every key to the focused window until the exit key
+		o.ToggleKeyPassthrough()
+		return o, nil
+	case "prefix_lock_input":
+		// Ignore keys for the focused window until the unlock chord
+		o.ToggleInputLock()
+		return o, nil
+	case "prefix_pin_min_size":
+		// Keep tiling and resizing from shrinking the focused window
+		o.TogglePinnedMinSize()
+		return o, nil
+	case "prefix_run_command":
+		// Prompt for a command whose output is shown in a popup
+		o.StartRunPrompt()
+		return o, nil
+	case "prefix_copy_last_output":
+		// Found with the shell's OSC 133 prompt marks
+		return o, o.CopyLastCommandOutput()
+	case "prefix_open_last_output":
+		if err := o.OpenLastCommandOutput(); err != nil {
+			o.ShowNotification(err.Error(), "error", config.NotificationDuration)
+		}
+		return o, nil
+	case "prefix_command_history":
+		// Commands reported by the shells' OSC 133 marks
+		o.ToggleCommandHistory()
+		return o, nil
+	case "prefix_set_mark":
+		// The next letter names the focused window
+		o.StartMarkPrompt(false)
+		return o, nil
+	case "prefix_jump_mark":
+		// The next letter picks the marked window to focus
+		o.StartMarkPrompt(true)
+		return o, nil
+	case "prefix_last_window":
+		// Bounce between the current and the previously focused window
+		o.FocusLastWindow()
+		return o, nil
+	case "prefix_urgent_window":
+		// Oldest bell, urgent trigger or failed command first
+		o.JumpToUrgentWindow()
+		return o, nil
+	case "prefix_tag_window":
+		o.StartTagPrompt()
+		return o, nil
+	case "prefix_zoom_window":
+		// Double the cell size of the focused window's content, or restore it
+		o.ToggleZoom()
+		return o, nil
+	case "prefix_find_on_screen":
+		o.StartFindPrompt()
+		return o, nil
+	case "prefix_move_window":
+		// Move the focused floating window with hjkl or the arrow keys
+		o.StartMoveMode()
+		return o, nil
+	case "prefix_gather_windows":
+		// Pull floating windows off screen back into view
+		o.GatherWindowsNotify()
+		return o, nil
+	case "prefix_split_horizontal":
 		// Split focused window horizontally (top/bottom)
 		if o.AutoTiling {
 			o.SplitFocusedHorizontal()
 			o.ShowNotification("Split Horizontal", "info", config.NotificationDuration)
 		}
 		return o, nil
-	case "|", "\\":
+	case "prefix_split_vertical":
 		// Split focused window vertically (left/right)
 		if o.AutoTiling {
 			o.SplitFocusedVertical()
 			o.ShowNotification("Split Vertical", "info", config.NotificationDuration)
 		}
 		return o, nil
-	case "R":
+	case "prefix_rotate_split":
 		// Rotate split direction at focused window
 		if o.AutoTiling {
 			o.RotateFocusedSplit()
 			o.ShowNotification("Split Rotated", "info", config.NotificationDuration)
 		}
 		return o, nil
-	case "=":
+	case "prefix_equalize_splits":
 		// Equalize all split ratios
 		if o.AutoTiling {
 			o.EqualizeSplits()
 			o.ShowNotification("Splits Equalized", "info", config.NotificationDuration)
 		}
 		return o, nil
+	case "prefix_grow_tile":
+		// Grow the focused window's share of the layout
+		o.GrowFocusedTile(0.05)
+		return o, nil
+	case "prefix_shrink_tile":
+		// Shrink the focused window's share of the layout
+		o.GrowFocusedTile(-0.05)
+		return o, nil
+	case "prefix_promote_master":
+		// Swap the focused window into the first tiling slot
+		o.PromoteFocusedToMaster()
+		return o, nil
 
 	// Copy mode
-	case "[":
+	case "prefix_selection":
 		// Enter copy mode (vim-style scrollback/selection)
-		if focusedWindow := o.GetFocusedWindow(); focusedWindow != nil {
+		if focusedWindow := o.GetFocusedPane(); focusedWindow != nil {
 			focusedWindow.EnterCopyMode()
 			o.ShowNotification("COPY MODE (hjkl/q)", "info", 2*time.Second)
 		}
 		return o, nil
 
 	// Help
-	case "?":
+	case "prefix_help":
 		// Toggle help
 		o.ShowHelp = !o.ShowHelp
 		return o, nil
 
-	case "d":
+	case "prefix_detach":
 		// Detach from daemon session - quit client but leave session running
 		if o.IsDaemonSession {
 			// Sync state to daemon before detaching
@@ -417,13 +864,12 @@ func HandlePrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		// Not in daemon mode, ignore
 		return o, nil
 
-	case "q":
-		// Show quit confirmation dialog (only if there are terminals with foreground processes)
+	case "prefix_quit":
+		// Show quit confirmation dialog (as set by appearance.confirm_quit)
 		if shouldShowQuitDialog(o) {
-			o.ShowQuitConfirm = true
-			o.QuitConfirmSelection = 0 // Default to Yes
+			o.OpenQuitConfirm()
 		} else {
-			// No foreground processes - quit and kill daemon session
+			// No confirmation needed - quit and kill daemon session
 			if o.IsDaemonSession && o.DaemonClient != nil {
 				_ = o.DaemonClient.KillSession()
 			}
@@ -432,11 +878,6 @@ func HandlePrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		}
 		return o, nil
 
-	// Exit prefix mode
-	case "esc", "ctrl+c":
-		// Just cancel prefix mode
-		return o, nil
-
 	default:
 		// Unknown command, ignore
 		return o, nil
@@ -444,8 +885,7 @@ func HandlePrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 }
 
 // handlePrefixWindowSelection handles window selection via prefix+number
-func handlePrefixWindowSelection(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-	num := int(msg.String()[0] - '0')
+func handlePrefixWindowSelection(num int, o *app.OS) (*app.OS, tea.Cmd) {
 	if o.AutoTiling {
 		// In tiling mode, select visible window in current workspace
 		visibleIndex := 0
@@ -0,0 +1,86 @@
+package input
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/app"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+)
+
+// TestShouldShowQuitDialog verifies quitting asks first only as
+// appearance.confirm_quit says, by default only while programs run.
+func TestShouldShowQuitDialog(t *testing.T) {
+	original := config.ConfirmQuit
+	defer func() { config.ConfirmQuit = original }()
+
+	o := &app.OS{Windows: []*terminal.Window{{ID: "shell"}}}
+	tests := []struct {
+		mode string
+		want bool
+	}{
+		{"always", true},
+		{"running", false}, // Only the shell runs
+		{"never", false},
+	}
+	for _, tt := range tests {
+		config.ConfirmQuit = tt.mode
+		if got := shouldShowQuitDialog(o); got != tt.want {
+			t.Errorf("shouldShowQuitDialog() with confirm_quit = %q is %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+	if got := shouldShowQuitDialog(&app.OS{}); got {
+		t.Error("shouldShowQuitDialog() without windows is true, want false")
+	}
+}
+
+// TestWorkspacePrefixFollowsKeybinds verifies the workspace prefix keys are
+// looked up in the keybind registry, so they can be rebound.
+func TestWorkspacePrefixFollowsKeybinds(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Keybindings.WorkspacePrefix["workspace_prefix_switch_2"] = []string{"x"}
+	o := &app.OS{
+		NumWorkspaces:    9,
+		CurrentWorkspace: 1,
+		WorkspaceFocus:   map[int]int{},
+		KeybindRegistry:  config.NewKeybindRegistry(cfg),
+	}
+
+	o.WorkspacePrefixActive = true
+	HandleWorkspacePrefixCommand(tea.KeyPressMsg{Code: '2', Text: "2"}, o)
+	if o.CurrentWorkspace != 1 {
+		t.Errorf("expected the unbound default key to do nothing, got workspace %d", o.CurrentWorkspace)
+	}
+
+	o.WorkspacePrefixActive = true
+	HandleWorkspacePrefixCommand(tea.KeyPressMsg{Code: 'x', Text: "x"}, o)
+	if o.CurrentWorkspace != 2 {
+		t.Errorf("expected the rebound key to switch to workspace 2, got %d", o.CurrentWorkspace)
+	}
+	if o.WorkspacePrefixActive {
+		t.Error("expected the workspace prefix to end")
+	}
+}
+
+// TestGuardedPopupIgnoresTyping verifies keys typed for a window cannot
+// answer a guarded popup: digits and Enter do nothing, Space picks.
+func TestGuardedPopupIgnoresTyping(t *testing.T) {
+	o := &app.OS{}
+	picked := -2
+	popup := o.ShowPopup("Clipboard write", "", 0, []string{"Allow", "Deny"}, "")
+	popup.Selection, popup.Guarded = 1, true
+	popup.OnClose = func(index int) { picked = index }
+
+	for _, key := range []tea.KeyPressMsg{{Code: '1', Text: "1"}, {Code: tea.KeyEnter}, {Code: 'h', Text: "h"}} {
+		handlePopupKey(key, o)
+	}
+	if o.Popup != popup || popup.Selection != 1 {
+		t.Fatalf("expected typing to leave the popup up on Deny, got popup %v selection %d", o.Popup, popup.Selection)
+	}
+	handlePopupKey(tea.KeyPressMsg{Code: tea.KeyLeft}, o)
+	handlePopupKey(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "}, o)
+	if o.Popup != nil || picked != 0 {
+		t.Errorf("expected Space to pick Allow after moving to it, got %d", picked)
+	}
+}
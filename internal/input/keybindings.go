@@ -8,7 +8,12 @@
 package input
 
 import (
+	"strconv"
+	"unicode"
+
 	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // Ctrl key combinations mapping
@@ -190,6 +195,94 @@ func getRawKeyBytesWithMode(msg tea.KeyPressMsg, applicationCursorKeys bool) []b
 	return []byte{}
 }
 
+// getTerminalKeyBytes converts a KeyPressMsg to the bytes the given terminal
+// expects. It follows the terminal's DECCKM mode and, when the application
+// enabled the Kitty keyboard protocol, sends CSI u sequences for keys the
+// legacy encoding cannot tell apart (e.g. Ctrl+I and Tab).
+func getTerminalKeyBytes(msg tea.KeyPressMsg, term *vt.Emulator) []byte {
+	if term == nil {
+		return getRawKeyBytes(msg)
+	}
+	flags := term.KittyKeyboardFlags()
+	if seq := getKittyKeyBytes(msg.Key(), flags); seq != nil {
+		return seq
+	}
+	// With all keys reported as escape codes, cursor keys always use CSI
+	appCursorKeys := term.ApplicationCursorKeys() && flags&ansi.KittyReportAllKeysAsEscapeCodes == 0
+	return getRawKeyBytesWithMode(msg, appCursorKeys)
+}
+
+// getKittyKeyBytes encodes a key as CSI code ; modifiers u for the given Kitty
+// keyboard protocol flags. It returns nil when the key keeps its legacy
+// encoding, which is the case for all keys when flags is zero and for
+// functional keys like arrows, whose legacy forms are already unambiguous.
+func getKittyKeyBytes(key tea.Key, flags int) []byte {
+	disambiguate := flags&ansi.KittyDisambiguateEscapeCodes != 0
+	allKeys := flags&ansi.KittyReportAllKeysAsEscapeCodes != 0
+	if !disambiguate && !allKeys {
+		return nil
+	}
+
+	mods := getKittyModParam(key.Mod)
+	switch key.Code {
+	case tea.KeyEscape:
+		return buildKittySequence(int(key.Code), mods)
+	case tea.KeyEnter, tea.KeyTab, tea.KeyBackspace:
+		// Unmodified Enter, Tab and Backspace stay legacy so shells keep working
+		if mods > 1 || allKeys {
+			return buildKittySequence(int(key.Code), mods)
+		}
+		return nil
+	}
+
+	// Functional keys (arrows, F-keys, ...) live above the Unicode range
+	if key.Code < ' ' || key.Code > unicode.MaxRune {
+		return nil
+	}
+
+	// Text keys with only Shift still produce their text
+	if allKeys || getKittyModParam(key.Mod&^tea.ModShift) > 1 {
+		return buildKittySequence(int(unicode.ToLower(key.Code)), mods)
+	}
+	return nil
+}
+
+// getKittyModParam calculates the Kitty keyboard protocol modifier parameter.
+// Unlike getModParam it includes Super, Hyper and Meta, which Kitty encodes
+// as separate bits. Lock keys are not reported.
+func getKittyModParam(mod tea.KeyMod) int {
+	modParam := 0
+	if mod&tea.ModShift != 0 {
+		modParam |= 1
+	}
+	if mod&tea.ModAlt != 0 {
+		modParam |= 2
+	}
+	if mod&tea.ModCtrl != 0 {
+		modParam |= 4
+	}
+	if mod&tea.ModSuper != 0 {
+		modParam |= 8
+	}
+	if mod&tea.ModHyper != 0 {
+		modParam |= 16
+	}
+	if mod&tea.ModMeta != 0 {
+		modParam |= 32
+	}
+	return modParam + 1
+}
+
+// buildKittySequence builds ESC[{code}u or ESC[{code};{mod}u.
+func buildKittySequence(code, modParam int) []byte {
+	seq := append([]byte{0x1b, '['}, strconv.Itoa(code)...)
+	if modParam > 1 {
+		seq = append(seq, ';')
+		seq = append(seq, strconv.Itoa(modParam)...)
+	}
+	return append(seq, 'u')
+}
+
 // handleModifierKeysWithMod handles keys with complex modifier combinations
 // The mod parameter should already be masked to only include actual modifier bits
 func handleModifierKeysWithMod(key tea.Key, mod tea.KeyMod) []byte {
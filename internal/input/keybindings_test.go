@@ -196,3 +196,32 @@ func TestIsMacOSOptionTab(t *testing.T) {
 		})
 	}
 }
+
+func TestGetKittyKeyBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      tea.Key
+		flags    int
+		expected []byte
+	}{
+		{"legacy flags", tea.Key{Code: 'i', Mod: tea.ModCtrl}, 0, nil},
+		{"ctrl+i", tea.Key{Code: 'i', Mod: tea.ModCtrl}, 1, []byte("\x1b[105;5u")},
+		{"plain tab", tea.Key{Code: tea.KeyTab}, 1, nil},
+		{"shift+enter", tea.Key{Code: tea.KeyEnter, Mod: tea.ModShift}, 1, []byte("\x1b[13;2u")},
+		{"escape", tea.Key{Code: tea.KeyEscape}, 1, []byte("\x1b[27u")},
+		{"shifted text", tea.Key{Code: 'a', Mod: tea.ModShift, Text: "A"}, 1, nil},
+		{"alt+shift+a", tea.Key{Code: 'A', Mod: tea.ModAlt | tea.ModShift}, 1, []byte("\x1b[97;4u")},
+		{"arrow", tea.Key{Code: tea.KeyUp, Mod: tea.ModCtrl}, 1, nil},
+		{"all keys text", tea.Key{Code: 'a', Text: "a"}, 8, []byte("\x1b[97u")},
+		{"all keys enter", tea.Key{Code: tea.KeyEnter}, 8, []byte("\x1b[13u")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getKittyKeyBytes(tt.key, tt.flags)
+			if !bytes.Equal(result, tt.expected) {
+				t.Errorf("getKittyKeyBytes(%v, %d) = %q, want %q", tt.key, tt.flags, result, tt.expected)
+			}
+		})
+	}
+}
@@ -2,12 +2,15 @@
 package input
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/Gaurav-Gosain/tuios/internal/app"
 	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 )
 
 // handleSidebarInput handles keyboard input when sidebar is focused
@@ -21,12 +24,55 @@ func handleSidebarInput(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	case "down", "j":
 		o.SidebarSelectNext()
 		return o, nil
-	case "enter", " ":
+	case "enter":
 		o.SidebarConfirmSelection()
 		return o, nil
+	case " ", "space":
+		// Mark windows for a batch action; while picking, space confirms
+		if o.SidebarPicking() {
+			o.SidebarConfirmSelection()
+		} else {
+			o.SidebarToggleMark()
+		}
+		return o, nil
 	case "esc", "q":
+		// Esc drops the marks before closing the sidebar
+		if key == "esc" && o.ClearSidebarMarks() {
+			return o, nil
+		}
 		o.CloseSidebar()
 		return o, nil
+	case "x":
+		if !o.SidebarPicking() {
+			o.SidebarCloseMarked()
+		}
+		return o, nil
+	case "m":
+		if !o.SidebarPicking() {
+			o.SidebarMinimizeMarked()
+		}
+		return o, nil
+	case "g":
+		if !o.SidebarPicking() {
+			o.SidebarGroupMarked()
+		}
+		return o, nil
+	case "t":
+		o.CycleSidebarTag()
+		return o, nil
+	case "c":
+		o.ToggleSidebarSort()
+		return o, nil
+	case "v":
+		// Show every window with the tag the sidebar is filtered by
+		if o.SidebarTag != "" && !o.SidebarPicking() {
+			tag := o.SidebarTag
+			o.CloseSidebar()
+			if err := o.ShowTagView(tag); err != nil {
+				o.ShowNotification(err.Error(), "error", config.NotificationDuration)
+			}
+		}
+		return o, nil
 	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 		// Quick jump to window by number
 		num := int(key[0] - '0')
@@ -36,12 +82,16 @@ func handleSidebarInput(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		}
 		return o, nil
 	}
+	if workspace := shiftedDigit(key); workspace > 0 && !o.SidebarPicking() {
+		o.SidebarMoveMarked(workspace)
+	}
 	return o, nil
 }
 
 // HandleTerminalModeKey handles keyboard input in terminal mode
 func HandleTerminalModeKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-	focusedWindow := o.GetFocusedWindow()
+	// Input goes to the active pane of a split window
+	focusedWindow := o.GetFocusedPane()
 
 	// Handle sidebar navigation (takes priority when sidebar is focused)
 	if o.SidebarVisible && o.SidebarFocused {
@@ -231,11 +281,64 @@ func HandleTerminalModeKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		return o, nil
 	}
 
+	// Handle scrollback memory viewer (takes priority in terminal mode)
+	if o.ShowScrollbackStats {
+		return handleScrollbackStatsKey(msg.String(), o)
+	}
+
+	// Handle window info overlay (takes priority in terminal mode)
+	if o.ShowWindowInfo {
+		return handleWindowInfoKey(msg.String(), o)
+	}
+
+	// Handle the minimized windows menu (takes priority in terminal mode)
+	if o.ShowMinimizedMenu {
+		return handleMinimizedMenuKey(msg.String(), o)
+	}
+
+	// Handle window jump labels (takes priority in terminal mode)
+	if o.ShowWindowHints {
+		o.SelectWindowHint(msg.String())
+		return o, nil
+	}
+
+	// Handle image file labels (takes priority in terminal mode)
+	if o.ImageHints != nil {
+		o.SelectImageHint(msg.String())
+		return o, nil
+	}
+
+	// Handle the letter of a mark to set or jump to
+	if o.MarkPrompt != "" {
+		o.HandleMarkKey(msg.String())
+		return o, nil
+	}
+
+	if o.MoveMode {
+		o.HandleMoveModeKey(msg.String())
+		return o, nil
+	}
+
+	// Handle output replay of the focused pane
+	if focusedWindow != nil && focusedWindow.Replay != nil {
+		return handleReplayKey(msg.String(), o, focusedWindow)
+	}
+
 	// Handle copy mode (vim-style scrollback/selection)
 	if focusedWindow != nil && focusedWindow.CopyMode != nil && focusedWindow.CopyMode.Active {
 		return HandleCopyModeKey(msg, o, focusedWindow)
 	}
 
+	// Key passthrough sends everything, the prefix key included, to the
+	// focused window, so a TUIOS running in it gets its own prefix commands
+	if o.KeyPassthrough {
+		if matchesBoundKey(o, "terminal_exit_passthrough", msg.String(), "f12") {
+			o.ToggleKeyPassthrough()
+			return o, nil
+		}
+		return sendKeyToWindow(msg, o, focusedWindow)
+	}
+
 	// Check for prefix key in terminal mode
 	msgStr := strings.ToLower(msg.String())
 	leaderKey := strings.ToLower(config.LeaderKey)
@@ -313,73 +416,124 @@ func HandleTerminalModeKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		return o, nil
 	}
 
-	// Normal terminal mode - pass through all keys
-	if focusedWindow != nil {
-		// Check if the terminal has DECCKM (application cursor keys) mode enabled
-		appCursorKeys := false
-		if focusedWindow.Terminal != nil {
-			appCursorKeys = focusedWindow.Terminal.ApplicationCursorKeys()
+	// Normal terminal mode - pass through all keys, to the other windows of
+	// the focused window's group as well
+	for _, member := range o.GroupInputTargets() {
+		if rawInput := getTerminalKeyBytes(msg, member.Terminal); len(rawInput) > 0 {
+			_ = member.SendInput(rawInput)
 		}
-		rawInput := getRawKeyBytesWithMode(msg, appCursorKeys)
+	}
+	return sendKeyToWindow(msg, o, focusedWindow)
+}
+
+// sendKeyToWindow sends a key to the focused window in terminal mode. Without
+// a window to receive it, TUIOS returns to window management mode.
+func sendKeyToWindow(msg tea.KeyPressMsg, o *app.OS, focusedWindow *terminal.Window) (*app.OS, tea.Cmd) {
+	clearOverrideSelection(o)
+	if focusedWindow != nil {
+		// Encode for the terminal's cursor key mode and Kitty keyboard flags
+		rawInput := getTerminalKeyBytes(msg, focusedWindow.Terminal)
 		if len(rawInput) > 0 {
 			if err := focusedWindow.SendInput(rawInput); err != nil {
 				// Terminal unavailable, switch back to window mode
 				o.Mode = app.WindowManagementMode
+				o.KeyPassthrough = false
 				focusedWindow.InvalidateCache()
 			}
 		}
 	} else {
 		// No focused window, switch back to window mode
 		o.Mode = app.WindowManagementMode
+		o.KeyPassthrough = false
 	}
 	return o, nil
 }
 
+// matchesBoundKey reports whether key is bound to the terminal mode action,
+// using fallback when there is no keybind registry. It serves direct
+// keybinds that are checked ahead of the usual keybind lookup.
+func matchesBoundKey(o *app.OS, action, key, fallback string) bool {
+	if o.KeybindRegistry == nil {
+		return strings.EqualFold(key, fallback)
+	}
+	return o.KeybindRegistry.GetTerminalModeAction(key) == action
+}
+
 // handleTerminalWorkspacePrefix handles workspace prefix commands in terminal mode
 func handleTerminalWorkspacePrefix(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	o.WorkspacePrefixActive = false
 	o.PrefixActive = false
 
+	registry := o.KeybindRegistry
+	if registry == nil {
+		registry = defaultKeybinds()
+	}
 	keyStr := msg.String()
+	action := registry.GetWorkspacePrefixAction(keyStr)
 
-	// Handle digit keys for workspace switching
-	if len(keyStr) == 1 && keyStr[0] >= '1' && keyStr[0] <= '9' {
-		num := int(keyStr[0] - '0')
-		o.SwitchToWorkspace(num)
-		return o, nil
-	}
-
-	// Handle Shift+digit for moving window to workspace
-	if o.FocusedWindow >= 0 && o.FocusedWindow < len(o.Windows) {
-		workspace := 0
-		switch keyStr {
-		case "shift+1", "!":
-			workspace = 1
-		case "shift+2", "@":
-			workspace = 2
-		case "shift+3", "#":
-			workspace = 3
-		case "shift+4", "$":
-			workspace = 4
-		case "shift+5", "%":
-			workspace = 5
-		case "shift+6", "^":
-			workspace = 6
-		case "shift+7", "&":
-			workspace = 7
-		case "shift+8", "*":
-			workspace = 8
-		case "shift+9", "(":
-			workspace = 9
-		}
-		if workspace > 0 {
-			o.MoveWindowToWorkspaceAndFollow(o.FocusedWindow, workspace)
-		}
+	switch action {
+	case "workspace_prefix_previous":
+		o.SwitchToPreviousWorkspace()
+		return o, nil
+	// Split the screen into virtual outputs, each with its own workspace
+	case "workspace_prefix_split":
+		o.ToggleOutputs()
+		return o, nil
+	case "workspace_prefix_output":
+		o.FocusNextOutput()
+		return o, nil
+	case "workspace_prefix_move_out":
+		o.MoveWindowToNextOutput()
+		return o, nil
+	}
+
+	if num, ok := strings.CutPrefix(action, "workspace_prefix_switch_"); ok {
+		workspace, _ := strconv.Atoi(num)
+		o.SelectWorkspace(workspace)
+		return o, nil
+	}
+
+	// Move the focused window, also for shift+N on layouts where the shifted
+	// digits are not the bound symbols
+	workspace := shiftedDigit(keyStr)
+	if num, ok := strings.CutPrefix(action, "workspace_prefix_move_"); ok {
+		workspace, _ = strconv.Atoi(num)
+	} else if action != "" {
+		return o, nil
+	}
+	if workspace > 0 && o.FocusedWindow >= 0 && o.FocusedWindow < len(o.Windows) {
+		o.MoveWindowToWorkspaceAndFollow(o.FocusedWindow, workspace)
 	}
 
 	return o, nil
 }
 
+// shiftedDigit returns the digit 1-9 typed with Shift held, read from either
+// the shift+N form or the US layout symbol, or 0 for other keys.
+func shiftedDigit(key string) int {
+	switch key {
+	case "shift+1", "!":
+		return 1
+	case "shift+2", "@":
+		return 2
+	case "shift+3", "#":
+		return 3
+	case "shift+4", "$":
+		return 4
+	case "shift+5", "%":
+		return 5
+	case "shift+6", "^":
+		return 6
+	case "shift+7", "&":
+		return 7
+	case "shift+8", "*":
+		return 8
+	case "shift+9", "(":
+		return 9
+	}
+	return 0
+}
+
 // handleTerminalMinimizePrefix handles minimize prefix commands in terminal mode
 func handleTerminalMinimizePrefix(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	o.MinimizePrefixActive = false
@@ -411,6 +565,14 @@ func handleTerminalMinimizePrefix(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.
 			}
 		}
 		return o, nil
+	case "l":
+		// List the minimized windows of every workspace
+		o.ToggleMinimizedMenu()
+		return o, nil
+	case "a":
+		// Minimize every window of the workspace
+		o.MinimizeAllWindows()
+		return o, nil
 	case "shift+m", "M":
 		// Restore all minimized windows
 		for _, idx := range minimizedWindows {
@@ -443,7 +605,7 @@ func handleTerminalTilingPrefix(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cm
 	case "x":
 		// Close window
 		if len(o.Windows) > 0 && o.FocusedWindow >= 0 {
-			o.DeleteWindow(o.FocusedWindow)
+			o.CloseWindowAndGroup(o.FocusedWindow)
 			// If we still have windows, stay in terminal mode
 			if len(o.Windows) > 0 {
 				if newFocused := o.GetFocusedWindow(); newFocused != nil {
@@ -455,6 +617,10 @@ func handleTerminalTilingPrefix(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cm
 			}
 		}
 		return o, nil
+	case "X":
+		// Close every window of the workspace after confirmation
+		o.RequestCloseAllWindows()
+		return o, nil
 	case "r":
 		// Rename window - exit terminal mode for this (unless titles are hidden)
 		if config.WindowTitlePosition != "hidden" && len(o.Windows) > 0 && o.FocusedWindow >= 0 {
@@ -522,6 +688,19 @@ func handleTerminalDebugPrefix(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd
 			o.ShowNotification("Cache Stats: OFF", "info", config.NotificationDuration)
 		}
 		return o, nil
+	case "w":
+		// Open a debug console window
+		o.OpenDebugConsole()
+		return o, nil
+	case "m":
+		// Toggle scrollback memory statistics
+		o.ShowScrollbackStats = !o.ShowScrollbackStats
+		if o.ShowScrollbackStats {
+			o.ShowNotification("Scrollback Memory: ON", "info", config.NotificationDuration)
+		} else {
+			o.ShowNotification("Scrollback Memory: OFF", "info", config.NotificationDuration)
+		}
+		return o, nil
 	case "k":
 		// Toggle showkeys overlay
 		o.ShowKeys = !o.ShowKeys
@@ -549,6 +728,106 @@ func handleTerminalDebugPrefix(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd
 	}
 }
 
+// handleScrollbackStatsKey handles keys while the scrollback memory viewer is shown
+func handleScrollbackStatsKey(key string, o *app.OS) (*app.OS, tea.Cmd) {
+	switch key {
+	case "q", "esc", "m":
+		o.ShowScrollbackStats = false
+	case "t":
+		if n := o.TrimScrollbackNow(); n > 0 {
+			o.ShowNotification(fmt.Sprintf("Trimmed %d scrollback lines", n), "info", config.NotificationDuration)
+		} else {
+			o.ShowNotification("Scrollback within budget", "info", config.NotificationDuration)
+		}
+	}
+	// Ignore other keys when the scrollback memory viewer is active
+	return o, nil
+}
+
+// handleWindowInfoKey handles keys while the window info overlay is shown
+func handleWindowInfoKey(key string, o *app.OS) (*app.OS, tea.Cmd) {
+	if o.WindowInfoEditing {
+		switch key {
+		case "enter":
+			o.ApplyWindowInfoInput()
+		case "esc":
+			o.WindowInfoEditing = false
+			o.WindowInfoBuffer = ""
+		case "backspace":
+			if len(o.WindowInfoBuffer) > 0 {
+				o.WindowInfoBuffer = o.WindowInfoBuffer[:len(o.WindowInfoBuffer)-1]
+			}
+		default:
+			if len(key) == 1 && key[0] >= '0' && key[0] <= '9' && len(o.WindowInfoBuffer) < 7 {
+				o.WindowInfoBuffer += key
+			}
+		}
+		return o, nil
+	}
+
+	switch key {
+	case "q", "esc", "i":
+		o.ShowWindowInfo = false
+	case "s":
+		o.WindowInfoEditing = true
+		o.WindowInfoBuffer = ""
+	}
+	return o, nil
+}
+
+// handleMinimizedMenuKey handles keys while the minimized windows menu is shown
+func handleMinimizedMenuKey(key string, o *app.OS) (*app.OS, tea.Cmd) {
+	switch key {
+	case "q", "esc":
+		o.ShowMinimizedMenu = false
+	case "j", "down":
+		o.MoveMinimizedMenuSelection(1)
+	case "k", "up":
+		o.MoveMinimizedMenuSelection(-1)
+	case "enter":
+		o.RestoreMinimizedMenuEntry(o.MinimizedMenuSelection)
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		o.RestoreMinimizedMenuEntry(int(key[0] - '1'))
+	}
+	// Ignore other keys when the minimized windows menu is active
+	return o, nil
+}
+
+// handleReplayKey handles keys while the focused pane replays its output
+func handleReplayKey(key string, o *app.OS, pane *terminal.Window) (*app.OS, tea.Cmd) {
+	r := pane.Replay
+	switch key {
+	case "q", "esc":
+		o.ToggleReplay()
+		return o, nil
+	case "space", " ":
+		r.TogglePause()
+	case "h", "left":
+		r.Seek(-5 * time.Second)
+	case "l", "right":
+		r.Seek(5 * time.Second)
+	case "H", "shift+h":
+		r.Seek(-30 * time.Second)
+	case "L", "shift+l":
+		r.Seek(30 * time.Second)
+	case "g", "0", "home":
+		r.Seek(-r.Duration)
+	case "G", "$", "end":
+		r.Seek(r.Duration)
+	case "+", "=":
+		r.ChangeSpeed(1)
+	case "-", "_":
+		r.ChangeSpeed(-1)
+	default:
+		return o, nil
+	}
+	pane.InvalidateCache()
+	if w := o.GetFocusedWindow(); w != nil {
+		w.InvalidateCache()
+	}
+	return o, nil
+}
+
 // handleTerminalTapePrefix handles tape prefix commands (Ctrl+B, T, ...)
 func handleTerminalTapePrefix(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	o.TapePrefixActive = false
@@ -588,38 +867,54 @@ func handleTerminalTapePrefix(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd)
 // handleTerminalPrefixCommand handles prefix commands in terminal mode
 func handleTerminalPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	o.PrefixActive = false
-	switch msg.String() {
-	case "w":
+
+	// Escape always just exits terminal mode (doesn't detach), even though it
+	// is also bound to prefix_detach
+	if msg.String() == "esc" {
+		o.Mode = app.WindowManagementMode
+		o.ShowNotification("Window Management Mode", "info", config.NotificationDuration)
+		if focusedWindow := o.GetFocusedWindow(); focusedWindow != nil {
+			focusedWindow.InvalidateCache()
+		}
+		return o, nil
+	}
+
+	action := prefixAction(msg, o)
+	if handlePaneAction(action, o) {
+		return o, nil
+	}
+	switch action {
+	case "prefix_workspace":
 		// Activate workspace prefix mode
 		o.WorkspacePrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
-	case "m":
+	case "prefix_minimize":
 		// Activate minimize prefix mode
 		o.MinimizePrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
-	case "t":
+	case "prefix_window":
 		// Activate tiling/window prefix mode
 		o.TilingPrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
-	case "D":
+	case "prefix_debug":
 		// Activate debug prefix mode (Ctrl+B, Shift+D)
 		o.DebugPrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
-	case "T":
+	case "prefix_tape":
 		// Activate tape prefix mode (Ctrl+B, Shift+T)
 		o.TapePrefixActive = true
 		o.PrefixActive = true // Keep prefix active for the next key
 		o.LastPrefixTime = time.Now()
 		return o, nil
-	case "d":
+	case "prefix_detach":
 		// Detach from daemon session - quit client but leave session running
 		if o.IsDaemonSession {
 			// Sync state to daemon before detaching
@@ -635,17 +930,9 @@ func handleTerminalPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.C
 			focusedWindow.InvalidateCache()
 		}
 		return o, nil
-	case "esc":
-		// Escape always just exits terminal mode (doesn't detach)
-		o.Mode = app.WindowManagementMode
-		o.ShowNotification("Window Management Mode", "info", config.NotificationDuration)
-		if focusedWindow := o.GetFocusedWindow(); focusedWindow != nil {
-			focusedWindow.InvalidateCache()
-		}
-		return o, nil
 
 	// Window navigation commands work in insert mode
-	case "n", "tab":
+	case "prefix_next_window":
 		// Next window
 		o.CycleToNextVisibleWindow()
 		// Refresh the new window in terminal mode
@@ -653,7 +940,7 @@ func handleTerminalPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.C
 			newFocused.InvalidateCache()
 		}
 		return o, nil
-	case "p", "shift+tab":
+	case "prefix_prev_window":
 		// Previous window (like tmux with 'p' or like normal mode with 'shift+tab')
 		o.CycleToPreviousVisibleWindow()
 		// Refresh the new window in terminal mode
@@ -661,19 +948,20 @@ func handleTerminalPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.C
 			newFocused.InvalidateCache()
 		}
 		return o, nil
-	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+	case "prefix_select_0", "prefix_select_1", "prefix_select_2", "prefix_select_3", "prefix_select_4",
+		"prefix_select_5", "prefix_select_6", "prefix_select_7", "prefix_select_8", "prefix_select_9":
 		// Jump to window by number
-		return handleTerminalWindowSelection(msg, o)
+		return handleTerminalWindowSelection(prefixSelectNumber(action), o)
 
 	// Window management
-	case "c":
+	case "prefix_new_window":
 		// Create new window
 		o.AddWindow("")
 		return o, nil
-	case "x":
+	case "prefix_close_window":
 		// Close current window
 		if len(o.Windows) > 0 && o.FocusedWindow >= 0 {
-			o.DeleteWindow(o.FocusedWindow)
+			o.CloseWindowAndGroup(o.FocusedWindow)
 			// If we still have windows, stay in terminal mode
 			if len(o.Windows) > 0 {
 				if newFocused := o.GetFocusedWindow(); newFocused != nil {
@@ -685,7 +973,7 @@ func handleTerminalPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.C
 			}
 		}
 		return o, nil
-	case ",", "r":
+	case "prefix_rename_window":
 		// Rename window - exit terminal mode for this (like tmux with ',' or like normal mode with 'r')
 		// Skip if window titles are hidden
 		if config.WindowTitlePosition != "hidden" && len(o.Windows) > 0 && o.FocusedWindow >= 0 {
@@ -699,73 +987,174 @@ func handleTerminalPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.C
 		return o, nil
 
 	// Layout commands
-	case "space":
+	case "prefix_toggle_tiling":
 		// Toggle tiling mode (like tmux)
 		o.AutoTiling = !o.AutoTiling
 		if o.AutoTiling {
 			o.TileAllWindows()
 		}
 		return o, nil
-	case "z":
+	case "prefix_toggle_floating":
+		// Take the focused window out of the tiling layout or put it back
+		o.ToggleFloating()
+		return o, nil
+	case "prefix_fullscreen":
 		// Toggle fullscreen for current window
 		if !o.AutoTiling && len(o.Windows) > 0 && o.FocusedWindow >= 0 {
 			o.Snap(o.FocusedWindow, app.SnapFullScreen)
 		}
 		return o, nil
-	case "-":
+	case "prefix_pip":
+		// Toggle picture-in-picture for current window
+		o.TogglePiP()
+		return o, nil
+	case "prefix_mouse_capture":
+		// Toggle whether the focused pane's app receives the mouse
+		o.ToggleMouseCapture()
+		return o, nil
+	case "prefix_window_info":
+		// Show info and the scrollback limit of the focused pane
+		o.ToggleWindowInfo()
+		return o, nil
+	case "prefix_replay":
+		// Play back the focused pane's recorded output
+		o.ToggleReplay()
+		return o, nil
+	case "prefix_window_hints":
+		// Label the visible windows, the next key picks one
+		o.ToggleWindowHints()
+		return o, nil
+	case "prefix_image_hints":
+		// Label the image files on screen, the next key opens one
+		o.ToggleImageHints()
+		return o, nil
+	case "prefix_key_passthrough":
+		// Send every key to the focused window until the exit key
+		o.ToggleKeyPassthrough()
+		return o, nil
+	case "prefix_lock_input":
+		// Ignore keys for the focused window until the unlock chord
+		o.ToggleInputLock()
+		return o, nil
+	case "prefix_pin_min_size":
+		// Keep tiling and resizing from shrinking the focused window
+		o.TogglePinnedMinSize()
+		return o, nil
+	case "prefix_run_command":
+		// Prompt for a command whose output is shown in a popup
+		o.StartRunPrompt()
+		return o, nil
+	case "prefix_copy_last_output":
+		// Found with the shell's OSC 133 prompt marks
+		return o, o.CopyLastCommandOutput()
+	case "prefix_open_last_output":
+		if err := o.OpenLastCommandOutput(); err != nil {
+			o.ShowNotification(err.Error(), "error", config.NotificationDuration)
+		}
+		return o, nil
+	case "prefix_command_history":
+		// Commands reported by the shells' OSC 133 marks
+		o.ToggleCommandHistory()
+		return o, nil
+	case "prefix_set_mark":
+		// The next letter names the focused window
+		o.StartMarkPrompt(false)
+		return o, nil
+	case "prefix_jump_mark":
+		// The next letter picks the marked window to focus
+		o.StartMarkPrompt(true)
+		return o, nil
+	case "prefix_last_window":
+		// Bounce between the current and the previously focused window
+		o.FocusLastWindow()
+		return o, nil
+	case "prefix_urgent_window":
+		// Oldest bell, urgent trigger or failed command first
+		o.JumpToUrgentWindow()
+		return o, nil
+	case "prefix_tag_window":
+		o.StartTagPrompt()
+		return o, nil
+	case "prefix_zoom_window":
+		// Double the cell size of the focused window's content, or restore it
+		o.ToggleZoom()
+		return o, nil
+	case "prefix_find_on_screen":
+		o.StartFindPrompt()
+		return o, nil
+	case "prefix_move_window":
+		// Move the focused floating window with hjkl or the arrow keys
+		o.StartMoveMode()
+		return o, nil
+	case "prefix_gather_windows":
+		// Pull floating windows off screen back into view
+		o.GatherWindowsNotify()
+		return o, nil
+	case "prefix_split_horizontal":
 		// Split focused window horizontally (top/bottom)
 		if o.AutoTiling {
 			o.SplitFocusedHorizontal()
 			o.ShowNotification("Split Horizontal", "info", config.NotificationDuration)
 		}
 		return o, nil
-	case "|", "\\":
+	case "prefix_split_vertical":
 		// Split focused window vertically (left/right)
 		if o.AutoTiling {
 			o.SplitFocusedVertical()
 			o.ShowNotification("Split Vertical", "info", config.NotificationDuration)
 		}
 		return o, nil
-	case "R":
+	case "prefix_rotate_split":
 		// Rotate split direction at focused window
 		if o.AutoTiling {
 			o.RotateFocusedSplit()
 			o.ShowNotification("Split Rotated", "info", config.NotificationDuration)
 		}
 		return o, nil
-	case "=":
+	case "prefix_equalize_splits":
 		// Equalize all split ratios
 		if o.AutoTiling {
 			o.EqualizeSplits()
 			o.ShowNotification("Splits Equalized", "info", config.NotificationDuration)
 		}
 		return o, nil
-	case "b":
+	case "prefix_grow_tile":
+		// Grow the focused window's share of the layout
+		o.GrowFocusedTile(0.05)
+		return o, nil
+	case "prefix_shrink_tile":
+		// Shrink the focused window's share of the layout
+		o.GrowFocusedTile(-0.05)
+		return o, nil
+	case "prefix_promote_master":
+		// Swap the focused window into the first tiling slot
+		o.PromoteFocusedToMaster()
+		return o, nil
+	case "prefix_sidebar":
 		// Toggle sidebar (browser-style window list)
 		o.ToggleSidebar()
 		return o, nil
-	case "[":
+	case "prefix_selection":
 		// Enter copy mode (vim-style scrollback/selection)
-		if focusedWindow := o.GetFocusedWindow(); focusedWindow != nil {
+		if focusedWindow := o.GetFocusedPane(); focusedWindow != nil {
 			focusedWindow.EnterCopyMode()
 			o.ShowNotification("COPY MODE (hjkl/q)", "info", config.NotificationDuration*2)
 		}
 		return o, nil
 
 	// Help
-	case "?":
+	case "prefix_help":
 		// Toggle help
 		o.ShowHelp = !o.ShowHelp
 		return o, nil
 
-	case "q":
-		// Show quit confirmation dialog (only if there are terminals with foreground processes)
+	case "prefix_quit":
+		// Show quit confirmation dialog (as set by appearance.confirm_quit)
 		o.PrefixActive = false
 		if shouldShowQuitDialog(o) {
-			o.ShowQuitConfirm = true
-			o.QuitConfirmSelection = 0 // Default to Yes
+			o.OpenQuitConfirm()
 		} else {
-			// No foreground processes - quit and kill daemon session
+			// No confirmation needed - quit and kill daemon session
 			if o.IsDaemonSession && o.DaemonClient != nil {
 				_ = o.DaemonClient.KillSession()
 			}
@@ -776,13 +1165,9 @@ func handleTerminalPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.C
 
 	default:
 		// Unknown prefix command, pass through the key
-		focusedWindow := o.GetFocusedWindow()
+		focusedWindow := o.GetFocusedPane()
 		if focusedWindow != nil {
-			appCursorKeys := false
-			if focusedWindow.Terminal != nil {
-				appCursorKeys = focusedWindow.Terminal.ApplicationCursorKeys()
-			}
-			rawInput := getRawKeyBytesWithMode(msg, appCursorKeys)
+			rawInput := getTerminalKeyBytes(msg, focusedWindow.Terminal)
 			if len(rawInput) > 0 {
 				_ = focusedWindow.SendInput(rawInput)
 			}
@@ -792,8 +1177,7 @@ func handleTerminalPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.C
 }
 
 // handleTerminalWindowSelection handles window selection in terminal mode
-func handleTerminalWindowSelection(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-	num := int(msg.String()[0] - '0')
+func handleTerminalWindowSelection(num int, o *app.OS) (*app.OS, tea.Cmd) {
 	if o.AutoTiling {
 		// In tiling mode, select visible window in current workspace
 		visibleIndex := 0
@@ -828,7 +1212,7 @@ func handleTerminalWindowSelection(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea
 
 // HandleWindowManagementModeKey handles keyboard input in window management mode
 func HandleWindowManagementModeKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd) {
-	focusedWindow := o.GetFocusedWindow()
+	focusedWindow := o.GetFocusedPane()
 
 	// Handle sidebar navigation (takes priority when sidebar is focused)
 	if o.SidebarVisible && o.SidebarFocused {
@@ -1008,6 +1392,49 @@ func HandleWindowManagementModeKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea
 		return o, nil
 	}
 
+	// Handle scrollback memory viewer (takes priority in window management mode)
+	if o.ShowScrollbackStats {
+		return handleScrollbackStatsKey(key, o)
+	}
+
+	// Handle window info overlay (takes priority in window management mode)
+	if o.ShowWindowInfo {
+		return handleWindowInfoKey(key, o)
+	}
+
+	// Handle the minimized windows menu (takes priority in window management mode)
+	if o.ShowMinimizedMenu {
+		return handleMinimizedMenuKey(key, o)
+	}
+
+	// Handle window jump labels (takes priority in window management mode)
+	if o.ShowWindowHints {
+		o.SelectWindowHint(key)
+		return o, nil
+	}
+
+	// Handle image file labels (takes priority in window management mode)
+	if o.ImageHints != nil {
+		o.SelectImageHint(key)
+		return o, nil
+	}
+
+	// Handle the letter of a mark to set or jump to
+	if o.MarkPrompt != "" {
+		o.HandleMarkKey(key)
+		return o, nil
+	}
+
+	if o.MoveMode {
+		o.HandleMoveModeKey(key)
+		return o, nil
+	}
+
+	// Handle output replay (takes priority in window management mode)
+	if pane := o.GetFocusedPane(); pane != nil && pane.Replay != nil {
+		return handleReplayKey(key, o, pane)
+	}
+
 	// Try config-based dispatch first (if registry is available)
 	if o.KeybindRegistry != nil {
 		action := o.KeybindRegistry.GetAction(key)
@@ -1023,12 +1450,11 @@ func HandleWindowManagementModeKey(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea
 	// Only Ctrl+C is kept as emergency quit
 	switch key {
 	case "ctrl+c":
-		// Emergency quit - show confirmation dialog (only if there are terminals)
+		// Emergency quit - show confirmation dialog (as set by appearance.confirm_quit)
 		if shouldShowQuitDialog(o) {
-			o.ShowQuitConfirm = true
-			o.QuitConfirmSelection = 0 // Default to Yes
+			o.OpenQuitConfirm()
 		} else {
-			// No terminals - just quit
+			// No confirmation needed - just quit
 			o.Cleanup()
 			return o, tea.Quit
 		}
@@ -1081,6 +1507,14 @@ func HandleMinimizePrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.C
 			}
 		}
 		return o, nil
+	case "l":
+		// List the minimized windows of every workspace
+		o.ToggleMinimizedMenu()
+		return o, nil
+	case "a":
+		// Minimize every window of the workspace
+		o.MinimizeAllWindows()
+		return o, nil
 	case "shift+m", "M":
 		// Restore all minimized windows
 		for _, idx := range minimizedWindows {
@@ -1113,9 +1547,13 @@ func HandleTilingPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd
 	case "x":
 		// Close window
 		if len(o.Windows) > 0 && o.FocusedWindow >= 0 {
-			o.DeleteWindow(o.FocusedWindow)
+			o.CloseWindowAndGroup(o.FocusedWindow)
 		}
 		return o, nil
+	case "X":
+		// Close every window of the workspace after confirmation
+		o.RequestCloseAllWindows()
+		return o, nil
 	case "r":
 		// Reset cache stats if showing cache stats overlay
 		if o.ShowCacheStats {
@@ -1187,6 +1625,19 @@ func HandleDebugPrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd)
 			o.ShowNotification("Cache Stats: OFF", "info", config.NotificationDuration)
 		}
 		return o, nil
+	case "w":
+		// Open a debug console window
+		o.OpenDebugConsole()
+		return o, nil
+	case "m":
+		// Toggle scrollback memory statistics
+		o.ShowScrollbackStats = !o.ShowScrollbackStats
+		if o.ShowScrollbackStats {
+			o.ShowNotification("Scrollback Memory: ON", "info", config.NotificationDuration)
+		} else {
+			o.ShowNotification("Scrollback Memory: OFF", "info", config.NotificationDuration)
+		}
+		return o, nil
 	case "k":
 		// Toggle showkeys overlay
 		o.ShowKeys = !o.ShowKeys
@@ -1250,7 +1701,8 @@ func HandleTapePrefixCommand(msg tea.KeyPressMsg, o *app.OS) (*app.OS, tea.Cmd)
 	}
 }
 
-// handleWorkspaceSwitch handles Alt+1-9 workspace switching (with macOS Option key support)
+// handleWorkspaceSwitch handles Alt+1-9 workspace switching and Alt+0 for the
+// previous workspace (with macOS Option key support)
 func handleWorkspaceSwitch(msg tea.KeyPressMsg, o *app.OS) bool {
 	keyStr := msg.String()
 
@@ -1258,7 +1710,11 @@ func handleWorkspaceSwitch(msg tea.KeyPressMsg, o *app.OS) bool {
 	if len(keyStr) > 0 {
 		firstRune := []rune(keyStr)[0]
 		if digit, ok := IsMacOSOptionKey(firstRune); ok {
-			o.SwitchToWorkspace(digit)
+			o.SelectWorkspace(digit)
+			return true
+		}
+		if firstRune == 'º' { // Option+0
+			o.SwitchToPreviousWorkspace()
 			return true
 		}
 	}
@@ -1266,31 +1722,34 @@ func handleWorkspaceSwitch(msg tea.KeyPressMsg, o *app.OS) bool {
 	// Check for standard Alt+digit keys
 	switch keyStr {
 	case "alt+1":
-		o.SwitchToWorkspace(1)
+		o.SelectWorkspace(1)
 		return true
 	case "alt+2":
-		o.SwitchToWorkspace(2)
+		o.SelectWorkspace(2)
 		return true
 	case "alt+3":
-		o.SwitchToWorkspace(3)
+		o.SelectWorkspace(3)
 		return true
 	case "alt+4":
-		o.SwitchToWorkspace(4)
+		o.SelectWorkspace(4)
 		return true
 	case "alt+5":
-		o.SwitchToWorkspace(5)
+		o.SelectWorkspace(5)
 		return true
 	case "alt+6":
-		o.SwitchToWorkspace(6)
+		o.SelectWorkspace(6)
 		return true
 	case "alt+7":
-		o.SwitchToWorkspace(7)
+		o.SelectWorkspace(7)
 		return true
 	case "alt+8":
-		o.SwitchToWorkspace(8)
+		o.SelectWorkspace(8)
 		return true
 	case "alt+9":
-		o.SwitchToWorkspace(9)
+		o.SelectWorkspace(9)
+		return true
+	case "alt+0":
+		o.SwitchToPreviousWorkspace()
 		return true
 	default:
 		return false
@@ -79,18 +79,64 @@ func sendMouseWheelToWindow(win *terminal.Window, event uv.MouseWheelEvent) {
 	}
 }
 
+// forwardMouse reports whether a mouse event with the given modifiers goes to
+// the window's application. Holding the configured override modifier keeps
+// the event in tuios, e.g. to select text while a mouse app runs.
+func forwardMouse(win *terminal.Window, mod tea.KeyMod) bool {
+	return win != nil && win.WantsMouse() && !overrideHeld(mod)
+}
+
+// overrideHeld reports whether the configured mouse override modifier is
+// among the given modifiers.
+func overrideHeld(mod tea.KeyMod) bool {
+	switch config.MouseOverrideModifier {
+	case "shift":
+		return mod&tea.ModShift != 0
+	case "alt":
+		return mod&tea.ModAlt != 0
+	case "ctrl":
+		return mod&tea.ModCtrl != 0
+	}
+	return false
+}
+
+// focusedPaneAt returns the terminal of the focused window at screen
+// position (x, y), which is the pane under it when the window is split, and
+// the position relative to that terminal. It returns nil outside the
+// window's content area and on pane separators.
+func focusedPaneAt(o *app.OS, x, y int) (*terminal.Window, int, int) {
+	window := o.GetFocusedWindow()
+	if window == nil {
+		return nil, 0, 0
+	}
+	// Convert to terminal-relative coordinates (0-based), accounting for the border
+	termX := x - window.X - 1
+	termY := y - window.Y - 1
+	if termX < 0 || termY < 0 || termX >= window.Width-2 || termY >= window.Height-2 {
+		return nil, 0, 0
+	}
+	return window.PaneAt(termX, termY)
+}
+
 // handleMouseClick handles mouse click events
 func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	mouse := msg.Mouse()
 	X := mouse.X
 	Y := mouse.Y
+	clearOverrideSelection(o)
 
 	// Check if click is in the sidebar area
 	if o.SidebarVisible {
 		sidebarWidth := o.GetSidebarWidth()
-		if X < sidebarWidth {
+		if o.ScreenX(X) < sidebarWidth {
 			// Click is within sidebar - check for window item click
-			windowIdx := o.FindSidebarItemClicked(X, Y)
+			windowIdx := o.FindSidebarItemClicked(o.ScreenX(X), Y)
+			if windowIdx >= 0 && windowIdx < len(o.Windows) && o.SidebarPicking() {
+				// Picking a window to merge or group with the focused window
+				o.SidebarSelectedIndex = windowIdx
+				o.SidebarConfirmSelection()
+				return o, nil
+			}
 			if windowIdx >= 0 && windowIdx < len(o.Windows) {
 				clickedWindow := o.Windows[windowIdx]
 				// Switch to window's workspace if different
@@ -118,7 +164,7 @@ func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	}
 
 	// Check if click is in the dock area (always reserved)
-	if ((config.DockbarPosition == "bottom") && (Y >= o.Height-config.DockHeight)) || ((config.DockbarPosition == "top") && (Y <= config.DockHeight)) {
+	if ((config.DockbarPosition == "bottom") && (Y >= o.Height-o.DockHeight())) || ((config.DockbarPosition == "top") && (Y <= o.DockHeight())) {
 		// Handle dock click only if there are minimized windows
 		if o.HasMinimizedWindows() {
 			dockIndex := findDockItemClicked(X, Y, o)
@@ -128,6 +174,9 @@ func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 				if o.AutoTiling {
 					o.TileAllWindows()
 				}
+			} else if o.IsDockOverflowAt(X, Y) {
+				// The "..." after the dock items lists every minimized window
+				o.ToggleMinimizedMenu()
 			}
 		}
 		return o, nil
@@ -136,19 +185,67 @@ func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	// Fast hit testing - find which window was clicked without expensive canvas generation
 	clickedWindowIndex := findClickedWindow(X, Y, o)
 
+	// Dragging the scrollbar of a scrolled back window seeks through its
+	// scrollback
+	if clickedWindowIndex != -1 && mouse.Button == tea.MouseLeft && o.OnScrollbar(o.Windows[clickedWindowIndex], X, Y) {
+		o.FocusWindow(clickedWindowIndex)
+		o.ScrollbarDrag = o.Windows[clickedWindowIndex]
+		o.SeekScrollbar(o.ScrollbarDrag, Y)
+		return o, nil
+	}
+
+	// Clicking a tab in a tabbed window's tab strip makes it the active tab
+	if clickedWindowIndex != -1 {
+		clickedWindow := o.Windows[clickedWindowIndex]
+		if clickedWindow.HasTabs() && Y == clickedWindow.Y+1 && X > clickedWindow.X && X < clickedWindow.X+clickedWindow.Width-1 {
+			o.SelectTabAt(clickedWindowIndex, X-clickedWindow.X-1)
+			o.FocusWindow(clickedWindowIndex)
+			return o, nil
+		}
+	}
+
+	// Clicking a pane of a split window makes it the active pane
+	if clickedWindowIndex != -1 {
+		clickedWindow := o.Windows[clickedWindowIndex]
+		clickedWindow.FocusPaneAt(X-clickedWindow.X-1, Y-clickedWindow.Y-1)
+	}
+
+	// A middle click pastes the text last selected with the mouse, unless the
+	// application under it reads the mouse
+	if clickedWindowIndex != -1 && o.Mode == app.TerminalMode && mouse.Button == tea.MouseMiddle && config.MiddleClickPaste && o.PrimarySelection != "" {
+		frame := o.Windows[clickedWindowIndex]
+		if x, y := X-frame.X-1, Y-frame.Y-1; isInTerminalContent(x, y, frame) {
+			if pane, _, _ := frame.PaneAt(x, y); pane != nil && pane.Terminal != nil && !forwardMouse(pane, mouse.Mod) {
+				o.FocusWindow(clickedWindowIndex)
+				return o, o.Paste(pane, o.PrimarySelection)
+			}
+		}
+	}
+
+	// Dragging with the override modifier held selects text in the window,
+	// whether or not its application reads the mouse; Alt selects a block
+	block := mouse.Mod&tea.ModAlt != 0
+	if clickedWindowIndex != -1 && o.Mode == app.TerminalMode && mouse.Button == tea.MouseLeft && (overrideHeld(mouse.Mod) || block) {
+		frame := o.Windows[clickedWindowIndex]
+		inCopyMode := frame.CopyMode != nil && frame.CopyMode.Active
+		if x, y := X-frame.X-1, Y-frame.Y-1; isInTerminalContent(x, y, frame) && !inCopyMode {
+			if pane, termX, termY := frame.PaneAt(x, y); pane != nil && pane.Terminal != nil {
+				o.FocusWindow(clickedWindowIndex)
+				startOverrideSelection(o, pane, termX, termY, block)
+				return o, nil
+			}
+		}
+	}
+
 	// Forward mouse events to terminal if in terminal mode and window has mouse tracking
 	if clickedWindowIndex != -1 && o.Mode == app.TerminalMode {
-		clickedWindow := o.Windows[clickedWindowIndex]
-		hasMouseMode := clickedWindow.Terminal != nil && clickedWindow.Terminal.HasMouseMode()
+		frame := o.Windows[clickedWindowIndex]
+		clickedWindow, termX, termY := frame.PaneAt(X-frame.X-1, Y-frame.Y-1)
 
 		// Forward mouse if alt screen or has mouse mode enabled (e.g., restored daemon session)
-		shouldForward := clickedWindow.IsAltScreen || hasMouseMode
-		if shouldForward && clickedWindow.Terminal != nil {
-			// Convert to terminal-relative coordinates (0-based)
-			termX := X - clickedWindow.X - 1 // Account for left border
-			termY := Y - clickedWindow.Y - 1 // Account for top border
+		if forwardMouse(clickedWindow, mouse.Mod) {
 			// Check if click is within terminal content area
-			if termX >= 0 && termY >= 0 && termX < clickedWindow.Width-2 && termY < clickedWindow.Height-2 {
+			if termX >= 0 && termY >= 0 && termX < frame.Width-2 && termY < frame.Height-2 {
 				// Focus the window first so subsequent events work
 				o.FocusWindow(clickedWindowIndex)
 
@@ -194,7 +291,7 @@ func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 
 		// cross (close button) - rightmost area
 		if mouse.Button == tea.MouseLeft && X >= leftMost-4 && X <= leftMost-1 && Y == titleBarY {
-			o.DeleteWindow(clickedWindowIndex)
+			o.CloseWindowAndGroup(clickedWindowIndex)
 			o.InteractionMode = false
 			return o, nil
 		}
@@ -232,7 +329,7 @@ func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 			terminalY := Y - clickedWindow.Y // Fixed: Y coordinate relative to window
 			if terminalX >= 0 && terminalY >= 0 && terminalX < clickedWindow.Width-2 && terminalY < clickedWindow.Height-2 {
 				// Start drag for visual selection
-				HandleCopyModeMouseDrag(clickedWindow.CopyMode, clickedWindow, X, Y)
+				HandleCopyModeMouseDrag(clickedWindow.CopyMode, clickedWindow, X, Y, block)
 				o.Dragging = true
 				o.DraggedWindowIndex = clickedWindowIndex
 				o.InteractionMode = true
@@ -258,40 +355,30 @@ func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 
 	switch mouse.Button {
 	case tea.MouseRight:
-		// Already in interaction mode, now set resize-specific flags
-		o.Resizing = true
-		o.Windows[clickedWindowIndex].IsBeingManipulated = true
-		o.ResizeStartX = mouse.X
-		o.ResizeStartY = mouse.Y
-		// Save state for resize calculations (avoid mutex copying)
-		o.PreResizeState = terminal.Window{
-			Title:  clickedWindow.Title,
-			Width:  clickedWindow.Width,
-			Height: clickedWindow.Height,
-			X:      clickedWindow.X,
-			Y:      clickedWindow.Y,
-			Z:      clickedWindow.Z,
-			ID:     clickedWindow.ID,
-		}
 		minX := clickedWindow.X
 		midX := clickedWindow.X + (clickedWindow.Width / 2)
 
 		minY := clickedWindow.Y
 		midY := clickedWindow.Y + (clickedWindow.Height / 2)
 
+		corner := app.BottomRight
 		if mouse.X < midX && mouse.X >= minX {
-			o.ResizeCorner = app.BottomLeft
+			corner = app.BottomLeft
 			if mouse.Y < midY && mouse.Y >= minY {
-				o.ResizeCorner = app.TopLeft
-			}
-		} else {
-			o.ResizeCorner = app.BottomRight
-			if mouse.Y < midY && mouse.Y >= minY {
-				o.ResizeCorner = app.TopRight
+				corner = app.TopLeft
 			}
+		} else if mouse.Y < midY && mouse.Y >= minY {
+			corner = app.TopRight
 		}
+		startResize(o, clickedWindowIndex, corner, mouse.X, mouse.Y)
 
 	case tea.MouseLeft:
+		// Dragging a border or corner resizes the window from it
+		if corner, ok := borderResizeZone(clickedWindow, X, Y); ok {
+			startResize(o, clickedWindowIndex, corner, mouse.X, mouse.Y)
+			return o, nil
+		}
+
 		// Check if we're in selection mode
 		if o.SelectionMode {
 			// Calculate terminal coordinates relative to window content
@@ -326,6 +413,9 @@ func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 					clickedWindow.SelectionStart.Y = terminalY
 					clickedWindow.SelectionEnd = clickedWindow.SelectionStart
 					clickedWindow.SelectionMode = 0 // Character mode
+					if block {
+						clickedWindow.SelectionMode = 3 // Block mode
+					}
 				case 2:
 					// Double click - word selection
 					selectWord(clickedWindow, terminalX, terminalY, o)
@@ -352,7 +442,7 @@ func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 
 		// In tiling mode, complete ALL pending animations to avoid state conflicts
 		// This ensures all windows are in their final positions before starting a new drag
-		if o.AutoTiling {
+		if o.AutoTiling && !clickedWindow.Floating {
 			o.CompleteAllAnimations()
 
 			// Store current position (after completing all animations) for tiling mode swaps
@@ -365,6 +455,89 @@ func handleMouseClick(msg tea.MouseClickMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	return o, nil
 }
 
+// resistScreenEdges keeps a dragged window at the screen edge or the dock
+// boundary it is being dragged across, until it is dragged
+// config.EdgeResistance cells past it. Only edges the window is still inside
+// resist, so a window pushed past an edge moves freely until it is back.
+func resistScreenEdges(o *app.OS, w *terminal.Window, x, y int) (int, int) {
+	r := config.EdgeResistance
+	if r <= 0 {
+		return x, y
+	}
+	top := o.GetTopMargin()
+	bottom := top + o.GetUsableHeight()
+	if w.X >= 0 && x < 0 && x > -r {
+		x = 0
+	}
+	if right := o.Width - w.Width; w.X <= right && x > right && x < right+r {
+		x = right
+	}
+	if w.Y >= top && y < top && y > top-r {
+		y = top
+	}
+	if floor := bottom - w.Height; w.Y <= floor && y > floor && y < floor+r {
+		y = floor
+	}
+	return x, y
+}
+
+// borderResizeZone returns the corner or edge of a window's border at screen
+// position (x, y), from which dragging resizes the window. The cells next to
+// a corner along both of its edges resize from the corner too, so corners
+// are easy to hit. The top border is the title bar, which only resizes from
+// its corners.
+func borderResizeZone(w *terminal.Window, x, y int) (app.ResizeCorner, bool) {
+	left, right := w.X, w.X+w.Width-1
+	top, bottom := w.Y, w.Y+w.Height-1
+	if x < left || x > right || y < top || y > bottom {
+		return 0, false
+	}
+	const cornerSize = 2
+	nearLeft, nearRight := x < left+cornerSize, x > right-cornerSize
+	nearTop, nearBottom := y < top+cornerSize, y > bottom-cornerSize
+
+	onVertical := x == left || x == right
+	switch {
+	case (onVertical || y == top) && nearTop && nearLeft:
+		return app.TopLeft, true
+	case (onVertical || y == top) && nearTop && nearRight:
+		return app.TopRight, true
+	case (onVertical || y == bottom) && nearBottom && nearLeft:
+		return app.BottomLeft, true
+	case (onVertical || y == bottom) && nearBottom && nearRight:
+		return app.BottomRight, true
+	case x == left:
+		return app.Left, true
+	case x == right:
+		return app.Right, true
+	case y == bottom:
+		return app.Bottom, true
+	}
+	return 0, false
+}
+
+// startResize starts resizing window i with the mouse from a corner or edge,
+// with the mouse at (x, y).
+func startResize(o *app.OS, i int, corner app.ResizeCorner, x, y int) {
+	w := o.Windows[i]
+	// Already in interaction mode, now set resize-specific flags
+	o.Resizing = true
+	o.ResizeCorner = corner
+	w.IsBeingManipulated = true
+	o.ResizeStartX = x
+	o.ResizeStartY = y
+	// Save state for resize calculations (avoid mutex copying)
+	o.PreResizeState = terminal.Window{
+		Title:  w.Title,
+		Width:  w.Width,
+		Height: w.Height,
+		X:      w.X,
+		Y:      w.Y,
+		Z:      w.Z,
+		ID:     w.ID,
+	}
+}
+
 // handleMouseMotion handles mouse motion events
 func handleMouseMotion(msg tea.MouseMotionMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	mouse := msg.Mouse()
@@ -375,7 +548,7 @@ func handleMouseMotion(msg tea.MouseMotionMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	o.LastMouseY = mouse.Y
 
 	// Check for sidebar hover trigger (left edge when sidebar hidden)
-	if o.IsSidebarHoverZone(mouse.X, mouse.Y) {
+	if o.IsSidebarHoverZone(o.ScreenX(mouse.X), mouse.Y) {
 		if !o.SidebarHoverTrigger {
 			o.SidebarHoverTrigger = true
 			o.SidebarVisible = true
@@ -385,10 +558,10 @@ func handleMouseMotion(msg tea.MouseMotionMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		}
 	} else if o.SidebarVisible {
 		sidebarWidth := o.GetSidebarWidth()
-		if mouse.X < sidebarWidth {
+		if screenX := o.ScreenX(mouse.X); screenX < sidebarWidth {
 			// Mouse is inside sidebar - keep it focused
 			o.SidebarFocused = true
-		} else if mouse.X > sidebarWidth+2 {
+		} else if screenX > sidebarWidth+2 {
 			// Mouse left sidebar area - close if hover-triggered
 			if o.SidebarHoverTrigger {
 				o.SidebarHoverTrigger = false
@@ -401,30 +574,31 @@ func handleMouseMotion(msg tea.MouseMotionMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		}
 	}
 
+	if o.ScrollbarDrag != nil {
+		o.SeekScrollbar(o.ScrollbarDrag, mouse.Y)
+		return o, nil
+	}
+	if o.MouseSelection != nil && o.MouseSelection.IsSelecting {
+		extendOverrideSelection(o, mouse.X, mouse.Y)
+		return o, nil
+	}
+
 	// Forward mouse motion to terminal if in terminal mode and window has mouse tracking
 	if o.Mode == app.TerminalMode {
-		focusedWindow := o.GetFocusedWindow()
-		if focusedWindow != nil && focusedWindow.Terminal != nil {
-			hasMouseMode := focusedWindow.Terminal.HasMouseMode()
-			shouldForward := focusedWindow.IsAltScreen || hasMouseMode
-
-			if shouldForward {
-				// Convert to terminal-relative coordinates (0-based)
-				termX := mouse.X - focusedWindow.X - 1 // Account for left border
-				termY := mouse.Y - focusedWindow.Y - 1 // Account for top border
-				// Check if motion is within terminal content area
-				if termX >= 0 && termY >= 0 && termX < focusedWindow.Width-2 && termY < focusedWindow.Height-2 {
-					// Create adjusted mouse event with terminal-relative coordinates
-					adjustedMouse := uv.MouseMotionEvent{
-						X:      termX,
-						Y:      termY,
-						Button: uv.MouseButton(mouse.Button),
-						Mod:    uv.KeyMod(mouse.Mod),
-					}
-					// Send to the terminal (uses PTY for daemon windows)
-					sendMouseMotionToWindow(focusedWindow, adjustedMouse)
-					return o, nil
+		// Motion is only forwarded within the active pane
+		focusedWindow, termX, termY := focusedPaneAt(o, mouse.X, mouse.Y)
+		if focusedWindow != nil && focusedWindow == o.GetFocusedPane() && focusedWindow.Terminal != nil {
+			if forwardMouse(focusedWindow, mouse.Mod) {
+				// Create adjusted mouse event with terminal-relative coordinates
+				adjustedMouse := uv.MouseMotionEvent{
+					X:      termX,
+					Y:      termY,
+					Button: uv.MouseButton(mouse.Button),
+					Mod:    uv.KeyMod(mouse.Mod),
 				}
+				// Send to the terminal (uses PTY for daemon windows)
+				sendMouseMotionToWindow(focusedWindow, adjustedMouse)
+				return o, nil
 			}
 		}
 	}
@@ -477,17 +651,19 @@ func handleMouseMotion(msg tea.MouseMotionMsg, o *app.OS) (*app.OS, tea.Cmd) {
 
 		// Minimal bounds to prevent rendering issues and windows disappearing behind dock
 		// Keep at least some of the window visible (title bar area)
-		minVisibleX := 20 // Keep at least 20px visible on the right
-		minVisibleY := 3  // Keep at least title bar visible at bottom
+		minVisibleX := config.VisibleColumns(focusedWindow.Width) // Keep some of the title bar visible
+		minVisibleY := 3                                          // Keep at least title bar visible at bottom
 
-		// Prevent window from going too far left (causes ANSI rendering issues)
-		if newX < -(focusedWindow.Width - minVisibleX) {
-			newX = -(focusedWindow.Width - minVisibleX)
-		}
+		if minVisibleX > 0 {
+			// Prevent window from going too far left (causes ANSI rendering issues)
+			if newX < -(focusedWindow.Width - minVisibleX) {
+				newX = -(focusedWindow.Width - minVisibleX)
+			}
 
-		// Prevent window from going too far right
-		if newX > o.Width-minVisibleX {
-			newX = o.Width - minVisibleX
+			// Prevent window from going too far right
+			if newX > o.Width-minVisibleX {
+				newX = o.Width - minVisibleX
+			}
 		}
 
 		// Prevent window from going too far up
@@ -502,6 +678,7 @@ func handleMouseMotion(msg tea.MouseMotionMsg, o *app.OS) (*app.OS, tea.Cmd) {
 			newY = maxY
 		}
 
+		newX, newY = resistScreenEdges(o, focusedWindow, newX, newY)
 		focusedWindow.X = newX
 		focusedWindow.Y = newY
 		focusedWindow.MarkPositionDirty()
@@ -517,93 +694,74 @@ func handleMouseMotion(msg tea.MouseMotionMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		newWidth := focusedWindow.Width
 		newHeight := focusedWindow.Height
 
-		switch o.ResizeCorner {
-		case app.TopLeft:
+		movesLeft, movesRight := o.ResizeCorner.MovesLeft(), o.ResizeCorner.MovesRight()
+		movesTop, movesBottom := o.ResizeCorner.MovesTop(), o.ResizeCorner.MovesBottom()
+		if movesLeft {
 			newX = o.PreResizeState.X + xOffset
-			newY = o.PreResizeState.Y + yOffset
 			newWidth = o.PreResizeState.Width - xOffset
-			newHeight = o.PreResizeState.Height - yOffset
-		case app.TopRight:
-			newY = o.PreResizeState.Y + yOffset
+		}
+		if movesRight {
 			newWidth = o.PreResizeState.Width + xOffset
+		}
+		if movesTop {
+			newY = o.PreResizeState.Y + yOffset
 			newHeight = o.PreResizeState.Height - yOffset
-		case app.BottomLeft:
-			newX = o.PreResizeState.X + xOffset
-			newWidth = o.PreResizeState.Width - xOffset
-			newHeight = o.PreResizeState.Height + yOffset
-		case app.BottomRight:
-			newWidth = o.PreResizeState.Width + xOffset
+		}
+		if movesBottom {
 			newHeight = o.PreResizeState.Height + yOffset
 		}
 
 		// Apply minimum size constraints
-		if newWidth < config.DefaultWindowWidth {
-			newWidth = config.DefaultWindowWidth
-			if o.ResizeCorner == app.TopLeft || o.ResizeCorner == app.BottomLeft {
-				newX = o.PreResizeState.X + o.PreResizeState.Width - config.DefaultWindowWidth
+		minWidth, minHeight := focusedWindow.MinimumSize()
+		if newWidth < minWidth {
+			newWidth = minWidth
+			if movesLeft {
+				newX = o.PreResizeState.X + o.PreResizeState.Width - minWidth
 			}
 		}
-		if newHeight < config.DefaultWindowHeight {
-			newHeight = config.DefaultWindowHeight
-			if o.ResizeCorner == app.TopLeft || o.ResizeCorner == app.TopRight {
-				newY = o.PreResizeState.Y + o.PreResizeState.Height - config.DefaultWindowHeight
+		if newHeight < minHeight {
+			newHeight = minHeight
+			if movesTop {
+				newY = o.PreResizeState.Y + o.PreResizeState.Height - minHeight
 			}
 		}
 
-		// Apply viewport bounds checking to prevent windows from going off-screen
-		// This is consistent with drag bounds checking and prevents layout issues
+		// Apply viewport bounds checking to the edges being dragged, which stop
+		// at the screen edges and the dock. The other edges stay where they
+		// are, so a window partly off screen keeps its place while it is
+		// resized from an edge in view
 
 		// Left edge: prevent negative X
-		if newX < 0 {
-			// If resizing from left, adjust width to compensate
-			if o.ResizeCorner == app.TopLeft || o.ResizeCorner == app.BottomLeft {
-				newWidth += newX // Add the negative offset back to width
-			}
+		if movesLeft && newX < 0 {
+			newWidth += newX // Add the negative offset back to width
 			newX = 0
 		}
 
 		// Top edge: prevent window from moving into dock area or above screen
 		topMargin := o.GetTopMargin()
-		if newY < topMargin {
-			// If resizing from top, adjust height to compensate
-			if o.ResizeCorner == app.TopLeft || o.ResizeCorner == app.TopRight {
-				newHeight += newY - topMargin // Add the offset back to height
-			}
+		if movesTop && newY < topMargin {
+			newHeight += newY - topMargin // Add the offset back to height
 			newY = topMargin
 		}
 
 		// Right edge: prevent window from exceeding viewport width
-		if newX+newWidth > o.Width {
-			if o.ResizeCorner == app.TopRight || o.ResizeCorner == app.BottomRight {
-				// Resizing from right edge - constrain width
-				newWidth = o.Width - newX
-			} else {
-				// Resizing from left edge - constrain X position
-				newX = o.Width - newWidth
-			}
+		if movesRight && newX+newWidth > o.Width {
+			newWidth = o.Width - newX
 		}
 
 		// Bottom edge: prevent window from exceeding usable height (dock area)
 		// maxY is the absolute bottom boundary accounting for dock position
 		maxY := topMargin + o.GetUsableHeight()
-		if newY+newHeight > maxY {
-			if o.ResizeCorner == app.BottomLeft || o.ResizeCorner == app.BottomRight {
-				// Resizing from bottom edge - constrain height
-				newHeight = maxY - newY
-			} else {
-				// Resizing from top edge - constrain Y position
-				newY = maxY - newHeight
-			}
+		if movesBottom && newY+newHeight > maxY {
+			newHeight = maxY - newY
 		}
 
 		// Final safety check: ensure dimensions stay within bounds after all adjustments
-		newWidth = max(newWidth, config.DefaultWindowWidth)
-		newHeight = max(newHeight, config.DefaultWindowHeight)
-		newWidth = min(newWidth, o.Width-newX)
-		newHeight = min(newHeight, maxY-newY)
+		newWidth = max(newWidth, minWidth)
+		newHeight = max(newHeight, minHeight)
 
 		// In tiling mode, block resizing edges at screen boundaries
-		if o.AutoTiling {
+		if o.AutoTiling && !focusedWindow.Floating {
 			const edgeTolerance = 2 // Small tolerance for detecting screen edges
 
 			// Check which edges are at screen boundaries
@@ -613,39 +771,19 @@ func handleMouseMotion(msg tea.MouseMotionMsg, o *app.OS) (*app.OS, tea.Cmd) {
 			atBottomEdge := (focusedWindow.Y + focusedWindow.Height) >= (maxY - edgeTolerance)
 
 			// Block resizing edges that are at screen boundaries
-			switch o.ResizeCorner {
-			case app.TopLeft:
-				if atLeftEdge {
-					newX = focusedWindow.X
-					newWidth = focusedWindow.Width
-				}
-				if atTopEdge {
-					newY = focusedWindow.Y
-					newHeight = focusedWindow.Height
-				}
-			case app.TopRight:
-				if atRightEdge {
-					newWidth = focusedWindow.Width
-				}
-				if atTopEdge {
-					newY = focusedWindow.Y
-					newHeight = focusedWindow.Height
-				}
-			case app.BottomLeft:
-				if atLeftEdge {
-					newX = focusedWindow.X
-					newWidth = focusedWindow.Width
-				}
-				if atBottomEdge {
-					newHeight = focusedWindow.Height
-				}
-			case app.BottomRight:
-				if atRightEdge {
-					newWidth = focusedWindow.Width
-				}
-				if atBottomEdge {
-					newHeight = focusedWindow.Height
-				}
+			if movesLeft && atLeftEdge {
+				newX = focusedWindow.X
+				newWidth = focusedWindow.Width
+			}
+			if movesRight && atRightEdge {
+				newWidth = focusedWindow.Width
+			}
+			if movesTop && atTopEdge {
+				newY = focusedWindow.Y
+				newHeight = focusedWindow.Height
+			}
+			if movesBottom && atBottomEdge {
+				newHeight = focusedWindow.Height
 			}
 
 			// In tiling mode, update visual state but defer PTY resize until drag completes
@@ -669,31 +807,31 @@ func handleMouseMotion(msg tea.MouseMotionMsg, o *app.OS) (*app.OS, tea.Cmd) {
 
 // handleMouseRelease handles mouse release events
 func handleMouseRelease(msg tea.MouseReleaseMsg, o *app.OS) (*app.OS, tea.Cmd) {
+	if o.ScrollbarDrag != nil {
+		o.ScrollbarDrag = nil
+		return o, nil
+	}
+	if o.MouseSelection != nil && o.MouseSelection.IsSelecting {
+		return o, finishOverrideSelection(o)
+	}
+
 	// Forward mouse release to terminal if in terminal mode and window has mouse tracking
 	if o.Mode == app.TerminalMode {
-		focusedWindow := o.GetFocusedWindow()
-		if focusedWindow != nil && focusedWindow.Terminal != nil {
-			hasMouseMode := focusedWindow.Terminal.HasMouseMode()
-			shouldForward := focusedWindow.IsAltScreen || hasMouseMode
-
-			if shouldForward {
-				mouse := msg.Mouse()
-				// Convert to terminal-relative coordinates (0-based)
-				termX := mouse.X - focusedWindow.X - 1 // Account for left border
-				termY := mouse.Y - focusedWindow.Y - 1 // Account for top border
-				// Check if release is within terminal content area
-				if termX >= 0 && termY >= 0 && termX < focusedWindow.Width-2 && termY < focusedWindow.Height-2 {
-					// Create adjusted mouse event with terminal-relative coordinates
-					adjustedMouse := uv.MouseReleaseEvent{
-						X:      termX,
-						Y:      termY,
-						Button: uv.MouseButton(mouse.Button),
-						Mod:    uv.KeyMod(mouse.Mod),
-					}
-					// Send to the terminal (uses PTY for daemon windows)
-					sendMouseReleaseToWindow(focusedWindow, adjustedMouse)
-					return o, nil
+		mouse := msg.Mouse()
+		// Releases are only forwarded within the active pane
+		focusedWindow, termX, termY := focusedPaneAt(o, mouse.X, mouse.Y)
+		if focusedWindow != nil && focusedWindow == o.GetFocusedPane() && focusedWindow.Terminal != nil {
+			if forwardMouse(focusedWindow, mouse.Mod) {
+				// Create adjusted mouse event with terminal-relative coordinates
+				adjustedMouse := uv.MouseReleaseEvent{
+					X:      termX,
+					Y:      termY,
+					Button: uv.MouseButton(mouse.Button),
+					Mod:    uv.KeyMod(mouse.Mod),
 				}
+				// Send to the terminal (uses PTY for daemon windows)
+				sendMouseReleaseToWindow(focusedWindow, adjustedMouse)
+				return o, nil
 			}
 		}
 	}
@@ -706,6 +844,12 @@ func handleMouseRelease(msg tea.MouseReleaseMsg, o *app.OS) (*app.OS, tea.Cmd) {
 			o.Dragging = false
 			o.DraggedWindowIndex = -1
 			o.InteractionMode = false
+			cm := draggedWindow.CopyMode
+			if cm.Visual() && cm.VisualStart != cm.VisualEnd {
+				if text := extractVisualText(cm, draggedWindow); text != "" {
+					return o, o.MouseSelected(text)
+				}
+			}
 			return o, nil
 		}
 	}
@@ -716,17 +860,20 @@ func handleMouseRelease(msg tea.MouseReleaseMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		if focusedWindow != nil && focusedWindow.IsSelecting {
 			// Extract selected text from terminal
 			selectedText := extractSelectedText(focusedWindow, o)
+			focusedWindow.IsSelecting = false
 			if selectedText != "" {
 				focusedWindow.SelectedText = selectedText
+				if cmd := o.MouseSelected(selectedText); cmd != nil {
+					return o, cmd
+				}
 				o.ShowNotification(fmt.Sprintf("Selected %d chars - Press 'c' to copy", len(selectedText)), "success", config.NotificationDuration)
 			}
-			focusedWindow.IsSelecting = false
 			return o, nil
 		}
 	}
 
 	// Handle window drop in tiling mode
-	if o.Dragging && o.AutoTiling && o.DraggedWindowIndex >= 0 && o.DraggedWindowIndex < len(o.Windows) {
+	if o.Dragging && o.AutoTiling && o.DraggedWindowIndex >= 0 && o.DraggedWindowIndex < len(o.Windows) && !o.Windows[o.DraggedWindowIndex].Floating {
 		mouse := msg.Mouse()
 
 		// Calculate drag distance to determine if this was actually a drag or just a click
@@ -740,7 +887,7 @@ func handleMouseRelease(msg tea.MouseReleaseMsg, o *app.OS) (*app.OS, tea.Cmd) {
 			// Find which window is under the cursor (excluding the dragged window)
 			targetWindowIndex := -1
 			for i := range o.Windows {
-				if i == o.DraggedWindowIndex || o.Windows[i].Minimized || o.Windows[i].Minimizing {
+				if i == o.DraggedWindowIndex || o.Windows[i].Minimized || o.Windows[i].Minimizing || o.Windows[i].Floating {
 					continue
 				}
 				// Only consider windows in current workspace
@@ -784,8 +931,8 @@ func handleMouseRelease(msg tea.MouseReleaseMsg, o *app.OS) (*app.OS, tea.Cmd) {
 		o.DraggedWindowIndex = -1
 	}
 
-	// Handle window edge snapping in floating mode (non-tiling)
-	if o.Dragging && !o.AutoTiling && o.DraggedWindowIndex >= 0 && o.DraggedWindowIndex < len(o.Windows) {
+	// Handle window edge snapping in floating mode (non-tiling) and for floating windows
+	if o.Dragging && o.DraggedWindowIndex >= 0 && o.DraggedWindowIndex < len(o.Windows) && (!o.AutoTiling || o.Windows[o.DraggedWindowIndex].Floating) {
 		mouse := msg.Mouse()
 		dragDistance := abs(mouse.X-o.DragStartX) + abs(mouse.Y-o.DragStartY)
 		const dragThreshold = 5
@@ -902,6 +1049,9 @@ func handleMouseRelease(msg tea.MouseReleaseMsg, o *app.OS) (*app.OS, tea.Cmd) {
 			o.InteractionMode = false
 		}
 
+		// Remember where a window started with a command was left
+		o.RememberWindowGeometry(o.GetFocusedWindow())
+
 		// Sync state to daemon after drag/resize completes
 		// This ensures window positions persist across reconnects
 		o.SyncStateToDaemon()
@@ -966,36 +1116,27 @@ func handleMouseWheel(msg tea.MouseWheelMsg, o *app.OS) (*app.OS, tea.Cmd) {
 	// Forward mouse wheel to terminal if in terminal mode and window has mouse tracking
 	// This allows applications like vim, less, htop to handle their own scrolling
 	if o.Mode == app.TerminalMode {
-		focusedWindow := o.GetFocusedWindow()
+		mouse := msg.Mouse()
+		focusedWindow, termX, termY := focusedPaneAt(o, mouse.X, mouse.Y)
 		if focusedWindow != nil && focusedWindow.Terminal != nil {
-			hasMouseMode := focusedWindow.Terminal.HasMouseMode()
-			shouldForward := focusedWindow.IsAltScreen || hasMouseMode
-
-			if shouldForward {
-				mouse := msg.Mouse()
-				// Convert to terminal-relative coordinates (0-based)
-				termX := mouse.X - focusedWindow.X - 1 // Account for left border
-				termY := mouse.Y - focusedWindow.Y - 1 // Account for top border
-				// Check if wheel is within terminal content area
-				if termX >= 0 && termY >= 0 && termX < focusedWindow.Width-2 && termY < focusedWindow.Height-2 {
-					// Create adjusted mouse event with terminal-relative coordinates
-					adjustedMouse := uv.MouseWheelEvent{
-						X:      termX,
-						Y:      termY,
-						Button: uv.MouseButton(mouse.Button),
-						Mod:    uv.KeyMod(mouse.Mod),
-					}
-					// Send to the terminal (uses PTY for daemon windows)
-					sendMouseWheelToWindow(focusedWindow, adjustedMouse)
-					return o, nil
+			if forwardMouse(focusedWindow, mouse.Mod) {
+				// Create adjusted mouse event with terminal-relative coordinates
+				adjustedMouse := uv.MouseWheelEvent{
+					X:      termX,
+					Y:      termY,
+					Button: uv.MouseButton(mouse.Button),
+					Mod:    uv.KeyMod(mouse.Mod),
 				}
+				// Send to the terminal (uses PTY for daemon windows)
+				sendMouseWheelToWindow(focusedWindow, adjustedMouse)
+				return o, nil
 			}
 		}
 	}
 
 	// Handle scrollback in terminal mode or selection mode
 	if o.Mode == app.TerminalMode || o.SelectionMode {
-		focusedWindow := o.GetFocusedWindow()
+		focusedWindow := o.GetFocusedPane()
 		if focusedWindow != nil {
 			switch msg.Button {
 			case tea.MouseWheelUp:
@@ -1076,9 +1217,9 @@ func findClickedWindow(x, y int, o *app.OS) int {
 		// Check if click is within window bounds
 		if x >= window.X && x < window.X+window.Width &&
 			y >= window.Y && y < window.Y+window.Height {
-			// This window contains the click - check if it's the topmost so far
-			if window.Z > topZ {
-				topZ = window.Z
+			// This window contains the click - check if it's drawn topmost so far
+			if z := app.LayerZ(window); z > topZ {
+				topZ = z
 				topWindow = i
 			}
 		}
@@ -1113,7 +1254,7 @@ func findDockItemClicked(x, y int, o *app.OS) int {
 		}
 
 		// Check if click is within this dock item
-		if x >= itemPos.StartX && x < itemPos.EndX && y == o.GetDockbarContentYPosition() {
+		if x >= itemPos.StartX && x < itemPos.EndX && y == o.DockRowY(itemPos.Row) {
 			// DEBUG: Log successful match
 			if os.Getenv("TUIOS_DEBUG_INTERNAL") == "1" {
 				if f, err := os.OpenFile("/tmp/tuios-dock-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600); err == nil {
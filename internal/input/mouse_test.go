@@ -1,9 +1,17 @@
 package input
 
 import (
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	tea "charm.land/bubbletea/v2"
+	"github.com/Gaurav-Gosain/tuios/internal/app"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
 )
 
 func TestIsInTerminalContent(t *testing.T) {
@@ -84,3 +92,370 @@ func TestIsInTerminalContent(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardMouse(t *testing.T) {
+	win := &terminal.Window{Terminal: vt.NewEmulator(80, 24)}
+	if forwardMouse(win, 0) {
+		t.Fatal("mouse forwarded to an app that did not request it")
+	}
+
+	// Apps on the alternate screen receive the mouse
+	win.IsAltScreen = true
+	if !forwardMouse(win, 0) {
+		t.Error("mouse not forwarded to an alternate screen app")
+	}
+
+	// Holding the override modifier keeps the event in tuios
+	if forwardMouse(win, tea.ModShift) {
+		t.Error("mouse forwarded while the override modifier is held")
+	}
+
+	// Capturing the mouse keeps all events in tuios
+	win.MouseCapture = true
+	if forwardMouse(win, 0) {
+		t.Error("mouse forwarded while captured by tuios")
+	}
+}
+
+func TestOverrideSelection(t *testing.T) {
+	m := app.NewOS(app.OSOptions{})
+	m.Width, m.Height = 120, 40
+	emu := vt.NewEmulator(38, 8)
+	_, _ = emu.Write([]byte("hello world"))
+	win := &terminal.Window{ID: "window-mouse-00", X: 10, Y: 5, Width: 40, Height: 10, Workspace: 1, Terminal: emu, IsAltScreen: true}
+	m.Windows = []*terminal.Window{win}
+	m.FocusedWindow = 0
+	m.Mode = app.TerminalMode
+
+	// Shift-dragging over "hello" selects it although the app reads the mouse
+	handleMouseClick(tea.MouseClickMsg{X: 11, Y: 6, Button: tea.MouseLeft, Mod: tea.ModShift}, m)
+	if m.MouseSelection != win || !win.IsSelecting {
+		t.Fatal("override click did not start a selection")
+	}
+	handleMouseMotion(tea.MouseMotionMsg{X: 15, Y: 6, Button: tea.MouseLeft, Mod: tea.ModShift}, m)
+	_, cmd := handleMouseRelease(tea.MouseReleaseMsg{X: 15, Y: 6, Button: tea.MouseLeft, Mod: tea.ModShift}, m)
+	if win.SelectedText != "hello" || cmd == nil {
+		t.Fatalf("selected %q, want hello copied", win.SelectedText)
+	}
+	if m.Mode != app.TerminalMode {
+		t.Error("override selection left terminal mode")
+	}
+	if m.PrimarySelection != "hello" {
+		t.Errorf("primary selection = %q, want hello", m.PrimarySelection)
+	}
+
+	// The next click clears the highlight
+	handleMouseClick(tea.MouseClickMsg{X: 20, Y: 8, Button: tea.MouseLeft}, m)
+	if m.MouseSelection != nil || win.SelectedText != "" {
+		t.Error("selection kept after the next click")
+	}
+
+	// A click without a drag copies nothing
+	handleMouseClick(tea.MouseClickMsg{X: 20, Y: 8, Button: tea.MouseLeft, Mod: tea.ModShift}, m)
+	if _, cmd := handleMouseRelease(tea.MouseReleaseMsg{X: 20, Y: 8, Button: tea.MouseLeft, Mod: tea.ModShift}, m); cmd != nil || win.SelectedText != "" {
+		t.Error("click without a drag copied text")
+	}
+}
+
+func TestCopyOnSelect(t *testing.T) {
+	defer func() { config.CopyOnSelect = false }()
+	m := app.NewOS(app.OSOptions{})
+	m.Width, m.Height = 120, 40
+	emu := vt.NewEmulator(38, 8)
+	_, _ = emu.Write([]byte("hello world"))
+	win := &terminal.Window{ID: "window-copy-000", X: 10, Y: 5, Width: 40, Height: 10, Workspace: 1, Terminal: emu}
+	m.Windows = []*terminal.Window{win}
+	m.FocusedWindow = 0
+	m.SelectionMode = true
+
+	selectHello := func() tea.Cmd {
+		handleMouseClick(tea.MouseClickMsg{X: 11, Y: 6, Button: tea.MouseLeft}, m)
+		handleMouseMotion(tea.MouseMotionMsg{X: 15, Y: 6, Button: tea.MouseLeft}, m)
+		_, cmd := handleMouseRelease(tea.MouseReleaseMsg{X: 15, Y: 6, Button: tea.MouseLeft}, m)
+		return cmd
+	}
+
+	// Without copy_on_select the selection waits for a copy key
+	if cmd := selectHello(); cmd != nil || win.SelectedText != "hello" || m.PrimarySelection != "hello" {
+		t.Fatalf("expected hello selected but not copied, got %q", win.SelectedText)
+	}
+
+	config.CopyOnSelect = true
+	m.PrimarySelection = ""
+	win.LastClickTime = time.Time{}
+	msgs := cmdMessages(selectHello())
+	for _, want := range []tea.Msg{tea.SetClipboard("hello")(), tea.SetPrimaryClipboard("hello")()} {
+		if !slices.ContainsFunc(msgs, func(msg tea.Msg) bool { return reflect.DeepEqual(msg, want) }) {
+			t.Errorf("expected %#v among the messages of the selection, got %#v", want, msgs)
+		}
+	}
+}
+
+func TestMiddleClickPaste(t *testing.T) {
+	defer func() { config.MiddleClickPaste = false }()
+	m := app.NewOS(app.OSOptions{})
+	m.Width, m.Height = 120, 40
+	var pasted []byte
+	win := &terminal.Window{ID: "window-paste-00", X: 10, Y: 5, Width: 40, Height: 10, Workspace: 1, Terminal: vt.NewEmulator(38, 8), DaemonMode: true}
+	win.DaemonWriteFunc = func(data []byte) error {
+		pasted = append(pasted, data...)
+		return nil
+	}
+	m.Windows = []*terminal.Window{win}
+	m.FocusedWindow = 0
+	m.Mode = app.TerminalMode
+	m.PrimarySelection = "echo hi"
+	middleClick := tea.MouseClickMsg{X: 12, Y: 7, Button: tea.MouseMiddle}
+
+	handleMouseClick(middleClick, m)
+	if len(pasted) != 0 {
+		t.Fatalf("expected no paste with middle_click_paste off, got %q", pasted)
+	}
+
+	// Clicking a window otherwise leaves terminal mode
+	m.Mode = app.TerminalMode
+	config.MiddleClickPaste = true
+	handleMouseClick(middleClick, m)
+	if string(pasted) != "echo hi" {
+		t.Fatalf("expected the selection pasted, got %q", pasted)
+	}
+
+	// A multi-line selection asks first, like any paste into a shell
+	pasted = nil
+	m.PrimarySelection = "rm -rf build\nmake"
+	handleMouseClick(middleClick, m)
+	if len(pasted) != 0 || !m.ShowPasteConfirm || m.PendingPaste != m.PrimarySelection {
+		t.Errorf("expected the paste held for confirmation, got %q", pasted)
+	}
+	m.CancelPendingPaste()
+
+	// Applications reading the mouse get the click instead
+	pasted = nil
+	m.PrimarySelection = "echo hi"
+	_, _ = win.Terminal.Write([]byte("\x1b[?1000h"))
+	win.IsAltScreen = true
+	handleMouseClick(middleClick, m)
+	if strings.Contains(string(pasted), "echo hi") {
+		t.Errorf("expected the click to go to the application, got %q", pasted)
+	}
+}
+
+// cmdMessages runs a command and the commands it batches, returning their
+// messages.
+func cmdMessages(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, c := range batch {
+		msgs = append(msgs, cmdMessages(c)...)
+	}
+	return msgs
+}
+
+func TestBlockSelection(t *testing.T) {
+	m := app.NewOS(app.OSOptions{})
+	m.Width, m.Height = 120, 40
+	emu := vt.NewEmulator(38, 8)
+	_, _ = emu.Write([]byte("id  name   size\r\n1   alpha  10\r\n22  beta   200"))
+	win := &terminal.Window{ID: "window-block-00", X: 10, Y: 5, Width: 40, Height: 10, Workspace: 1, Terminal: emu}
+	m.Windows = []*terminal.Window{win}
+	m.FocusedWindow = 0
+	m.Mode = app.TerminalMode
+
+	// Alt-dragging from the "name" column header to the last row keeps to
+	// the columns in between
+	handleMouseClick(tea.MouseClickMsg{X: 15, Y: 6, Button: tea.MouseLeft, Mod: tea.ModAlt}, m)
+	handleMouseMotion(tea.MouseMotionMsg{X: 19, Y: 8, Button: tea.MouseLeft, Mod: tea.ModAlt}, m)
+	if _, cmd := handleMouseRelease(tea.MouseReleaseMsg{X: 19, Y: 8, Button: tea.MouseLeft, Mod: tea.ModAlt}, m); cmd == nil {
+		t.Fatal("block selection copied nothing")
+	}
+	if want := "name\nalpha\nbeta"; win.SelectedText != want {
+		t.Errorf("selected %q, want %q", win.SelectedText, want)
+	}
+
+	// Copy mode block visual selects the same rectangle
+	cm := &terminal.CopyMode{Active: true, CursorX: 4, CursorY: 0}
+	enterVisualBlock(cm, win)
+	cm.CursorX, cm.CursorY = 8, 2
+	updateVisualEnd(cm, win)
+	if got, want := extractVisualText(cm, win), "name\nalpha\nbeta"; got != want {
+		t.Errorf("visual block = %q, want %q", got, want)
+	}
+}
+
+func TestBorderResizeZone(t *testing.T) {
+	win := &terminal.Window{X: 10, Y: 5, Width: 40, Height: 10}
+	tests := []struct {
+		x, y int
+		want app.ResizeCorner
+		ok   bool
+	}{
+		{10, 5, app.TopLeft, true},
+		{11, 5, app.TopLeft, true},
+		{10, 6, app.TopLeft, true},
+		{49, 6, app.TopRight, true},
+		{25, 5, 0, false}, // Title bar
+		{10, 9, app.Left, true},
+		{49, 9, app.Right, true},
+		{25, 14, app.Bottom, true},
+		{48, 14, app.BottomRight, true},
+		{49, 13, app.BottomRight, true},
+		{10, 14, app.BottomLeft, true},
+		{25, 9, 0, false}, // Content
+		{50, 9, 0, false}, // Outside
+	}
+	for _, tt := range tests {
+		got, ok := borderResizeZone(win, tt.x, tt.y)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("borderResizeZone(%d, %d) = %v, %v, want %v, %v", tt.x, tt.y, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestResizeFromBorder(t *testing.T) {
+	m := app.NewOS(app.OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	top := m.GetTopMargin()
+	win := &terminal.Window{ID: "window-resize-0", X: -10, Y: top + 5, Width: 40, Height: 10, Workspace: 1, Terminal: vt.NewEmulator(38, 8)}
+	m.Windows = []*terminal.Window{win}
+	m.FocusedWindow = 0
+
+	// The right edge of a window partly off screen resizes it in place
+	handleMouseClick(tea.MouseClickMsg{X: 29, Y: top + 9, Button: tea.MouseLeft}, m)
+	if !m.Resizing || m.Dragging {
+		t.Fatal("clicking the right border did not start a resize")
+	}
+	handleMouseMotion(tea.MouseMotionMsg{X: 35, Y: top + 12, Button: tea.MouseLeft}, m)
+	handleMouseRelease(tea.MouseReleaseMsg{X: 35, Y: top + 12, Button: tea.MouseLeft}, m)
+	if win.X != -10 || win.Y != top+5 || win.Width != 46 || win.Height != 10 {
+		t.Errorf("after resizing from the right edge, window at %d,%d %dx%d, want -10,%d 46x10", win.X, win.Y, win.Width, win.Height, top+5)
+	}
+
+	// The bottom edge only changes the height
+	handleMouseClick(tea.MouseClickMsg{X: 10, Y: top + 14, Button: tea.MouseLeft}, m)
+	handleMouseMotion(tea.MouseMotionMsg{X: 20, Y: top + 16, Button: tea.MouseLeft}, m)
+	handleMouseRelease(tea.MouseReleaseMsg{X: 20, Y: top + 16, Button: tea.MouseLeft}, m)
+	if win.X != -10 || win.Width != 46 || win.Height != 12 {
+		t.Errorf("after resizing from the bottom edge, window at x %d %dx%d, want -10 46x12", win.X, win.Width, win.Height)
+	}
+
+	// The title bar still moves the window
+	handleMouseClick(tea.MouseClickMsg{X: 10, Y: top + 5, Button: tea.MouseLeft}, m)
+	if m.Resizing || !m.Dragging {
+		t.Error("clicking the title bar did not start a drag")
+	}
+	handleMouseRelease(tea.MouseReleaseMsg{X: 10, Y: top + 5, Button: tea.MouseLeft}, m)
+
+	// While scrolled back the right border still resizes, and the column
+	// inside it is the scrollbar
+	win.ScrollbackOffset = 1
+	handleMouseClick(tea.MouseClickMsg{X: 35, Y: top + 9, Button: tea.MouseLeft}, m)
+	if !m.Resizing || m.ScrollbarDrag != nil {
+		t.Error("clicking the right border of a scrolled back window did not start a resize")
+	}
+	handleMouseRelease(tea.MouseReleaseMsg{X: 35, Y: top + 9, Button: tea.MouseLeft}, m)
+	handleMouseClick(tea.MouseClickMsg{X: 34, Y: top + 9, Button: tea.MouseLeft}, m)
+	if m.ScrollbarDrag != win {
+		t.Error("clicking inside the right border did not grab the scrollbar")
+	}
+	handleMouseRelease(tea.MouseReleaseMsg{X: 34, Y: top + 9, Button: tea.MouseLeft}, m)
+}
+
+func TestFindClickedWindowPiP(t *testing.T) {
+	m := app.NewOS(app.OSOptions{})
+	m.CurrentWorkspace = 1
+	m.Windows = []*terminal.Window{
+		{ID: "window-pip-0000", X: 60, Y: 20, Width: 30, Height: 10, Z: 0, Workspace: 1, PiP: true},
+		{ID: "window-back-000", X: 0, Y: 0, Width: 100, Height: 40, Z: 5, Workspace: 1},
+	}
+
+	// The miniature is drawn above windows with a higher Z
+	if got := findClickedWindow(70, 25, m); got != 0 {
+		t.Errorf("click on the picture-in-picture window hit %d, want 0", got)
+	}
+	if got := findClickedWindow(10, 5, m); got != 1 {
+		t.Errorf("click beside the picture-in-picture window hit %d, want 1", got)
+	}
+
+	m.Windows[0].PiP = false
+	if got := findClickedWindow(70, 25, m); got != 1 {
+		t.Errorf("click after leaving picture-in-picture hit %d, want the higher window 1", got)
+	}
+}
+
+func TestEdgeResistance(t *testing.T) {
+	defer func(r int) { config.EdgeResistance = r }(config.EdgeResistance)
+	config.EdgeResistance = 4
+
+	m := app.NewOS(app.OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	top := m.GetTopMargin()
+	win := &terminal.Window{ID: "window-resist-0", X: 10, Y: top + 5, Width: 40, Height: 10, Workspace: 1, Terminal: vt.NewEmulator(38, 8)}
+	m.Windows = []*terminal.Window{win}
+	m.FocusedWindow = 0
+
+	// Grab the title bar 5 cells in and drag left past the screen edge
+	handleMouseClick(tea.MouseClickMsg{X: 15, Y: top + 5, Button: tea.MouseLeft}, m)
+	handleMouseMotion(tea.MouseMotionMsg{X: 2, Y: top + 5, Button: tea.MouseLeft}, m)
+	if win.X != 0 {
+		t.Errorf("window dragged 3 cells past the left edge at x %d, want it held at 0", win.X)
+	}
+	handleMouseMotion(tea.MouseMotionMsg{X: 0, Y: top + 5, Button: tea.MouseLeft}, m)
+	if win.X != -5 {
+		t.Errorf("window dragged 5 cells past the left edge at x %d, want -5", win.X)
+	}
+	// Past the edge it moves freely
+	handleMouseMotion(tea.MouseMotionMsg{X: 3, Y: top + 5, Button: tea.MouseLeft}, m)
+	if win.X != -2 {
+		t.Errorf("window moved back toward the left edge at x %d, want -2", win.X)
+	}
+
+	// The dock boundary resists too
+	handleMouseMotion(tea.MouseMotionMsg{X: 20, Y: top + m.GetUsableHeight() - 8, Button: tea.MouseLeft}, m)
+	handleMouseMotion(tea.MouseMotionMsg{X: 20, Y: top + m.GetUsableHeight() - 9, Button: tea.MouseLeft}, m)
+	if floor := top + m.GetUsableHeight() - win.Height; win.Y != floor {
+		t.Errorf("window dragged 1 cell past the dock at y %d, want it held at %d", win.Y, floor)
+	}
+	handleMouseRelease(tea.MouseReleaseMsg{X: 20, Y: top + 20, Button: tea.MouseLeft}, m)
+
+	config.EdgeResistance = 0
+	if x, _ := resistScreenEdges(m, win, -2, win.Y); x != -2 {
+		t.Errorf("window held at the edge with resistance off, x %d", x)
+	}
+}
+
+func TestDragKeepsTitleBarOnScreen(t *testing.T) {
+	defer func(r, n int) { config.EdgeResistance, config.MinVisibleColumns = r, n }(config.EdgeResistance, config.MinVisibleColumns)
+	config.EdgeResistance, config.MinVisibleColumns = 0, 20
+
+	m := app.NewOS(app.OSOptions{})
+	m.Width, m.Height = 120, 40
+	m.CurrentWorkspace = 1
+	top := m.GetTopMargin()
+	win := &terminal.Window{ID: "window-narrow-0", X: 10, Y: top + 5, Width: 16, Height: 6, Workspace: 1, Terminal: vt.NewEmulator(14, 4)}
+	m.Windows = []*terminal.Window{win}
+	m.FocusedWindow = 0
+
+	// A window narrower than min_visible_columns stops at the edge
+	handleMouseClick(tea.MouseClickMsg{X: 12, Y: top + 5, Button: tea.MouseLeft}, m)
+	handleMouseMotion(tea.MouseMotionMsg{X: 1, Y: top + 5, Button: tea.MouseLeft}, m)
+	if win.X != 0 {
+		t.Errorf("16 column window dragged past the left edge at x %d, want 0", win.X)
+	}
+
+	// With the clamp off it leaves the screen
+	config.MinVisibleColumns = 0
+	handleMouseMotion(tea.MouseMotionMsg{X: 0, Y: top + 5, Button: tea.MouseLeft}, m)
+	if win.X != -2 {
+		t.Errorf("window dragged with the clamp off at x %d, want -2", win.X)
+	}
+	handleMouseRelease(tea.MouseReleaseMsg{X: 0, Y: top + 5, Button: tea.MouseLeft}, m)
+}
@@ -4,13 +4,107 @@ package input
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	tea "charm.land/bubbletea/v2"
 	"github.com/Gaurav-Gosain/tuios/internal/app"
 	"github.com/Gaurav-Gosain/tuios/internal/config"
 	"github.com/Gaurav-Gosain/tuios/internal/terminal"
 	uv "github.com/charmbracelet/ultraviolet"
 )
 
+// startOverrideSelection starts a text selection in pane at (x, y), the
+// position relative to the pane, for a click with the mouse override
+// modifier or Alt held; with block set it selects a rectangle. As in
+// selection mode a double click selects the word and a triple click the
+// line under the mouse.
+func startOverrideSelection(o *app.OS, pane *terminal.Window, x, y int, block bool) {
+	now := time.Now()
+	if now.Sub(pane.LastClickTime) > 500*time.Millisecond || pane.LastClickX != x || pane.LastClickY != y {
+		pane.ClickCount = 1
+	} else {
+		pane.ClickCount++
+	}
+	pane.LastClickTime = now
+	pane.LastClickX = x
+	pane.LastClickY = y
+
+	switch pane.ClickCount {
+	case 2:
+		selectWord(pane, x, y, o)
+		pane.SelectionMode = 1 // Word mode
+	case 3:
+		selectLine(pane, y)
+		pane.SelectionMode = 2 // Line mode
+		pane.ClickCount = 0
+	default:
+		pane.IsSelecting = true
+		pane.SelectionStart.X = x
+		pane.SelectionStart.Y = y
+		pane.SelectionEnd = pane.SelectionStart
+		pane.SelectionMode = 0 // Character mode
+		if block {
+			pane.SelectionMode = 3 // Block mode
+		}
+		pane.SelectedText = ""
+	}
+	o.MouseSelection = pane
+	if frame := o.GetFocusedWindow(); frame != nil {
+		frame.MarkContentDirty()
+	}
+}
+
+// extendOverrideSelection moves the end of the override selection to screen
+// position (x, y), kept inside the pane so dragging past its edge selects
+// up to the edge.
+func extendOverrideSelection(o *app.OS, x, y int) {
+	pane, frame := o.MouseSelection, o.GetFocusedWindow()
+	if frame == nil || o.GetFocusedPane() != pane {
+		return
+	}
+	rect := frame.ActivePaneRect()
+	x, y = x-frame.X-1-rect.X, y-frame.Y-1-rect.Y
+	if frame.Zoomed {
+		x, y = x/2, y/2
+	}
+	pane.SelectionEnd.X = min(max(x, 0), pane.Terminal.Width()-1)
+	pane.SelectionEnd.Y = min(max(y, 0), pane.Terminal.Height()-1)
+	frame.MarkContentDirty()
+}
+
+// finishOverrideSelection ends the override selection and copies the text
+// selected to the clipboard. The selection stays highlighted until the next
+// click or key press. A click that did not drag selects nothing.
+func finishOverrideSelection(o *app.OS) tea.Cmd {
+	pane := o.MouseSelection
+	pane.IsSelecting = false
+	text := ""
+	if pane.ClickCount != 1 || pane.SelectionStart != pane.SelectionEnd {
+		text = extractSelectedText(pane, o)
+	}
+	if text == "" {
+		clearOverrideSelection(o)
+		return nil
+	}
+	pane.SelectedText = text
+	if cmd := o.MouseSelected(text); cmd != nil {
+		return cmd
+	}
+	return o.CopyToClipboard(text, fmt.Sprintf("Copied %d chars", len(text)))
+}
+
+// clearOverrideSelection removes the highlight of the override selection.
+func clearOverrideSelection(o *app.OS) {
+	pane := o.MouseSelection
+	if pane == nil {
+		return
+	}
+	o.MouseSelection = nil
+	pane.IsSelecting = false
+	pane.SelectedText = ""
+	o.MarkAllDirty()
+}
+
 // extractSelectedText extracts selected text from terminal based on selection coordinates
 // This handles both current screen content and scrollback buffer
 func extractSelectedText(window *terminal.Window, _ *app.OS) string {
@@ -26,6 +120,7 @@ func extractSelectedText(window *terminal.Window, _ *app.OS) string {
 	// Get selection bounds
 	startX, startY := window.SelectionStart.X, window.SelectionStart.Y
 	endX, endY := window.SelectionEnd.X, window.SelectionEnd.Y
+	blockLeft, blockRight := min(startX, endX), max(startX, endX)
 
 	// Normalize selection (ensure start is before end)
 	if startY > endY || (startY == endY && startX > endX) {
@@ -81,6 +176,24 @@ func extractSelectedText(window *terminal.Window, _ *app.OS) string {
 		}
 	}
 
+	// Block selection: the same columns of every line, without trailing spaces
+	if window.SelectionMode == 3 {
+		lines := make([]string, 0, endY-startY+1)
+		for y := startY; y <= endY; y++ {
+			var line strings.Builder
+			for x := blockLeft; x <= blockRight && x < screenWidth; x++ {
+				cell := getCellAt(x, y)
+				if cell != nil && cell.Content != "" {
+					line.WriteString(cell.Content)
+				} else if cell == nil || cell.Width > 0 {
+					line.WriteRune(' ')
+				}
+			}
+			lines = append(lines, strings.TrimRight(line.String(), " "))
+		}
+		return strings.Join(lines, "\n")
+	}
+
 	// Single line selection
 	if startY == endY {
 		// Clamp selection bounds to line length
@@ -146,36 +259,22 @@ func extractSelectedText(window *terminal.Window, _ *app.OS) string {
 	return strings.TrimSpace(selectedText.String())
 }
 
-// handleClipboardPaste processes clipboard content and sends it to the focused terminal
-func handleClipboardPaste(o *app.OS) {
+// handleClipboardPaste processes clipboard content and sends it to the focused terminal.
+// Risky multi-line pastes are confirmed first and large pastes are streamed in chunks.
+func handleClipboardPaste(o *app.OS) tea.Cmd {
 	if o.FocusedWindow < 0 || o.FocusedWindow >= len(o.Windows) {
-		return
+		return nil
 	}
 
-	focusedWindow := o.GetFocusedWindow()
+	focusedWindow := o.GetFocusedPane()
 	if focusedWindow == nil {
-		return
+		return nil
 	}
 
 	if o.ClipboardContent == "" {
 		o.ShowNotification("Clipboard is empty", "warning", config.NotificationDuration)
-		return
-	}
-
-	// Build paste content with bracketed paste sequences if the app has enabled it.
-	// We use SendInput() instead of Terminal.Paste() because in daemon mode,
-	// Terminal.Paste() writes to an internal pipe that gets drained by
-	// StartDaemonResponseReader() - the data never reaches the PTY.
-	// SendInput() properly routes through DaemonWriteFunc in daemon mode.
-	pasteContent := o.ClipboardContent
-	if focusedWindow.Terminal != nil && focusedWindow.Terminal.BracketedPasteEnabled() {
-		pasteContent = "\x1b[200~" + pasteContent + "\x1b[201~"
-	}
-
-	if err := focusedWindow.SendInput([]byte(pasteContent)); err != nil {
-		o.ShowNotification("Paste failed", "error", config.NotificationDuration)
-		return
+		return nil
 	}
 
-	o.ShowNotification(fmt.Sprintf("Pasted %d characters", len(o.ClipboardContent)), "success", config.NotificationDuration)
+	return o.Paste(focusedWindow, o.ClipboardContent)
 }
@@ -2,6 +2,7 @@
 package layout
 
 import (
+	"slices"
 	"sync/atomic"
 
 	"github.com/Gaurav-Gosain/tuios/internal/config"
@@ -391,15 +392,23 @@ func (t *BSPTree) RemoveWindow(windowID int) {
 // ApplyLayout calculates positions for all windows in the tree.
 // Returns a map of windowID -> Rect with the calculated layout.
 func (t *BSPTree) ApplyLayout(bounds Rect) map[int]Rect {
+	return t.ApplyLayoutWithMinSizes(bounds, nil)
+}
+
+// ApplyLayoutWithMinSizes is like ApplyLayout but moves split lines off their
+// ratio where needed to give windows at least their minimum size, keyed by
+// window ID with W and H set. Windows without an entry get the default
+// minimum. A split that cannot satisfy both sides keeps its ratio.
+func (t *BSPTree) ApplyLayoutWithMinSizes(bounds Rect, minSizes map[int]Rect) map[int]Rect {
 	result := make(map[int]Rect)
 	if t.Root == nil {
 		return result
 	}
-	t.applyLayoutRecursive(t.Root, bounds, result)
+	t.applyLayoutRecursive(t.Root, bounds, minSizes, result)
 	return result
 }
 
-func (t *BSPTree) applyLayoutRecursive(node *TileNode, bounds Rect, result map[int]Rect) {
+func (t *BSPTree) applyLayoutRecursive(node *TileNode, bounds Rect, minSizes map[int]Rect, result map[int]Rect) {
 	if node == nil {
 		return
 	}
@@ -407,35 +416,66 @@ func (t *BSPTree) applyLayoutRecursive(node *TileNode, bounds Rect, result map[i
 	// Leaf node - this is a window
 	if node.IsLeaf() {
 		// Enforce minimum sizes
-		w := bounds.W
-		h := bounds.H
-		if w < config.DefaultWindowWidth {
-			w = config.DefaultWindowWidth
-		}
-		if h < config.DefaultWindowHeight {
-			h = config.DefaultWindowHeight
-		}
+		minSize := leafMinSize(node, minSizes)
+		w := max(bounds.W, minSize.W)
+		h := max(bounds.H, minSize.H)
 		result[node.WindowID] = Rect{X: bounds.X, Y: bounds.Y, W: w, H: h}
 		return
 	}
 
 	// Internal node - split the bounds
 	var leftBounds, rightBounds Rect
+	leftMin := subtreeMinSize(node.Left, minSizes)
+	rightMin := subtreeMinSize(node.Right, minSizes)
 
 	if node.SplitType == SplitVertical {
 		// Vertical split: left | right
 		splitX := bounds.X + int(float64(bounds.W)*node.SplitRatio)
+		if lo, hi := bounds.X+leftMin.W, bounds.X+bounds.W-rightMin.W; lo <= hi {
+			splitX = max(lo, min(splitX, hi))
+		}
 		leftBounds = Rect{X: bounds.X, Y: bounds.Y, W: splitX - bounds.X, H: bounds.H}
 		rightBounds = Rect{X: splitX, Y: bounds.Y, W: bounds.X + bounds.W - splitX, H: bounds.H}
 	} else {
 		// Horizontal split: top / bottom
 		splitY := bounds.Y + int(float64(bounds.H)*node.SplitRatio)
+		if lo, hi := bounds.Y+leftMin.H, bounds.Y+bounds.H-rightMin.H; lo <= hi {
+			splitY = max(lo, min(splitY, hi))
+		}
 		leftBounds = Rect{X: bounds.X, Y: bounds.Y, W: bounds.W, H: splitY - bounds.Y}
 		rightBounds = Rect{X: bounds.X, Y: splitY, W: bounds.W, H: bounds.Y + bounds.H - splitY}
 	}
 
-	t.applyLayoutRecursive(node.Left, leftBounds, result)
-	t.applyLayoutRecursive(node.Right, rightBounds, result)
+	t.applyLayoutRecursive(node.Left, leftBounds, minSizes, result)
+	t.applyLayoutRecursive(node.Right, rightBounds, minSizes, result)
+}
+
+// leafMinSize returns the minimum size of the window in a leaf node.
+func leafMinSize(node *TileNode, minSizes map[int]Rect) Rect {
+	size := Rect{W: config.DefaultWindowWidth, H: config.DefaultWindowHeight}
+	if minSize, ok := minSizes[node.WindowID]; ok {
+		size.W = max(size.W, minSize.W)
+		size.H = max(size.H, minSize.H)
+	}
+	return size
+}
+
+// subtreeMinSize returns the smallest bounds that fit every window of a
+// subtree at its minimum size: side by side across vertical splits and
+// stacked across horizontal ones.
+func subtreeMinSize(node *TileNode, minSizes map[int]Rect) Rect {
+	if node == nil {
+		return Rect{}
+	}
+	if node.IsLeaf() {
+		return leafMinSize(node, minSizes)
+	}
+	left := subtreeMinSize(node.Left, minSizes)
+	right := subtreeMinSize(node.Right, minSizes)
+	if node.SplitType == SplitVertical {
+		return Rect{W: left.W + right.W, H: max(left.H, right.H)}
+	}
+	return Rect{W: max(left.W, right.W), H: left.H + right.H}
 }
 
 // SyncRatiosFromGeometry updates the tree's split ratios based on actual window positions.
@@ -575,6 +615,48 @@ func (t *BSPTree) RotateSplit(windowID int) {
 	}
 }
 
+// Split ratios set by GrowWindow stay within these bounds so the other
+// side of a split never disappears.
+const (
+	minSplitRatio = 0.1
+	maxSplitRatio = 0.9
+)
+
+// GrowWindow moves the nearest vertical and the nearest horizontal split
+// around the given window by delta, growing the window's share of the layout
+// for positive deltas and shrinking it for negative ones. The windows on the
+// other side of each split give up or take the space. It reports whether any
+// split moved.
+func (t *BSPTree) GrowWindow(windowID int, delta float64) bool {
+	node := t.WindowToNode[windowID]
+	if node == nil {
+		return false
+	}
+
+	changed := false
+	seen := map[SplitType]bool{}
+	for child := node; child.Parent != nil && len(seen) < 2; child = child.Parent {
+		parent := child.Parent
+		if seen[parent.SplitType] {
+			continue
+		}
+		seen[parent.SplitType] = true
+
+		ratio := parent.SplitRatio
+		if child.IsLeftChild() {
+			ratio += delta
+		} else {
+			ratio -= delta
+		}
+		ratio = max(minSplitRatio, min(ratio, maxSplitRatio))
+		if ratio != parent.SplitRatio {
+			parent.SplitRatio = ratio
+			changed = true
+		}
+	}
+	return changed
+}
+
 // SwapWindows swaps the positions of two windows in the tree
 func (t *BSPTree) SwapWindows(windowID1, windowID2 int) {
 	node1 := t.WindowToNode[windowID1]
@@ -591,6 +673,38 @@ func (t *BSPTree) SwapWindows(windowID1, windowID2 int) {
 	t.WindowToNode[windowID2] = node1
 }
 
+// MoveWindowToFront moves a window into the first slot of the tiling order,
+// shifting the windows before it one slot along. The shape of the tree and
+// its split ratios are kept.
+func (t *BSPTree) MoveWindowToFront(windowID int) {
+	var leaves []*TileNode
+	collectLeaves(t.Root, &leaves)
+
+	i := slices.IndexFunc(leaves, func(n *TileNode) bool { return n.WindowID == windowID })
+	if i < 0 {
+		return
+	}
+	for ; i > 0; i-- {
+		leaves[i].WindowID = leaves[i-1].WindowID
+		t.WindowToNode[leaves[i].WindowID] = leaves[i]
+	}
+	leaves[0].WindowID = windowID
+	t.WindowToNode[windowID] = leaves[0]
+}
+
+// collectLeaves appends the leaves of a subtree in tiling order.
+func collectLeaves(node *TileNode, leaves *[]*TileNode) {
+	if node == nil {
+		return
+	}
+	if node.IsLeaf() {
+		*leaves = append(*leaves, node)
+		return
+	}
+	collectLeaves(node.Left, leaves)
+	collectLeaves(node.Right, leaves)
+}
+
 // EqualizeRatios sets all split ratios to 0.5
 func (t *BSPTree) EqualizeRatios() {
 	equalizeRatiosRecursive(t.Root)
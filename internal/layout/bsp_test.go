@@ -1,6 +1,8 @@
 package layout
 
 import (
+	"math"
+	"slices"
 	"testing"
 )
 
@@ -206,6 +208,90 @@ func TestBSPTree_InsertSecondWindow(t *testing.T) {
 	}
 }
 
+// TestBSPTree_ApplyLayoutWithMinSizes tests that splits move to give windows
+// their minimum size
+func TestBSPTree_ApplyLayoutWithMinSizes(t *testing.T) {
+	tree := NewBSPTree()
+	bounds := Rect{X: 0, Y: 0, W: 100, H: 50}
+	tree.InsertWindow(1, 0, SplitNone, 0.5, bounds)
+	tree.InsertWindow(2, 1, SplitVertical, 0.5, bounds)
+
+	layouts := tree.ApplyLayoutWithMinSizes(bounds, map[int]Rect{2: {W: 70, H: 10}})
+	if layouts[2].W != 70 || layouts[2].X != 30 {
+		t.Errorf("Expected window 2 to get its minimum width of 70 at x=30, got %+v", layouts[2])
+	}
+	if layouts[1].W != 30 {
+		t.Errorf("Expected window 1 to keep the remaining 30 columns, got %+v", layouts[1])
+	}
+
+	// A minimum that cannot fit beside the other window keeps the ratio
+	layouts = tree.ApplyLayoutWithMinSizes(bounds, map[int]Rect{2: {W: 95}})
+	if layouts[1].W != 50 {
+		t.Errorf("Expected the split to keep its ratio, got %+v", layouts[1])
+	}
+}
+
+// TestBSPTree_GrowWindow tests that growing a window moves the splits around it
+func TestBSPTree_GrowWindow(t *testing.T) {
+	tree := NewBSPTree()
+	bounds := Rect{X: 0, Y: 0, W: 100, H: 100}
+	tree.InsertWindow(1, 0, SplitNone, 0.5, bounds)
+	tree.InsertWindow(2, 1, SplitVertical, 0.5, bounds)
+	tree.InsertWindow(3, 2, SplitHorizontal, 0.5, bounds)
+
+	// Window 3 is the bottom of the right half: both splits move toward it
+	if !tree.GrowWindow(3, 0.1) {
+		t.Fatal("Expected GrowWindow to move splits")
+	}
+	vertical, horizontal := tree.Root, tree.FindNode(3).Parent
+	if !ratioEqual(vertical.SplitRatio, 0.4) || !ratioEqual(horizontal.SplitRatio, 0.4) {
+		t.Errorf("Expected ratios 0.4/0.4, got %v/%v", vertical.SplitRatio, horizontal.SplitRatio)
+	}
+
+	// Window 1 only has the vertical split next to it
+	tree.GrowWindow(1, 0.2)
+	if !ratioEqual(vertical.SplitRatio, 0.6) || !ratioEqual(horizontal.SplitRatio, 0.4) {
+		t.Errorf("Expected ratios 0.6/0.4, got %v/%v", vertical.SplitRatio, horizontal.SplitRatio)
+	}
+
+	// Ratios stop at the bounds
+	tree.GrowWindow(1, 1)
+	if tree.GrowWindow(1, 1) || vertical.SplitRatio != maxSplitRatio {
+		t.Errorf("Expected ratio clamped to %v, got %v", maxSplitRatio, vertical.SplitRatio)
+	}
+	if tree.GrowWindow(99, 0.1) {
+		t.Error("Expected unknown window to change nothing")
+	}
+}
+
+// TestBSPTree_MoveWindowToFront tests moving a window into the first slot
+func TestBSPTree_MoveWindowToFront(t *testing.T) {
+	tree := NewBSPTree()
+	bounds := Rect{X: 0, Y: 0, W: 100, H: 100}
+	tree.InsertWindow(1, 0, SplitNone, 0.5, bounds)
+	tree.InsertWindow(2, 1, SplitVertical, 0.5, bounds)
+	tree.InsertWindow(3, 2, SplitHorizontal, 0.5, bounds)
+
+	tree.MoveWindowToFront(3)
+	if got := tree.GetAllWindowIDs(); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("Expected order [3 1 2], got %v", got)
+	}
+	for _, id := range []int{1, 2, 3} {
+		if tree.FindNode(id).WindowID != id {
+			t.Errorf("Window %d maps to the node of window %d", id, tree.FindNode(id).WindowID)
+		}
+	}
+
+	tree.MoveWindowToFront(99)
+	if got := tree.GetAllWindowIDs(); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("Expected unknown window to change nothing, got %v", got)
+	}
+}
+
+func ratioEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
 // TestBSPTree_InsertDuplicate tests that duplicate windows are not inserted
 func TestBSPTree_InsertDuplicate(t *testing.T) {
 	tree := NewBSPTree()
@@ -0,0 +1,153 @@
+package profiling
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// frameBuckets are the upper bounds of the frame time histogram, in seconds.
+var frameBuckets = [...]float64{0.001, 0.0025, 0.005, 0.01, 0.016, 0.025, 0.05, 0.1, 0.25, 1}
+
+// frameHistogram counts frames by render time. counts[i] holds the frames
+// no slower than frameBuckets[i] but slower than the bucket before; the last
+// slot holds the frames slower than every bucket.
+var frameHistogram struct {
+	counts [len(frameBuckets) + 1]atomic.Int64
+}
+
+// observeFrame adds a frame render time to the histogram.
+func observeFrame(d time.Duration) {
+	seconds := d.Seconds()
+	for i, bound := range frameBuckets {
+		if seconds <= bound {
+			frameHistogram.counts[i].Add(1)
+			return
+		}
+	}
+	frameHistogram.counts[len(frameBuckets)].Add(1)
+}
+
+// WindowMetrics are the metrics of one window.
+type WindowMetrics struct {
+	ID              string
+	Name            string
+	Workspace       int
+	OutputBytes     uint64 // Output received from the window's processes in total
+	ScrollbackBytes int    // Estimated memory held by the scrollback
+	ScrollbackLines int
+}
+
+// SessionMetrics are the metrics of the session, published by the app
+// while a metrics endpoint is being served.
+type SessionMetrics struct {
+	Workspace int // Current workspace
+	Windows   []WindowMetrics
+}
+
+var (
+	session        atomic.Pointer[SessionMetrics]
+	metricsServers atomic.Int32
+)
+
+// MetricsEnabled reports whether a metrics endpoint is being served, so the
+// app knows to publish session metrics.
+func MetricsEnabled() bool {
+	return metricsServers.Load() > 0
+}
+
+// SetSessionMetrics publishes the session metrics served at /metrics.
+func SetSessionMetrics(metrics SessionMetrics) {
+	session.Store(&metrics)
+}
+
+// StartMetrics starts a server on addr, which must be a loopback address,
+// that serves only the Prometheus metrics under /metrics.
+func StartMetrics(addr string) (*Server, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", serveMetrics)
+	return listen(addr, mux)
+}
+
+// serveMetrics writes the metrics in the Prometheus text exposition format.
+func serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	WriteMetrics(w)
+}
+
+// WriteMetrics writes the metrics in the Prometheus text exposition format.
+func WriteMetrics(w io.Writer) {
+	fmt.Fprintln(w, "# HELP tuios_frame_seconds Time taken to render a frame.")
+	fmt.Fprintln(w, "# TYPE tuios_frame_seconds histogram")
+	var cumulative int64
+	for i, bound := range frameBuckets {
+		cumulative += frameHistogram.counts[i].Load()
+		fmt.Fprintf(w, "tuios_frame_seconds_bucket{le=\"%s\"} %d\n", formatFloat(bound), cumulative)
+	}
+	cumulative += frameHistogram.counts[len(frameBuckets)].Load()
+	fmt.Fprintf(w, "tuios_frame_seconds_bucket{le=\"+Inf\"} %d\n", cumulative)
+	fmt.Fprintf(w, "tuios_frame_seconds_sum %s\n", formatFloat(float64(frames.totalNs.Load())/1e9))
+	fmt.Fprintf(w, "tuios_frame_seconds_count %d\n", frames.count.Load())
+
+	fmt.Fprintln(w, "# HELP tuios_goroutines Number of goroutines.")
+	fmt.Fprintln(w, "# TYPE tuios_goroutines gauge")
+	fmt.Fprintf(w, "tuios_goroutines %d\n", runtime.NumGoroutine())
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	fmt.Fprintln(w, "# HELP tuios_heap_bytes Bytes of allocated heap objects.")
+	fmt.Fprintln(w, "# TYPE tuios_heap_bytes gauge")
+	fmt.Fprintf(w, "tuios_heap_bytes %d\n", mem.HeapAlloc)
+
+	metrics := session.Load()
+	if metrics == nil {
+		return
+	}
+	fmt.Fprintln(w, "# HELP tuios_windows Number of open windows.")
+	fmt.Fprintln(w, "# TYPE tuios_windows gauge")
+	fmt.Fprintf(w, "tuios_windows %d\n", len(metrics.Windows))
+	fmt.Fprintln(w, "# HELP tuios_workspace Current workspace.")
+	fmt.Fprintln(w, "# TYPE tuios_workspace gauge")
+	fmt.Fprintf(w, "tuios_workspace %d\n", metrics.Workspace)
+
+	total := 0
+	fmt.Fprintln(w, "# HELP tuios_window_output_bytes_total Output received from a window's processes.")
+	fmt.Fprintln(w, "# TYPE tuios_window_output_bytes_total counter")
+	for _, win := range metrics.Windows {
+		fmt.Fprintf(w, "tuios_window_output_bytes_total%s %d\n", windowLabels(win), win.OutputBytes)
+	}
+	fmt.Fprintln(w, "# HELP tuios_window_scrollback_bytes Estimated memory held by a window's scrollback.")
+	fmt.Fprintln(w, "# TYPE tuios_window_scrollback_bytes gauge")
+	for _, win := range metrics.Windows {
+		fmt.Fprintf(w, "tuios_window_scrollback_bytes%s %d\n", windowLabels(win), win.ScrollbackBytes)
+		total += win.ScrollbackBytes
+	}
+	fmt.Fprintln(w, "# HELP tuios_window_scrollback_lines Lines stored in a window's scrollback.")
+	fmt.Fprintln(w, "# TYPE tuios_window_scrollback_lines gauge")
+	for _, win := range metrics.Windows {
+		fmt.Fprintf(w, "tuios_window_scrollback_lines%s %d\n", windowLabels(win), win.ScrollbackLines)
+	}
+	fmt.Fprintln(w, "# HELP tuios_scrollback_bytes Estimated memory held by the scrollback of every window.")
+	fmt.Fprintln(w, "# TYPE tuios_scrollback_bytes gauge")
+	fmt.Fprintf(w, "tuios_scrollback_bytes %d\n", total)
+}
+
+// windowLabels returns the label set identifying a window.
+func windowLabels(win WindowMetrics) string {
+	return fmt.Sprintf("{window=%s,name=%s,workspace=\"%d\"}", labelValue(win.ID), labelValue(win.Name), win.Workspace)
+}
+
+// labelValue quotes a label value, escaping it as the text format requires.
+func labelValue(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// formatFloat formats a sample value.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,150 @@
+// Package profiling provides an optional localhost debug server exposing
+// pprof profiles and expvar metrics for profiling tuios in real sessions.
+package profiling
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// frameStats accumulates frame rendering times.
+type frameStats struct {
+	count   atomic.Int64
+	totalNs atomic.Int64
+	lastNs  atomic.Int64
+	maxNs   atomic.Int64
+}
+
+var (
+	frames      frameStats
+	publishOnce sync.Once
+)
+
+// RecordFrame records the time taken to render a single frame. It is cheap
+// enough to call on every frame whether or not the server is running.
+func RecordFrame(d time.Duration) {
+	ns := d.Nanoseconds()
+	frames.count.Add(1)
+	frames.totalNs.Add(ns)
+	frames.lastNs.Store(ns)
+	observeFrame(d)
+	for {
+		cur := frames.maxNs.Load()
+		if ns <= cur || frames.maxNs.CompareAndSwap(cur, ns) {
+			break
+		}
+	}
+}
+
+// FrameSnapshot is a point-in-time view of the recorded frame timings.
+type FrameSnapshot struct {
+	Count  int64   `json:"count"`
+	LastMs float64 `json:"last_ms"`
+	AvgMs  float64 `json:"avg_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// Frames returns the frame timings recorded so far.
+func Frames() FrameSnapshot {
+	count := frames.count.Load()
+	snap := FrameSnapshot{
+		Count:  count,
+		LastMs: float64(frames.lastNs.Load()) / 1e6,
+		MaxMs:  float64(frames.maxNs.Load()) / 1e6,
+	}
+	if count > 0 {
+		snap.AvgMs = float64(frames.totalNs.Load()) / float64(count) / 1e6
+	}
+	return snap
+}
+
+// publish registers the tuios metrics with expvar. The runtime memstats and
+// command line are published by expvar itself.
+func publish() {
+	publishOnce.Do(func() {
+		expvar.Publish("goroutines", expvar.Func(func() any {
+			return runtime.NumGoroutine()
+		}))
+		expvar.Publish("frames", expvar.Func(func() any {
+			return Frames()
+		}))
+	})
+}
+
+// Server is a running debug server.
+type Server struct {
+	srv  *http.Server
+	addr string
+}
+
+// Start starts the debug server on addr, which must be a loopback address
+// such as "localhost:6060" or "127.0.0.1:6060". Profiles are served under
+// /debug/pprof/, expvar metrics under /debug/vars and Prometheus metrics
+// under /metrics.
+func Start(addr string) (*Server, error) {
+	publish()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/metrics", serveMetrics)
+	return listen(addr, mux)
+}
+
+// listen serves mux on addr, which must be a loopback address.
+func listen(addr string, mux *http.ServeMux) (*Server, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid debug server address %q: %w", addr, err)
+	}
+	if !isLoopback(host) {
+		return nil, fmt.Errorf("debug server address %q is not a loopback address", addr)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s := &Server{
+		srv:  &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+		addr: ln.Addr().String(),
+	}
+	metricsServers.Add(1)
+	go func() { _ = s.srv.Serve(ln) }()
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Close shuts the server down.
+func (s *Server) Close() error {
+	metricsServers.Add(-1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
+
+// isLoopback reports whether host names the local machine.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,112 @@
+package profiling
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartRejectsNonLoopback(t *testing.T) {
+	for _, addr := range []string{"0.0.0.0:6060", ":6060", "192.168.1.10:6060", "localhost"} {
+		if s, err := Start(addr); err == nil {
+			_ = s.Close()
+			t.Errorf("Start(%q) succeeded, want error", addr)
+		}
+	}
+}
+
+func TestServerExposesMetrics(t *testing.T) {
+	s, err := Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	RecordFrame(2 * time.Millisecond)
+
+	resp, err := http.Get("http://" + s.Addr() + "/debug/vars")
+	if err != nil {
+		t.Fatalf("GET /debug/vars failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	var vars map[string]json.RawMessage
+	if err := json.Unmarshal(body, &vars); err != nil {
+		t.Fatalf("invalid expvar output: %v", err)
+	}
+	for _, key := range []string{"goroutines", "frames", "memstats"} {
+		if _, ok := vars[key]; !ok {
+			t.Errorf("expected %q in /debug/vars", key)
+		}
+	}
+
+	var snap FrameSnapshot
+	if err := json.Unmarshal(vars["frames"], &snap); err != nil {
+		t.Fatalf("invalid frames metric: %v", err)
+	}
+	if snap.Count == 0 || snap.MaxMs < 2 {
+		t.Errorf("expected recorded frame in snapshot, got %+v", snap)
+	}
+
+	resp, err = http.Get("http://" + s.Addr() + "/debug/pprof/goroutine?debug=1")
+	if err != nil {
+		t.Fatalf("GET pprof failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("pprof status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	s, err := StartMetrics("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("StartMetrics failed: %v", err)
+	}
+	if !MetricsEnabled() {
+		t.Error("expected metrics enabled while the server runs")
+	}
+
+	RecordFrame(3 * time.Millisecond)
+	SetSessionMetrics(SessionMetrics{Workspace: 2, Windows: []WindowMetrics{
+		{ID: "w1", Name: `say "hi"`, Workspace: 2, OutputBytes: 4096, ScrollbackBytes: 100, ScrollbackLines: 5},
+		{ID: "w2", Name: "logs", Workspace: 1, ScrollbackBytes: 50},
+	}})
+
+	resp, err := http.Get("http://" + s.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	for _, want := range []string{
+		"# TYPE tuios_frame_seconds histogram",
+		`tuios_frame_seconds_bucket{le="+Inf"}`,
+		"tuios_windows 2\n",
+		"tuios_workspace 2\n",
+		`tuios_window_output_bytes_total{window="w1",name="say \"hi\"",workspace="2"} 4096`,
+		`tuios_window_scrollback_lines{window="w1",name="say \"hi\"",workspace="2"} 5`,
+		"tuios_scrollback_bytes 150\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected %q in /metrics, got:\n%s", want, body)
+		}
+	}
+
+	resp, err = http.Get("http://" + s.Addr() + "/debug/vars")
+	if err == nil {
+		_ = resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Error("expected the metrics server not to serve expvar")
+		}
+	}
+
+	_ = s.Close()
+	if MetricsEnabled() {
+		t.Error("expected metrics disabled once the server closed")
+	}
+}
@@ -0,0 +1,203 @@
+// Package replay records the input messages the Update loop receives and
+// replays them headlessly, to reproduce reported crashes and to fuzz the key
+// and mouse handling.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// Event types.
+const (
+	EventKey        = "key"
+	EventKeyRelease = "key_release"
+	EventClick      = "click"
+	EventRelease    = "release"
+	EventWheel      = "wheel"
+	EventMotion     = "motion"
+	EventPaste      = "paste"
+	EventResize     = "resize"
+	EventFocus      = "focus"
+	EventBlur       = "blur"
+)
+
+// Event is one recorded input message, stored as a line of JSON.
+type Event struct {
+	At      int64  `json:"at"` // Milliseconds since the recording started
+	Type    string `json:"type"`
+	Code    rune   `json:"code,omitempty"`
+	Text    string `json:"text,omitempty"` // Typed text, or the pasted content
+	Mod     int    `json:"mod,omitempty"`
+	Shifted rune   `json:"shifted,omitempty"`
+	Base    rune   `json:"base,omitempty"`
+	Repeat  bool   `json:"repeat,omitempty"`
+	X       int    `json:"x,omitempty"`
+	Y       int    `json:"y,omitempty"`
+	Button  int    `json:"button,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+}
+
+// FromMsg returns the event for an input message. It reports false for
+// messages that are not input, such as ticks and PTY output, which replays
+// do not need.
+func FromMsg(msg tea.Msg) (Event, bool) {
+	switch msg := msg.(type) {
+	case tea.KeyPressMsg:
+		return keyEvent(EventKey, tea.Key(msg)), true
+	case tea.KeyReleaseMsg:
+		return keyEvent(EventKeyRelease, tea.Key(msg)), true
+	case tea.MouseClickMsg:
+		return mouseEvent(EventClick, tea.Mouse(msg)), true
+	case tea.MouseReleaseMsg:
+		return mouseEvent(EventRelease, tea.Mouse(msg)), true
+	case tea.MouseWheelMsg:
+		return mouseEvent(EventWheel, tea.Mouse(msg)), true
+	case tea.MouseMotionMsg:
+		return mouseEvent(EventMotion, tea.Mouse(msg)), true
+	case tea.PasteMsg:
+		return Event{Type: EventPaste, Text: msg.Content}, true
+	case tea.WindowSizeMsg:
+		return Event{Type: EventResize, Width: msg.Width, Height: msg.Height}, true
+	case tea.FocusMsg:
+		return Event{Type: EventFocus}, true
+	case tea.BlurMsg:
+		return Event{Type: EventBlur}, true
+	}
+	return Event{}, false
+}
+
+func keyEvent(kind string, k tea.Key) Event {
+	return Event{Type: kind, Code: k.Code, Text: k.Text, Mod: int(k.Mod), Shifted: k.ShiftedCode, Base: k.BaseCode, Repeat: k.IsRepeat}
+}
+
+func mouseEvent(kind string, m tea.Mouse) Event {
+	return Event{Type: kind, X: m.X, Y: m.Y, Button: int(m.Button), Mod: int(m.Mod)}
+}
+
+// Msg returns the input message the event records, or nil for an unknown
+// event type.
+func (e Event) Msg() tea.Msg {
+	key := tea.Key{Code: e.Code, Text: e.Text, Mod: tea.KeyMod(e.Mod), ShiftedCode: e.Shifted, BaseCode: e.Base, IsRepeat: e.Repeat}
+	mouse := tea.Mouse{X: e.X, Y: e.Y, Button: tea.MouseButton(e.Button), Mod: tea.KeyMod(e.Mod)}
+	switch e.Type {
+	case EventKey:
+		return tea.KeyPressMsg(key)
+	case EventKeyRelease:
+		return tea.KeyReleaseMsg(key)
+	case EventClick:
+		return tea.MouseClickMsg(mouse)
+	case EventRelease:
+		return tea.MouseReleaseMsg(mouse)
+	case EventWheel:
+		return tea.MouseWheelMsg(mouse)
+	case EventMotion:
+		return tea.MouseMotionMsg(mouse)
+	case EventPaste:
+		return tea.PasteMsg{Content: e.Text}
+	case EventResize:
+		return tea.WindowSizeMsg{Width: e.Width, Height: e.Height}
+	case EventFocus:
+		return tea.FocusMsg{}
+	case EventBlur:
+		return tea.BlurMsg{}
+	}
+	return nil
+}
+
+// Recorder writes the input messages it is given to a file, one event per
+// line. Every event is flushed at once so a crash loses nothing.
+type Recorder struct {
+	mu    sync.Mutex
+	f     *os.File
+	w     *bufio.Writer
+	start time.Time
+}
+
+// NewRecorder creates the recording file at path.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+	return &Recorder{f: f, w: bufio.NewWriter(f), start: time.Now()}, nil
+}
+
+// Record writes msg if it is an input message.
+func (r *Recorder) Record(msg tea.Msg) {
+	event, ok := FromMsg(msg)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.At = time.Since(r.start).Milliseconds()
+	if data, err := json.Marshal(event); err == nil {
+		_, _ = r.w.Write(append(data, '\n'))
+		_ = r.w.Flush()
+	}
+}
+
+// Close closes the recording file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.w.Flush(); err != nil {
+		_ = r.f.Close()
+		return err
+	}
+	return r.f.Close()
+}
+
+// Load reads the events of a recording.
+func Load(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return Read(f)
+}
+
+// Read reads recorded events, one JSON object per line.
+func Read(r io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Pastes can be long
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if event.Msg() == nil {
+			return nil, fmt.Errorf("line %d: unknown event type %q", line, event.Type)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return events, nil
+}
+
+// Write writes events as a recording.
+func Write(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
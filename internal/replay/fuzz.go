@@ -0,0 +1,71 @@
+package replay
+
+import (
+	"math/rand/v2"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// fuzzSpecialKeys are the non-printable keys random input presses.
+var fuzzSpecialKeys = []rune{
+	tea.KeyEnter, tea.KeyEscape, tea.KeyTab, tea.KeyBackspace, tea.KeySpace, tea.KeyDelete,
+	tea.KeyUp, tea.KeyDown, tea.KeyLeft, tea.KeyRight, tea.KeyHome, tea.KeyEnd,
+	tea.KeyPgUp, tea.KeyPgDown, tea.KeyF1, tea.KeyF12,
+}
+
+// fuzzPrintable are the characters random input types: the letters,
+// digits and punctuation the default key bindings use.
+const fuzzPrintable = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 -_=+[]{}|\\;:'\",.<>/?`~!@#$%^&*()"
+
+// Random returns n random input events for a screen of the given size. The
+// same seed always returns the same events. The prefix key is pressed often,
+// so the prefix and sub-prefix commands are reached.
+func Random(seed uint64, n, width, height int) []Event {
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	events := []Event{{Type: EventResize, Width: width, Height: height}}
+	pressed := false
+	for len(events) < n {
+		var e Event
+		switch r := rng.IntN(100); {
+		case r < 15:
+			// The default prefix key
+			e = Event{Type: EventKey, Code: 'b', Mod: int(tea.ModCtrl)}
+		case r < 50:
+			c := rune(fuzzPrintable[rng.IntN(len(fuzzPrintable))])
+			e = Event{Type: EventKey, Code: c, Text: string(c)}
+		case r < 65:
+			e = Event{Type: EventKey, Code: fuzzSpecialKeys[rng.IntN(len(fuzzSpecialKeys))]}
+			if rng.IntN(4) == 0 {
+				e.Mod = int([]tea.KeyMod{tea.ModCtrl, tea.ModAlt, tea.ModShift}[rng.IntN(3)])
+			}
+		case r < 70:
+			c := rune('a' + rng.IntN(26))
+			e = Event{Type: EventKey, Code: c, Mod: int([]tea.KeyMod{tea.ModCtrl, tea.ModAlt}[rng.IntN(2)])}
+		case r < 90:
+			e = Event{Type: EventClick, X: rng.IntN(width), Y: rng.IntN(height), Button: int(tea.MouseLeft)}
+			switch {
+			case pressed && rng.IntN(2) == 0:
+				e.Type = EventMotion
+			case pressed:
+				e.Type = EventRelease
+				pressed = false
+			case rng.IntN(5) == 0:
+				e.Type = EventWheel
+				e.Button = int([]tea.MouseButton{tea.MouseWheelUp, tea.MouseWheelDown}[rng.IntN(2)])
+			case rng.IntN(6) == 0:
+				e.Button = int(tea.MouseRight)
+			default:
+				pressed = true
+			}
+		case r < 95:
+			e = Event{Type: EventMotion, X: rng.IntN(width), Y: rng.IntN(height)}
+		case r < 98:
+			e = Event{Type: EventPaste, Text: "echo fuzz\nline\ttwo"}
+		default:
+			width, height = 20+rng.IntN(200), 8+rng.IntN(70)
+			e = Event{Type: EventResize, Width: width, Height: height}
+		}
+		events = append(events, e)
+	}
+	return events
+}
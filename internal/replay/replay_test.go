@@ -0,0 +1,112 @@
+package replay
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestEventRoundTrip(t *testing.T) {
+	msgs := []tea.Msg{
+		tea.KeyPressMsg{Code: 'b', Mod: tea.ModCtrl},
+		tea.KeyPressMsg{Code: 'A', Text: "A", ShiftedCode: 'A', BaseCode: 'a', IsRepeat: true},
+		tea.KeyReleaseMsg{Code: tea.KeyEnter},
+		tea.MouseClickMsg{X: 3, Y: 4, Button: tea.MouseLeft},
+		tea.MouseReleaseMsg{X: 5, Y: 6, Button: tea.MouseLeft, Mod: tea.ModShift},
+		tea.MouseWheelMsg{X: 1, Y: 2, Button: tea.MouseWheelDown},
+		tea.MouseMotionMsg{X: 7, Y: 8},
+		tea.PasteMsg{Content: "echo hi\n"},
+		tea.WindowSizeMsg{Width: 100, Height: 30},
+		tea.FocusMsg{},
+		tea.BlurMsg{},
+	}
+
+	var events []Event
+	for _, msg := range msgs {
+		event, ok := FromMsg(msg)
+		if !ok {
+			t.Fatalf("FromMsg(%#v) reported not input", msg)
+		}
+		events = append(events, event)
+	}
+	if _, ok := FromMsg(tea.QuitMsg{}); ok {
+		t.Error("FromMsg(QuitMsg) reported input")
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, events); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(read) != len(msgs) {
+		t.Fatalf("read %d events, want %d", len(read), len(msgs))
+	}
+	for i, event := range read {
+		if got := event.Msg(); !reflect.DeepEqual(got, msgs[i]) {
+			t.Errorf("event %d replays as %#v, want %#v", i, got, msgs[i])
+		}
+	}
+
+	if _, err := Read(bytes.NewBufferString(`{"type":"bogus"}`)); err == nil {
+		t.Error("Read accepted an unknown event type")
+	}
+}
+
+func TestRun(t *testing.T) {
+	events := []Event{
+		{Type: EventResize, Width: 100, Height: 30},
+		{Type: EventKey, Code: 'b', Mod: int(tea.ModCtrl)},
+		{Type: EventKey, Code: 'c', Text: "c"},
+		{Type: EventClick, X: 10, Y: 10, Button: int(tea.MouseLeft)},
+		{Type: EventRelease, X: 10, Y: 10, Button: int(tea.MouseLeft)},
+	}
+	result, err := Run(events, Options{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Crash != nil {
+		t.Fatalf("replay crashed: %v\n%s", result.Crash, result.Crash.Stack)
+	}
+	if result.Events != len(events) {
+		t.Errorf("replayed %d events, want %d", result.Events, len(events))
+	}
+	if result.Windows != 1 {
+		t.Errorf("replay ended with %d windows, want 1", result.Windows)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	a, b := Random(7, 500, 80, 24), Random(7, 500, 80, 24)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("Random returned different events for the same seed")
+	}
+	if len(a) != 500 {
+		t.Errorf("Random returned %d events, want 500", len(a))
+	}
+	for i, event := range a {
+		if event.Msg() == nil {
+			t.Fatalf("event %d has unknown type %q", i, event.Type)
+		}
+	}
+}
+
+// FuzzUpdate replays random input built from the fuzz data and fails on any
+// panic in the Update loop or rendering.
+func FuzzUpdate(f *testing.F) {
+	f.Add(uint64(1), uint16(200))
+	f.Add(uint64(42), uint16(1000))
+	f.Fuzz(func(t *testing.T, seed uint64, n uint16) {
+		result, err := Run(Random(seed, int(n%2000)+1, 120, 40), Options{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if result.Crash != nil {
+			t.Fatalf("%v\n%s", result.Crash, result.Crash.Stack)
+		}
+	})
+}
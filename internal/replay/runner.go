@@ -0,0 +1,128 @@
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"github.com/Gaurav-Gosain/tuios/internal/app"
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/input"
+	"github.com/adrg/xdg"
+)
+
+// Options configures a replay.
+type Options struct {
+	Width  int                // Screen width until the first resize event (default: 120)
+	Height int                // Screen height until the first resize event (default: 40)
+	Config *config.UserConfig // Key bindings to replay with (default: the defaults)
+}
+
+// Crash describes a panic hit while replaying.
+type Crash struct {
+	Index int    // Event that panicked, counting from 0
+	Event Event  // The event itself
+	Value any    // Value the panic was raised with
+	Stack string // Stack trace of the panic
+}
+
+// Error returns a one-line description of the crash.
+func (c *Crash) Error() string {
+	return fmt.Sprintf("panic at event %d (%s): %v", c.Index, c.Event.Type, c.Value)
+}
+
+// Result is the outcome of a replay.
+type Result struct {
+	Events  int    // Events replayed, including the one that crashed
+	Windows int    // Windows open at the end
+	Crash   *Crash // The panic that stopped the replay (nil if none)
+}
+
+// Run replays events through the Update loop of a headless session,
+// rendering a frame after each one. Windows opened during the replay have
+// no process and any files the session writes go to a throwaway directory,
+// so the same events always take the same path. Commands returned by Update
+// are not run.
+func Run(events []Event, opts Options) (Result, error) {
+	restore, err := sandbox()
+	if err != nil {
+		return Result{}, err
+	}
+	defer restore()
+
+	suppressed := config.AnimationsSuppressed
+	config.AnimationsSuppressed = true
+	defer func() { config.AnimationsSuppressed = suppressed }()
+
+	userConfig := opts.Config
+	if userConfig == nil {
+		userConfig = config.DefaultConfig()
+	}
+	app.SetInputHandler(input.HandleInput)
+	m := app.NewOS(app.OSOptions{KeybindRegistry: config.NewKeybindRegistry(userConfig)})
+	m.Headless = true
+	m.Width, m.Height = opts.Width, opts.Height
+	if m.Width <= 0 || m.Height <= 0 {
+		m.Width, m.Height = 120, 40
+	}
+
+	var result Result
+	for i, event := range events {
+		result.Events = i + 1
+		if crash := step(m, i, event); crash != nil {
+			result.Crash = crash
+			break
+		}
+	}
+	result.Windows = len(m.Windows)
+	for _, w := range m.Windows {
+		w.Close()
+	}
+	return result, nil
+}
+
+// step runs one event through Update and renders a frame, returning the
+// panic it raised, if any.
+func step(m *app.OS, index int, event Event) (crash *Crash) {
+	defer func() {
+		if r := recover(); r != nil {
+			crash = &Crash{Index: index, Event: event, Value: r, Stack: string(debug.Stack())}
+		}
+	}()
+	m.Update(event.Msg())
+	_ = m.View()
+	return nil
+}
+
+// sandbox points the XDG directories at a new temporary directory, so a
+// replay can neither read nor change the user's tapes, layouts and state.
+// The returned function restores them and removes the directory.
+func sandbox() (func(), error) {
+	dir, err := os.MkdirTemp("", "tuios-replay-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay directory: %w", err)
+	}
+	vars := []string{"XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_STATE_HOME", "XDG_CACHE_HOME"}
+	saved := make(map[string]*string, len(vars))
+	for _, name := range vars {
+		if value, ok := os.LookupEnv(name); ok {
+			saved[name] = &value
+		} else {
+			saved[name] = nil
+		}
+		_ = os.Setenv(name, filepath.Join(dir, name))
+	}
+	xdg.Reload()
+	return func() {
+		for name, value := range saved {
+			if value == nil {
+				_ = os.Unsetenv(name)
+			} else {
+				_ = os.Setenv(name, *value)
+			}
+		}
+		xdg.Reload()
+		_ = os.RemoveAll(dir)
+	}, nil
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/ssh"
+)
+
+// DefaultAuthorizedKeysPath returns ~/.ssh/authorized_keys.
+func DefaultAuthorizedKeysPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".ssh", "authorized_keys"), nil
+}
+
+// loadAuthorizedKeys parses an authorized_keys file. An empty file is an
+// error, since it would silently reject every client.
+func loadAuthorizedKeys(path string) ([]ssh.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorized keys: %w", err)
+	}
+
+	var keys []ssh.PublicKey
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		key, _, _, _, err := ssh.ParseAuthorizedKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %w", path, i+1, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys found in %s", path)
+	}
+	return keys, nil
+}
+
+// publicKeyHandler accepts clients whose key is listed in the authorized_keys
+// file. The file is read again on every login so keys can be added or revoked
+// without restarting the server.
+func publicKeyHandler(path string) ssh.PublicKeyHandler {
+	return func(ctx ssh.Context, key ssh.PublicKey) bool {
+		keys, err := loadAuthorizedKeys(path)
+		if err != nil {
+			log.Printf("Rejecting %s@%s: %v", ctx.User(), ctx.RemoteAddr(), err)
+			return false
+		}
+		for _, k := range keys {
+			if ssh.KeysEqual(key, k) {
+				return true
+			}
+		}
+		log.Printf("Rejecting %s@%s: public key not authorized", ctx.User(), ctx.RemoteAddr())
+		return false
+	}
+}
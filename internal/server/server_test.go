@@ -1,6 +1,8 @@
 package server
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Gaurav-Gosain/tuios/internal/session"
@@ -514,3 +516,41 @@ func BenchmarkNewSessionPicker(b *testing.B) {
 		NewSessionPicker(sessions)
 	}
 }
+
+// TestLoadAuthorizedKeys tests parsing of authorized_keys files.
+func TestLoadAuthorizedKeys(t *testing.T) {
+	const key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAII/pboSwPVl6SZBqzHFZXVgoZlINIpWAPeqobHLf3E3b test@example"
+
+	tests := []struct {
+		name     string
+		content  string
+		wantKeys int
+		wantErr  bool
+	}{
+		{"single key", key + "\n", 1, false},
+		{"comments and blank lines", "# laptop\n\n" + key + "\n\n", 1, false},
+		{"empty file", "", 0, true},
+		{"only comments", "# nobody yet\n", 0, true},
+		{"malformed line", key + "\nnot-a-key\n", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "authorized_keys")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			keys, err := loadAuthorizedKeys(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadAuthorizedKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(keys) != tt.wantKeys {
+				t.Errorf("got %d keys, want %d", len(keys), tt.wantKeys)
+			}
+		})
+	}
+
+	if _, err := loadAuthorizedKeys(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
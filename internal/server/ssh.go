@@ -29,6 +29,11 @@ type SSHServerConfig struct {
 	DefaultSession string // If set, all connections attach to this session
 	Ephemeral      bool   // If true, don't use daemon (old behavior)
 	Version        string // For daemon handshake
+
+	// AuthorizedKeysPath enables public-key authentication: only clients whose
+	// key is listed in this authorized_keys file may connect. If empty, all
+	// clients are accepted.
+	AuthorizedKeysPath string
 }
 
 // sshServerContext holds the server-wide context for daemon mode
@@ -59,8 +64,7 @@ func StartSSHServer(ctx context.Context, cfg *SSHServerConfig) error {
 		}
 	}
 
-	// Create SSH server with middleware
-	server, err := wish.NewServer(
+	options := []ssh.Option{
 		wish.WithAddress(net.JoinHostPort(cfg.Host, cfg.Port)),
 		wish.WithHostKeyPath(hostKeyPath),
 		wish.WithMiddleware(
@@ -69,7 +73,17 @@ func StartSSHServer(ctx context.Context, cfg *SSHServerConfig) error {
 			// Logging middleware for connection tracking
 			logging.Middleware(),
 		),
-	)
+	}
+	if cfg.AuthorizedKeysPath != "" {
+		// Fail early rather than starting a server nobody can log in to
+		if _, err := loadAuthorizedKeys(cfg.AuthorizedKeysPath); err != nil {
+			return err
+		}
+		options = append(options, wish.WithPublicKeyAuth(publicKeyHandler(cfg.AuthorizedKeysPath)))
+	}
+
+	// Create SSH server with middleware
+	server, err := wish.NewServer(options...)
 	if err != nil {
 		return fmt.Errorf("failed to create SSH server: %w", err)
 	}
@@ -80,7 +94,11 @@ func StartSSHServer(ctx context.Context, cfg *SSHServerConfig) error {
 		if cfg.Ephemeral {
 			mode = "ephemeral"
 		}
-		log.Printf("Starting SSH server on %s (mode: %s)", server.Addr, mode)
+		auth := "none"
+		if cfg.AuthorizedKeysPath != "" {
+			auth = "public key (" + cfg.AuthorizedKeysPath + ")"
+		}
+		log.Printf("Starting SSH server on %s (mode: %s, auth: %s)", server.Addr, mode, auth)
 		if err := server.ListenAndServe(); err != nil {
 			log.Printf("SSH server error: %v", err)
 		}
@@ -17,9 +17,10 @@ import (
 
 // Client connects to the TUIOS daemon and handles terminal I/O.
 type Client struct {
-	conn     net.Conn
-	version  string
-	attached bool
+	conn       net.Conn
+	version    string
+	socketPath string // Socket to connect to instead of the default
+	attached   bool
 
 	// Terminal state
 	width    int
@@ -42,29 +43,39 @@ type Client struct {
 	// Prefix key state for detach detection (Ctrl+B, d)
 	prefixActive bool
 	prefixKey    byte // Default: Ctrl+B (0x02)
+
+	// How long to wait for a response (0 = 30s, negative = no limit)
+	responseTimeout time.Duration
 }
 
 // ClientConfig holds configuration for creating a client.
 type ClientConfig struct {
-	Version    string
-	SocketPath string // Optional override
+	Version         string
+	SocketPath      string        // Optional override
+	ResponseTimeout time.Duration // How long control messages wait for a response (0 = 30s, negative = no limit)
 }
 
 // NewClient creates a new daemon client.
 func NewClient(cfg *ClientConfig) *Client {
 	return &Client{
-		version:   cfg.Version,
-		done:      make(chan struct{}),
-		prefixKey: 0x02,           // Ctrl+B
-		codec:     DefaultCodec(), // gob by default
+		version:         cfg.Version,
+		socketPath:      cfg.SocketPath,
+		done:            make(chan struct{}),
+		prefixKey:       0x02,           // Ctrl+B
+		codec:           DefaultCodec(), // gob by default
+		responseTimeout: cfg.ResponseTimeout,
 	}
 }
 
 // Connect connects to the daemon.
 func (c *Client) Connect() error {
-	socketPath, err := GetSocketPath()
-	if err != nil {
-		return fmt.Errorf("failed to get socket path: %w", err)
+	socketPath := c.socketPath
+	if socketPath == "" {
+		var err error
+		socketPath, err = GetSocketPath()
+		if err != nil {
+			return fmt.Errorf("failed to get socket path: %w", err)
+		}
 	}
 
 	conn, err := net.DialTimeout("unix", socketPath, 5*time.Second)
@@ -598,7 +609,14 @@ func (c *Client) recv() (*Message, error) {
 	c.recvMu.Lock()
 	defer c.recvMu.Unlock()
 
-	_ = c.conn.SetReadDeadline(time.Now().Add(30 * time.Second))
+	switch {
+	case c.responseTimeout < 0:
+		_ = c.conn.SetReadDeadline(time.Time{})
+	case c.responseTimeout > 0:
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.responseTimeout))
+	default:
+		_ = c.conn.SetReadDeadline(time.Now().Add(30 * time.Second))
+	}
 	msg, _, err := ReadMessageWithCodec(c.conn)
 	return msg, err
 }
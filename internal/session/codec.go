@@ -145,6 +145,7 @@ func init() {
 	gob.Register(CreatePTYPayload{})
 	gob.Register(PTYCreatedPayload{})
 	gob.Register(ClosePTYPayload{})
+	gob.Register(MovePTYPayload{})
 	gob.Register(FocusPTYPayload{})
 	gob.Register(InputPayload{})
 	gob.Register(PTYOutputPayload{})
@@ -0,0 +1,69 @@
+package session
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+// NewSetColorsPayload encodes the colors a client gives its terminals. Nil
+// colors are left unset.
+func NewSetColorsPayload(fg, cursor color.Color, palette [16]color.Color, hostFg, hostBg color.Color) *SetColorsPayload {
+	payload := &SetColorsPayload{
+		Foreground:     hexColor(fg),
+		Cursor:         hexColor(cursor),
+		HostForeground: hexColor(hostFg),
+		HostBackground: hexColor(hostBg),
+	}
+	if palette != [16]color.Color{} {
+		payload.Palette = make([]string, len(palette))
+		for i, c := range palette {
+			payload.Palette[i] = hexColor(c)
+		}
+	}
+	return payload
+}
+
+// apply gives an emulator the colors, as the client gives its own.
+func (p *SetColorsPayload) apply(t *vt.Emulator) {
+	var palette [16]color.Color
+	for i := range min(len(p.Palette), len(palette)) {
+		palette[i] = parseHexColor(p.Palette[i])
+	}
+	// The background stays transparent, as on the client
+	t.SetThemeColors(parseHexColor(p.Foreground), nil, parseHexColor(p.Cursor), palette)
+	t.SetHostColors(parseHexColor(p.HostForeground), parseHexColor(p.HostBackground))
+}
+
+// SetColors sets the colors the session's terminal emulators report to
+// applications, for its PTYs and those created later.
+func (s *Session) SetColors(colors *SetColorsPayload) {
+	s.ptysMu.Lock()
+	defer s.ptysMu.Unlock()
+	s.colors = colors
+	for _, pty := range s.ptys {
+		pty.terminalMu.Lock()
+		if pty.terminal != nil {
+			colors.apply(pty.terminal)
+		}
+		pty.terminalMu.Unlock()
+	}
+}
+
+func hexColor(c color.Color) string {
+	if c == nil {
+		return ""
+	}
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+func parseHexColor(s string) color.Color {
+	if s == "" {
+		return nil
+	}
+	return ansi.XParseColor(s)
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
@@ -57,6 +58,14 @@ type connState struct {
 	// TUI clients can receive and execute remote commands
 	isTUIClient bool
 
+	// readOnly clients mirror the session: the daemon drops their input and
+	// state changes and never asks them to execute remote commands
+	readOnly bool
+
+	// lastSized is the last time the client typed or resized, used by the
+	// "latest" window size policy.
+	lastSized time.Time
+
 	// Client terminal dimensions (for multi-client size calculation)
 	width  int
 	height int
@@ -301,6 +310,11 @@ func (d *Daemon) handleConnection(conn net.Conn) {
 }
 
 func (d *Daemon) handleMessage(cs *connState, msg *Message) error {
+	if cs.readOnly && isMutatingMessage(msg.Type) {
+		debugLog("[DEBUG] Dropping message type %d from read-only client %s", msg.Type, cs.clientID)
+		return nil
+	}
+
 	switch msg.Type {
 	case MsgHello:
 		return d.handleHello(cs, msg)
@@ -354,11 +368,26 @@ func (d *Daemon) handleMessage(cs *connState, msg *Message) error {
 		return d.handleWindowListResponse(cs, msg)
 	case MsgSessionInfo:
 		return d.handleSessionInfoResponse(cs, msg)
+	case MsgMovePTY:
+		return d.handleMovePTY(cs, msg)
+	case MsgSetColors:
+		return d.handleSetColors(cs, msg)
 	default:
 		return fmt.Errorf("unknown message type: %d", msg.Type)
 	}
 }
 
+// isMutatingMessage reports whether a message changes the session and must be
+// dropped when it comes from a read-only client.
+func isMutatingMessage(t MessageType) bool {
+	switch t {
+	case MsgInput, MsgKill, MsgCreatePTY, MsgClosePTY, MsgUpdateState, MsgMovePTY, MsgSetColors:
+		return true
+	default:
+		return false
+	}
+}
+
 func (d *Daemon) handleHello(cs *connState, msg *Message) error {
 	var payload HelloPayload
 	if err := msg.ParsePayloadWithCodec(&payload, cs.codec); err != nil {
@@ -433,14 +462,22 @@ func (d *Daemon) handleAttach(cs *connState, msg *Message) error {
 	// Don't store client dimensions yet - the attach payload has placeholder values (80x24).
 	// The real terminal size will be sent via NotifyTerminalSize after Bubble Tea starts.
 	// Setting width/height to 0 excludes this client from calculateEffectiveSize until then.
+	// The fields are read by calculateEffectiveSize under clientsMu
+	d.clientsMu.Lock()
 	cs.width = 0
 	cs.height = 0
 	// Mark as TUI client if they have PTY subscriptions or if they're creating a new session
 	// TUI clients are the ones that can receive and execute remote commands
 	cs.isTUIClient = true
+	cs.readOnly = payload.ReadOnly
+	d.clientsMu.Unlock()
 
 	clientCount := d.getSessionClientCount(session.ID)
-	log.Printf("Client %s attached to session %s (TUI client, %d clients total)", cs.clientID, session.Name, clientCount)
+	kind := "TUI client"
+	if cs.readOnly {
+		kind = "read-only TUI client"
+	}
+	log.Printf("Client %s attached to session %s (%s, %d clients total)", cs.clientID, session.Name, kind, clientCount)
 
 	// Calculate effective size from existing clients (new client excluded since width/height = 0)
 	effectiveWidth, effectiveHeight := d.calculateEffectiveSize(session.ID)
@@ -516,9 +553,11 @@ func (d *Daemon) handleDetach(cs *connState) error {
 		}
 	}
 	cs.ptySubscriptions = make(map[string]struct{})
+	d.clientsMu.Lock()
 	cs.sessionID = ""
 	cs.width = 0
 	cs.height = 0
+	d.clientsMu.Unlock()
 
 	// Notify other clients that this client left
 	d.notifyClientLeft(sessionID, clientID)
@@ -598,6 +637,18 @@ func (d *Daemon) handleInput(cs *connState, msg *Message) error {
 		data = payload.Data
 	}
 
+	// Under the "latest" policy the typing client takes over the session size.
+	// Keystrokes of the client already setting the size change nothing.
+	if GetWindowSizePolicy() == WindowSizeLatest {
+		d.clientsMu.Lock()
+		takesOver := cs.width > 0 && cs.height > 0 && d.latestSizedClient(cs.sessionID) != cs
+		cs.lastSized = time.Now()
+		d.clientsMu.Unlock()
+		if takesOver {
+			d.recalculateAndBroadcastSize(cs.sessionID)
+		}
+	}
+
 	if ptyID != "" {
 		if pty := session.GetPTY(ptyID); pty != nil {
 			debugLog("[DEBUG] Writing %d bytes to PTY %s", len(data), ptyID[:8])
@@ -628,11 +679,14 @@ func (d *Daemon) handleResize(cs *connState, msg *Message) error {
 	// Update client dimensions for multi-client size calculation
 	if payload.PTYID == "" {
 		// This is a client resize, not a PTY-specific resize
+		d.clientsMu.Lock()
 		cs.width = payload.Width
 		cs.height = payload.Height
+		cs.lastSized = time.Now()
+		d.clientsMu.Unlock()
 		// Recalculate effective session size
 		d.recalculateAndBroadcastSize(cs.sessionID)
-	} else {
+	} else if !cs.readOnly {
 		// PTY-specific resize
 		if pty := session.GetPTY(payload.PTYID); pty != nil {
 			_ = pty.Resize(payload.Width, payload.Height)
@@ -673,7 +727,13 @@ func (d *Daemon) handleCreatePTY(cs *connState, msg *Message) error {
 	}
 
 	debugLog("[DEBUG] Creating PTY %dx%d for session %s", width, height, session.Name)
-	pty, err := session.CreatePTY(width, height)
+	pty, err := session.CreateCommandPTY(width, height, PTYOptions{
+		Command:        payload.Command,
+		Dir:            payload.Dir,
+		Env:            payload.Env,
+		AmbiguousWidth: payload.AmbiguousWidth,
+		EmojiWidth:     payload.EmojiWidth,
+	})
 	if err != nil {
 		debugLog("[DEBUG] handleCreatePTY: failed to create PTY: %v", err)
 		return d.sendError(cs, ErrCodeInternal, fmt.Sprintf("failed to create PTY: %v", err))
@@ -689,6 +749,9 @@ func (d *Daemon) handleCreatePTY(cs *connState, msg *Message) error {
 	pty.SetOnExit(func(ptyID string) {
 		d.notifyPTYClosed(sessionID, ptyID)
 	})
+	pty.SetOnSecureInput(func(ptyID string, active bool) {
+		d.notifyPTYSecureInput(sessionID, ptyID, active)
+	})
 
 	debugLog("[DEBUG] PTY created: %s", pty.ID)
 	return d.sendMessage(cs, MsgPTYCreated, &PTYCreatedPayload{
@@ -722,6 +785,69 @@ func (d *Daemon) handleClosePTY(cs *connState, msg *Message) error {
 	return d.sendMessage(cs, MsgPTYClosed, &ClosePTYPayload{PTYID: payload.PTYID})
 }
 
+// handleMovePTY moves a PTY of the client's session, with its window, to
+// another session. Clients of the old session stop receiving its output,
+// and a TUI attached to the new session is asked to open the window; a
+// session without one shows it when next attached.
+func (d *Daemon) handleMovePTY(cs *connState, msg *Message) error {
+	if cs.sessionID == "" {
+		return d.sendError(cs, ErrCodeNotAttached, "not attached to any session")
+	}
+
+	source := d.manager.GetSessionByID(cs.sessionID)
+	if source == nil {
+		return d.sendError(cs, ErrCodeSessionNotFound, "session not found")
+	}
+
+	var payload MovePTYPayload
+	if err := msg.ParsePayloadWithCodec(&payload, cs.codec); err != nil {
+		return fmt.Errorf("invalid move PTY payload: %w", err)
+	}
+
+	target := d.manager.GetSession(payload.TargetSession)
+	if target == nil {
+		return d.sendError(cs, ErrCodeSessionNotFound, fmt.Sprintf("session %s not found", payload.TargetSession))
+	}
+
+	pty, err := source.MovePTY(payload.PTYID, target, payload.Window)
+	if err != nil {
+		return d.sendError(cs, ErrCodePTYNotFound, err.Error())
+	}
+	LogBasic("Moved PTY %s from session %s to %s", payload.PTYID, source.Name, target.Name)
+
+	// Stop streaming to the old session's clients
+	delete(cs.ptySubscriptions, payload.PTYID)
+	d.clientsMu.RLock()
+	for _, client := range d.clients {
+		if client.sessionID == source.ID {
+			pty.Unsubscribe(client.clientID)
+		}
+	}
+	d.clientsMu.RUnlock()
+
+	targetID := target.ID
+	pty.SetOnExit(func(ptyID string) {
+		d.notifyPTYClosed(targetID, ptyID)
+	})
+	pty.SetOnSecureInput(func(ptyID string, active bool) {
+		d.notifyPTYSecureInput(targetID, ptyID, active)
+	})
+
+	if err := d.sendMessage(cs, MsgPTYMoved, &ClosePTYPayload{PTYID: payload.PTYID}); err != nil {
+		return err
+	}
+
+	if tuiClient := d.findTUIClient(target.ID); tuiClient != nil {
+		window := payload.Window
+		window.PTYID = payload.PTYID
+		return d.sendMessage(tuiClient, MsgRemoteCommand, &RemoteCommandPayload{
+			CommandType: "adopt_window",
+			Window:      &window,
+		})
+	}
+	return nil
+}
+
 func (d *Daemon) handleListPTYs(cs *connState) error {
 	if cs.sessionID == "" {
 		return d.sendError(cs, ErrCodeNotAttached, "not attached to any session")
@@ -739,8 +865,11 @@ func (d *Daemon) handleListPTYs(cs *connState) error {
 		pty := session.GetPTY(id)
 		if pty != nil {
 			ptys = append(ptys, PTYInfo{
-				ID:     pty.ID,
-				Exited: pty.IsExited(),
+				ID:      pty.ID,
+				Exited:  pty.IsExited(),
+				Command: pty.ForegroundCommand(),
+				Cwd:     pty.WorkingDirectory(),
+				PID:     pty.ShellPID(),
 			})
 		}
 	}
@@ -883,6 +1012,13 @@ func (d *Daemon) streamPTYOutput(cs *connState, pty *PTY) {
 	debugLog("[DEBUG] streamPTYOutput started for PTY %s, client %s", pty.ID[:8], cs.clientID)
 	outputCh := pty.Subscribe(cs.clientID)
 	debugLog("[DEBUG] Subscribed to PTY output channel")
+	// A new subscriber starts out with secure input off; later changes are
+	// sent by notifyPTYSecureInput
+	if pty.SecureInput() {
+		if err := d.sendMessage(cs, MsgPTYSecureInput, &PTYSecureInputPayload{PTYID: pty.ID, Active: true}); err != nil {
+			debugLog("[DEBUG] streamPTYOutput: failed to send secure input: %v", err)
+		}
+	}
 
 	for {
 		select {
@@ -946,6 +1082,29 @@ func (d *Daemon) notifyPTYClosed(sessionID, ptyID string) {
 	}
 }
 
+// notifyPTYSecureInput sends MsgPTYSecureInput to all clients subscribed to
+// the given PTY when its program starts or stops reading a password.
+func (d *Daemon) notifyPTYSecureInput(sessionID, ptyID string, active bool) {
+	d.clientsMu.RLock()
+	defer d.clientsMu.RUnlock()
+
+	for _, cs := range d.clients {
+		if cs.sessionID != sessionID {
+			continue
+		}
+		if _, subscribed := cs.ptySubscriptions[ptyID]; !subscribed {
+			continue
+		}
+		d.wg.Add(1)
+		go func(client *connState) {
+			defer d.wg.Done()
+			if err := d.sendMessage(client, MsgPTYSecureInput, &PTYSecureInputPayload{PTYID: ptyID, Active: active}); err != nil {
+				debugLog("[DEBUG] notifyPTYSecureInput: failed to send to client: %v", err)
+			}
+		}(cs)
+	}
+}
+
 func (d *Daemon) sendMessage(cs *connState, msgType MessageType, payload any) error {
 	msg, err := NewMessageWithCodec(msgType, payload, cs.codec)
 	if err != nil {
@@ -1008,20 +1167,45 @@ func (d *Daemon) getSessionClientCount(sessionID string) int {
 	return count
 }
 
-// calculateEffectiveSize returns the minimum dimensions across all clients in a session.
-// This is used for multi-client rendering where all clients need to see the same content.
+// sizingClient reports whether a client counts toward the size of a session:
+// a TUI client of the session, not read-only, whose terminal size is known.
+// Read-only clients only watch, so they never shrink the session for the
+// clients typing in it. The caller holds clientsMu.
+func sizingClient(cs *connState, sessionID string) bool {
+	return cs.sessionID == sessionID && cs.isTUIClient && !cs.readOnly && cs.width > 0 && cs.height > 0
+}
+
+// latestSizedClient returns the client that most recently typed or resized
+// among those sizing a session, or nil. The caller holds clientsMu.
+func (d *Daemon) latestSizedClient(sessionID string) *connState {
+	var latest *connState
+	for _, cs := range d.clients {
+		if sizingClient(cs, sessionID) && (latest == nil || cs.lastSized.After(latest.lastSized)) {
+			latest = cs
+		}
+	}
+	return latest
+}
+
+// calculateEffectiveSize returns the session dimensions for multi-client rendering.
+// With the default "smallest" policy this is the minimum across all clients so
+// every client sees the same content; with "latest" it is the size of the client
+// that most recently typed or resized. Read-only clients are left out.
 func (d *Daemon) calculateEffectiveSize(sessionID string) (width, height int) {
 	d.clientsMu.RLock()
 	defer d.clientsMu.RUnlock()
 
+	if GetWindowSizePolicy() == WindowSizeLatest {
+		if cs := d.latestSizedClient(sessionID); cs != nil {
+			return cs.width, cs.height
+		}
+		return 0, 0
+	}
+
 	width, height = 0, 0
 	first := true
-
 	for _, cs := range d.clients {
-		if cs.sessionID != sessionID || !cs.isTUIClient {
-			continue
-		}
-		if cs.width == 0 || cs.height == 0 {
+		if !sizingClient(cs, sessionID) {
 			continue
 		}
 		if first {
@@ -1095,7 +1279,7 @@ func (d *Daemon) recalculateAndBroadcastSize(sessionID string) {
 			ClientCount: d.getSessionClientCount(sessionID),
 		}
 		d.broadcastToSession(sessionID, MsgSessionResize, payload, "")
-		LogBasic("Session %s resized to %dx%d (min of %d clients)", session.Name, newWidth, newHeight, payload.ClientCount)
+		LogBasic("Session %s resized to %dx%d (%s of %d clients)", session.Name, newWidth, newHeight, GetWindowSizePolicy(), payload.ClientCount)
 	}
 }
 
@@ -1166,6 +1350,8 @@ func (d *Daemon) handleExecuteCommand(cs *connState, msg *Message) error {
 			CommandType: "tape_command",
 			TapeCommand: payload.CommandType,
 			TapeArgs:    payload.Args,
+			SourcePTYID: session.PTYForProcess(payload.CallerPID),
+			CallerPID:   payload.CallerPID,
 		}
 	}
 
@@ -1228,6 +1414,21 @@ func (d *Daemon) handleSendKeys(cs *connState, msg *Message) error {
 }
 
 // handleSetConfig routes a config change to the TUI client attached to the session.
+// handleSetColors gives the PTYs of the client's session the colors of its
+// terminals, so color queries are answered with them.
+func (d *Daemon) handleSetColors(cs *connState, msg *Message) error {
+	var payload SetColorsPayload
+	if err := msg.ParsePayloadWithCodec(&payload, cs.codec); err != nil {
+		return fmt.Errorf("invalid set colors payload: %w", err)
+	}
+	session := d.manager.GetSessionByID(cs.sessionID)
+	if session == nil {
+		return d.sendError(cs, ErrCodeNotAttached, "not attached to any session")
+	}
+	session.SetColors(&payload)
+	return nil
+}
+
 func (d *Daemon) handleSetConfig(cs *connState, msg *Message) error {
 	var payload SetConfigPayload
 	if err := msg.ParsePayloadWithCodec(&payload, cs.codec); err != nil {
@@ -1328,13 +1529,14 @@ func (d *Daemon) findTargetSession(sessionName string) *Session {
 	return mostRecent
 }
 
-// findTUIClient finds the TUI client attached to a session.
+// findTUIClient finds a TUI client attached to a session that can execute
+// commands. Read-only clients are skipped.
 func (d *Daemon) findTUIClient(sessionID string) *connState {
 	d.clientsMu.RLock()
 	defer d.clientsMu.RUnlock()
 
 	for _, cs := range d.clients {
-		if cs.sessionID == sessionID && cs.isTUIClient {
+		if cs.sessionID == sessionID && cs.isTUIClient && !cs.readOnly {
 			return cs
 		}
 	}
@@ -1389,6 +1591,9 @@ func (d *Daemon) handleQueryWindows(cs *connState, msg *Message) error {
 	// Build window list from state
 	windows := make([]map[string]any, 0, len(state.Windows))
 	for i, w := range state.Windows {
+		if payload.Tag != "" && !slices.Contains(w.Tags, payload.Tag) {
+			continue
+		}
 		displayName := w.Title
 		if w.CustomName != "" {
 			displayName = w.CustomName
@@ -1410,6 +1615,9 @@ func (d *Daemon) handleQueryWindows(cs *connState, msg *Message) error {
 		if w.CustomName != "" {
 			winInfo["custom_name"] = w.CustomName
 		}
+		if len(w.Tags) > 0 {
+			winInfo["tags"] = w.Tags
+		}
 		windows = append(windows, winInfo)
 	}
 
@@ -1432,7 +1640,7 @@ func (d *Daemon) handleQueryWindows(cs *connState, msg *Message) error {
 
 	resultData := map[string]any{
 		"windows":           windows,
-		"total":             len(state.Windows),
+		"total":             len(windows),
 		"focused_index":     focusedIndex,
 		"focused_window_id": state.FocusedWindowID,
 		"current_workspace": state.CurrentWorkspace,
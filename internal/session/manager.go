@@ -63,6 +63,7 @@ func (m *Manager) CreateSession(name string, cfg *SessionConfig, width, height i
 
 	// If no name was provided, one was auto-generated
 	name = session.Name
+	session.socketPath = m.SocketPath()
 
 	// Register the session
 	m.sessions[name] = session
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// PTYForProcess returns the ID of the session's PTY whose shell is pid or
+// one of its ancestors, or "" when pid is not running inside the session.
+func (s *Session) PTYForProcess(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+
+	shells := make(map[int32]string)
+	s.ptysMu.RLock()
+	for id, p := range s.ptys {
+		if p.cmd != nil && p.cmd.Process != nil {
+			shells[int32(p.cmd.Process.Pid)] = id
+		}
+	}
+	s.ptysMu.RUnlock()
+	if len(shells) == 0 {
+		return ""
+	}
+
+	// Walk up the process tree until a shell of the session is found
+	for current := int32(pid); current > 1; {
+		if id, ok := shells[current]; ok {
+			return id
+		}
+		proc, err := process.NewProcess(current)
+		if err != nil {
+			return ""
+		}
+		parent, err := proc.Ppid()
+		if err != nil || parent == current {
+			return ""
+		}
+		current = parent
+	}
+	return ""
+}
+
+// ForegroundCommand returns the arguments of the newest process started by
+// the PTY's shell, taken to be the command running in it, or nil when the
+// shell runs nothing or the process cannot be inspected.
+func (p *PTY) ForegroundCommand() []string {
+	if p.cmd == nil || p.cmd.Process == nil {
+		return nil
+	}
+	shell, err := process.NewProcess(int32(p.cmd.Process.Pid))
+	if err != nil {
+		return nil
+	}
+	children, err := shell.Children()
+	if err != nil || len(children) == 0 {
+		return nil
+	}
+
+	newest, newestTime := children[0], int64(0)
+	for _, child := range children {
+		if created, err := child.CreateTime(); err == nil && created > newestTime {
+			newest, newestTime = child, created
+		}
+	}
+	args, err := newest.CmdlineSlice()
+	if err != nil || len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+// ShellPID returns the process ID of the PTY's shell, or 0 when it has not
+// started.
+func (p *PTY) ShellPID() int {
+	if p.cmd == nil || p.cmd.Process == nil {
+		return 0
+	}
+	return p.cmd.Process.Pid
+}
+
+// WorkingDirectory returns the working directory of the PTY's shell, or ""
+// when it cannot be inspected.
+func (p *PTY) WorkingDirectory() string {
+	if p.cmd == nil || p.cmd.Process == nil {
+		return ""
+	}
+	shell, err := process.NewProcess(int32(p.cmd.Process.Pid))
+	if err != nil {
+		return ""
+	}
+	cwd, err := shell.Cwd()
+	if err != nil {
+		return ""
+	}
+	return cwd
+}
@@ -68,6 +68,16 @@ const (
 	MsgSessionResize   // Session effective size changed (min of all clients)
 	MsgForceRefresh    // Force all clients to re-render
 	MsgRequestFullSync // Client requests full state sync from leader
+
+	// Window teleport messages
+	MsgMovePTY  // Move a PTY and its window to another session
+	MsgPTYMoved // Confirms a PTY was moved
+
+	// Secure input messages
+	MsgPTYSecureInput // A PTY's program turned echo off for a password, or back on
+
+	// Color messages
+	MsgSetColors // Client's theme and host colors, for answering color queries
 )
 
 // Message is the base protocol message structure.
@@ -112,6 +122,7 @@ type AttachPayload struct {
 	CreateNew   bool   `json:"create_new,omitempty"` // Create if doesn't exist
 	Width       int    `json:"width"`                // Client terminal width
 	Height      int    `json:"height"`               // Client terminal height
+	ReadOnly    bool   `json:"read_only,omitempty"`  // Mirror the session without sending input
 }
 
 // AttachedPayload confirms successful session attachment.
@@ -169,11 +180,14 @@ type ErrorPayload struct {
 
 // PTYInfo describes a single PTY.
 type PTYInfo struct {
-	ID     string `json:"id"`
-	Title  string `json:"title"`
-	Width  int    `json:"width"`
-	Height int    `json:"height"`
-	Exited bool   `json:"exited"`
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Width   int      `json:"width"`
+	Height  int      `json:"height"`
+	Exited  bool     `json:"exited"`
+	Command []string `json:"command,omitempty"` // Command running in the PTY's shell
+	Cwd     string   `json:"cwd,omitempty"`     // Working directory of the PTY's shell
+	PID     int      `json:"pid,omitempty"`     // Process ID of the PTY's shell
 }
 
 // PTYListPayload contains list of PTYs in a session.
@@ -183,9 +197,16 @@ type PTYListPayload struct {
 
 // CreatePTYPayload requests creation of a new PTY.
 type CreatePTYPayload struct {
-	Title  string `json:"title,omitempty"`
-	Width  int    `json:"width,omitempty"`
-	Height int    `json:"height,omitempty"`
+	Title  string   `json:"title,omitempty"`
+	Width  int      `json:"width,omitempty"`
+	Height int      `json:"height,omitempty"`
+	Env    []string `json:"env,omitempty"` // Extra "NAME=value" variables for the shell
+	// Command runs instead of the shell when set, in Dir when that is set
+	Command []string `json:"command,omitempty"`
+	Dir     string   `json:"dir,omitempty"`
+	// Width options of the PTY's emulator (see vt.Emulator.SetWidthOptions)
+	AmbiguousWidth int `json:"ambiguous_width,omitempty"`
+	EmojiWidth     int `json:"emoji_width,omitempty"`
 }
 
 // PTYCreatedPayload confirms PTY creation.
@@ -199,6 +220,21 @@ type ClosePTYPayload struct {
 	PTYID string `json:"pty_id"`
 }
 
+// PTYSecureInputPayload reports whether the program of a PTY reads a
+// password: it turned echo off while keeping line editing on.
+type PTYSecureInputPayload struct {
+	PTYID  string `json:"pty_id"`
+	Active bool   `json:"active"`
+}
+
+// MovePTYPayload requests moving a PTY of the client's session, with the
+// window showing it, to another session of the same daemon.
+type MovePTYPayload struct {
+	PTYID         string      `json:"pty_id"`
+	TargetSession string      `json:"target_session"`
+	Window        WindowState `json:"window"` // State of the window being moved
+}
+
 // FocusPTYPayload requests focus on a PTY.
 type FocusPTYPayload struct {
 	PTYID string `json:"pty_id"`
@@ -255,6 +291,7 @@ type ExecuteCommandPayload struct {
 	Args        []string `json:"args,omitempty"`         // Command arguments
 	TapeScript  string   `json:"tape_script,omitempty"`  // Raw tape script to execute (alternative to CommandType)
 	RequestID   string   `json:"request_id,omitempty"`   // Optional ID for matching responses
+	CallerPID   int      `json:"caller_pid,omitempty"`   // PID of the requesting process, to find the window it runs in
 }
 
 // SendKeysPayload requests sending keystrokes to a session.
@@ -274,6 +311,17 @@ type SetConfigPayload struct {
 	RequestID   string `json:"request_id,omitempty"`   // Optional ID for matching responses
 }
 
+// SetColorsPayload tells the daemon the colors the client gives its
+// terminals, so the daemon's emulators answer OSC 4, 10 and 11 color queries
+// the same way. Colors are "#rrggbb", empty when unset.
+type SetColorsPayload struct {
+	Foreground     string   `json:"foreground,omitempty"`      // Theme foreground
+	Cursor         string   `json:"cursor,omitempty"`          // Theme cursor color
+	Palette        []string `json:"palette,omitempty"`         // Theme's first 16 indexed colors
+	HostForeground string   `json:"host_foreground,omitempty"` // Host terminal foreground
+	HostBackground string   `json:"host_background,omitempty"` // Background windows are drawn over
+}
+
 // CommandResultPayload contains the result of a remote command execution.
 type CommandResultPayload struct {
 	RequestID string         `json:"request_id,omitempty"` // Matches the request
@@ -295,6 +343,10 @@ type RemoteCommandPayload struct {
 	Raw         bool     `json:"raw,omitempty"`          // For send_keys (no splitting)
 	ConfigPath  string   `json:"config_path,omitempty"`  // For set_config
 	ConfigValue string   `json:"config_value,omitempty"` // For set_config
+	SourcePTYID string   `json:"source_pty,omitempty"`   // PTY of the window the request was made from
+	CallerPID   int      `json:"caller_pid,omitempty"`   // PID of the requesting process
+
+	Window *WindowState `json:"window,omitempty"` // For adopt_window (window moved in from another session)
 }
 
 // GetLogsPayload requests log entries from the daemon.
@@ -312,6 +364,7 @@ type LogsDataPayload struct {
 type QueryWindowsPayload struct {
 	SessionName string `json:"session_name,omitempty"` // Target session (empty = most recently active)
 	RequestID   string `json:"request_id,omitempty"`
+	Tag         string `json:"tag,omitempty"` // Only list the windows with this tag
 }
 
 // WindowInfo contains detailed information about a single window.
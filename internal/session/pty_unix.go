@@ -7,6 +7,7 @@ import (
 	"syscall"
 	"unsafe"
 
+	"github.com/charmbracelet/x/termios"
 	"golang.org/x/sys/unix"
 )
 
@@ -46,3 +47,16 @@ func (p *PTY) SetPixelSize(cols, rows, xpixel, ypixel int) error {
 	}
 	return nil
 }
+
+// echoOff reports whether the PTY has echo off while line editing is on, as
+// password prompts set it. Full screen programs turn both off.
+func (p *PTY) echoOff() bool {
+	if p.pty == nil {
+		return false
+	}
+	t, err := termios.GetTermios(int(p.pty.Fd()))
+	if err != nil {
+		return false
+	}
+	return t.Lflag&unix.ECHO == 0 && t.Lflag&unix.ICANON != 0
+}
@@ -17,3 +17,9 @@ func configurePTYCommand(cmd *exec.Cmd) {
 func (p *PTY) SetPixelSize(cols, rows, xpixel, ypixel int) error {
 	return nil
 }
+
+// echoOff reports whether the PTY has echo off. ConPTY does not tell, so it
+// is always false on Windows.
+func (p *PTY) echoOff() bool {
+	return false
+}
@@ -9,7 +9,9 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	uv "github.com/charmbracelet/ultraviolet"
@@ -17,6 +19,8 @@ import (
 	xpty "github.com/charmbracelet/x/xpty"
 	"github.com/google/uuid"
 
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/system"
 	"github.com/Gaurav-Gosain/tuios/internal/vt"
 )
 
@@ -37,6 +41,8 @@ type WindowState struct {
 	ID           string `json:"id"`
 	Title        string `json:"title"`
 	CustomName   string `json:"custom_name,omitempty"`
+	Group        string `json:"group,omitempty"`
+	SwallowedBy  string `json:"swallowed_by,omitempty"`
 	X            int    `json:"x"`
 	Y            int    `json:"y"`
 	Width        int    `json:"width"`
@@ -48,8 +54,43 @@ type WindowState struct {
 	PreMinimizeY int    `json:"pre_minimize_y,omitempty"`
 	PreMinimizeW int    `json:"pre_minimize_w,omitempty"`
 	PreMinimizeH int    `json:"pre_minimize_h,omitempty"`
+	Floating     bool   `json:"floating,omitempty"` // Kept out of the tiling layout
+	FloatX       int    `json:"float_x,omitempty"`  // Last floating geometry
+	FloatY       int    `json:"float_y,omitempty"`
+	FloatW       int    `json:"float_w,omitempty"`
+	FloatH       int    `json:"float_h,omitempty"`
+	PiP          bool   `json:"pip,omitempty"`       // Shown as a picture-in-picture miniature
+	PrePiPX      int    `json:"pre_pip_x,omitempty"` // Geometry before picture-in-picture
+	PrePiPY      int    `json:"pre_pip_y,omitempty"`
+	PrePiPW      int    `json:"pre_pip_w,omitempty"`
+	PrePiPH      int    `json:"pre_pip_h,omitempty"`
+	Zoomed       bool   `json:"zoomed,omitempty"`        // Content drawn at double cell size
 	PTYID        string `json:"pty_id"`                  // Reference to daemon-managed PTY
 	IsAltScreen  bool   `json:"is_alt_screen,omitempty"` // Alternate screen buffer active (for mouse forwarding)
+	Cwd          string `json:"cwd,omitempty"`           // Shell working directory (saved for crash recovery)
+	Scrollback   *int   `json:"scrollback,omitempty"`    // Scrollback line limit set for this window (nil = rules or global limit)
+	Clipboard    string `json:"clipboard,omitempty"`     // OSC 52 clipboard write policy set for this window
+	MinWidth     int    `json:"min_width,omitempty"`     // Minimum terminal size pinned for this window
+	MinHeight    int    `json:"min_height,omitempty"`
+	Command      string `json:"command,omitempty"` // Executable the window was started with
+	Icon         string `json:"icon,omitempty"`    // Icon set over IPC in place of the program's icon
+	Run          string `json:"run,omitempty"`     // Command line running in the window (session bundles only)
+
+	Tags     []string `json:"tags,omitempty"`      // Tags set on the window
+	RuleName string   `json:"rule_name,omitempty"` // Name a naming rule gave the window
+
+	Panes      []PaneState        `json:"panes,omitempty"`       // Panes of a split window, its own terminal among them
+	PaneTree   *SerializedBSPNode `json:"pane_tree,omitempty"`   // Layout of the panes
+	ActivePane int                `json:"active_pane,omitempty"` // Leaf ID of the pane that receives input
+}
+
+// PaneState represents one pane of a split window. The window's own terminal
+// is the pane whose WindowID is the window's ID.
+type PaneState struct {
+	ID       int    `json:"id"` // Leaf ID in the pane tree
+	WindowID string `json:"window_id"`
+	Title    string `json:"title"`
+	PTYID    string `json:"pty_id"`
 }
 
 // SerializedBSPNode represents a BSP tree node for serialization
@@ -85,7 +126,38 @@ type SessionState struct {
 	WorkspaceTrees  map[int]*SerializedBSPTree `json:"workspace_trees,omitempty"`  // BSP tree per workspace
 	WindowToBSPID   map[string]int             `json:"window_to_bsp_id,omitempty"` // Window UUID -> BSP int ID
 	NextBSPWindowID int                        `json:"next_bsp_window_id,omitempty"`
-	TilingScheme    int                        `json:"tiling_scheme,omitempty"` // Default auto-insertion scheme
+	TilingScheme    int                        `json:"tiling_scheme,omitempty"`    // Default auto-insertion scheme
+	WorkspaceTiling map[int]bool               `json:"workspace_tiling,omitempty"` // Workspaces in tiling mode (nil in older states, where AutoTiling applied to all)
+	// Window marks: mark letter -> window ID
+	Marks map[string]string `json:"marks,omitempty"`
+	// Workspace names: workspace -> name
+	WorkspaceNames map[int]string `json:"workspace_names,omitempty"`
+	// Workspaces already filled from their workspace template, or not filled
+	// because they had windows the first time they were switched to
+	PopulatedWorkspaces []int `json:"populated_workspaces,omitempty"`
+	// Tag view shown, so leaving it after a reattach puts the windows back
+	TagView *TagViewState `json:"tag_view,omitempty"`
+}
+
+// TagViewState is a tag view: the windows with a tag gathered onto an empty
+// workspace, with what it changed to put back when the view is left.
+type TagViewState struct {
+	Tag       string                   `json:"tag"`               // Tag whose windows are shown
+	Workspace int                      `json:"workspace"`         // Empty workspace borrowed to show them
+	Origin    int                      `json:"origin"`            // Workspace active before the view
+	Name      string                   `json:"name,omitempty"`    // Name the borrowed workspace had
+	Tiling    bool                     `json:"tiling,omitempty"`  // Tiling mode the borrowed workspace had
+	Windows   map[string]TagViewOrigin `json:"windows,omitempty"` // Window ID -> where the window came from
+}
+
+// TagViewOrigin is where a window shown in a tag view came from.
+type TagViewOrigin struct {
+	Workspace int  `json:"workspace"`
+	Minimized bool `json:"minimized,omitempty"`
+	X         int  `json:"x"`
+	Y         int  `json:"y"`
+	Width     int  `json:"width"`
+	Height    int  `json:"height"`
 }
 
 // PTY represents a daemon-managed pseudo-terminal.
@@ -115,11 +187,23 @@ type PTY struct {
 	exited   bool
 	exitedMu sync.RWMutex
 	exitCode int
+	done     chan struct{} // Closed once the shell has been reaped
 
 	// Callback when PTY process exits - used by daemon to notify clients
 	onExit func(ptyID string)
+
+	// Whether the program reads a password, sampled every
+	// secureInputInterval, and the callback told when it changes
+	secureInput   atomic.Bool
+	onSecureInput func(ptyID string, active bool) // Guarded by subscribersMu
+	closeMu       sync.RWMutex                    // Held to close the PTY, and read while its echo is sampled
 }
 
+// secureInputInterval is how often the PTYs' echo setting is sampled.
+// Programs such as read -s turn echo off without printing anything, so it is
+// sampled on a timer rather than when output arrives.
+const secureInputInterval = 100 * time.Millisecond
+
 // Session represents a persistent TUIOS session.
 // The daemon manages PTYs and stores state; the client runs the TUI.
 type Session struct {
@@ -130,6 +214,7 @@ type Session struct {
 	// PTYs managed by this session
 	ptys   map[string]*PTY
 	ptysMu sync.RWMutex
+	colors *SetColorsPayload // Colors from the client for new PTYs, guarded by ptysMu
 
 	// Session state (serializable)
 	state   *SessionState
@@ -144,7 +229,8 @@ type Session struct {
 	LastActive time.Time
 
 	// Configuration
-	config *SessionConfig
+	config     *SessionConfig
+	socketPath string // Daemon socket, exported to shells as TUIOS_SOCKET
 }
 
 // SessionConfig holds configuration for a session.
@@ -186,15 +272,35 @@ func NewSession(name string, cfg *SessionConfig, width, height int) (*Session, e
 	return session, nil
 }
 
-// CreatePTY creates a new PTY in this session.
-func (s *Session) CreatePTY(width, height int) (*PTY, error) {
+// CreatePTY creates a new PTY in this session. env holds extra "NAME=value"
+// environment variables for its shell.
+func (s *Session) CreatePTY(width, height int, env ...string) (*PTY, error) {
+	return s.CreateCommandPTY(width, height, PTYOptions{Env: env})
+}
+
+// PTYOptions are the optional settings of a new PTY.
+type PTYOptions struct {
+	Command []string // Runs instead of the shell when set
+	Dir     string   // Directory the command starts in, empty for the daemon's
+	Env     []string // Extra "NAME=value" environment variables
+
+	// Width options of the emulator (see vt.Emulator.SetWidthOptions)
+	AmbiguousWidth int
+	EmojiWidth     int
+}
+
+// CreateCommandPTY creates a new PTY like CreatePTY with the given options.
+func (s *Session) CreateCommandPTY(width, height int, opts PTYOptions) (*PTY, error) {
 	s.ptysMu.Lock()
 	defer s.ptysMu.Unlock()
 
 	id := uuid.New().String()
 	ctx, cancel := context.WithCancel(context.Background())
 
-	shell := s.getShell()
+	argv := opts.Command
+	if len(argv) == 0 {
+		argv = []string{s.getShell()}
+	}
 
 	// Create PTY
 	ptyInstance, err := xpty.NewPty(width, height)
@@ -204,8 +310,9 @@ func (s *Session) CreatePTY(width, height int) (*PTY, error) {
 	}
 
 	// Create command
-	cmd := exec.Command(shell)
-	cmd.Env = s.buildEnv()
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = opts.Dir
+	cmd.Env = append(s.buildEnv(), opts.Env...)
 
 	// Set up the command to use the PTY as controlling terminal
 	// This is required for interactive shells to work properly
@@ -223,6 +330,10 @@ func (s *Session) CreatePTY(width, height int) (*PTY, error) {
 	// This maintains scrollback, screen content, cursor position across reconnects
 	terminal := vt.NewEmulator(width, height)
 	terminal.SetScrollbackMaxLines(10000) // Match default scrollback
+	terminal.SetWidthOptions(opts.AmbiguousWidth, opts.EmojiWidth)
+	if s.colors != nil {
+		s.colors.apply(terminal)
+	}
 
 	// Set a no-op Kitty passthrough func to prevent the daemon's VT emulator from
 	// generating responses to Kitty graphics commands. Responses should only come
@@ -244,6 +355,7 @@ func (s *Session) CreatePTY(width, height int) (*PTY, error) {
 		height:       height,
 		outputBuffer: make([]byte, 64*1024), // 64KB ring buffer
 		subscribers:  make(map[string]chan []byte),
+		done:         make(chan struct{}),
 	}
 
 	s.ptys[id] = pty
@@ -259,10 +371,18 @@ func (s *Session) CreatePTY(width, height int) (*PTY, error) {
 	// Monitor process exit
 	go pty.monitorExit()
 
+	// ConPTY does not report echo
+	if runtime.GOOS != "windows" {
+		go pty.watchSecureInput()
+	}
+
 	s.LastActive = time.Now()
 	return pty, nil
 }
 
+// shutdowns tracks the PTY processes still being stopped after Close.
+var shutdowns sync.WaitGroup
+
 // GetPTY returns a PTY by ID.
 func (s *Session) GetPTY(id string) *PTY {
 	s.ptysMu.RLock()
@@ -284,6 +404,40 @@ func (s *Session) ClosePTY(id string) error {
 	return pty.Close()
 }
 
+// MovePTY moves a PTY to another session, along with the state of the
+// window showing it, which replaces any the session had for the PTY.
+func (s *Session) MovePTY(id string, to *Session, window WindowState) (*PTY, error) {
+	if to == s {
+		return nil, fmt.Errorf("PTY %s is already in session %s", id, s.Name)
+	}
+
+	s.ptysMu.Lock()
+	pty, exists := s.ptys[id]
+	delete(s.ptys, id)
+	s.ptysMu.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("PTY %s not found", id)
+	}
+
+	s.stateMu.Lock()
+	s.state.Windows = slices.DeleteFunc(slices.Clone(s.state.Windows), func(w WindowState) bool {
+		return w.PTYID == id
+	})
+	s.stateMu.Unlock()
+
+	to.ptysMu.Lock()
+	to.ptys[id] = pty
+	to.ptysMu.Unlock()
+
+	window.PTYID = id
+	to.stateMu.Lock()
+	to.state.Windows = append(slices.Clone(to.state.Windows), window)
+	to.LastActive = time.Now()
+	to.stateMu.Unlock()
+
+	return pty, nil
+}
+
 // ListPTYIDs returns all PTY IDs in this session.
 func (s *Session) ListPTYIDs() []string {
 	s.ptysMu.RLock()
@@ -316,6 +470,12 @@ func (s *Session) GetState() *SessionState {
 		stateCopy.WorkspaceFocus = make(map[int]string)
 		maps.Copy(stateCopy.WorkspaceFocus, s.state.WorkspaceFocus)
 	}
+	if s.state.Marks != nil {
+		stateCopy.Marks = maps.Clone(s.state.Marks)
+	}
+	if s.state.WorkspaceTiling != nil {
+		stateCopy.WorkspaceTiling = maps.Clone(s.state.WorkspaceTiling)
+	}
 	return &stateCopy
 }
 
@@ -336,6 +496,9 @@ func (s *Session) Stop() {
 		_ = pty.Close()
 		delete(s.ptys, id)
 	}
+
+	// Give the shells their grace period before the daemon exits
+	shutdowns.Wait()
 }
 
 // WindowCount returns the number of windows in state.
@@ -406,6 +569,9 @@ func (s *Session) buildEnv() []string {
 	}
 	env = append(env, "COLORTERM="+colorTerm)
 	env = append(env, "TUIOS_SESSION="+s.Name)
+	if s.socketPath != "" {
+		env = append(env, "TUIOS_SOCKET="+s.socketPath)
+	}
 
 	return env
 }
@@ -532,6 +698,7 @@ func (p *PTY) GetTerminalState() *TerminalState {
 		ScrollbackLen: p.terminal.ScrollbackLen(),
 		IsAltScreen:   p.terminal.IsAltScreen(), // Capture alt screen state for mouse event forwarding
 		Modes:         p.terminal.GetModes(),    // Capture terminal modes (mouse tracking, bracketed paste, etc.)
+		KeyboardFlags: p.terminal.KittyKeyboardFlags(),
 		Screen:        make([][]CellState, p.height),
 		Scrollback:    make([][]CellState, 0),
 	}
@@ -575,8 +742,9 @@ type TerminalState struct {
 	CursorX       int           `json:"cursor_x"`
 	CursorY       int           `json:"cursor_y"`
 	ScrollbackLen int           `json:"scrollback_len"`
-	IsAltScreen   bool          `json:"is_alt_screen,omitempty"` // Alternate screen buffer active (for mouse event forwarding)
-	Modes         map[int]bool  `json:"modes,omitempty"`         // Terminal modes (mouse tracking, bracketed paste, etc.)
+	IsAltScreen   bool          `json:"is_alt_screen,omitempty"`  // Alternate screen buffer active (for mouse event forwarding)
+	Modes         map[int]bool  `json:"modes,omitempty"`          // Terminal modes (mouse tracking, bracketed paste, etc.)
+	KeyboardFlags int           `json:"keyboard_flags,omitempty"` // Kitty keyboard protocol flags of the active screen
 	Screen        [][]CellState `json:"screen"`
 	Scrollback    [][]CellState `json:"scrollback,omitempty"`
 }
@@ -681,13 +849,23 @@ func (p *PTY) Close() error {
 	}
 	p.subscribersMu.Unlock()
 
-	// Kill process
+	// Stop the process, looking up its foreground job while the PTY is open
 	if p.cmd != nil && p.cmd.Process != nil {
-		_ = p.cmd.Process.Kill()
+		foreground := 0
+		if p.pty != nil {
+			foreground = system.ForegroundProcessGroup(p.pty.Fd())
+		}
+		shutdowns.Add(1)
+		go func() {
+			defer shutdowns.Done()
+			system.StopProcess(p.cmd.Process.Pid, foreground, p.done, config.ShutdownGracePeriod)
+		}()
 	}
 
 	// Close PTY
 	if p.pty != nil {
+		p.closeMu.Lock()
+		defer p.closeMu.Unlock()
 		return p.pty.Close()
 	}
 	return nil
@@ -782,6 +960,7 @@ func (p *PTY) monitorExit() {
 	}
 
 	_ = p.cmd.Wait()
+	close(p.done)
 
 	p.exitedMu.Lock()
 	p.exited = true
@@ -803,6 +982,54 @@ func (p *PTY) SetOnExit(callback func(ptyID string)) {
 	p.onExit = callback
 }
 
+// SecureInput reports whether the PTY's program reads a password: its echo
+// was off while line editing was on when last sampled.
+func (p *PTY) SecureInput() bool {
+	return p.secureInput.Load()
+}
+
+// SetOnSecureInput sets the callback to be called when the PTY's program
+// starts or stops reading a password.
+func (p *PTY) SetOnSecureInput(callback func(ptyID string, active bool)) {
+	p.subscribersMu.Lock()
+	p.onSecureInput = callback
+	p.subscribersMu.Unlock()
+}
+
+// watchSecureInput samples the PTY's echo setting until the PTY closes,
+// calling the secure input callback once per change.
+func (p *PTY) watchSecureInput() {
+	ticker := time.NewTicker(secureInputInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+		// Close cancels the context before it takes the lock, so a closed
+		// PTY is never sampled
+		p.closeMu.RLock()
+		if p.ctx.Err() != nil {
+			p.closeMu.RUnlock()
+			return
+		}
+		active := p.echoOff()
+		p.closeMu.RUnlock()
+		if p.secureInput.Swap(active) == active {
+			continue
+		}
+		p.subscribersMu.RLock()
+		callback := p.onSecureInput
+		p.subscribersMu.RUnlock()
+		if callback != nil {
+			callback(p.ID, active)
+		}
+	}
+}
+
 // forwardTerminalResponses reads responses from the daemon's terminal emulator and
 // forwards them to the PTY as input for applications to receive.
 // The emulator writes responses (like DA1, CPR) to its pipe. If nothing reads from the pipe,
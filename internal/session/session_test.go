@@ -2,7 +2,12 @@ package session
 
 import (
 	"bytes"
+	"image/color"
+	"runtime"
 	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
 )
 
 // TestProtocolMessages tests the protocol message encoding/decoding
@@ -292,6 +297,71 @@ func TestSessionState(t *testing.T) {
 	}
 }
 
+// TestMovePTY tests handing a PTY and its window over to another session
+func TestMovePTY(t *testing.T) {
+	from, _ := NewSession("laptop", nil, 80, 24)
+	to, _ := NewSession("server", nil, 80, 24)
+	from.ptys["pty-1"] = &PTY{ID: "pty-1"}
+	from.UpdateState(&SessionState{Name: "laptop", Windows: []WindowState{
+		{ID: "win-1", PTYID: "pty-1"},
+		{ID: "win-2", PTYID: "pty-2"},
+	}})
+
+	if _, err := from.MovePTY("pty-1", from, WindowState{}); err == nil {
+		t.Error("moving a PTY to its own session should fail")
+	}
+	if _, err := from.MovePTY("missing", to, WindowState{}); err == nil {
+		t.Error("moving an unknown PTY should fail")
+	}
+
+	pty, err := from.MovePTY("pty-1", to, WindowState{ID: "win-1", Title: "build", Width: 40, Height: 12})
+	if err != nil {
+		t.Fatalf("MovePTY failed: %v", err)
+	}
+	if pty.ID != "pty-1" || from.GetPTY("pty-1") != nil || to.GetPTY("pty-1") != pty {
+		t.Errorf("PTY was not moved: from=%v to=%v", from.GetPTY("pty-1"), to.GetPTY("pty-1"))
+	}
+	if state := from.GetState(); len(state.Windows) != 1 || state.Windows[0].ID != "win-2" {
+		t.Errorf("old session windows = %+v, want only win-2", state.Windows)
+	}
+	state := to.GetState()
+	if len(state.Windows) != 1 || state.Windows[0].Title != "build" || state.Windows[0].PTYID != "pty-1" {
+		t.Errorf("new session windows = %+v, want the moved window", state.Windows)
+	}
+}
+
+func TestPTYSecureInputWithoutOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY does not report echo")
+	}
+	sess, _ := NewSession("secure", nil, 80, 24)
+	// Like read -s, stty turns echo off without printing anything
+	pty, err := sess.CreateCommandPTY(80, 24, PTYOptions{Command: []string{"sh", "-c", "stty -echo; sleep 30"}})
+	if err != nil {
+		t.Skipf("Cannot start a PTY: %v", err)
+	}
+	defer func() { _ = pty.Close() }()
+
+	changes := make(chan bool, 4)
+	pty.SetOnSecureInput(func(ptyID string, active bool) {
+		if ptyID == pty.ID {
+			changes <- active
+		}
+	})
+	select {
+	case active := <-changes:
+		if !active || !pty.SecureInput() {
+			t.Errorf("secure input change = %v, want it turned on", active)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("echo turned off was never reported")
+	}
+	time.Sleep(3 * secureInputInterval)
+	if len(changes) != 0 {
+		t.Errorf("got %d more reports without echo changing, want none", len(changes))
+	}
+}
+
 // TestSocketPath tests socket path generation
 func TestSocketPath(t *testing.T) {
 	path, err := GetSocketPath()
@@ -1557,3 +1627,101 @@ func TestAttachedPayloadWithState(t *testing.T) {
 		t.Errorf("State.Windows length = %d, want 2", len(decoded.State.Windows))
 	}
 }
+
+// TestCalculateEffectiveSizeLatest tests that the "latest" policy follows the
+// client that typed or resized most recently
+func TestCalculateEffectiveSizeLatest(t *testing.T) {
+	defer SetWindowSizePolicy(GetWindowSizePolicy())
+
+	now := time.Now()
+	d := &Daemon{clients: map[string]*connState{
+		"a":     {sessionID: "s", isTUIClient: true, width: 200, height: 60, lastSized: now.Add(-time.Minute)},
+		"b":     {sessionID: "s", isTUIClient: true, width: 80, height: 24, lastSized: now},
+		"other": {sessionID: "t", isTUIClient: true, width: 300, height: 90, lastSized: now.Add(time.Minute)},
+	}}
+
+	SetWindowSizePolicy(WindowSizeSmallest)
+	if w, h := d.calculateEffectiveSize("s"); w != 80 || h != 24 {
+		t.Errorf("smallest: got %dx%d, want 80x24", w, h)
+	}
+
+	SetWindowSizePolicy(ParseWindowSizePolicy("latest"))
+	if w, h := d.calculateEffectiveSize("s"); w != 80 || h != 24 {
+		t.Errorf("latest: got %dx%d, want 80x24", w, h)
+	}
+
+	d.clients["a"].lastSized = now.Add(time.Second)
+	if w, h := d.calculateEffectiveSize("s"); w != 200 || h != 60 {
+		t.Errorf("latest after input from a: got %dx%d, want 200x60", w, h)
+	}
+	if cs := d.latestSizedClient("s"); cs != d.clients["a"] {
+		t.Errorf("latestSizedClient = %v, want a", cs)
+	}
+
+	// A client that has not reported its size yet never takes over.
+	d.clients["c"] = &connState{sessionID: "s", isTUIClient: true, lastSized: now.Add(time.Hour)}
+	if cs := d.latestSizedClient("s"); cs != d.clients["a"] {
+		t.Errorf("latestSizedClient with unsized client = %v, want a", cs)
+	}
+}
+
+// TestReadOnlyClients tests that read-only clients cannot change the session
+// and are never picked to execute remote commands
+func TestReadOnlyClients(t *testing.T) {
+	for _, mt := range []MessageType{MsgInput, MsgKill, MsgCreatePTY, MsgClosePTY, MsgUpdateState, MsgMovePTY} {
+		if !isMutatingMessage(mt) {
+			t.Errorf("message type %d should be dropped for read-only clients", mt)
+		}
+	}
+	for _, mt := range []MessageType{MsgPing, MsgDetach, MsgResize, MsgSubscribePTY, MsgGetState} {
+		if isMutatingMessage(mt) {
+			t.Errorf("message type %d should be allowed for read-only clients", mt)
+		}
+	}
+
+	d := &Daemon{clients: map[string]*connState{
+		"mirror": {clientID: "mirror", sessionID: "s", isTUIClient: true, readOnly: true},
+	}}
+	if cs := d.findTUIClient("s"); cs != nil {
+		t.Errorf("findTUIClient returned read-only client %s", cs.clientID)
+	}
+
+	d.clients["presenter"] = &connState{clientID: "presenter", sessionID: "s", isTUIClient: true}
+	if cs := d.findTUIClient("s"); cs == nil || cs.clientID != "presenter" {
+		t.Errorf("findTUIClient = %v, want presenter", cs)
+	}
+
+	// A small read-only mirror must not shrink the session.
+	defer SetWindowSizePolicy(GetWindowSizePolicy())
+	SetWindowSizePolicy(WindowSizeSmallest)
+	d.clients["mirror"].width, d.clients["mirror"].height = 40, 10
+	d.clients["presenter"].width, d.clients["presenter"].height = 120, 40
+	if w, h := d.calculateEffectiveSize("s"); w != 120 || h != 40 {
+		t.Errorf("effective size with read-only mirror = %dx%d, want 120x40", w, h)
+	}
+}
+
+func TestSetColorsPayload(t *testing.T) {
+	var palette [16]color.Color
+	palette[1] = color.RGBA{R: 0xcc, G: 0x11, B: 0x22, A: 0xff}
+	payload := NewSetColorsPayload(color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}, nil, palette, nil, color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff})
+	if payload.Foreground != "#eeeeee" || payload.Cursor != "" || payload.HostBackground != "#102030" || len(payload.Palette) != 16 || payload.Palette[1] != "#cc1122" {
+		t.Fatalf("NewSetColorsPayload() = %+v", payload)
+	}
+
+	// A daemon emulator given the colors answers with them, and keeps the
+	// standard palette where the theme sets none
+	e := vt.NewEmulator(10, 2)
+	payload.apply(e)
+	if r, g, b, _ := e.IndexedColor(1).RGBA(); r>>8 != 0xcc || g>>8 != 0x11 || b>>8 != 0x22 {
+		t.Errorf("indexed color 1 = %v, want the theme's", e.IndexedColor(1))
+	}
+	if r, _, _, _ := e.IndexedColor(196).RGBA(); r>>8 != 0xff {
+		t.Errorf("indexed color 196 = %v, want the standard red", e.IndexedColor(196))
+	}
+
+	// Without a theme nothing but the host colors is sent
+	if plain := NewSetColorsPayload(nil, nil, [16]color.Color{}, nil, nil); plain.Palette != nil || plain.Foreground != "" {
+		t.Errorf("NewSetColorsPayload() without a theme = %+v", plain)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
 )
 
 // RemoteCommandHandler is a callback for handling remote commands from the CLI.
@@ -56,6 +58,10 @@ type TUIClient struct {
 	ptyClosedHandlers   map[string]func()
 	ptyClosedHandlersMu sync.RWMutex
 
+	// PTY secure input handlers - called when a PTY's program turns echo off or on
+	ptySecureHandlers   map[string]func(bool)
+	ptySecureHandlersMu sync.RWMutex
+
 	// Remote command handler - called when a remote command is received
 	remoteCommandHandler RemoteCommandHandler
 	remoteCommandMu      sync.RWMutex
@@ -89,6 +95,7 @@ func NewTUIClient() *TUIClient {
 		codec:             DefaultCodec(), // gob by default
 		ptyHandlers:       make(map[string]func([]byte)),
 		ptyClosedHandlers: make(map[string]func()),
+		ptySecureHandlers: make(map[string]func(bool)),
 		pendingResponses:  make(map[MessageType]chan *Message),
 		done:              make(chan struct{}),
 	}
@@ -186,12 +193,27 @@ func (c *TUIClient) ConnectWithCapabilities(version string, width, height int, c
 // AttachSession attaches to a session (creates if createNew is true).
 // Returns the session state for restoration.
 func (c *TUIClient) AttachSession(name string, createNew bool, width, height int) (*SessionState, error) {
-	msg, err := NewMessageWithCodec(MsgAttach, &AttachPayload{
+	return c.attach(&AttachPayload{
 		SessionName: name,
 		CreateNew:   createNew,
 		Width:       width,
 		Height:      height,
-	}, c.codec)
+	})
+}
+
+// AttachSessionReadOnly attaches to an existing session as a mirror: the
+// daemon drops all input and state changes sent by this client.
+func (c *TUIClient) AttachSessionReadOnly(name string, width, height int) (*SessionState, error) {
+	return c.attach(&AttachPayload{
+		SessionName: name,
+		Width:       width,
+		Height:      height,
+		ReadOnly:    true,
+	})
+}
+
+func (c *TUIClient) attach(payload *AttachPayload) (*SessionState, error) {
+	msg, err := NewMessageWithCodec(MsgAttach, payload, c.codec)
 	if err != nil {
 		return nil, err
 	}
@@ -236,12 +258,35 @@ func (c *TUIClient) Detach() error {
 	return c.send(msg)
 }
 
-// CreatePTY creates a new PTY in the session.
-func (c *TUIClient) CreatePTY(title string, width, height int) (string, error) {
+// SetColors tells the daemon the colors the client gives its terminals, so
+// its emulators answer color queries from applications with them.
+func (c *TUIClient) SetColors(colors *SetColorsPayload) error {
+	msg, err := NewMessageWithCodec(MsgSetColors, colors, c.codec)
+	if err != nil {
+		return err
+	}
+	return c.send(msg)
+}
+
+// CreatePTY creates a new PTY in the session. env holds extra "NAME=value"
+// environment variables for its shell.
+func (c *TUIClient) CreatePTY(title string, width, height int, env ...string) (string, error) {
+	return c.CreateCommandPTY(title, width, height, nil, "", env...)
+}
+
+// CreateCommandPTY creates a new PTY like CreatePTY that runs argv in dir
+// instead of the shell. Its emulator measures characters with the
+// configured ambiguous and emoji widths, as the client's do.
+func (c *TUIClient) CreateCommandPTY(title string, width, height int, argv []string, dir string, env ...string) (string, error) {
 	msg, err := NewMessageWithCodec(MsgCreatePTY, &CreatePTYPayload{
-		Title:  title,
-		Width:  width,
-		Height: height,
+		Title:          title,
+		Width:          width,
+		Height:         height,
+		Env:            env,
+		Command:        argv,
+		Dir:            dir,
+		AmbiguousWidth: config.AmbiguousWidth,
+		EmojiWidth:     config.EmojiWidth,
 	}, c.codec)
 	if err != nil {
 		return "", err
@@ -279,6 +324,36 @@ func (c *TUIClient) ClosePTY(ptyID string) error {
 	return c.send(msg)
 }
 
+// ListPTYs returns the PTYs of the attached session.
+func (c *TUIClient) ListPTYs() ([]PTYInfo, error) {
+	msg, err := NewMessageWithCodec(MsgListPTYs, nil, c.codec)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendAndWaitResponse(msg, MsgPTYList, MsgError)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Type {
+	case MsgPTYList:
+		var payload PTYListPayload
+		if err := resp.ParsePayloadWithCodec(&payload, c.codec); err != nil {
+			return nil, err
+		}
+		return payload.PTYs, nil
+
+	case MsgError:
+		var errPayload ErrorPayload
+		_ = resp.ParsePayloadWithCodec(&errPayload, c.codec)
+		return nil, fmt.Errorf("list PTYs failed: %s", errPayload.Message)
+
+	default:
+		return nil, fmt.Errorf("unexpected response: %d", resp.Type)
+	}
+}
+
 // SubscribePTY subscribes to PTY output and registers a handler.
 func (c *TUIClient) SubscribePTY(ptyID string, handler func([]byte)) error {
 	c.ptyHandlersMu.Lock()
@@ -297,6 +372,9 @@ func (c *TUIClient) UnsubscribePTY(ptyID string) {
 	c.ptyHandlersMu.Lock()
 	delete(c.ptyHandlers, ptyID)
 	c.ptyHandlersMu.Unlock()
+	c.ptySecureHandlersMu.Lock()
+	delete(c.ptySecureHandlers, ptyID)
+	c.ptySecureHandlersMu.Unlock()
 
 	// Send unsubscribe message to daemon to stop streaming
 	msg, err := NewMessageWithCodec(MsgUnsubscribePTY, &UnsubscribePTYPayload{PTYID: ptyID}, c.codec)
@@ -313,6 +391,55 @@ func (c *TUIClient) OnPTYClosed(ptyID string, handler func()) {
 	c.ptyClosedHandlersMu.Unlock()
 }
 
+// OnPTYSecureInput registers a handler to be called when the PTY's program
+// turns echo off to read a password, and when it turns it back on.
+func (c *TUIClient) OnPTYSecureInput(ptyID string, handler func(bool)) {
+	c.ptySecureHandlersMu.Lock()
+	c.ptySecureHandlers[ptyID] = handler
+	c.ptySecureHandlersMu.Unlock()
+}
+
+// MovePTY moves a PTY of the attached session, with the window showing it,
+// to another session of the same daemon. Once moved, the PTY's output and
+// exit are no longer reported to this client.
+func (c *TUIClient) MovePTY(ptyID, targetSession string, window WindowState) error {
+	msg, err := NewMessageWithCodec(MsgMovePTY, &MovePTYPayload{
+		PTYID:         ptyID,
+		TargetSession: targetSession,
+		Window:        window,
+	}, c.codec)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.sendAndWaitResponse(msg, MsgPTYMoved, MsgError)
+	if err != nil {
+		return err
+	}
+
+	switch resp.Type {
+	case MsgPTYMoved:
+		c.ptyHandlersMu.Lock()
+		delete(c.ptyHandlers, ptyID)
+		c.ptyHandlersMu.Unlock()
+		c.ptyClosedHandlersMu.Lock()
+		delete(c.ptyClosedHandlers, ptyID)
+		c.ptyClosedHandlersMu.Unlock()
+		c.ptySecureHandlersMu.Lock()
+		delete(c.ptySecureHandlers, ptyID)
+		c.ptySecureHandlersMu.Unlock()
+		return nil
+
+	case MsgError:
+		var errPayload ErrorPayload
+		_ = resp.ParsePayloadWithCodec(&errPayload, c.codec)
+		return fmt.Errorf("move PTY failed: %s", errPayload.Message)
+
+	default:
+		return fmt.Errorf("unexpected response: %d", resp.Type)
+	}
+}
+
 // OnRemoteCommand registers a handler for remote commands from the CLI.
 // The handler should execute the command and return an error if it fails.
 func (c *TUIClient) OnRemoteCommand(handler RemoteCommandHandler) {
@@ -605,11 +732,28 @@ func (c *TUIClient) handleMessage(msg *Message) {
 		delete(c.ptyClosedHandlers, payload.PTYID)
 		c.ptyClosedHandlersMu.Unlock()
 
+		c.ptySecureHandlersMu.Lock()
+		delete(c.ptySecureHandlers, payload.PTYID)
+		c.ptySecureHandlersMu.Unlock()
+
 		// Call the closed handler to notify window
 		if closedHandler != nil {
 			closedHandler()
 		}
 
+	case MsgPTYSecureInput:
+		var payload PTYSecureInputPayload
+		if err := msg.ParsePayloadWithCodec(&payload, c.codec); err != nil {
+			return
+		}
+		c.ptySecureHandlersMu.RLock()
+		secureHandler := c.ptySecureHandlers[payload.PTYID]
+		c.ptySecureHandlersMu.RUnlock()
+
+		if secureHandler != nil {
+			secureHandler(payload.Active)
+		}
+
 	case MsgDetached:
 		// Session detached
 		close(c.done)
@@ -0,0 +1,59 @@
+package session
+
+import (
+	"strings"
+	"sync"
+)
+
+// WindowSizePolicy controls how the size of a session shared by several
+// clients is chosen.
+type WindowSizePolicy int
+
+const (
+	// WindowSizeSmallest sizes the session to the smallest attached client so
+	// every client sees the whole session.
+	WindowSizeSmallest WindowSizePolicy = iota
+	// WindowSizeLatest sizes the session to the client that most recently
+	// typed or resized. Smaller clients see a clipped view.
+	WindowSizeLatest
+)
+
+var (
+	windowSizePolicy   = WindowSizeSmallest
+	windowSizePolicyMu sync.RWMutex
+)
+
+// ParseWindowSizePolicy parses a window size policy name. Unknown names
+// fall back to WindowSizeSmallest.
+func ParseWindowSizePolicy(s string) WindowSizePolicy {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "latest":
+		return WindowSizeLatest
+	default:
+		return WindowSizeSmallest
+	}
+}
+
+// String returns the config name of the policy.
+func (p WindowSizePolicy) String() string {
+	switch p {
+	case WindowSizeLatest:
+		return "latest"
+	default:
+		return "smallest"
+	}
+}
+
+// SetWindowSizePolicy sets the policy used by the daemon to size sessions.
+func SetWindowSizePolicy(p WindowSizePolicy) {
+	windowSizePolicyMu.Lock()
+	defer windowSizePolicyMu.Unlock()
+	windowSizePolicy = p
+}
+
+// GetWindowSizePolicy returns the policy used by the daemon to size sessions.
+func GetWindowSizePolicy() WindowSizePolicy {
+	windowSizePolicyMu.RLock()
+	defer windowSizePolicyMu.RUnlock()
+	return windowSizePolicy
+}
@@ -0,0 +1,91 @@
+//go:build unix
+
+package system
+
+import (
+	"errors"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// killWait is how long StopProcess waits for a process to be reaped after
+// it was killed.
+const killWait = time.Second
+
+// pollInterval is how often StopProcess checks whether the signalled
+// processes are gone.
+const pollInterval = 10 * time.Millisecond
+
+// ForegroundProcessGroup returns the foreground process group of the
+// terminal open as fd, or 0 if it cannot be determined.
+func ForegroundProcessGroup(fd uintptr) int {
+	var pgrp int
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		fd,
+		uintptr(unix.TIOCGPGRP),
+		uintptr(unsafe.Pointer(&pgrp)),
+	)
+	if errno != 0 {
+		return 0
+	}
+	return pgrp
+}
+
+// StopProcess shuts down a shell started as the leader of its own session,
+// together with the job in the foreground of its terminal (foreground is
+// that job's process group, 0 if unknown). Both process groups get SIGHUP
+// and SIGTERM, as when their terminal hangs up, and SIGKILL once grace has
+// passed with any of them still running. exited must be closed once the
+// shell has been reaped. StopProcess returns when everything has exited,
+// or shortly after the SIGKILL.
+func StopProcess(pid, foreground int, exited <-chan struct{}, grace time.Duration) {
+	groups := []int{pid}
+	if foreground > 0 && foreground != pid {
+		groups = append(groups, foreground)
+	}
+	signal := func(sig syscall.Signal) {
+		for _, g := range groups {
+			_ = syscall.Kill(-g, sig)
+		}
+	}
+
+	// SIGCONT lets stopped jobs act on the signals
+	signal(syscall.SIGHUP)
+	signal(syscall.SIGTERM)
+	signal(syscall.SIGCONT)
+
+	running := func() bool {
+		select {
+		case <-exited:
+		default:
+			return true
+		}
+		for _, g := range groups[1:] {
+			if err := syscall.Kill(-g, 0); !errors.Is(err, syscall.ESRCH) {
+				return true
+			}
+		}
+		return false
+	}
+
+	deadline := time.NewTimer(grace)
+	defer deadline.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for running() {
+		select {
+		case <-deadline.C:
+			signal(syscall.SIGKILL)
+			select {
+			case <-exited:
+			case <-time.After(killWait):
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,61 @@
+//go:build unix
+
+package system
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startSession starts a shell script as the leader of its own session and
+// returns its PID and a channel closed once it has been reaped.
+func startSession(t *testing.T, script string) (int, <-chan struct{}) {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sh: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	return cmd.Process.Pid, exited
+}
+
+// TestStopProcess tests that processes exit on SIGTERM within the grace
+// period and that those ignoring it are killed once it has passed.
+func TestStopProcess(t *testing.T) {
+	t.Run("exits on signal", func(t *testing.T) {
+		pid, exited := startSession(t, "sleep 30")
+		start := time.Now()
+		StopProcess(pid, 0, exited, 5*time.Second)
+		select {
+		case <-exited:
+		default:
+			t.Fatal("process still running after StopProcess")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("StopProcess took %v, want the process to exit on SIGTERM", elapsed)
+		}
+	})
+
+	t.Run("killed after grace period", func(t *testing.T) {
+		pid, exited := startSession(t, `trap "" HUP TERM; while :; do sleep 1; done`)
+		// Give the shell time to install the trap
+		time.Sleep(100 * time.Millisecond)
+		start := time.Now()
+		StopProcess(pid, 0, exited, 200*time.Millisecond)
+		select {
+		case <-exited:
+		default:
+			t.Fatal("process still running after StopProcess")
+		}
+		if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+			t.Errorf("StopProcess returned after %v, before the grace period", elapsed)
+		}
+	})
+}
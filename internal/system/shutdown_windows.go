@@ -0,0 +1,23 @@
+//go:build windows
+
+package system
+
+import (
+	"os"
+	"time"
+)
+
+// ForegroundProcessGroup is a stub for Windows - there are no process groups,
+// so it always returns 0.
+func ForegroundProcessGroup(_ uintptr) int {
+	return 0
+}
+
+// StopProcess kills the process right away, since Windows has no hangup
+// signal to ask it to exit first. It does not wait for exited, because
+// ConPTY processes cannot be waited for reliably.
+func StopProcess(pid, _ int, _ <-chan struct{}, _ time.Duration) {
+	if p, err := os.FindProcess(pid); err == nil {
+		_ = p.Kill()
+	}
+}
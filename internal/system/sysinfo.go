@@ -1,4 +1,4 @@
-// Package system provides system information and resource monitoring.
+// Package system provides system information, resource monitoring and process shutdown.
 package system
 
 import (
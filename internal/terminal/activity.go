@@ -0,0 +1,27 @@
+package terminal
+
+// noteOutput records that output arrived, for the activity badge, the
+// triggers and the output metrics. Output replayed while callbacks are
+// suppressed during state restoration is old and does not count.
+func (w *Window) noteOutput(data []byte) {
+	if !w.suppressCallbacks.Load() {
+		w.outputSeen.Store(true)
+		w.totalOutput.Add(uint64(len(data)))
+		w.scanTriggers(data)
+	}
+}
+
+// TotalOutput returns the bytes of output received since the window opened.
+func (w *Window) TotalOutput() uint64 {
+	return w.totalOutput.Load()
+}
+
+// TakeActivity reports whether output arrived since the last call.
+func (w *Window) TakeActivity() bool {
+	return w.outputSeen.Swap(false)
+}
+
+// TakeBells returns the number of bells rung since the last call.
+func (w *Window) TakeBells() int {
+	return int(w.bellsRung.Swap(0))
+}
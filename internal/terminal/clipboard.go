@@ -0,0 +1,61 @@
+package terminal
+
+import (
+	"sync"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+// ClipboardWrite is a clipboard write a program in a window asked for with
+// OSC 52.
+type ClipboardWrite struct {
+	Selection string // Selections named by the program, such as "c" or "p"
+	Content   string
+}
+
+// clipboardSlot holds the latest clipboard write not yet taken. A program
+// that sets the clipboard again before the write is handled only needs its
+// last content delivered.
+type clipboardSlot struct {
+	mu      sync.Mutex
+	pending *ClipboardWrite
+}
+
+// noteClipboardWrite records a clipboard write asked for by the program.
+// Writes replayed while callbacks are suppressed during state restoration
+// are old and are dropped.
+func (w *Window) noteClipboardWrite(selection string, content []byte) {
+	if w.suppressCallbacks.Load() {
+		return
+	}
+	w.clipboard.mu.Lock()
+	defer w.clipboard.mu.Unlock()
+	w.clipboard.pending = &ClipboardWrite{Selection: selection, Content: string(content)}
+}
+
+// TakeClipboardWrite returns the clipboard write asked for since the last
+// call, if any.
+func (w *Window) TakeClipboardWrite() (ClipboardWrite, bool) {
+	w.clipboard.mu.Lock()
+	defer w.clipboard.mu.Unlock()
+	if w.clipboard.pending == nil {
+		return ClipboardWrite{}, false
+	}
+	write := *w.clipboard.pending
+	w.clipboard.pending = nil
+	return write, true
+}
+
+// ClipboardWritePolicy returns what happens to the window's clipboard
+// writes (allow, confirm or deny) and where that comes from: "window" when
+// set for this window, "rule" when a window rule matches it, otherwise
+// "global".
+func (w *Window) ClipboardWritePolicy() (string, string) {
+	if w.ClipboardPolicy != "" {
+		return w.ClipboardPolicy, "window"
+	}
+	if policy, ok := config.RuleClipboardWrite(w.CustomName, w.Title, w.App); ok {
+		return policy, "rule"
+	}
+	return config.ClipboardWrite, "global"
+}
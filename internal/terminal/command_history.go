@@ -0,0 +1,58 @@
+package terminal
+
+import (
+	"sync"
+	"time"
+)
+
+// maxPendingCommands caps the commands waiting to be taken.
+const maxPendingCommands = 64
+
+// CommandRecord is a command line run in a window's shell, reported with
+// OSC 133 shell integration marks.
+type CommandRecord struct {
+	Command  string
+	ExitCode int       // -1 when the shell did not report one
+	Time     time.Time // When the command finished
+	Dir      string    // Working directory of the shell ("" when unknown)
+	WindowID string
+}
+
+// commandLog holds the commands that finished since TakeCommands was last
+// called.
+type commandLog struct {
+	mu      sync.Mutex
+	pending []CommandRecord
+}
+
+// noteCommand records a command the shell reported as finished. Commands
+// replayed while callbacks are suppressed during state restoration are old
+// and do not count.
+func (w *Window) noteCommand(command string, exitCode int) {
+	if w.suppressCallbacks.Load() {
+		return
+	}
+	record := CommandRecord{
+		Command:  command,
+		ExitCode: exitCode,
+		Time:     time.Now(),
+		Dir:      w.WorkingDirectory(),
+		WindowID: w.ID,
+	}
+	w.commands.mu.Lock()
+	defer w.commands.mu.Unlock()
+	if len(w.commands.pending) >= maxPendingCommands {
+		w.commands.pending = w.commands.pending[1:]
+	}
+	w.commands.pending = append(w.commands.pending, record)
+}
+
+// TakeCommands returns the commands that finished in the window's shell
+// since the last call.
+func (w *Window) TakeCommands() []CommandRecord {
+	w.commands.mu.Lock()
+	defer w.commands.mu.Unlock()
+	commands := w.commands.pending
+	w.commands.pending = nil
+	return commands
+}
@@ -0,0 +1,472 @@
+package terminal
+
+import (
+	"slices"
+
+	"github.com/Gaurav-Gosain/tuios/internal/layout"
+)
+
+// Pane is one terminal inside a split window.
+type Pane struct {
+	ID     int         // Leaf ID in the pane tree
+	Window *Window     // Terminal of the pane (the host window itself for its first pane)
+	Rect   layout.Rect // Position within the window's content area
+}
+
+// PaneSet holds the panes of a window that has been split. The host window's
+// own terminal is its first pane; every other pane is a separate Window that
+// is never added to the window list and is drawn inside the host's frame.
+type PaneSet struct {
+	Tree   *layout.BSPTree
+	Panes  []*Pane
+	Active int // ID of the pane that receives input
+	nextID int
+}
+
+// HasPanes reports whether the window is split into panes.
+func (w *Window) HasPanes() bool {
+	return w.Panes != nil && len(w.Panes.Panes) > 0
+}
+
+// ActivePane returns the terminal of the active pane or tab, or the window
+// itself when it is neither split nor tabbed.
+func (w *Window) ActivePane() *Window {
+	if w.HasTabs() {
+		return w.ActiveTab()
+	}
+	if pane := w.activePane(); pane != nil {
+		return pane.Window
+	}
+	return w
+}
+
+// ActivePaneRect returns the position of the active pane within the window's
+// content area. The terminal of a tabbed window sits below its tab strip.
+func (w *Window) ActivePaneRect() layout.Rect {
+	if w.HasTabs() {
+		return layout.Rect{Y: 1, W: max(w.Width-2, 1), H: max(w.Height-3, 1)}
+	}
+	if pane := w.activePane(); pane != nil {
+		return pane.Rect
+	}
+	return layout.Rect{W: max(w.Width-2, 1), H: max(w.Height-2, 1)}
+}
+
+func (w *Window) activePane() *Pane {
+	if !w.HasPanes() {
+		return nil
+	}
+	for _, pane := range w.Panes.Panes {
+		if pane.ID == w.Panes.Active {
+			return pane
+		}
+	}
+	return w.Panes.Panes[0]
+}
+
+// PaneWindows returns the terminals of the window's panes in creation order,
+// or nil when it is not split.
+func (w *Window) PaneWindows() []*Window {
+	if !w.HasPanes() {
+		return nil
+	}
+	windows := make([]*Window, len(w.Panes.Panes))
+	for i, pane := range w.Panes.Panes {
+		windows[i] = pane.Window
+	}
+	return windows
+}
+
+// FindPane returns the pane or tab terminal with the given window ID, or nil.
+func (w *Window) FindPane(id string) *Window {
+	for _, tab := range w.Tabs {
+		if tab.ID == id {
+			return tab
+		}
+	}
+	if !w.HasPanes() {
+		return nil
+	}
+	for _, pane := range w.Panes.Panes {
+		if pane.Window.ID == id {
+			return pane.Window
+		}
+	}
+	return nil
+}
+
+// SplitPane splits the active pane in two, placing pane in the new half, and
+// makes it active. SplitVertical puts the new pane on the right,
+// SplitHorizontal below.
+func (w *Window) SplitPane(pane *Window, split layout.SplitType) {
+	w.Zoomed = false // Panes are drawn at normal size
+	if w.Panes == nil {
+		w.Panes = &PaneSet{Tree: layout.NewBSPTree(), nextID: 1}
+	}
+	set := w.Panes
+	if len(set.Panes) == 0 {
+		set.Panes = append(set.Panes, &Pane{ID: set.nextID, Window: w})
+		set.Tree.InsertWindow(set.nextID, 0, layout.SplitNone, 0, layout.Rect{})
+		set.Active = set.nextID
+		set.nextID++
+	}
+
+	id := set.nextID
+	set.nextID++
+	set.Tree.InsertWindow(id, set.Active, split, 0.5, layout.Rect{})
+	set.Panes = append(set.Panes, &Pane{ID: id, Window: pane})
+	set.Active = id
+
+	w.layoutPanes()
+	w.MarkContentDirty()
+}
+
+// PaneLayout returns the pane tree of a split window for saving, or nil when
+// the window is not split.
+func (w *Window) PaneLayout() *layout.SerializedBSPTree {
+	if !w.HasPanes() {
+		return nil
+	}
+	return w.Panes.Tree.Serialize()
+}
+
+// RestorePanes splits the window into the panes of a saved pane tree. panes
+// maps the tree's leaf IDs to their terminals, the window's own terminal
+// among them; leaves without a terminal are dropped. The window stays
+// unsplit when fewer than two panes are left.
+func (w *Window) RestorePanes(tree *layout.BSPTree, panes map[int]*Window, active int) {
+	set := &PaneSet{Tree: tree, Active: active, nextID: 1}
+	ids := tree.GetAllWindowIDs()
+	slices.Sort(ids) // Creation order
+	for _, id := range ids {
+		pane := panes[id]
+		if pane == nil {
+			tree.RemoveWindow(id)
+			continue
+		}
+		set.Panes = append(set.Panes, &Pane{ID: id, Window: pane})
+		set.nextID = max(set.nextID, id+1)
+	}
+	if len(set.Panes) < 2 {
+		return
+	}
+	if !slices.ContainsFunc(set.Panes, func(p *Pane) bool { return p.ID == active }) {
+		set.Active = set.Panes[0].ID
+	}
+
+	w.Panes = set
+	w.layoutPanes()
+	w.MarkContentDirty()
+}
+
+// ClosePane removes a pane from the window and stops its process. The host
+// window's own pane only has its process stopped, since the window is closed
+// by its owner once no panes are left.
+func (w *Window) ClosePane(pane *Window) {
+	if !w.removePane(pane) {
+		return
+	}
+	if pane == w {
+		w.stopProcess()
+	} else {
+		pane.Close()
+	}
+}
+
+// BreakPane removes the active pane from the window without stopping its
+// process and returns the window to add to the window list for it: the
+// pane's own window, or, when the active pane is the window's own terminal,
+// a window that takes over the remaining panes. It returns nil when the
+// window is not split.
+func (w *Window) BreakPane() *Window {
+	active := w.activePane()
+	if active == nil {
+		return nil
+	}
+	if active.Window != w {
+		w.removePane(active.Window)
+		return active.Window
+	}
+
+	// Keep the active terminal in this window and hand the other panes to
+	// the first of them
+	set := w.Panes
+	w.removePane(w)
+	w.Panes = nil
+	host := set.Panes[0].Window
+	if len(set.Panes) > 1 {
+		set.Active = set.Panes[0].ID
+		host.Panes = set
+	}
+	host.Width, host.Height = w.Width, w.Height
+	w.Resize(w.Width, w.Height)
+	return host
+}
+
+// removePane takes pane out of the window's pane list and tree and lays out
+// the remaining panes. A window left with only its own pane is no longer
+// split. It reports whether pane was one of the window's panes.
+func (w *Window) removePane(pane *Window) bool {
+	if !w.HasPanes() {
+		return false
+	}
+	set := w.Panes
+	index := -1
+	for i, p := range set.Panes {
+		if p.Window == pane {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return false
+	}
+
+	removed := set.Panes[index]
+	set.Panes = append(set.Panes[:index], set.Panes[index+1:]...)
+	set.Tree.RemoveWindow(removed.ID)
+
+	switch {
+	case len(set.Panes) == 0:
+		w.Panes = nil
+	case len(set.Panes) == 1 && set.Panes[0].Window == w:
+		w.Panes = nil
+		w.Resize(w.Width, w.Height)
+	default:
+		if set.Active == removed.ID {
+			set.Active = set.Panes[max(index-1, 0)].ID
+		}
+		w.layoutPanes()
+	}
+	w.MarkContentDirty()
+	return true
+}
+
+// CyclePane activates the next (delta > 0) or previous (delta < 0) pane.
+func (w *Window) CyclePane(delta int) {
+	if !w.HasPanes() {
+		return
+	}
+	panes := w.Panes.Panes
+	current := 0
+	for i, pane := range panes {
+		if pane.ID == w.Panes.Active {
+			current = i
+			break
+		}
+	}
+	next := ((current+delta)%len(panes) + len(panes)) % len(panes)
+	w.Panes.Active = panes[next].ID
+	w.MarkContentDirty()
+}
+
+// FocusPaneDirection activates the nearest pane beside the active pane in
+// the direction (dx, dy). It reports whether the active pane changed.
+func (w *Window) FocusPaneDirection(dx, dy int) bool {
+	active := w.activePane()
+	if active == nil {
+		return false
+	}
+	from := active.Rect
+
+	var best *Pane
+	bestDistance := 0
+	for _, pane := range w.Panes.Panes {
+		r := pane.Rect
+		var distance int
+		var overlaps bool
+		switch {
+		case dx > 0:
+			distance = r.X - (from.X + from.W)
+			overlaps = r.Y < from.Y+from.H && from.Y < r.Y+r.H
+		case dx < 0:
+			distance = from.X - (r.X + r.W)
+			overlaps = r.Y < from.Y+from.H && from.Y < r.Y+r.H
+		case dy > 0:
+			distance = r.Y - (from.Y + from.H)
+			overlaps = r.X < from.X+from.W && from.X < r.X+r.W
+		case dy < 0:
+			distance = from.Y - (r.Y + r.H)
+			overlaps = r.X < from.X+from.W && from.X < r.X+r.W
+		}
+		if pane == active || !overlaps || distance < 0 {
+			continue
+		}
+		if best == nil || distance < bestDistance {
+			best = pane
+			bestDistance = distance
+		}
+	}
+	if best == nil {
+		return false
+	}
+	w.Panes.Active = best.ID
+	w.MarkContentDirty()
+	return true
+}
+
+// PaneAt returns the pane terminal at content-area position (x, y) and the
+// position relative to that pane, or nil when (x, y) is on a separator or
+// the tab strip.
+func (w *Window) PaneAt(x, y int) (*Window, int, int) {
+	if w.HasTabs() {
+		if y < 1 {
+			return nil, 0, 0
+		}
+		return w.ActiveTab(), x, y - 1
+	}
+	if !w.HasPanes() {
+		if w.Zoomed {
+			return w, x / 2, y / 2
+		}
+		return w, x, y
+	}
+	for _, pane := range w.Panes.Panes {
+		r := pane.Rect
+		if x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H {
+			return pane.Window, x - r.X, y - r.Y
+		}
+	}
+	return nil, 0, 0
+}
+
+// FocusPaneAt activates the pane at content-area position (x, y).
+func (w *Window) FocusPaneAt(x, y int) {
+	pane, _, _ := w.PaneAt(x, y)
+	if pane == nil || !w.HasPanes() {
+		return
+	}
+	for _, p := range w.Panes.Panes {
+		if p.Window == pane && p.ID != w.Panes.Active {
+			w.Panes.Active = p.ID
+			w.MarkContentDirty()
+			return
+		}
+	}
+}
+
+// ResizePane moves the nearest split above the active pane on the axis of
+// (dx, dy) by step in that direction.
+func (w *Window) ResizePane(dx, dy int, step float64) {
+	active := w.activePane()
+	if active == nil {
+		return
+	}
+	split, delta := layout.SplitVertical, dx
+	if dy != 0 {
+		split, delta = layout.SplitHorizontal, dy
+	}
+
+	for node := w.Panes.Tree.FindNode(active.ID); node != nil && node.Parent != nil; node = node.Parent {
+		if node.Parent.SplitType != split {
+			continue
+		}
+		ratio := node.Parent.SplitRatio
+		if delta > 0 {
+			ratio += step
+		} else {
+			ratio -= step
+		}
+		node.Parent.SplitRatio = min(max(ratio, 0.1), 0.9)
+		w.layoutPanes()
+		w.MarkContentDirty()
+		return
+	}
+}
+
+// HasDamage reports whether the terminal of the window, of any of its panes
+// or of its active tab changed since it was last rendered.
+func (w *Window) HasDamage() bool {
+	if w.Terminal != nil && w.Terminal.HasDamage() {
+		return true
+	}
+	if tab := w.ActiveTab(); tab != w && tab.Terminal != nil && tab.Terminal.HasDamage() {
+		return true
+	}
+	for _, pane := range w.PaneWindows() {
+		if pane != w && pane.Terminal != nil && pane.Terminal.HasDamage() {
+			return true
+		}
+	}
+	return false
+}
+
+// SynchronizedOutput reports whether the terminal of the window, of any of
+// its panes or of its active tab is in the middle of a synchronized update
+// (DEC 2026), so the window should keep showing its previous frame.
+func (w *Window) SynchronizedOutput() bool {
+	if w.Terminal != nil && w.Terminal.SynchronizedOutput() {
+		return true
+	}
+	if tab := w.ActiveTab(); tab != w && tab.Terminal != nil && tab.Terminal.SynchronizedOutput() {
+		return true
+	}
+	for _, pane := range w.PaneWindows() {
+		if pane != w && pane.Terminal != nil && pane.Terminal.SynchronizedOutput() {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Window) forEachChildPane(fn func(*Window)) {
+	for _, tab := range w.Tabs {
+		if tab != w {
+			fn(tab)
+		}
+	}
+	if w.Panes == nil {
+		return
+	}
+	for _, pane := range w.Panes.Panes {
+		if pane.Window != w {
+			fn(pane.Window)
+		}
+	}
+}
+
+// layoutPanes divides the window's content area between its panes and
+// resizes their terminals to match.
+func (w *Window) layoutPanes() {
+	set := w.Panes
+	rects := make(map[int]layout.Rect, len(set.Panes))
+	layoutPaneNode(set.Tree.Root, layout.Rect{W: max(w.Width-2, 1), H: max(w.Height-2, 1)}, rects)
+
+	for _, pane := range set.Panes {
+		pane.Rect = rects[pane.ID]
+		cols, rows := max(pane.Rect.W, 1), max(pane.Rect.H, 1)
+		if pane.Window == w {
+			if w.Terminal != nil {
+				w.resizeTerminal(cols, rows)
+			}
+			continue
+		}
+		pane.Window.Resize(cols+2, rows+2)
+	}
+}
+
+// layoutPaneNode computes the rectangles of the panes under node within
+// bounds, leaving one cell between siblings for the separator.
+func layoutPaneNode(node *layout.TileNode, bounds layout.Rect, rects map[int]layout.Rect) {
+	if node == nil {
+		return
+	}
+	if node.IsLeaf() {
+		rects[node.WindowID] = bounds
+		return
+	}
+
+	left, right := bounds, bounds
+	switch node.SplitType {
+	case layout.SplitVertical:
+		left.W = min(max(int(float64(bounds.W)*node.SplitRatio), 1), max(bounds.W-2, 1))
+		right.X = bounds.X + left.W + 1
+		right.W = max(bounds.W-left.W-1, 1)
+	default:
+		left.H = min(max(int(float64(bounds.H)*node.SplitRatio), 1), max(bounds.H-2, 1))
+		right.Y = bounds.Y + left.H + 1
+		right.H = max(bounds.H-left.H-1, 1)
+	}
+	layoutPaneNode(node.Left, left, rects)
+	layoutPaneNode(node.Right, right, rects)
+}
@@ -0,0 +1,155 @@
+package terminal
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/layout"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestPaneLayoutAndNavigation(t *testing.T) {
+	w := &Window{ID: "host", Width: 42, Height: 22}
+	right := &Window{ID: "right"}
+	below := &Window{ID: "below"}
+
+	if w.ActivePane() != w {
+		t.Fatal("unsplit window should be its own active pane")
+	}
+
+	w.SplitPane(right, layout.SplitVertical)
+	w.SplitPane(below, layout.SplitHorizontal)
+
+	// 40x20 content area: host | (right over below), one-cell separators
+	want := map[*Window]layout.Rect{
+		w:     {X: 0, Y: 0, W: 20, H: 20},
+		right: {X: 21, Y: 0, W: 19, H: 10},
+		below: {X: 21, Y: 11, W: 19, H: 9},
+	}
+	for _, pane := range w.Panes.Panes {
+		if pane.Rect != want[pane.Window] {
+			t.Errorf("pane %s rect = %+v, want %+v", pane.Window.ID, pane.Rect, want[pane.Window])
+		}
+	}
+	if w.ActivePane() != below {
+		t.Fatalf("expected the newest pane to be active, got %s", w.ActivePane().ID)
+	}
+
+	if p, x, y := w.PaneAt(25, 12); p != below || x != 4 || y != 1 {
+		t.Errorf("PaneAt(25, 12) = %v, %d, %d", p, x, y)
+	}
+	if p, _, _ := w.PaneAt(20, 5); p != nil {
+		t.Errorf("PaneAt on a separator = %s, want nil", p.ID)
+	}
+
+	if !w.FocusPaneDirection(0, -1) || w.ActivePane() != right {
+		t.Fatalf("expected up to focus the right pane, got %s", w.ActivePane().ID)
+	}
+	if !w.FocusPaneDirection(-1, 0) || w.ActivePane() != w {
+		t.Fatalf("expected left to focus the host pane, got %s", w.ActivePane().ID)
+	}
+	if w.FocusPaneDirection(-1, 0) {
+		t.Error("expected no pane left of the host pane")
+	}
+
+	w.ResizePane(1, 0, 0.25)
+	if got := w.Panes.Panes[0].Rect.W; got != 30 {
+		t.Errorf("host pane width after resize = %d, want 30", got)
+	}
+
+	w.CyclePane(-1)
+	if w.ActivePane() != below {
+		t.Errorf("expected cycling back to wrap to the last pane, got %s", w.ActivePane().ID)
+	}
+
+	w.ClosePane(right)
+	w.ClosePane(below)
+	if w.HasPanes() {
+		t.Error("expected a window left with its own pane to no longer be split")
+	}
+}
+
+func TestSynchronizedOutputOfPanes(t *testing.T) {
+	w := &Window{ID: "host", Width: 42, Height: 22, Terminal: vt.NewEmulator(40, 20)}
+	pane := &Window{ID: "pane", Terminal: vt.NewEmulator(20, 20)}
+	w.SplitPane(pane, layout.SplitVertical)
+
+	if w.SynchronizedOutput() {
+		t.Fatal("expected no synchronized update before one starts")
+	}
+	_, _ = pane.Terminal.Write([]byte("\x1b[?2026h"))
+	if !w.SynchronizedOutput() {
+		t.Error("expected a synchronized update in a pane to hold the window")
+	}
+	_, _ = pane.Terminal.Write([]byte("\x1b[?2026l"))
+	if w.SynchronizedOutput() {
+		t.Error("expected the window to be shown once the pane's update ends")
+	}
+}
+
+func TestBreakPane(t *testing.T) {
+	w := &Window{ID: "host", Width: 42, Height: 22}
+	a := &Window{ID: "a"}
+	b := &Window{ID: "b"}
+	w.SplitPane(a, layout.SplitVertical)
+	w.SplitPane(b, layout.SplitHorizontal)
+
+	// Breaking out another pane returns that pane's window
+	if got := w.BreakPane(); got != b {
+		t.Fatalf("BreakPane() = %v, want pane b", got)
+	}
+	if len(w.Panes.Panes) != 2 || w.FindPane("b") != nil {
+		t.Fatalf("expected b to be removed from the window, panes: %v", w.PaneWindows())
+	}
+
+	w.SplitPane(b, layout.SplitHorizontal)
+	w.FocusPaneAt(0, 0)
+
+	// Breaking out the window's own terminal hands the other panes to a new host
+	host := w.BreakPane()
+	if host != a {
+		t.Fatalf("BreakPane() of the host pane = %v, want pane a", host)
+	}
+	if w.HasPanes() {
+		t.Error("expected the window to keep only its own terminal")
+	}
+	if !host.HasPanes() || host.FindPane("b") == nil || host.ActivePane() != a {
+		t.Errorf("expected the new window to hold a and b, got %v", host.PaneWindows())
+	}
+	if host.Width != w.Width || host.Height != w.Height {
+		t.Errorf("new window size = %dx%d, want %dx%d", host.Width, host.Height, w.Width, w.Height)
+	}
+}
+
+func TestRestorePanes(t *testing.T) {
+	w := &Window{ID: "host", Width: 42, Height: 22}
+	a := &Window{ID: "a"}
+	b := &Window{ID: "b"}
+	w.SplitPane(a, layout.SplitVertical)
+	w.SplitPane(b, layout.SplitHorizontal)
+	w.FocusPaneAt(0, 0)
+
+	ids := make(map[string]int)
+	for _, pane := range w.Panes.Panes {
+		ids[pane.Window.ID] = pane.ID
+	}
+	saved, active := w.PaneLayout(), w.Panes.Active
+
+	restored := &Window{ID: "host", Width: 42, Height: 22}
+	ra, rb := &Window{ID: "a"}, &Window{ID: "b"}
+	restored.RestorePanes(saved.Deserialize(), map[int]*Window{ids["host"]: restored, ids["a"]: ra, ids["b"]: rb}, active)
+	if restored.ActivePane() != restored {
+		t.Errorf("expected the host pane to be active again, got %s", restored.ActivePane().ID)
+	}
+	for i, pane := range restored.Panes.Panes {
+		if pane.Rect != w.Panes.Panes[i].Rect || pane.Window.ID != w.Panes.Panes[i].Window.ID {
+			t.Errorf("pane %d = %s %+v, want %s %+v", i, pane.Window.ID, pane.Rect, w.Panes.Panes[i].Window.ID, w.Panes.Panes[i].Rect)
+		}
+	}
+
+	// A pane whose terminal is gone is dropped, and one pane is no split
+	lone := &Window{ID: "host", Width: 42, Height: 22}
+	lone.RestorePanes(saved.Deserialize(), map[int]*Window{ids["host"]: lone}, active)
+	if lone.HasPanes() {
+		t.Errorf("expected no split with a single pane left, got %v", lone.PaneWindows())
+	}
+}
@@ -0,0 +1,265 @@
+package terminal
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+// outputMergeWindow is how close together output chunks must arrive to be
+// recorded as one, which keeps floods from creating millions of tiny chunks.
+const outputMergeWindow = 10 * time.Millisecond
+
+// Replay speeds range from a quarter of real time to 16 times faster.
+const (
+	minReplaySpeed = 0.25
+	maxReplaySpeed = 16
+)
+
+// outputChunk is output received by a window at one point in time.
+type outputChunk struct {
+	at   time.Time
+	data []byte
+}
+
+// OutputRecorder keeps the most recent output of a window together with the
+// time it arrived. Once it holds more than its byte limit, the oldest chunks
+// are dropped. It is safe for concurrent use.
+type OutputRecorder struct {
+	mu       sync.Mutex
+	chunks   []outputChunk
+	size     int
+	maxBytes int
+	dropped  bool
+}
+
+// NewOutputRecorder returns a recorder keeping up to maxBytes of output, or
+// nil if maxBytes is not positive.
+func NewOutputRecorder(maxBytes int) *OutputRecorder {
+	if maxBytes <= 0 {
+		return nil
+	}
+	return &OutputRecorder{maxBytes: maxBytes}
+}
+
+// Record appends output received now. A nil recorder records nothing.
+func (r *OutputRecorder) Record(data []byte) {
+	if r == nil || len(data) == 0 {
+		return
+	}
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n := len(r.chunks); n > 0 && now.Sub(r.chunks[n-1].at) < outputMergeWindow {
+		r.chunks[n-1].data = append(r.chunks[n-1].data, data...)
+	} else {
+		r.chunks = append(r.chunks, outputChunk{at: now, data: append([]byte(nil), data...)})
+	}
+	r.size += len(data)
+
+	drop := 0
+	for r.size > r.maxBytes && drop < len(r.chunks)-1 {
+		r.size -= len(r.chunks[drop].data)
+		drop++
+	}
+	if drop > 0 {
+		// Copy the survivors so the dropped chunks can be garbage collected
+		r.chunks = append([]outputChunk(nil), r.chunks[drop:]...)
+		r.dropped = true
+	}
+}
+
+// Size returns the number of bytes recorded.
+func (r *OutputRecorder) Size() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.size
+}
+
+// snapshot returns a copy of the recorded chunks and whether older output
+// was dropped. Chunk data is shared, since recorded chunks are only appended
+// to while they are the newest.
+func (r *OutputRecorder) snapshot() ([]outputChunk, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	chunks := make([]outputChunk, len(r.chunks))
+	for i, c := range r.chunks {
+		chunks[i] = outputChunk{at: c.at, data: c.data[:len(c.data):len(c.data)]}
+	}
+	return chunks, r.dropped
+}
+
+// Replay plays a window's recorded output back into a separate emulator of
+// the same size. Pauses between chunks longer than config.ReplayIdleLimit are
+// shortened, so Position and Duration are playback time rather than wall time.
+type Replay struct {
+	Emulator  *vt.Emulator
+	Position  time.Duration // Playback position
+	Duration  time.Duration // Length of the recording
+	Speed     float64       // Playback speed, 1 is real time
+	Paused    bool
+	Truncated bool      // Older output was dropped, so playback starts mid-stream
+	Start     time.Time // When the first recorded chunk arrived
+
+	chunks        []outputChunk
+	offsets       []time.Duration // Playback offset of each chunk
+	next          int             // Next chunk to write
+	lastTick      time.Time
+	width, height int
+}
+
+// ErrNothingRecorded is returned when replay is started for a window without
+// recorded output.
+var ErrNothingRecorded = errors.New("no output recorded")
+
+// newReplay creates a replay of the recorded chunks positioned at the start.
+func newReplay(chunks []outputChunk, truncated bool, width, height int) *Replay {
+	r := &Replay{
+		Speed:     1,
+		Truncated: truncated,
+		Start:     chunks[0].at,
+		chunks:    chunks,
+		offsets:   make([]time.Duration, len(chunks)),
+		lastTick:  time.Now(),
+		width:     width,
+		height:    height,
+	}
+	for i := 1; i < len(chunks); i++ {
+		r.offsets[i] = r.offsets[i-1] + min(chunks[i].at.Sub(chunks[i-1].at), config.ReplayIdleLimit)
+	}
+	r.Duration = r.offsets[len(r.offsets)-1]
+	r.reset()
+	r.feed()
+	return r
+}
+
+// reset replaces the emulator with a blank one and rewinds to the start.
+func (r *Replay) reset() {
+	r.close()
+	emu := vt.NewEmulator(r.width, r.height)
+	emu.SetScrollbackMaxLines(0)
+	emu.SetWidthOptions(config.AmbiguousWidth, config.EmojiWidth)
+	applyThemeColors(emu)
+	// Discard the emulator's replies to queries in the recorded output,
+	// otherwise writing them blocks once the pipe fills
+	go func() { _, _ = io.Copy(io.Discard, emu) }()
+	r.Emulator = emu
+	r.next = 0
+}
+
+// close releases the emulator and stops its reply drain.
+func (r *Replay) close() {
+	if r.Emulator == nil {
+		return
+	}
+	_ = r.Emulator.Close()
+	if c, ok := r.Emulator.InputPipe().(io.Closer); ok {
+		_ = c.Close()
+	}
+	r.Emulator = nil
+}
+
+// feed writes every chunk up to the current position. It reports whether
+// anything was written.
+func (r *Replay) feed() bool {
+	written := false
+	for r.next < len(r.chunks) && r.offsets[r.next] <= r.Position {
+		_, _ = r.Emulator.Write(r.chunks[r.next].data)
+		r.next++
+		written = true
+	}
+	return written
+}
+
+// Advance moves playback forward by the time since the last call, scaled by
+// the speed. It reports whether the screen changed.
+func (r *Replay) Advance(now time.Time) bool {
+	elapsed := now.Sub(r.lastTick)
+	r.lastTick = now
+	if r.Paused || r.Finished() {
+		return false
+	}
+	r.Position = min(r.Position+time.Duration(float64(elapsed)*r.Speed), r.Duration)
+	return r.feed()
+}
+
+// Time returns when the output shown last arrived.
+func (r *Replay) Time() time.Time {
+	if r.next == 0 {
+		return r.Start
+	}
+	return r.chunks[r.next-1].at
+}
+
+// Finished reports whether all recorded output has been played.
+func (r *Replay) Finished() bool {
+	return r.next >= len(r.chunks)
+}
+
+// Seek moves playback by d, rebuilding the screen from the start of the
+// recording when seeking backwards.
+func (r *Replay) Seek(d time.Duration) {
+	target := min(max(r.Position+d, 0), r.Duration)
+	if target < r.Position {
+		r.reset()
+	}
+	r.Position = target
+	r.feed()
+}
+
+// TogglePause pauses or resumes playback. Resuming a finished replay starts
+// it over.
+func (r *Replay) TogglePause() {
+	if r.Paused && r.Finished() {
+		r.Seek(-r.Duration)
+	}
+	r.Paused = !r.Paused
+}
+
+// ChangeSpeed doubles the speed for positive steps and halves it for
+// negative ones, within the supported range.
+func (r *Replay) ChangeSpeed(step int) {
+	switch {
+	case step > 0:
+		r.Speed = min(r.Speed*2, maxReplaySpeed)
+	case step < 0:
+		r.Speed = max(r.Speed/2, minReplaySpeed)
+	}
+}
+
+// StartReplay starts replaying the window's recorded output. The window's
+// own emulator keeps receiving live output in the meantime.
+func (w *Window) StartReplay() error {
+	if w.Recorder == nil {
+		return ErrNothingRecorded
+	}
+	chunks, truncated := w.Recorder.snapshot()
+	if len(chunks) == 0 || w.Terminal == nil {
+		return ErrNothingRecorded
+	}
+	w.StopReplay()
+	w.ExitCopyMode()
+	w.ExitScrollbackMode()
+	w.Replay = newReplay(chunks, truncated, w.Terminal.Width(), w.Terminal.Height())
+	w.InvalidateCache()
+	return nil
+}
+
+// StopReplay leaves replay and shows live output again.
+func (w *Window) StopReplay() {
+	if w.Replay == nil {
+		return
+	}
+	w.Replay.close()
+	w.Replay = nil
+	w.InvalidateCache()
+}
@@ -0,0 +1,67 @@
+package terminal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+func TestOutputRecorderDropsOldest(t *testing.T) {
+	r := NewOutputRecorder(8)
+	r.Record([]byte("abcd"))
+	r.Record([]byte("efgh"))
+	if r.Size() != 8 {
+		t.Fatalf("expected 8 bytes recorded, got %d", r.Size())
+	}
+
+	// Chunks recorded in quick succession are merged, so make a new one
+	r.chunks[0].at = r.chunks[0].at.Add(-time.Second)
+	r.Record([]byte("ij"))
+	chunks, dropped := r.snapshot()
+	if !dropped || r.Size() != 2 || len(chunks) != 1 || string(chunks[0].data) != "ij" {
+		t.Errorf("expected only %q after dropping, got %d chunks, size %d, dropped %v", "ij", len(chunks), r.Size(), dropped)
+	}
+
+	if NewOutputRecorder(0) != nil {
+		t.Error("expected no recorder when recording is disabled")
+	}
+}
+
+func TestReplaySeekAndIdleLimit(t *testing.T) {
+	start := time.Now()
+	chunks := []outputChunk{
+		{at: start, data: []byte("one")},
+		{at: start.Add(time.Second), data: []byte(" two")},
+		{at: start.Add(time.Hour), data: []byte(" three")},
+	}
+	r := newReplay(chunks, false, 20, 2)
+	defer r.close()
+
+	if want := time.Second + config.ReplayIdleLimit; r.Duration != want {
+		t.Errorf("expected idle gap capped to a duration of %v, got %v", want, r.Duration)
+	}
+	if cell := r.Emulator.CellAt(0, 0); cell == nil || cell.Content != "o" {
+		t.Errorf("expected the first chunk shown at the start, got %v", cell)
+	}
+
+	r.Seek(time.Second)
+	if r.next != 2 {
+		t.Errorf("expected two chunks played after seeking 1s, got %d", r.next)
+	}
+	r.Seek(-time.Second)
+	if r.next != 1 || r.Position != 0 {
+		t.Errorf("expected rewind to the first chunk, got next %d at %v", r.next, r.Position)
+	}
+
+	r.Paused = true
+	if r.Advance(time.Now().Add(time.Minute)) {
+		t.Error("expected a paused replay not to advance")
+	}
+	r.Paused = false
+	r.Speed = maxReplaySpeed
+	r.Advance(r.lastTick.Add(time.Second))
+	if !r.Finished() || r.Time() != chunks[2].at {
+		t.Errorf("expected replay finished at the last chunk, got next %d", r.next)
+	}
+}
@@ -0,0 +1,32 @@
+package terminal
+
+// SecureInput reports whether the window's program reads a password: it
+// turned echo off, as password prompts do.
+func (w *Window) SecureInput() bool {
+	return w.secureInput.Load()
+}
+
+// SetSecureInput sets whether the window's program reads a password. The
+// daemon reports it for the windows of a session.
+func (w *Window) SetSecureInput(active bool) {
+	w.secureInput.Store(active)
+}
+
+// SampleSecureInput finds out whether the program of a local window reads a
+// password from the echo setting of its PTY, and reports whether that
+// changed. Daemon windows are left to the daemon's reports.
+func (w *Window) SampleSecureInput() bool {
+	if w.DaemonMode || w.Pty == nil {
+		return false
+	}
+	active := w.echoDisabled()
+	return w.secureInput.Swap(active) != active
+}
+
+// recordOutput keeps output for replay unless the program reads a password,
+// so what is typed at and shown around a password prompt is never kept.
+func (w *Window) recordOutput(data []byte) {
+	if !w.SecureInput() {
+		w.Recorder.Record(data)
+	}
+}
@@ -0,0 +1,17 @@
+package terminal
+
+import (
+	"testing"
+)
+
+func TestSecureInputIsNotRecorded(t *testing.T) {
+	w := &Window{Recorder: NewOutputRecorder(64)}
+	w.recordOutput([]byte("login: "))
+	w.SetSecureInput(true)
+	w.recordOutput([]byte("hunter2"))
+	w.SetSecureInput(false)
+	w.recordOutput([]byte("\r\n"))
+	if w.Recorder.Size() != len("login: \r\n") {
+		t.Errorf("expected output read with echo off to be left out, got %d bytes", w.Recorder.Size())
+	}
+}
@@ -0,0 +1,107 @@
+package terminal
+
+// HasTabs reports whether the window hosts tabs.
+func (w *Window) HasTabs() bool {
+	return len(w.Tabs) > 0
+}
+
+// ActiveTab returns the terminal of the active tab, or the window itself when
+// it has no tabs.
+func (w *Window) ActiveTab() *Window {
+	if !w.HasTabs() {
+		return w
+	}
+	return w.Tabs[min(max(w.TabIndex, 0), len(w.Tabs)-1)]
+}
+
+// AddTab adds tab after the active tab and makes it active. The first tab
+// added also turns the window's own terminal into its first tab.
+func (w *Window) AddTab(tab *Window) {
+	w.Zoomed = false // Tabs are drawn at normal size
+	if !w.HasTabs() {
+		w.Tabs = []*Window{w}
+		w.TabIndex = 0
+	}
+	w.TabIndex++
+	w.Tabs = append(w.Tabs[:w.TabIndex], append([]*Window{tab}, w.Tabs[w.TabIndex:]...)...)
+	w.layoutTabs()
+	w.MarkContentDirty()
+}
+
+// CloseTab removes a tab from the window and stops its process. As with
+// panes, the window's own tab only has its process stopped.
+func (w *Window) CloseTab(tab *Window) {
+	if !w.removeTab(tab) {
+		return
+	}
+	if tab == w {
+		w.stopProcess()
+	} else {
+		tab.Close()
+	}
+}
+
+// removeTab takes tab out of the window's tab list. A window left with only
+// its own tab no longer has tabs. It reports whether tab was one of the
+// window's tabs.
+func (w *Window) removeTab(tab *Window) bool {
+	index := -1
+	for i, t := range w.Tabs {
+		if t == tab {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return false
+	}
+
+	w.Tabs = append(w.Tabs[:index], w.Tabs[index+1:]...)
+	switch {
+	case len(w.Tabs) == 0:
+		w.Tabs = nil
+	case len(w.Tabs) == 1 && w.Tabs[0] == w:
+		w.Tabs = nil
+		w.TabIndex = 0
+		w.Resize(w.Width, w.Height)
+	default:
+		if index < w.TabIndex || w.TabIndex >= len(w.Tabs) {
+			w.TabIndex--
+		}
+		w.TabIndex = max(w.TabIndex, 0)
+	}
+	w.MarkContentDirty()
+	return true
+}
+
+// SelectTab makes the tab at index active.
+func (w *Window) SelectTab(index int) {
+	if index < 0 || index >= len(w.Tabs) || index == w.TabIndex {
+		return
+	}
+	w.TabIndex = index
+	w.MarkContentDirty()
+}
+
+// CycleTab activates the next (delta > 0) or previous (delta < 0) tab.
+func (w *Window) CycleTab(delta int) {
+	if !w.HasTabs() {
+		return
+	}
+	w.SelectTab(((w.TabIndex+delta)%len(w.Tabs) + len(w.Tabs)) % len(w.Tabs))
+}
+
+// layoutTabs resizes the terminals of the window's tabs to its content area
+// below the tab strip.
+func (w *Window) layoutTabs() {
+	cols, rows := max(w.Width-2, 1), max(w.Height-3, 1)
+	for _, tab := range w.Tabs {
+		if tab == w {
+			if w.Terminal != nil {
+				w.resizeTerminal(cols, rows)
+			}
+			continue
+		}
+		tab.Resize(cols+2, rows+2)
+	}
+}
@@ -0,0 +1,47 @@
+package terminal
+
+import (
+	"testing"
+)
+
+func TestTabs(t *testing.T) {
+	w := &Window{ID: "host", Width: 42, Height: 22}
+	a := &Window{ID: "a"}
+	b := &Window{ID: "b"}
+	w.AddTab(a)
+	w.AddTab(b)
+
+	if len(w.Tabs) != 3 || w.Tabs[0] != w || w.ActivePane() != b {
+		t.Fatalf("expected tabs [host a b] with b active, got %v (active %d)", w.Tabs, w.TabIndex)
+	}
+	if rect := w.ActivePaneRect(); rect.Y != 1 || rect.H != 19 {
+		t.Errorf("ActivePaneRect() = %+v, want the content area below the tab strip", rect)
+	}
+	if pane, _, _ := w.PaneAt(3, 0); pane != nil {
+		t.Error("expected no terminal under the tab strip")
+	}
+	if pane, x, y := w.PaneAt(3, 4); pane != b || x != 3 || y != 3 {
+		t.Errorf("PaneAt(3, 4) = %v, %d, %d, want b, 3, 3", pane, x, y)
+	}
+
+	w.CycleTab(1)
+	if w.ActiveTab() != w {
+		t.Errorf("expected cycling past the last tab to wrap to the first")
+	}
+	w.CycleTab(-1)
+	if w.ActiveTab() != b {
+		t.Errorf("expected cycling back to wrap to the last tab")
+	}
+
+	// Removing the active tab activates the one before it
+	w.removeTab(b)
+	if w.ActiveTab() != a || w.FindPane("b") != nil {
+		t.Errorf("expected a to be active after removing b, got %v", w.ActiveTab())
+	}
+
+	// A window left with only its own tab no longer has tabs
+	w.removeTab(a)
+	if w.HasTabs() || w.ActivePane() != w {
+		t.Errorf("expected the window to have no tabs, got %v", w.Tabs)
+	}
+}
@@ -0,0 +1,97 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxTriggerLine caps the bytes of an unterminated line kept for trigger
+// matching, so output without newlines cannot grow it without bound.
+const maxTriggerLine = 4096
+
+// maxTriggerHits caps the hits waiting to be taken.
+const maxTriggerHits = 16
+
+// TriggerHit is a line of window output that matched a trigger.
+type TriggerHit struct {
+	Trigger config.CompiledTrigger
+	Line    string // Output line, without escape sequences
+	Match   string // Part of the line the pattern matched
+}
+
+// triggerScanner splits window output into lines and matches them against
+// the configured triggers.
+type triggerScanner struct {
+	mu    sync.Mutex
+	tail  []byte               // Unterminated last line
+	hits  []TriggerHit         // Hits since TakeTriggerHits was last called
+	fired map[string]time.Time // When each trigger last fired, for the cooldown
+}
+
+// scanTriggers matches the complete lines of output against the triggers.
+func (w *Window) scanTriggers(data []byte) {
+	triggers := config.ActiveTriggers()
+	if len(triggers) == 0 {
+		return
+	}
+	s := &w.triggerScan
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tail = append(s.tail, data...)
+	for {
+		i := bytes.IndexByte(s.tail, '\n')
+		if i < 0 {
+			break
+		}
+		s.matchLine(triggers, string(s.tail[:i]))
+		s.tail = s.tail[i+1:]
+	}
+	if len(s.tail) > maxTriggerLine {
+		s.tail = s.tail[len(s.tail)-maxTriggerLine:]
+	}
+	// Keep the buffer from pinning a large batch
+	s.tail = append([]byte(nil), s.tail...)
+}
+
+// matchLine records a hit for each trigger that matches a line and is not
+// cooling down.
+func (s *triggerScanner) matchLine(triggers []config.CompiledTrigger, line string) {
+	line = strings.TrimRight(ansi.Strip(line), "\r")
+	if line == "" {
+		return
+	}
+	now := time.Now()
+	for _, t := range triggers {
+		match := t.Regexp.FindString(line)
+		if match == "" {
+			continue
+		}
+		key := t.Action + "\x00" + t.Pattern
+		if now.Sub(s.fired[key]) < config.TriggerCooldown {
+			continue
+		}
+		if s.fired == nil {
+			s.fired = make(map[string]time.Time)
+		}
+		s.fired[key] = now
+		if len(s.hits) < maxTriggerHits {
+			s.hits = append(s.hits, TriggerHit{Trigger: t, Line: line, Match: match})
+		}
+	}
+}
+
+// TakeTriggerHits returns the output lines that matched triggers since the
+// last call.
+func (w *Window) TakeTriggerHits() []TriggerHit {
+	w.triggerScan.mu.Lock()
+	defer w.triggerScan.mu.Unlock()
+	hits := w.triggerScan.hits
+	w.triggerScan.hits = nil
+	return hits
+}
@@ -0,0 +1,36 @@
+package terminal
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+)
+
+func TestScanTriggers(t *testing.T) {
+	config.SetTriggers([]config.Trigger{
+		{Pattern: `panic:`},
+		{Pattern: `Connection refused`, Action: "urgent"},
+		{Pattern: `[`, Action: "notify"},
+	})
+	defer config.SetTriggers(nil)
+
+	w := &Window{}
+	w.noteOutput([]byte("ok\r\n\x1b[31mpanic: boom"))
+	if hits := w.TakeTriggerHits(); len(hits) != 0 {
+		t.Fatalf("expected no hit before the line ends, got %+v", hits)
+	}
+	w.noteOutput([]byte("\r\ncurl: Connection refused\npanic: again\n"))
+	hits := w.TakeTriggerHits()
+	if len(hits) != 2 {
+		t.Fatalf("expected two hits, the repeated panic cooling down, got %+v", hits)
+	}
+	if hits[0].Line != "panic: boom" || hits[0].Match != "panic:" || hits[0].Trigger.Action != "notify" {
+		t.Errorf("expected the stripped panic line with the default action, got %+v", hits[0])
+	}
+	if hits[1].Trigger.Action != "urgent" {
+		t.Errorf("expected the urgent trigger, got %+v", hits[1])
+	}
+	if hits := w.TakeTriggerHits(); len(hits) != 0 {
+		t.Errorf("expected hits to be taken once, got %+v", hits)
+	}
+}
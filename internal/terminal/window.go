@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"image/color"
 	"io"
+	"net/url"
 	"os"
 	"os/exec"
 	"runtime"
@@ -21,7 +22,9 @@ import (
 	xpty "github.com/charmbracelet/x/xpty"
 
 	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/debuglog"
 	"github.com/Gaurav-Gosain/tuios/internal/pool"
+	"github.com/Gaurav-Gosain/tuios/internal/system"
 	"github.com/Gaurav-Gosain/tuios/internal/theme"
 	"github.com/Gaurav-Gosain/tuios/internal/vt"
 )
@@ -81,6 +84,19 @@ var (
 type Window struct {
 	Title                  string
 	CustomName             string // User-defined window name
+	RuleName               string // Name a naming rule gave the window, followed until the window is renamed
+	Group                  string // ID of the window group the window belongs to ("" when ungrouped)
+	SwallowedBy            string // ID of the window hiding this one while it is open ("" when not swallowed)
+	PiP                    bool   // Shown as a small always-on-top miniature
+	PrePiPX                int    // Geometry to return to when leaving picture-in-picture
+	PrePiPY                int
+	PrePiPWidth            int
+	PrePiPHeight           int
+	Floating               bool // Kept out of the tiling layout in tiling mode
+	FloatX                 int  // Geometry the window last had while floating
+	FloatY                 int
+	FloatWidth             int
+	FloatHeight            int
 	Width                  int
 	Height                 int
 	X                      int
@@ -97,6 +113,7 @@ type Window struct {
 	PositionDirty          bool
 	CachedContent          string
 	CachedLayer            *lipgloss.Layer
+	LineCache              *LineCache // Per-row render cache for damage-region rendering
 	LastTerminalSeq        int
 	IsBeingManipulated     bool               // True when being dragged or resized
 	UpdateCounter          int                // Counter for throttling background updates
@@ -118,16 +135,62 @@ type Window struct {
 	SelectionCursor        struct{ X, Y int } // Current cursor position in selection mode
 	ProcessExited          bool               // True when process has exited
 	// Enhanced text selection support
-	SelectionMode int // 0 = character, 1 = word, 2 = line
+	SelectionMode int // 0 = character, 1 = word, 2 = line, 3 = block
 	LastClickTime time.Time
 	LastClickX    int
 	LastClickY    int
 	ClickCount    int // Track number of consecutive clicks for word/line selection
 	// Scrollback mode support
-	ScrollbackMode   bool // True when viewing scrollback history
-	ScrollbackOffset int  // Number of lines scrolled back (0 = at bottom, viewing live output)
+	ScrollbackMode   bool      // True when viewing scrollback history
+	ScrollbackOffset int       // Number of lines scrolled back (0 = at bottom, viewing live output)
+	ShowTimestamps   bool      // Show when each line appeared in a gutter while viewing scrollback
+	LastViewed       time.Time // Last time the window was focused or visible (for scrollback trimming)
 	// Alternate screen buffer tracking for TUI detection
 	IsAltScreen bool // True when application is using alternate screen buffer (nvim, vim, etc.)
+	// Mouse passthrough control
+	MouseCapture bool // tuios handles the mouse even when the application requested mouse reporting
+	// Zoom: content drawn at double cell size for reading small output
+	Zoomed bool
+	// Input lock: keys are ignored while the window is focused, except the unlock chord
+	InputLocked bool
+	// Program running in the window, looked up periodically for its icon
+	App string
+	// Icon set over IPC, shown in place of the program's icon
+	Icon string
+	// Tags set on the window, sorted, that tag: selectors, the sidebar
+	// filter and the tag view pick windows by
+	Tags []string
+	// Title bar text last drawn, redrawn once its fields change
+	TitleBar string
+	// Executable of the command the window was started with, the key of its
+	// remembered geometry
+	Command string
+	// Temporary file shown in the window, removed when the window closes
+	// ("" = none)
+	TempFile string
+	// Dock badges: bells rung and whether output arrived while the window was out of view
+	Bells    int
+	Activity bool
+	// When the window was flagged urgent out of view, by a bell, an urgent
+	// trigger or a failed command (zero = not urgent)
+	UrgentSince time.Time
+	// Minimum terminal size in cells pinned interactively, on top of window
+	// rules (0 = not set)
+	MinWidth  int
+	MinHeight int
+	// Per-window scrollback limit set interactively, overriding window rules
+	// and the global limit (nil = not set, 0 = no scrollback)
+	ScrollbackOverride *int
+	// Lower scrollback limit the window rules gave the window's name and
+	// when it may be applied (zero = none pending)
+	scrollbackShrinkTo int
+	scrollbackShrinkAt time.Time
+	// OSC 52 clipboard write policy set for this window, overriding window
+	// rules and the global policy ("" = not set)
+	ClipboardPolicy string
+	// Output replay
+	Recorder *OutputRecorder // Recent output kept for replay (nil when recording is disabled)
+	Replay   *Replay         // Active replay shown instead of live output (nil when not replaying)
 	// Cursor style tracking for passthrough to parent terminal
 	CursorStyle vt.CursorStyle // Current cursor style (block, underline, bar)
 	CursorBlink bool           // Whether cursor should blink
@@ -136,6 +199,11 @@ type Window struct {
 	CellPixelHeight int
 	// Vim-style copy mode
 	CopyMode *CopyMode // Copy mode state (nil when not active)
+	// Intra-window panes
+	Panes *PaneSet // Panes sharing the window's frame (nil until the window is split)
+	// Tabs within the window
+	Tabs     []*Window // Terminals of the window's tabs, the window itself first (nil without tabs)
+	TabIndex int       // Index of the active tab in Tabs
 	// Daemon session support
 	PTYID             string               // ID of daemon-managed PTY (empty for local PTYs)
 	DaemonMode        bool                 // True when PTY is managed by daemon
@@ -143,19 +211,48 @@ type Window struct {
 	DaemonResizeFunc  func(w, h int) error // Callback for resizing daemon PTY
 	DaemonCloseFunc   func()               // Callback when window is closed (to notify daemon)
 	OnProcessExit     func()               // Callback when PTY process exits (to close window)
-	outputChan        chan []byte          // Channel for serializing daemon PTY output writes
+	outputMu          sync.Mutex           // Guards outputQueue and outputClosed
+	outputQueue       []byte               // Daemon PTY output waiting for the output writer
+	outputClosed      bool                 // The output writer stopped, so output is dropped
+	outputReady       chan struct{}        // Wakes the output writer when outputQueue has data
 	outputDone        chan struct{}        // Signal to stop output writer goroutine
 	suppressCallbacks atomic.Bool          // Suppress VT emulator callbacks during state restoration (prevents race conditions)
+	// PTY output flow control
+	outputBytes   atomic.Int64 // Bytes applied to the emulator since the last frame
+	outputSeen    atomic.Bool  // Output arrived since TakeActivity was last called
+	bellsRung     atomic.Int32 // Bells rung since TakeBells was last called
+	skippedFrames int          // Consecutive frames skipped while output floods
+	// Output lines matched against triggers
+	triggerScan triggerScanner
+	// Commands the shell reported as finished
+	commands commandLog
+	// Clipboard write asked for with OSC 52
+	clipboard clipboardSlot
+	// Output received in total, for the metrics endpoint
+	totalOutput atomic.Uint64
+	// Program reads a password with echo off, so output is not recorded
+	secureInput atomic.Bool
 
 	KittyPassthroughFunc func(cmd *vt.KittyCommand, rawData []byte)
 	SixelPassthroughFunc func(cmd *vt.SixelCommand, cursorX, cursorY, absLine int)
 
 	// cmdWaitOnce ensures cmd.Wait() is only called once to prevent race conditions
 	cmdWaitOnce sync.Once
+	// processDone is closed once the shell has exited and been reaped
+	processDone chan struct{}
 	// ioWg tracks I/O goroutines for clean shutdown
 	ioWg sync.WaitGroup
 }
 
+// LineCache holds the rendered rows of a window's terminal content so that
+// only rows damaged since the previous frame need to be rendered again.
+type LineCache struct {
+	Lines     []string // Rendered content of each row
+	Width     int      // Number of columns the rows were rendered with
+	Optimized bool     // True when rows were rendered with the unfocused style path
+	CursorRow int      // Row holding the rendered cursor (-1 when none)
+}
+
 // CopyModeState represents the current state within copy mode
 type CopyModeState int
 
@@ -168,6 +265,8 @@ const (
 	CopyModeVisualChar
 	// CopyModeVisualLine is line-wise visual selection
 	CopyModeVisualLine
+	// CopyModeVisualBlock is rectangular visual selection
+	CopyModeVisualBlock
 )
 
 // Position represents a 2D coordinate
@@ -224,10 +323,29 @@ type CopyMode struct {
 	CountStartTime time.Time // When count entry started (for timeout)
 }
 
+// Visual reports whether copy mode is in one of its visual selection states.
+func (cm *CopyMode) Visual() bool {
+	return cm.State == CopyModeVisualChar || cm.State == CopyModeVisualLine || cm.State == CopyModeVisualBlock
+}
+
 // NewWindow creates a new terminal window with the specified properties.
 // It spawns a shell process, sets up PTY communication, and initializes the virtual terminal.
-// Returns nil if window creation fails.
-func NewWindow(id, title string, x, y, width, height, z int, exitChan chan string) *Window {
+// Returns nil if window creation fails. env holds extra "NAME=value"
+// environment variables for the shell.
+func NewWindow(id, title string, x, y, width, height, z int, exitChan chan string, env ...string) *Window {
+	return NewWindowInDir(id, title, x, y, width, height, z, exitChan, "", env...)
+}
+
+// NewWindowInDir creates a new terminal window like NewWindow, starting the
+// shell in dir. An empty or missing dir falls back to the current directory.
+func NewWindowInDir(id, title string, x, y, width, height, z int, exitChan chan string, dir string, env ...string) *Window {
+	return NewCommandWindow(id, title, x, y, width, height, z, exitChan, dir, nil, env...)
+}
+
+// NewCommandWindow creates a new terminal window like NewWindowInDir that
+// runs argv instead of the shell, so the window closes when it exits. An
+// empty argv starts the shell.
+func NewCommandWindow(id, title string, x, y, width, height, z int, exitChan chan string, dir string, argv []string, env ...string) *Window {
 	if title == "" {
 		title = "Terminal " + id[:8]
 	}
@@ -239,6 +357,7 @@ func NewWindow(id, title string, x, y, width, height, z int, exitChan chan strin
 	terminal := vt.NewEmulator(terminalWidth, terminalHeight)
 	// Set scrollback buffer size from config (default: 10000, configurable via --scrollback-lines or config file)
 	terminal.SetScrollbackMaxLines(config.ScrollbackLines)
+	terminal.SetWidthOptions(config.AmbiguousWidth, config.EmojiWidth)
 
 	// Set cell size for XTWINOPS terminal size reporting
 	// Using 10x20 pixels as reasonable defaults for a typical monospace font
@@ -261,20 +380,11 @@ func NewWindow(id, title string, x, y, width, height, z int, exitChan chan strin
 		CachedLayer:        nil,
 		IsBeingManipulated: false,
 		IsAltScreen:        false,
+		Recorder:           NewOutputRecorder(config.ReplayBufferBytes),
 	}
 
 	// Apply theme colors to the terminal (only if theming is enabled)
-	if theme.IsEnabled() {
-		terminal.SetThemeColors(
-			theme.TerminalFg(),
-			nil, // Always use transparent background so TUI apps render correctly
-			theme.TerminalCursor(),
-			theme.GetANSIPalette(),
-		)
-	} else {
-		// When theming is disabled, just set nil colors to use terminal defaults
-		terminal.SetThemeColors(nil, nil, nil, [16]color.Color{})
-	}
+	applyThemeColors(terminal)
 
 	// Set up callbacks to track terminal state changes
 	terminal.SetCallbacks(vt.Callbacks{
@@ -295,29 +405,39 @@ func NewWindow(id, title string, x, y, width, height, z int, exitChan chan strin
 			// Update window title from terminal escape sequence
 			if title != "" {
 				window.Title = title
+				window.Dirty = true
 			}
 		},
+		Bell: func() {
+			if !window.suppressCallbacks.Load() {
+				window.bellsRung.Add(1)
+			}
+		},
+		CommandFinished: window.noteCommand,
+		ClipboardWrite:  window.noteClipboardWrite,
 	})
+	window.ApplyScrollbackLimit()
 
 	// Detect shell
-	shell := detectShell()
+	if len(argv) == 0 {
+		argv = []string{detectShell()}
+	}
 
 	// Set up environment
 	// #nosec G204 - shell is intentionally user-controlled for terminal functionality
-	cmd := exec.Command(shell)
+	cmd := exec.Command(argv[0], argv[1:]...)
+	if dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			cmd.Dir = dir
+		}
+	}
 
 	// Get cached terminal environment (detected once on first window creation)
 	termType, colorTerm := getTerminalEnv()
 
 	// Debug logging for terminal environment
-	if os.Getenv("TUIOS_DEBUG_INTERNAL") == "1" {
-		debugMsg := fmt.Sprintf("[%s] NewWindow TERM=%s COLORTERM=%s (envTERM=%s envCOLORTERM=%s)\n",
-			time.Now().Format("15:04:05.000"), termType, colorTerm, os.Getenv("TERM"), os.Getenv("COLORTERM"))
-		if f, err := os.OpenFile("/tmp/tuios-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
-			_, _ = f.WriteString(debugMsg)
-			_ = f.Close()
-		}
-	}
+	debuglog.Logf(debuglog.Debug, "PTY", "NewWindow TERM=%s COLORTERM=%s (envTERM=%s envCOLORTERM=%s)",
+		termType, colorTerm, os.Getenv("TERM"), os.Getenv("COLORTERM"))
 
 	cmd.Env = append(os.Environ(),
 		"TERM="+termType,
@@ -326,6 +446,7 @@ func NewWindow(id, title string, x, y, width, height, z int, exitChan chan strin
 		"TERM_PROGRAM_VERSION=0.1.0", // Version for compatibility checking
 		"TUIOS_WINDOW_ID="+id,
 	)
+	cmd.Env = append(cmd.Env, env...)
 
 	// Create PTY with initial size
 	// xpty requires dimensions at creation time
@@ -359,6 +480,7 @@ func NewWindow(id, title string, x, y, width, height, z int, exitChan chan strin
 	window.Pty = ptyInstance
 	window.Cmd = cmd
 	window.cancelFunc = cancel
+	window.processDone = make(chan struct{})
 
 	// Store shell's process group ID for later detection of foreground processes
 	if cmd.Process != nil {
@@ -382,9 +504,10 @@ func NewWindow(id, title string, x, y, width, height, z int, exitChan chan strin
 			}
 		}()
 
-		// Wait for process to exit using sync.Once to prevent race conditions
-		// with Close() which may also wait for the process.
-		window.waitForCmd()
+		// Wait for process to exit; Close() relies on processDone to know
+		// when the shell is gone.
+		window.waitForCmd(cmd)
+		close(window.processDone)
 
 		// Mark process as exited
 		window.ProcessExited = true
@@ -421,6 +544,7 @@ func NewDaemonWindow(id, title string, x, y, width, height, z int, ptyID string)
 	terminalHeight := max(height-2, 1)
 	terminal := vt.NewEmulator(terminalWidth, terminalHeight)
 	terminal.SetScrollbackMaxLines(config.ScrollbackLines)
+	terminal.SetWidthOptions(config.AmbiguousWidth, config.EmojiWidth)
 	terminal.SetCellSize(10, 20)
 
 	window := &Window{
@@ -440,9 +564,10 @@ func NewDaemonWindow(id, title string, x, y, width, height, z int, ptyID string)
 		CachedLayer:        nil,
 		IsBeingManipulated: false,
 		IsAltScreen:        false,
+		Recorder:           NewOutputRecorder(config.ReplayBufferBytes),
 		PTYID:              ptyID,
 		DaemonMode:         true,
-		outputChan:         make(chan []byte, 1000), // Buffered channel for output
+		outputReady:        make(chan struct{}, 1),
 		outputDone:         make(chan struct{}),
 		// suppressCallbacks defaults to false (zero value)
 	}
@@ -451,16 +576,7 @@ func NewDaemonWindow(id, title string, x, y, width, height, z int, ptyID string)
 	go window.outputWriter()
 
 	// Apply theme colors to the terminal (only if theming is enabled)
-	if theme.IsEnabled() {
-		terminal.SetThemeColors(
-			theme.TerminalFg(),
-			nil, // Always use transparent background so TUI apps render correctly
-			theme.TerminalCursor(),
-			theme.GetANSIPalette(),
-		)
-	} else {
-		terminal.SetThemeColors(nil, nil, nil, [16]color.Color{})
-	}
+	applyThemeColors(terminal)
 
 	// Set up callbacks to track terminal state changes
 	terminal.SetCallbacks(vt.Callbacks{
@@ -481,9 +597,18 @@ func NewDaemonWindow(id, title string, x, y, width, height, z int, ptyID string)
 			// Update window title from terminal escape sequence
 			if title != "" {
 				window.Title = title
+				window.Dirty = true
+			}
+		},
+		Bell: func() {
+			if !window.suppressCallbacks.Load() {
+				window.bellsRung.Add(1)
 			}
 		},
+		CommandFinished: window.noteCommand,
+		ClipboardWrite:  window.noteClipboardWrite,
 	})
+	window.ApplyScrollbackLimit()
 
 	return window
 }
@@ -492,7 +617,7 @@ func NewDaemonWindow(id, title string, x, y, width, height, z int, ptyID string)
 // This ensures output is written in order for daemon mode windows.
 func (w *Window) outputWriter() {
 	// Nil channel check - if channels aren't initialized, exit
-	if w.outputDone == nil || w.outputChan == nil {
+	if w.outputDone == nil || w.outputReady == nil {
 		return
 	}
 
@@ -500,21 +625,44 @@ func (w *Window) outputWriter() {
 		select {
 		case <-w.outputDone:
 			return
-		case data, ok := <-w.outputChan:
-			if !ok {
-				// Channel closed
-				return
-			}
-			if w.Terminal != nil {
+		case <-w.outputReady:
+			// Everything queued since the last wake-up is applied in as few
+			// emulator writes as possible, so a flooding PTY costs one dirty
+			// mark per batch instead of one per chunk
+			for batch := w.takeOutput(); len(batch) > 0; batch = w.takeOutput() {
+				if w.Terminal == nil {
+					continue
+				}
 				w.ioMu.Lock()
-				_, _ = w.Terminal.Write(data)
+				_, _ = w.Terminal.Write(batch)
 				w.ioMu.Unlock()
-				w.MarkContentDirty()
+				w.recordOutput(batch)
+				w.noteOutput(batch)
+				// No dirty mark here: the render loop picks up the damage on its
+				// next tick, which lets flooding windows skip intermediate frames
+				w.outputBytes.Add(int64(len(batch)))
 			}
 		}
 	}
 }
 
+// takeOutput removes up to config.OutputBatchMaxBytes of queued output and
+// returns it, or nil when nothing is queued.
+func (w *Window) takeOutput() []byte {
+	w.outputMu.Lock()
+	defer w.outputMu.Unlock()
+	n := min(len(w.outputQueue), config.OutputBatchMaxBytes)
+	if n == 0 {
+		return nil
+	}
+	batch := w.outputQueue[:n:n]
+	w.outputQueue = w.outputQueue[n:]
+	if len(w.outputQueue) == 0 {
+		w.outputQueue = nil
+	}
+	return batch
+}
+
 // StartDaemonResponseReader starts a goroutine to read and DRAIN responses from
 // the terminal emulator. We don't forward these to the PTY because:
 //  1. Responses were appearing as visible escape sequences in the output
@@ -548,46 +696,84 @@ func (w *Window) WriteOutput(data []byte) {
 		w.ioMu.Lock()
 		_, _ = w.Terminal.Write(data)
 		w.ioMu.Unlock()
+		w.recordOutput(data)
+		w.noteOutput(data)
 		w.MarkContentDirty()
 	}
 }
 
-// WriteOutputAsync writes output data to the terminal emulator without blocking.
-// Used in daemon mode to process PTY output received from the daemon.
-// Data is queued to a channel and written in order by the outputWriter goroutine.
+// WriteOutputAsync queues output data for the terminal emulator and returns
+// right away. Used in daemon mode from the client's read loop, which serves
+// every window and must never wait for one. Output is queued without limit
+// and applied in order by the outputWriter goroutine, which catches up by
+// writing everything queued at once.
 func (w *Window) WriteOutputAsync(data []byte) {
-	if w.Terminal == nil || w.outputChan == nil {
+	if w.Terminal == nil || w.outputReady == nil {
 		return
 	}
-	// Copy data since the caller's buffer may be reused
-	dataCopy := make([]byte, len(data))
-	copy(dataCopy, data)
+	w.outputMu.Lock()
+	if w.outputClosed {
+		w.outputMu.Unlock()
+		return
+	}
+	// Appending copies data, since the caller's buffer may be reused
+	w.outputQueue = append(w.outputQueue, data...)
+	w.outputMu.Unlock()
 
-	// Queue to channel - non-blocking with buffered channel
 	select {
-	case w.outputChan <- dataCopy:
-		// Successfully queued
+	case w.outputReady <- struct{}{}:
 	default:
-		// Channel full - drop data (shouldn't happen with large buffer)
+		// The writer is already woken up and takes this output along
+	}
+}
+
+// ShouldSkipFrame reports whether re-rendering this window can be skipped for
+// the current frame because it is flooding output faster than it can be
+// displayed. Intermediate frames are dropped, but a flooding window is still
+// rendered at least every config.OutputFloodMaxSkippedFrames frames.
+// It must be called once per frame.
+func (w *Window) ShouldSkipFrame() bool {
+	flooding := w.outputBytes.Swap(0) > config.OutputFloodBytesPerFrame
+	if flooding && w.skippedFrames < config.OutputFloodMaxSkippedFrames {
+		w.skippedFrames++
+		return true
+	}
+	w.skippedFrames = 0
+	return false
+}
+
+// ThemeColors returns the colors terminals get from the current theme: the
+// default foreground and cursor colors and the first 16 indexed colors, all
+// nil when theming is disabled, and the host colors reported to applications.
+func ThemeColors() (fg, cursor color.Color, palette [16]color.Color, hostFg, hostBg color.Color) {
+	if theme.IsEnabled() {
+		fg, cursor, palette = theme.TerminalFg(), theme.TerminalCursor(), theme.GetANSIPalette()
 	}
+	hostFg, hostBg = theme.HostColors()
+	if hostBg == nil && theme.IsEnabled() {
+		hostBg = theme.TerminalBg()
+	}
+	return fg, cursor, palette, hostFg, hostBg
+}
+
+// applyThemeColors sets the emulator's palette and default colors from the
+// current theme, or the standard colors when theming is disabled, and tells it
+// the host's colors so color queries from applications are answered right.
+func applyThemeColors(t *vt.Emulator) {
+	fg, cursor, palette, hostFg, hostBg := ThemeColors()
+	// Always use transparent background so TUI apps render correctly
+	t.SetThemeColors(fg, nil, cursor, palette)
+	t.SetHostColors(hostFg, hostBg)
 }
 
 // UpdateThemeColors updates the terminal colors when the theme changes
 func (w *Window) UpdateThemeColors() {
 	if w.Terminal != nil {
-		if theme.IsEnabled() {
-			w.Terminal.SetThemeColors(
-				theme.TerminalFg(),
-				nil, // Always use transparent background so TUI apps render correctly
-				theme.TerminalCursor(),
-				theme.GetANSIPalette(),
-			)
-		} else {
-			w.Terminal.SetThemeColors(nil, nil, nil, [16]color.Color{})
-		}
+		applyThemeColors(w.Terminal)
 		// Mark the window as dirty to trigger a redraw
 		w.Dirty = true
 		w.ContentDirty = true
+		w.LineCache = nil
 	}
 }
 
@@ -803,15 +989,8 @@ func (w *Window) handleIOOperations() {
 				}
 				if n > 0 {
 					// Debug: Log all data from PTY (applications sending queries)
-					if os.Getenv("TUIOS_DEBUG_INTERNAL") == "1" {
-						if len(buf[:n]) >= 2 && buf[0] == '\x1b' {
-							debugMsg := fmt.Sprintf("[%s] PTY->Terminal query: %q (hex: % x)\n",
-								time.Now().Format("15:04:05.000"), string(buf[:n]), buf[:n])
-							if f, err := os.OpenFile("/tmp/tuios-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
-								_, _ = f.WriteString(debugMsg)
-								_ = f.Close()
-							}
-						}
+					if n >= 2 && buf[0] == '\x1b' && debuglog.Enabled() && !w.SecureInput() {
+						debuglog.Logf(debuglog.Debug, "PTY", "PTY->Terminal query: %q (hex: % x)", string(buf[:n]), buf[:n])
 					}
 
 					// Pass through cursor style sequences to parent terminal
@@ -824,6 +1003,9 @@ func (w *Window) handleIOOperations() {
 						_, _ = w.Terminal.Write(buf[:n]) // Ignore write errors in read loop
 					}
 					w.ioMu.RUnlock()
+					w.recordOutput(buf[:n])
+					w.noteOutput(buf[:n])
+					w.outputBytes.Add(int64(n))
 				}
 			}
 		}
@@ -871,15 +1053,8 @@ func (w *Window) handleIOOperations() {
 					data := buf[:n]
 
 					// Debug: Log all escape sequences from terminal when debug mode is enabled
-					if os.Getenv("TUIOS_DEBUG_INTERNAL") == "1" {
-						if len(data) >= 2 && data[0] == '\x1b' {
-							debugMsg := fmt.Sprintf("[%s] Terminal->PTY escape seq: %q (hex: % x)\n",
-								time.Now().Format("15:04:05.000"), string(data), data)
-							if f, err := os.OpenFile("/tmp/tuios-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
-								_, _ = f.WriteString(debugMsg)
-								_ = f.Close()
-							}
-						}
+					if len(data) >= 2 && data[0] == '\x1b' && debuglog.Enabled() {
+						debuglog.Logf(debuglog.Debug, "PTY", "Terminal->PTY escape seq: %q (hex: % x)", string(data), data)
 					}
 
 					// Fix incorrect CPR responses from VT library for nushell compatibility
@@ -903,17 +1078,9 @@ func (w *Window) handleIOOperations() {
 					}
 
 					// Debug: Log XTWINOPS responses when debug mode is enabled
-					if os.Getenv("TUIOS_DEBUG_INTERNAL") == "1" {
-						if len(data) >= 6 && data[0] == '\x1b' && data[1] == '[' && data[len(data)-1] == 't' {
-							// This looks like an XTWINOPS response
-							debugMsg := fmt.Sprintf("[%s] XTWINOPS response to PTY: %q (hex: % x)\n",
-								time.Now().Format("15:04:05.000"), string(data), data)
-							// Append to debug log file
-							if f, err := os.OpenFile("/tmp/tuios-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
-								_, _ = f.WriteString(debugMsg)
-								_ = f.Close()
-							}
-						}
+					if len(data) >= 6 && data[0] == '\x1b' && data[1] == '[' && data[len(data)-1] == 't' && debuglog.Enabled() {
+						// This looks like an XTWINOPS response
+						debuglog.Logf(debuglog.Debug, "PTY", "XTWINOPS response to PTY: %q (hex: % x)", string(data), data)
 					}
 
 					// Write to PTY
@@ -937,34 +1104,46 @@ func (w *Window) Resize(width, height int) {
 		return
 	}
 
-	termWidth := max(width-2, 1)
-	termHeight := max(height-2, 1)
+	w.Width = width
+	w.Height = height
+
+	// A split window shares its content area between its panes,
+	// and a tabbed window gives up a row to its tab strip
+	switch {
+	case w.Panes != nil:
+		w.layoutPanes()
+	case w.HasTabs():
+		w.layoutTabs()
+	default:
+		w.resizeTerminal(w.contentSize(width, height))
+	}
+
+	// Mark both position and content dirty for resize operations
+	w.MarkPositionDirty()
+	w.MarkContentDirty()
+}
 
+// resizeTerminal resizes the terminal emulator and PTY to cols x rows cells.
+func (w *Window) resizeTerminal(cols, rows int) {
 	// Check if size actually changed
-	sizeChanged := w.Width != width || w.Height != height
+	sizeChanged := w.Terminal.Width() != cols || w.Terminal.Height() != rows
 
-	w.Terminal.Resize(termWidth, termHeight)
+	w.Terminal.Resize(cols, rows)
 	if w.Pty != nil {
-		if err := w.Pty.Resize(termWidth, termHeight); err != nil {
+		if err := w.Pty.Resize(cols, rows); err != nil {
 			_ = err
 		}
 		if w.CellPixelWidth > 0 && w.CellPixelHeight > 0 {
-			xpixel := termWidth * w.CellPixelWidth
-			ypixel := termHeight * w.CellPixelHeight
-			_ = w.SetPtyPixelSize(termWidth, termHeight, xpixel, ypixel)
+			xpixel := cols * w.CellPixelWidth
+			ypixel := rows * w.CellPixelHeight
+			_ = w.SetPtyPixelSize(cols, rows, xpixel, ypixel)
 		}
 	} else if w.DaemonMode && w.DaemonResizeFunc != nil {
 		// In daemon mode, use the resize callback to notify the daemon
-		if err := w.DaemonResizeFunc(termWidth, termHeight); err != nil {
+		if err := w.DaemonResizeFunc(cols, rows); err != nil {
 			_ = err // Acknowledge error but don't break functionality
 		}
 	}
-	w.Width = width
-	w.Height = height
-
-	// Mark both position and content dirty for resize operations
-	w.MarkPositionDirty()
-	w.MarkContentDirty()
 
 	// Trigger redraw if size changed to force applications to adapt
 	if sizeChanged && w.Pty != nil {
@@ -983,10 +1162,9 @@ func (w *Window) ResizeVisual(width, height int) {
 	// Critical: Update terminal emulator dimensions so rendering uses correct bounds.
 	// This prevents the "stuck" height and dimension mismatch issues during drag.
 	// PTY resize is still deferred until mouse release (via pending resizes).
-	if w.Terminal != nil {
-		termWidth := max(width-2, 1)
-		termHeight := max(height-2, 1)
-		w.Terminal.Resize(termWidth, termHeight)
+	// Panes and tabs are laid out once the final size is applied.
+	if w.Terminal != nil && w.Panes == nil && !w.HasTabs() {
+		w.Terminal.Resize(w.contentSize(width, height))
 	}
 
 	w.MarkPositionDirty()
@@ -1004,8 +1182,7 @@ func (w *Window) SetCellPixelDimensions(cellWidth, cellHeight int) {
 	w.Terminal.SetCellSize(cellWidth, cellHeight)
 
 	if w.Pty != nil && cellWidth > 0 && cellHeight > 0 {
-		termWidth := max(w.Width-2, 1)
-		termHeight := max(w.Height-2, 1)
+		termWidth, termHeight := w.contentSize(w.Width, w.Height)
 		xpixel := termWidth * cellWidth
 		ypixel := termHeight * cellHeight
 		_ = w.SetPtyPixelSize(termWidth, termHeight, xpixel, ypixel)
@@ -1015,15 +1192,58 @@ func (w *Window) SetCellPixelDimensions(cellWidth, cellHeight int) {
 // waitForCmd waits for the command to exit, ensuring Wait() is only called once.
 // This prevents race conditions when both the process monitor goroutine and Close()
 // try to wait for the process.
-func (w *Window) waitForCmd() {
-	if w == nil || w.Cmd == nil {
+func (w *Window) waitForCmd(cmd *exec.Cmd) {
+	if w == nil || cmd == nil {
 		return
 	}
 	w.cmdWaitOnce.Do(func() {
-		_ = w.Cmd.Wait() // Best effort, ignore error
+		_ = cmd.Wait() // Best effort, ignore error
 	})
 }
 
+// shutdowns tracks the processes still being stopped by stopProcess.
+var shutdowns sync.WaitGroup
+
+// WaitForShutdowns waits until the processes of closed windows have exited
+// or been killed.
+func WaitForShutdowns() {
+	shutdowns.Wait()
+}
+
+// stopProcess asks the window's shell and its foreground job to exit, as a
+// terminal hanging up would, and kills them if they are still running after
+// config.ShutdownGracePeriod. It returns immediately; the shutdown finishes
+// in the background and is reaped by the process monitor.
+func (w *Window) stopProcess() {
+	if w.Cmd == nil || w.Cmd.Process == nil {
+		return
+	}
+	if w.processDone == nil {
+		_ = w.Cmd.Process.Kill()
+		return
+	}
+	select {
+	case <-w.processDone:
+		return
+	default:
+	}
+
+	// The foreground job can only be looked up while the PTY is open
+	foreground := 0
+	w.ioMu.RLock()
+	if w.Pty != nil {
+		foreground = system.ForegroundProcessGroup(w.Pty.Fd())
+	}
+	w.ioMu.RUnlock()
+
+	pid, done := w.Cmd.Process.Pid, w.processDone
+	shutdowns.Add(1)
+	go func() {
+		defer shutdowns.Done()
+		system.StopProcess(pid, foreground, done, config.ShutdownGracePeriod)
+	}()
+}
+
 // Close closes the window and cleans up resources.
 func (w *Window) Close() {
 	// Nil safety check
@@ -1031,6 +1251,26 @@ func (w *Window) Close() {
 		return
 	}
 
+	// Close the other panes of a split window
+	if w.Panes != nil {
+		for _, pane := range w.Panes.Panes {
+			if pane.Window != w {
+				pane.Window.Close()
+			}
+		}
+		w.Panes = nil
+	}
+
+	// Close the other tabs of a tabbed window
+	for _, tab := range w.Tabs {
+		if tab != w {
+			tab.Close()
+		}
+	}
+	w.Tabs = nil
+
+	w.StopReplay()
+
 	// Disable terminal features before closing
 	w.disableTerminalFeatures()
 
@@ -1039,11 +1279,14 @@ func (w *Window) Close() {
 		close(w.outputDone)
 		w.outputDone = nil
 	}
-	// Close output channel to unblock any pending writes
-	if w.outputChan != nil {
-		close(w.outputChan)
-		w.outputChan = nil
-	}
+	// Drop output queued for the writer and any arriving later
+	w.outputMu.Lock()
+	w.outputClosed = true
+	w.outputQueue = nil
+	w.outputMu.Unlock()
+
+	// Signal the process while its foreground job can still be found
+	w.stopProcess()
 
 	// Cancel all goroutines first
 	if w.cancelFunc != nil {
@@ -1077,16 +1320,12 @@ func (w *Window) Close() {
 	case <-time.After(10 * time.Millisecond):
 	}
 
-	// Kill the process
-	if w.Cmd != nil && w.Cmd.Process != nil {
-		_ = w.Cmd.Process.Kill()
-		w.waitForCmd()
-		w.Cmd = nil
-	}
+	w.Cmd = nil
 
 	// Clear caches to free memory
 	w.CachedContent = ""
 	w.CachedLayer = nil
+	w.LineCache = nil
 	w.SelectedText = ""
 
 	// Clear copy mode to free memory
@@ -1097,6 +1336,40 @@ func (w *Window) Close() {
 	}
 }
 
+// WorkingDirectory returns the current working directory of the window's
+// shell. The directory reported via OSC 7 is preferred; otherwise it is read
+// from the shell process where the platform allows. Returns an empty string
+// if it cannot be determined.
+func (w *Window) WorkingDirectory() string {
+	if cwd := w.ReportedWorkingDirectory(); cwd != "" {
+		return cwd
+	}
+	return w.ShellWorkingDirectory()
+}
+
+// ReportedWorkingDirectory returns the working directory the shell reported
+// via OSC 7, or an empty string if it reported none.
+func (w *Window) ReportedWorkingDirectory() string {
+	if w.Terminal == nil {
+		return ""
+	}
+	cwd := w.Terminal.WorkingDirectory()
+	if u, err := url.Parse(cwd); err == nil && u.Scheme == "file" {
+		return u.Path
+	}
+	return cwd
+}
+
+// ShellWorkingDirectory reads the working directory of the window's shell
+// process where the platform allows. Returns an empty string for windows
+// without a local process.
+func (w *Window) ShellWorkingDirectory() string {
+	if w.Cmd != nil && w.Cmd.Process != nil {
+		return processWorkingDirectory(w.Cmd.Process.Pid)
+	}
+	return ""
+}
+
 // SendInput sends input to the window's terminal with enhanced error handling.
 func (w *Window) SendInput(input []byte) error {
 	if w == nil {
@@ -1163,6 +1436,7 @@ func (w *Window) MarkContentDirty() {
 	// Content changes invalidate both cached content and layer
 	w.CachedContent = ""
 	w.CachedLayer = nil
+	w.forEachChildPane((*Window).MarkContentDirty)
 }
 
 // ClearDirtyFlags clears all dirty flags.
@@ -1176,6 +1450,18 @@ func (w *Window) ClearDirtyFlags() {
 func (w *Window) InvalidateCache() {
 	w.CachedLayer = nil
 	w.CachedContent = ""
+	w.LineCache = nil
+	w.forEachChildPane((*Window).InvalidateCache)
+}
+
+// WantsMouse reports whether mouse events over the window go to the embedded
+// application: it is using the alternate screen or requested mouse reporting,
+// and mouse capture was not turned on for the window.
+func (w *Window) WantsMouse() bool {
+	if w.MouseCapture || w.Terminal == nil {
+		return false
+	}
+	return w.IsAltScreen || w.Terminal.HasMouseMode()
 }
 
 // ScrollbackLen returns the number of lines in the scrollback buffer.
@@ -1195,6 +1481,18 @@ func (w *Window) ScrollbackLine(index int) []uv.Cell {
 	return w.Terminal.ScrollbackLine(index)
 }
 
+// LastCommandOutput returns the output of the last command run in the
+// window's shell, located with OSC 133 shell integration marks. It reports
+// false if the shell sent no marks.
+func (w *Window) LastCommandOutput() (string, bool) {
+	if w.Terminal == nil {
+		return "", false
+	}
+	w.ioMu.Lock()
+	defer w.ioMu.Unlock()
+	return w.Terminal.LastCommandOutput()
+}
+
 // ClearScrollback clears the scrollback buffer.
 func (w *Window) ClearScrollback() {
 	if w.Terminal != nil {
@@ -1209,6 +1507,64 @@ func (w *Window) SetScrollbackMaxLines(maxLines int) {
 	}
 }
 
+// ScrollbackLimit returns the scrollback line limit in effect for the window
+// and where it comes from: "window" when set for this window, "rule" when a
+// window rule matches its name, otherwise "global".
+func (w *Window) ScrollbackLimit() (int, string) {
+	if w.ScrollbackOverride != nil {
+		return *w.ScrollbackOverride, "window"
+	}
+	if lines, ok := config.RuleScrollbackLines(w.CustomName, w.Title); ok {
+		return lines, "rule"
+	}
+	return config.ScrollbackLines, "global"
+}
+
+// ApplyScrollbackLimit resizes the scrollback buffer to the limit returned by
+// ScrollbackLimit. Lowering the limit discards the oldest lines.
+func (w *Window) ApplyScrollbackLimit() {
+	lines, _ := w.ScrollbackLimit()
+	w.SetScrollbackMaxLines(lines)
+}
+
+// scrollbackShrinkDelay is how long a lower scrollback limit from the window
+// rules has to stay the same before the oldest lines are dropped for it, so a
+// title a command shows for a moment does not cut the scrollback.
+const scrollbackShrinkDelay = 5 * time.Second
+
+// RefreshScrollbackLimit applies the scrollback limit the window's current
+// name gets from the window rules. A higher limit applies at once, a lower
+// one once it has stayed the same for scrollbackShrinkDelay. It must be
+// called from the UI goroutine.
+func (w *Window) RefreshScrollbackLimit(now time.Time) {
+	if w.Terminal == nil {
+		return
+	}
+	lines, _ := w.ScrollbackLimit()
+	current := w.Terminal.Scrollback().MaxLines()
+	switch {
+	case lines >= current:
+		w.scrollbackShrinkAt = time.Time{}
+		w.SetScrollbackMaxLines(lines)
+	case w.scrollbackShrinkAt.IsZero() || lines != w.scrollbackShrinkTo:
+		w.scrollbackShrinkTo, w.scrollbackShrinkAt = lines, now.Add(scrollbackShrinkDelay)
+	case !now.Before(w.scrollbackShrinkAt):
+		w.scrollbackShrinkAt = time.Time{}
+		w.SetScrollbackMaxLines(lines)
+	}
+}
+
+// MinimumSize returns the smallest window size, borders included, that
+// tiling and resizing may give the window: the built-in minimum unless the
+// window rules matching it or MinWidth and MinHeight ask for a larger
+// terminal.
+func (w *Window) MinimumSize() (width, height int) {
+	width, height = config.RuleMinSize(w.CustomName, w.Title, w.App)
+	width = max(max(width, w.MinWidth)+2, config.DefaultWindowWidth)
+	height = max(max(height, w.MinHeight)+2, config.DefaultWindowHeight)
+	return width, height
+}
+
 // EnterScrollbackMode enters scrollback viewing mode.
 func (w *Window) EnterScrollbackMode() {
 	w.ScrollbackMode = true
@@ -1223,6 +1579,35 @@ func (w *Window) ExitScrollbackMode() {
 	w.InvalidateCache()
 }
 
+// TimestampGutterWidth is the width of the time gutter drawn at the left of
+// the content while scrollback timestamps are shown.
+const TimestampGutterWidth = 9
+
+// TimestampGutter returns the width of the time gutter currently drawn at the
+// left of the window content: TimestampGutterWidth while timestamps are
+// shown in scrollback or copy mode, otherwise 0.
+func (w *Window) TimestampGutter() int {
+	if !w.ShowTimestamps || w.Width-2 <= TimestampGutterWidth {
+		return 0
+	}
+	if w.ScrollbackOffset > 0 || (w.CopyMode != nil && w.CopyMode.Active) {
+		return TimestampGutterWidth
+	}
+	return 0
+}
+
+// ViewLineTime returns when row y of the current view, scrolled back by
+// ScrollbackOffset, was first written, or the zero time if it is unknown.
+func (w *Window) ViewLineTime(y int) time.Time {
+	if w.Terminal == nil {
+		return time.Time{}
+	}
+	if y < w.ScrollbackOffset {
+		return w.Terminal.ScrollbackLineTime(w.ScrollbackLen() - w.ScrollbackOffset + y)
+	}
+	return w.Terminal.LineTime(y - w.ScrollbackOffset)
+}
+
 // ScrollUp scrolls up in the scrollback buffer.
 func (w *Window) ScrollUp(lines int) {
 	if !w.ScrollbackMode || w.Terminal == nil {
@@ -0,0 +1,110 @@
+package terminal
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/config"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestShouldSkipFrame(t *testing.T) {
+	w := &Window{}
+
+	if w.ShouldSkipFrame() {
+		t.Fatal("window without output should not skip frames")
+	}
+
+	// A flooding window skips up to the configured number of frames...
+	for i := range config.OutputFloodMaxSkippedFrames {
+		w.outputBytes.Add(config.OutputFloodBytesPerFrame + 1)
+		if !w.ShouldSkipFrame() {
+			t.Fatalf("expected frame %d to be skipped while flooding", i)
+		}
+	}
+
+	// ...and is then rendered even though it is still flooding
+	w.outputBytes.Add(config.OutputFloodBytesPerFrame + 1)
+	if w.ShouldSkipFrame() {
+		t.Error("expected flooding window to render after max skipped frames")
+	}
+
+	// Small updates never skip
+	w.outputBytes.Add(128)
+	if w.ShouldSkipFrame() {
+		t.Error("expected small update to render")
+	}
+}
+
+func TestWriteOutputAsyncNeverBlocks(t *testing.T) {
+	w := &Window{Terminal: vt.NewEmulator(20, 5), outputReady: make(chan struct{}, 1)}
+	// No output writer runs, so every chunk stays queued
+	for range 10000 {
+		w.WriteOutputAsync([]byte("abc"))
+	}
+	if len(w.outputReady) != 1 {
+		t.Errorf("expected one wake-up for the writer, got %d", len(w.outputReady))
+	}
+
+	var got []byte
+	for batch := w.takeOutput(); batch != nil; batch = w.takeOutput() {
+		if len(batch) > config.OutputBatchMaxBytes {
+			t.Fatalf("expected batches of at most %d bytes, got %d", config.OutputBatchMaxBytes, len(batch))
+		}
+		got = append(got, batch...)
+	}
+	if !bytes.Equal(got, bytes.Repeat([]byte("abc"), 10000)) {
+		t.Errorf("expected every chunk coalesced in order, got %d bytes", len(got))
+	}
+
+	w.outputClosed = true
+	w.WriteOutputAsync([]byte("late"))
+	if w.takeOutput() != nil {
+		t.Error("expected output after close to be dropped")
+	}
+}
+
+// TestRefreshScrollbackLimit verifies a window rule raises the scrollback
+// limit at once but only lowers it once the window's name stays matched.
+func TestRefreshScrollbackLimit(t *testing.T) {
+	originalRules, originalLines := config.WindowRules, config.ScrollbackLines
+	defer func() { config.WindowRules, config.ScrollbackLines = originalRules, originalLines }()
+	small, large := 100, 20000
+	config.ScrollbackLines = 1000
+	config.WindowRules = []config.WindowRule{
+		{Match: "htop*", ScrollbackLines: &small},
+		{Match: "logs*", ScrollbackLines: &large},
+	}
+
+	w := &Window{Title: "zsh", Terminal: vt.NewEmulator(20, 5)}
+	w.ApplyScrollbackLimit()
+	maxLines := func() int { return w.Terminal.Scrollback().MaxLines() }
+	now := time.Now()
+
+	w.Title = "logs"
+	w.RefreshScrollbackLimit(now)
+	if maxLines() != large {
+		t.Fatalf("limit after a title with a higher limit = %d, want %d", maxLines(), large)
+	}
+
+	// A title shown for a moment keeps the scrollback
+	w.Title = "htop"
+	w.RefreshScrollbackLimit(now)
+	w.Title = "logs"
+	w.RefreshScrollbackLimit(now.Add(scrollbackShrinkDelay))
+	if maxLines() != large {
+		t.Fatalf("limit after a passing title = %d, want %d", maxLines(), large)
+	}
+
+	w.Title = "htop"
+	w.RefreshScrollbackLimit(now)
+	w.RefreshScrollbackLimit(now.Add(time.Second))
+	if maxLines() != large {
+		t.Fatalf("limit before the delay = %d, want %d", maxLines(), large)
+	}
+	w.RefreshScrollbackLimit(now.Add(scrollbackShrinkDelay))
+	if maxLines() != small {
+		t.Fatalf("limit after the delay = %d, want %d", maxLines(), small)
+	}
+}
@@ -3,10 +3,14 @@
 package terminal
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"syscall"
 	"unsafe"
 
+	"github.com/charmbracelet/x/termios"
+	"github.com/shirou/gopsutil/v4/process"
 	"golang.org/x/sys/unix"
 )
 
@@ -31,6 +35,24 @@ func (w *Window) TriggerRedraw() {
 	}
 }
 
+// processWorkingDirectory returns the current working directory of a process,
+// or an empty string if it cannot be determined. It reads /proc where there
+// is one, and asks the kernel through gopsutil on macOS and the BSDs.
+func processWorkingDirectory(pid int) string {
+	if dir, err := os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid)); err == nil {
+		return dir
+	}
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return ""
+	}
+	dir, err := proc.Cwd()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
 // getPgid gets the process group ID of a given process ID.
 // Returns the PGID or an error if unable to determine it.
 func getPgid(pid int) (int, error) {
@@ -41,8 +63,54 @@ func getPgid(pid int) (int, error) {
 // Returns true if there's a foreground process different from the shell itself.
 // Returns false if only the shell is running or if unable to determine.
 func (w *Window) HasForegroundProcess() bool {
+	_, ok := w.foregroundPgrp()
+	return ok
+}
+
+// ForegroundProcessName returns the command name of the process running in
+// the foreground instead of the shell, or an empty string when only the
+// shell runs or the name cannot be determined (it relies on /proc).
+func (w *Window) ForegroundProcessName() string {
+	pgrp, ok := w.foregroundPgrp()
+	if !ok {
+		return ""
+	}
+	// The process group leader's ID is the group ID
+	comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pgrp))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(comm))
+}
+
+// ForegroundCommandLine returns the arguments of the process running in the
+// foreground instead of the shell, or nil when only the shell runs or they
+// cannot be determined. It reads /proc where there is one, and asks the
+// kernel through gopsutil on macOS and the BSDs.
+func (w *Window) ForegroundCommandLine() []string {
+	pgrp, ok := w.foregroundPgrp()
+	if !ok {
+		return nil
+	}
+	if cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pgrp)); err == nil && len(cmdline) > 0 {
+		return strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+	}
+	proc, err := process.NewProcess(int32(pgrp))
+	if err != nil {
+		return nil
+	}
+	args, err := proc.CmdlineSlice()
+	if err != nil || len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+// foregroundPgrp returns the foreground process group of the window's PTY
+// and whether it differs from the shell's process group.
+func (w *Window) foregroundPgrp() (int, bool) {
 	if w.Pty == nil || w.ShellPgid <= 0 {
-		return false
+		return 0, false
 	}
 
 	// Get the PTY file descriptor
@@ -60,12 +128,12 @@ func (w *Window) HasForegroundProcess() bool {
 
 	if errno != 0 {
 		// If we can't determine, assume no foreground process
-		return false
+		return 0, false
 	}
 
 	// If foreground process group is different from shell's process group,
 	// there's an active foreground process running
-	return fgpgrp != w.ShellPgid
+	return fgpgrp, fgpgrp != w.ShellPgid
 }
 
 // SetPtyPixelSize sets the pixel dimensions on the PTY using TIOCSWINSZ.
@@ -95,3 +163,16 @@ func (w *Window) SetPtyPixelSize(cols, rows, xpixel, ypixel int) error {
 	}
 	return nil
 }
+
+// echoDisabled reports whether the PTY has echo off while line editing is
+// on, as password prompts set it. Full screen programs turn both off.
+func (w *Window) echoDisabled() bool {
+	if w.Pty == nil {
+		return false
+	}
+	t, err := termios.GetTermios(int(w.Pty.Fd()))
+	if err != nil {
+		return false
+	}
+	return t.Lflag&unix.ECHO == 0 && t.Lflag&unix.ICANON != 0
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"unsafe"
 
+	"github.com/charmbracelet/x/termios"
 	"golang.org/x/sys/unix"
 )
 
@@ -102,3 +103,48 @@ func TestSetCellPixelDimensions(t *testing.T) {
 		t.Errorf("Expected Ypixel=%d, got %d", expectedYpixel, ws.Ypixel)
 	}
 }
+
+func TestSecureInputFollowsEcho(t *testing.T) {
+	exitChan := make(chan string, 1)
+	window := NewWindow("test-id-secure00", "Test", 0, 0, 80, 24, 0, exitChan)
+	if window == nil || window.Pty == nil {
+		t.Skip("No PTY available")
+	}
+	defer window.Close()
+
+	fd := int(window.Pty.Fd())
+	attrs, err := termios.GetTermios(fd)
+	if err != nil {
+		t.Skipf("Cannot read PTY attributes: %v", err)
+	}
+	if window.echoDisabled() {
+		t.Fatal("expected echo on in a new window")
+	}
+
+	setLine := func(echo, canonical bool) {
+		t.Helper()
+		if err := termios.SetTermios(fd, uint32(attrs.Ispeed), uint32(attrs.Ospeed), nil, nil, nil, nil,
+			map[termios.L]bool{termios.ECHO: echo, termios.ICANON: canonical}); err != nil {
+			t.Skipf("Cannot set PTY attributes: %v", err)
+		}
+	}
+
+	setLine(false, true)
+	if !window.echoDisabled() {
+		t.Error("expected a password prompt to turn secure input on")
+	}
+	// Like read -s, nothing is printed; sampling finds it all the same
+	if !window.SampleSecureInput() || !window.SecureInput() {
+		t.Error("expected echo turned off without output to be sampled")
+	}
+	if window.SampleSecureInput() {
+		t.Error("expected no change reported while echo stays off")
+	}
+	setLine(false, false)
+	if window.echoDisabled() {
+		t.Error("expected a full screen program not to count as secure input")
+	}
+	if !window.SampleSecureInput() || window.SecureInput() {
+		t.Error("expected secure input sampled off for a full screen program")
+	}
+}
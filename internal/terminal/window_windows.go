@@ -10,6 +10,11 @@ func (w *Window) TriggerRedraw() {
 	// The Pty.Resize() call in the Resize() method is sufficient
 }
 
+// processWorkingDirectory is a stub for Windows - not supported on this platform.
+func processWorkingDirectory(_ int) string {
+	return ""
+}
+
 // getPgid is a stub for Windows - not supported on this platform.
 // Returns an error indicating the operation is not supported.
 func getPgid(_ int) (int, error) {
@@ -27,7 +32,23 @@ func (w *Window) HasForegroundProcess() bool {
 	return false
 }
 
+// ForegroundProcessName is a stub for Windows - always returns an empty string.
+func (w *Window) ForegroundProcessName() string {
+	return ""
+}
+
+// ForegroundCommandLine is a stub for Windows - always returns nil.
+func (w *Window) ForegroundCommandLine() []string {
+	return nil
+}
+
 // SetPtyPixelSize is a stub for Windows - ConPTY doesn't support pixel dimensions.
 func (w *Window) SetPtyPixelSize(cols, rows, xpixel, ypixel int) error {
 	return nil
 }
+
+// echoDisabled is a stub for Windows - ConPTY does not report echo, so it
+// always returns false.
+func (w *Window) echoDisabled() bool {
+	return false
+}
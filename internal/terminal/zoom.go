@@ -0,0 +1,29 @@
+package terminal
+
+// SetZoomed turns on or off drawing the window's content at double cell
+// size. A zoomed window's terminal gets half its content area in each
+// direction, so applications reflow to fit, and each cell is drawn over 2x2
+// cells. Windows with panes or tabs cannot be zoomed; SetZoomed reports
+// whether the window is zoomed as asked.
+func (w *Window) SetZoomed(zoomed bool) bool {
+	if zoomed && (w.HasPanes() || w.HasTabs()) {
+		return false
+	}
+	if w.Zoomed == zoomed {
+		return true
+	}
+	w.Zoomed = zoomed
+	w.Resize(w.Width, w.Height)
+	w.InvalidateCache()
+	return true
+}
+
+// contentSize returns the size of the terminal of a window width x height
+// cells large, borders included: half the content area when zoomed.
+func (w *Window) contentSize(width, height int) (cols, rows int) {
+	cols, rows = max(width-2, 1), max(height-2, 1)
+	if w.Zoomed {
+		cols, rows = max(cols/2, 1), max(rows/2, 1)
+	}
+	return cols, rows
+}
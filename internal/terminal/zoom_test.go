@@ -0,0 +1,27 @@
+package terminal
+
+import (
+	"testing"
+
+	"github.com/Gaurav-Gosain/tuios/internal/layout"
+	"github.com/Gaurav-Gosain/tuios/internal/vt"
+)
+
+func TestZoomedWindow(t *testing.T) {
+	w := &Window{Width: 40, Height: 20, Terminal: vt.NewEmulator(38, 18)}
+	if !w.SetZoomed(true) {
+		t.Fatal("plain window could not be zoomed")
+	}
+	if w.Terminal.Width() != 19 || w.Terminal.Height() != 9 {
+		t.Errorf("zoomed terminal is %dx%d, want 19x9", w.Terminal.Width(), w.Terminal.Height())
+	}
+	if pane, x, y := w.PaneAt(7, 5); pane != w || x != 3 || y != 2 {
+		t.Errorf("PaneAt(7, 5) = %v, %d, %d, want the window at 3, 2", pane, x, y)
+	}
+
+	// Splitting draws the window at normal size again
+	w.SplitPane(&Window{Terminal: vt.NewEmulator(10, 10)}, layout.SplitVertical)
+	if w.Zoomed || w.SetZoomed(true) {
+		t.Error("window with panes is zoomed")
+	}
+}
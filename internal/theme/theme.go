@@ -4,6 +4,8 @@ package theme
 import (
 	"fmt"
 	"image/color"
+	"slices"
+	"sync"
 
 	"charm.land/lipgloss/v2"
 	tint "github.com/lrstanley/bubbletint/v2"
@@ -11,6 +13,32 @@ import (
 
 var enabled bool
 
+// hostColors are the host terminal's default colors, nil while unknown.
+var hostColors struct {
+	sync.RWMutex
+	fg, bg color.Color
+}
+
+// SetHostColors records the host terminal's default foreground and background
+// colors, as reported by the terminal. A nil color leaves that color as it is.
+func SetHostColors(fg, bg color.Color) {
+	hostColors.Lock()
+	defer hostColors.Unlock()
+	if fg != nil {
+		hostColors.fg = fg
+	}
+	if bg != nil {
+		hostColors.bg = bg
+	}
+}
+
+// HostColors returns the host terminal's default colors, nil while unknown.
+func HostColors() (fg, bg color.Color) {
+	hostColors.RLock()
+	defer hostColors.RUnlock()
+	return hostColors.fg, hostColors.bg
+}
+
 // Initialize sets up the theme registry with the specified theme name.
 // Call this once at application startup.
 // If themeName is empty, theming will be disabled and standard terminal colors will be used.
@@ -34,6 +62,12 @@ func Initialize(themeName string) error {
 	return nil
 }
 
+// Exists reports whether themeName is a known theme ID.
+func Exists(themeName string) bool {
+	tint.NewDefaultRegistry()
+	return slices.Contains(tint.TintIDs(), themeName)
+}
+
 // IsEnabled returns true if theming is enabled
 func IsEnabled() bool {
 	return enabled
@@ -139,6 +173,16 @@ func BorderFocusedTerminal() color.Color {
 	return t.BrightGreen
 }
 
+// BorderRemoteFocus returns the border color for windows focused by another
+// client attached to the same session.
+func BorderRemoteFocus() color.Color {
+	t := Current()
+	if t == nil {
+		return lipgloss.Color("#FFAFFF")
+	}
+	return t.BrightPurple
+}
+
 // DockColorWindow returns the dock indicator color for window management mode.
 func DockColorWindow() color.Color {
 	t := Current()
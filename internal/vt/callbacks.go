@@ -60,4 +60,14 @@ type Callbacks struct {
 	// DisableMode callback. When set, this function is called when a mode is
 	// disabled.
 	DisableMode func(mode ansi.Mode)
+
+	// CommandFinished callback. When set, this function is called when the
+	// shell reports with OSC 133 marks that a command line finished, with its
+	// exit code (-1 when the shell did not report one).
+	CommandFinished func(command string, exitCode int)
+
+	// ClipboardWrite callback. When set, this function is called when an
+	// application asks to set a clipboard with OSC 52, with the selections it
+	// names (such as "c" or "p") and the decoded content.
+	ClipboardWrite func(selection string, content []byte)
 }
@@ -2,10 +2,15 @@ package vt
 
 import (
 	"io"
+	"time"
 
 	"github.com/charmbracelet/x/ansi"
 )
 
+// syncOutputTimeout is the longest a synchronized update may hold back the
+// screen before it is shown regardless.
+const syncOutputTimeout = time.Second
+
 func (e *Emulator) handleMode(params ansi.Params, set, isAnsi bool) {
 	for _, p := range params {
 		param := p.Param(-1)
@@ -49,6 +54,7 @@ func (e *Emulator) setAltScreenMode(on bool) {
 	} else {
 		e.scr = &e.scrs[0]
 	}
+	e.syncKittyKeyboardFlags()
 	if e.cb.AltScreen != nil {
 		e.cb.AltScreen(on)
 	}
@@ -90,6 +96,12 @@ func (e *Emulator) setMode(mode ansi.Mode, setting ansi.ModeSetting) {
 			e.saveCursor()
 		}
 		e.setAltScreenMode(setting.IsSet())
+	case ansi.ModeSynchronizedOutput:
+		if setting.IsSet() {
+			e.syncStart.Store(time.Now().UnixNano())
+		} else {
+			e.syncStart.Store(0)
+		}
 	case ansi.ModeInBandResize:
 		if setting.IsSet() {
 			_, _ = io.WriteString(e.pw, ansi.InBandResize(e.Height(), e.Width(), 0, 0))
@@ -118,6 +130,20 @@ func (e *Emulator) ApplicationCursorKeys() bool {
 	return e.isModeSet(ansi.ModeCursorKeys)
 }
 
+// SynchronizedOutput returns true while the application is in the middle of a
+// synchronized update (?2026), so its screen should not be shown yet. An
+// update that is not finished within syncOutputTimeout is shown anyway, so a
+// crashed application cannot freeze its window.
+func (e *Emulator) SynchronizedOutput() bool {
+	start := e.syncStart.Load()
+	return start != 0 && time.Since(time.Unix(0, start)) < syncOutputTimeout
+}
+
+// FocusEventsEnabled returns true if focus reporting (?1004) is enabled.
+func (e *Emulator) FocusEventsEnabled() bool {
+	return e.isModeSet(ansi.ModeFocusEvent)
+}
+
 // BracketedPasteEnabled returns true if bracketed paste mode (?2004) is enabled.
 // When enabled, pasted text should be wrapped with escape sequences.
 func (e *Emulator) BracketedPasteEnabled() bool {
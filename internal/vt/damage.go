@@ -69,3 +69,44 @@ type ScrollDamage struct {
 	uv.Rectangle
 	Dx, Dy int
 }
+
+// HasDamage reports whether the active screen changed since the last call to
+// [Emulator.TakeDamage].
+func (e *Emulator) HasDamage() bool {
+	if e.scr != e.damageScr || e.Width() != e.damageW || e.Height() != e.damageH {
+		return true
+	}
+	for _, ld := range e.scr.Touched() {
+		if ld != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// TakeDamage returns the areas of the active screen that changed since the
+// previous call and resets the touched state of the screen buffer.
+// Switching between the main and alternate screen or resizing the terminal
+// is reported as a single [ScreenDamage].
+func (e *Emulator) TakeDamage() []Damage {
+	width, height := e.Width(), e.Height()
+	full := e.scr != e.damageScr || width != e.damageW || height != e.damageH
+	e.damageScr, e.damageW, e.damageH = e.scr, width, height
+
+	var damage []Damage
+	if full {
+		damage = append(damage, ScreenDamage{Width: width, Height: height})
+	}
+
+	touched := e.scr.Touched()
+	for y, ld := range touched {
+		if ld == nil {
+			continue
+		}
+		if !full {
+			damage = append(damage, CellDamage{X: ld.FirstCell, Y: y, Width: ld.LastCell - ld.FirstCell})
+		}
+		touched[y] = nil
+	}
+	return damage
+}
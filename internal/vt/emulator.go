@@ -4,11 +4,13 @@ import (
 	"image/color"
 	"io"
 	"sync/atomic"
+	"time"
 
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/charmbracelet/ultraviolet/screen"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/ansi/parser"
+	"github.com/clipperhouse/displaywidth"
 )
 
 // Logger represents a logger interface.
@@ -20,8 +22,11 @@ type Logger interface {
 type Emulator struct {
 	handlers
 
-	// The terminal's indexed 256 colors.
-	colors [256]color.Color
+	// The terminal's indexed 256 colors, always set: the standard palette
+	// with the theme's first 16 colors and those the application set with
+	// OSC 4. customPalette is true when they differ from standardPalette.
+	colors        [256]color.Color
+	customPalette bool
 
 	// Both main and alt screens and a pointer to the currently active screen.
 	scrs [2]Screen
@@ -36,6 +41,11 @@ type Emulator struct {
 	// terminal default colors.
 	defaultFg, defaultBg, defaultCur color.Color
 	fgColor, bgColor, curColor       color.Color
+	// host terminal colors, reported to applications (see SetHostColors).
+	hostFg, hostBg color.Color
+	// themePalette is the theme's first 16 colors, which OSC 104 resets them
+	// to. Unset entries reset to the standard palette.
+	themePalette [16]color.Color
 
 	// Terminal modes.
 	modes ansi.Modes
@@ -44,6 +54,9 @@ type Emulator struct {
 	lastChar rune // either ansi.Rune or ansi.Grapheme
 	// A slice of runes to compose a grapheme.
 	grapheme []rune
+	// Grapheme width options (see SetWidthOptions)
+	widthOpts   displaywidth.Options
+	narrowEmoji bool
 
 	// The ANSI parser to use.
 	parser *ansi.Parser
@@ -56,6 +69,8 @@ type Emulator struct {
 	iconName, title string
 	// The current reported working directory. This is not validated.
 	cwd string
+	// Command output located with OSC 133 shell integration marks.
+	marks commandMarks
 
 	// tabstop is the list of tab stops.
 	tabstops *uv.TabStops
@@ -92,6 +107,18 @@ type Emulator struct {
 
 	// Sixel graphics passthrough callback
 	sixelPassthroughFunc func(cmd *SixelCommand, cursorX, cursorY, absLine int)
+
+	// Kitty keyboard protocol flag stacks for main and alt screens, and the
+	// active screen's flags for readers outside the parser goroutine
+	kittyKeys     [2][]int
+	kittyKeyFlags atomic.Int32
+
+	// Start of the current synchronized update in Unix nanoseconds (0 = none)
+	syncStart atomic.Int64
+
+	// Screen and size damage was last collected for (see TakeDamage)
+	damageScr        *Screen
+	damageW, damageH int
 }
 
 // NewEmulator creates a new virtual terminal emulator.
@@ -133,6 +160,7 @@ func NewEmulator(w, h int) *Emulator {
 	t.defaultFg = color.White
 	t.defaultBg = color.Black
 	t.defaultCur = color.White
+	t.colors = standardPalette
 
 	t.kittyMain = NewKittyState()
 	t.kittyAlt = NewKittyState()
@@ -192,6 +220,12 @@ func (e *Emulator) SetCell(x, y int, c *uv.Cell) {
 	e.scr.SetCell(x, y, c)
 }
 
+// WorkingDirectory returns the working directory last reported by the
+// application via OSC 7, usually a file:// URL. It is empty if none was reported.
+func (e *Emulator) WorkingDirectory() string {
+	return e.cwd
+}
+
 // Scrollback returns the scrollback buffer of the main screen.
 // Note: The alternate screen does not maintain scrollback.
 func (e *Emulator) Scrollback() *Scrollback {
@@ -208,12 +242,43 @@ func (e *Emulator) ScrollbackLen() int {
 	return e.scrs[0].ScrollbackLen()
 }
 
+// ScrollbackPushed returns the number of lines ever pushed into the main
+// screen's scrollback. Images are anchored to lines counted this way so they
+// keep their position once the scrollback is full and old lines are dropped.
+func (e *Emulator) ScrollbackPushed() int {
+	return e.scrs[0].ScrollbackPushed()
+}
+
 // ScrollbackLine returns a line from the scrollback buffer at the given index.
 // Index 0 is the oldest line. Returns nil if index is out of bounds.
 func (e *Emulator) ScrollbackLine(index int) []uv.Cell {
 	return e.scrs[0].ScrollbackLine(index)
 }
 
+// ScrollbackLineTime returns when the scrollback line at the given index was
+// first written to the screen, or the zero time if it is unknown.
+func (e *Emulator) ScrollbackLineTime(index int) time.Time {
+	return e.scrs[0].ScrollbackLineTime(index)
+}
+
+// LineTime returns when row y of the active screen was first written since
+// it was last cleared, or the zero time for a blank row.
+func (e *Emulator) LineTime(y int) time.Time {
+	return e.scr.LineTime(y)
+}
+
+// ScrollbackMemory returns the estimated number of bytes held by the
+// scrollback buffer of the main screen.
+func (e *Emulator) ScrollbackMemory() int {
+	return e.scrs[0].ScrollbackMemory()
+}
+
+// TrimScrollback discards up to n of the oldest scrollback lines and returns
+// the number of lines removed.
+func (e *Emulator) TrimScrollback(n int) int {
+	return e.scrs[0].TrimScrollback(n)
+}
+
 // SetScrollbackMaxLines sets the maximum number of lines for the scrollback buffer.
 func (e *Emulator) SetScrollbackMaxLines(maxLines int) {
 	e.scrs[0].SetScrollbackMaxLines(maxLines)
@@ -349,6 +414,7 @@ func (e *Emulator) RestoreAltScreenMode(enabled bool) {
 			e.scr = &e.scrs[0]
 		}
 	}
+	e.syncKittyKeyboardFlags()
 	// NOTE: We don't modify e.modes[] here to avoid concurrent map access.
 	// The modes will be updated naturally when PTY output is processed.
 }
@@ -615,14 +681,12 @@ func (e *Emulator) ForegroundColor() color.Color {
 	return e.fgColor
 }
 
-// SetForegroundColor sets the terminal's foreground color.
+// SetForegroundColor sets the terminal's foreground color. A nil color resets it
+// to the default.
 func (e *Emulator) SetForegroundColor(c color.Color) {
-	if c == nil {
-		c = e.defaultFg
-	}
 	e.fgColor = c
 	if e.cb.ForegroundColor != nil {
-		e.cb.ForegroundColor(c)
+		e.cb.ForegroundColor(e.ForegroundColor())
 	}
 }
 
@@ -644,14 +708,12 @@ func (e *Emulator) BackgroundColor() color.Color {
 	return e.bgColor
 }
 
-// SetBackgroundColor sets the terminal's background color.
+// SetBackgroundColor sets the terminal's background color. A nil color resets it
+// to the default.
 func (e *Emulator) SetBackgroundColor(c color.Color) {
-	if c == nil {
-		c = e.defaultBg
-	}
 	e.bgColor = c
 	if e.cb.BackgroundColor != nil {
-		e.cb.BackgroundColor(c)
+		e.cb.BackgroundColor(e.BackgroundColor())
 	}
 }
 
@@ -672,14 +734,12 @@ func (e *Emulator) CursorColor() color.Color {
 	return e.curColor
 }
 
-// SetCursorColor sets the terminal's cursor color.
+// SetCursorColor sets the terminal's cursor color. A nil color resets it
+// to the default.
 func (e *Emulator) SetCursorColor(c color.Color) {
-	if c == nil {
-		c = e.defaultCur
-	}
 	e.curColor = c
 	if e.cb.CursorColor != nil {
-		e.cb.CursorColor(c)
+		e.cb.CursorColor(e.CursorColor())
 	}
 }
 
@@ -691,31 +751,43 @@ func (e *Emulator) SetDefaultCursorColor(c color.Color) {
 	e.defaultCur = c
 }
 
+// standardPalette is the xterm 256-color palette indexed colors start as.
+var standardPalette = func() (palette [256]color.Color) {
+	for i := range palette {
+		palette[i] = ansi.IndexedColor(uint8(i)) // #nosec G115 - i is in [0, 255]
+	}
+	return palette
+}()
+
 // IndexedColor returns a terminal's indexed color. An indexed color is a color
 // between 0 and 255.
 func (e *Emulator) IndexedColor(i int) color.Color {
 	if i < 0 || i > 255 {
 		return nil
 	}
-
-	c := e.colors[i]
-	if c == nil {
-		// Return the default color. Safe conversion: i is already validated to be in [0, 255]
-		// #nosec G115 - false positive, i is validated to be in valid uint8 range above
-		return ansi.IndexedColor(uint8(i))
-	}
-
-	return c
+	return e.colors[i]
 }
 
 // SetIndexedColor sets a terminal's indexed color.
-// The index must be between 0 and 255.
+// The index must be between 0 and 255. A nil color restores the default.
 func (e *Emulator) SetIndexedColor(i int, c color.Color) {
 	if i < 0 || i > 255 {
 		return
 	}
-
+	if c == nil {
+		c = e.defaultIndexedColor(i)
+	}
 	e.colors[i] = c
+	e.customPalette = e.colors != standardPalette
+}
+
+// defaultIndexedColor returns the color index i has before an application
+// changes it: the theme's for the first 16, else the standard one.
+func (e *Emulator) defaultIndexedColor(i int) color.Color {
+	if i < len(e.themePalette) && e.themePalette[i] != nil {
+		return e.themePalette[i]
+	}
+	return standardPalette[i]
 }
 
 // SetThemeColors sets the terminal's color palette from a theme.
@@ -727,21 +799,51 @@ func (e *Emulator) SetThemeColors(fg, bg, cur color.Color, ansiPalette [16]color
 	e.SetDefaultBackgroundColor(bg)
 	e.SetDefaultCursorColor(cur)
 
-	// Only set indexed colors if we have a theme (fg/bg are not nil)
-	// This prevents overriding standard terminal colors when theming is disabled
+	// Only take the theme's indexed colors if we have a theme (fg/bg are not
+	// nil); without one the first 16 colors are the standard ones
+	e.themePalette = [16]color.Color{}
 	if fg != nil || bg != nil {
-		// Set the first 16 ANSI colors
-		for i := range 16 {
-			e.SetIndexedColor(i, ansiPalette[i])
-		}
+		e.themePalette = ansiPalette
+	}
+	for i := range 16 {
+		e.SetIndexedColor(i, nil)
+	}
+}
+
+// SetHostColors sets the host terminal's foreground and background colors.
+// Windows are drawn over the host's background, so it is what applications
+// asking for the background color (OSC 11) are told, and the host's
+// foreground is reported when no theme sets one. Nil colors are unknown.
+func (e *Emulator) SetHostColors(fg, bg color.Color) {
+	e.hostFg, e.hostBg = fg, bg
+}
+
+// AppColors returns the default foreground and background colors the
+// application set with OSC 10 and OSC 11, or nil for colors it did not set.
+func (e *Emulator) AppColors() (fg, bg color.Color) {
+	return e.fgColor, e.bgColor
+}
+
+// reportedForegroundColor returns the foreground color reported for OSC 10.
+func (e *Emulator) reportedForegroundColor() color.Color {
+	if e.fgColor == nil && e.themePalette[0] == nil && e.hostFg != nil {
+		return e.hostFg
+	}
+	return e.ForegroundColor()
+}
+
+// reportedBackgroundColor returns the background color reported for OSC 11.
+func (e *Emulator) reportedBackgroundColor() color.Color {
+	if e.bgColor == nil && e.hostBg != nil {
+		return e.hostBg
 	}
+	return e.BackgroundColor()
 }
 
-// hasThemeColors returns true if theme colors have been set
+// hasThemeColors returns true if the palette differs from the standard one,
+// from a theme or OSC 4, so indexed colors have to be resolved through it
 func (e *Emulator) hasThemeColors() bool {
-	// Check if any indexed colors have been set
-	// If colors[0] is nil, no theme has been applied
-	return e.colors[0] != nil
+	return e.customPalette
 }
 
 // resetTabStops resets the terminal tab stops to the default set.
@@ -853,7 +955,7 @@ func (e *Emulator) registerSixelGraphicsHandler() {
 		cursorX, cursorY := e.scr.CursorPosition()
 
 		// Calculate absolute line (accounting for scrollback)
-		absLine := e.scrs[0].ScrollbackLen() + cursorY
+		absLine := e.scrs[0].ScrollbackPushed() + cursorY
 		if e.IsAltScreen() {
 			// Alt screen doesn't have scrollback, use viewport position
 			absLine = cursorY
@@ -480,6 +480,61 @@ func TestEmulator_Scrollback(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Damage Tracking Tests
+// =============================================================================
+
+func TestEmulator_TakeDamage(t *testing.T) {
+	emu := vt.NewEmulator(80, 24)
+
+	// The first collection always reports the whole screen
+	damage := emu.TakeDamage()
+	if len(damage) != 1 {
+		t.Fatalf("Expected a single damage entry, got %d", len(damage))
+	}
+	if _, ok := damage[0].(vt.ScreenDamage); !ok {
+		t.Fatalf("Expected ScreenDamage, got %T", damage[0])
+	}
+	if emu.HasDamage() {
+		t.Error("Expected no damage after TakeDamage")
+	}
+
+	b := testutil.NewANSIBuilder()
+	_, _ = emu.Write([]byte(b.CursorTo(6, 1).Text("hello").String()))
+
+	if !emu.HasDamage() {
+		t.Fatal("Expected damage after writing text")
+	}
+	damage = emu.TakeDamage()
+	if len(damage) != 1 {
+		t.Fatalf("Expected damage on one line, got %d entries", len(damage))
+	}
+	if got := damage[0].Bounds().Min.Y; got != 5 {
+		t.Errorf("Expected damage on row 5, got row %d", got)
+	}
+
+	// Switching screens invalidates everything
+	b.Clear()
+	_, _ = emu.Write([]byte(b.AltScreen().String()))
+	damage = emu.TakeDamage()
+	if len(damage) != 1 {
+		t.Fatalf("Expected full damage after screen switch, got %d entries", len(damage))
+	}
+	if _, ok := damage[0].(vt.ScreenDamage); !ok {
+		t.Errorf("Expected ScreenDamage after screen switch, got %T", damage[0])
+	}
+
+	// Resizing invalidates everything
+	emu.Resize(100, 30)
+	damage = emu.TakeDamage()
+	if len(damage) == 0 {
+		t.Fatal("Expected damage after resize")
+	}
+	if _, ok := damage[0].(vt.ScreenDamage); !ok {
+		t.Errorf("Expected ScreenDamage after resize, got %T", damage[0])
+	}
+}
+
 // =============================================================================
 // Integration Test: Shell-like Output
 // =============================================================================
@@ -590,3 +645,21 @@ func BenchmarkEmulator_Render(b *testing.B) {
 		_ = emu.Render()
 	}
 }
+
+func TestEmulator_SynchronizedOutput(t *testing.T) {
+	emu := vt.NewEmulator(80, 24)
+
+	_, _ = emu.Write([]byte("\x1b[?2026h"))
+	if !emu.SynchronizedOutput() {
+		t.Fatal("synchronized update not reported after ?2026h")
+	}
+	_, _ = emu.Write([]byte("\x1b[2J\x1b[Hframe"))
+	if !emu.SynchronizedOutput() {
+		t.Error("synchronized update ended before ?2026l")
+	}
+
+	_, _ = emu.Write([]byte("\x1b[?2026l"))
+	if emu.SynchronizedOutput() {
+		t.Error("synchronized update still reported after ?2026l")
+	}
+}
@@ -3,11 +3,11 @@ package vt
 import (
 	"fmt"
 	"io"
-	"os"
-	"time"
 
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/charmbracelet/x/ansi"
+
+	"github.com/Gaurav-Gosain/tuios/internal/debuglog"
 )
 
 // DcsHandler is a function that handles a DCS escape sequence.
@@ -202,6 +202,7 @@ func (h *handlers) handleEsc(cmd int) bool {
 func (e *Emulator) registerDefaultHandlers() {
 	e.registerDefaultCcHandlers()
 	e.registerDefaultCsiHandlers()
+	e.registerKittyKeyboardHandlers()
 	e.registerDefaultEscHandlers()
 	e.registerDefaultOscHandlers()
 }
@@ -309,6 +310,18 @@ func (e *Emulator) registerDefaultOscHandlers() {
 		return true
 	})
 
+	e.RegisterOscHandler(133, func(data []byte) bool {
+		// Shell integration prompt and command marks
+		e.handleSemanticPrompt(data)
+		return true
+	})
+
+	e.RegisterOscHandler(52, func(data []byte) bool {
+		// Set clipboard [ansi.SetClipboard]
+		e.handleClipboard(data)
+		return true
+	})
+
 	e.RegisterOscHandler(8, func(data []byte) bool {
 		// Set/Query Hyperlink [ansi.SetHyperlink]
 		e.handleHyperlink(8, data)
@@ -328,6 +341,16 @@ func (e *Emulator) registerDefaultOscHandlers() {
 			return true
 		})
 	}
+
+	for _, cmd := range []int{
+		4,   // Set/Query indexed colors
+		104, // Reset indexed colors
+	} {
+		e.RegisterOscHandler(cmd, func(data []byte) bool {
+			e.handlePaletteColor(cmd, data)
+			return true
+		})
+	}
 }
 
 // registerDefaultEscHandlers registers the default ESC escape sequence handlers.
@@ -546,7 +569,7 @@ func (e *Emulator) registerDefaultCsiHandlers() {
 			// Don't clear images for ED 1 - commonly used by apps
 		case 2: // erase screen (clear command)
 			e.scr.Clear()
-			e.KittyState().ClearPlacements()
+			e.KittyState().ClearPlacements(e.scrs[0].ScrollbackPushed())
 		case 3: // erase display including scrollback
 			e.scr.ClearScrollback()
 			e.scr.Clear()
@@ -824,12 +847,7 @@ func (e *Emulator) registerDefaultCsiHandlers() {
 
 		// Debug logging
 		debugLog := func(msg string) {
-			if os.Getenv("TUIOS_DEBUG_INTERNAL") == "1" {
-				if f, err := os.OpenFile("/tmp/tuios-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
-					_, _ = fmt.Fprintf(f, "[%s] VT-XTWINOPS: %s\n", time.Now().Format("15:04:05.000"), msg)
-					_ = f.Close()
-				}
-			}
+			debuglog.Logf(debuglog.Debug, "VT-XTWINOPS", "%s", msg)
 			if e.logger != nil {
 				e.logger.Printf("XTWINOPS: %s", msg)
 			}
@@ -3,22 +3,14 @@ package vt
 import (
 	"bytes"
 	"compress/zlib"
-	"fmt"
 	"io"
-	"os"
 	"time"
+
+	"github.com/Gaurav-Gosain/tuios/internal/debuglog"
 )
 
 func kittyDebugLog(format string, args ...any) {
-	if os.Getenv("TUIOS_DEBUG_INTERNAL") != "1" {
-		return
-	}
-	f, err := os.OpenFile("/tmp/tuios-debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return
-	}
-	defer func() { _ = f.Close() }()
-	_, _ = fmt.Fprintf(f, "[%s] KITTY: %s\n", time.Now().Format("15:04:05.000"), fmt.Sprintf(format, args...))
+	debuglog.Logf(debuglog.Debug, "KITTY", format, args...)
 }
 
 // KittyGraphicsHandler handles Kitty graphics protocol commands for a screen.
@@ -315,8 +307,7 @@ func (h *KittyGraphicsHandler) handleDelete(cmd *KittyCommand) bool {
 
 func (h *KittyGraphicsHandler) placeImageAtCursor(img *KittyImage, cmd *KittyCommand) {
 	x, y := h.screen.CursorPosition()
-	scrollbackLen := h.screen.ScrollbackLen()
-	absoluteLine := scrollbackLen + y
+	absoluteLine := h.screen.ScrollbackPushed() + y
 
 	placement := &KittyPlacement{
 		ImageID:      img.ID,
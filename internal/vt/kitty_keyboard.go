@@ -0,0 +1,134 @@
+package vt
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// KittyKeyboardSupported are the Kitty keyboard protocol flags the emulator
+// honors. Other flags requested by applications are ignored, so a query
+// reports what keys will actually look like.
+const KittyKeyboardSupported = ansi.KittyDisambiguateEscapeCodes | ansi.KittyReportAllKeysAsEscapeCodes
+
+// maxKittyKeyboardStack bounds the push stack so a misbehaving application
+// cannot grow it without limit. The oldest entries are dropped first.
+const maxKittyKeyboardStack = 16
+
+// kittyKeyboardStack returns the flags stack of the active screen. The main
+// and alternate screens keep separate stacks, as the protocol requires.
+func (e *Emulator) kittyKeyboardStack() *[]int {
+	if e.scr == &e.scrs[1] {
+		return &e.kittyKeys[1]
+	}
+	return &e.kittyKeys[0]
+}
+
+// currentKittyKeyboardFlags returns the flags on top of the active stack.
+func (e *Emulator) currentKittyKeyboardFlags() int {
+	stack := *e.kittyKeyboardStack()
+	if len(stack) == 0 {
+		return 0
+	}
+	return stack[len(stack)-1]
+}
+
+// syncKittyKeyboardFlags publishes the active flags for KittyKeyboardFlags.
+// It must be called whenever a stack changes or the active screen switches.
+func (e *Emulator) syncKittyKeyboardFlags() {
+	e.kittyKeyFlags.Store(int32(e.currentKittyKeyboardFlags()))
+}
+
+// KittyKeyboardFlags returns the Kitty keyboard protocol flags the running
+// application enabled on the active screen. Zero means legacy key encoding.
+// It is safe to call while output is being processed.
+func (e *Emulator) KittyKeyboardFlags() int {
+	return int(e.kittyKeyFlags.Load())
+}
+
+// SetKittyKeyboardFlags replaces the active screen's flags without an escape
+// sequence. It is used to restore a saved session.
+func (e *Emulator) SetKittyKeyboardFlags(flags int) {
+	stack := e.kittyKeyboardStack()
+	*stack = nil
+	if flags &= KittyKeyboardSupported; flags != 0 {
+		*stack = []int{flags}
+	}
+	e.syncKittyKeyboardFlags()
+}
+
+// pushKittyKeyboard handles CSI > flags u.
+func (e *Emulator) pushKittyKeyboard(flags int) {
+	stack := e.kittyKeyboardStack()
+	if len(*stack) >= maxKittyKeyboardStack {
+		*stack = (*stack)[1:]
+	}
+	*stack = append(*stack, flags&KittyKeyboardSupported)
+	e.syncKittyKeyboardFlags()
+}
+
+// popKittyKeyboard handles CSI < n u. Popping more entries than were pushed
+// empties the stack, which resets to legacy encoding.
+func (e *Emulator) popKittyKeyboard(n int) {
+	stack := e.kittyKeyboardStack()
+	*stack = (*stack)[:max(len(*stack)-n, 0)]
+	e.syncKittyKeyboardFlags()
+}
+
+// setKittyKeyboard handles CSI = flags ; mode u. Mode 1 replaces the current
+// flags, 2 adds the given flags and 3 removes them.
+func (e *Emulator) setKittyKeyboard(flags, mode int) bool {
+	current := e.currentKittyKeyboardFlags()
+	switch mode {
+	case 1:
+		current = flags
+	case 2:
+		current |= flags
+	case 3:
+		current &^= flags
+	default:
+		return false
+	}
+
+	stack := e.kittyKeyboardStack()
+	if len(*stack) == 0 {
+		*stack = []int{0}
+	}
+	(*stack)[len(*stack)-1] = current & KittyKeyboardSupported
+	e.syncKittyKeyboardFlags()
+	return true
+}
+
+// resetKittyKeyboard clears the stacks of both screens.
+func (e *Emulator) resetKittyKeyboard() {
+	e.kittyKeys = [2][]int{}
+	e.syncKittyKeyboardFlags()
+}
+
+// registerKittyKeyboardHandlers registers the Kitty keyboard protocol
+// sequences: push, pop, set and query of the progressive enhancement flags.
+func (e *Emulator) registerKittyKeyboardHandlers() {
+	e.RegisterCsiHandler(ansi.Command('>', 0, 'u'), func(params ansi.Params) bool {
+		flags, _, _ := params.Param(0, 0)
+		e.pushKittyKeyboard(flags)
+		return true
+	})
+
+	e.RegisterCsiHandler(ansi.Command('<', 0, 'u'), func(params ansi.Params) bool {
+		n, _, _ := params.Param(0, 1)
+		e.popKittyKeyboard(n)
+		return true
+	})
+
+	e.RegisterCsiHandler(ansi.Command('=', 0, 'u'), func(params ansi.Params) bool {
+		flags, _, _ := params.Param(0, 0)
+		mode, _, _ := params.Param(1, 1)
+		return e.setKittyKeyboard(flags, mode)
+	})
+
+	e.RegisterCsiHandler(ansi.Command('?', 0, 'u'), func(params ansi.Params) bool {
+		_, _ = io.WriteString(e.pw, fmt.Sprintf("\x1b[?%du", e.currentKittyKeyboardFlags()))
+		return true
+	})
+}
@@ -0,0 +1,55 @@
+package vt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKittyKeyboardFlags(t *testing.T) {
+	e := NewEmulator(80, 24)
+
+	// Push enables flags, unsupported bits are dropped
+	_, _ = e.Write([]byte("\x1b[>3u"))
+	if got := e.KittyKeyboardFlags(); got != 1 {
+		t.Fatalf("after push flags = %d, want 1", got)
+	}
+
+	// Set with mode 2 adds flags to the current entry
+	_, _ = e.Write([]byte("\x1b[=8;2u"))
+	if got := e.KittyKeyboardFlags(); got != 9 {
+		t.Fatalf("after set flags = %d, want 9", got)
+	}
+
+	// The alternate screen has its own stack
+	_, _ = e.Write([]byte("\x1b[?1049h"))
+	if got := e.KittyKeyboardFlags(); got != 0 {
+		t.Fatalf("alt screen flags = %d, want 0", got)
+	}
+	_, _ = e.Write([]byte("\x1b[?1049l"))
+	if got := e.KittyKeyboardFlags(); got != 9 {
+		t.Fatalf("main screen flags after alt = %d, want 9", got)
+	}
+
+	// Query reports the current flags
+	response := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 64)
+		n, _ := e.Read(buf)
+		response <- string(buf[:n])
+	}()
+	_, _ = e.Write([]byte("\x1b[?u"))
+	select {
+	case got := <-response:
+		if got != "\x1b[?9u" {
+			t.Errorf("query response = %q, want %q", got, "\x1b[?9u")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for query response")
+	}
+
+	// Popping past the bottom of the stack resets to legacy keys
+	_, _ = e.Write([]byte("\x1b[<5u"))
+	if got := e.KittyKeyboardFlags(); got != 0 {
+		t.Errorf("after pop flags = %d, want 0", got)
+	}
+}
@@ -14,6 +14,10 @@ type KittyState struct {
 	pending       *KittyPendingChunk
 	dirty         bool
 	clearCallback func() // Called when placements/images are cleared
+
+	// Called instead of clearCallback when only the placements on the visible
+	// screen are cleared
+	screenClearCallback func(screenTop int)
 }
 
 func NewKittyState() *KittyState {
@@ -32,6 +36,15 @@ func (s *KittyState) SetClearCallback(fn func()) {
 	s.clearCallback = fn
 }
 
+// SetScreenClearCallback sets a callback that will be called when the
+// placements on the visible screen are cleared, with the absolute line the
+// screen starts at. Placements above it are in the scrollback and are kept.
+func (s *KittyState) SetScreenClearCallback(fn func(screenTop int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.screenClearCallback = fn
+}
+
 func (s *KittyState) AllocateID() uint32 {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -198,18 +211,29 @@ func (s *KittyState) Clear() {
 	}
 }
 
-func (s *KittyState) ClearPlacements() {
+// ClearPlacements removes the placements that extend onto the visible screen,
+// which starts at absolute line screenTop. Placements that have scrolled
+// entirely into the scrollback are kept so they show again when scrolling up.
+func (s *KittyState) ClearPlacements(screenTop int) {
 	s.mu.Lock()
 	callback := s.clearCallback
-	hadPlacements := len(s.placements) > 0
-	if hadPlacements {
-		s.placements = nil
+	screenCallback := s.screenClearCallback
+	var kept []*KittyPlacement
+	for _, p := range s.placements {
+		if p.AbsoluteLine+max(p.Rows, 1) <= screenTop {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) != len(s.placements) {
+		s.placements = kept
 		s.dirty = true
 	}
 	s.mu.Unlock()
 	// Always call callback (needed for passthrough mode where placements
 	// are stored externally, not in KittyState)
-	if callback != nil {
+	if screenCallback != nil {
+		screenCallback(screenTop)
+	} else if callback != nil {
 		callback()
 	}
 }
@@ -4,8 +4,11 @@ package vt
 
 import (
 	"bytes"
+	"encoding/base64"
+	"fmt"
 	"image/color"
 	"io"
+	"strconv"
 
 	"github.com/charmbracelet/x/ansi"
 )
@@ -82,12 +85,12 @@ func (e *Emulator) handleDefaultColor(cmd int, data []byte) {
 			var xrgb ansi.XRGBColor
 			switch cmd {
 			case 10: // Query foreground color
-				xrgb.Color = e.ForegroundColor()
+				xrgb.Color = e.reportedForegroundColor()
 				if xrgb.Color != nil {
 					_, _ = io.WriteString(e.pw, ansi.SetForegroundColor(xrgb.String()))
 				}
 			case 11: // Query background color
-				xrgb.Color = e.BackgroundColor()
+				xrgb.Color = e.reportedBackgroundColor()
 				if xrgb.Color != nil {
 					_, _ = io.WriteString(e.pw, ansi.SetBackgroundColor(xrgb.String()))
 				}
@@ -103,6 +106,65 @@ func (e *Emulator) handleDefaultColor(cmd int, data []byte) {
 	}
 }
 
+// handlePaletteColor handles OSC 4 (set or query indexed colors) and OSC 104
+// (reset indexed colors). OSC 4 takes index;spec pairs where a spec of "?"
+// queries the color. OSC 104 resets the given indexes, or all of them, to the
+// theme's palette, or the standard one for indexes the theme leaves alone.
+func (e *Emulator) handlePaletteColor(cmd int, data []byte) {
+	parts := bytes.Split(data, []byte{';'})[1:]
+	switch cmd {
+	case 4:
+		for i := 0; i+1 < len(parts); i += 2 {
+			index, err := strconv.Atoi(string(parts[i]))
+			if err != nil || index < 0 || index > 255 {
+				continue
+			}
+			spec := string(parts[i+1])
+			if spec == "?" {
+				xrgb := ansi.XRGBColor{Color: e.IndexedColor(index)}
+				_, _ = io.WriteString(e.pw, fmt.Sprintf("\x1b]4;%d;%s\x07", index, xrgb.String()))
+			} else if c := ansi.XParseColor(spec); c != nil {
+				e.SetIndexedColor(index, c)
+			}
+		}
+	case 104:
+		if len(parts) == 0 || (len(parts) == 1 && len(parts[0]) == 0) {
+			for i := range e.colors {
+				e.SetIndexedColor(i, nil)
+			}
+			return
+		}
+		for _, part := range parts {
+			if index, err := strconv.Atoi(string(part)); err == nil {
+				e.SetIndexedColor(index, nil)
+			}
+		}
+	}
+}
+
+// handleClipboard handles OSC 52 clipboard writes. The content is base64
+// encoded; the selection defaults to "s 0" in xterm, which hosts treat as
+// the clipboard. Queries ("?") are never answered, so applications cannot
+// read the host clipboard, and clearing requests are ignored.
+func (e *Emulator) handleClipboard(data []byte) {
+	parts := bytes.SplitN(data, []byte{';'}, 3)
+	if len(parts) != 3 || len(parts[2]) == 0 || string(parts[2]) == "?" {
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(string(parts[2]))
+	if err != nil {
+		e.logf("invalid OSC 52 clipboard data: %v", err)
+		return
+	}
+	selection := string(parts[1])
+	if selection == "" {
+		selection = "s0"
+	}
+	if e.cb.ClipboardWrite != nil {
+		e.cb.ClipboardWrite(selection, content)
+	}
+}
+
 func (e *Emulator) handleWorkingDirectory(cmd int, data []byte) {
 	if cmd != 7 {
 		// Invalid, ignore
@@ -131,6 +193,7 @@ func (e *Emulator) handleHyperlink(cmd int, data []byte) {
 		return
 	}
 
-	e.scr.cur.Link.URL = string(parts[1])
-	e.scr.cur.Link.Params = string(parts[2])
+	// OSC 8 ; params ; URI
+	e.scr.cur.Link.Params = string(parts[1])
+	e.scr.cur.Link.URL = string(parts[2])
 }
@@ -0,0 +1,163 @@
+package vt
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+// queryResponse writes query to the emulator and returns its reply.
+func queryResponse(t *testing.T, e *Emulator, query string) string {
+	t.Helper()
+	response := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 256)
+		n, _ := e.Read(buf)
+		response <- string(buf[:n])
+	}()
+	_, _ = e.Write([]byte(query))
+	select {
+	case got := <-response:
+		return got
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timeout waiting for a reply to %q", query)
+		return ""
+	}
+}
+
+func TestOSCColorQueries(t *testing.T) {
+	e := NewEmulator(80, 24)
+	e.SetHostColors(color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}, color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff})
+
+	// Without a theme both default colors come from the host
+	if got, want := queryResponse(t, e, "\x1b]11;?\x07"), "\x1b]11;rgb:1010/2020/3030\x07"; got != want {
+		t.Errorf("background query = %q, want %q", got, want)
+	}
+	if got, want := queryResponse(t, e, "\x1b]10;?\x07"), "\x1b]10;rgb:eeee/eeee/eeee\x07"; got != want {
+		t.Errorf("foreground query = %q, want %q", got, want)
+	}
+
+	// Colors set by the app are reported until they are reset
+	_, _ = e.Write([]byte("\x1b]11;#ff0000\x07"))
+	if _, bg := e.AppColors(); bg == nil {
+		t.Fatal("background set with OSC 11 not recorded")
+	}
+	_, _ = e.Write([]byte("\x1b]111\x07"))
+	if _, bg := e.AppColors(); bg != nil {
+		t.Errorf("background after OSC 111 = %v, want nil", bg)
+	}
+}
+
+func TestOSCPaletteColors(t *testing.T) {
+	e := NewEmulator(80, 24)
+
+	_, _ = e.Write([]byte("\x1b]4;1;rgb:12/34/56;200;#abcdef\x07"))
+	if got, want := queryResponse(t, e, "\x1b]4;1;?\x07"), "\x1b]4;1;rgb:1212/3434/5656\x07"; got != want {
+		t.Errorf("palette query = %q, want %q", got, want)
+	}
+
+	// Resetting one index leaves the others alone
+	_, _ = e.Write([]byte("\x1b]104;1\x07"))
+	if e.colors[1] != standardPalette[1] || e.colors[200] == standardPalette[200] {
+		t.Errorf("after OSC 104;1 colors[1] = %v, colors[200] = %v", e.colors[1], e.colors[200])
+	}
+	_, _ = e.Write([]byte("\x1b]104\x07"))
+	if e.colors != standardPalette || e.hasThemeColors() {
+		t.Errorf("after OSC 104 colors[200] = %v, want the standard palette", e.colors[200])
+	}
+
+	// Colors never set are answered from the standard palette, and a reset
+	// goes back to the theme's
+	if got, want := queryResponse(t, e, "\x1b]4;196;?\x07"), "\x1b]4;196;rgb:ffff/0000/0000\x07"; got != want {
+		t.Errorf("default palette query = %q, want %q", got, want)
+	}
+	var palette [16]color.Color
+	palette[1] = color.RGBA{R: 0xaa, A: 0xff}
+	e.SetThemeColors(color.White, nil, nil, palette)
+	_, _ = e.Write([]byte("\x1b]4;1;#000000\x07\x1b]104;1\x07"))
+	if got, want := queryResponse(t, e, "\x1b]4;1;?\x07"), "\x1b]4;1;rgb:aaaa/0000/0000\x07"; got != want {
+		t.Errorf("themed palette query after reset = %q, want %q", got, want)
+	}
+}
+
+func TestLastCommandOutput(t *testing.T) {
+	e := NewEmulator(20, 4)
+	if _, ok := e.LastCommandOutput(); ok {
+		t.Fatal("expected no output before any command finished")
+	}
+
+	prompt := "\x1b]133;A\x07$ \x1b]133;B\x07"
+	_, _ = e.Write([]byte(prompt + "seq 3\r\n\x1b]133;C\x07one\r\ntwo\r\nthree\r\n\x1b]133;D;0\x07"))
+	got, ok := e.LastCommandOutput()
+	if !ok || got != "one\ntwo\nthree" {
+		t.Fatalf("LastCommandOutput() = %q, %v, want the three lines", got, ok)
+	}
+
+	// Output scrolled into the scrollback is still found, and a prompt ends
+	// the output of shells that send no D mark
+	_, _ = e.Write([]byte(prompt + "ls\r\n\x1b]133;C\x07a\r\nb\r\nc\r\nd\r\ne\r\n" + prompt))
+	if got, _ := e.LastCommandOutput(); got != "a\nb\nc\nd\ne" {
+		t.Errorf("LastCommandOutput() = %q, want the output spanning the scrollback", got)
+	}
+
+	// Marks written by full-screen programs are ignored
+	_, _ = e.Write([]byte("\x1b[?1049h\x1b]133;C\x07x\r\n\x1b]133;D\x07\x1b[?1049l"))
+	if got, _ := e.LastCommandOutput(); got != "a\nb\nc\nd\ne" {
+		t.Errorf("LastCommandOutput() = %q after alternate screen marks", got)
+	}
+}
+
+func TestCommandFinishedCallback(t *testing.T) {
+	type finished struct {
+		command  string
+		exitCode int
+	}
+	var got []finished
+	e := NewEmulator(10, 5)
+	e.SetCallbacks(Callbacks{CommandFinished: func(command string, exitCode int) {
+		got = append(got, finished{command, exitCode})
+	}})
+
+	prompt := "\x1b]133;A\x07$ \x1b]133;B\x07"
+	// The long command line wraps onto a second row
+	_, _ = e.Write([]byte(prompt + "echo hello\r\n\x1b]133;C\x07hello\r\n\x1b]133;D;0\x07"))
+	_, _ = e.Write([]byte(prompt + "false\r\n\x1b]133;C\x07\x1b]133;D;1\x07"))
+	_, _ = e.Write([]byte(prompt + "\r\n\x1b]133;D\x07"))
+	_, _ = e.Write([]byte(prompt + "true\r\n\x1b]133;C\x07" + prompt))
+
+	want := []finished{{"echo hello", 0}, {"false", 1}, {"true", -1}}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("command %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestOSC52ClipboardWrite(t *testing.T) {
+	e := NewEmulator(80, 24)
+	var selection, content string
+	writes := 0
+	e.SetCallbacks(Callbacks{ClipboardWrite: func(sel string, data []byte) {
+		selection, content = sel, string(data)
+		writes++
+	}})
+
+	_, _ = e.Write([]byte("\x1b]52;c;aGVsbG8gd29ybGQ=\x07"))
+	if writes != 1 || selection != "c" || content != "hello world" {
+		t.Errorf("clipboard write = %d, %q, %q; want 1, c, hello world", writes, selection, content)
+	}
+
+	_, _ = e.Write([]byte("\x1b]52;;cHJpbWFyeQ==\x1b\\"))
+	if writes != 2 || selection != "s0" || content != "primary" {
+		t.Errorf("clipboard write without selection = %q, %q", selection, content)
+	}
+
+	// Queries, clears and invalid data never reach the callback
+	_, _ = e.Write([]byte("\x1b]52;c;?\x07\x1b]52;c;\x07\x1b]52;c;not base64!\x07"))
+	if writes != 2 {
+		t.Errorf("got %d clipboard writes, want 2", writes)
+	}
+}
@@ -0,0 +1,142 @@
+package vt
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// commandMarks tracks command output located with OSC 133 shell integration
+// marks, in absolute lines of the main screen (see ScrollbackPushed).
+type commandMarks struct {
+	editing   bool   // The user is typing a command line
+	inputLine int    // Line the command line starts on
+	inputX    int    // Column the command line starts at
+	command   string // Command line of the running command
+	running   bool   // A command started writing output
+	start     int    // First line of the running command's output
+	finished  bool   // A command finished since the emulator started
+	lastStart int    // First line of the last finished command's output
+	lastEnd   int    // Line after the last finished command's output
+}
+
+// handleSemanticPrompt handles OSC 133 shell integration marks: A starts a
+// prompt, B the command line, C the command's output and D reports that the
+// command finished. Marks written on the alternate screen are ignored.
+func (e *Emulator) handleSemanticPrompt(data []byte) {
+	parts := bytes.Split(data, []byte{';'})
+	if len(parts) < 2 || len(parts[1]) == 0 || e.IsAltScreen() {
+		return
+	}
+	x, y := e.scrs[0].CursorPosition()
+	line := e.scrs[0].ScrollbackPushed() + y
+
+	switch parts[1][0] {
+	case 'B':
+		e.marks.editing = true
+		e.marks.inputLine, e.marks.inputX = line, x
+	case 'C':
+		e.marks.command = ""
+		if e.marks.editing {
+			e.marks.command = e.mainText(e.marks.inputLine, e.marks.inputX, line, x)
+		}
+		e.marks.editing = false
+		e.marks.running = true
+		e.marks.start = line
+	case 'D', 'A':
+		e.marks.editing = false
+		// A prompt ends the output of shells that do not send D
+		if !e.marks.running {
+			return
+		}
+		if x > 0 {
+			// The output's last line has no newline
+			line++
+		}
+		e.marks.running = false
+		e.marks.finished = true
+		e.marks.lastStart, e.marks.lastEnd = e.marks.start, max(line, e.marks.start)
+
+		exitCode := -1
+		if parts[1][0] == 'D' && len(parts) > 2 {
+			if code, err := strconv.Atoi(string(parts[2])); err == nil {
+				exitCode = code
+			}
+		}
+		if e.marks.command != "" && e.cb.CommandFinished != nil {
+			e.cb.CommandFinished(e.marks.command, exitCode)
+		}
+		e.marks.command = ""
+	}
+}
+
+// mainLine returns an absolute line of the main screen, from the scrollback
+// or the screen, or nil if it was dropped or is below the screen.
+func (e *Emulator) mainLine(l int) uv.Line {
+	main := &e.scrs[0]
+	pushed := main.ScrollbackPushed()
+	if first := pushed - main.ScrollbackLen(); l < pushed {
+		if l < first {
+			return nil
+		}
+		return main.ScrollbackLine(l - first)
+	}
+	y := l - pushed
+	if y >= main.Height() {
+		return nil
+	}
+	line := make(uv.Line, main.Width())
+	for x := range line {
+		if cell := main.CellAt(x, y); cell != nil {
+			line[x] = *cell
+		}
+	}
+	return line
+}
+
+// mainText returns the text of the main screen from a position to another,
+// with wrapped lines joined and surrounding spaces trimmed.
+func (e *Emulator) mainText(fromLine, fromX, toLine, toX int) string {
+	var text strings.Builder
+	for l := fromLine; l <= toLine; l++ {
+		line := e.mainLine(l)
+		start, end := 0, len(line)
+		if l == fromLine {
+			start = min(fromX, end)
+		}
+		if l == toLine {
+			end = min(toX, end)
+		}
+		if start < end {
+			text.WriteString(strings.TrimRight(line[start:end].String(), " "))
+		}
+	}
+	return strings.TrimSpace(text.String())
+}
+
+// LastCommandOutput returns the output of the last finished command, located
+// with OSC 133 shell integration marks. It reports false if no command
+// finished with marks. Lines dropped from the scrollback are left out.
+func (e *Emulator) LastCommandOutput() (string, bool) {
+	if !e.marks.finished {
+		return "", false
+	}
+	main := &e.scrs[0]
+	first := main.ScrollbackPushed() - main.ScrollbackLen()
+
+	var lines []string
+	for l := max(e.marks.lastStart, first); l < e.marks.lastEnd; l++ {
+		line := e.mainLine(l)
+		if line == nil {
+			break
+		}
+		lines = append(lines, strings.TrimRight(line.String(), " "))
+	}
+	// Drop the blank lines the screen keeps below the output
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n"), true
+}
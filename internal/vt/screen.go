@@ -2,6 +2,7 @@ package vt
 
 import (
 	"sync"
+	"time"
 
 	uv "github.com/charmbracelet/ultraviolet"
 )
@@ -18,6 +19,9 @@ type Screen struct {
 	scroll uv.Rectangle
 	// scrollback is the scrollback buffer for lines that have scrolled off the top.
 	scrollback *Scrollback
+	// lineTimes holds when each row was first written since it was last
+	// cleared, in Unix nanoseconds (0 = blank).
+	lineTimes []int64
 	// mutex for the screen.
 	mu sync.RWMutex
 }
@@ -35,6 +39,7 @@ func NewScreen(w, h int) *Screen {
 // cursor styles, and resets the scroll region.
 func (s *Screen) Reset() {
 	s.buf.Clear()
+	clear(s.lineTimes)
 	s.cur = Cursor{}
 	s.saved = Cursor{}
 	s.scroll = s.buf.Bounds()
@@ -58,6 +63,49 @@ func (s *Screen) CellAt(x int, y int) *uv.Cell {
 // SetCell sets the cell at the given x, y position.
 func (s *Screen) SetCell(x, y int, c *uv.Cell) {
 	s.buf.SetCell(x, y, c)
+	if y >= 0 && y < len(s.lineTimes) && s.lineTimes[y] == 0 {
+		s.lineTimes[y] = time.Now().UnixNano()
+	}
+}
+
+// LineTime returns when row y was first written since it was last cleared,
+// or the zero time for a blank row.
+func (s *Screen) LineTime(y int) time.Time {
+	times := s.lineTimes
+	if y < 0 || y >= len(times) || times[y] == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, times[y])
+}
+
+// clearLineTimes forgets the write times of the rows fully covered by area.
+func (s *Screen) clearLineTimes(area uv.Rectangle) {
+	if area.Min.X > 0 || area.Max.X < s.buf.Width() {
+		return
+	}
+	for y := max(area.Min.Y, 0); y < min(area.Max.Y, len(s.lineTimes)); y++ {
+		s.lineTimes[y] = 0
+	}
+}
+
+// shiftLineTimes moves the write times of rows y up to bottom by n rows,
+// down for positive n and up for negative n, mirroring line insertion and
+// deletion. Rows that are uncovered become blank.
+func (s *Screen) shiftLineTimes(y, bottom, n int) {
+	bottom = min(bottom, len(s.lineTimes))
+	if y < 0 || y >= bottom {
+		return
+	}
+	rows := s.lineTimes[y:bottom]
+	if n > 0 {
+		n = min(n, len(rows))
+		copy(rows[n:], rows)
+		clear(rows[:n])
+	} else {
+		n = min(-n, len(rows))
+		copy(rows, rows[n:])
+		clear(rows[len(rows)-n:])
+	}
 }
 
 // Height returns the height of the screen.
@@ -68,6 +116,11 @@ func (s *Screen) Height() int {
 // Resize resizes the screen.
 func (s *Screen) Resize(width int, height int) {
 	s.buf.Resize(width, height)
+	if height > len(s.lineTimes) {
+		s.lineTimes = append(s.lineTimes, make([]int64, height-len(s.lineTimes))...)
+	} else {
+		s.lineTimes = s.lineTimes[:height]
+	}
 	s.scroll = s.buf.Bounds()
 }
 
@@ -84,6 +137,7 @@ func (s *Screen) Clear() {
 // ClearArea clears the given area.
 func (s *Screen) ClearArea(area uv.Rectangle) {
 	s.buf.ClearArea(area)
+	s.clearLineTimes(area)
 }
 
 // Fill fills the screen or part of it.
@@ -94,6 +148,7 @@ func (s *Screen) Fill(c *uv.Cell) {
 // FillArea fills the given area with the given cell.
 func (s *Screen) FillArea(c *uv.Cell, area uv.Rectangle) {
 	s.buf.FillArea(c, area)
+	s.clearLineTimes(area)
 }
 
 // setHorizontalMargins sets the horizontal margins.
@@ -277,7 +332,7 @@ func (s *Screen) ScrollUp(n int) {
 		for i := 0; i < n && i < scroll.Dy(); i++ {
 			y := scroll.Min.Y + i
 			line := extractLine(&s.buf, y, width)
-			s.scrollback.PushLine(line)
+			s.scrollback.PushLineAt(line, s.LineTime(y))
 		}
 	}
 	s.mu.Unlock()
@@ -316,6 +371,7 @@ func (s *Screen) InsertLine(n int) bool {
 	}
 
 	s.buf.InsertLineArea(y, n, s.blankCell(), s.scroll)
+	s.shiftLineTimes(y, s.scroll.Max.Y, n)
 
 	return true
 }
@@ -339,6 +395,7 @@ func (s *Screen) DeleteLine(n int) bool {
 	}
 
 	s.buf.DeleteLineArea(y, n, s.blankCell(), scroll)
+	s.shiftLineTimes(y, scroll.Max.Y, -n)
 
 	return true
 }
@@ -380,6 +437,17 @@ func (s *Screen) ScrollbackLen() int {
 	return s.scrollback.Len()
 }
 
+// ScrollbackPushed returns the number of lines ever pushed into the
+// scrollback buffer. See [Scrollback.Pushed].
+func (s *Screen) ScrollbackPushed() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.scrollback == nil {
+		return 0
+	}
+	return s.scrollback.Pushed()
+}
+
 // ScrollbackLine returns the line at the specified index in the scrollback buffer.
 // Index 0 is the oldest line. Returns nil if the index is out of bounds.
 func (s *Screen) ScrollbackLine(index int) []uv.Cell {
@@ -391,6 +459,39 @@ func (s *Screen) ScrollbackLine(index int) []uv.Cell {
 	return s.scrollback.Line(index)
 }
 
+// ScrollbackLineTime returns when the scrollback line at index was first
+// written to the screen. See [Scrollback.LineTime].
+func (s *Screen) ScrollbackLineTime(index int) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.scrollback == nil {
+		return time.Time{}
+	}
+	return s.scrollback.LineTime(index)
+}
+
+// ScrollbackMemory returns the estimated number of bytes held by the
+// scrollback buffer's lines.
+func (s *Screen) ScrollbackMemory() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.scrollback == nil {
+		return 0
+	}
+	return s.scrollback.MemoryUsage()
+}
+
+// TrimScrollback discards up to n of the oldest scrollback lines and returns
+// the number of lines removed.
+func (s *Screen) TrimScrollback(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scrollback == nil {
+		return 0
+	}
+	return s.scrollback.TrimOldest(n)
+}
+
 // SetScrollbackMaxLines sets the maximum number of lines for the scrollback buffer.
 func (s *Screen) SetScrollbackMaxLines(maxLines int) {
 	s.mu.Lock()
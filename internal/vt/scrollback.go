@@ -1,9 +1,16 @@
 package vt
 
 import (
+	"time"
+	"unsafe"
+
 	uv "github.com/charmbracelet/ultraviolet"
 )
 
+// cellSize is the in-memory size of a single scrollback cell, excluding the
+// bytes referenced by its content string.
+const cellSize = int(unsafe.Sizeof(uv.Cell{}))
+
 // Scrollback represents a scrollback buffer that stores lines that have
 // scrolled off the top of the visible screen.
 // Uses a ring buffer for O(1) insertions instead of O(n) slice reallocations.
@@ -25,6 +32,13 @@ type Scrollback struct {
 	// softWrapped indicates which lines are soft-wrapped (not hard breaks)
 	// A soft-wrapped line can be reflowed to a different width
 	softWrapped []bool
+	// written holds when each line was first written to the screen, in Unix
+	// nanoseconds (0 = unknown)
+	written []int64
+	// memory is the estimated number of bytes held by the stored lines
+	memory int
+	// pushed counts every line ever pushed, including lines since dropped
+	pushed int
 }
 
 // NewScrollback creates a new scrollback buffer with the specified maximum
@@ -41,6 +55,7 @@ func NewScrollback(maxLines int) *Scrollback {
 		full:              false,
 		lastWidthCaptured: 0,
 		softWrapped:       make([]bool, maxLines), // Track which lines are soft-wrapped
+		written:           make([]int64, maxLines),
 	}
 }
 
@@ -55,17 +70,36 @@ func (sb *Scrollback) PushLine(line []uv.Cell) {
 
 // PushLineWithWrap adds a line with wrap information for soft-wrap support.
 func (sb *Scrollback) PushLineWithWrap(line []uv.Cell, isSoftWrapped bool) {
+	sb.pushLine(line, isSoftWrapped, time.Time{})
+}
+
+// PushLineAt adds a line that was first written to the screen at the given
+// time. See LineTime.
+func (sb *Scrollback) PushLineAt(line []uv.Cell, written time.Time) {
+	sb.pushLine(line, true, written)
+}
+
+func (sb *Scrollback) pushLine(line []uv.Cell, isSoftWrapped bool, written time.Time) {
 	if len(line) == 0 {
 		return
 	}
+	if sb.maxLines == 0 {
+		// Scrollback is disabled, the line is dropped right away
+		sb.pushed++
+		return
+	}
 
 	// Make a copy of the line to avoid aliasing issues
 	lineCopy := make([]uv.Cell, len(line))
 	copy(lineCopy, line)
 
-	// Insert at tail position
+	// Insert at tail position, releasing the line being overwritten
+	sb.memory -= lineMemory(sb.lines[sb.tail])
+	sb.memory += lineMemory(lineCopy)
 	sb.lines[sb.tail] = lineCopy
 	sb.softWrapped[sb.tail] = isSoftWrapped
+	sb.written[sb.tail] = unixNano(written)
+	sb.pushed++
 
 	// Advance tail (wraps around at maxLines)
 	sb.tail = (sb.tail + 1) % sb.maxLines
@@ -92,6 +126,14 @@ func (sb *Scrollback) Len() int {
 	return sb.maxLines - sb.head + sb.tail
 }
 
+// Pushed returns the number of lines pushed into the scrollback buffer since
+// it was created, including lines that have since been dropped, trimmed or
+// cleared. Unlike Len it keeps growing once the buffer is full, so content
+// can be anchored to an absolute scrollback position.
+func (sb *Scrollback) Pushed() int {
+	return sb.pushed
+}
+
 // Line returns the line at the specified index in the scrollback buffer.
 // Index 0 is the oldest line, and Len()-1 is the newest (most recently scrolled).
 // Returns nil if the index is out of bounds.
@@ -111,6 +153,18 @@ func (sb *Scrollback) Line(index int) []uv.Cell {
 	return sb.lines[physicalIndex]
 }
 
+// LineTime returns when the line at the specified index was first written
+// to the screen, or the zero time if it is unknown or out of bounds.
+func (sb *Scrollback) LineTime(index int) time.Time {
+	if index < 0 || index >= sb.Len() {
+		return time.Time{}
+	}
+	if written := sb.written[(sb.head+index)%sb.maxLines]; written != 0 {
+		return time.Unix(0, written)
+	}
+	return time.Time{}
+}
+
 // Lines returns a slice of all lines in the scrollback buffer, from oldest
 // to newest. The returned slice should not be modified.
 func (sb *Scrollback) Lines() [][]uv.Cell {
@@ -133,10 +187,12 @@ func (sb *Scrollback) Clear() {
 	sb.head = 0
 	sb.tail = 0
 	sb.full = false
+	sb.memory = 0
 	// Optionally nil out the lines to help GC, but keep the slice
 	for i := range sb.lines {
 		sb.lines[i] = nil
 		sb.softWrapped[i] = false
+		sb.written[i] = 0
 	}
 }
 
@@ -183,9 +239,10 @@ func (sb *Scrollback) MaxLines() int {
 
 // SetMaxLines sets the maximum number of lines for the scrollback buffer.
 // If the new limit is smaller than the current number of lines, older lines
-// are discarded to fit the new limit.
+// are discarded to fit the new limit. Zero disables scrollback and a
+// negative value restores the default size.
 func (sb *Scrollback) SetMaxLines(maxLines int) {
-	if maxLines <= 0 {
+	if maxLines < 0 {
 		maxLines = 10000 // Default scrollback size
 	}
 
@@ -194,38 +251,79 @@ func (sb *Scrollback) SetMaxLines(maxLines int) {
 	}
 
 	oldLen := sb.Len()
-	if oldLen == 0 {
-		// Empty buffer, just resize
+	if oldLen == 0 || maxLines == 0 {
+		// Empty or disabled buffer, just resize
 		sb.lines = make([][]uv.Cell, maxLines)
 		sb.softWrapped = make([]bool, maxLines)
+		sb.written = make([]int64, maxLines)
 		sb.maxLines = maxLines
 		sb.head = 0
 		sb.tail = 0
 		sb.full = false
+		sb.memory = 0
 		return
 	}
 
 	// Create new ring buffer and copy existing lines
 	newLines := make([][]uv.Cell, maxLines)
 	newSoftWrapped := make([]bool, maxLines)
+	newWritten := make([]int64, maxLines)
 	newLen := min(oldLen, maxLines)
 
 	// Copy the most recent newLen lines
 	startIndex := oldLen - newLen // Skip oldest lines if downsizing
+	sb.memory = 0
 	for i := range newLen {
 		physicalIndex := (sb.head + startIndex + i) % sb.maxLines
 		newLines[i] = sb.lines[physicalIndex]
 		newSoftWrapped[i] = sb.softWrapped[physicalIndex]
+		newWritten[i] = sb.written[physicalIndex]
+		sb.memory += lineMemory(newLines[i])
 	}
 
 	sb.lines = newLines
 	sb.softWrapped = newSoftWrapped
+	sb.written = newWritten
 	sb.maxLines = maxLines
 	sb.head = 0
 	sb.tail = newLen % maxLines
 	sb.full = (newLen == maxLines)
 }
 
+// MemoryUsage returns the estimated number of bytes held by the lines
+// currently stored in the scrollback buffer. The fixed cost of the ring
+// buffer itself is not included.
+func (sb *Scrollback) MemoryUsage() int {
+	return sb.memory
+}
+
+// TrimOldest discards up to n of the oldest lines and returns the number of
+// lines that were removed. The capacity of the buffer is unchanged.
+func (sb *Scrollback) TrimOldest(n int) int {
+	n = min(n, sb.Len())
+	for range n {
+		sb.memory -= lineMemory(sb.lines[sb.head])
+		sb.lines[sb.head] = nil
+		sb.softWrapped[sb.head] = false
+		sb.written[sb.head] = 0
+		sb.head = (sb.head + 1) % sb.maxLines
+		sb.full = false
+	}
+	return max(n, 0)
+}
+
+// lineMemory estimates the number of bytes held by a scrollback line.
+func lineMemory(line []uv.Cell) int {
+	if line == nil {
+		return 0
+	}
+	n := cap(line) * cellSize
+	for i := range line {
+		n += len(line[i].Content)
+	}
+	return n
+}
+
 // extractLine extracts a complete line from the buffer at the given Y coordinate.
 // This is a helper function to copy cells from a buffer line.
 func extractLine(buf *uv.Buffer, y, width int) []uv.Cell {
@@ -239,3 +337,11 @@ func extractLine(buf *uv.Buffer, y, width int) []uv.Cell {
 	}
 	return line
 }
+
+// unixNano converts t to Unix nanoseconds, mapping the zero time to 0.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
@@ -2,6 +2,7 @@ package vt
 
 import (
 	"testing"
+	"time"
 
 	uv "github.com/charmbracelet/ultraviolet"
 )
@@ -128,6 +129,35 @@ func TestScrollbackSetMaxLines(t *testing.T) {
 	}
 }
 
+func TestScrollbackSetMaxLinesZero(t *testing.T) {
+	sb := NewScrollback(10)
+	for i := range 4 {
+		sb.PushLine([]uv.Cell{{Content: string(rune('A' + i)), Width: 1}})
+	}
+
+	// Zero disables scrollback and releases the stored lines
+	sb.SetMaxLines(0)
+	if sb.Len() != 0 || sb.MaxLines() != 0 || sb.MemoryUsage() != 0 {
+		t.Fatalf("disabled scrollback: len=%d max=%d memory=%d, want all 0", sb.Len(), sb.MaxLines(), sb.MemoryUsage())
+	}
+
+	// Lines pushed while disabled are dropped but still counted
+	sb.PushLine([]uv.Cell{{Content: "E", Width: 1}})
+	if sb.Len() != 0 || sb.Pushed() != 5 {
+		t.Errorf("after push: len=%d pushed=%d, want 0 and 5", sb.Len(), sb.Pushed())
+	}
+	if sb.Line(0) != nil || sb.TrimOldest(1) != 0 {
+		t.Error("disabled scrollback should have no lines")
+	}
+
+	// Re-enabling starts from an empty buffer
+	sb.SetMaxLines(3)
+	sb.PushLine([]uv.Cell{{Content: "F", Width: 1}})
+	if sb.Len() != 1 || sb.Line(0)[0].Content != "F" {
+		t.Errorf("re-enabled scrollback: len=%d, want 1 line 'F'", sb.Len())
+	}
+}
+
 func TestScrollbackBoundsChecking(t *testing.T) {
 	sb := NewScrollback(5)
 
@@ -177,3 +207,88 @@ func TestScrollbackEmptyPushIgnored(t *testing.T) {
 		t.Errorf("expected empty scrollback after pushing empty line, got %d", sb.Len())
 	}
 }
+
+func TestScrollbackMemoryAndTrim(t *testing.T) {
+	sb := NewScrollback(4)
+	line := []uv.Cell{{Content: "A", Width: 1}, {Content: "B", Width: 1}}
+	perLine := lineMemory(line)
+
+	for range 3 {
+		sb.PushLine(line)
+	}
+	if got := sb.MemoryUsage(); got != 3*perLine {
+		t.Errorf("expected %d bytes, got %d", 3*perLine, got)
+	}
+
+	// Overwriting the oldest line keeps the estimate stable
+	for range 3 {
+		sb.PushLine(line)
+	}
+	if got := sb.MemoryUsage(); got != 4*perLine {
+		t.Errorf("expected %d bytes when full, got %d", 4*perLine, got)
+	}
+
+	if removed := sb.TrimOldest(3); removed != 3 {
+		t.Errorf("expected 3 lines trimmed, got %d", removed)
+	}
+	if sb.Len() != 1 || sb.MemoryUsage() != perLine {
+		t.Errorf("expected 1 line (%d bytes) after trim, got %d lines (%d bytes)", perLine, sb.Len(), sb.MemoryUsage())
+	}
+
+	// Trimming more than available only removes what is there
+	if removed := sb.TrimOldest(10); removed != 1 {
+		t.Errorf("expected 1 line trimmed, got %d", removed)
+	}
+	if sb.Len() != 0 || sb.MemoryUsage() != 0 {
+		t.Errorf("expected empty scrollback, got %d lines (%d bytes)", sb.Len(), sb.MemoryUsage())
+	}
+
+	// Buffer keeps working as a ring after trimming
+	for i := range 6 {
+		sb.PushLine([]uv.Cell{{Content: string(rune('A' + i)), Width: 1}})
+	}
+	if sb.Len() != 4 || sb.Line(0)[0].Content != "C" {
+		t.Errorf("expected 4 lines starting with 'C', got %d lines", sb.Len())
+	}
+}
+
+func TestScrollbackPushedKeepsCounting(t *testing.T) {
+	sb := NewScrollback(3)
+	for i := range 5 {
+		sb.PushLine([]uv.Cell{{Content: string(rune('A' + i)), Width: 1}})
+	}
+	if sb.Len() != 3 || sb.Pushed() != 5 {
+		t.Errorf("expected 3 lines and 5 pushed, got %d lines and %d pushed", sb.Len(), sb.Pushed())
+	}
+
+	sb.TrimOldest(2)
+	sb.Clear()
+	if sb.Pushed() != 5 {
+		t.Errorf("expected trimming and clearing to keep the pushed count, got %d", sb.Pushed())
+	}
+}
+
+func TestScrollbackLineTimes(t *testing.T) {
+	before := time.Now()
+	e := NewEmulator(10, 2)
+	_, _ = e.Write([]byte("one\r\ntwo\r\nthree"))
+
+	if e.ScrollbackLen() != 1 {
+		t.Fatalf("expected 1 scrollback line, got %d", e.ScrollbackLen())
+	}
+	if written := e.ScrollbackLineTime(0); written.Before(before) || written.After(time.Now()) {
+		t.Errorf("scrollback line time = %v, want between %v and now", written, before)
+	}
+	if e.LineTime(1).IsZero() {
+		t.Error("expected a time for the last written row")
+	}
+	if !e.ScrollbackLineTime(5).IsZero() {
+		t.Error("expected zero time for an out-of-range line")
+	}
+
+	// Erasing the display forgets when the rows were written
+	_, _ = e.Write([]byte("\x1b[2J"))
+	if !e.LineTime(0).IsZero() || !e.LineTime(1).IsZero() {
+		t.Error("expected cleared rows to have no time")
+	}
+}
@@ -0,0 +1,217 @@
+package vt
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SixelImage is a decoded sixel raster: one color register per pixel, or -1
+// where nothing was drawn. It lets passthrough images be scaled and cropped
+// before they are sent to the host, since sixel data itself can only be
+// drawn whole.
+type SixelImage struct {
+	Width  int
+	Height int
+	Pixels []int // Width*Height color registers, row by row
+
+	// Color register definitions, in the order they were given, and the
+	// background mode (P2) of the original sequence.
+	colors     []string
+	background int
+}
+
+// DecodeSixel decodes the raster of a sixel command. It returns nil when the
+// command draws no pixels.
+func DecodeSixel(cmd *SixelCommand) *SixelImage {
+	if cmd == nil {
+		return nil
+	}
+	img := &SixelImage{background: cmd.BackgroundMode}
+	var rows [][]int
+	data := cmd.Data
+	x, band, color := 0, 0, 0
+
+	// number reads the decimal parameter at data[i:], returning it and the
+	// index after it.
+	number := func(i int) (int, int) {
+		j := i
+		for j < len(data) && data[j] >= '0' && data[j] <= '9' {
+			j++
+		}
+		n, _ := strconv.Atoi(string(data[i:j]))
+		return n, j
+	}
+	draw := func(c byte, count int) {
+		bits := c - '?'
+		for ; count > 0; count-- {
+			for bit := range 6 {
+				if bits&(1<<bit) == 0 {
+					continue
+				}
+				y := band*6 + bit
+				for len(rows) <= y {
+					rows = append(rows, nil)
+				}
+				for len(rows[y]) <= x {
+					rows[y] = append(rows[y], -1)
+				}
+				rows[y][x] = color
+			}
+			x++
+			img.Width = max(img.Width, x)
+		}
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == '#':
+			start := i
+			var n int
+			n, i = number(i + 1)
+			color = n
+			if i < len(data) && data[i] == ';' {
+				for i < len(data) && (data[i] == ';' || (data[i] >= '0' && data[i] <= '9')) {
+					i++
+				}
+				img.colors = append(img.colors, string(data[start:i]))
+			}
+		case c == '"':
+			// Raster attributes; the size is taken from the pixels drawn.
+			i++
+			for i < len(data) && (data[i] == ';' || (data[i] >= '0' && data[i] <= '9')) {
+				i++
+			}
+		case c == '!':
+			var n int
+			n, i = number(i + 1)
+			if i < len(data) && data[i] >= '?' && data[i] <= '~' {
+				draw(data[i], max(n, 1))
+				i++
+			}
+		case c == '$':
+			x = 0
+			i++
+		case c == '-':
+			x = 0
+			band++
+			i++
+		case c >= '?' && c <= '~':
+			draw(c, 1)
+			i++
+		default:
+			i++
+		}
+	}
+
+	if img.Width == 0 {
+		return nil
+	}
+	img.Height = len(rows)
+	img.Pixels = make([]int, img.Width*img.Height)
+	for y, row := range rows {
+		for x := range img.Width {
+			p := -1
+			if x < len(row) {
+				p = row[x]
+			}
+			img.Pixels[y*img.Width+x] = p
+		}
+	}
+	return img
+}
+
+// Scale returns the image resized to width x height pixels, picking the
+// nearest source pixel for each one.
+func (img *SixelImage) Scale(width, height int) *SixelImage {
+	width, height = max(width, 1), max(height, 1)
+	if width == img.Width && height == img.Height {
+		return img
+	}
+	out := &SixelImage{Width: width, Height: height, Pixels: make([]int, width*height), colors: img.colors, background: img.background}
+	for y := range height {
+		sy := y * img.Height / height
+		for x := range width {
+			out.Pixels[y*width+x] = img.Pixels[sy*img.Width+x*img.Width/width]
+		}
+	}
+	return out
+}
+
+// Crop returns the part of the image inside the given pixel rectangle,
+// limited to the image.
+func (img *SixelImage) Crop(x, y, width, height int) *SixelImage {
+	x, y = max(x, 0), max(y, 0)
+	width, height = min(width, img.Width-x), min(height, img.Height-y)
+	if width <= 0 || height <= 0 {
+		return &SixelImage{colors: img.colors, background: img.background}
+	}
+	out := &SixelImage{Width: width, Height: height, Pixels: make([]int, width*height), colors: img.colors, background: img.background}
+	for row := range height {
+		copy(out.Pixels[row*width:(row+1)*width], img.Pixels[(y+row)*img.Width+x:])
+	}
+	return out
+}
+
+// Encode returns the image as the body of a DCS sixel sequence: the
+// parameters, the 'q' introducer and the sixel data, without ESC P and ST.
+func (img *SixelImage) Encode() []byte {
+	var b strings.Builder
+	// Square pixels; the raster attributes give the exact size.
+	fmt.Fprintf(&b, "0;%d;0q\"1;1;%d;%d", img.background, img.Width, img.Height)
+	for _, c := range img.colors {
+		b.WriteString(c)
+	}
+
+	for top := 0; top < img.Height; top += 6 {
+		var colors []int
+		for y := top; y < min(top+6, img.Height); y++ {
+			for _, p := range img.Pixels[y*img.Width : (y+1)*img.Width] {
+				if p >= 0 && !slices.Contains(colors, p) {
+					colors = append(colors, p)
+				}
+			}
+		}
+		slices.Sort(colors)
+
+		for i, color := range colors {
+			if i > 0 {
+				b.WriteByte('$')
+			}
+			fmt.Fprintf(&b, "#%d", color)
+			line := make([]byte, img.Width)
+			for x := range img.Width {
+				bits := byte(0)
+				for bit := range min(6, img.Height-top) {
+					if img.Pixels[(top+bit)*img.Width+x] == color {
+						bits |= 1 << bit
+					}
+				}
+				line[x] = '?' + bits
+			}
+			writeSixelRuns(&b, strings.TrimRight(string(line), "?"))
+		}
+		if top+6 < img.Height {
+			b.WriteByte('-')
+		}
+	}
+	return []byte(b.String())
+}
+
+// writeSixelRuns writes sixel characters, using repeat introducers for runs.
+func writeSixelRuns(b *strings.Builder, line string) {
+	for i := 0; i < len(line); {
+		j := i
+		for j < len(line) && line[j] == line[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, line[i])
+		} else {
+			b.WriteString(line[i:j])
+		}
+		i = j
+	}
+}
@@ -0,0 +1,39 @@
+package vt
+
+import "testing"
+
+func TestSixelRaster(t *testing.T) {
+	// Color 1 fills the top band, color 2 draws one pixel row of the second.
+	cmd := ParseSixelCommand([]byte("0;1;0q\"1;1;4;7#1;2;100;0;0#2;2;0;100;0#1!4~-#2@@@@"))
+	img := DecodeSixel(cmd)
+	if img == nil {
+		t.Fatal("DecodeSixel returned nil")
+	}
+	if img.Width != 4 || img.Height != 7 {
+		t.Fatalf("size = %dx%d, want 4x7", img.Width, img.Height)
+	}
+	if img.Pixels[0] != 1 || img.Pixels[6*4] != 2 {
+		t.Errorf("pixels = %v", img.Pixels)
+	}
+
+	// Encoding and decoding again keeps every pixel.
+	again := DecodeSixel(ParseSixelCommand(img.Encode()))
+	if again == nil || again.Width != img.Width || again.Height != img.Height {
+		t.Fatalf("round trip = %+v", again)
+	}
+	for i := range img.Pixels {
+		if again.Pixels[i] != img.Pixels[i] {
+			t.Fatalf("pixel %d = %d after round trip, want %d", i, again.Pixels[i], img.Pixels[i])
+		}
+	}
+
+	scaled := img.Scale(8, 14)
+	if scaled.Width != 8 || scaled.Height != 14 || scaled.Pixels[13*8+7] != 2 {
+		t.Errorf("Scale(8, 14) = %dx%d, last pixel %d", scaled.Width, scaled.Height, scaled.Pixels[13*8+7])
+	}
+
+	cropped := img.Crop(2, 5, 10, 10)
+	if cropped.Width != 2 || cropped.Height != 2 || cropped.Pixels[0] != 1 || cropped.Pixels[2] != 2 {
+		t.Errorf("Crop(2, 5, 10, 10) = %+v", cropped)
+	}
+}
@@ -27,14 +27,14 @@ func (e *Emulator) flushGrapheme() {
 		return
 	}
 
-	// We always use ansi.GraphemeWidth here to report accurate widths
-	// and it's up to the caller to decide how to handle Unicode vs non-Unicode
-	// modes.
-	method := ansi.GraphemeWidth
+	// We always segment and measure whole grapheme clusters here to report
+	// accurate widths and it's up to the caller to decide how to handle
+	// Unicode vs non-Unicode modes. The width honors the configured
+	// ambiguous-width and emoji width options.
 	graphemes := string(e.grapheme)
 	for len(graphemes) > 0 {
-		cluster, width := ansi.FirstGraphemeCluster(graphemes, method)
-		e.handleGrapheme(cluster, width)
+		cluster, _ := ansi.FirstGraphemeCluster(graphemes, ansi.GraphemeWidth)
+		e.handleGrapheme(cluster, e.graphemeWidth(cluster))
 		graphemes = graphemes[len(cluster):]
 	}
 	e.grapheme = e.grapheme[:0] // Reset the grapheme buffer.
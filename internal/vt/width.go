@@ -0,0 +1,47 @@
+package vt
+
+import (
+	"github.com/clipperhouse/displaywidth"
+)
+
+// SetWidthOptions sets how many cells East Asian ambiguous-width characters
+// and emoji occupy. ambiguousWidth 2 renders characters such as "○" or "→"
+// double-width, as CJK terminals and fonts do; emojiWidth 1 renders emoji
+// single-width for fonts that draw them narrow. Other values keep the
+// defaults of 1 and 2. Call it before any output is written, since existing
+// cells keep the width they were written with.
+func (e *Emulator) SetWidthOptions(ambiguousWidth, emojiWidth int) {
+	e.widthOpts = displaywidth.Options{EastAsianWidth: ambiguousWidth == 2}
+	e.narrowEmoji = emojiWidth == 1
+}
+
+// graphemeWidth returns the number of cells a single grapheme cluster
+// occupies under the emulator's width options.
+func (e *Emulator) graphemeWidth(cluster string) int {
+	width := e.widthOpts.String(cluster)
+	if width == 2 && e.narrowEmoji && isEmojiCluster(cluster) {
+		return 1
+	}
+	return width
+}
+
+// isEmojiCluster reports whether a double-width grapheme cluster is an emoji
+// rather than a wide CJK character. Emoji presentation selectors and ZWJ
+// sequences mark emoji wherever they appear; otherwise the cluster's first
+// rune must come from one of the emoji blocks.
+func isEmojiCluster(cluster string) bool {
+	for i, r := range cluster {
+		switch {
+		case r == 0xFE0F, r == 0x200D:
+			return true
+		case i > 0:
+			continue
+		case r >= 0x1F000 && r <= 0x1FAFF,
+			r >= 0x2300 && r <= 0x23FF,
+			r >= 0x2600 && r <= 0x27BF,
+			r >= 0x2B00 && r <= 0x2BFF:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package vt
+
+import "testing"
+
+func TestWidthOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		ambiguousWidth int
+		emojiWidth     int
+		input          string
+		wantWidth      int
+	}{
+		{"ambiguous narrow by default", 0, 0, "○", 1},
+		{"ambiguous wide", 2, 2, "○", 2},
+		{"wide CJK unaffected", 1, 1, "中", 2},
+		{"emoji wide by default", 0, 0, "😀", 2},
+		{"emoji narrow", 1, 1, "😀", 1},
+		{"ZWJ sequence is one cluster", 1, 2, "👨‍👩‍👧", 2},
+		{"ZWJ sequence narrow", 1, 1, "👨‍👩‍👧", 1},
+		{"flag is one cluster", 1, 2, "🇯🇵", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEmulator(20, 2)
+			e.SetWidthOptions(tt.ambiguousWidth, tt.emojiWidth)
+			// The trailing ASCII character flushes the grapheme buffer
+			_, _ = e.Write([]byte(tt.input + "x"))
+
+			cell := e.CellAt(0, 0)
+			if cell == nil || cell.Content != tt.input {
+				t.Fatalf("cell content = %v, want %q", cell, tt.input)
+			}
+			if cell.Width != tt.wantWidth {
+				t.Errorf("cell width = %d, want %d", cell.Width, tt.wantWidth)
+			}
+			if x := e.CursorPosition().X; x != tt.wantWidth+1 {
+				t.Errorf("cursor x = %d, want %d", x, tt.wantWidth+1)
+			}
+		})
+	}
+}